@@ -6,18 +6,27 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/jackc/pgx/v5"
-	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 
 	"albatross-2026-backend/account"
+	"albatross-2026-backend/checker"
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
+	"albatross-2026-backend/export"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/problempkg"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/ranking"
+	"albatross-2026-backend/rating"
+	"albatross-2026-backend/scoring"
 	"albatross-2026-backend/session"
+	"albatross-2026-backend/taskqueue"
 	"albatross-2026-backend/tournament"
 )
 
@@ -26,12 +35,17 @@ var jst = time.FixedZone("Asia/Tokyo", 9*60*60)
 type Handler struct {
 	gameSvc       *game.Service
 	tournamentSvc *tournament.Service
+	ratingSvc     *rating.Service
+	qualifyingSvc *qualifying.Service
+	exportSvc     *export.Service
+	problemPkgSvc *problempkg.Service
+	workers       *taskqueue.WorkerRegistry
 	q             db.Querier
 	conf          *config.Config
 }
 
-func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, q db.Querier, conf *config.Config) *Handler {
-	return &Handler{gameSvc: gameSvc, tournamentSvc: tournamentSvc, q: q, conf: conf}
+func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, ratingSvc *rating.Service, qualifyingSvc *qualifying.Service, exportSvc *export.Service, problemPkgSvc *problempkg.Service, workers *taskqueue.WorkerRegistry, q db.Querier, conf *config.Config) *Handler {
+	return &Handler{gameSvc: gameSvc, tournamentSvc: tournamentSvc, ratingSvc: ratingSvc, qualifyingSvc: qualifyingSvc, exportSvc: exportSvc, problemPkgSvc: problemPkgSvc, workers: workers, q: q, conf: conf}
 }
 
 func (h *Handler) newAdminMiddleware() echo.MiddlewareFunc {
@@ -55,7 +69,6 @@ func (h *Handler) RegisterHandlers(g *echo.Group) {
 
 	g.GET("/dashboard", h.getDashboard)
 
-	g.GET("/online-qualifying-ranking", h.getOnlineQualifyingRanking)
 	g.POST("/fix", h.postFix)
 
 	g.GET("/users", h.getUsers)
@@ -68,81 +81,90 @@ func (h *Handler) RegisterHandlers(g *echo.Group) {
 	g.POST("/games/new", h.postGameNew)
 	g.GET("/games/:gameID", h.getGameEdit)
 	g.POST("/games/:gameID", h.postGameEdit)
+	g.POST("/games/:gameID/schedule", h.postGameSchedule)
 	g.POST("/games/:gameID/start", h.postGameStart)
+	g.POST("/games/:gameID/pause", h.postGamePause)
+	g.POST("/games/:gameID/resume", h.postGameResume)
+	g.POST("/games/:gameID/extend", h.postGameExtend)
+	g.POST("/games/:gameID/end", h.postGameEnd)
+	g.POST("/games/:gameID/cancel", h.postGameCancel)
+	g.POST("/games/:gameID/reveal-next", h.postGameRevealNext)
+	g.POST("/games/:gameID/reveal-all", h.postGameRevealAll)
+	g.GET("/games/:gameID/ranking", h.getGameRanking)
+	g.GET("/games/:gameID/teams", h.getGameTeams)
+	g.POST("/games/:gameID/teams", h.postGameTeams)
 	g.GET("/games/:gameID/submissions", h.getSubmissions)
 	g.POST("/games/:gameID/submissions/rejudge-latest", h.postSubmissionsRejudgeLatest)
 	g.POST("/games/:gameID/submissions/rejudge-all", h.postSubmissionsRejudgeAll)
 	g.GET("/games/:gameID/submissions/:submissionID", h.getSubmissionDetail)
 	g.POST("/games/:gameID/submissions/:submissionID/rejudge", h.postSubmissionRejudge)
+	g.GET("/games/:gameID/export", h.getGameExport)
 
 	g.GET("/problems", h.getProblems)
 	g.GET("/problems/new", h.getProblemNew)
 	g.POST("/problems/new", h.postProblemNew)
+	g.POST("/problems/import", h.postProblemImport)
 	g.GET("/problems/:problemID", h.getProblemEdit)
 	g.POST("/problems/:problemID", h.postProblemEdit)
+	g.GET("/problems/:problemID/export", h.getProblemExport)
 	g.GET("/problems/:problemID/testcases", h.getTestcases)
 	g.GET("/problems/:problemID/testcases/new", h.getTestcaseNew)
 	g.POST("/problems/:problemID/testcases/new", h.postTestcaseNew)
 	g.GET("/problems/:problemID/testcases/:testcaseID", h.getTestcaseEdit)
 	g.POST("/problems/:problemID/testcases/:testcaseID", h.postTestcaseEdit)
 	g.POST("/problems/:problemID/testcases/:testcaseID/delete", h.postTestcaseDelete)
+	g.POST("/problems/:problemID/rejudge", h.postProblemRejudge)
+	g.GET("/problems/:problemID/validation", h.getProblemValidation)
+	g.POST("/problems/:problemID/validation", h.postProblemValidation)
+	g.POST("/problems/:problemID/reference-solutions/new", h.postReferenceSolutionNew)
+	g.POST("/problems/:problemID/reference-solutions/:referenceSolutionID/delete", h.postReferenceSolutionDelete)
+	g.GET("/rejudges", h.getRejudges)
+	g.GET("/rejudges/:rejudgeID", h.getRejudge)
 
 	g.GET("/tournaments", h.getTournaments)
 	g.GET("/tournaments/new", h.getTournamentNew)
 	g.POST("/tournaments/new", h.postTournamentNew)
 	g.GET("/tournaments/:tournamentID", h.getTournamentEdit)
 	g.POST("/tournaments/:tournamentID", h.postTournamentEdit)
+	g.GET("/tournaments/:tournamentID/export", h.getTournamentExport)
+
+	g.GET("/qualifying", h.getQualifyingStages)
+	g.GET("/qualifying/new", h.getQualifyingStageNew)
+	g.POST("/qualifying/new", h.postQualifyingStageNew)
+	g.GET("/qualifying/:stageID", h.getQualifyingStageEdit)
+	g.POST("/qualifying/:stageID", h.postQualifyingStageEdit)
+	g.GET("/qualifying/:stageID/ranking", h.getQualifyingStageRanking)
+
+	g.GET("/ratings", h.getRatings)
+	g.POST("/ratings/recompute", h.postRatingsRecompute)
 }
 
 func (h *Handler) getDashboard(c echo.Context) error {
+	statuses := h.workers.Status()
+	workers := make([]echo.Map, len(statuses))
+	for i, s := range statuses {
+		w := echo.Map{
+			"Language": s.Language,
+			"URL":      s.URL,
+			"Healthy":  s.Healthy,
+			"Running":  s.Running,
+			"Error":    s.Error,
+		}
+		if s.Info != nil {
+			w["Version"] = s.Info.Version
+			w["MaxMemoryBytes"] = s.Info.MaxMemoryBytes
+			w["MaxOutputBytes"] = s.Info.MaxOutputBytes
+			w["WorkerRunning"] = s.Info.Running
+		}
+		if !s.CheckedAt.IsZero() {
+			w["CheckedAt"] = s.CheckedAt.In(jst).Format("2006-01-02 15:04:05")
+		}
+		workers[i] = w
+	}
 	return c.Render(http.StatusOK, "dashboard", echo.Map{
 		"BasePath": h.conf.BasePath,
 		"Title":    "Dashboard",
-	})
-}
-
-func (h *Handler) getOnlineQualifyingRanking(c echo.Context) error {
-	game1, err := strconv.Atoi(c.QueryParam("game_1"))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_1")
-	}
-	game2, err := strconv.Atoi(c.QueryParam("game_2"))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_2")
-	}
-	game3, err := strconv.Atoi(c.QueryParam("game_3"))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_3")
-	}
-
-	rows, err := h.q.GetQualifyingRanking(c.Request().Context(), db.GetQualifyingRankingParams{
-		GameID:   int32(game1),
-		GameID_2: int32(game2),
-		GameID_3: int32(game3),
-	})
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-	}
-
-	entries := make([]echo.Map, len(rows))
-	for i, r := range rows {
-		entries[i] = echo.Map{
-			"Rank":         i + 1,
-			"Username":     r.Username,
-			"UserLabel":    r.UserLabel,
-			"Score1":       r.CodeSize1,
-			"Score2":       r.CodeSize2,
-			"Score3":       r.CodeSize3,
-			"TotalScore":   r.TotalCodeSize,
-			"SubmittedAt1": r.SubmittedAt1.Time.In(jst).Format("2006-01-02T15:04"),
-			"SubmittedAt2": r.SubmittedAt2.Time.In(jst).Format("2006-01-02T15:04"),
-			"SubmittedAt3": r.SubmittedAt3.Time.In(jst).Format("2006-01-02T15:04"),
-		}
-	}
-	return c.Render(http.StatusOK, "online_qualifying_ranking", echo.Map{
-		"BasePath": h.conf.BasePath,
-		"Title":    "Online Qualifying Ranking",
-		"Entries":  entries,
+		"Workers":  workers,
 	})
 }
 
@@ -264,6 +286,7 @@ func (h *Handler) getGames(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
+	now := time.Now()
 	games := make([]echo.Map, len(rows))
 	for i, g := range rows {
 		var startedAt string
@@ -277,7 +300,7 @@ func (h *Handler) getGames(c echo.Context) error {
 			"DisplayName":     g.DisplayName,
 			"DurationSeconds": g.DurationSeconds,
 			"StartedAt":       startedAt,
-			"ProblemID":       g.ProblemID,
+			"State":           game.LifecycleFromGame(g).StateAt(now),
 		}
 	}
 
@@ -318,22 +341,22 @@ func (h *Handler) postGameNew(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid duration_seconds")
 	}
-	var problemID int
-	{
-		problemIDRaw := c.FormValue("problem_id")
-		problemIDInt, err := strconv.Atoi(problemIDRaw)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
-		}
-		problemID = problemIDInt
+	unsolvedPenalty, err := parseUnsolvedPenalty(c)
+	if err != nil {
+		return err
+	}
+	problemIDs, err := parseProblemIDs(c)
+	if err != nil {
+		return err
 	}
 
-	_, err = h.q.CreateGame(c.Request().Context(), db.CreateGameParams{
+	_, err = h.gameSvc.CreateGame(c.Request().Context(), game.CreateGameParams{
 		GameType:        gameType,
 		IsPublic:        isPublic,
 		DisplayName:     displayName,
-		DurationSeconds: int32(durationSeconds),
-		ProblemID:       int32(problemID),
+		DurationSeconds: durationSeconds,
+		UnsolvedPenalty: unsolvedPenalty,
+		ProblemIDs:      problemIDs,
 	})
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
@@ -359,6 +382,42 @@ func (h *Handler) getGameEdit(c echo.Context) error {
 	if row.StartedAt.Valid {
 		startedAt = row.StartedAt.Time.In(jst).Format("2006-01-02T15:04")
 	}
+	lifecycle := game.Lifecycle{
+		StartedAt:       row.StartedAt,
+		DurationSeconds: row.DurationSeconds,
+		PausedAt:        row.PausedAt,
+		OverrideState:   row.OverrideState,
+	}
+	now := time.Now()
+	state := lifecycle.StateAt(now)
+	var frozenAt string
+	cutoff, frozen := game.RankingCutoff(row, now)
+	if frozen {
+		frozenAt = cutoff.In(jst).Format("2006-01-02T15:04:05")
+	}
+
+	eventRows, err := h.q.ListGameLifecycleEvents(c.Request().Context(), int32(gameID))
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+	lifecycleEvents := make([]echo.Map, len(eventRows))
+	for i, r := range eventRows {
+		var eventStartedAt string
+		if r.StartedAt.Valid {
+			eventStartedAt = r.StartedAt.Time.In(jst).Format("2006-01-02T15:04:05")
+		}
+		lifecycleEvents[i] = echo.Map{
+			"Action":          r.Action,
+			"FromState":       r.FromState,
+			"ToState":         r.ToState,
+			"StartedAt":       eventStartedAt,
+			"DurationSeconds": r.DurationSeconds,
+			"UserID":          r.UserID,
+			"CreatedAt":       r.CreatedAt.Time.In(jst).Format("2006-01-02T15:04:05"),
+		}
+	}
 
 	mainPlayerRows, err := h.q.ListMainPlayers(c.Request().Context(), []int32{int32(gameID)})
 	if err != nil {
@@ -388,6 +447,14 @@ func (h *Handler) getGameEdit(c echo.Context) error {
 			"Title":     p.Title,
 		})
 	}
+	gameProblemRows, err := h.q.ListGameProblems(c.Request().Context(), []int32{int32(gameID)})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	gameProblemIDs := make([]string, len(gameProblemRows))
+	for i, r := range gameProblemRows {
+		gameProblemIDs[i] = strconv.Itoa(int(r.Problem.ProblemID))
+	}
 
 	userRows, err := h.q.ListUsers(c.Request().Context())
 	if err != nil {
@@ -412,13 +479,23 @@ func (h *Handler) getGameEdit(c echo.Context) error {
 			"IsPublic":        row.IsPublic,
 			"DisplayName":     row.DisplayName,
 			"DurationSeconds": row.DurationSeconds,
+			"FreezeSeconds":   row.FreezeSeconds,
+			"TieBreak":        row.TieBreak,
 			"StartedAt":       startedAt,
-			"ProblemID":       row.ProblemID,
+			"State":           state,
+			"FrozenAt":        frozenAt,
+			"CanReveal":       frozen && state == game.StateFinished,
+			"UnsolvedPenalty": row.UnsolvedPenalty,
+			"AllowPractice":   row.AllowPractice,
+			"ProblemIDs":      strings.Join(gameProblemIDs, ","),
 			"MainPlayer1":     mainPlayer1,
 			"MainPlayer2":     mainPlayer2,
 		},
-		"Problems": problems,
-		"Users":    users,
+		"Actions":         game.AllowedActions(state),
+		"LifecycleEvents": lifecycleEvents,
+		"Problems":        problems,
+		"Users":           users,
+		"TieBreakNames":   ranking.Names,
 	})
 }
 
@@ -435,40 +512,28 @@ func (h *Handler) postGameEdit(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid duration_seconds")
 	}
-	var problemID int
-	{
-		problemIDRaw := c.FormValue("problem_id")
-		problemIDInt, err := strconv.Atoi(problemIDRaw)
-		if err != nil {
-			return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	freezeSeconds := 0
+	if freezeSecondsRaw := c.FormValue("freeze_seconds"); freezeSecondsRaw != "" {
+		freezeSeconds, err = strconv.Atoi(freezeSecondsRaw)
+		if err != nil || freezeSeconds < 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid freeze_seconds")
 		}
-		problemID = problemIDInt
 	}
-	var startedAt *time.Time
-	{
-		startedAtRaw := c.FormValue("started_at")
-		if startedAtRaw != "" {
-			startedAtJST, err := time.ParseInLocation("2006-01-02T15:04", startedAtRaw, jst)
-			if err != nil {
-				return echo.NewHTTPError(http.StatusBadRequest, "Invalid started_at")
-			}
-			startedAtUTC := startedAtJST.UTC()
-			startedAt = &startedAtUTC
-		}
+	tieBreak := c.FormValue("tie_break")
+	if tieBreak == "" {
+		tieBreak = ranking.Default
 	}
-
-	var changedStartedAt pgtype.Timestamp
-	if startedAt == nil {
-		changedStartedAt = pgtype.Timestamp{
-			Valid: false,
-		}
-	} else {
-		changedStartedAt = pgtype.Timestamp{
-			Time:  *startedAt,
-			Valid: true,
-		}
+	if !ranking.IsValid(tieBreak) {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tie_break")
+	}
+	unsolvedPenalty, err := parseUnsolvedPenalty(c)
+	if err != nil {
+		return err
+	}
+	problemIDs, err := parseProblemIDs(c)
+	if err != nil {
+		return err
 	}
-
 	mainPlayers := []int{}
 	mainPlayer1Raw := c.FormValue("main_player_1")
 	if mainPlayer1Raw != "" && mainPlayer1Raw != "0" {
@@ -486,6 +551,10 @@ func (h *Handler) postGameEdit(c echo.Context) error {
 		}
 		mainPlayers = append(mainPlayers, mainPlayer2)
 	}
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
 
 	err = h.gameSvc.UpdateGameWithPlayers(c.Request().Context(), game.UpdateGameParams{
 		GameID:          gameID,
@@ -493,35 +562,301 @@ func (h *Handler) postGameEdit(c echo.Context) error {
 		IsPublic:        isPublic,
 		DisplayName:     displayName,
 		DurationSeconds: durationSeconds,
-		StartedAt:       changedStartedAt,
-		ProblemID:       problemID,
+		FreezeSeconds:   freezeSeconds,
+		TieBreak:        tieBreak,
+		UnsolvedPenalty: unsolvedPenalty,
+		AllowPractice:   c.FormValue("allow_practice") != "",
+		ProblemIDs:      problemIDs,
 		MainPlayerIDs:   mainPlayers,
+		AdminID:         user.UserID,
 	})
 	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+		}
+		if errors.Is(err, game.ErrInvalidTransition) {
+			return echo.NewHTTPError(http.StatusConflict, "Cannot change the duration once the game has started; extend it instead")
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/games")
 }
 
+func (h *Handler) postGameSchedule(c echo.Context) error {
+	startAtRaw := c.FormValue("start_at")
+	startAtJST, err := time.ParseInLocation("2006-01-02T15:04", startAtRaw, jst)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid start_at")
+	}
+	if !startAtJST.After(time.Now()) {
+		return echo.NewHTTPError(http.StatusBadRequest, "start_at must be in the future")
+	}
+	return h.transitionGame(c, game.ActionSchedule, startAtJST.UTC(), 0)
+}
+
 func (h *Handler) postGameStart(c echo.Context) error {
+	return h.transitionGame(c, game.ActionStart, time.Time{}, 0)
+}
+
+func (h *Handler) postGamePause(c echo.Context) error {
+	return h.transitionGame(c, game.ActionPause, time.Time{}, 0)
+}
+
+func (h *Handler) postGameResume(c echo.Context) error {
+	return h.transitionGame(c, game.ActionResume, time.Time{}, 0)
+}
+
+func (h *Handler) postGameExtend(c echo.Context) error {
+	extendMinutes, err := strconv.Atoi(c.FormValue("extend_minutes"))
+	if err != nil || extendMinutes <= 0 {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid extend_minutes")
+	}
+	return h.transitionGame(c, game.ActionExtend, time.Time{}, extendMinutes*60)
+}
+
+func (h *Handler) postGameEnd(c echo.Context) error {
+	return h.transitionGame(c, game.ActionEnd, time.Time{}, 0)
+}
+
+func (h *Handler) postGameCancel(c echo.Context) error {
+	return h.transitionGame(c, game.ActionCancel, time.Time{}, 0)
+}
+
+func (h *Handler) transitionGame(c echo.Context, action game.Action, startAt time.Time, extendSeconds int) error {
 	gameID, err := strconv.Atoi(c.Param("gameID"))
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game id")
 	}
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
 
-	err = h.gameSvc.StartGame(c.Request().Context(), gameID)
+	err = h.gameSvc.TransitionGame(c.Request().Context(), game.TransitionGameParams{
+		GameID:         gameID,
+		Action:         action,
+		StartAt:        startAt,
+		ExtendSeconds:  extendSeconds,
+		AdminID:        user.UserID,
+		SkipValidation: c.FormValue("skip_validation") != "",
+	})
 	if err != nil {
 		if errors.Is(err, game.ErrNotFound) {
 			return echo.NewHTTPError(http.StatusNotFound, "Game not found")
 		}
+		if errors.Is(err, game.ErrNoProblems) {
+			return echo.NewHTTPError(http.StatusBadRequest, "No problems")
+		}
 		if errors.Is(err, game.ErrNoTestcases) {
 			return echo.NewHTTPError(http.StatusBadRequest, "No testcases")
 		}
+		if errors.Is(err, game.ErrNotValidated) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		if errors.Is(err, game.ErrInvalidTransition) {
+			return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("Cannot %s the game in its current state", action))
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/games")
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/games/%d", h.conf.BasePath, gameID))
+}
+
+func (h *Handler) postGameRevealNext(c echo.Context) error {
+	return h.revealRanking(c, false)
+}
+
+func (h *Handler) postGameRevealAll(c echo.Context) error {
+	return h.revealRanking(c, true)
+}
+
+func (h *Handler) revealRanking(c echo.Context, all bool) error {
+	gameID, err := strconv.Atoi(c.Param("gameID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game id")
+	}
+
+	err = h.gameSvc.RevealRanking(c.Request().Context(), gameID, all)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound, "Game not found")
+		}
+		if errors.Is(err, game.ErrGameNotFinished) {
+			return echo.NewHTTPError(http.StatusConflict, "Game is not finished")
+		}
+		if errors.Is(err, game.ErrNotFrozen) {
+			return echo.NewHTTPError(http.StatusConflict, "Ranking is not frozen")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/games/%d", h.conf.BasePath, gameID))
+}
+
+func (h *Handler) getGameRanking(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("gameID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_id")
+	}
+	cursor := c.QueryParam("cursor")
+	practice := c.QueryParam("practice") != ""
+	language := c.QueryParam("language")
+
+	var page game.Ranking
+	if practice {
+		page, err = h.gameSvc.GetPracticeRanking(c.Request().Context(), gameID, language, cursor, 0)
+	} else {
+		page, err = h.gameSvc.GetRanking(c.Request().Context(), gameID, true, language, cursor, 0)
+	}
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, ranking.ErrInvalidCursor) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid cursor")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	problemRows, err := h.q.ListGameProblems(c.Request().Context(), []int32{int32(gameID)})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	problemIDs := make([]int32, len(problemRows))
+	var languages []string
+	for i, r := range problemRows {
+		problemIDs[i] = r.Problem.ProblemID
+		if !slices.Contains(languages, r.Problem.Language) {
+			languages = append(languages, r.Problem.Language)
+		}
+	}
+	languageRows, err := h.q.ListProblemLanguages(c.Request().Context(), problemIDs)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	for _, r := range languageRows {
+		if !slices.Contains(languages, r.Language) {
+			languages = append(languages, r.Language)
+		}
+	}
+
+	entries := make([]echo.Map, len(page.Entries))
+	for i, e := range page.Entries {
+		problemScores := make([]echo.Map, len(e.ProblemScores))
+		for j, ps := range e.ProblemScores {
+			problemScores[j] = echo.Map{
+				"ProblemID": ps.ProblemID,
+				"Score":     ps.Score,
+			}
+		}
+		members := make([]echo.Map, len(e.Team.Members))
+		for j, m := range e.Team.Members {
+			members[j] = echo.Map{
+				"UserID":   m.UserID,
+				"Username": m.Username,
+				"Label":    m.Label,
+			}
+		}
+		entries[i] = echo.Map{
+			"Rank":            e.Rank,
+			"TeamID":          e.Team.TeamID,
+			"TeamName":        e.Team.DisplayName,
+			"Members":         members,
+			"Score":           e.Score,
+			"ProblemScores":   problemScores,
+			"SubmissionCount": e.SubmissionCount,
+			"SubmittedAt":     time.Unix(e.SubmittedAt, 0).In(jst).Format("2006-01-02T15:04:05"),
+		}
+	}
+
+	return c.Render(http.StatusOK, "game_ranking", echo.Map{
+		"BasePath":   h.conf.BasePath,
+		"Title":      "Ranking",
+		"GameID":     gameID,
+		"Practice":   practice,
+		"Language":   language,
+		"Languages":  languages,
+		"TieBreak":   page.TieBreak,
+		"ProblemIDs": problemIDs,
+		"IsFirst":    cursor == "",
+		"NextCursor": page.NextCursor,
+		"Entries":    entries,
+	})
+}
+
+func (h *Handler) getGameTeams(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("gameID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_id")
+	}
+
+	teams, err := h.gameSvc.ListTeams(c.Request().Context(), gameID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	entries := make([]echo.Map, len(teams))
+	lines := make([]string, len(teams))
+	for i, t := range teams {
+		members := make([]echo.Map, len(t.Members))
+		userIDs := make([]string, len(t.Members))
+		for j, m := range t.Members {
+			members[j] = echo.Map{
+				"UserID":   m.UserID,
+				"Username": m.Username,
+			}
+			userIDs[j] = strconv.Itoa(m.UserID)
+		}
+		entries[i] = echo.Map{
+			"TeamID":      t.TeamID,
+			"DisplayName": t.DisplayName,
+			"Members":     members,
+		}
+		lines[i] = t.DisplayName + ": " + strings.Join(userIDs, ", ")
+	}
+
+	return c.Render(http.StatusOK, "game_teams", echo.Map{
+		"BasePath":  h.conf.BasePath,
+		"Title":     "Teams",
+		"GameID":    gameID,
+		"Teams":     entries,
+		"TeamsText": strings.Join(lines, "\n"),
+	})
+}
+
+func (h *Handler) postGameTeams(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("gameID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_id")
+	}
+
+	teams, err := parseTeams(c.FormValue("teams"))
+	if err != nil {
+		return err
+	}
+
+	err = h.gameSvc.ReplaceTeams(c.Request().Context(), gameID, teams)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, game.ErrNotMultiplayer) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Teams are only for multiplayer games")
+		}
+		if errors.Is(err, game.ErrGameStarted) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Game has already started")
+		}
+		if errors.Is(err, game.ErrDuplicateTeamMember) {
+			return echo.NewHTTPError(http.StatusBadRequest, "A user is in more than one team")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/games/%d/teams", h.conf.BasePath, gameID))
 }
 
 func (h *Handler) getSubmissions(c echo.Context) error {
@@ -539,8 +874,10 @@ func (h *Handler) getSubmissions(c echo.Context) error {
 	for i, r := range submissions {
 		entries[i] = echo.Map{
 			"SubmissionID": r.SubmissionID,
+			"TeamID":       r.TeamID,
 			"UserID":       r.UserID,
 			"Status":       r.Status,
+			"IsPractice":   r.IsPractice,
 			"CodeSize":     r.CodeSize,
 			"CreatedAt":    r.CreatedAt.Time.In(jst).Format("2006-01-02T15:04"),
 		}
@@ -586,6 +923,8 @@ func (h *Handler) getSubmissionDetail(c echo.Context) error {
 			"CreatedAt":        r.CreatedAt.Time.In(jst).Format("2006-01-02T15:04"),
 			"Stdout":           r.Stdout,
 			"Stderr":           r.Stderr,
+			"TimeMs":           r.TimeMs,
+			"MemoryBytes":      r.MemoryBytes,
 		}
 	}
 
@@ -595,8 +934,11 @@ func (h *Handler) getSubmissionDetail(c echo.Context) error {
 		"GameID":   gameID,
 		"Submission": echo.Map{
 			"SubmissionID": submission.SubmissionID,
+			"TeamID":       submission.TeamID,
 			"UserID":       submission.UserID,
 			"Status":       submission.Status,
+			"IsPractice":   submission.IsPractice,
+			"Language":     submission.Language,
 			"CodeSize":     submission.CodeSize,
 			"CreatedAt":    submission.CreatedAt.Time.In(jst).Format("2006-01-02T15:04"),
 			"Code":         submission.Code,
@@ -626,15 +968,7 @@ func (h *Handler) postSubmissionRejudge(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	g, err := h.q.GetGameByID(ctx, int32(gameID))
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return echo.NewHTTPError(http.StatusNotFound)
-		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
-	}
-
-	if err := h.gameSvc.RejudgeSubmission(ctx, submission.SubmissionID, int(submission.GameID), int(submission.UserID), g.Language, submission.Code); err != nil {
+	if err := h.gameSvc.RejudgeSubmission(ctx, submission.SubmissionID, int(submission.GameID), int(submission.UserID), int(submission.ProblemID), submission.Language, submission.Code); err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
@@ -675,6 +1009,48 @@ func (h *Handler) postSubmissionsRejudgeAll(c echo.Context) error {
 	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/games/%d/submissions", h.conf.BasePath, gameID))
 }
 
+func (h *Handler) getGameExport(c echo.Context) error {
+	gameID, err := strconv.Atoi(c.Param("gameID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid game_id")
+	}
+	return h.writeExport(c, func(ctx context.Context) (*export.Archive, error) {
+		return h.exportSvc.Game(ctx, gameID)
+	})
+}
+
+// writeExport streams the archive in the format given by the query, CSV by
+// default, as a download.
+func (h *Handler) writeExport(c echo.Context, load func(ctx context.Context) (*export.Archive, error)) error {
+	format := c.QueryParam("format")
+	if format == "" {
+		format = export.FormatCSV
+	}
+	contentType, ext, err := export.FileType(format)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid format")
+	}
+	ctx := c.Request().Context()
+
+	archive, err := load(ctx)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, contentType)
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", archive.Name+ext))
+	res.WriteHeader(http.StatusOK)
+	// The status has been sent, so an error can only cut the download short.
+	if err := archive.Write(ctx, res, format); err != nil {
+		slog.Error("failed to write export", "name", archive.Name, "error", err)
+	}
+	return nil
+}
+
 func (h *Handler) getProblems(c echo.Context) error {
 	rows, err := h.q.ListProblems(c.Request().Context())
 	if err != nil {
@@ -687,6 +1063,7 @@ func (h *Handler) getProblems(c echo.Context) error {
 			"Title":       p.Title,
 			"Description": p.Description,
 			"Language":    p.Language,
+			"Scoring":     p.Scoring,
 		}
 	}
 
@@ -699,81 +1076,405 @@ func (h *Handler) getProblems(c echo.Context) error {
 
 func (h *Handler) getProblemNew(c echo.Context) error {
 	return c.Render(http.StatusOK, "problem_new", echo.Map{
-		"BasePath": h.conf.BasePath,
-		"Title":    "New Problem",
+		"BasePath":       h.conf.BasePath,
+		"Title":          "New Problem",
+		"ScoringNames":   scoring.Names,
+		"CheckerNames":   checker.Names,
+		"OtherLanguages": otherLanguagesMap(nil),
 	})
 }
 
-func (h *Handler) postProblemNew(c echo.Context) error {
-	title := c.FormValue("title")
-	description := c.FormValue("description")
-	language := c.FormValue("language")
-	sampleCode := c.FormValue("sample_code")
-
-	_, err := h.q.CreateProblem(c.Request().Context(), db.CreateProblemParams{
-		Title:       title,
-		Description: description,
-		Language:    language,
-		SampleCode:  sampleCode,
-	})
-	if err != nil {
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+// otherLanguagesMap lists all the languages for the form of a problem, telling
+// which of them the problem accepts.
+func otherLanguagesMap(languages []game.ProblemLanguage) []echo.Map {
+	rows := make([]echo.Map, len(game.Languages))
+	for i, language := range game.Languages {
+		row := echo.Map{"Language": language, "Enabled": false, "SampleCode": ""}
+		for _, l := range languages {
+			if l.Language == language {
+				row["Enabled"] = true
+				row["SampleCode"] = l.SampleCode
+			}
+		}
+		rows[i] = row
 	}
-
-	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems")
+	return rows
 }
 
-func (h *Handler) getProblemEdit(c echo.Context) error {
-	problemID, err := strconv.Atoi(c.Param("problemID"))
+// parseOtherLanguages reads the languages the problem accepts besides its own
+// language, each with the sample code for it.
+func parseOtherLanguages(c echo.Context, language string) ([]game.ProblemLanguage, error) {
+	params, err := c.FormParams()
 	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem id")
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
 	}
-	row, err := h.q.GetProblemByID(c.Request().Context(), int32(problemID))
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return echo.NewHTTPError(http.StatusNotFound)
+	var others []game.ProblemLanguage
+	for _, l := range params["other_languages"] {
+		if !slices.Contains(game.Languages, l) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid other_languages")
 		}
-		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		if l == language || slices.ContainsFunc(others, func(o game.ProblemLanguage) bool { return o.Language == l }) {
+			continue
+		}
+		others = append(others, game.ProblemLanguage{
+			Language:   l,
+			SampleCode: c.FormValue("sample_code_" + l),
+		})
 	}
-
-	return c.Render(http.StatusOK, "problem_edit", echo.Map{
-		"BasePath": h.conf.BasePath,
-		"Title":    "Problem Edit",
-		"Problem": echo.Map{
-			"ProblemID":   row.ProblemID,
-			"Title":       row.Title,
-			"Description": row.Description,
-			"Language":    row.Language,
-			"SampleCode":  row.SampleCode,
-		},
-	})
+	return others, nil
 }
 
-func (h *Handler) postProblemEdit(c echo.Context) error {
-	problemID, err := strconv.Atoi(c.Param("problemID"))
-	if err != nil {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+// parseProblemIDs reads the comma-separated problem IDs of a game from the
+// form, in the order they are given.
+func parseProblemIDs(c echo.Context) ([]int, error) {
+	var problemIDs []int
+	for raw := range strings.SplitSeq(c.FormValue("problem_ids"), ",") {
+		problemID, err := strconv.Atoi(strings.TrimSpace(raw))
+		if err != nil || slices.Contains(problemIDs, problemID) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_ids")
+		}
+		problemIDs = append(problemIDs, problemID)
 	}
+	return problemIDs, nil
+}
 
-	title := c.FormValue("title")
-	description := c.FormValue("description")
+// parseTeams reads teams written one per line as "Team Name: 1, 2, 3", where
+// the numbers are the user IDs of the members. Blank lines are ignored.
+func parseTeams(raw string) ([]game.TeamParams, error) {
+	var teams []game.TeamParams
+	for line := range strings.Lines(raw) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		sep := strings.LastIndex(line, ":")
+		if sep < 0 {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid teams")
+		}
+		team := game.TeamParams{DisplayName: strings.TrimSpace(line[:sep])}
+		if team.DisplayName == "" {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid teams")
+		}
+		for rawID := range strings.SplitSeq(line[sep+1:], ",") {
+			userID, err := strconv.Atoi(strings.TrimSpace(rawID))
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid teams")
+			}
+			team.UserIDs = append(team.UserIDs, userID)
+		}
+		teams = append(teams, team)
+	}
+	return teams, nil
+}
+
+// parseUnsolvedPenalty reads the score added for each unsolved problem. It
+// defaults to 0.
+func parseUnsolvedPenalty(c echo.Context) (int, error) {
+	raw := c.FormValue("unsolved_penalty")
+	if raw == "" {
+		return 0, nil
+	}
+	penalty, err := strconv.Atoi(raw)
+	if err != nil || penalty < 0 {
+		return 0, echo.NewHTTPError(http.StatusBadRequest, "Invalid unsolved_penalty")
+	}
+	return penalty, nil
+}
+
+// parseScoring reads the scoring strategy from the form, which must support
+// all the languages of the problem. If it is omitted, fallback is used.
+func parseScoring(c echo.Context, language string, others []game.ProblemLanguage, fallback string) (string, error) {
+	name := c.FormValue("scoring")
+	if name == "" {
+		name = fallback
+	}
+	if _, err := scoring.New(name); err != nil {
+		return "", echo.NewHTTPError(http.StatusBadRequest, "Invalid scoring")
+	}
+	if !scoring.SupportsLanguage(name, language) {
+		return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Scoring %s does not support %s", name, language))
+	}
+	for _, l := range others {
+		if !scoring.SupportsLanguage(name, l.Language) {
+			return "", echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Scoring %s does not support %s", name, l.Language))
+		}
+	}
+	return name, nil
+}
+
+// parseChecker reads the checker settings from the form. If the checker is
+// omitted, the default one is used.
+func parseChecker(c echo.Context) (string, float64, string, error) {
+	name := c.FormValue("checker")
+	if name == "" {
+		name = checker.Default
+	}
+	if !checker.IsValid(name) {
+		return "", 0, "", echo.NewHTTPError(http.StatusBadRequest, "Invalid checker")
+	}
+	var epsilon float64
+	if epsilonRaw := c.FormValue("checker_epsilon"); epsilonRaw != "" {
+		var err error
+		epsilon, err = strconv.ParseFloat(epsilonRaw, 64)
+		if err != nil || !(epsilon >= 0) {
+			return "", 0, "", echo.NewHTTPError(http.StatusBadRequest, "Invalid checker_epsilon")
+		}
+	}
+	code := c.FormValue("checker_code")
+	if name == checker.Special && code == "" {
+		return "", 0, "", echo.NewHTTPError(http.StatusBadRequest, "checker_code is required for the special checker")
+	}
+	return name, epsilon, code, nil
+}
+
+// parseLimits reads the time limit in milliseconds and the memory limit in MiB
+// from the form. Each of them is nil if omitted, meaning the default.
+func parseLimits(c echo.Context) (*int32, *int32, error) {
+	var limits [2]*int32
+	for i, name := range []string{"time_limit_ms", "memory_limit_mib"} {
+		raw := c.FormValue(name)
+		if raw == "" {
+			continue
+		}
+		v, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || v <= 0 {
+			return nil, nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid "+name)
+		}
+		limit := int32(v)
+		limits[i] = &limit
+	}
+	return limits[0], limits[1], nil
+}
+
+// checkMemoryLimit refuses a memory limit in MiB above what the workers for
+// the languages accept, as they would reject every run with it.
+func (h *Handler) checkMemoryLimit(memoryLimitMiB *int32, language string, others []game.ProblemLanguage) error {
+	if memoryLimitMiB == nil {
+		return nil
+	}
+	languages := []string{language}
+	for _, o := range others {
+		languages = append(languages, o.Language)
+	}
+	for _, l := range languages {
+		maxBytes := h.workers.MaxMemoryBytes(l)
+		if maxBytes > 0 && int64(*memoryLimitMiB)*1024*1024 > maxBytes {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("memory_limit_mib exceeds the limit of the %s workers, %d bytes", l, maxBytes))
+		}
+	}
+	return nil
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func (h *Handler) postProblemNew(c echo.Context) error {
+	title := c.FormValue("title")
+	description := c.FormValue("description")
+	language := c.FormValue("language")
+	sampleCode := c.FormValue("sample_code")
+	others, err := parseOtherLanguages(c, language)
+	if err != nil {
+		return err
+	}
+	scoringName, err := parseScoring(c, language, others, scoring.Default)
+	if err != nil {
+		return err
+	}
+	checkerName, checkerEpsilon, checkerCode, err := parseChecker(c)
+	if err != nil {
+		return err
+	}
+	timeLimitMs, memoryLimitMiB, err := parseLimits(c)
+	if err != nil {
+		return err
+	}
+	if err := h.checkMemoryLimit(memoryLimitMiB, language, others); err != nil {
+		return err
+	}
+
+	problemID, err := h.q.CreateProblem(c.Request().Context(), db.CreateProblemParams{
+		Title:          title,
+		Description:    description,
+		Language:       language,
+		SampleCode:     sampleCode,
+		Scoring:        scoringName,
+		Checker:        checkerName,
+		CheckerEpsilon: checkerEpsilon,
+		CheckerCode:    checkerCode,
+		TimeLimitMs:    timeLimitMs,
+		MemoryLimitMib: memoryLimitMiB,
+	})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if len(others) > 0 {
+		if err := h.gameSvc.SetProblemLanguages(c.Request().Context(), int(problemID), others); err != nil {
+			return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+		}
+	}
+
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems")
+}
+
+func (h *Handler) getProblemEdit(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem id")
+	}
+	row, err := h.q.GetProblemByID(c.Request().Context(), int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	languages, err := h.gameSvc.ListProblemLanguages(c.Request().Context(), problemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Render(http.StatusOK, "problem_edit", echo.Map{
+		"BasePath": h.conf.BasePath,
+		"Title":    "Problem Edit",
+		"Problem": echo.Map{
+			"ProblemID":      row.ProblemID,
+			"Title":          row.Title,
+			"Description":    row.Description,
+			"Language":       row.Language,
+			"SampleCode":     row.SampleCode,
+			"Scoring":        row.Scoring,
+			"Checker":        row.Checker,
+			"CheckerEpsilon": row.CheckerEpsilon,
+			"CheckerCode":    row.CheckerCode,
+			"TimeLimitMs":    row.TimeLimitMs,
+			"MemoryLimitMiB": row.MemoryLimitMib,
+		},
+		"ScoringNames":   scoring.Names,
+		"CheckerNames":   checker.Names,
+		"OtherLanguages": otherLanguagesMap(languages[1:]),
+	})
+}
+
+func (h *Handler) postProblemEdit(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	}
+
+	current, err := h.q.GetProblemByID(c.Request().Context(), int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	title := c.FormValue("title")
+	description := c.FormValue("description")
 	language := c.FormValue("language")
 	sampleCode := c.FormValue("sample_code")
+	others, err := parseOtherLanguages(c, language)
+	if err != nil {
+		return err
+	}
+	scoringName, err := parseScoring(c, language, others, current.Scoring)
+	if err != nil {
+		return err
+	}
+	checkerName, checkerEpsilon, checkerCode, err := parseChecker(c)
+	if err != nil {
+		return err
+	}
+	timeLimitMs, memoryLimitMiB, err := parseLimits(c)
+	if err != nil {
+		return err
+	}
+	if err := h.checkMemoryLimit(memoryLimitMiB, language, others); err != nil {
+		return err
+	}
 
-	err = h.q.UpdateProblem(c.Request().Context(), db.UpdateProblemParams{
-		ProblemID:   int32(problemID),
-		Title:       title,
-		Description: description,
-		Language:    language,
-		SampleCode:  sampleCode,
+	err = h.gameSvc.UpdateProblem(c.Request().Context(), game.UpdateProblemParams{
+		ProblemID:      problemID,
+		Title:          title,
+		Description:    description,
+		Language:       language,
+		SampleCode:     sampleCode,
+		Scoring:        scoringName,
+		Checker:        checkerName,
+		CheckerEpsilon: checkerEpsilon,
+		CheckerCode:    checkerCode,
+		TimeLimitMs:    timeLimitMs,
+		MemoryLimitMiB: memoryLimitMiB,
+		OtherLanguages: others,
 	})
 	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, game.ErrLanguageNotAllowed) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
 	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems")
 }
 
+func (h *Handler) postProblemImport(c echo.Context) error {
+	fh, err := c.FormFile("package")
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "package is required")
+	}
+	f, err := fh.Open()
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	defer f.Close()
+
+	pkg, err := problempkg.ReadZip(f, fh.Size)
+	if err != nil {
+		if errors.Is(err, problempkg.ErrInvalidPackage) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	problemID, err := h.problemPkgSvc.Import(c.Request().Context(), pkg)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID))
+}
+
+func (h *Handler) getProblemExport(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem id")
+	}
+	pkg, err := h.problemPkgSvc.Export(c.Request().Context(), problemID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "application/zip")
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=\"problem-%d.zip\"", problemID))
+	res.WriteHeader(http.StatusOK)
+	if err := pkg.WriteZip(res); err != nil {
+		slog.Error("failed to write problem package", "problem_id", problemID, "error", err)
+	}
+	return nil
+}
+
 func (h *Handler) getTestcases(c echo.Context) error {
 	problemID, err := strconv.Atoi(c.Param("problemID"))
 	if err != nil {
@@ -802,14 +1503,37 @@ func (h *Handler) getTestcases(c echo.Context) error {
 			"ProblemID":  tc.ProblemID,
 			"Stdin":      tc.Stdin,
 			"Stdout":     tc.Stdout,
+			"IsSample":   tc.IsSample,
+		}
+	}
+
+	staleCount, err := h.gameSvc.CountStaleSubmissions(c.Request().Context(), problemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	revisionRows, err := h.gameSvc.ListTestcaseRevisions(c.Request().Context(), problemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	revisions := make([]echo.Map, len(revisionRows))
+	for i, r := range revisionRows {
+		revisions[i] = echo.Map{
+			"TestcaseID":     r.TestcaseID,
+			"Action":         r.Action,
+			"IsSample":       r.IsSample,
+			"AffectsResults": r.AffectsResults,
+			"UserID":         r.UserID,
+			"CreatedAt":      r.CreatedAt.In(jst).Format("2006-01-02T15:04:05"),
 		}
 	}
 
 	return c.Render(http.StatusOK, "testcases", echo.Map{
-		"BasePath":  h.conf.BasePath,
-		"Title":     "Testcases for " + problem.Title,
-		"Problem":   echo.Map{"ProblemID": problem.ProblemID, "Title": problem.Title},
-		"Testcases": testcases,
+		"BasePath":   h.conf.BasePath,
+		"Title":      "Testcases for " + problem.Title,
+		"Problem":    echo.Map{"ProblemID": problem.ProblemID, "Title": problem.Title},
+		"Testcases":  testcases,
+		"StaleCount": staleCount,
+		"Revisions":  revisions,
 	})
 }
 
@@ -840,19 +1564,57 @@ func (h *Handler) postTestcaseNew(c echo.Context) error {
 	if err != nil {
 		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
 	}
-	stdin := c.FormValue("stdin")
-	stdout := c.FormValue("stdout")
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
 
-	_, err = h.q.CreateTestcase(c.Request().Context(), db.CreateTestcaseParams{
-		ProblemID: int32(problemID),
-		Stdin:     stdin,
-		Stdout:    stdout,
-	})
+	params, err := testcaseParamsFromForm(c)
+	if err != nil {
+		return err
+	}
+	testcaseID, err := h.gameSvc.CreateTestcase(c.Request().Context(), problemID, params, user.UserID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/testcases")
+	return h.rejudgeAfterTestcaseChange(c, problemID, fmt.Sprintf("testcase %d created", testcaseID), user.UserID)
+}
+
+func testcaseParamsFromForm(c echo.Context) (game.TestcaseParams, error) {
+	timeLimitMs, memoryLimitMiB, err := parseLimits(c)
+	if err != nil {
+		return game.TestcaseParams{}, err
+	}
+	return game.TestcaseParams{
+		Stdin:          c.FormValue("stdin"),
+		Stdout:         c.FormValue("stdout"),
+		IsSample:       c.FormValue("is_sample") != "",
+		TimeLimitMs:    intPtr(timeLimitMs),
+		MemoryLimitMiB: intPtr(memoryLimitMiB),
+	}, nil
+}
+
+// rejudgeAfterTestcaseChange rejudges the submissions judged with the old
+// testcases if the admin asked to, and shows the rejudge. Otherwise the
+// testcases page prompts to rejudge them later.
+func (h *Handler) rejudgeAfterTestcaseChange(c echo.Context, problemID int, reason string, adminID int32) error {
+	testcasesURL := h.conf.BasePath + "admin/problems/" + strconv.Itoa(problemID) + "/testcases"
+	if c.FormValue("rejudge") == "" {
+		return c.Redirect(http.StatusSeeOther, testcasesURL)
+	}
+	rejudgeID, err := h.gameSvc.RejudgeStaleSubmissions(c.Request().Context(), problemID, reason, adminID)
+	if err != nil {
+		if errors.Is(err, game.ErrNoTestcases) {
+			// Nothing can be judged until a testcase is added.
+			return c.Redirect(http.StatusSeeOther, testcasesURL)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if rejudgeID == 0 {
+		return c.Redirect(http.StatusSeeOther, testcasesURL)
+	}
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/rejudges/"+strconv.Itoa(rejudgeID))
 }
 
 func (h *Handler) getTestcaseEdit(c echo.Context) error {
@@ -893,10 +1655,13 @@ func (h *Handler) getTestcaseEdit(c echo.Context) error {
 		"Title":    "Edit Testcase for " + problem.Title,
 		"Problem":  echo.Map{"ProblemID": problem.ProblemID, "Title": problem.Title},
 		"Testcase": echo.Map{
-			"TestcaseID": testcase.TestcaseID,
-			"ProblemID":  testcase.ProblemID,
-			"Stdin":      testcase.Stdin,
-			"Stdout":     testcase.Stdout,
+			"TestcaseID":     testcase.TestcaseID,
+			"ProblemID":      testcase.ProblemID,
+			"Stdin":          testcase.Stdin,
+			"Stdout":         testcase.Stdout,
+			"IsSample":       testcase.IsSample,
+			"TimeLimitMs":    testcase.TimeLimitMs,
+			"MemoryLimitMiB": testcase.MemoryLimitMib,
 		},
 	})
 }
@@ -923,20 +1688,21 @@ func (h *Handler) postTestcaseEdit(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound)
 	}
 
-	stdin := c.FormValue("stdin")
-	stdout := c.FormValue("stdout")
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
 
-	err = h.q.UpdateTestcase(c.Request().Context(), db.UpdateTestcaseParams{
-		TestcaseID: int32(testcaseID),
-		ProblemID:  int32(problemID),
-		Stdin:      stdin,
-		Stdout:     stdout,
-	})
+	params, err := testcaseParamsFromForm(c)
+	if err != nil {
+		return err
+	}
+	err = h.gameSvc.UpdateTestcase(c.Request().Context(), testcaseID, params, user.UserID)
 	if err != nil {
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/testcases")
+	return h.rejudgeAfterTestcaseChange(c, problemID, fmt.Sprintf("testcase %d updated", testcaseID), user.UserID)
 }
 
 func (h *Handler) postTestcaseDelete(c echo.Context) error {
@@ -961,12 +1727,249 @@ func (h *Handler) postTestcaseDelete(c echo.Context) error {
 		return echo.NewHTTPError(http.StatusNotFound)
 	}
 
-	err = h.q.DeleteTestcase(c.Request().Context(), int32(testcaseID))
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
+
+	err = h.gameSvc.DeleteTestcase(c.Request().Context(), testcaseID, user.UserID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return h.rejudgeAfterTestcaseChange(c, problemID, fmt.Sprintf("testcase %d deleted", testcaseID), user.UserID)
+}
+
+func (h *Handler) postProblemRejudge(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	}
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
+
+	rejudgeID, err := h.gameSvc.RejudgeStaleSubmissions(c.Request().Context(), problemID, "requested from the testcases page", user.UserID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, game.ErrNoTestcases) {
+			return echo.NewHTTPError(http.StatusBadRequest, "No testcases")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	if rejudgeID == 0 {
+		return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/testcases")
+	}
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/rejudges/"+strconv.Itoa(rejudgeID))
+}
+
+func (h *Handler) getRejudges(c echo.Context) error {
+	rows, err := h.gameSvc.ListRejudges(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rejudges := make([]echo.Map, len(rows))
+	for i, r := range rows {
+		rejudges[i] = rejudgeMap(r)
+	}
+
+	return c.Render(http.StatusOK, "rejudges", echo.Map{
+		"BasePath": h.conf.BasePath,
+		"Title":    "Rejudges",
+		"Rejudges": rejudges,
+	})
+}
+
+func (h *Handler) getRejudge(c echo.Context) error {
+	rejudgeID, err := strconv.Atoi(c.Param("rejudgeID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid rejudge_id")
+	}
+
+	rejudge, submissionRows, changeRows, err := h.gameSvc.GetRejudge(c.Request().Context(), rejudgeID)
 	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	submissions := make([]echo.Map, len(submissionRows))
+	for i, r := range submissionRows {
+		submissions[i] = echo.Map{
+			"SubmissionID": r.SubmissionID,
+			"GameID":       r.GameID,
+			"TeamID":       r.TeamID,
+			"IsPractice":   r.IsPractice,
+			"StatusBefore": r.StatusBefore,
+			"Status":       r.Status,
+			"Changed":      r.Status != "running" && r.Status != r.StatusBefore,
+		}
+	}
+	changes := make([]echo.Map, len(changeRows))
+	for i, r := range changeRows {
+		changes[i] = echo.Map{
+			"GameID":      r.GameID,
+			"TeamID":      r.TeamID,
+			"TeamName":    r.TeamName,
+			"RankBefore":  r.RankBefore,
+			"ScoreBefore": r.ScoreBefore,
+			"RankAfter":   r.RankAfter,
+			"ScoreAfter":  r.ScoreAfter,
+		}
+	}
+
+	return c.Render(http.StatusOK, "rejudge", echo.Map{
+		"BasePath":       h.conf.BasePath,
+		"Title":          fmt.Sprintf("Rejudge %d", rejudgeID),
+		"Rejudge":        rejudgeMap(rejudge),
+		"Submissions":    submissions,
+		"RankingChanges": changes,
+	})
+}
+
+func rejudgeMap(r game.Rejudge) echo.Map {
+	finishedAt := ""
+	if r.FinishedAt != nil {
+		finishedAt = r.FinishedAt.In(jst).Format("2006-01-02T15:04:05")
+	}
+	return echo.Map{
+		"RejudgeID":  r.RejudgeID,
+		"ProblemID":  r.ProblemID,
+		"Reason":     r.Reason,
+		"UserID":     r.UserID,
+		"CreatedAt":  r.CreatedAt.In(jst).Format("2006-01-02T15:04:05"),
+		"FinishedAt": finishedAt,
+	}
+}
+
+func (h *Handler) getProblemValidation(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem id")
+	}
+	problem, err := h.q.GetProblemByID(c.Request().Context(), int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	solutionRows, err := h.gameSvc.ListReferenceSolutions(c.Request().Context(), problemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	solutions := make([]echo.Map, len(solutionRows))
+	for i, r := range solutionRows {
+		solutions[i] = echo.Map{
+			"ReferenceSolutionID": r.ReferenceSolutionID,
+			"Name":                r.Name,
+			"Code":                r.Code,
+			"CreatedAt":           r.CreatedAt.In(jst).Format("2006-01-02T15:04:05"),
+		}
+	}
+	validation, err := h.gameSvc.GetValidation(c.Request().Context(), problemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	results := make([]echo.Map, len(validation.Results))
+	for i, r := range validation.Results {
+		results[i] = echo.Map{
+			"ReferenceSolutionID": r.ReferenceSolutionID,
+			"TestcaseID":          r.TestcaseID,
+			"Status":              r.Status,
+			"Stdout":              r.Stdout,
+			"Stderr":              r.Stderr,
+		}
+	}
+	finishedAt := ""
+	if validation.FinishedAt != nil {
+		finishedAt = validation.FinishedAt.In(jst).Format("2006-01-02T15:04:05")
+	}
+
+	return c.Render(http.StatusOK, "problem_validation", echo.Map{
+		"BasePath":           h.conf.BasePath,
+		"Title":              "Validation of " + problem.Title,
+		"Problem":            echo.Map{"ProblemID": problem.ProblemID, "Title": problem.Title},
+		"ReferenceSolutions": solutions,
+		"Validation": echo.Map{
+			"State":        string(validation.State),
+			"ValidationID": validation.ValidationID,
+			"CreatedAt":    validation.CreatedAt.In(jst).Format("2006-01-02T15:04:05"),
+			"FinishedAt":   finishedAt,
+			"Results":      results,
+		},
+	})
+}
+
+func (h *Handler) postProblemValidation(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	}
+	user, ok := session.GetUserFromContext(c.Request().Context())
+	if !ok {
+		return echo.NewHTTPError(http.StatusUnauthorized)
+	}
+
+	if _, err := h.gameSvc.ValidateProblem(c.Request().Context(), problemID, user.UserID); err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, game.ErrNoTestcases) {
+			return echo.NewHTTPError(http.StatusBadRequest, "No testcases")
+		}
+		if errors.Is(err, game.ErrNoReferenceSolutions) {
+			return echo.NewHTTPError(http.StatusBadRequest, "No reference solutions")
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/validation")
+}
+
+func (h *Handler) postReferenceSolutionNew(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	}
+	name := c.FormValue("name")
+	code := c.FormValue("code")
+	if name == "" || code == "" {
+		return echo.NewHTTPError(http.StatusBadRequest, "Name and code are required")
+	}
+
+	if _, err := h.gameSvc.CreateReferenceSolution(c.Request().Context(), problemID, name, code); err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/validation")
+}
+
+func (h *Handler) postReferenceSolutionDelete(c echo.Context) error {
+	problemID, err := strconv.Atoi(c.Param("problemID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid problem_id")
+	}
+	referenceSolutionID, err := strconv.Atoi(c.Param("referenceSolutionID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid reference_solution_id")
+	}
+
+	if err := h.gameSvc.DeleteReferenceSolution(c.Request().Context(), problemID, referenceSolutionID); err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
 		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
 
-	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/testcases")
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/problems/"+strconv.Itoa(problemID)+"/validation")
 }
 
 func (h *Handler) getTournaments(c echo.Context) error {
@@ -1157,3 +2160,218 @@ func (h *Handler) postTournamentEdit(c echo.Context) error {
 
 	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/tournaments")
 }
+
+func (h *Handler) getTournamentExport(c echo.Context) error {
+	tournamentID, err := strconv.Atoi(c.Param("tournamentID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid tournament id")
+	}
+	return h.writeExport(c, func(ctx context.Context) (*export.Archive, error) {
+		return h.exportSvc.Tournament(ctx, tournamentID)
+	})
+}
+
+func (h *Handler) getQualifyingStages(c echo.Context) error {
+	stages, err := h.qualifyingSvc.ListStages(c.Request().Context(), true)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Render(http.StatusOK, "qualifying_stages", echo.Map{
+		"BasePath": h.conf.BasePath,
+		"Title":    "Qualifying Stages",
+		"Stages":   stages,
+	})
+}
+
+func (h *Handler) getQualifyingStageNew(c echo.Context) error {
+	return c.Render(http.StatusOK, "qualifying_stage_new", echo.Map{
+		"BasePath":        h.conf.BasePath,
+		"Title":           "New Qualifying Stage",
+		"MissingPolicies": qualifying.MissingPolicies,
+	})
+}
+
+func (h *Handler) postQualifyingStageNew(c echo.Context) error {
+	params, err := parseQualifyingStage(c)
+	if err != nil {
+		return err
+	}
+	stageID, err := h.qualifyingSvc.CreateStage(c.Request().Context(), params)
+	if err != nil {
+		if errors.Is(err, qualifying.ErrUnknownMissingPolicy) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid missing_policy")
+		}
+		if errors.Is(err, qualifying.ErrGameNotPublic) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/qualifying/%d", h.conf.BasePath, stageID))
+}
+
+func (h *Handler) getQualifyingStageEdit(c echo.Context) error {
+	stageID, err := strconv.Atoi(c.Param("stageID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid stage id")
+	}
+	stage, err := h.qualifyingSvc.GetStage(c.Request().Context(), stageID, true)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	var gamesText strings.Builder
+	for _, g := range stage.Games {
+		fmt.Fprintf(&gamesText, "%d: %s\n", g.GameID, strconv.FormatFloat(g.Weight, 'f', -1, 64))
+	}
+	return c.Render(http.StatusOK, "qualifying_stage_edit", echo.Map{
+		"BasePath":        h.conf.BasePath,
+		"Title":           "Edit Qualifying Stage",
+		"Stage":           stage,
+		"GamesText":       gamesText.String(),
+		"MissingPolicies": qualifying.MissingPolicies,
+	})
+}
+
+func (h *Handler) postQualifyingStageEdit(c echo.Context) error {
+	stageID, err := strconv.Atoi(c.Param("stageID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid stage id")
+	}
+	params, err := parseQualifyingStage(c)
+	if err != nil {
+		return err
+	}
+	err = h.qualifyingSvc.UpdateStage(c.Request().Context(), stageID, params)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		if errors.Is(err, qualifying.ErrUnknownMissingPolicy) {
+			return echo.NewHTTPError(http.StatusBadRequest, "Invalid missing_policy")
+		}
+		if errors.Is(err, qualifying.ErrGameNotPublic) {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Redirect(http.StatusSeeOther, fmt.Sprintf("%sadmin/qualifying/%d", h.conf.BasePath, stageID))
+}
+
+func (h *Handler) getQualifyingStageRanking(c echo.Context) error {
+	stageID, err := strconv.Atoi(c.Param("stageID"))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, "Invalid stage id")
+	}
+	stage, entries, err := h.qualifyingSvc.GetRanking(c.Request().Context(), stageID, true)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return echo.NewHTTPError(http.StatusNotFound)
+		}
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	rows := make([]echo.Map, len(entries))
+	for i, e := range entries {
+		scores := make([]string, len(e.Results))
+		for j, r := range e.Results {
+			if r == nil {
+				scores[j] = "-"
+			} else {
+				scores[j] = strconv.Itoa(r.Score)
+			}
+		}
+		rows[i] = echo.Map{
+			"Rank":        e.Rank,
+			"Username":    e.Player.Username,
+			"UserLabel":   e.Player.Label,
+			"Scores":      scores,
+			"Total":       strconv.FormatFloat(e.Total, 'f', -1, 64),
+			"SubmittedAt": e.SubmittedAt.In(jst).Format("2006-01-02T15:04"),
+		}
+	}
+	return c.Render(http.StatusOK, "qualifying_stage_ranking", echo.Map{
+		"BasePath": h.conf.BasePath,
+		"Title":    "Qualifying Ranking: " + stage.DisplayName,
+		"Stage":    stage,
+		"Entries":  rows,
+	})
+}
+
+// parseQualifyingStage reads the settings of a qualifying stage from the form.
+func parseQualifyingStage(c echo.Context) (qualifying.StageParams, error) {
+	params := qualifying.StageParams{
+		DisplayName:   c.FormValue("display_name"),
+		IsPublic:      c.FormValue("is_public") != "",
+		MissingPolicy: c.FormValue("missing_policy"),
+	}
+	if raw := c.FormValue("missing_penalty"); raw != "" {
+		penalty, err := strconv.Atoi(raw)
+		if err != nil || penalty < 0 {
+			return params, echo.NewHTTPError(http.StatusBadRequest, "Invalid missing_penalty")
+		}
+		params.MissingPenalty = penalty
+	}
+	games, err := parseStageGames(c.FormValue("games"))
+	if err != nil {
+		return params, err
+	}
+	params.Games = games
+	return params, nil
+}
+
+// parseStageGames reads the games of a qualifying stage written one per line
+// as "game_id: weight". The weight defaults to 1. Blank lines are ignored.
+func parseStageGames(raw string) ([]qualifying.StageGameParams, error) {
+	var games []qualifying.StageGameParams
+	for line := range strings.Lines(raw) {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		rawID, rawWeight, hasWeight := strings.Cut(line, ":")
+		gameID, err := strconv.Atoi(strings.TrimSpace(rawID))
+		if err != nil || slices.ContainsFunc(games, func(g qualifying.StageGameParams) bool { return g.GameID == gameID }) {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid games")
+		}
+		weight := 1.0
+		if hasWeight {
+			weight, err = strconv.ParseFloat(strings.TrimSpace(rawWeight), 64)
+			if err != nil || weight < 0 {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid games")
+			}
+		}
+		games = append(games, qualifying.StageGameParams{GameID: gameID, Weight: weight})
+	}
+	return games, nil
+}
+
+func (h *Handler) getRatings(c echo.Context) error {
+	players, err := h.ratingSvc.Leaderboard(c.Request().Context())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	users := make([]echo.Map, len(players))
+	for i, p := range players {
+		users[i] = echo.Map{
+			"UserID":      p.UserID,
+			"Username":    p.Username,
+			"DisplayName": p.DisplayName,
+			"Rating":      p.Rating,
+			"RatingRank":  *p.RatingRank,
+		}
+	}
+
+	return c.Render(http.StatusOK, "ratings", echo.Map{
+		"BasePath": h.conf.BasePath,
+		"Title":    "Ratings",
+		"Users":    users,
+	})
+}
+
+func (h *Handler) postRatingsRecompute(c echo.Context) error {
+	if err := h.ratingSvc.Recompute(c.Request().Context()); err != nil {
+		return echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return c.Redirect(http.StatusSeeOther, h.conf.BasePath+"admin/ratings")
+}
@@ -1,23 +1,35 @@
 package admin
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
+	"mime/multipart"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
 
+	"albatross-2026-backend/checker"
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
+	"albatross-2026-backend/export"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/problempkg"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/ranking"
+	"albatross-2026-backend/rating"
+	"albatross-2026-backend/scoring"
 	"albatross-2026-backend/session"
+	"albatross-2026-backend/taskqueue"
 	"albatross-2026-backend/tournament"
 )
 
@@ -28,7 +40,7 @@ type mockQuerier struct {
 	listUsersFunc                           func(ctx context.Context) ([]db.User, error)
 	updateUserFunc                          func(ctx context.Context, arg db.UpdateUserParams) error
 	listAllGamesFunc                        func(ctx context.Context) ([]db.Game, error)
-	getGameByIDFunc                         func(ctx context.Context, gameID int32) (db.GetGameByIDRow, error)
+	getGameByIDFunc                         func(ctx context.Context, gameID int32) (db.Game, error)
 	listProblemsFunc                        func(ctx context.Context) ([]db.Problem, error)
 	getProblemByIDFunc                      func(ctx context.Context, problemID int32) (db.Problem, error)
 	createGameFunc                          func(ctx context.Context, arg db.CreateGameParams) (int32, error)
@@ -40,6 +52,11 @@ type mockQuerier struct {
 	updateTestcaseFunc                      func(ctx context.Context, arg db.UpdateTestcaseParams) error
 	deleteTestcaseFunc                      func(ctx context.Context, testcaseID int32) error
 	deleteTestcaseResultsBySubmissionIDFunc func(ctx context.Context, submissionID int32) error
+	createTestcaseRevisionFunc              func(ctx context.Context, arg db.CreateTestcaseRevisionParams) error
+	listStaleSubmissionsByProblemIDFunc     func(ctx context.Context, problemID int32) ([]db.Submission, error)
+	createRejudgeFunc                       func(ctx context.Context, arg db.CreateRejudgeParams) (int32, error)
+	getLatestProblemValidationFunc          func(ctx context.Context, problemID int32) (db.ProblemValidation, error)
+	listReferenceSolutionsByProblemIDFunc   func(ctx context.Context, problemID int32) ([]db.ReferenceSolution, error)
 	listMainPlayersFunc                     func(ctx context.Context, gameIDs []int32) ([]db.ListMainPlayersRow, error)
 	listSubmissionIDsFunc                   func(ctx context.Context) ([]int32, error)
 	getSubmissionsByGameIDFunc              func(ctx context.Context, gameID int32) ([]db.Submission, error)
@@ -47,8 +64,16 @@ type mockQuerier struct {
 	getSubmissionByIDFunc                   func(ctx context.Context, submissionID int32) (db.Submission, error)
 	getTestcaseResultsBySubmIDFunc          func(ctx context.Context, submissionID int32) ([]db.TestcaseResult, error)
 	updateSubmissionStatusFunc              func(ctx context.Context, arg db.UpdateSubmissionStatusParams) error
-	listTestcasesByGameIDFunc               func(ctx context.Context, gameID int32) ([]db.Testcase, error)
-	updateGameStartedAtFunc                 func(ctx context.Context, arg db.UpdateGameStartedAtParams) error
+	listGameProblemsFunc                    func(ctx context.Context, gameIDs []int32) ([]db.ListGameProblemsRow, error)
+	listProblemLanguagesFunc                func(ctx context.Context, problemIDs []int32) ([]db.ProblemLanguage, error)
+	deleteProblemLanguagesFunc              func(ctx context.Context, problemID int32) error
+	createProblemLanguageFunc               func(ctx context.Context, arg db.CreateProblemLanguageParams) error
+	addGameProblemFunc                      func(ctx context.Context, arg db.AddGameProblemParams) error
+	removeAllGameProblemsFunc               func(ctx context.Context, gameID int32) error
+	getGameLifecycleForUpdateFunc           func(ctx context.Context, gameID int32) (db.GetGameLifecycleForUpdateRow, error)
+	updateGameLifecycleFunc                 func(ctx context.Context, arg db.UpdateGameLifecycleParams) error
+	createGameLifecycleEventFunc            func(ctx context.Context, arg db.CreateGameLifecycleEventParams) error
+	listGameLifecycleEventsFunc             func(ctx context.Context, gameID int32) ([]db.GameLifecycleEvent, error)
 	listTournamentsFunc                     func(ctx context.Context) ([]db.Tournament, error)
 	getTournamentByIDFunc                   func(ctx context.Context, tournamentID int32) (db.Tournament, error)
 	createTournamentFunc                    func(ctx context.Context, arg db.CreateTournamentParams) (int32, error)
@@ -60,11 +85,24 @@ type mockQuerier struct {
 	createTournamentMatchFunc               func(ctx context.Context, arg db.CreateTournamentMatchParams) error
 	updateTournamentMatchGameFunc           func(ctx context.Context, arg db.UpdateTournamentMatchGameParams) error
 	updateGameFunc                          func(ctx context.Context, arg db.UpdateGameParams) error
+	getRankingFunc                          func(ctx context.Context, gameID int32) ([]db.GetRankingRow, error)
+	listBestPracticeSubmissionsFunc         func(ctx context.Context, arg db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error)
 	removeAllMainPlayersFunc                func(ctx context.Context, gameID int32) error
 	addMainPlayerFunc                       func(ctx context.Context, arg db.AddMainPlayerParams) error
 	aggregateTestcaseResultsFunc            func(ctx context.Context, submissionID int32) (string, error)
 	listGameStateIDsFunc                    func(ctx context.Context) ([]db.ListGameStateIDsRow, error)
 	syncGameStateBestScoreSubmissionFunc    func(ctx context.Context, arg db.SyncGameStateBestScoreSubmissionParams) error
+	listSubmissionsByProblemIDFunc          func(ctx context.Context, problemID int32) ([]db.Submission, error)
+	updateSubmissionCodeSizeFunc            func(ctx context.Context, arg db.UpdateSubmissionCodeSizeParams) error
+	listGameStateIDsByProblemIDFunc         func(ctx context.Context, problemID int32) ([]db.ListGameStateIDsByProblemIDRow, error)
+	listTeamsFunc                           func(ctx context.Context, gameID int32) ([]db.GameTeam, error)
+	listTeamMembersFunc                     func(ctx context.Context, gameID int32) ([]db.ListTeamMembersRow, error)
+	createTeamFunc                          func(ctx context.Context, arg db.CreateTeamParams) (int32, error)
+	addTeamMemberFunc                       func(ctx context.Context, arg db.AddTeamMemberParams) (int64, error)
+	removeAllTeamMembersFunc                func(ctx context.Context, gameID int32) error
+	removeAllTeamsFunc                      func(ctx context.Context, gameID int32) error
+	createQualifyingStageFunc               func(ctx context.Context, arg db.CreateQualifyingStageParams) (int32, error)
+	addQualifyingStageGameFunc              func(ctx context.Context, arg db.AddQualifyingStageGameParams) error
 }
 
 func (m *mockQuerier) GetUserByID(ctx context.Context, userID int32) (db.User, error) {
@@ -95,11 +133,25 @@ func (m *mockQuerier) ListAllGames(ctx context.Context) ([]db.Game, error) {
 	return nil, nil
 }
 
-func (m *mockQuerier) GetGameByID(ctx context.Context, gameID int32) (db.GetGameByIDRow, error) {
+func (m *mockQuerier) GetGameByID(ctx context.Context, gameID int32) (db.Game, error) {
 	if m.getGameByIDFunc != nil {
 		return m.getGameByIDFunc(ctx, gameID)
 	}
-	return db.GetGameByIDRow{}, pgx.ErrNoRows
+	return db.Game{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) GetRanking(ctx context.Context, gameID int32) ([]db.GetRankingRow, error) {
+	if m.getRankingFunc != nil {
+		return m.getRankingFunc(ctx, gameID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListBestPracticeSubmissions(ctx context.Context, arg db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error) {
+	if m.listBestPracticeSubmissionsFunc != nil {
+		return m.listBestPracticeSubmissionsFunc(ctx, arg)
+	}
+	return nil, nil
 }
 
 func (m *mockQuerier) ListProblems(ctx context.Context) ([]db.Problem, error) {
@@ -214,20 +266,82 @@ func (m *mockQuerier) GetTestcaseResultsBySubmissionID(ctx context.Context, subm
 	return nil, nil
 }
 
-func (m *mockQuerier) ListTestcasesByGameID(ctx context.Context, gameID int32) ([]db.Testcase, error) {
-	if m.listTestcasesByGameIDFunc != nil {
-		return m.listTestcasesByGameIDFunc(ctx, gameID)
+// ListGameProblems returns problem 1 as the only problem of each game unless
+// overridden.
+func (m *mockQuerier) ListGameProblems(ctx context.Context, gameIDs []int32) ([]db.ListGameProblemsRow, error) {
+	if m.listGameProblemsFunc != nil {
+		return m.listGameProblemsFunc(ctx, gameIDs)
+	}
+	rows := make([]db.ListGameProblemsRow, len(gameIDs))
+	for i, gameID := range gameIDs {
+		rows[i] = db.ListGameProblemsRow{GameID: gameID, Problem: db.Problem{ProblemID: 1, Language: "php"}}
+	}
+	return rows, nil
+}
+
+func (m *mockQuerier) ListProblemLanguages(ctx context.Context, problemIDs []int32) ([]db.ProblemLanguage, error) {
+	if m.listProblemLanguagesFunc != nil {
+		return m.listProblemLanguagesFunc(ctx, problemIDs)
 	}
 	return nil, nil
 }
 
-func (m *mockQuerier) UpdateGameStartedAt(ctx context.Context, arg db.UpdateGameStartedAtParams) error {
-	if m.updateGameStartedAtFunc != nil {
-		return m.updateGameStartedAtFunc(ctx, arg)
+func (m *mockQuerier) DeleteProblemLanguages(ctx context.Context, problemID int32) error {
+	if m.deleteProblemLanguagesFunc != nil {
+		return m.deleteProblemLanguagesFunc(ctx, problemID)
+	}
+	return nil
+}
+
+func (m *mockQuerier) CreateProblemLanguage(ctx context.Context, arg db.CreateProblemLanguageParams) error {
+	if m.createProblemLanguageFunc != nil {
+		return m.createProblemLanguageFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) AddGameProblem(ctx context.Context, arg db.AddGameProblemParams) error {
+	if m.addGameProblemFunc != nil {
+		return m.addGameProblemFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) RemoveAllGameProblems(ctx context.Context, gameID int32) error {
+	if m.removeAllGameProblemsFunc != nil {
+		return m.removeAllGameProblemsFunc(ctx, gameID)
+	}
+	return nil
+}
+
+func (m *mockQuerier) GetGameLifecycleForUpdate(ctx context.Context, gameID int32) (db.GetGameLifecycleForUpdateRow, error) {
+	if m.getGameLifecycleForUpdateFunc != nil {
+		return m.getGameLifecycleForUpdateFunc(ctx, gameID)
+	}
+	return db.GetGameLifecycleForUpdateRow{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) UpdateGameLifecycle(ctx context.Context, arg db.UpdateGameLifecycleParams) error {
+	if m.updateGameLifecycleFunc != nil {
+		return m.updateGameLifecycleFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) CreateGameLifecycleEvent(ctx context.Context, arg db.CreateGameLifecycleEventParams) error {
+	if m.createGameLifecycleEventFunc != nil {
+		return m.createGameLifecycleEventFunc(ctx, arg)
 	}
 	return nil
 }
 
+func (m *mockQuerier) ListGameLifecycleEvents(ctx context.Context, gameID int32) ([]db.GameLifecycleEvent, error) {
+	if m.listGameLifecycleEventsFunc != nil {
+		return m.listGameLifecycleEventsFunc(ctx, gameID)
+	}
+	return nil, nil
+}
+
 func (m *mockQuerier) ListTestcasesByProblemIDForUpdate(ctx context.Context, problemID int32) ([]db.Testcase, error) {
 	return m.ListTestcasesByProblemID(ctx, problemID)
 }
@@ -302,6 +416,62 @@ func (m *mockQuerier) DeleteTestcaseResultsBySubmissionID(ctx context.Context, s
 	return nil
 }
 
+func (m *mockQuerier) DeleteTestcaseResultsByTestcaseID(_ context.Context, _ int32) error {
+	return nil
+}
+
+func (m *mockQuerier) CreateTestcaseRevision(ctx context.Context, arg db.CreateTestcaseRevisionParams) error {
+	if m.createTestcaseRevisionFunc != nil {
+		return m.createTestcaseRevisionFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) ListTestcaseRevisionsByProblemID(_ context.Context, _ int32) ([]db.TestcaseRevision, error) {
+	return nil, nil
+}
+
+func (m *mockQuerier) ListStaleSubmissionsByProblemID(ctx context.Context, problemID int32) ([]db.Submission, error) {
+	if m.listStaleSubmissionsByProblemIDFunc != nil {
+		return m.listStaleSubmissionsByProblemIDFunc(ctx, problemID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) CreateRejudge(ctx context.Context, arg db.CreateRejudgeParams) (int32, error) {
+	if m.createRejudgeFunc != nil {
+		return m.createRejudgeFunc(ctx, arg)
+	}
+	return 1, nil
+}
+
+func (m *mockQuerier) CreateRejudgeSubmission(_ context.Context, _ db.CreateRejudgeSubmissionParams) error {
+	return nil
+}
+
+func (m *mockQuerier) GetRejudgeByID(_ context.Context, _ int32) (db.Rejudge, error) {
+	return db.Rejudge{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) GetLatestProblemValidation(ctx context.Context, problemID int32) (db.ProblemValidation, error) {
+	if m.getLatestProblemValidationFunc != nil {
+		return m.getLatestProblemValidationFunc(ctx, problemID)
+	}
+	return db.ProblemValidation{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) ListReferenceSolutionsByProblemID(ctx context.Context, problemID int32) ([]db.ReferenceSolution, error) {
+	if m.listReferenceSolutionsByProblemIDFunc != nil {
+		return m.listReferenceSolutionsByProblemIDFunc(ctx, problemID)
+	}
+	return nil, nil
+}
+
+// GetProblemJudgeChangedAt reports no changes since any validation.
+func (m *mockQuerier) GetProblemJudgeChangedAt(_ context.Context, _ int32) (pgtype.Timestamp, error) {
+	return pgtype.Timestamp{}, nil
+}
+
 func (m *mockQuerier) UpdateSubmissionStatus(ctx context.Context, arg db.UpdateSubmissionStatusParams) error {
 	if m.updateSubmissionStatusFunc != nil {
 		return m.updateSubmissionStatusFunc(ctx, arg)
@@ -358,22 +528,113 @@ func (m *mockQuerier) SyncGameStateBestScoreSubmission(ctx context.Context, arg
 	return nil
 }
 
+func (m *mockQuerier) ListSubmissionsByProblemID(ctx context.Context, problemID int32) ([]db.Submission, error) {
+	if m.listSubmissionsByProblemIDFunc != nil {
+		return m.listSubmissionsByProblemIDFunc(ctx, problemID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) UpdateSubmissionCodeSize(ctx context.Context, arg db.UpdateSubmissionCodeSizeParams) error {
+	if m.updateSubmissionCodeSizeFunc != nil {
+		return m.updateSubmissionCodeSizeFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) ListGameStateIDsByProblemID(ctx context.Context, problemID int32) ([]db.ListGameStateIDsByProblemIDRow, error) {
+	if m.listGameStateIDsByProblemIDFunc != nil {
+		return m.listGameStateIDsByProblemIDFunc(ctx, problemID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListTeams(ctx context.Context, gameID int32) ([]db.GameTeam, error) {
+	if m.listTeamsFunc != nil {
+		return m.listTeamsFunc(ctx, gameID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListTeamMembers(ctx context.Context, gameID int32) ([]db.ListTeamMembersRow, error) {
+	if m.listTeamMembersFunc != nil {
+		return m.listTeamMembersFunc(ctx, gameID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) CreateTeam(ctx context.Context, arg db.CreateTeamParams) (int32, error) {
+	if m.createTeamFunc != nil {
+		return m.createTeamFunc(ctx, arg)
+	}
+	return 1, nil
+}
+
+func (m *mockQuerier) CreateQualifyingStage(ctx context.Context, arg db.CreateQualifyingStageParams) (int32, error) {
+	if m.createQualifyingStageFunc != nil {
+		return m.createQualifyingStageFunc(ctx, arg)
+	}
+	return 1, nil
+}
+
+func (m *mockQuerier) AddQualifyingStageGame(ctx context.Context, arg db.AddQualifyingStageGameParams) error {
+	if m.addQualifyingStageGameFunc != nil {
+		return m.addQualifyingStageGameFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) AddTeamMember(ctx context.Context, arg db.AddTeamMemberParams) (int64, error) {
+	if m.addTeamMemberFunc != nil {
+		return m.addTeamMemberFunc(ctx, arg)
+	}
+	return 1, nil
+}
+
+func (m *mockQuerier) RemoveAllTeamMembers(ctx context.Context, gameID int32) error {
+	if m.removeAllTeamMembersFunc != nil {
+		return m.removeAllTeamMembersFunc(ctx, gameID)
+	}
+	return nil
+}
+
+func (m *mockQuerier) RemoveAllTeams(ctx context.Context, gameID int32) error {
+	if m.removeAllTeamsFunc != nil {
+		return m.removeAllTeamsFunc(ctx, gameID)
+	}
+	return nil
+}
+
 // mockGameHub implements game.HubInterface for testing.
 type mockGameHub struct {
-	enqueueTestTasksFunc func(ctx context.Context, submissionID, gameID, userID int, language, code string) error
+	enqueueTestTasksFunc       func(ctx context.Context, submissionID, gameID, userID, problemID int, language, code string) error
+	enqueueValidationTasksFunc func(ctx context.Context, validationID int, problem db.Problem, solutions []db.ReferenceSolution, testcases []db.Testcase) error
 }
 
-func (m *mockGameHub) CalcCodeSize(_ string, _ string) int {
-	return 0
+func (m *mockGameHub) EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID, problemID int, language, code string) error {
+	if m.enqueueTestTasksFunc != nil {
+		return m.enqueueTestTasksFunc(ctx, submissionID, gameID, userID, problemID, language, code)
+	}
+	return nil
 }
 
-func (m *mockGameHub) EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID int, language, code string) error {
-	if m.enqueueTestTasksFunc != nil {
-		return m.enqueueTestTasksFunc(ctx, submissionID, gameID, userID, language, code)
+func (m *mockGameHub) EnqueueValidationTasks(ctx context.Context, validationID int, problem db.Problem, solutions []db.ReferenceSolution, testcases []db.Testcase) error {
+	if m.enqueueValidationTasksFunc != nil {
+		return m.enqueueValidationTasksFunc(ctx, validationID, problem, solutions, testcases)
 	}
 	return nil
 }
 
+func (m *mockGameHub) PublishEvent(_ game.Event) {}
+
+func (m *mockGameHub) SubscribeEvents(_ int) (<-chan game.Event, func()) {
+	return make(chan game.Event), func() {}
+}
+
+func (m *mockGameHub) RunCode(_ context.Context, _, _ int, _, _, _ string, _, _ int) (game.RunResult, error) {
+	return game.RunResult{}, nil
+}
+
 // mockTxManager implements db.TxManager for testing.
 // By default it passes the provided querier to the function.
 type mockTxManager struct {
@@ -411,6 +672,10 @@ func newTestHandler(q *mockQuerier) *Handler {
 	return &Handler{
 		gameSvc:       gameSvc,
 		tournamentSvc: tournamentSvc,
+		ratingSvc:     rating.NewService(q, txm),
+		qualifyingSvc: qualifying.NewService(q, txm),
+		exportSvc:     export.NewService(q),
+		problemPkgSvc: problempkg.NewService(q, txm),
 		q:             q,
 		conf:          &config.Config{BasePath: "/test/"},
 	}
@@ -423,6 +688,10 @@ func newTestHandlerWithHub(q *mockQuerier, hub *mockGameHub) *Handler {
 	return &Handler{
 		gameSvc:       gameSvc,
 		tournamentSvc: tournamentSvc,
+		ratingSvc:     rating.NewService(q, txm),
+		qualifyingSvc: qualifying.NewService(q, txm),
+		exportSvc:     export.NewService(q),
+		problemPkgSvc: problempkg.NewService(q, txm),
 		q:             q,
 		conf:          &config.Config{BasePath: "/test/"},
 	}
@@ -543,6 +812,28 @@ func TestAdminMiddleware_AdminUser(t *testing.T) {
 
 // --- Handler tests ---
 
+func TestGetDashboard_Workers(t *testing.T) {
+	workers, err := taskqueue.NewWorkerRegistry(map[string][]string{"swift": {"http://worker-swift:80"}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	h := newTestHandler(&mockQuerier{})
+	h.workers = workers
+
+	c, rec := newEchoContext(http.MethodGet, "/admin/dashboard", nil)
+	if err := h.getDashboard(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+	data := c.Echo().Renderer.(*mockRenderer).lastData.(echo.Map)
+	rows := data["Workers"].([]echo.Map)
+	if len(rows) != 1 || rows[0]["URL"] != "http://worker-swift:80" || rows[0]["Healthy"] != true {
+		t.Errorf("Workers = %+v", rows)
+	}
+}
+
 func TestGetUsers_Success(t *testing.T) {
 	q := &mockQuerier{
 		listUsersFunc: func(_ context.Context) ([]db.User, error) {
@@ -708,7 +999,7 @@ func TestGetGames_Success(t *testing.T) {
 	q := &mockQuerier{
 		listAllGamesFunc: func(_ context.Context) ([]db.Game, error) {
 			return []db.Game{
-				{GameID: 1, GameType: "golf", DisplayName: "Game 1", DurationSeconds: 300, ProblemID: 1},
+				{GameID: 1, GameType: "golf", DisplayName: "Game 1", DurationSeconds: 300},
 			}, nil
 		},
 	}
@@ -726,11 +1017,16 @@ func TestGetGames_Success(t *testing.T) {
 
 func TestPostGameNew_Success(t *testing.T) {
 	var createdParams db.CreateGameParams
+	var addedProblems []db.AddGameProblemParams
 	q := &mockQuerier{
 		createGameFunc: func(_ context.Context, arg db.CreateGameParams) (int32, error) {
 			createdParams = arg
 			return 1, nil
 		},
+		addGameProblemFunc: func(_ context.Context, arg db.AddGameProblemParams) error {
+			addedProblems = append(addedProblems, arg)
+			return nil
+		},
 	}
 	h := newTestHandler(q)
 
@@ -739,7 +1035,8 @@ func TestPostGameNew_Success(t *testing.T) {
 		"is_public":        {"on"},
 		"display_name":     {"Test Game"},
 		"duration_seconds": {"300"},
-		"problem_id":       {"1"},
+		"problem_ids":      {"3, 1"},
+		"unsolved_penalty": {"500"},
 	}
 	c, rec := newEchoContextWithForm("/admin/games/new", nil, form)
 
@@ -759,8 +1056,15 @@ func TestPostGameNew_Success(t *testing.T) {
 	if createdParams.DurationSeconds != 300 {
 		t.Errorf("DurationSeconds = %d, want 300", createdParams.DurationSeconds)
 	}
-	if createdParams.ProblemID != 1 {
-		t.Errorf("ProblemID = %d, want 1", createdParams.ProblemID)
+	if createdParams.UnsolvedPenalty != 500 {
+		t.Errorf("UnsolvedPenalty = %d, want 500", createdParams.UnsolvedPenalty)
+	}
+	want := []db.AddGameProblemParams{
+		{GameID: 1, ProblemID: 3, Position: 0},
+		{GameID: 1, ProblemID: 1, Position: 1},
+	}
+	if !slices.Equal(addedProblems, want) {
+		t.Errorf("added problems = %v, want %v", addedProblems, want)
 	}
 }
 
@@ -771,7 +1075,7 @@ func TestPostGameNew_InvalidDuration(t *testing.T) {
 		"game_type":        {"golf"},
 		"display_name":     {"Test Game"},
 		"duration_seconds": {"invalid"},
-		"problem_id":       {"1"},
+		"problem_ids":      {"1"},
 	}
 	c, _ := newEchoContextWithForm("/admin/games/new", nil, form)
 
@@ -788,27 +1092,31 @@ func TestPostGameNew_InvalidDuration(t *testing.T) {
 	}
 }
 
-func TestPostGameNew_InvalidProblemID(t *testing.T) {
-	h := newTestHandler(&mockQuerier{})
+func TestPostGameNew_InvalidProblemIDs(t *testing.T) {
+	for _, problemIDs := range []string{"invalid", "", "1,,2", "1,2,1"} {
+		t.Run(problemIDs, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{})
 
-	form := url.Values{
-		"game_type":        {"golf"},
-		"display_name":     {"Test Game"},
-		"duration_seconds": {"300"},
-		"problem_id":       {"invalid"},
-	}
-	c, _ := newEchoContextWithForm("/admin/games/new", nil, form)
+			form := url.Values{
+				"game_type":        {"golf"},
+				"display_name":     {"Test Game"},
+				"duration_seconds": {"300"},
+				"problem_ids":      {problemIDs},
+			}
+			c, _ := newEchoContextWithForm("/admin/games/new", nil, form)
 
-	err := h.postGameNew(c)
-	if err == nil {
-		t.Fatal("expected error for invalid problem_id")
-	}
-	httpErr, ok := err.(*echo.HTTPError)
-	if !ok {
-		t.Fatalf("expected echo.HTTPError, got %T", err)
-	}
-	if httpErr.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			err := h.postGameNew(c)
+			if err == nil {
+				t.Fatal("expected error for invalid problem_ids")
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
 	}
 }
 
@@ -863,6 +1171,252 @@ func TestPostProblemNew_Success(t *testing.T) {
 	if createdParams.Language != "php" {
 		t.Errorf("Language = %q, want %q", createdParams.Language, "php")
 	}
+	if createdParams.Scoring != scoring.Default {
+		t.Errorf("Scoring = %q, want %q", createdParams.Scoring, scoring.Default)
+	}
+}
+
+func TestPostProblemNew_InvalidScoring(t *testing.T) {
+	tests := []struct {
+		name     string
+		language string
+		scoring  string
+	}{
+		{name: "unknown strategy", language: "php", scoring: "lines"},
+		{name: "unsupported language", language: "swift", scoring: scoring.PHPTokens},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{
+				createProblemFunc: func(_ context.Context, _ db.CreateProblemParams) (int32, error) {
+					t.Fatal("CreateProblem should not be called")
+					return 0, nil
+				},
+			})
+
+			form := url.Values{
+				"title":       {"FizzBuzz"},
+				"description": {"Write FizzBuzz"},
+				"language":    {tt.language},
+				"sample_code": {""},
+				"scoring":     {tt.scoring},
+			}
+			c, _ := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+			err := h.postProblemNew(c)
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPostProblemNew_Checker(t *testing.T) {
+	var createdParams db.CreateProblemParams
+	h := newTestHandler(&mockQuerier{
+		createProblemFunc: func(_ context.Context, arg db.CreateProblemParams) (int32, error) {
+			createdParams = arg
+			return 1, nil
+		},
+	})
+
+	form := url.Values{
+		"title":           {"Circle"},
+		"description":     {"Print the area"},
+		"language":        {"php"},
+		"sample_code":     {""},
+		"checker":         {checker.Float},
+		"checker_epsilon": {"1e-6"},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+	if err := h.postProblemNew(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if createdParams.Checker != checker.Float {
+		t.Errorf("Checker = %q, want %q", createdParams.Checker, checker.Float)
+	}
+	if createdParams.CheckerEpsilon != 1e-6 {
+		t.Errorf("CheckerEpsilon = %v, want %v", createdParams.CheckerEpsilon, 1e-6)
+	}
+}
+
+func TestPostProblemNew_MemoryLimitAboveWorkers(t *testing.T) {
+	worker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"language":"php","max_memory_bytes":16777216}`))
+	}))
+	defer worker.Close()
+	workers, err := taskqueue.NewWorkerRegistry(map[string][]string{"php": {worker.URL}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	workers.CheckHealth(context.Background())
+
+	tests := []struct {
+		name           string
+		memoryLimitMiB string
+		wantCreated    bool
+	}{
+		{name: "within", memoryLimitMiB: "16", wantCreated: true},
+		{name: "above", memoryLimitMiB: "17", wantCreated: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			created := false
+			h := newTestHandler(&mockQuerier{
+				createProblemFunc: func(_ context.Context, _ db.CreateProblemParams) (int32, error) {
+					created = true
+					return 1, nil
+				},
+			})
+			h.workers = workers
+
+			form := url.Values{
+				"title":            {"FizzBuzz"},
+				"description":      {"Write FizzBuzz"},
+				"language":         {"php"},
+				"sample_code":      {""},
+				"memory_limit_mib": {tt.memoryLimitMiB},
+			}
+			c, _ := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+			err := h.postProblemNew(c)
+			if created != tt.wantCreated {
+				t.Errorf("created = %v, want %v", created, tt.wantCreated)
+			}
+			if tt.wantCreated {
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+				}
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok || httpErr.Code != http.StatusBadRequest {
+				t.Errorf("err = %v, want 400", err)
+			}
+		})
+	}
+}
+
+func TestPostProblemNew_Limits(t *testing.T) {
+	var createdParams db.CreateProblemParams
+	h := newTestHandler(&mockQuerier{
+		createProblemFunc: func(_ context.Context, arg db.CreateProblemParams) (int32, error) {
+			createdParams = arg
+			return 1, nil
+		},
+	})
+
+	form := url.Values{
+		"title":            {"FizzBuzz"},
+		"description":      {"Write FizzBuzz"},
+		"language":         {"php"},
+		"sample_code":      {""},
+		"time_limit_ms":    {"2000"},
+		"memory_limit_mib": {""},
+	}
+	c, _ := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+	if err := h.postProblemNew(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if createdParams.TimeLimitMs == nil || *createdParams.TimeLimitMs != 2000 {
+		t.Errorf("TimeLimitMs = %v, want 2000", createdParams.TimeLimitMs)
+	}
+	if createdParams.MemoryLimitMib != nil {
+		t.Errorf("MemoryLimitMib = %v, want nil", *createdParams.MemoryLimitMib)
+	}
+}
+
+func TestPostProblemNew_InvalidLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		form url.Values
+	}{
+		{name: "zero time limit", form: url.Values{"time_limit_ms": {"0"}}},
+		{name: "negative memory limit", form: url.Values{"memory_limit_mib": {"-1"}}},
+		{name: "non-numeric time limit", form: url.Values{"time_limit_ms": {"1s"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{
+				createProblemFunc: func(_ context.Context, _ db.CreateProblemParams) (int32, error) {
+					t.Fatal("CreateProblem should not be called")
+					return 0, nil
+				},
+			})
+
+			form := url.Values{
+				"title":       {"FizzBuzz"},
+				"description": {"Write FizzBuzz"},
+				"language":    {"php"},
+				"sample_code": {""},
+			}
+			for k, v := range tt.form {
+				form[k] = v
+			}
+			c, _ := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+			err := h.postProblemNew(c)
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestPostProblemNew_InvalidChecker(t *testing.T) {
+	tests := []struct {
+		name string
+		form url.Values
+	}{
+		{name: "unknown checker", form: url.Values{"checker": {"regex"}}},
+		{name: "negative epsilon", form: url.Values{"checker": {checker.Float}, "checker_epsilon": {"-1"}}},
+		{name: "non-numeric epsilon", form: url.Values{"checker": {checker.Float}, "checker_epsilon": {"small"}}},
+		{name: "special without code", form: url.Values{"checker": {checker.Special}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{
+				createProblemFunc: func(_ context.Context, _ db.CreateProblemParams) (int32, error) {
+					t.Fatal("CreateProblem should not be called")
+					return 0, nil
+				},
+			})
+
+			form := url.Values{
+				"title":       {"FizzBuzz"},
+				"description": {"Write FizzBuzz"},
+				"language":    {"php"},
+				"sample_code": {""},
+			}
+			for k, v := range tt.form {
+				form[k] = v
+			}
+			c, _ := newEchoContextWithForm("/admin/problems/new", nil, form)
+
+			err := h.postProblemNew(c)
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
+	}
 }
 
 func TestGetProblemEdit_Success(t *testing.T) {
@@ -903,6 +1457,13 @@ func TestGetProblemEdit_NotFound(t *testing.T) {
 func TestPostProblemEdit_Success(t *testing.T) {
 	var updatedParams db.UpdateProblemParams
 	q := &mockQuerier{
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php", Scoring: scoring.StrippedBytes}, nil
+		},
+		listSubmissionsByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Submission, error) {
+			t.Fatal("submissions should not be rescored")
+			return nil, nil
+		},
 		updateProblemFunc: func(_ context.Context, arg db.UpdateProblemParams) error {
 			updatedParams = arg
 			return nil
@@ -931,240 +1492,1116 @@ func TestPostProblemEdit_Success(t *testing.T) {
 	if updatedParams.Title != "Updated Title" {
 		t.Errorf("Title = %q, want %q", updatedParams.Title, "Updated Title")
 	}
+	if updatedParams.Scoring != scoring.StrippedBytes {
+		t.Errorf("Scoring = %q, want %q", updatedParams.Scoring, scoring.StrippedBytes)
+	}
 }
 
-func TestGetTestcases_Success(t *testing.T) {
+func TestPostProblemEdit_OtherLanguages(t *testing.T) {
+	var deleted bool
+	var created []db.CreateProblemLanguageParams
 	q := &mockQuerier{
 		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
-			return db.Problem{ProblemID: problemID, Title: "Test Problem"}, nil
+			return db.Problem{ProblemID: problemID, Language: "php", Scoring: scoring.StrippedBytes}, nil
 		},
-		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
-			return []db.Testcase{
-				{TestcaseID: 1, ProblemID: 1, Stdin: "in", Stdout: "out"},
-			}, nil
+		updateProblemFunc: func(_ context.Context, _ db.UpdateProblemParams) error {
+			return nil
 		},
-	}
-	h := newTestHandler(q)
-
-	c, rec := newEchoContext(http.MethodGet, "/admin/problems/1/testcases", map[string]string{"problemID": "1"})
-	err := h.getTestcases(c)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
-	if rec.Code != http.StatusOK {
-		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
-	}
-}
-
-func TestPostTestcaseNew_Success(t *testing.T) {
-	var createdParams db.CreateTestcaseParams
-	q := &mockQuerier{
-		createTestcaseFunc: func(_ context.Context, arg db.CreateTestcaseParams) (int32, error) {
-			createdParams = arg
-			return 1, nil
+		deleteProblemLanguagesFunc: func(_ context.Context, _ int32) error {
+			deleted = true
+			return nil
+		},
+		createProblemLanguageFunc: func(_ context.Context, arg db.CreateProblemLanguageParams) error {
+			created = append(created, arg)
+			return nil
 		},
 	}
 	h := newTestHandler(q)
 
 	form := url.Values{
-		"stdin":  {"hello"},
-		"stdout": {"world"},
+		"title":             {"Title"},
+		"description":       {"Desc"},
+		"language":          {"php"},
+		"sample_code":       {"<?php"},
+		"other_languages":   {"swift", "php"},
+		"sample_code_swift": {"print(1)"},
+		"sample_code_php":   {"ignored"},
 	}
-	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/new", map[string]string{"problemID": "1"}, form)
+	c, rec := newEchoContextWithForm("/admin/problems/1", map[string]string{"problemID": "1"}, form)
 
-	err := h.postTestcaseNew(c)
-	if err != nil {
+	if err := h.postProblemEdit(c); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if rec.Code != http.StatusSeeOther {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
 	}
-	if createdParams.ProblemID != 1 {
-		t.Errorf("ProblemID = %d, want 1", createdParams.ProblemID)
+	if !deleted {
+		t.Error("old languages should be deleted")
 	}
-	if createdParams.Stdin != "hello" {
-		t.Errorf("Stdin = %q, want %q", createdParams.Stdin, "hello")
+	want := []db.CreateProblemLanguageParams{{ProblemID: 1, Language: "swift", SampleCode: "print(1)"}}
+	if len(created) != len(want) || created[0] != want[0] {
+		t.Errorf("created = %+v, want %+v", created, want)
 	}
 }
 
-func TestPostTestcaseEdit_Success(t *testing.T) {
+func TestPostProblemEdit_OtherLanguageNotSupportedByScoring(t *testing.T) {
 	q := &mockQuerier{
-		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php", Scoring: scoring.PHPTokens}, nil
+		},
+		updateProblemFunc: func(_ context.Context, _ db.UpdateProblemParams) error {
+			t.Fatal("UpdateProblem should not be called")
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"title":           {"Title"},
+		"description":     {"Desc"},
+		"language":        {"php"},
+		"sample_code":     {""},
+		"other_languages": {"swift"},
+	}
+	c, _ := newEchoContextWithForm("/admin/problems/1", map[string]string{"problemID": "1"}, form)
+
+	err := h.postProblemEdit(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostProblemEdit_ScoringChangedRescores(t *testing.T) {
+	problem := db.Problem{ProblemID: 1, Language: "php", Scoring: scoring.StrippedBytes}
+	var updatedCodeSizes []db.UpdateSubmissionCodeSizeParams
+	var synced []db.SyncGameStateBestScoreSubmissionParams
+	q := &mockQuerier{
+		getProblemByIDFunc: func(_ context.Context, _ int32) (db.Problem, error) {
+			return problem, nil
+		},
+		updateProblemFunc: func(_ context.Context, arg db.UpdateProblemParams) error {
+			problem.Scoring = arg.Scoring
+			return nil
+		},
+		listSubmissionsByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Submission, error) {
+			return []db.Submission{
+				{SubmissionID: 10, GameID: 1, UserID: 1, ProblemID: 1, Code: "<?php echo 1;", CodeSize: 6},
+				{SubmissionID: 11, GameID: 1, UserID: 2, ProblemID: 1, Code: "<?php echo 1 + 2;", CodeSize: 8},
+			}, nil
+		},
+		updateSubmissionCodeSizeFunc: func(_ context.Context, arg db.UpdateSubmissionCodeSizeParams) error {
+			updatedCodeSizes = append(updatedCodeSizes, arg)
+			return nil
+		},
+		listGameStateIDsByProblemIDFunc: func(_ context.Context, _ int32) ([]db.ListGameStateIDsByProblemIDRow, error) {
+			return []db.ListGameStateIDsByProblemIDRow{{GameID: 1, TeamID: 1}, {GameID: 1, TeamID: 2}}, nil
+		},
+		syncGameStateBestScoreSubmissionFunc: func(_ context.Context, arg db.SyncGameStateBestScoreSubmissionParams) error {
+			synced = append(synced, arg)
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"title":       {"Title"},
+		"description": {"Desc"},
+		"language":    {"php"},
+		"sample_code": {""},
+		"scoring":     {scoring.PHPTokens},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/1", map[string]string{"problemID": "1"}, form)
+
+	err := h.postProblemEdit(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	want := []db.UpdateSubmissionCodeSizeParams{
+		{SubmissionID: 10, CodeSize: 3},
+		{SubmissionID: 11, CodeSize: 5},
+	}
+	if len(updatedCodeSizes) != len(want) {
+		t.Fatalf("updated %d submissions, want %d", len(updatedCodeSizes), len(want))
+	}
+	for i := range want {
+		if updatedCodeSizes[i] != want[i] {
+			t.Errorf("updatedCodeSizes[%d] = %+v, want %+v", i, updatedCodeSizes[i], want[i])
+		}
+	}
+	if len(synced) != 2 {
+		t.Errorf("synced %d game states, want 2", len(synced))
+	}
+}
+
+func TestGetTestcases_Success(t *testing.T) {
+	q := &mockQuerier{
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Title: "Test Problem"}, nil
+		},
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return []db.Testcase{
+				{TestcaseID: 1, ProblemID: 1, Stdin: "in", Stdout: "out"},
+			}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, rec := newEchoContext(http.MethodGet, "/admin/problems/1/testcases", map[string]string{"problemID": "1"})
+	err := h.getTestcases(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
+
+func TestPostTestcaseNew_Success(t *testing.T) {
+	var createdParams db.CreateTestcaseParams
+	q := &mockQuerier{
+		createTestcaseFunc: func(_ context.Context, arg db.CreateTestcaseParams) (int32, error) {
+			createdParams = arg
+			return 1, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"stdin":  {"hello"},
+		"stdout": {"world"},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/new", map[string]string{"problemID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postTestcaseNew(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if createdParams.ProblemID != 1 {
+		t.Errorf("ProblemID = %d, want 1", createdParams.ProblemID)
+	}
+	if createdParams.Stdin != "hello" {
+		t.Errorf("Stdin = %q, want %q", createdParams.Stdin, "hello")
+	}
+}
+
+func TestPostTestcaseNew_Limits(t *testing.T) {
+	var createdParams db.CreateTestcaseParams
+	q := &mockQuerier{
+		createTestcaseFunc: func(_ context.Context, arg db.CreateTestcaseParams) (int32, error) {
+			createdParams = arg
+			return 1, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"stdin":            {"hello"},
+		"stdout":           {"world"},
+		"memory_limit_mib": {"64"},
+	}
+	c, _ := newEchoContextWithForm("/admin/problems/1/testcases/new", map[string]string{"problemID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	if err := h.postTestcaseNew(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if createdParams.TimeLimitMs != nil {
+		t.Errorf("TimeLimitMs = %v, want nil", *createdParams.TimeLimitMs)
+	}
+	if createdParams.MemoryLimitMib == nil || *createdParams.MemoryLimitMib != 64 {
+		t.Errorf("MemoryLimitMib = %v, want 64", createdParams.MemoryLimitMib)
+	}
+}
+
+func TestPostTestcaseEdit_Success(t *testing.T) {
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
 			return db.Testcase{TestcaseID: testcaseID, ProblemID: 1}, nil
 		},
 	}
 	h := newTestHandler(q)
 
 	form := url.Values{
-		"stdin":  {"updated_in"},
-		"stdout": {"updated_out"},
+		"stdin":  {"updated_in"},
+		"stdout": {"updated_out"},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "1",
+	}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postTestcaseEdit(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+}
+
+func TestPostTestcaseEdit_Rejudge(t *testing.T) {
+	var revision db.CreateTestcaseRevisionParams
+	var rejudge db.CreateRejudgeParams
+	var enqueued []int
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+			return db.Testcase{TestcaseID: testcaseID, ProblemID: 1, Stdin: "in", Stdout: "out"}, nil
+		},
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php"}, nil
+		},
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return []db.Testcase{{TestcaseID: 1, ProblemID: 1}}, nil
+		},
+		createTestcaseRevisionFunc: func(_ context.Context, arg db.CreateTestcaseRevisionParams) error {
+			revision = arg
+			return nil
+		},
+		listStaleSubmissionsByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Submission, error) {
+			return []db.Submission{{SubmissionID: 3, GameID: 1, ProblemID: 1, Status: "success", IsPractice: true}}, nil
+		},
+		createRejudgeFunc: func(_ context.Context, arg db.CreateRejudgeParams) (int32, error) {
+			rejudge = arg
+			return 7, nil
+		},
+	}
+	hub := &mockGameHub{
+		enqueueTestTasksFunc: func(_ context.Context, submissionID, _, _, _ int, _, _ string) error {
+			enqueued = append(enqueued, submissionID)
+			return nil
+		},
+	}
+	h := newTestHandlerWithHub(q, hub)
+
+	form := url.Values{
+		"stdin":   {"in"},
+		"stdout":  {"fixed"},
+		"rejudge": {"on"},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "1",
+	}, form)
+	setUserInContext(c, &db.User{UserID: 2, IsAdmin: true})
+
+	if err := h.postTestcaseEdit(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !revision.AffectsResults || revision.Action != game.TestcaseUpdated || revision.UserID == nil || *revision.UserID != 2 {
+		t.Errorf("revision = %+v, want an update affecting results by user 2", revision)
+	}
+	if rejudge.ProblemID != 1 || rejudge.Reason != "testcase 1 updated" {
+		t.Errorf("rejudge = %+v", rejudge)
+	}
+	if !slices.Equal(enqueued, []int{3}) {
+		t.Errorf("enqueued = %v, want [3]", enqueued)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/test/admin/rejudges/7" {
+		t.Errorf("Location = %q, want %q", loc, "/test/admin/rejudges/7")
+	}
+}
+
+func TestPostTestcaseEdit_SampleOnly(t *testing.T) {
+	var revision db.CreateTestcaseRevisionParams
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+			return db.Testcase{TestcaseID: testcaseID, ProblemID: 1, Stdin: "in", Stdout: "out"}, nil
+		},
+		createTestcaseRevisionFunc: func(_ context.Context, arg db.CreateTestcaseRevisionParams) error {
+			revision = arg
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"stdin":     {"in"},
+		"stdout":    {"out"},
+		"is_sample": {"on"},
+	}
+	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "1",
+	}, form)
+	setUserInContext(c, &db.User{UserID: 2, IsAdmin: true})
+
+	if err := h.postTestcaseEdit(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if revision.AffectsResults || !revision.IsSample {
+		t.Errorf("revision = %+v, want a sample change not affecting results", revision)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/test/admin/problems/1/testcases" {
+		t.Errorf("Location = %q, want %q", loc, "/test/admin/problems/1/testcases")
+	}
+}
+
+func TestGetRejudge_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+
+	c, _ := newEchoContext(http.MethodGet, "/admin/rejudges/1", map[string]string{"rejudgeID": "1"})
+	err := h.getRejudge(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	}
+}
+
+func TestPostTestcaseEdit_WrongProblem(t *testing.T) {
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+			return db.Testcase{TestcaseID: testcaseID, ProblemID: 2}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"stdin":  {"in"},
+		"stdout": {"out"},
+	}
+	c, _ := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "1",
+	}, form)
+
+	err := h.postTestcaseEdit(c)
+	if err == nil {
+		t.Fatal("expected error when testcase belongs to different problem")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	}
+}
+
+func TestPostTestcaseDelete_Success(t *testing.T) {
+	deletedID := int32(0)
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+			return db.Testcase{TestcaseID: testcaseID, ProblemID: 1}, nil
+		},
+		deleteTestcaseFunc: func(_ context.Context, testcaseID int32) error {
+			deletedID = testcaseID
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/5/delete", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "5",
+	}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postTestcaseDelete(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if deletedID != 5 {
+		t.Errorf("deleted testcase ID = %d, want 5", deletedID)
+	}
+}
+
+func TestPostTestcaseDelete_WrongProblem(t *testing.T) {
+	q := &mockQuerier{
+		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
+			return db.Testcase{TestcaseID: testcaseID, ProblemID: 99}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, _ := newEchoContextWithForm("/admin/problems/1/testcases/5/delete", map[string]string{
+		"problemID":  "1",
+		"testcaseID": "5",
+	}, url.Values{})
+
+	err := h.postTestcaseDelete(c)
+	if err == nil {
+		t.Fatal("expected error when testcase belongs to different problem")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	}
+}
+
+func TestPostGameStart_Success(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return []db.Testcase{{TestcaseID: 1, ProblemID: 1}}, nil
+		},
+		getLatestProblemValidationFunc: func(_ context.Context, problemID int32) (db.ProblemValidation, error) {
+			return db.ProblemValidation{ProblemValidationID: 1, ProblemID: problemID, Status: "success"}, nil
+		},
+		listReferenceSolutionsByProblemIDFunc: func(_ context.Context, problemID int32) ([]db.ReferenceSolution, error) {
+			return []db.ReferenceSolution{{ReferenceSolutionID: 1, ProblemID: problemID}}, nil
+		},
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{DurationSeconds: 300}, nil
+		},
+		updateGameLifecycleFunc: func(_ context.Context, arg db.UpdateGameLifecycleParams) error {
+			if !arg.StartedAt.Valid {
+				t.Error("expected started_at to be set")
+			}
+			return nil
+		},
+		createGameLifecycleEventFunc: func(_ context.Context, arg db.CreateGameLifecycleEventParams) error {
+			if arg.Action != "start" || arg.FromState != "waiting" || arg.ToState != "scheduled" {
+				t.Errorf("unexpected event: %+v", arg)
+			}
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, rec := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameStart(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+}
+
+func TestPostGameStart_GameNotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+
+	c, _ := newEchoContextWithForm("/admin/games/999/start", map[string]string{"gameID": "999"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameStart(c)
+	if err == nil {
+		t.Fatal("expected error for non-existent game")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	}
+}
+
+func TestPostGameStart_NoTestcases(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return []db.Testcase{}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, _ := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameStart(c)
+	if err == nil {
+		t.Fatal("expected error when no testcases")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostGameStart_NotValidated(t *testing.T) {
+	tests := []struct {
+		name       string
+		validation db.ProblemValidation
+		form       url.Values
+		wantCode   int
+	}{
+		{name: "failed", validation: db.ProblemValidation{Status: "failure"}, wantCode: http.StatusBadRequest},
+		{name: "running", validation: db.ProblemValidation{Status: "running"}, wantCode: http.StatusBadRequest},
+		{name: "skipped", validation: db.ProblemValidation{Status: "failure"}, form: url.Values{"skip_validation": {"on"}}, wantCode: http.StatusSeeOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			started := false
+			q := &mockQuerier{
+				getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+					return db.Game{GameID: gameID}, nil
+				},
+				listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+					return []db.Testcase{{TestcaseID: 1, ProblemID: 1}}, nil
+				},
+				getLatestProblemValidationFunc: func(_ context.Context, _ int32) (db.ProblemValidation, error) {
+					return tt.validation, nil
+				},
+				listReferenceSolutionsByProblemIDFunc: func(_ context.Context, problemID int32) ([]db.ReferenceSolution, error) {
+					return []db.ReferenceSolution{{ReferenceSolutionID: 1, ProblemID: problemID}}, nil
+				},
+				getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+					return db.GetGameLifecycleForUpdateRow{DurationSeconds: 300}, nil
+				},
+				updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+					started = true
+					return nil
+				},
+			}
+			h := newTestHandler(q)
+
+			form := tt.form
+			if form == nil {
+				form = url.Values{}
+			}
+			c, rec := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, form)
+			setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+			err := h.postGameStart(c)
+			if tt.wantCode == http.StatusSeeOther {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if rec.Code != http.StatusSeeOther || !started {
+					t.Errorf("status = %d, started = %v, want the game started", rec.Code, started)
+				}
+				return
+			}
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != tt.wantCode {
+				t.Errorf("status = %d, want %d", httpErr.Code, tt.wantCode)
+			}
+			if started {
+				t.Error("expected the game not to start")
+			}
+		})
+	}
+}
+
+func TestPostGameStart_NoProblems(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		listGameProblemsFunc: func(_ context.Context, _ []int32) ([]db.ListGameProblemsRow, error) {
+			return nil, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, _ := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameStart(c)
+	if err == nil {
+		t.Fatal("expected error when no problems")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest || httpErr.Message != "No problems" {
+		t.Errorf("unexpected error: %v", httpErr)
+	}
+}
+
+func TestPostGamePause_Success(t *testing.T) {
+	startedAt := pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true}
+	var updated db.UpdateGameLifecycleParams
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{StartedAt: startedAt, DurationSeconds: 300}, nil
+		},
+		updateGameLifecycleFunc: func(_ context.Context, arg db.UpdateGameLifecycleParams) error {
+			updated = arg
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, rec := newEchoContextWithForm("/admin/games/1/pause", map[string]string{"gameID": "1"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGamePause(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if updated.OverrideState == nil || *updated.OverrideState != "paused" {
+		t.Errorf("override_state = %v, want paused", updated.OverrideState)
+	}
+	if !updated.PausedAt.Valid {
+		t.Error("expected paused_at to be set")
+	}
+}
+
+func TestPostGameSchedule_Past(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+			t.Error("game should not be updated")
+			return nil
+		},
+	})
+
+	form := url.Values{"start_at": {time.Now().Add(-time.Hour).In(jst).Format("2006-01-02T15:04")}}
+	c, _ := newEchoContextWithForm("/admin/games/1/schedule", map[string]string{"gameID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameSchedule(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostGameResume_NotPaused(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{DurationSeconds: 300}, nil
+		},
+		updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+			t.Error("game should not be updated")
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, _ := newEchoContextWithForm("/admin/games/1/resume", map[string]string{"gameID": "1"}, url.Values{})
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	err := h.postGameResume(c)
+	if err == nil {
+		t.Fatal("expected error for invalid transition")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusConflict)
+	}
+}
+
+func TestPostGameExtend_InvalidMinutes(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+
+	c, _ := newEchoContextWithForm("/admin/games/1/extend", map[string]string{"gameID": "1"}, url.Values{"extend_minutes": {"0"}})
+
+	err := h.postGameExtend(c)
+	if err == nil {
+		t.Fatal("expected error for invalid extend_minutes")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostGameEdit_InvalidTieBreak(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+
+	form := url.Values{
+		"game_type":        {"multiplayer"},
+		"display_name":     {"Test Game"},
+		"duration_seconds": {"300"},
+		"problem_ids":      {"1"},
+		"tie_break":        {"random"},
+	}
+	c, _ := newEchoContextWithForm("/admin/games/1", map[string]string{"gameID": "1"}, form)
+
+	err := h.postGameEdit(c)
+	if err == nil {
+		t.Fatal("expected error for invalid tie_break")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostGameEdit_AllowPractice(t *testing.T) {
+	var updated db.UpdateGameParams
+	h := newTestHandler(&mockQuerier{
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{DurationSeconds: 300}, nil
+		},
+		updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+			t.Error("the lifecycle should not be updated when the duration is unchanged")
+			return nil
+		},
+		updateGameFunc: func(_ context.Context, arg db.UpdateGameParams) error {
+			updated = arg
+			return nil
+		},
+	})
+
+	form := url.Values{
+		"game_type":        {"multiplayer"},
+		"display_name":     {"Test Game"},
+		"duration_seconds": {"300"},
+		"problem_ids":      {"1"},
+		"allow_practice":   {"on"},
+	}
+	c, rec := newEchoContextWithForm("/admin/games/1", map[string]string{"gameID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
+
+	if err := h.postGameEdit(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if !updated.AllowPractice {
+		t.Error("expected AllowPractice to be set")
+	}
+}
+
+func TestPostGameEdit_DurationRecorded(t *testing.T) {
+	var event db.CreateGameLifecycleEventParams
+	h := newTestHandler(&mockQuerier{
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{DurationSeconds: 300}, nil
+		},
+		updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+			return nil
+		},
+		createGameLifecycleEventFunc: func(_ context.Context, arg db.CreateGameLifecycleEventParams) error {
+			event = arg
+			return nil
+		},
+		updateGameFunc: func(_ context.Context, _ db.UpdateGameParams) error {
+			return nil
+		},
+	})
+
+	form := url.Values{
+		"game_type":        {"multiplayer"},
+		"display_name":     {"Test Game"},
+		"duration_seconds": {"600"},
+		"problem_ids":      {"1"},
 	}
-	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
-		"problemID":  "1",
-		"testcaseID": "1",
-	}, form)
+	c, rec := newEchoContextWithForm("/admin/games/1", map[string]string{"gameID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 7, IsAdmin: true})
 
-	err := h.postTestcaseEdit(c)
-	if err != nil {
+	if err := h.postGameEdit(c); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 	if rec.Code != http.StatusSeeOther {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
 	}
+	if event.Action != string(game.ActionSetDuration) || event.DurationSeconds != 600 {
+		t.Errorf("event = %+v, want set_duration to 600", event)
+	}
+	if event.UserID == nil || *event.UserID != 7 {
+		t.Errorf("event user = %v, want 7", event.UserID)
+	}
 }
 
-func TestPostTestcaseEdit_WrongProblem(t *testing.T) {
-	q := &mockQuerier{
-		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
-			return db.Testcase{TestcaseID: testcaseID, ProblemID: 2}, nil
+func TestPostGameEdit_DurationOfRunningGame(t *testing.T) {
+	startedAt := pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true}
+	h := newTestHandler(&mockQuerier{
+		getGameLifecycleForUpdateFunc: func(_ context.Context, _ int32) (db.GetGameLifecycleForUpdateRow, error) {
+			return db.GetGameLifecycleForUpdateRow{StartedAt: startedAt, DurationSeconds: 600}, nil
 		},
-	}
-	h := newTestHandler(q)
+		updateGameLifecycleFunc: func(_ context.Context, _ db.UpdateGameLifecycleParams) error {
+			t.Error("the lifecycle should not be updated")
+			return nil
+		},
+		updateGameFunc: func(_ context.Context, _ db.UpdateGameParams) error {
+			t.Error("the game should not be updated")
+			return nil
+		},
+	})
 
 	form := url.Values{
-		"stdin":  {"in"},
-		"stdout": {"out"},
+		"game_type":        {"multiplayer"},
+		"display_name":     {"Test Game"},
+		"duration_seconds": {"300"},
+		"problem_ids":      {"1"},
 	}
-	c, _ := newEchoContextWithForm("/admin/problems/1/testcases/1", map[string]string{
-		"problemID":  "1",
-		"testcaseID": "1",
-	}, form)
+	c, _ := newEchoContextWithForm("/admin/games/1", map[string]string{"gameID": "1"}, form)
+	setUserInContext(c, &db.User{UserID: 1, IsAdmin: true})
 
-	err := h.postTestcaseEdit(c)
+	err := h.postGameEdit(c)
 	if err == nil {
-		t.Fatal("expected error when testcase belongs to different problem")
+		t.Fatal("expected error for changing the duration of a running game")
 	}
 	httpErr, ok := err.(*echo.HTTPError)
 	if !ok {
 		t.Fatalf("expected echo.HTTPError, got %T", err)
 	}
-	if httpErr.Code != http.StatusNotFound {
-		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	if httpErr.Code != http.StatusConflict {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusConflict)
 	}
 }
 
-func TestPostTestcaseDelete_Success(t *testing.T) {
-	deletedID := int32(0)
+func TestGetGameRanking_Practice(t *testing.T) {
 	q := &mockQuerier{
-		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
-			return db.Testcase{TestcaseID: testcaseID, ProblemID: 1}, nil
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, TieBreak: ranking.EarliestSubmission}, nil
 		},
-		deleteTestcaseFunc: func(_ context.Context, testcaseID int32) error {
-			deletedID = testcaseID
-			return nil
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			t.Error("expected the official ranking not to be queried")
+			return nil, nil
+		},
+		listBestPracticeSubmissionsFunc: func(_ context.Context, _ db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error) {
+			return []db.ListBestPracticeSubmissionsRow{
+				{Submission: db.Submission{ProblemID: 1, CodeSize: 10, IsPractice: true}, GameTeam: db.GameTeam{TeamID: 1, DisplayName: "Team A"}, SubmissionCount: 1},
+			}, nil
 		},
 	}
 	h := newTestHandler(q)
 
-	c, rec := newEchoContextWithForm("/admin/problems/1/testcases/5/delete", map[string]string{
-		"problemID":  "1",
-		"testcaseID": "5",
-	}, url.Values{})
+	c, rec := newEchoContext(http.MethodGet, "/admin/games/1/ranking?practice=1", map[string]string{"gameID": "1"})
+	if err := h.getGameRanking(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
 
-	err := h.postTestcaseDelete(c)
+func TestGetGameRanking_Success(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, TieBreak: ranking.FewestSubmissions}, nil
+		},
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			return []db.GetRankingRow{
+				{Submission: db.Submission{ProblemID: 1, CodeSize: 10}, GameTeam: db.GameTeam{TeamID: 1, DisplayName: "Team A"}, SubmissionCount: 3},
+				{Submission: db.Submission{ProblemID: 1, CodeSize: 10}, GameTeam: db.GameTeam{TeamID: 2, DisplayName: "Team B"}, SubmissionCount: 1},
+			}, nil
+		},
+		listTeamMembersFunc: func(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+			return []db.ListTeamMembersRow{
+				{TeamID: 1, User: db.User{UserID: 1, Username: "alice"}},
+				{TeamID: 2, User: db.User{UserID: 2, Username: "bob"}},
+			}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, rec := newEchoContext(http.MethodGet, "/admin/games/1/ranking", map[string]string{"gameID": "1"})
+	err := h.getGameRanking(c)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if rec.Code != http.StatusSeeOther {
-		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
-	}
-	if deletedID != 5 {
-		t.Errorf("deleted testcase ID = %d, want 5", deletedID)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
 }
 
-func TestPostTestcaseDelete_WrongProblem(t *testing.T) {
+func TestGetGameRanking_InvalidCursor(t *testing.T) {
 	q := &mockQuerier{
-		getTestcaseByIDFunc: func(_ context.Context, testcaseID int32) (db.Testcase, error) {
-			return db.Testcase{TestcaseID: testcaseID, ProblemID: 99}, nil
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, TieBreak: ranking.EarliestSubmission}, nil
 		},
 	}
 	h := newTestHandler(q)
 
-	c, _ := newEchoContextWithForm("/admin/problems/1/testcases/5/delete", map[string]string{
-		"problemID":  "1",
-		"testcaseID": "5",
-	}, url.Values{})
+	c, _ := newEchoContext(http.MethodGet, "/admin/games/1/ranking?cursor=!!!", map[string]string{"gameID": "1"})
 
-	err := h.postTestcaseDelete(c)
+	err := h.getGameRanking(c)
 	if err == nil {
-		t.Fatal("expected error when testcase belongs to different problem")
+		t.Fatal("expected error for invalid cursor")
 	}
 	httpErr, ok := err.(*echo.HTTPError)
 	if !ok {
 		t.Fatalf("expected echo.HTTPError, got %T", err)
 	}
-	if httpErr.Code != http.StatusNotFound {
-		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
 	}
 }
 
-func TestPostGameStart_Success(t *testing.T) {
+func TestGetGameTeams_Success(t *testing.T) {
 	q := &mockQuerier{
-		getGameByIDFunc: func(_ context.Context, gameID int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{GameID: gameID, ProblemID: 1}, nil
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, GameType: "multiplayer"}, nil
 		},
-		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
-			return []db.Testcase{{TestcaseID: 1, ProblemID: 1}}, nil
+		listTeamsFunc: func(_ context.Context, _ int32) ([]db.GameTeam, error) {
+			return []db.GameTeam{{TeamID: 1, GameID: 1, DisplayName: "Team A"}}, nil
 		},
-		updateGameStartedAtFunc: func(_ context.Context, _ db.UpdateGameStartedAtParams) error {
-			return nil
+		listTeamMembersFunc: func(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+			return []db.ListTeamMembersRow{
+				{TeamID: 1, User: db.User{UserID: 1, Username: "alice"}},
+				{TeamID: 1, User: db.User{UserID: 2, Username: "bob"}},
+			}, nil
 		},
 	}
 	h := newTestHandler(q)
 
-	c, rec := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, url.Values{})
-
-	err := h.postGameStart(c)
+	c, rec := newEchoContext(http.MethodGet, "/admin/games/1/teams", map[string]string{"gameID": "1"})
+	err := h.getGameTeams(c)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	if rec.Code != http.StatusSeeOther {
-		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
 }
 
-func TestPostGameStart_GameNotFound(t *testing.T) {
-	h := newTestHandler(&mockQuerier{})
+func TestPostGameTeams_Success(t *testing.T) {
+	var createdTeams []db.CreateTeamParams
+	var addedMembers []db.AddTeamMemberParams
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, GameType: "multiplayer"}, nil
+		},
+		createTeamFunc: func(_ context.Context, arg db.CreateTeamParams) (int32, error) {
+			createdTeams = append(createdTeams, arg)
+			return int32(len(createdTeams)), nil
+		},
+		addTeamMemberFunc: func(_ context.Context, arg db.AddTeamMemberParams) (int64, error) {
+			addedMembers = append(addedMembers, arg)
+			return 1, nil
+		},
+	}
+	h := newTestHandler(q)
 
-	c, _ := newEchoContextWithForm("/admin/games/999/start", map[string]string{"gameID": "999"}, url.Values{})
+	form := url.Values{"teams": {"Team A: 1, 2\n\nTeam: B: 3\n"}}
+	c, rec := newEchoContextWithForm("/admin/games/1/teams", map[string]string{"gameID": "1"}, form)
 
-	err := h.postGameStart(c)
-	if err == nil {
-		t.Fatal("expected error for non-existent game")
+	err := h.postGameTeams(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	httpErr, ok := err.(*echo.HTTPError)
-	if !ok {
-		t.Fatalf("expected echo.HTTPError, got %T", err)
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
 	}
-	if httpErr.Code != http.StatusNotFound {
-		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	if len(createdTeams) != 2 || createdTeams[0].DisplayName != "Team A" || createdTeams[1].DisplayName != "Team: B" {
+		t.Errorf("unexpected teams: %+v", createdTeams)
+	}
+	want := []db.AddTeamMemberParams{
+		{TeamID: 1, GameID: 1, UserID: 1},
+		{TeamID: 1, GameID: 1, UserID: 2},
+		{TeamID: 2, GameID: 1, UserID: 3},
+	}
+	if !slices.Equal(addedMembers, want) {
+		t.Errorf("members = %+v, want %+v", addedMembers, want)
 	}
 }
 
-func TestPostGameStart_NoTestcases(t *testing.T) {
-	q := &mockQuerier{
-		getGameByIDFunc: func(_ context.Context, gameID int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{GameID: gameID, ProblemID: 1}, nil
+func TestPostGameTeams_BadRequest(t *testing.T) {
+	multiplayer := func(_ context.Context, _ int32) (db.Game, error) {
+		return db.Game{GameID: 1, GameType: "multiplayer"}, nil
+	}
+	tests := []struct {
+		name  string
+		q     *mockQuerier
+		teams string
+	}{
+		{
+			name:  "missing separator",
+			q:     &mockQuerier{getGameByIDFunc: multiplayer},
+			teams: "Team A 1, 2",
 		},
-		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
-			return []db.Testcase{}, nil
+		{
+			name:  "invalid user id",
+			q:     &mockQuerier{getGameByIDFunc: multiplayer},
+			teams: "Team A: 1, x",
+		},
+		{
+			name: "1v1 game",
+			q: &mockQuerier{getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+				return db.Game{GameID: 1, GameType: "1v1"}, nil
+			}},
+			teams: "Team A: 1",
+		},
+		{
+			name: "started game",
+			q: &mockQuerier{getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+				return db.Game{
+					GameID:          1,
+					GameType:        "multiplayer",
+					StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true},
+					DurationSeconds: 300,
+				}, nil
+			}},
+			teams: "Team A: 1",
+		},
+		{
+			name: "user in two teams",
+			q: &mockQuerier{
+				getGameByIDFunc: multiplayer,
+				addTeamMemberFunc: func(_ context.Context, arg db.AddTeamMemberParams) (int64, error) {
+					if arg.TeamID == 2 {
+						return 0, nil
+					}
+					return 1, nil
+				},
+				createTeamFunc: func() func(context.Context, db.CreateTeamParams) (int32, error) {
+					var n int32
+					return func(_ context.Context, _ db.CreateTeamParams) (int32, error) {
+						n++
+						return n, nil
+					}
+				}(),
+			},
+			teams: "Team A: 1\nTeam B: 1",
 		},
 	}
-	h := newTestHandler(q)
-
-	c, _ := newEchoContextWithForm("/admin/games/1/start", map[string]string{"gameID": "1"}, url.Values{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(tt.q)
+			form := url.Values{"teams": {tt.teams}}
+			c, _ := newEchoContextWithForm("/admin/games/1/teams", map[string]string{"gameID": "1"}, form)
 
-	err := h.postGameStart(c)
-	if err == nil {
-		t.Fatal("expected error when no testcases")
-	}
-	httpErr, ok := err.(*echo.HTTPError)
-	if !ok {
-		t.Fatalf("expected echo.HTTPError, got %T", err)
-	}
-	if httpErr.Code != http.StatusBadRequest {
-		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			err := h.postGameTeams(c)
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
 	}
 }
 
@@ -1196,6 +2633,7 @@ func TestGetSubmissions_Success(t *testing.T) {
 }
 
 func TestGetSubmissionDetail_Success(t *testing.T) {
+	timeMs, memoryBytes := int32(12), int64(4<<20)
 	q := &mockQuerier{
 		getSubmissionByIDFunc: func(_ context.Context, submissionID int32) (db.Submission, error) {
 			return db.Submission{
@@ -1216,6 +2654,8 @@ func TestGetSubmissionDetail_Success(t *testing.T) {
 					TestcaseID:       1,
 					Status:           "pass",
 					CreatedAt:        pgtype.Timestamp{Valid: true},
+					TimeMs:           &timeMs,
+					MemoryBytes:      &memoryBytes,
 				},
 			}, nil
 		},
@@ -1233,6 +2673,11 @@ func TestGetSubmissionDetail_Success(t *testing.T) {
 	if rec.Code != http.StatusOK {
 		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
 	}
+	data := c.Echo().Renderer.(*mockRenderer).lastData.(echo.Map)
+	results := data["TestcaseResults"].([]echo.Map)
+	if len(results) != 1 || results[0]["TimeMs"] != &timeMs || results[0]["MemoryBytes"] != &memoryBytes {
+		t.Errorf("TestcaseResults = %+v", results)
+	}
 }
 
 func TestGetSubmissionDetail_NotFound(t *testing.T) {
@@ -1429,7 +2874,7 @@ func TestPostTournamentEdit_NotFound(t *testing.T) {
 // --- Rejudge tests ---
 
 func TestPostSubmissionRejudge_Success(t *testing.T) {
-	var enqueuedSubmissionID, enqueuedGameID, enqueuedUserID int
+	var enqueuedSubmissionID, enqueuedGameID, enqueuedUserID, enqueuedProblemID int
 	var enqueuedLanguage, enqueuedCode string
 
 	q := &mockQuerier{
@@ -1438,22 +2883,25 @@ func TestPostSubmissionRejudge_Success(t *testing.T) {
 				SubmissionID: submissionID,
 				GameID:       1,
 				UserID:       10,
-				Code:         "<?php echo 1;",
-				CodeSize:     14,
+				ProblemID:    1,
+				Language:     "swift",
+				Code:         "print(1)",
+				CodeSize:     8,
 				Status:       "wrong_answer",
 				CreatedAt:    pgtype.Timestamp{Valid: true},
 			}, nil
 		},
-		getGameByIDFunc: func(_ context.Context, gameID int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{GameID: gameID, ProblemID: 1, Language: "php"}, nil
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php"}, nil
 		},
 	}
 
 	hub := &mockGameHub{
-		enqueueTestTasksFunc: func(_ context.Context, submissionID, gameID, userID int, language, code string) error {
+		enqueueTestTasksFunc: func(_ context.Context, submissionID, gameID, userID, problemID int, language, code string) error {
 			enqueuedSubmissionID = submissionID
 			enqueuedGameID = gameID
 			enqueuedUserID = userID
+			enqueuedProblemID = problemID
 			enqueuedLanguage = language
 			enqueuedCode = code
 			return nil
@@ -1483,11 +2931,15 @@ func TestPostSubmissionRejudge_Success(t *testing.T) {
 	if enqueuedUserID != 10 {
 		t.Errorf("enqueued user ID = %d, want 10", enqueuedUserID)
 	}
-	if enqueuedLanguage != "php" {
-		t.Errorf("enqueued language = %q, want %q", enqueuedLanguage, "php")
+	if enqueuedProblemID != 1 {
+		t.Errorf("enqueued problem ID = %d, want 1", enqueuedProblemID)
+	}
+	// The submission is judged in its own language, not the problem's.
+	if enqueuedLanguage != "swift" {
+		t.Errorf("enqueued language = %q, want %q", enqueuedLanguage, "swift")
 	}
-	if enqueuedCode != "<?php echo 1;" {
-		t.Errorf("enqueued code = %q, want %q", enqueuedCode, "<?php echo 1;")
+	if enqueuedCode != "print(1)" {
+		t.Errorf("enqueued code = %q, want %q", enqueuedCode, "print(1)")
 	}
 }
 
@@ -1516,19 +2968,22 @@ func TestPostSubmissionsRejudgeLatest_Success(t *testing.T) {
 	var enqueuedIDs []int
 
 	q := &mockQuerier{
-		getGameByIDFunc: func(_ context.Context, gameID int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{GameID: gameID, ProblemID: 1, Language: "php"}, nil
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php"}, nil
 		},
 		getLatestSubmissionsByGameIDFunc: func(_ context.Context, _ int32) ([]db.Submission, error) {
 			return []db.Submission{
-				{SubmissionID: 10, GameID: 1, UserID: 1, Code: "<?php echo 1;", CreatedAt: pgtype.Timestamp{Valid: true}},
-				{SubmissionID: 20, GameID: 1, UserID: 2, Code: "<?php echo 2;", CreatedAt: pgtype.Timestamp{Valid: true}},
+				{SubmissionID: 10, GameID: 1, UserID: 1, ProblemID: 1, Code: "<?php echo 1;", CreatedAt: pgtype.Timestamp{Valid: true}},
+				{SubmissionID: 20, GameID: 1, UserID: 2, ProblemID: 1, Code: "<?php echo 2;", CreatedAt: pgtype.Timestamp{Valid: true}},
 			}, nil
 		},
 	}
 
 	hub := &mockGameHub{
-		enqueueTestTasksFunc: func(_ context.Context, submissionID, _, _ int, _, _ string) error {
+		enqueueTestTasksFunc: func(_ context.Context, submissionID, _, _, _ int, _, _ string) error {
 			enqueuedIDs = append(enqueuedIDs, submissionID)
 			return nil
 		},
@@ -1559,20 +3014,23 @@ func TestPostSubmissionsRejudgeAll_Success(t *testing.T) {
 	var enqueuedIDs []int
 
 	q := &mockQuerier{
-		getGameByIDFunc: func(_ context.Context, gameID int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{GameID: gameID, ProblemID: 1, Language: "php"}, nil
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID}, nil
+		},
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, Language: "php"}, nil
 		},
 		getSubmissionsByGameIDFunc: func(_ context.Context, _ int32) ([]db.Submission, error) {
 			return []db.Submission{
-				{SubmissionID: 10, GameID: 1, UserID: 1, Code: "<?php echo 1;", CreatedAt: pgtype.Timestamp{Valid: true}},
-				{SubmissionID: 11, GameID: 1, UserID: 1, Code: "<?php echo 11;", CreatedAt: pgtype.Timestamp{Valid: true}},
-				{SubmissionID: 20, GameID: 1, UserID: 2, Code: "<?php echo 2;", CreatedAt: pgtype.Timestamp{Valid: true}},
+				{SubmissionID: 10, GameID: 1, UserID: 1, ProblemID: 1, Code: "<?php echo 1;", CreatedAt: pgtype.Timestamp{Valid: true}},
+				{SubmissionID: 11, GameID: 1, UserID: 1, ProblemID: 1, Code: "<?php echo 11;", CreatedAt: pgtype.Timestamp{Valid: true}},
+				{SubmissionID: 20, GameID: 1, UserID: 2, ProblemID: 1, Code: "<?php echo 2;", CreatedAt: pgtype.Timestamp{Valid: true}},
 			}, nil
 		},
 	}
 
 	hub := &mockGameHub{
-		enqueueTestTasksFunc: func(_ context.Context, submissionID, _, _ int, _, _ string) error {
+		enqueueTestTasksFunc: func(_ context.Context, submissionID, _, _, _ int, _, _ string) error {
 			enqueuedIDs = append(enqueuedIDs, submissionID)
 			return nil
 		},
@@ -1618,3 +3076,220 @@ func TestPostSubmissionsRejudgeAll_GameNotFound(t *testing.T) {
 		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
 	}
 }
+
+func TestPostQualifyingStageNew_Success(t *testing.T) {
+	var created db.CreateQualifyingStageParams
+	var games []db.AddQualifyingStageGameParams
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID, IsPublic: true}, nil
+		},
+		createQualifyingStageFunc: func(_ context.Context, arg db.CreateQualifyingStageParams) (int32, error) {
+			created = arg
+			return 5, nil
+		},
+		addQualifyingStageGameFunc: func(_ context.Context, arg db.AddQualifyingStageGameParams) error {
+			games = append(games, arg)
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"display_name":    {"Online Qualifying"},
+		"is_public":       {"on"},
+		"missing_policy":  {"penalty"},
+		"missing_penalty": {"500"},
+		"games":           {"3: 1\n\n7: 0.5\n9\n"},
+	}
+	c, rec := newEchoContextWithForm("/admin/qualifying/new", nil, form)
+
+	err := h.postQualifyingStageNew(c)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/test/admin/qualifying/5" {
+		t.Errorf("Location = %q, want %q", loc, "/test/admin/qualifying/5")
+	}
+	if created.DisplayName != "Online Qualifying" || !created.IsPublic || created.MissingPolicy != "penalty" || created.MissingPenalty != 500 {
+		t.Errorf("unexpected stage: %+v", created)
+	}
+	want := []db.AddQualifyingStageGameParams{
+		{QualifyingStageID: 5, GameID: 3, Position: 0, Weight: 1},
+		{QualifyingStageID: 5, GameID: 7, Position: 1, Weight: 0.5},
+		{QualifyingStageID: 5, GameID: 9, Position: 2, Weight: 1},
+	}
+	if !slices.Equal(games, want) {
+		t.Errorf("games = %+v, want %+v", games, want)
+	}
+}
+
+func TestPostQualifyingStageNew_PrivateGameInPublicStage(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID, IsPublic: gameID != 7}, nil
+		},
+		createQualifyingStageFunc: func(_ context.Context, _ db.CreateQualifyingStageParams) (int32, error) {
+			return 5, nil
+		},
+		addQualifyingStageGameFunc: func(_ context.Context, _ db.AddQualifyingStageGameParams) error {
+			return nil
+		},
+	}
+	h := newTestHandler(q)
+
+	form := url.Values{
+		"display_name":   {"Online Qualifying"},
+		"is_public":      {"on"},
+		"missing_policy": {"penalty"},
+		"games":          {"3: 1\n7: 1"},
+	}
+	c, _ := newEchoContextWithForm("/admin/qualifying/new", nil, form)
+
+	err := h.postQualifyingStageNew(c)
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostQualifyingStageNew_BadRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		policy string
+		games  string
+	}{
+		{name: "unknown policy", policy: "ignore", games: "1: 1"},
+		{name: "invalid weight", policy: "exclude", games: "1: x"},
+		{name: "negative weight", policy: "exclude", games: "1: -1"},
+		{name: "duplicate game", policy: "exclude", games: "1: 1\n1: 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{})
+			form := url.Values{
+				"display_name":   {"Stage"},
+				"missing_policy": {tt.policy},
+				"games":          {tt.games},
+			}
+			c, _ := newEchoContextWithForm("/admin/qualifying/new", nil, form)
+
+			err := h.postQualifyingStageNew(c)
+			httpErr, ok := err.(*echo.HTTPError)
+			if !ok {
+				t.Fatalf("expected echo.HTTPError, got %T", err)
+			}
+			if httpErr.Code != http.StatusBadRequest {
+				t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+			}
+		})
+	}
+}
+
+func TestGetGameExport_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+
+	c, _ := newEchoContext(http.MethodGet, "/admin/games/999/export", map[string]string{"gameID": "999"})
+	err := h.getGameExport(c)
+	if err == nil {
+		t.Fatal("expected error for non-existent game")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusNotFound)
+	}
+}
+
+func TestGetGameExport_InvalidFormat(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, gameID int32) (db.Game, error) {
+			return db.Game{GameID: gameID, TieBreak: "earliest_submission"}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	c, _ := newEchoContext(http.MethodGet, "/admin/games/1/export?format=xml", map[string]string{"gameID": "1"})
+	err := h.getGameExport(c)
+	if err == nil {
+		t.Fatal("expected error for unknown format")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestPostProblemImport_Success(t *testing.T) {
+	var created db.CreateProblemParams
+	var testcases []db.CreateTestcaseParams
+	q := &mockQuerier{
+		createProblemFunc: func(_ context.Context, arg db.CreateProblemParams) (int32, error) {
+			created = arg
+			return 12, nil
+		},
+		createTestcaseFunc: func(_ context.Context, arg db.CreateTestcaseParams) (int32, error) {
+			testcases = append(testcases, arg)
+			return int32(len(testcases)), nil
+		},
+	}
+	h := newTestHandler(q)
+
+	pkg := problempkg.Package{
+		Title:       "Hello",
+		Description: "Print Hello.",
+		Language:    "php",
+		SampleCode:  "<?php",
+		Scoring:     scoring.Default,
+		Checker:     checker.Default,
+		Testcases: []problempkg.Testcase{
+			{Stdin: "", Stdout: "Hello", IsSample: true},
+			{Stdin: "1", Stdout: "Hello"},
+		},
+	}
+	var body bytes.Buffer
+	mw := multipart.NewWriter(&body)
+	fw, err := mw.CreateFormFile("package", "hello.zip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := pkg.WriteZip(fw); err != nil {
+		t.Fatal(err)
+	}
+	if err := mw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/admin/problems/import", &body)
+	req.Header.Set(echo.HeaderContentType, mw.FormDataContentType())
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := h.postProblemImport(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusSeeOther {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusSeeOther)
+	}
+	if loc := rec.Header().Get("Location"); loc != "/test/admin/problems/12" {
+		t.Errorf("Location = %q, want %q", loc, "/test/admin/problems/12")
+	}
+	if created.Title != "Hello" || created.Language != "php" {
+		t.Errorf("created = %+v", created)
+	}
+	if len(testcases) != 2 || testcases[0].ProblemID != 12 || !testcases[0].IsSample || testcases[1].IsSample {
+		t.Errorf("testcases = %+v", testcases)
+	}
+}
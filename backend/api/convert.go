@@ -3,7 +3,10 @@ package api
 import (
 	"github.com/oapi-codegen/nullable"
 
+	"albatross-2026-backend/codediff"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/tournament"
 )
 
@@ -15,6 +18,20 @@ func toAPIUser(p game.Player) User {
 		IconPath:    p.IconPath,
 		IsAdmin:     p.IsAdmin,
 		Label:       toNullable(p.Label),
+		Rating:      p.Rating,
+		RatingRank:  toNullable(p.RatingRank),
+	}
+}
+
+func toAPITeam(t game.Team) Team {
+	members := make([]User, len(t.Members))
+	for i, m := range t.Members {
+		members[i] = toAPIUser(m)
+	}
+	return Team{
+		TeamID:      t.TeamID,
+		DisplayName: t.DisplayName,
+		Members:     members,
 	}
 }
 
@@ -24,6 +41,30 @@ func toAPIGame(g game.Detail) Game {
 		ts := g.StartedAt.Unix()
 		startedAt = &ts
 	}
+	var pausedAt *int64
+	if g.PausedAt != nil {
+		ts := g.PausedAt.Unix()
+		pausedAt = &ts
+	}
+	problems := make([]Problem, len(g.Problems))
+	for i, p := range g.Problems {
+		languages := make([]ProblemLanguageOption, len(p.Languages))
+		for j, l := range p.Languages {
+			languages[j] = ProblemLanguageOption{
+				Language:   ProblemLanguage(l.Language),
+				SampleCode: l.SampleCode,
+			}
+		}
+		problems[i] = Problem{
+			ProblemID:   p.ProblemID,
+			Title:       p.Title,
+			Description: p.Description,
+			Language:    ProblemLanguage(p.Language),
+			SampleCode:  p.SampleCode,
+			Scoring:     ScoringStrategy(p.Scoring),
+			Languages:   languages,
+		}
+	}
 	mainPlayers := make([]User, len(g.MainPlayers))
 	for i, p := range g.MainPlayers {
 		mainPlayers[i] = toAPIUser(p)
@@ -35,14 +76,20 @@ func toAPIGame(g game.Detail) Game {
 		DisplayName:     g.DisplayName,
 		DurationSeconds: g.DurationSeconds,
 		StartedAt:       startedAt,
-		Problem: Problem{
-			ProblemID:   g.Problem.ProblemID,
-			Title:       g.Problem.Title,
-			Description: g.Problem.Description,
-			Language:    ProblemLanguage(g.Problem.Language),
-			SampleCode:  g.Problem.SampleCode,
-		},
-		MainPlayers: mainPlayers,
+		State:           GameState(g.State),
+		PausedAt:        pausedAt,
+		AllowPractice:   g.AllowPractice,
+		Problems:        problems,
+		MainPlayers:     mainPlayers,
+	}
+}
+
+func toAPICodeSnapshot(s game.CodeSnapshot) CodeSnapshot {
+	return CodeSnapshot{
+		UserID:       s.UserID,
+		Code:         s.Code,
+		IsSubmission: s.IsSubmission,
+		CreatedAt:    s.CreatedAt.Unix(),
 	}
 }
 
@@ -60,6 +107,7 @@ func toAPILatestState(s game.LatestState) LatestGameState {
 		submittedAt = nullable.NewNullNullable[int64]()
 	}
 	return LatestGameState{
+		Language:             toAPILanguage(s.Language),
 		Code:                 s.Code,
 		Score:                score,
 		BestScoreSubmittedAt: submittedAt,
@@ -67,6 +115,26 @@ func toAPILatestState(s game.LatestState) LatestGameState {
 	}
 }
 
+func toAPIGameEvent(e game.Event) GameEvent {
+	event := GameEvent{
+		Type:                 GameEventType(e.Type),
+		UserID:               e.UserID,
+		TeamID:               e.TeamID,
+		ProblemID:            e.ProblemID,
+		Score:                e.Score,
+		BestScoreSubmittedAt: e.BestScoreSubmittedAt,
+	}
+	switch e.Type {
+	case game.EventTypeCode:
+		event.Language = toAPILanguage(e.Language)
+		event.Code = &e.Code
+	case game.EventTypeStatus:
+		status := ExecutionStatus(e.Status)
+		event.Status = &status
+	}
+	return event
+}
+
 func toAPIRankingEntry(r game.RankingEntry) RankingEntry {
 	var code nullable.Nullable[string]
 	if r.Code != nil {
@@ -74,11 +142,21 @@ func toAPIRankingEntry(r game.RankingEntry) RankingEntry {
 	} else {
 		code = nullable.NewNullNullable[string]()
 	}
+	problemScores := make([]ProblemScore, len(r.ProblemScores))
+	for i, ps := range r.ProblemScores {
+		problemScores[i] = ProblemScore{
+			ProblemID: ps.ProblemID,
+			Score:     toNullable(ps.Score),
+		}
+	}
 	return RankingEntry{
-		Player:      toAPIUser(r.Player),
-		Score:       r.Score,
-		SubmittedAt: r.SubmittedAt,
-		Code:        code,
+		Rank:            r.Rank,
+		Team:            toAPITeam(r.Team),
+		Score:           r.Score,
+		ProblemScores:   problemScores,
+		SubmissionCount: r.SubmissionCount,
+		SubmittedAt:     r.SubmittedAt,
+		Code:            code,
 	}
 }
 
@@ -86,13 +164,47 @@ func toAPISubmission(s game.SubmissionDetail) Submission {
 	return Submission{
 		SubmissionID: s.SubmissionID,
 		GameID:       s.GameID,
+		ProblemID:    s.ProblemID,
+		Language:     ProblemLanguage(s.Language),
 		Code:         s.Code,
 		CodeSize:     s.CodeSize,
 		Status:       ExecutionStatus(s.Status),
+		IsPractice:   s.IsPractice,
 		CreatedAt:    s.CreatedAt,
 	}
 }
 
+func toAPITestcaseResult(v game.TestcaseVerdict) TestcaseResult {
+	r := TestcaseResult{
+		TestcaseID: v.TestcaseID,
+		IsSample:   v.IsSample,
+		Passed:     v.Passed,
+	}
+	if v.Sample != nil {
+		status := ExecutionStatus(v.Sample.Status)
+		r.Status = &status
+		r.Stdin = &v.Sample.Stdin
+		r.ExpectedStdout = &v.Sample.ExpectedStdout
+		r.Stdout = &v.Sample.Stdout
+		r.Stderr = &v.Sample.Stderr
+	}
+	return r
+}
+
+func toAPIDiffLine(l codediff.Line) DiffLine {
+	d := DiffLine{
+		Op:   DiffOp(l.Op),
+		Text: l.Text,
+	}
+	if l.OldLine != 0 {
+		d.OldLine = &l.OldLine
+	}
+	if l.NewLine != 0 {
+		d.NewLine = &l.NewLine
+	}
+	return d
+}
+
 func toAPITournamentUser(p tournament.Player) User {
 	return User{
 		UserID:      p.UserID,
@@ -101,6 +213,8 @@ func toAPITournamentUser(p tournament.Player) User {
 		IconPath:    p.IconPath,
 		IsAdmin:     p.IsAdmin,
 		Label:       toNullable(p.Label),
+		Rating:      p.Rating,
+		RatingRank:  toNullable(p.RatingRank),
 	}
 }
 
@@ -145,6 +259,74 @@ func toAPITournament(t tournament.Bracket) Tournament {
 	}
 }
 
+func toAPIRatingChange(c rating.Change) RatingChange {
+	return RatingChange{
+		GameID:          c.GameID,
+		GameDisplayName: c.GameDisplayName,
+		GameRank:        c.GameRank,
+		RatingBefore:    c.RatingBefore,
+		RatingAfter:     c.RatingAfter,
+		RatedAt:         c.RatedAt.Unix(),
+	}
+}
+
+func toAPIQualifyingStage(s qualifying.Stage) QualifyingStage {
+	games := make([]QualifyingStageGame, len(s.Games))
+	for i, g := range s.Games {
+		games[i] = QualifyingStageGame{
+			GameID:      g.GameID,
+			DisplayName: g.DisplayName,
+			Weight:      g.Weight,
+		}
+	}
+	return QualifyingStage{
+		StageID:        s.StageID,
+		DisplayName:    s.DisplayName,
+		MissingPolicy:  MissingScorePolicy(s.MissingPolicy),
+		MissingPenalty: s.MissingPenalty,
+		Games:          games,
+	}
+}
+
+func toAPIQualifyingEntry(s qualifying.Stage, e qualifying.Entry) QualifyingEntry {
+	results := make([]QualifyingResult, 0, len(e.Results))
+	for i, r := range e.Results {
+		if r == nil {
+			continue
+		}
+		results = append(results, QualifyingResult{
+			GameID:      s.Games[i].GameID,
+			Score:       r.Score,
+			SubmittedAt: r.SubmittedAt.Unix(),
+		})
+	}
+	return QualifyingEntry{
+		Rank:        e.Rank,
+		User:        toAPIUser(e.Player),
+		Results:     results,
+		Total:       e.Total,
+		SubmittedAt: e.SubmittedAt.Unix(),
+	}
+}
+
+// toAPILanguage returns nil for an empty language.
+func toAPILanguage(language string) *ProblemLanguage {
+	if language == "" {
+		return nil
+	}
+	l := ProblemLanguage(language)
+	return &l
+}
+
+// fromAPILanguage returns an empty language for nil, which means the language
+// of the problem or all languages depending on the request.
+func fromAPILanguage(l *ProblemLanguage) string {
+	if l == nil {
+		return ""
+	}
+	return string(*l)
+}
+
 func toNullable[T any](p *T) nullable.Nullable[T] {
 	if p == nil {
 		return nullable.NewNullNullable[T]()
@@ -0,0 +1,75 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"albatross-2026-backend/game"
+)
+
+// Proxies close idle connections (nginx does after 60 seconds by default), so
+// a comment line is sent periodically while no events happen.
+const eventStreamKeepAliveInterval = 30 * time.Second
+
+// eventStreamResponse writes game events as server-sent events until the
+// client disconnects.
+type eventStreamResponse struct {
+	ctx         context.Context
+	events      <-chan game.Event
+	unsubscribe func()
+}
+
+func (r eventStreamResponse) VisitGetGamePlayEventsResponse(w http.ResponseWriter) error {
+	return r.stream(w)
+}
+
+func (r eventStreamResponse) VisitGetGameWatchEventsResponse(w http.ResponseWriter) error {
+	return r.stream(w)
+}
+
+func (r eventStreamResponse) stream(w http.ResponseWriter) error {
+	defer r.unsubscribe()
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return errors.New("streaming is not supported")
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	// Disable response buffering by nginx.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(200)
+	flusher.Flush()
+
+	ticker := time.NewTicker(eventStreamKeepAliveInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-r.ctx.Done():
+			return nil
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return err
+			}
+			flusher.Flush()
+		case event, ok := <-r.events:
+			if !ok {
+				return nil
+			}
+			data, err := json.Marshal(toAPIGameEvent(event))
+			if err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event.Type, data); err != nil {
+				return err
+			}
+			flusher.Flush()
+		}
+	}
+}
@@ -10,6 +10,7 @@ import (
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
 	"path"
@@ -22,16 +23,41 @@ import (
 	strictecho "github.com/oapi-codegen/runtime/strictmiddleware/echo"
 )
 
+// Defines values for DiffOp.
+const (
+	Delete DiffOp = "delete"
+	Equal  DiffOp = "equal"
+	Insert DiffOp = "insert"
+)
+
 // Defines values for ExecutionStatus.
 const (
-	CompileError  ExecutionStatus = "compile_error"
-	InternalError ExecutionStatus = "internal_error"
-	None          ExecutionStatus = "none"
-	Running       ExecutionStatus = "running"
-	RuntimeError  ExecutionStatus = "runtime_error"
-	Success       ExecutionStatus = "success"
-	Timeout       ExecutionStatus = "timeout"
-	WrongAnswer   ExecutionStatus = "wrong_answer"
+	ExecutionStatusCompileError  ExecutionStatus = "compile_error"
+	ExecutionStatusInternalError ExecutionStatus = "internal_error"
+	ExecutionStatusNone          ExecutionStatus = "none"
+	ExecutionStatusRunning       ExecutionStatus = "running"
+	ExecutionStatusRuntimeError  ExecutionStatus = "runtime_error"
+	ExecutionStatusSuccess       ExecutionStatus = "success"
+	ExecutionStatusTimeout       ExecutionStatus = "timeout"
+	ExecutionStatusWrongAnswer   ExecutionStatus = "wrong_answer"
+)
+
+// Defines values for GameEventType.
+const (
+	GameEventTypeBestScore GameEventType = "best_score"
+	GameEventTypeCode      GameEventType = "code"
+	GameEventTypeGame      GameEventType = "game"
+	GameEventTypeStatus    GameEventType = "status"
+)
+
+// Defines values for GameState.
+const (
+	GameStateCancelled GameState = "cancelled"
+	GameStateFinished  GameState = "finished"
+	GameStatePaused    GameState = "paused"
+	GameStateRunning   GameState = "running"
+	GameStateScheduled GameState = "scheduled"
+	GameStateWaiting   GameState = "waiting"
 )
 
 // Defines values for GameType.
@@ -40,12 +66,52 @@ const (
 	N1V1        GameType = "1v1"
 )
 
+// Defines values for MissingScorePolicy.
+const (
+	Exclude MissingScorePolicy = "exclude"
+	Penalty MissingScorePolicy = "penalty"
+)
+
 // Defines values for ProblemLanguage.
 const (
 	Php   ProblemLanguage = "php"
 	Swift ProblemLanguage = "swift"
 )
 
+// Defines values for ScoringStrategy.
+const (
+	Bytes         ScoringStrategy = "bytes"
+	Codepoints    ScoringStrategy = "codepoints"
+	PhpTokens     ScoringStrategy = "php_tokens"
+	StrippedBytes ScoringStrategy = "stripped_bytes"
+)
+
+// Defines values for TieBreak.
+const (
+	EarliestSubmission TieBreak = "earliest_submission"
+	FewestSubmissions  TieBreak = "fewest_submissions"
+	SharedRank         TieBreak = "shared_rank"
+)
+
+// CodeSnapshot defines model for CodeSnapshot.
+type CodeSnapshot struct {
+	Code         string `json:"code"`
+	CreatedAt    int64  `json:"created_at"`
+	IsSubmission bool   `json:"is_submission"`
+	UserID       int    `json:"user_id"`
+}
+
+// DiffLine defines model for DiffLine.
+type DiffLine struct {
+	NewLine *int   `json:"new_line,omitempty"`
+	OldLine *int   `json:"old_line,omitempty"`
+	Op      DiffOp `json:"op"`
+	Text    string `json:"text"`
+}
+
+// DiffOp defines model for DiffOp.
+type DiffOp string
+
 // Error defines model for Error.
 type Error struct {
 	Message string `json:"message"`
@@ -56,15 +122,37 @@ type ExecutionStatus string
 
 // Game defines model for Game.
 type Game struct {
-	DisplayName     string   `json:"display_name"`
-	DurationSeconds int      `json:"duration_seconds"`
-	GameID          int      `json:"game_id"`
-	GameType        GameType `json:"game_type"`
-	IsPublic        bool     `json:"is_public"`
-	MainPlayers     []User   `json:"main_players"`
-	Problem         Problem  `json:"problem"`
-	StartedAt       *int64   `json:"started_at,omitempty"`
-}
+	AllowPractice   bool      `json:"allow_practice"`
+	DisplayName     string    `json:"display_name"`
+	DurationSeconds int       `json:"duration_seconds"`
+	GameID          int       `json:"game_id"`
+	GameType        GameType  `json:"game_type"`
+	IsPublic        bool      `json:"is_public"`
+	MainPlayers     []User    `json:"main_players"`
+	PausedAt        *int64    `json:"paused_at,omitempty"`
+	Problems        []Problem `json:"problems"`
+	StartedAt       *int64    `json:"started_at,omitempty"`
+	State           GameState `json:"state"`
+}
+
+// GameEvent Sent as the data of a server-sent event whose event name is the same as `type`.
+type GameEvent struct {
+	BestScoreSubmittedAt *int64           `json:"best_score_submitted_at,omitempty"`
+	Code                 *string          `json:"code,omitempty"`
+	Language             *ProblemLanguage `json:"language,omitempty"`
+	ProblemID            int              `json:"problem_id"`
+	Score                *int             `json:"score,omitempty"`
+	Status               *ExecutionStatus `json:"status,omitempty"`
+	TeamID               int              `json:"team_id"`
+	Type                 GameEventType    `json:"type"`
+	UserID               int              `json:"user_id"`
+}
+
+// GameEventType defines model for GameEventType.
+type GameEventType string
+
+// GameState defines model for GameState.
+type GameState string
 
 // GameType defines model for GameType.
 type GameType string
@@ -73,40 +161,131 @@ type GameType string
 type LatestGameState struct {
 	BestScoreSubmittedAt nullable.Nullable[int64] `json:"best_score_submitted_at"`
 	Code                 string                   `json:"code"`
+	Language             *ProblemLanguage         `json:"language,omitempty"`
 	Score                nullable.Nullable[int]   `json:"score"`
 	Status               ExecutionStatus          `json:"status"`
 }
 
+// MissingScorePolicy defines model for MissingScorePolicy.
+type MissingScorePolicy string
+
 // Problem defines model for Problem.
 type Problem struct {
-	Description string          `json:"description"`
-	Language    ProblemLanguage `json:"language"`
-	ProblemID   int             `json:"problem_id"`
-	SampleCode  string          `json:"sample_code"`
-	Title       string          `json:"title"`
+	Description string                  `json:"description"`
+	Language    ProblemLanguage         `json:"language"`
+	Languages   []ProblemLanguageOption `json:"languages"`
+	ProblemID   int                     `json:"problem_id"`
+	SampleCode  string                  `json:"sample_code"`
+	Scoring     ScoringStrategy         `json:"scoring"`
+	Title       string                  `json:"title"`
 }
 
 // ProblemLanguage defines model for ProblemLanguage.
 type ProblemLanguage string
 
+// ProblemLanguageOption defines model for ProblemLanguageOption.
+type ProblemLanguageOption struct {
+	Language   ProblemLanguage `json:"language"`
+	SampleCode string          `json:"sample_code"`
+}
+
+// ProblemScore defines model for ProblemScore.
+type ProblemScore struct {
+	ProblemID int                    `json:"problem_id"`
+	Score     nullable.Nullable[int] `json:"score"`
+}
+
+// QualifyingEntry defines model for QualifyingEntry.
+type QualifyingEntry struct {
+	Rank        int                `json:"rank"`
+	Results     []QualifyingResult `json:"results"`
+	SubmittedAt int64              `json:"submitted_at"`
+	Total       float64            `json:"total"`
+	User        User               `json:"user"`
+}
+
+// QualifyingResult defines model for QualifyingResult.
+type QualifyingResult struct {
+	GameID      int   `json:"game_id"`
+	Score       int   `json:"score"`
+	SubmittedAt int64 `json:"submitted_at"`
+}
+
+// QualifyingStage defines model for QualifyingStage.
+type QualifyingStage struct {
+	DisplayName    string                `json:"display_name"`
+	Games          []QualifyingStageGame `json:"games"`
+	MissingPenalty int                   `json:"missing_penalty"`
+	MissingPolicy  MissingScorePolicy    `json:"missing_policy"`
+	StageID        int                   `json:"stage_id"`
+}
+
+// QualifyingStageGame defines model for QualifyingStageGame.
+type QualifyingStageGame struct {
+	DisplayName string  `json:"display_name"`
+	GameID      int     `json:"game_id"`
+	Weight      float64 `json:"weight"`
+}
+
 // RankingEntry defines model for RankingEntry.
 type RankingEntry struct {
-	Code        nullable.Nullable[string] `json:"code"`
-	Player      User                      `json:"player"`
-	Score       int                       `json:"score"`
-	SubmittedAt int64                     `json:"submitted_at"`
+	Code            nullable.Nullable[string] `json:"code"`
+	ProblemScores   []ProblemScore            `json:"problem_scores"`
+	Rank            int                       `json:"rank"`
+	Score           int                       `json:"score"`
+	SubmissionCount int                       `json:"submission_count"`
+	SubmittedAt     int64                     `json:"submitted_at"`
+	Team            Team                      `json:"team"`
+}
+
+// RatingChange defines model for RatingChange.
+type RatingChange struct {
+	GameDisplayName string `json:"game_display_name"`
+	GameID          int    `json:"game_id"`
+	GameRank        int    `json:"game_rank"`
+	RatedAt         int64  `json:"rated_at"`
+	RatingAfter     int    `json:"rating_after"`
+	RatingBefore    int    `json:"rating_before"`
 }
 
+// ScoringStrategy defines model for ScoringStrategy.
+type ScoringStrategy string
+
 // Submission defines model for Submission.
 type Submission struct {
 	Code         string          `json:"code"`
 	CodeSize     int             `json:"code_size"`
 	CreatedAt    int64           `json:"created_at"`
 	GameID       int             `json:"game_id"`
+	IsPractice   bool            `json:"is_practice"`
+	Language     ProblemLanguage `json:"language"`
+	ProblemID    int             `json:"problem_id"`
 	Status       ExecutionStatus `json:"status"`
 	SubmissionID int             `json:"submission_id"`
 }
 
+// Team defines model for Team.
+type Team struct {
+	DisplayName string `json:"display_name"`
+	Members     []User `json:"members"`
+	TeamID      int    `json:"team_id"`
+}
+
+// TestcaseResult defines model for TestcaseResult.
+type TestcaseResult struct {
+	ExpectedStdout *string          `json:"expected_stdout,omitempty"`
+	IsSample       bool             `json:"is_sample"`
+	Passed         bool             `json:"passed"`
+	Status         *ExecutionStatus `json:"status,omitempty"`
+	Stderr         *string          `json:"stderr,omitempty"`
+	Stdin          *string          `json:"stdin,omitempty"`
+	Stdout         *string          `json:"stdout,omitempty"`
+	TestcaseID     int              `json:"testcase_id"`
+}
+
+// TieBreak defines model for TieBreak.
+type TieBreak string
+
 // Tournament defines model for Tournament.
 type Tournament struct {
 	BracketSize  int               `json:"bracket_size"`
@@ -143,18 +322,62 @@ type User struct {
 	IconPath    *string                   `json:"icon_path,omitempty"`
 	IsAdmin     bool                      `json:"is_admin"`
 	Label       nullable.Nullable[string] `json:"label"`
+	Rating      int                       `json:"rating"`
+	RatingRank  nullable.Nullable[int]    `json:"rating_rank"`
 	UserID      int                       `json:"user_id"`
 	Username    string                    `json:"username"`
 }
 
 // PostGamePlayCodeJSONBody defines parameters for PostGamePlayCode.
 type PostGamePlayCodeJSONBody struct {
-	Code string `json:"code"`
+	Code      string           `json:"code"`
+	Language  *ProblemLanguage `json:"language,omitempty"`
+	ProblemID int              `json:"problem_id"`
+}
+
+// GetGamePlayLatestStateParams defines parameters for GetGamePlayLatestState.
+type GetGamePlayLatestStateParams struct {
+	ProblemID int `form:"problem_id" json:"problem_id"`
+}
+
+// PostGamePlayRunJSONBody defines parameters for PostGamePlayRun.
+type PostGamePlayRunJSONBody struct {
+	Code      string           `json:"code"`
+	Language  *ProblemLanguage `json:"language,omitempty"`
+	ProblemID int              `json:"problem_id"`
+	Stdin     string           `json:"stdin"`
 }
 
 // PostGamePlaySubmitJSONBody defines parameters for PostGamePlaySubmit.
 type PostGamePlaySubmitJSONBody struct {
-	Code string `json:"code"`
+	Code      string           `json:"code"`
+	Language  *ProblemLanguage `json:"language,omitempty"`
+	ProblemID int              `json:"problem_id"`
+}
+
+// GetGameSubmissionDiffParams defines parameters for GetGameSubmissionDiff.
+type GetGameSubmissionDiffParams struct {
+	From int `form:"from" json:"from"`
+	To   int `form:"to" json:"to"`
+}
+
+// GetGameWatchLatestStatesParams defines parameters for GetGameWatchLatestStates.
+type GetGameWatchLatestStatesParams struct {
+	ProblemID int `form:"problem_id" json:"problem_id"`
+}
+
+// GetGameWatchRankingParams defines parameters for GetGameWatchRanking.
+type GetGameWatchRankingParams struct {
+	Cursor   *string          `form:"cursor,omitempty" json:"cursor,omitempty"`
+	Limit    *int             `form:"limit,omitempty" json:"limit,omitempty"`
+	Practice *bool            `form:"practice,omitempty" json:"practice,omitempty"`
+	Language *ProblemLanguage `form:"language,omitempty" json:"language,omitempty"`
+}
+
+// GetGameWatchReplayParams defines parameters for GetGameWatchReplay.
+type GetGameWatchReplayParams struct {
+	UserID    int `form:"user_id" json:"user_id"`
+	ProblemID int `form:"problem_id" json:"problem_id"`
 }
 
 // PostLoginJSONBody defines parameters for PostLogin.
@@ -166,6 +389,9 @@ type PostLoginJSONBody struct {
 // PostGamePlayCodeJSONRequestBody defines body for PostGamePlayCode for application/json ContentType.
 type PostGamePlayCodeJSONRequestBody PostGamePlayCodeJSONBody
 
+// PostGamePlayRunJSONRequestBody defines body for PostGamePlayRun for application/json ContentType.
+type PostGamePlayRunJSONRequestBody PostGamePlayRunJSONBody
+
 // PostGamePlaySubmitJSONRequestBody defines body for PostGamePlaySubmit for application/json ContentType.
 type PostGamePlaySubmitJSONRequestBody PostGamePlaySubmitJSONBody
 
@@ -184,20 +410,41 @@ type ServerInterface interface {
 	// (POST /games/{game_id}/play/code)
 	PostGamePlayCode(ctx echo.Context, gameID int) error
 
+	// (GET /games/{game_id}/play/events)
+	GetGamePlayEvents(ctx echo.Context, gameID int) error
+
 	// (GET /games/{game_id}/play/latest_state)
-	GetGamePlayLatestState(ctx echo.Context, gameID int) error
+	GetGamePlayLatestState(ctx echo.Context, gameID int, params GetGamePlayLatestStateParams) error
+
+	// (POST /games/{game_id}/play/run)
+	PostGamePlayRun(ctx echo.Context, gameID int) error
 
 	// (GET /games/{game_id}/play/submissions)
 	GetGamePlaySubmissions(ctx echo.Context, gameID int) error
 
+	// (GET /games/{game_id}/play/submissions/{submission_id})
+	GetGamePlaySubmission(ctx echo.Context, gameID int, submissionID int) error
+
 	// (POST /games/{game_id}/play/submit)
 	PostGamePlaySubmit(ctx echo.Context, gameID int) error
 
+	// (GET /games/{game_id}/submissions/diff)
+	GetGameSubmissionDiff(ctx echo.Context, gameID int, params GetGameSubmissionDiffParams) error
+
+	// (GET /games/{game_id}/watch/events)
+	GetGameWatchEvents(ctx echo.Context, gameID int) error
+
 	// (GET /games/{game_id}/watch/latest_states)
-	GetGameWatchLatestStates(ctx echo.Context, gameID int) error
+	GetGameWatchLatestStates(ctx echo.Context, gameID int, params GetGameWatchLatestStatesParams) error
 
 	// (GET /games/{game_id}/watch/ranking)
-	GetGameWatchRanking(ctx echo.Context, gameID int) error
+	GetGameWatchRanking(ctx echo.Context, gameID int, params GetGameWatchRankingParams) error
+
+	// (GET /games/{game_id}/watch/replay)
+	GetGameWatchReplay(ctx echo.Context, gameID int, params GetGameWatchReplayParams) error
+
+	// (GET /games/{game_id}/watch/teams)
+	GetGameWatchTeams(ctx echo.Context, gameID int) error
 
 	// (POST /login)
 	PostLogin(ctx echo.Context) error
@@ -208,8 +455,20 @@ type ServerInterface interface {
 	// (GET /me)
 	GetMe(ctx echo.Context) error
 
+	// (GET /qualifying_stages)
+	GetQualifyingStages(ctx echo.Context) error
+
+	// (GET /qualifying_stages/{stage_id}/ranking)
+	GetQualifyingStageRanking(ctx echo.Context, stageID int) error
+
+	// (GET /ratings)
+	GetRatings(ctx echo.Context) error
+
 	// (GET /tournaments/{tournament_id})
 	GetTournament(ctx echo.Context, tournamentID int) error
+
+	// (GET /users/{user_id}/rating_history)
+	GetUserRatingHistory(ctx echo.Context, userID int) error
 }
 
 // ServerInterfaceWrapper converts echo contexts to parameters.
@@ -258,6 +517,22 @@ func (w *ServerInterfaceWrapper) PostGamePlayCode(ctx echo.Context) error {
 	return err
 }
 
+// GetGamePlayEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGamePlayEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGamePlayEvents(ctx, gameID)
+	return err
+}
+
 // GetGamePlayLatestState converts echo context to params.
 func (w *ServerInterfaceWrapper) GetGamePlayLatestState(ctx echo.Context) error {
 	var err error
@@ -269,8 +544,33 @@ func (w *ServerInterfaceWrapper) GetGamePlayLatestState(ctx echo.Context) error
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGamePlayLatestStateParams
+	// ------------- Required query parameter "problem_id" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "problem_id", ctx.QueryParams(), &params.ProblemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter problem_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGamePlayLatestState(ctx, gameID, params)
+	return err
+}
+
+// PostGamePlayRun converts echo context to params.
+func (w *ServerInterfaceWrapper) PostGamePlayRun(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetGamePlayLatestState(ctx, gameID)
+	err = w.Handler.PostGamePlayRun(ctx, gameID)
 	return err
 }
 
@@ -290,6 +590,30 @@ func (w *ServerInterfaceWrapper) GetGamePlaySubmissions(ctx echo.Context) error
 	return err
 }
 
+// GetGamePlaySubmission converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGamePlaySubmission(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
+	// ------------- Path parameter "submission_id" -------------
+	var submissionID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "submission_id", ctx.Param("submission_id"), &submissionID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter submission_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGamePlaySubmission(ctx, gameID, submissionID)
+	return err
+}
+
 // PostGamePlaySubmit converts echo context to params.
 func (w *ServerInterfaceWrapper) PostGamePlaySubmit(ctx echo.Context) error {
 	var err error
@@ -306,6 +630,54 @@ func (w *ServerInterfaceWrapper) PostGamePlaySubmit(ctx echo.Context) error {
 	return err
 }
 
+// GetGameSubmissionDiff converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGameSubmissionDiff(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGameSubmissionDiffParams
+	// ------------- Required query parameter "from" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "from", ctx.QueryParams(), &params.From)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter from: %s", err))
+	}
+
+	// ------------- Required query parameter "to" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "to", ctx.QueryParams(), &params.To)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter to: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGameSubmissionDiff(ctx, gameID, params)
+	return err
+}
+
+// GetGameWatchEvents converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGameWatchEvents(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGameWatchEvents(ctx, gameID)
+	return err
+}
+
 // GetGameWatchLatestStates converts echo context to params.
 func (w *ServerInterfaceWrapper) GetGameWatchLatestStates(ctx echo.Context) error {
 	var err error
@@ -317,8 +689,17 @@ func (w *ServerInterfaceWrapper) GetGameWatchLatestStates(ctx echo.Context) erro
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGameWatchLatestStatesParams
+	// ------------- Required query parameter "problem_id" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "problem_id", ctx.QueryParams(), &params.ProblemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter problem_id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetGameWatchLatestStates(ctx, gameID)
+	err = w.Handler.GetGameWatchLatestStates(ctx, gameID, params)
 	return err
 }
 
@@ -333,8 +714,86 @@ func (w *ServerInterfaceWrapper) GetGameWatchRanking(ctx echo.Context) error {
 		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
 	}
 
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGameWatchRankingParams
+	// ------------- Optional query parameter "cursor" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "cursor", ctx.QueryParams(), &params.Cursor)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter cursor: %s", err))
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "limit", ctx.QueryParams(), &params.Limit)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter limit: %s", err))
+	}
+
+	// ------------- Optional query parameter "practice" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "practice", ctx.QueryParams(), &params.Practice)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter practice: %s", err))
+	}
+
+	// ------------- Optional query parameter "language" -------------
+
+	err = runtime.BindQueryParameter("form", false, false, "language", ctx.QueryParams(), &params.Language)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter language: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGameWatchRanking(ctx, gameID, params)
+	return err
+}
+
+// GetGameWatchReplay converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGameWatchReplay(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetGameWatchReplayParams
+	// ------------- Required query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "user_id", ctx.QueryParams(), &params.UserID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// ------------- Required query parameter "problem_id" -------------
+
+	err = runtime.BindQueryParameter("form", false, true, "problem_id", ctx.QueryParams(), &params.ProblemID)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter problem_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetGameWatchReplay(ctx, gameID, params)
+	return err
+}
+
+// GetGameWatchTeams converts echo context to params.
+func (w *ServerInterfaceWrapper) GetGameWatchTeams(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "game_id" -------------
+	var gameID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "game_id", ctx.Param("game_id"), &gameID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter game_id: %s", err))
+	}
+
 	// Invoke the callback with all the unmarshaled arguments
-	err = w.Handler.GetGameWatchRanking(ctx, gameID)
+	err = w.Handler.GetGameWatchTeams(ctx, gameID)
 	return err
 }
 
@@ -365,6 +824,40 @@ func (w *ServerInterfaceWrapper) GetMe(ctx echo.Context) error {
 	return err
 }
 
+// GetQualifyingStages converts echo context to params.
+func (w *ServerInterfaceWrapper) GetQualifyingStages(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetQualifyingStages(ctx)
+	return err
+}
+
+// GetQualifyingStageRanking converts echo context to params.
+func (w *ServerInterfaceWrapper) GetQualifyingStageRanking(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "stage_id" -------------
+	var stageID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "stage_id", ctx.Param("stage_id"), &stageID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter stage_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetQualifyingStageRanking(ctx, stageID)
+	return err
+}
+
+// GetRatings converts echo context to params.
+func (w *ServerInterfaceWrapper) GetRatings(ctx echo.Context) error {
+	var err error
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetRatings(ctx)
+	return err
+}
+
 // GetTournament converts echo context to params.
 func (w *ServerInterfaceWrapper) GetTournament(ctx echo.Context) error {
 	var err error
@@ -381,6 +874,22 @@ func (w *ServerInterfaceWrapper) GetTournament(ctx echo.Context) error {
 	return err
 }
 
+// GetUserRatingHistory converts echo context to params.
+func (w *ServerInterfaceWrapper) GetUserRatingHistory(ctx echo.Context) error {
+	var err error
+	// ------------- Path parameter "user_id" -------------
+	var userID int
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", ctx.Param("user_id"), &userID, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true})
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid format for parameter user_id: %s", err))
+	}
+
+	// Invoke the callback with all the unmarshaled arguments
+	err = w.Handler.GetUserRatingHistory(ctx, userID)
+	return err
+}
+
 // This is a simple interface which specifies echo.Route addition functions which
 // are present on both echo.Echo and echo.Group, since we want to allow using
 // either of them for path registration
@@ -412,15 +921,26 @@ func RegisterHandlersWithBaseURL(router EchoRouter, si ServerInterface, baseURL
 	router.GET(baseURL+"/games", wrapper.GetGames)
 	router.GET(baseURL+"/games/:game_id", wrapper.GetGame)
 	router.POST(baseURL+"/games/:game_id/play/code", wrapper.PostGamePlayCode)
+	router.GET(baseURL+"/games/:game_id/play/events", wrapper.GetGamePlayEvents)
 	router.GET(baseURL+"/games/:game_id/play/latest_state", wrapper.GetGamePlayLatestState)
+	router.POST(baseURL+"/games/:game_id/play/run", wrapper.PostGamePlayRun)
 	router.GET(baseURL+"/games/:game_id/play/submissions", wrapper.GetGamePlaySubmissions)
+	router.GET(baseURL+"/games/:game_id/play/submissions/:submission_id", wrapper.GetGamePlaySubmission)
 	router.POST(baseURL+"/games/:game_id/play/submit", wrapper.PostGamePlaySubmit)
+	router.GET(baseURL+"/games/:game_id/submissions/diff", wrapper.GetGameSubmissionDiff)
+	router.GET(baseURL+"/games/:game_id/watch/events", wrapper.GetGameWatchEvents)
 	router.GET(baseURL+"/games/:game_id/watch/latest_states", wrapper.GetGameWatchLatestStates)
 	router.GET(baseURL+"/games/:game_id/watch/ranking", wrapper.GetGameWatchRanking)
+	router.GET(baseURL+"/games/:game_id/watch/replay", wrapper.GetGameWatchReplay)
+	router.GET(baseURL+"/games/:game_id/watch/teams", wrapper.GetGameWatchTeams)
 	router.POST(baseURL+"/login", wrapper.PostLogin)
 	router.POST(baseURL+"/logout", wrapper.PostLogout)
 	router.GET(baseURL+"/me", wrapper.GetMe)
+	router.GET(baseURL+"/qualifying_stages", wrapper.GetQualifyingStages)
+	router.GET(baseURL+"/qualifying_stages/:stage_id/ranking", wrapper.GetQualifyingStageRanking)
+	router.GET(baseURL+"/ratings", wrapper.GetRatings)
 	router.GET(baseURL+"/tournaments/:tournament_id", wrapper.GetTournament)
+	router.GET(baseURL+"/users/:user_id/rating_history", wrapper.GetUserRatingHistory)
 
 }
 
@@ -523,6 +1043,15 @@ func (response PostGamePlayCode200Response) VisitPostGamePlayCodeResponse(w http
 	return nil
 }
 
+type PostGamePlayCode400JSONResponse Error
+
+func (response PostGamePlayCode400JSONResponse) VisitPostGamePlayCodeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type PostGamePlayCode401JSONResponse Error
 
 func (response PostGamePlayCode401JSONResponse) VisitPostGamePlayCodeResponse(w http.ResponseWriter) error {
@@ -550,44 +1079,99 @@ func (response PostGamePlayCode404JSONResponse) VisitPostGamePlayCodeResponse(w
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetGamePlayLatestStateRequestObject struct {
+type GetGamePlayEventsRequestObject struct {
 	GameID int `json:"game_id"`
 }
 
-type GetGamePlayLatestStateResponseObject interface {
-	VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error
+type GetGamePlayEventsResponseObject interface {
+	VisitGetGamePlayEventsResponse(w http.ResponseWriter) error
 }
 
-type GetGamePlayLatestState200JSONResponse struct {
-	State LatestGameState `json:"state"`
+type GetGamePlayEvents200TextEventStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
 }
 
-func (response GetGamePlayLatestState200JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
-	w.Header().Set("Content-Type", "application/json")
+func (response GetGamePlayEvents200TextEventStreamResponse) VisitGetGamePlayEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
 	w.WriteHeader(200)
 
-	return json.NewEncoder(w).Encode(response)
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
 }
 
-type GetGamePlayLatestState401JSONResponse Error
+type GetGamePlayEvents401JSONResponse Error
 
-func (response GetGamePlayLatestState401JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
+func (response GetGamePlayEvents401JSONResponse) VisitGetGamePlayEventsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetGamePlayLatestState403JSONResponse Error
+type GetGamePlayEvents403JSONResponse Error
 
-func (response GetGamePlayLatestState403JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
+func (response GetGamePlayEvents403JSONResponse) VisitGetGamePlayEventsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(403)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetGamePlayLatestState404JSONResponse Error
+type GetGamePlayEvents404JSONResponse Error
+
+func (response GetGamePlayEvents404JSONResponse) VisitGetGamePlayEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlayLatestStateRequestObject struct {
+	GameID int `json:"game_id"`
+	Params GetGamePlayLatestStateParams
+}
+
+type GetGamePlayLatestStateResponseObject interface {
+	VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error
+}
+
+type GetGamePlayLatestState200JSONResponse struct {
+	State LatestGameState `json:"state"`
+}
+
+func (response GetGamePlayLatestState200JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlayLatestState401JSONResponse Error
+
+func (response GetGamePlayLatestState401JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlayLatestState403JSONResponse Error
+
+func (response GetGamePlayLatestState403JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlayLatestState404JSONResponse Error
 
 func (response GetGamePlayLatestState404JSONResponse) VisitGetGamePlayLatestStateResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
@@ -596,6 +1180,64 @@ func (response GetGamePlayLatestState404JSONResponse) VisitGetGamePlayLatestStat
 	return json.NewEncoder(w).Encode(response)
 }
 
+type PostGamePlayRunRequestObject struct {
+	GameID int `json:"game_id"`
+	Body   *PostGamePlayRunJSONRequestBody
+}
+
+type PostGamePlayRunResponseObject interface {
+	VisitPostGamePlayRunResponse(w http.ResponseWriter) error
+}
+
+type PostGamePlayRun200JSONResponse struct {
+	Status ExecutionStatus `json:"status"`
+	Stderr string          `json:"stderr"`
+	Stdout string          `json:"stdout"`
+}
+
+func (response PostGamePlayRun200JSONResponse) VisitPostGamePlayRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostGamePlayRun400JSONResponse Error
+
+func (response PostGamePlayRun400JSONResponse) VisitPostGamePlayRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostGamePlayRun401JSONResponse Error
+
+func (response PostGamePlayRun401JSONResponse) VisitPostGamePlayRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostGamePlayRun403JSONResponse Error
+
+func (response PostGamePlayRun403JSONResponse) VisitPostGamePlayRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type PostGamePlayRun404JSONResponse Error
+
+func (response PostGamePlayRun404JSONResponse) VisitPostGamePlayRunResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type GetGamePlaySubmissionsRequestObject struct {
 	GameID int `json:"game_id"`
 }
@@ -642,6 +1284,54 @@ func (response GetGamePlaySubmissions404JSONResponse) VisitGetGamePlaySubmission
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetGamePlaySubmissionRequestObject struct {
+	GameID       int `json:"game_id"`
+	SubmissionID int `json:"submission_id"`
+}
+
+type GetGamePlaySubmissionResponseObject interface {
+	VisitGetGamePlaySubmissionResponse(w http.ResponseWriter) error
+}
+
+type GetGamePlaySubmission200JSONResponse struct {
+	Submission      Submission       `json:"submission"`
+	TestcaseResults []TestcaseResult `json:"testcase_results"`
+}
+
+func (response GetGamePlaySubmission200JSONResponse) VisitGetGamePlaySubmissionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlaySubmission401JSONResponse Error
+
+func (response GetGamePlaySubmission401JSONResponse) VisitGetGamePlaySubmissionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlaySubmission403JSONResponse Error
+
+func (response GetGamePlaySubmission403JSONResponse) VisitGetGamePlaySubmissionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGamePlaySubmission404JSONResponse Error
+
+func (response GetGamePlaySubmission404JSONResponse) VisitGetGamePlaySubmissionResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type PostGamePlaySubmitRequestObject struct {
 	GameID int `json:"game_id"`
 	Body   *PostGamePlaySubmitJSONRequestBody
@@ -659,6 +1349,15 @@ func (response PostGamePlaySubmit200Response) VisitPostGamePlaySubmitResponse(w
 	return nil
 }
 
+type PostGamePlaySubmit400JSONResponse Error
+
+func (response PostGamePlaySubmit400JSONResponse) VisitPostGamePlaySubmitResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type PostGamePlaySubmit401JSONResponse Error
 
 func (response PostGamePlaySubmit401JSONResponse) VisitPostGamePlaySubmitResponse(w http.ResponseWriter) error {
@@ -686,8 +1385,112 @@ func (response PostGamePlaySubmit404JSONResponse) VisitPostGamePlaySubmitRespons
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetGameSubmissionDiffRequestObject struct {
+	GameID int `json:"game_id"`
+	Params GetGameSubmissionDiffParams
+}
+
+type GetGameSubmissionDiffResponseObject interface {
+	VisitGetGameSubmissionDiffResponse(w http.ResponseWriter) error
+}
+
+type GetGameSubmissionDiff200JSONResponse struct {
+	Lines     []DiffLine `json:"lines"`
+	SizeDelta int        `json:"size_delta"`
+	Unified   string     `json:"unified"`
+}
+
+func (response GetGameSubmissionDiff200JSONResponse) VisitGetGameSubmissionDiffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameSubmissionDiff401JSONResponse Error
+
+func (response GetGameSubmissionDiff401JSONResponse) VisitGetGameSubmissionDiffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameSubmissionDiff403JSONResponse Error
+
+func (response GetGameSubmissionDiff403JSONResponse) VisitGetGameSubmissionDiffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameSubmissionDiff404JSONResponse Error
+
+func (response GetGameSubmissionDiff404JSONResponse) VisitGetGameSubmissionDiffResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchEventsRequestObject struct {
+	GameID int `json:"game_id"`
+}
+
+type GetGameWatchEventsResponseObject interface {
+	VisitGetGameWatchEventsResponse(w http.ResponseWriter) error
+}
+
+type GetGameWatchEvents200TextEventStreamResponse struct {
+	Body          io.Reader
+	ContentLength int64
+}
+
+func (response GetGameWatchEvents200TextEventStreamResponse) VisitGetGameWatchEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "text/event-stream")
+	if response.ContentLength != 0 {
+		w.Header().Set("Content-Length", fmt.Sprint(response.ContentLength))
+	}
+	w.WriteHeader(200)
+
+	if closer, ok := response.Body.(io.ReadCloser); ok {
+		defer closer.Close()
+	}
+	_, err := io.Copy(w, response.Body)
+	return err
+}
+
+type GetGameWatchEvents401JSONResponse Error
+
+func (response GetGameWatchEvents401JSONResponse) VisitGetGameWatchEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchEvents403JSONResponse Error
+
+func (response GetGameWatchEvents403JSONResponse) VisitGetGameWatchEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchEvents404JSONResponse Error
+
+func (response GetGameWatchEvents404JSONResponse) VisitGetGameWatchEventsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type GetGameWatchLatestStatesRequestObject struct {
 	GameID int `json:"game_id"`
+	Params GetGameWatchLatestStatesParams
 }
 
 type GetGameWatchLatestStatesResponseObject interface {
@@ -734,6 +1537,7 @@ func (response GetGameWatchLatestStates404JSONResponse) VisitGetGameWatchLatestS
 
 type GetGameWatchRankingRequestObject struct {
 	GameID int `json:"game_id"`
+	Params GetGameWatchRankingParams
 }
 
 type GetGameWatchRankingResponseObject interface {
@@ -741,7 +1545,10 @@ type GetGameWatchRankingResponseObject interface {
 }
 
 type GetGameWatchRanking200JSONResponse struct {
-	Ranking []RankingEntry `json:"ranking"`
+	IsFrozen   bool                      `json:"is_frozen"`
+	NextCursor nullable.Nullable[string] `json:"next_cursor"`
+	Ranking    []RankingEntry            `json:"ranking"`
+	TieBreak   TieBreak                  `json:"tie_break"`
 }
 
 func (response GetGameWatchRanking200JSONResponse) VisitGetGameWatchRankingResponse(w http.ResponseWriter) error {
@@ -751,6 +1558,15 @@ func (response GetGameWatchRanking200JSONResponse) VisitGetGameWatchRankingRespo
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetGameWatchRanking400JSONResponse Error
+
+func (response GetGameWatchRanking400JSONResponse) VisitGetGameWatchRankingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(400)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type GetGameWatchRanking401JSONResponse Error
 
 func (response GetGameWatchRanking401JSONResponse) VisitGetGameWatchRankingResponse(w http.ResponseWriter) error {
@@ -778,6 +1594,99 @@ func (response GetGameWatchRanking404JSONResponse) VisitGetGameWatchRankingRespo
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetGameWatchReplayRequestObject struct {
+	GameID int `json:"game_id"`
+	Params GetGameWatchReplayParams
+}
+
+type GetGameWatchReplayResponseObject interface {
+	VisitGetGameWatchReplayResponse(w http.ResponseWriter) error
+}
+
+type GetGameWatchReplay200JSONResponse struct {
+	Snapshots []CodeSnapshot `json:"snapshots"`
+}
+
+func (response GetGameWatchReplay200JSONResponse) VisitGetGameWatchReplayResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchReplay401JSONResponse Error
+
+func (response GetGameWatchReplay401JSONResponse) VisitGetGameWatchReplayResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchReplay403JSONResponse Error
+
+func (response GetGameWatchReplay403JSONResponse) VisitGetGameWatchReplayResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchReplay404JSONResponse Error
+
+func (response GetGameWatchReplay404JSONResponse) VisitGetGameWatchReplayResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchTeamsRequestObject struct {
+	GameID int `json:"game_id"`
+}
+
+type GetGameWatchTeamsResponseObject interface {
+	VisitGetGameWatchTeamsResponse(w http.ResponseWriter) error
+}
+
+type GetGameWatchTeams200JSONResponse struct {
+	Teams []Team `json:"teams"`
+}
+
+func (response GetGameWatchTeams200JSONResponse) VisitGetGameWatchTeamsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchTeams401JSONResponse Error
+
+func (response GetGameWatchTeams401JSONResponse) VisitGetGameWatchTeamsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchTeams403JSONResponse Error
+
+func (response GetGameWatchTeams403JSONResponse) VisitGetGameWatchTeamsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetGameWatchTeams404JSONResponse Error
+
+func (response GetGameWatchTeams404JSONResponse) VisitGetGameWatchTeamsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type PostLoginRequestObject struct {
 	Body *PostLoginJSONRequestBody
 }
@@ -830,33 +1739,152 @@ func (response PostLogout401JSONResponse) VisitPostLogoutResponse(w http.Respons
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetMeRequestObject struct {
+type GetMeRequestObject struct {
+}
+
+type GetMeResponseObject interface {
+	VisitGetMeResponse(w http.ResponseWriter) error
+}
+
+type GetMe200JSONResponse struct {
+	User User `json:"user"`
+}
+
+func (response GetMe200JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetMe401JSONResponse Error
+
+func (response GetMe401JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStagesRequestObject struct {
+}
+
+type GetQualifyingStagesResponseObject interface {
+	VisitGetQualifyingStagesResponse(w http.ResponseWriter) error
+}
+
+type GetQualifyingStages200JSONResponse struct {
+	Stages []QualifyingStage `json:"stages"`
+}
+
+func (response GetQualifyingStages200JSONResponse) VisitGetQualifyingStagesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStages401JSONResponse Error
+
+func (response GetQualifyingStages401JSONResponse) VisitGetQualifyingStagesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStages403JSONResponse Error
+
+func (response GetQualifyingStages403JSONResponse) VisitGetQualifyingStagesResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStageRankingRequestObject struct {
+	StageID int `json:"stage_id"`
+}
+
+type GetQualifyingStageRankingResponseObject interface {
+	VisitGetQualifyingStageRankingResponse(w http.ResponseWriter) error
+}
+
+type GetQualifyingStageRanking200JSONResponse struct {
+	Ranking []QualifyingEntry `json:"ranking"`
+	Stage   QualifyingStage   `json:"stage"`
+}
+
+func (response GetQualifyingStageRanking200JSONResponse) VisitGetQualifyingStageRankingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStageRanking401JSONResponse Error
+
+func (response GetQualifyingStageRanking401JSONResponse) VisitGetQualifyingStageRankingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStageRanking403JSONResponse Error
+
+func (response GetQualifyingStageRanking403JSONResponse) VisitGetQualifyingStageRankingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetQualifyingStageRanking404JSONResponse Error
+
+func (response GetQualifyingStageRanking404JSONResponse) VisitGetQualifyingStageRankingResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetRatingsRequestObject struct {
 }
 
-type GetMeResponseObject interface {
-	VisitGetMeResponse(w http.ResponseWriter) error
+type GetRatingsResponseObject interface {
+	VisitGetRatingsResponse(w http.ResponseWriter) error
 }
 
-type GetMe200JSONResponse struct {
-	User User `json:"user"`
+type GetRatings200JSONResponse struct {
+	Users []User `json:"users"`
 }
 
-func (response GetMe200JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+func (response GetRatings200JSONResponse) VisitGetRatingsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(200)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
-type GetMe401JSONResponse Error
+type GetRatings401JSONResponse Error
 
-func (response GetMe401JSONResponse) VisitGetMeResponse(w http.ResponseWriter) error {
+func (response GetRatings401JSONResponse) VisitGetRatingsResponse(w http.ResponseWriter) error {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(401)
 
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetRatings403JSONResponse Error
+
+func (response GetRatings403JSONResponse) VisitGetRatingsResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 type GetTournamentRequestObject struct {
 	TournamentID int `json:"tournament_id"`
 }
@@ -903,6 +1931,53 @@ func (response GetTournament404JSONResponse) VisitGetTournamentResponse(w http.R
 	return json.NewEncoder(w).Encode(response)
 }
 
+type GetUserRatingHistoryRequestObject struct {
+	UserID int `json:"user_id"`
+}
+
+type GetUserRatingHistoryResponseObject interface {
+	VisitGetUserRatingHistoryResponse(w http.ResponseWriter) error
+}
+
+type GetUserRatingHistory200JSONResponse struct {
+	History []RatingChange `json:"history"`
+	User    User           `json:"user"`
+}
+
+func (response GetUserRatingHistory200JSONResponse) VisitGetUserRatingHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(200)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUserRatingHistory401JSONResponse Error
+
+func (response GetUserRatingHistory401JSONResponse) VisitGetUserRatingHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(401)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUserRatingHistory403JSONResponse Error
+
+func (response GetUserRatingHistory403JSONResponse) VisitGetUserRatingHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(403)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
+type GetUserRatingHistory404JSONResponse Error
+
+func (response GetUserRatingHistory404JSONResponse) VisitGetUserRatingHistoryResponse(w http.ResponseWriter) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(404)
+
+	return json.NewEncoder(w).Encode(response)
+}
+
 // StrictServerInterface represents all server handlers.
 type StrictServerInterface interface {
 
@@ -915,21 +1990,42 @@ type StrictServerInterface interface {
 	// (POST /games/{game_id}/play/code)
 	PostGamePlayCode(ctx context.Context, request PostGamePlayCodeRequestObject) (PostGamePlayCodeResponseObject, error)
 
+	// (GET /games/{game_id}/play/events)
+	GetGamePlayEvents(ctx context.Context, request GetGamePlayEventsRequestObject) (GetGamePlayEventsResponseObject, error)
+
 	// (GET /games/{game_id}/play/latest_state)
 	GetGamePlayLatestState(ctx context.Context, request GetGamePlayLatestStateRequestObject) (GetGamePlayLatestStateResponseObject, error)
 
+	// (POST /games/{game_id}/play/run)
+	PostGamePlayRun(ctx context.Context, request PostGamePlayRunRequestObject) (PostGamePlayRunResponseObject, error)
+
 	// (GET /games/{game_id}/play/submissions)
 	GetGamePlaySubmissions(ctx context.Context, request GetGamePlaySubmissionsRequestObject) (GetGamePlaySubmissionsResponseObject, error)
 
+	// (GET /games/{game_id}/play/submissions/{submission_id})
+	GetGamePlaySubmission(ctx context.Context, request GetGamePlaySubmissionRequestObject) (GetGamePlaySubmissionResponseObject, error)
+
 	// (POST /games/{game_id}/play/submit)
 	PostGamePlaySubmit(ctx context.Context, request PostGamePlaySubmitRequestObject) (PostGamePlaySubmitResponseObject, error)
 
+	// (GET /games/{game_id}/submissions/diff)
+	GetGameSubmissionDiff(ctx context.Context, request GetGameSubmissionDiffRequestObject) (GetGameSubmissionDiffResponseObject, error)
+
+	// (GET /games/{game_id}/watch/events)
+	GetGameWatchEvents(ctx context.Context, request GetGameWatchEventsRequestObject) (GetGameWatchEventsResponseObject, error)
+
 	// (GET /games/{game_id}/watch/latest_states)
 	GetGameWatchLatestStates(ctx context.Context, request GetGameWatchLatestStatesRequestObject) (GetGameWatchLatestStatesResponseObject, error)
 
 	// (GET /games/{game_id}/watch/ranking)
 	GetGameWatchRanking(ctx context.Context, request GetGameWatchRankingRequestObject) (GetGameWatchRankingResponseObject, error)
 
+	// (GET /games/{game_id}/watch/replay)
+	GetGameWatchReplay(ctx context.Context, request GetGameWatchReplayRequestObject) (GetGameWatchReplayResponseObject, error)
+
+	// (GET /games/{game_id}/watch/teams)
+	GetGameWatchTeams(ctx context.Context, request GetGameWatchTeamsRequestObject) (GetGameWatchTeamsResponseObject, error)
+
 	// (POST /login)
 	PostLogin(ctx context.Context, request PostLoginRequestObject) (PostLoginResponseObject, error)
 
@@ -939,8 +2035,20 @@ type StrictServerInterface interface {
 	// (GET /me)
 	GetMe(ctx context.Context, request GetMeRequestObject) (GetMeResponseObject, error)
 
+	// (GET /qualifying_stages)
+	GetQualifyingStages(ctx context.Context, request GetQualifyingStagesRequestObject) (GetQualifyingStagesResponseObject, error)
+
+	// (GET /qualifying_stages/{stage_id}/ranking)
+	GetQualifyingStageRanking(ctx context.Context, request GetQualifyingStageRankingRequestObject) (GetQualifyingStageRankingResponseObject, error)
+
+	// (GET /ratings)
+	GetRatings(ctx context.Context, request GetRatingsRequestObject) (GetRatingsResponseObject, error)
+
 	// (GET /tournaments/{tournament_id})
 	GetTournament(ctx context.Context, request GetTournamentRequestObject) (GetTournamentResponseObject, error)
+
+	// (GET /users/{user_id}/rating_history)
+	GetUserRatingHistory(ctx context.Context, request GetUserRatingHistoryRequestObject) (GetUserRatingHistoryResponseObject, error)
 }
 
 type StrictHandlerFunc = strictecho.StrictEchoHandlerFunc
@@ -1034,11 +2142,37 @@ func (sh *strictHandler) PostGamePlayCode(ctx echo.Context, gameID int) error {
 	return nil
 }
 
+// GetGamePlayEvents operation middleware
+func (sh *strictHandler) GetGamePlayEvents(ctx echo.Context, gameID int) error {
+	var request GetGamePlayEventsRequestObject
+
+	request.GameID = gameID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGamePlayEvents(ctx.Request().Context(), request.(GetGamePlayEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGamePlayEvents")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGamePlayEventsResponseObject); ok {
+		return validResponse.VisitGetGamePlayEventsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // GetGamePlayLatestState operation middleware
-func (sh *strictHandler) GetGamePlayLatestState(ctx echo.Context, gameID int) error {
+func (sh *strictHandler) GetGamePlayLatestState(ctx echo.Context, gameID int, params GetGamePlayLatestStateParams) error {
 	var request GetGamePlayLatestStateRequestObject
 
 	request.GameID = gameID
+	request.Params = params
 
 	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
 		return sh.ssi.GetGamePlayLatestState(ctx.Request().Context(), request.(GetGamePlayLatestStateRequestObject))
@@ -1059,6 +2193,37 @@ func (sh *strictHandler) GetGamePlayLatestState(ctx echo.Context, gameID int) er
 	return nil
 }
 
+// PostGamePlayRun operation middleware
+func (sh *strictHandler) PostGamePlayRun(ctx echo.Context, gameID int) error {
+	var request PostGamePlayRunRequestObject
+
+	request.GameID = gameID
+
+	var body PostGamePlayRunJSONRequestBody
+	if err := ctx.Bind(&body); err != nil {
+		return err
+	}
+	request.Body = &body
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.PostGamePlayRun(ctx.Request().Context(), request.(PostGamePlayRunRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "PostGamePlayRun")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(PostGamePlayRunResponseObject); ok {
+		return validResponse.VisitPostGamePlayRunResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // GetGamePlaySubmissions operation middleware
 func (sh *strictHandler) GetGamePlaySubmissions(ctx echo.Context, gameID int) error {
 	var request GetGamePlaySubmissionsRequestObject
@@ -1084,6 +2249,32 @@ func (sh *strictHandler) GetGamePlaySubmissions(ctx echo.Context, gameID int) er
 	return nil
 }
 
+// GetGamePlaySubmission operation middleware
+func (sh *strictHandler) GetGamePlaySubmission(ctx echo.Context, gameID int, submissionID int) error {
+	var request GetGamePlaySubmissionRequestObject
+
+	request.GameID = gameID
+	request.SubmissionID = submissionID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGamePlaySubmission(ctx.Request().Context(), request.(GetGamePlaySubmissionRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGamePlaySubmission")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGamePlaySubmissionResponseObject); ok {
+		return validResponse.VisitGetGamePlaySubmissionResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // PostGamePlaySubmit operation middleware
 func (sh *strictHandler) PostGamePlaySubmit(ctx echo.Context, gameID int) error {
 	var request PostGamePlaySubmitRequestObject
@@ -1115,11 +2306,63 @@ func (sh *strictHandler) PostGamePlaySubmit(ctx echo.Context, gameID int) error
 	return nil
 }
 
+// GetGameSubmissionDiff operation middleware
+func (sh *strictHandler) GetGameSubmissionDiff(ctx echo.Context, gameID int, params GetGameSubmissionDiffParams) error {
+	var request GetGameSubmissionDiffRequestObject
+
+	request.GameID = gameID
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGameSubmissionDiff(ctx.Request().Context(), request.(GetGameSubmissionDiffRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGameSubmissionDiff")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGameSubmissionDiffResponseObject); ok {
+		return validResponse.VisitGetGameSubmissionDiffResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetGameWatchEvents operation middleware
+func (sh *strictHandler) GetGameWatchEvents(ctx echo.Context, gameID int) error {
+	var request GetGameWatchEventsRequestObject
+
+	request.GameID = gameID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGameWatchEvents(ctx.Request().Context(), request.(GetGameWatchEventsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGameWatchEvents")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGameWatchEventsResponseObject); ok {
+		return validResponse.VisitGetGameWatchEventsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // GetGameWatchLatestStates operation middleware
-func (sh *strictHandler) GetGameWatchLatestStates(ctx echo.Context, gameID int) error {
+func (sh *strictHandler) GetGameWatchLatestStates(ctx echo.Context, gameID int, params GetGameWatchLatestStatesParams) error {
 	var request GetGameWatchLatestStatesRequestObject
 
 	request.GameID = gameID
+	request.Params = params
 
 	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
 		return sh.ssi.GetGameWatchLatestStates(ctx.Request().Context(), request.(GetGameWatchLatestStatesRequestObject))
@@ -1141,10 +2384,11 @@ func (sh *strictHandler) GetGameWatchLatestStates(ctx echo.Context, gameID int)
 }
 
 // GetGameWatchRanking operation middleware
-func (sh *strictHandler) GetGameWatchRanking(ctx echo.Context, gameID int) error {
+func (sh *strictHandler) GetGameWatchRanking(ctx echo.Context, gameID int, params GetGameWatchRankingParams) error {
 	var request GetGameWatchRankingRequestObject
 
 	request.GameID = gameID
+	request.Params = params
 
 	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
 		return sh.ssi.GetGameWatchRanking(ctx.Request().Context(), request.(GetGameWatchRankingRequestObject))
@@ -1165,6 +2409,57 @@ func (sh *strictHandler) GetGameWatchRanking(ctx echo.Context, gameID int) error
 	return nil
 }
 
+// GetGameWatchReplay operation middleware
+func (sh *strictHandler) GetGameWatchReplay(ctx echo.Context, gameID int, params GetGameWatchReplayParams) error {
+	var request GetGameWatchReplayRequestObject
+
+	request.GameID = gameID
+	request.Params = params
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGameWatchReplay(ctx.Request().Context(), request.(GetGameWatchReplayRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGameWatchReplay")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGameWatchReplayResponseObject); ok {
+		return validResponse.VisitGetGameWatchReplayResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetGameWatchTeams operation middleware
+func (sh *strictHandler) GetGameWatchTeams(ctx echo.Context, gameID int) error {
+	var request GetGameWatchTeamsRequestObject
+
+	request.GameID = gameID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetGameWatchTeams(ctx.Request().Context(), request.(GetGameWatchTeamsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetGameWatchTeams")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetGameWatchTeamsResponseObject); ok {
+		return validResponse.VisitGetGameWatchTeamsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // PostLogin operation middleware
 func (sh *strictHandler) PostLogin(ctx echo.Context) error {
 	var request PostLoginRequestObject
@@ -1240,6 +2535,77 @@ func (sh *strictHandler) GetMe(ctx echo.Context) error {
 	return nil
 }
 
+// GetQualifyingStages operation middleware
+func (sh *strictHandler) GetQualifyingStages(ctx echo.Context) error {
+	var request GetQualifyingStagesRequestObject
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetQualifyingStages(ctx.Request().Context(), request.(GetQualifyingStagesRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetQualifyingStages")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetQualifyingStagesResponseObject); ok {
+		return validResponse.VisitGetQualifyingStagesResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetQualifyingStageRanking operation middleware
+func (sh *strictHandler) GetQualifyingStageRanking(ctx echo.Context, stageID int) error {
+	var request GetQualifyingStageRankingRequestObject
+
+	request.StageID = stageID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetQualifyingStageRanking(ctx.Request().Context(), request.(GetQualifyingStageRankingRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetQualifyingStageRanking")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetQualifyingStageRankingResponseObject); ok {
+		return validResponse.VisitGetQualifyingStageRankingResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
+// GetRatings operation middleware
+func (sh *strictHandler) GetRatings(ctx echo.Context) error {
+	var request GetRatingsRequestObject
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetRatings(ctx.Request().Context(), request.(GetRatingsRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetRatings")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetRatingsResponseObject); ok {
+		return validResponse.VisitGetRatingsResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // GetTournament operation middleware
 func (sh *strictHandler) GetTournament(ctx echo.Context, tournamentID int) error {
 	var request GetTournamentRequestObject
@@ -1265,33 +2631,81 @@ func (sh *strictHandler) GetTournament(ctx echo.Context, tournamentID int) error
 	return nil
 }
 
+// GetUserRatingHistory operation middleware
+func (sh *strictHandler) GetUserRatingHistory(ctx echo.Context, userID int) error {
+	var request GetUserRatingHistoryRequestObject
+
+	request.UserID = userID
+
+	handler := func(ctx echo.Context, request interface{}) (interface{}, error) {
+		return sh.ssi.GetUserRatingHistory(ctx.Request().Context(), request.(GetUserRatingHistoryRequestObject))
+	}
+	for _, middleware := range sh.middlewares {
+		handler = middleware(handler, "GetUserRatingHistory")
+	}
+
+	response, err := handler(ctx, request)
+
+	if err != nil {
+		return err
+	} else if validResponse, ok := response.(GetUserRatingHistoryResponseObject); ok {
+		return validResponse.VisitGetUserRatingHistoryResponse(ctx.Response())
+	} else if response != nil {
+		return fmt.Errorf("unexpected response type: %T", response)
+	}
+	return nil
+}
+
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xaTW/bOBP+KwLf96jaThPswbfsIigKpICxSbGHohAoaWKzlUgth4rrGv7vC5L6tChZ",
-	"TtJdOPHNlsjhfDzPzJDUlkQizQQHrpDMtwSjFaTU/LyRUkj9I5MiA6kYmMcpINIl6J9qkwGZE1SS8SXZ",
-	"7Xwi4e+cSYjJ/Es18KtfDhThN4gU2fnk5gdEuWKC3ymqciMXeJ7qaVxwID6ROedaqk8wjyJAJD5ZS8GX",
-	"AeW4Bkl8olgKIlfENzawBAIwKpvJ+mX1n3EFktOkeFBrVKrukw80ha6xMcMsoZuAF2870+JcUm1HgBAJ",
-	"HmNjkF50CVKPWtIUAhYPvLSPt+T/Eh7InPxvWodlWsRkqlW81+N2PmEYZHmYsKghMxQiAcr165QyHmjN",
-	"QRqVmIIUD8n/jFahQhyVkm70/0yKMIH00PRFMWznE1RUKogDqhwm++THu6V4Vz/97aqDndJhTe80jfbb",
-	"kXHEoVZ7zxsuOFaebeDw4vFCT80TxexUJ2xuqQJUer5GsgNBIaAKMBISAszDlKnaLzxPEhomQOZK5uCP",
-	"8pPGeuyGollkhFwbn4J1QwHdJ+l+kIwm5bp+r6XVci7PL2ps7TEPMJIs08s7rU0oX+ZFHhqByttyeI3n",
-	"XkIiTbMEgl5HK6aSEfmvsUw5x2+Z1TCiveiAo24bZpdIzVaZFrBmD8qJ0T8p/8748oYruek6ujSzBzW1",
-	"mIIFI5NIhUaHf/d48IT8UOhSg28Pcb1uvNPjEAtUuT3RsVy/CJD97LEnkkCPs+ZARXgiPQsvGOt6ZO+5",
-	"sT3eb6Tdgtu15ZVWLXtdLr4XudRZmStHNpQ0+g5qwJkHKy5wJQtpo6parY4lgKPApVRFqyeJ/KRnukTy",
-	"PA2kyHs7AlWJGBep9vhO+Wv5tbV67bDazuGo9eQJBOiBa45j08KeUWaibyUP62T93NFpkEUMg3AD7v7I",
-	"5o+LscmsGB4MJDU75P1xEt8PShTI9spf460J70FsmYj3emjNOAcZ6DAcjcJKcqlKQ+HK966gfi7QcmSj",
-	"zSLBg4yqlfstBjROGXdHO6EhJKNq3IAr7Mse/RzAtr6p5nQoW6lc6td1lhbL+IMwC9qmg1wnIVVSIHrl",
-	"psZbQ+hdLz4SnzyCtKWNzCaXk5lWWmTAacbInFxOZpOZDhNVK+PzqaaP5RGYXK0DYrrojzGZkw9g+lqd",
-	"OCRgJjjawe9nM1stuSpyPM2yhEVm5vQbWsRawLspOz7Tml1ZJ706NgvY475WG0nuV+DpmYDKW1H0zNYS",
-	"YognepGr2cVRhg0WZ7PRdKhwbTazHkMv5zRXKyHZz2r9y39z/QchQxbHwCd63M4v8DDdFll1dwgZBkuS",
-	"pqDMLvPLlmgCGnzpGmSY0mgp6pBZ8tWGdLLO1xeH3DigOYB1xtVzcKUXv/r1i2v/I8hHkF5EORfKe2A8",
-	"9lQdFog9CShyGUEf3Kc6O0/LTUAm0IH8hbCb/UVCN3/YFvlXUsCo/ruIN89Af8+uxrWXd0O9rfTOTc0z",
-	"H14pHxJzwBVgebo1VBA0K+yBmD0MO5H6UNk25Ov9g77OVto8PReLN0WO+vwEx3DjrjH8VLjRtnBU0944",
-	"YzvUujfFn7nz9rijxrVad3bsudk6N1uvlRNrqqJVq9s6WFL+0lMa/RaeVMNlftE4NkeGNFm0RhzViXWJ",
-	"5GjNzvXlrXFJ2mvPUSwqrkhPhUAN00Z1ZK0b4EM9WSn8zJfXypdELO1VSX/ndWuGvFRblFHEtZCx8+7m",
-	"uGsVXh48FxKf0UY90ZjnXXeeOqsqBIlcHYSQ/TrvtFtYa3A6ePj1CcgZYi/s8fquGafb1ucPgxdTja9P",
-	"xhTz/e8q/rOSrlpfzYz7+mTgfv5cvV9n9d7t/gkAAP//fXodcbItAAA=",
+	"H4sIAAAAAAAC/+xcS3PbOBL+KyzsHhnLmUntwbdMNjU7VUnFG3lqD1NTHIhsSZiQAAOAkRWX/vsWHnwK",
+	"JEFLjhObpygmHo3urx/oBnCHYpbljAKVAl3dIRFvIcP65xuWwJLiXGyZVP/POcuBSwL6a8wSUP/KfQ7o",
+	"CgnJCd2gQ4hiDlhCEmHZ+EyohA1wFKLbFxv2ov7rv16pPkREolhlRAjCaKPbirEUMFVNCgE8IoljzMMh",
+	"RBw+F4RDgq7+qFqGhsbu6C0K/wzL0djqb4ilmunfZL1+Rygcr5nCLkrtly4RIWJpMvQ1V3//J4c1ukL/",
+	"WNRcX1iWL9S8H3LVVsKtdPC2s06WI9u0bxUf9JxAi0y1h88FThU7qAAuUYgSSEFCo3ctxLecM37MgAyE",
+	"wBsYp61s6KLs7S3EhSSMLiWWhWiSSBkFFCJeUKpGDZEo4hiEQCHacUY3EaZip2EkSQaskFrIWU5SiECT",
+	"rDurj9X/lQw4xan9g2u1v+LMIW2cpmwX5RzHksTgRmVCRJ7ifURx1mxRD50UHKu1RgJiRhPhBscGZ9AD",
+	"bvvR/HkYQGoZN6qd0ai8WKUkdtOdYUIjRTlwTRKRkImx8X8XhiA7HOYc79X/c1yIiQqfc7ZKIfOf+9p0",
+	"cE0vJOZTDY6QWHrxc6kbduFdyqspnCbPO8BwwKAkIezCrMGbjphcuqRIfPsFqF56AiLmJJfahqIlUBlg",
+	"EcgtBAmWOGDrAAcC+BfgL4T6CKpjsNsyAfa3ojYgpo9Qv7EI/lKT/nWBwo56rEDISMSMg7GucqoMej1I",
+	"iummwJtR+VhMvCub17jq1SVNb8+nyhoNzdk1Xtpa4/4JffVWC7FUXn9fZ5FXu7ySmBYrBpFzYyksbbB1",
+	"mpYdYUPOFu69JnRZKlU51A4Tae14vIWkSCFpWXdjOFCI1oQSsdU/Y0xjSFXLvmm6BL/88lKpSpFKYlTF",
+	"2fMdliBki0xvPNMiTfEqBXQleQHhI+K7wu8ISffHcwdgJRqs/PuYVE3ngtp7FXnRzVJ1u2YpifdN8eVA",
+	"cSr3KERwG6dF4sZXaf+PxNYyeudldtl1spcqB/lgyHK5zDE7hbM8hagXQkoE6ucIPUvTbCk5lrDRM0si",
+	"U48ArkFg2Sds8brB2Ta5NXFNDrpw0WV5ExTbXA20I2s5BIcOo4/AcYqmDUqgw60eVgwselkqcptgb/81",
+	"pv9D4jSDuIj7b4FTst4TunlLJd8f08cx/eSmjIMoUumvKvVUH3VPZ2R3r7hCMolT1XzNeKY6ooQVKw1g",
+	"25QW2eqo/zpl2I6g3KlfMNxhs2aP7V+zpCSps6BhAViuHElgcKswFN7cg5e9UW/pDiasZymtHnbM99gG",
+	"Sk16H1Tp+fTGzgGszHikqPQ9To5VjSqXNTSzw8kZJ7wBv1Cuanq0eegQckx+ySUPCbi3ul5S6EXdDshm",
+	"K0/QuF6cdRhhJ3It8yOmn/rNVmnFe6xmvc7STmqAT/b7xqg78NZvNsc0VuesopgVVJ5Pr802YWxRN6pN",
+	"j4XT/Ws70GGbg/YOoTY6dktS7RnebDF12QuNjdPgqj8OOLLJ2UuuKY7wWhq34RxTtVjBukfaw/mFjhrU",
+	"C+iO3KGlsRgXp7vRYSP+Wu0lCCulnBGqvZjib55DEpVf820eSfYJqHAGactWStc3fcwSiAT52qMU98ku",
+	"D6KBiDr34kyVPWQ24r4ph4Z6+fmWVvuwga9WYNgIY20oX0ujkRFosmw0m35jLc1Eh5OBchin5yYHkjMd",
+	"HtWZk677taS4VydkjAX0RWtwm0OsuCNkwgrpXKoqT+hNgxt/ORYCEve3+wNIJsC5kxwhE0L7vvStQVo+",
+	"+LK6bt1cf7VYJ6sJ/MIBf2qVNDBPiU5JNOs7a9i1/6aN1xZzSIzZdBmrG1ZwJXDqkOKK4/gTyAGzNIpn",
+	"oJKTCRFFTY4JaVxBLJbx9l5Dvlc9XUPSIos4K3rrFLIawlPQrfZHmtXia2v2mmH1Op2g6LDpSHQCoMf4",
+	"3nuTZ3d3euRhmgyfp23hiIhW+z5ToNObL31toW0eDcSYpslP00b8aXBEJkgnGdf4qsU7ii0t8f7tBqEU",
+	"eDQhSe4YuSSlQXDFe5dQf7domejGSMxolGO57bP8OMkI7Qs8VpB67VpM0DcYepbx7njeeICv5mPPYnur",
+	"8FWfI/2v1l8utlpKm+5jgajZCF0zTYfJaKLX6QpLzoQIypJvsINV8Pr6NxSiL8BNIIouL36+uDQVeaA4",
+	"J+gK/XxxeXGpvY/carkuqqzDBrQ/UELXBbzfEnSFfgVdSBAmx5MzKkzjny4vTWxLpfUjOM9TEuuei7+F",
+	"0QqjVG6z4G/N3akNxz5C9LCvXS282UKgeoKQwRaLQBfeIYHkQk3y6vLlpIUNRiO6DO8g4bUu9avyY0Fx",
+	"IbeMk6/V/D9/y/nXjK9IkgC9UO0OocXD4s5a7sMYMjSWOM5A6hj2jzuklFzjC4XIKFAjCK9FZpSyXsiR",
+	"Zfvz7JDzA5oDWDOuTsGVmvzVw0+u+G9q/kGMKWUyWBOaBLIWCyQBB8EKHkMf3BfKaC/KLXvOhAP518xU",
+	"V69TvH/DkodWAU36LyzZn4D+b38AYagq05MQOxy6zDm4TcA0vbv8ttBjRZoECn0FTYALidsYDJICAskC",
+	"Qr/glCSB2FOJb2cT8aOZCH2KaDRuUibirWn5iH5SnZw0BL8Qktss1dGIVXQ7e7anC9tUnw2KqkOBY+A1",
+	"Z4nMOaIHQ3Cos4ep9lFrnAoIzdCfC+D7euyWD3m0QNLrPGX3CJajAirnqPKZ6R4vqF9Q+bGgc0zZU0Zy",
+	"Z+xHo82y6wlR5wn24gEKF+7yhMPMFAJVHapBz2F55rh6NpLnN5LNUpZHfLJsVb5+iExUZ4VeKdB6maOJ",
+	"0Obwc4DxXHVncdc6CHGYpksPGuo7huoe2vgOdHOiRpZl/qlngzunKvyVGzkmndX9+am79NtSLE3beVcx",
+	"Z6rniPrZmIlmQJCQ9XosCqidmrpT/+gJvzVn2YMMLNljBhkpoRNOP1RvNLhuDZGvECWQStxzhoWSNYHE",
+	"YU+7R1hsw9AS1xp6Diuei73YqTNjnqWt/6m2c21rBu73A9xmccsPv43ylpjrW3V9S//CSaKPi+L0utVi",
+	"UuHr2Hc4KmHz1vW5qSo3lxi9lNReeHx0/YwLLhhH4YB78RwpJWYzfg6bUd1OOhqsOmDtTVbjbr8XVI72",
+	"2me2RUREa86+Qs+xcQq3MrJS8Ts8XoHOK/Ru3bR13bgiEK3KizqDWb7yQo/rjqmBdr3U5rjtNc5Vu9me",
+	"f6/2HFRK0s+cm6aPbc3rWxtPNpKzL1r65xpa72CO1iKq4efg7XkpuwSc+e2vbnTLH6QwXy3Ls3KHs1Ed",
+	"MUPO+vFU9SNlGzJyqO+dbnKu8liOhdgxnjhLZNOuLNLy9pYd8dsfjTvtXvKPrlUVguxRvkEIMX167/QS",
+	"4+MvOBs8d/4e0AyxM3P8c/UuVqRf3hp03Z1HtM588bee/z5vnI2HpLLnBcbZ3068BXyEmcVd+WrbwSd5",
+	"15HclBRe43W4RwsFp2aKuq9Jut/u3oD/OBbwLoCjOpM1I/2pRpbmUYhBU/3RNjm7wzz1LSqHK52N8hmM",
+	"cv26i1jctR4cGjzf23jvycf+dl8yerz9eOudKr/3ngZexJkR+GSNpbYwizub0j1Y4xltiZCMD2allQEz",
+	"dvQ/trGPitwrd3xe5WiszbOW1Xhr1BGenPpGWEnPrGRPUskOh/8PAOkz6C4/awAA",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -11,11 +11,15 @@ import (
 
 	"github.com/jackc/pgx/v5/pgtype"
 	"github.com/labstack/echo/v4"
+	"github.com/oapi-codegen/nullable"
 
 	"albatross-2026-backend/auth"
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/ranking"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/session"
 	"albatross-2026-backend/tournament"
 )
@@ -27,6 +31,8 @@ type AuthenticatorInterface interface {
 type Handler struct {
 	gameSvc       *game.Service
 	tournamentSvc *tournament.Service
+	ratingSvc     *rating.Service
+	qualifyingSvc *qualifying.Service
 	auth          AuthenticatorInterface
 	conf          *config.Config
 	q             db.Querier // for session management (login/logout)
@@ -98,28 +104,14 @@ func (h *Handler) PostLogin(ctx context.Context, request PostLoginRequestObject)
 			SameSite: http.SameSiteLaxMode,
 		},
 		body: PostLogin200JSONResponse{
-			User: User{
-				UserID:      int(dbUser.UserID),
-				Username:    dbUser.Username,
-				DisplayName: dbUser.DisplayName,
-				IconPath:    dbUser.IconPath,
-				IsAdmin:     dbUser.IsAdmin,
-				Label:       toNullable(dbUser.Label),
-			},
+			User: toAPIUser(game.PlayerFromUser(dbUser)),
 		},
 	}, nil
 }
 
 func (h *Handler) GetMe(_ context.Context, _ GetMeRequestObject, user *db.User) (GetMeResponseObject, error) {
 	return GetMe200JSONResponse{
-		User: User{
-			UserID:      int(user.UserID),
-			Username:    user.Username,
-			DisplayName: user.DisplayName,
-			IconPath:    user.IconPath,
-			IsAdmin:     user.IsAdmin,
-			Label:       toNullable(user.Label),
-		},
+		User: toAPIUser(game.PlayerFromUser(*user)),
 	}, nil
 }
 
@@ -175,7 +167,7 @@ func (h *Handler) GetGame(ctx context.Context, request GetGameRequestObject, use
 }
 
 func (h *Handler) GetGamePlayLatestState(ctx context.Context, request GetGamePlayLatestStateRequestObject, user *db.User) (GetGamePlayLatestStateResponseObject, error) {
-	state, err := h.gameSvc.GetLatestState(ctx, request.GameID, user.UserID)
+	state, err := h.gameSvc.GetLatestState(ctx, request.GameID, user.UserID, request.Params.ProblemID)
 	if err != nil {
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
@@ -189,7 +181,7 @@ func (h *Handler) GetGameWatchLatestStates(ctx context.Context, request GetGameW
 		userID = &user.UserID
 		isAdmin = user.IsAdmin
 	}
-	stateMap, err := h.gameSvc.GetWatchLatestStates(ctx, request.GameID, userID, isAdmin)
+	stateMap, err := h.gameSvc.GetWatchLatestStates(ctx, request.GameID, request.Params.ProblemID, userID, isAdmin)
 	if err != nil {
 		if errors.Is(err, game.ErrForbidden) {
 			return GetGameWatchLatestStates403JSONResponse{
@@ -205,19 +197,129 @@ func (h *Handler) GetGameWatchLatestStates(ctx context.Context, request GetGameW
 	return GetGameWatchLatestStates200JSONResponse{States: states}, nil
 }
 
-func (h *Handler) GetGameWatchRanking(ctx context.Context, request GetGameWatchRankingRequestObject, _ *db.User) (GetGameWatchRankingResponseObject, error) {
-	ranking, _, err := h.gameSvc.GetRanking(ctx, request.GameID)
+func (h *Handler) GetGameWatchEvents(ctx context.Context, request GetGameWatchEventsRequestObject, user *db.User) (GetGameWatchEventsResponseObject, error) {
+	var userID *int32
+	var isAdmin bool
+	if user != nil {
+		userID = &user.UserID
+		isAdmin = user.IsAdmin
+	}
+	events, unsubscribe, err := h.gameSvc.SubscribeWatchEvents(ctx, request.GameID, userID, isAdmin)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGameWatchEvents404JSONResponse{Message: "Game not found"}, nil
+		}
+		if errors.Is(err, game.ErrForbidden) {
+			return GetGameWatchEvents403JSONResponse{
+				Message: "You are one of the main players of this game",
+			}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return eventStreamResponse{
+		ctx:         ctx,
+		events:      events,
+		unsubscribe: unsubscribe,
+	}, nil
+}
+
+func (h *Handler) GetGameWatchRanking(ctx context.Context, request GetGameWatchRankingRequestObject, user *db.User) (GetGameWatchRankingResponseObject, error) {
+	isAdmin := user != nil && user.IsAdmin
+	var cursor string
+	if request.Params.Cursor != nil {
+		cursor = *request.Params.Cursor
+	}
+	var limit int
+	if request.Params.Limit != nil {
+		limit = *request.Params.Limit
+	}
+	var page game.Ranking
+	var err error
+	language := fromAPILanguage(request.Params.Language)
+	if request.Params.Practice != nil && *request.Params.Practice {
+		page, err = h.gameSvc.GetPracticeRanking(ctx, request.GameID, language, cursor, limit)
+	} else {
+		page, err = h.gameSvc.GetRanking(ctx, request.GameID, isAdmin, language, cursor, limit)
+	}
 	if err != nil {
 		if errors.Is(err, game.ErrNotFound) {
 			return GetGameWatchRanking404JSONResponse{Message: "Game not found"}, nil
 		}
+		if errors.Is(err, ranking.ErrInvalidCursor) {
+			return GetGameWatchRanking400JSONResponse{Message: "Invalid cursor"}, nil
+		}
 		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
 	}
-	apiRanking := make([]RankingEntry, len(ranking))
-	for i, r := range ranking {
+	apiRanking := make([]RankingEntry, len(page.Entries))
+	for i, r := range page.Entries {
 		apiRanking[i] = toAPIRankingEntry(r)
 	}
-	return GetGameWatchRanking200JSONResponse{Ranking: apiRanking}, nil
+	nextCursor := nullable.NewNullNullable[string]()
+	if page.NextCursor != "" {
+		nextCursor = nullable.NewNullableWithValue(page.NextCursor)
+	}
+	return GetGameWatchRanking200JSONResponse{
+		Ranking:    apiRanking,
+		IsFrozen:   page.Frozen,
+		TieBreak:   TieBreak(page.TieBreak),
+		NextCursor: nextCursor,
+	}, nil
+}
+
+func (h *Handler) GetGameWatchReplay(ctx context.Context, request GetGameWatchReplayRequestObject, user *db.User) (GetGameWatchReplayResponseObject, error) {
+	var userID *int32
+	var isAdmin bool
+	if user != nil {
+		userID = &user.UserID
+		isAdmin = user.IsAdmin
+	}
+	snapshots, err := h.gameSvc.GetReplay(ctx, request.GameID, request.Params.ProblemID, int32(request.Params.UserID), userID, isAdmin)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGameWatchReplay404JSONResponse{Message: "Game not found"}, nil
+		}
+		if errors.Is(err, game.ErrForbidden) {
+			return GetGameWatchReplay403JSONResponse{
+				Message: "The replay is not available until the game finishes",
+			}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiSnapshots := make([]CodeSnapshot, len(snapshots))
+	for i, s := range snapshots {
+		apiSnapshots[i] = toAPICodeSnapshot(s)
+	}
+	return GetGameWatchReplay200JSONResponse{Snapshots: apiSnapshots}, nil
+}
+
+func (h *Handler) GetGameWatchTeams(ctx context.Context, request GetGameWatchTeamsRequestObject, _ *db.User) (GetGameWatchTeamsResponseObject, error) {
+	teams, err := h.gameSvc.ListTeams(ctx, request.GameID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGameWatchTeams404JSONResponse{Message: "Game not found"}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiTeams := make([]Team, len(teams))
+	for i, t := range teams {
+		apiTeams[i] = toAPITeam(t)
+	}
+	return GetGameWatchTeams200JSONResponse{Teams: apiTeams}, nil
+}
+
+func (h *Handler) GetGamePlayEvents(ctx context.Context, request GetGamePlayEventsRequestObject, user *db.User) (GetGamePlayEventsResponseObject, error) {
+	events, unsubscribe, err := h.gameSvc.SubscribePlayEvents(ctx, request.GameID, user.UserID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGamePlayEvents404JSONResponse{Message: "Game not found"}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return eventStreamResponse{
+		ctx:         ctx,
+		events:      events,
+		unsubscribe: unsubscribe,
+	}, nil
 }
 
 func (h *Handler) GetGamePlaySubmissions(ctx context.Context, request GetGamePlaySubmissionsRequestObject, user *db.User) (GetGamePlaySubmissionsResponseObject, error) {
@@ -235,11 +337,32 @@ func (h *Handler) GetGamePlaySubmissions(ctx context.Context, request GetGamePla
 	return GetGamePlaySubmissions200JSONResponse{Submissions: apiSubmissions}, nil
 }
 
+func (h *Handler) GetGamePlaySubmission(ctx context.Context, request GetGamePlaySubmissionRequestObject, user *db.User) (GetGamePlaySubmissionResponseObject, error) {
+	submission, verdicts, err := h.gameSvc.GetSubmission(ctx, request.GameID, user.UserID, request.SubmissionID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGamePlaySubmission404JSONResponse{Message: "Submission not found"}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	testcaseResults := make([]TestcaseResult, len(verdicts))
+	for i, v := range verdicts {
+		testcaseResults[i] = toAPITestcaseResult(v)
+	}
+	return GetGamePlaySubmission200JSONResponse{
+		Submission:      toAPISubmission(submission),
+		TestcaseResults: testcaseResults,
+	}, nil
+}
+
 func (h *Handler) PostGamePlayCode(ctx context.Context, request PostGamePlayCodeRequestObject, user *db.User) (PostGamePlayCodeResponseObject, error) {
-	err := h.gameSvc.SaveCode(ctx, request.GameID, user.UserID, request.Body.Code)
+	err := h.gameSvc.SaveCode(ctx, request.GameID, user.UserID, request.Body.ProblemID, fromAPILanguage(request.Body.Language), request.Body.Code)
 	if err != nil {
 		if errors.Is(err, game.ErrNotFound) {
-			return PostGamePlayCode404JSONResponse{Message: "Game not found"}, nil
+			return PostGamePlayCode404JSONResponse{Message: "Game or problem not found"}, nil
+		}
+		if errors.Is(err, game.ErrLanguageNotAllowed) {
+			return PostGamePlayCode400JSONResponse{Message: "Language is not allowed for the problem"}, nil
 		}
 		if errors.Is(err, game.ErrGameNotRunning) {
 			return PostGamePlayCode403JSONResponse{Message: "Game is not running"}, nil
@@ -250,11 +373,14 @@ func (h *Handler) PostGamePlayCode(ctx context.Context, request PostGamePlayCode
 }
 
 func (h *Handler) PostGamePlaySubmit(ctx context.Context, request PostGamePlaySubmitRequestObject, user *db.User) (PostGamePlaySubmitResponseObject, error) {
-	err := h.gameSvc.SubmitCode(ctx, request.GameID, user.UserID, request.Body.Code)
+	err := h.gameSvc.SubmitCode(ctx, request.GameID, user.UserID, request.Body.ProblemID, fromAPILanguage(request.Body.Language), request.Body.Code)
 	if err != nil {
 		if errors.Is(err, game.ErrNotFound) {
 			return PostGamePlaySubmit404JSONResponse{}, nil
 		}
+		if errors.Is(err, game.ErrLanguageNotAllowed) {
+			return PostGamePlaySubmit400JSONResponse{Message: "Language is not allowed for the problem"}, nil
+		}
 		if errors.Is(err, game.ErrGameNotRunning) {
 			return PostGamePlaySubmit403JSONResponse{Message: "Game is not running"}, nil
 		}
@@ -263,6 +389,60 @@ func (h *Handler) PostGamePlaySubmit(ctx context.Context, request PostGamePlaySu
 	return PostGamePlaySubmit200Response{}, nil
 }
 
+func (h *Handler) PostGamePlayRun(ctx context.Context, request PostGamePlayRunRequestObject, user *db.User) (PostGamePlayRunResponseObject, error) {
+	result, err := h.gameSvc.RunCode(ctx, request.GameID, user.UserID, request.Body.ProblemID, fromAPILanguage(request.Body.Language), request.Body.Code, request.Body.Stdin)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return PostGamePlayRun404JSONResponse{Message: "Game or problem not found"}, nil
+		}
+		if errors.Is(err, game.ErrLanguageNotAllowed) {
+			return PostGamePlayRun400JSONResponse{Message: "Language is not allowed for the problem"}, nil
+		}
+		if errors.Is(err, game.ErrGameNotRunning) {
+			return PostGamePlayRun403JSONResponse{Message: "Game is not running"}, nil
+		}
+		if errors.Is(err, game.ErrRunTimedOut) {
+			return nil, echo.NewHTTPError(http.StatusGatewayTimeout, err.Error())
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	return PostGamePlayRun200JSONResponse{
+		Status: ExecutionStatus(result.Status),
+		Stdout: result.Stdout,
+		Stderr: result.Stderr,
+	}, nil
+}
+
+func (h *Handler) GetGameSubmissionDiff(ctx context.Context, request GetGameSubmissionDiffRequestObject, user *db.User) (GetGameSubmissionDiffResponseObject, error) {
+	var userID *int32
+	var isAdmin bool
+	if user != nil {
+		userID = &user.UserID
+		isAdmin = user.IsAdmin
+	}
+	diff, err := h.gameSvc.DiffSubmissions(ctx, request.GameID, request.Params.From, request.Params.To, userID, isAdmin)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetGameSubmissionDiff404JSONResponse{Message: "Submission not found"}, nil
+		}
+		if errors.Is(err, game.ErrForbidden) {
+			return GetGameSubmissionDiff403JSONResponse{
+				Message: "Other submissions are not available until the game finishes",
+			}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	lines := make([]DiffLine, len(diff.Lines))
+	for i, l := range diff.Lines {
+		lines[i] = toAPIDiffLine(l)
+	}
+	return GetGameSubmissionDiff200JSONResponse{
+		Unified:   diff.Unified,
+		Lines:     lines,
+		SizeDelta: diff.SizeDelta,
+	}, nil
+}
+
 func (h *Handler) GetTournament(ctx context.Context, request GetTournamentRequestObject, _ *db.User) (GetTournamentResponseObject, error) {
 	t, err := h.tournamentSvc.GetTournament(ctx, request.TournamentID)
 	if err != nil {
@@ -273,3 +453,65 @@ func (h *Handler) GetTournament(ctx context.Context, request GetTournamentReques
 	}
 	return GetTournament200JSONResponse{Tournament: toAPITournament(t)}, nil
 }
+
+func (h *Handler) GetQualifyingStages(ctx context.Context, _ GetQualifyingStagesRequestObject, user *db.User) (GetQualifyingStagesResponseObject, error) {
+	isAdmin := user != nil && user.IsAdmin
+	stages, err := h.qualifyingSvc.ListStages(ctx, isAdmin)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	apiStages := make([]QualifyingStage, len(stages))
+	for i, s := range stages {
+		apiStages[i] = toAPIQualifyingStage(s)
+	}
+	return GetQualifyingStages200JSONResponse{Stages: apiStages}, nil
+}
+
+func (h *Handler) GetQualifyingStageRanking(ctx context.Context, request GetQualifyingStageRankingRequestObject, user *db.User) (GetQualifyingStageRankingResponseObject, error) {
+	isAdmin := user != nil && user.IsAdmin
+	stage, entries, err := h.qualifyingSvc.GetRanking(ctx, request.StageID, isAdmin)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetQualifyingStageRanking404JSONResponse{Message: "Qualifying stage not found"}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	ranking := make([]QualifyingEntry, len(entries))
+	for i, e := range entries {
+		ranking[i] = toAPIQualifyingEntry(stage, e)
+	}
+	return GetQualifyingStageRanking200JSONResponse{
+		Stage:   toAPIQualifyingStage(stage),
+		Ranking: ranking,
+	}, nil
+}
+
+func (h *Handler) GetRatings(ctx context.Context, _ GetRatingsRequestObject, _ *db.User) (GetRatingsResponseObject, error) {
+	players, err := h.ratingSvc.Leaderboard(ctx)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	users := make([]User, len(players))
+	for i, p := range players {
+		users[i] = toAPIUser(p)
+	}
+	return GetRatings200JSONResponse{Users: users}, nil
+}
+
+func (h *Handler) GetUserRatingHistory(ctx context.Context, request GetUserRatingHistoryRequestObject, _ *db.User) (GetUserRatingHistoryResponseObject, error) {
+	player, changes, err := h.ratingSvc.History(ctx, request.UserID)
+	if err != nil {
+		if errors.Is(err, game.ErrNotFound) {
+			return GetUserRatingHistory404JSONResponse{Message: "User not found"}, nil
+		}
+		return nil, echo.NewHTTPError(http.StatusInternalServerError, err.Error())
+	}
+	history := make([]RatingChange, len(changes))
+	for i, c := range changes {
+		history[i] = toAPIRatingChange(c)
+	}
+	return GetUserRatingHistory200JSONResponse{
+		User:    toAPIUser(player),
+		History: history,
+	}, nil
+}
@@ -3,6 +3,8 @@ package api
 import (
 	"context"
 	"errors"
+	"net/http/httptest"
+	"slices"
 	"testing"
 	"time"
 
@@ -12,6 +14,9 @@ import (
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/ranking"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/session"
 	"albatross-2026-backend/tournament"
 )
@@ -19,26 +24,43 @@ import (
 // mockQuerier implements db.Querier for testing.
 type mockQuerier struct {
 	db.Querier
-	getGameByIDFunc                     func(ctx context.Context, gameID int32) (db.GetGameByIDRow, error)
+	getGameByIDFunc                     func(ctx context.Context, gameID int32) (db.Game, error)
 	listMainPlayersFunc                 func(ctx context.Context, gameIDs []int32) ([]db.ListMainPlayersRow, error)
-	listPublicGamesFunc                 func(ctx context.Context) ([]db.ListPublicGamesRow, error)
+	listPublicGamesFunc                 func(ctx context.Context) ([]db.Game, error)
+	listGameProblemsFunc                func(ctx context.Context, gameIDs []int32) ([]db.ListGameProblemsRow, error)
+	listProblemLanguagesFunc            func(ctx context.Context, problemIDs []int32) ([]db.ProblemLanguage, error)
 	deleteSessionFunc                   func(ctx context.Context, sessionID string) error
 	getLatestStateFunc                  func(ctx context.Context, arg db.GetLatestStateParams) (db.GetLatestStateRow, error)
 	updateCodeFunc                      func(ctx context.Context, arg db.UpdateCodeParams) error
 	getRankingFunc                      func(ctx context.Context, gameID int32) ([]db.GetRankingRow, error)
-	getLatestStatesFunc                 func(ctx context.Context, gameID int32) ([]db.GetLatestStatesOfMainPlayersRow, error)
+	getLatestStatesFunc                 func(ctx context.Context, arg db.GetLatestStatesOfMainPlayersParams) ([]db.GetLatestStatesOfMainPlayersRow, error)
 	getTournamentByIDFunc               func(ctx context.Context, tournamentID int32) (db.Tournament, error)
 	listTournamentEntriesFunc           func(ctx context.Context, tournamentID int32) ([]db.ListTournamentEntriesRow, error)
 	listTournamentMatchesFunc           func(ctx context.Context, tournamentID int32) ([]db.TournamentMatch, error)
-	getSubmissionsByGameIDAndUserIDFunc func(ctx context.Context, arg db.GetSubmissionsByGameIDAndUserIDParams) ([]db.Submission, error)
+	getSubmissionsByGameIDAndTeamIDFunc func(ctx context.Context, arg db.GetSubmissionsByGameIDAndTeamIDParams) ([]db.Submission, error)
+	getTeamByUserIDFunc                 func(ctx context.Context, arg db.GetTeamByUserIDParams) (db.GameTeam, error)
+	listTeamsFunc                       func(ctx context.Context, gameID int32) ([]db.GameTeam, error)
+	listTeamMembersFunc                 func(ctx context.Context, gameID int32) ([]db.ListTeamMembersRow, error)
+	getCodeForSnapshotFunc              func(ctx context.Context, arg db.GetCodeForSnapshotParams) (db.GetCodeForSnapshotRow, error)
+	createCodeSnapshotFunc              func(ctx context.Context, arg db.CreateCodeSnapshotParams) error
+	listCodeSnapshotsFunc               func(ctx context.Context, arg db.ListCodeSnapshotsParams) ([]db.CodeSnapshot, error)
 	getUserByIDFunc                     func(ctx context.Context, userID int32) (db.User, error)
+	getSubmissionByIDFunc               func(ctx context.Context, submissionID int32) (db.Submission, error)
+	listTestcaseResultsWithTestcaseFunc func(ctx context.Context, submissionID int32) ([]db.ListTestcaseResultsWithTestcaseBySubmissionIDRow, error)
+	listBestSubmissionsAtFunc           func(ctx context.Context, arg db.ListBestSubmissionsAtParams) ([]db.ListBestSubmissionsAtRow, error)
+	listBestPracticeSubmissionsFunc     func(ctx context.Context, arg db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error)
+	createSubmissionFunc                func(ctx context.Context, arg db.CreateSubmissionParams) (int32, error)
+	listRatedUsersFunc                  func(ctx context.Context) ([]db.User, error)
+	listRatingHistoryByUserIDFunc       func(ctx context.Context, userID int32) ([]db.ListRatingHistoryByUserIDRow, error)
+	getQualifyingStageByIDFunc          func(ctx context.Context, stageID int32) (db.QualifyingStage, error)
+	listQualifyingStageGamesFunc        func(ctx context.Context, stageID int32) ([]db.ListQualifyingStageGamesRow, error)
 }
 
-func (m *mockQuerier) GetGameByID(ctx context.Context, gameID int32) (db.GetGameByIDRow, error) {
+func (m *mockQuerier) GetGameByID(ctx context.Context, gameID int32) (db.Game, error) {
 	if m.getGameByIDFunc != nil {
 		return m.getGameByIDFunc(ctx, gameID)
 	}
-	return db.GetGameByIDRow{}, pgx.ErrNoRows
+	return db.Game{}, pgx.ErrNoRows
 }
 
 func (m *mockQuerier) ListMainPlayers(ctx context.Context, gameIDs []int32) ([]db.ListMainPlayersRow, error) {
@@ -48,13 +70,48 @@ func (m *mockQuerier) ListMainPlayers(ctx context.Context, gameIDs []int32) ([]d
 	return nil, nil
 }
 
-func (m *mockQuerier) ListPublicGames(ctx context.Context) ([]db.ListPublicGamesRow, error) {
+func (m *mockQuerier) ListPublicGames(ctx context.Context) ([]db.Game, error) {
 	if m.listPublicGamesFunc != nil {
 		return m.listPublicGamesFunc(ctx)
 	}
 	return nil, nil
 }
 
+// testProblem is the only problem of the games unless ListGameProblems is
+// overridden.
+var testProblem = db.Problem{ProblemID: 10, Title: "Test Problem", Language: "php"}
+
+func (m *mockQuerier) ListGameProblems(ctx context.Context, gameIDs []int32) ([]db.ListGameProblemsRow, error) {
+	if m.listGameProblemsFunc != nil {
+		return m.listGameProblemsFunc(ctx, gameIDs)
+	}
+	rows := make([]db.ListGameProblemsRow, len(gameIDs))
+	for i, gameID := range gameIDs {
+		rows[i] = db.ListGameProblemsRow{GameID: gameID, Problem: testProblem}
+	}
+	return rows, nil
+}
+
+func (m *mockQuerier) ListProblemLanguages(ctx context.Context, problemIDs []int32) ([]db.ProblemLanguage, error) {
+	if m.listProblemLanguagesFunc != nil {
+		return m.listProblemLanguagesFunc(ctx, problemIDs)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) GetGameProblem(ctx context.Context, arg db.GetGameProblemParams) (db.Problem, error) {
+	rows, err := m.ListGameProblems(ctx, []int32{arg.GameID})
+	if err != nil {
+		return db.Problem{}, err
+	}
+	for _, row := range rows {
+		if row.Problem.ProblemID == arg.ProblemID {
+			return row.Problem, nil
+		}
+	}
+	return db.Problem{}, pgx.ErrNoRows
+}
+
 func (m *mockQuerier) DeleteSession(ctx context.Context, sessionID string) error {
 	if m.deleteSessionFunc != nil {
 		return m.deleteSessionFunc(ctx, sessionID)
@@ -83,16 +140,81 @@ func (m *mockQuerier) GetRanking(ctx context.Context, gameID int32) ([]db.GetRan
 	return nil, nil
 }
 
-func (m *mockQuerier) GetLatestStatesOfMainPlayers(ctx context.Context, gameID int32) ([]db.GetLatestStatesOfMainPlayersRow, error) {
+func (m *mockQuerier) ListBestSubmissionsAt(ctx context.Context, arg db.ListBestSubmissionsAtParams) ([]db.ListBestSubmissionsAtRow, error) {
+	if m.listBestSubmissionsAtFunc != nil {
+		return m.listBestSubmissionsAtFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListBestPracticeSubmissions(ctx context.Context, arg db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error) {
+	if m.listBestPracticeSubmissionsFunc != nil {
+		return m.listBestPracticeSubmissionsFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) CreateSubmission(ctx context.Context, arg db.CreateSubmissionParams) (int32, error) {
+	if m.createSubmissionFunc != nil {
+		return m.createSubmissionFunc(ctx, arg)
+	}
+	return 1, nil
+}
+
+func (m *mockQuerier) GetLatestStatesOfMainPlayers(ctx context.Context, arg db.GetLatestStatesOfMainPlayersParams) ([]db.GetLatestStatesOfMainPlayersRow, error) {
 	if m.getLatestStatesFunc != nil {
-		return m.getLatestStatesFunc(ctx, gameID)
+		return m.getLatestStatesFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) GetSubmissionsByGameIDAndTeamID(ctx context.Context, arg db.GetSubmissionsByGameIDAndTeamIDParams) ([]db.Submission, error) {
+	if m.getSubmissionsByGameIDAndTeamIDFunc != nil {
+		return m.getSubmissionsByGameIDAndTeamIDFunc(ctx, arg)
+	}
+	return nil, nil
+}
+
+// GetTeamByUserID puts every user in a team of their own whose ID is the
+// user ID unless overridden.
+func (m *mockQuerier) GetTeamByUserID(ctx context.Context, arg db.GetTeamByUserIDParams) (db.GameTeam, error) {
+	if m.getTeamByUserIDFunc != nil {
+		return m.getTeamByUserIDFunc(ctx, arg)
+	}
+	return db.GameTeam{TeamID: arg.UserID, GameID: arg.GameID}, nil
+}
+
+func (m *mockQuerier) ListTeams(ctx context.Context, gameID int32) ([]db.GameTeam, error) {
+	if m.listTeamsFunc != nil {
+		return m.listTeamsFunc(ctx, gameID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListTeamMembers(ctx context.Context, gameID int32) ([]db.ListTeamMembersRow, error) {
+	if m.listTeamMembersFunc != nil {
+		return m.listTeamMembersFunc(ctx, gameID)
 	}
 	return nil, nil
 }
 
-func (m *mockQuerier) GetSubmissionsByGameIDAndUserID(ctx context.Context, arg db.GetSubmissionsByGameIDAndUserIDParams) ([]db.Submission, error) {
-	if m.getSubmissionsByGameIDAndUserIDFunc != nil {
-		return m.getSubmissionsByGameIDAndUserIDFunc(ctx, arg)
+func (m *mockQuerier) GetCodeForSnapshot(ctx context.Context, arg db.GetCodeForSnapshotParams) (db.GetCodeForSnapshotRow, error) {
+	if m.getCodeForSnapshotFunc != nil {
+		return m.getCodeForSnapshotFunc(ctx, arg)
+	}
+	return db.GetCodeForSnapshotRow{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) CreateCodeSnapshot(ctx context.Context, arg db.CreateCodeSnapshotParams) error {
+	if m.createCodeSnapshotFunc != nil {
+		return m.createCodeSnapshotFunc(ctx, arg)
+	}
+	return nil
+}
+
+func (m *mockQuerier) ListCodeSnapshots(ctx context.Context, arg db.ListCodeSnapshotsParams) ([]db.CodeSnapshot, error) {
+	if m.listCodeSnapshotsFunc != nil {
+		return m.listCodeSnapshotsFunc(ctx, arg)
 	}
 	return nil, nil
 }
@@ -125,27 +247,94 @@ func (m *mockQuerier) GetUserByID(ctx context.Context, userID int32) (db.User, e
 	return db.User{}, pgx.ErrNoRows
 }
 
-// mockTxManager implements db.TxManager for testing.
-type mockTxManager struct{}
+func (m *mockQuerier) ListRatedUsers(ctx context.Context) ([]db.User, error) {
+	if m.listRatedUsersFunc != nil {
+		return m.listRatedUsersFunc(ctx)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) ListRatingHistoryByUserID(ctx context.Context, userID int32) ([]db.ListRatingHistoryByUserIDRow, error) {
+	if m.listRatingHistoryByUserIDFunc != nil {
+		return m.listRatingHistoryByUserIDFunc(ctx, userID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) GetQualifyingStageByID(ctx context.Context, stageID int32) (db.QualifyingStage, error) {
+	if m.getQualifyingStageByIDFunc != nil {
+		return m.getQualifyingStageByIDFunc(ctx, stageID)
+	}
+	return db.QualifyingStage{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) ListQualifyingStageGames(ctx context.Context, stageID int32) ([]db.ListQualifyingStageGamesRow, error) {
+	if m.listQualifyingStageGamesFunc != nil {
+		return m.listQualifyingStageGamesFunc(ctx, stageID)
+	}
+	return nil, nil
+}
+
+func (m *mockQuerier) GetSubmissionByID(ctx context.Context, submissionID int32) (db.Submission, error) {
+	if m.getSubmissionByIDFunc != nil {
+		return m.getSubmissionByIDFunc(ctx, submissionID)
+	}
+	return db.Submission{}, pgx.ErrNoRows
+}
+
+func (m *mockQuerier) ListTestcaseResultsWithTestcaseBySubmissionID(ctx context.Context, submissionID int32) ([]db.ListTestcaseResultsWithTestcaseBySubmissionIDRow, error) {
+	if m.listTestcaseResultsWithTestcaseFunc != nil {
+		return m.listTestcaseResultsWithTestcaseFunc(ctx, submissionID)
+	}
+	return nil, nil
+}
+
+// mockTxManager implements db.TxManager for testing. Transactions run on q,
+// or on an empty mockQuerier if q is nil.
+type mockTxManager struct {
+	q db.Querier
+}
 
 func (m *mockTxManager) RunInTx(_ context.Context, fn func(q db.Querier) error) error {
+	if m.q != nil {
+		return fn(m.q)
+	}
 	return fn(&mockQuerier{})
 }
 
 // mockGameHub implements game.HubInterface for testing.
 type mockGameHub struct {
-	calcCodeSizeResult int
-	enqueueErr         error
+	enqueueErr      error
+	publishedEvents []game.Event
+	events          chan game.Event
+	runResult       game.RunResult
+	runErr          error
+	runStdins       []string
+	enqueuedLangs   []string
 }
 
-func (m *mockGameHub) CalcCodeSize(_ string, _ string) int {
-	return m.calcCodeSizeResult
+func (m *mockGameHub) EnqueueTestTasks(_ context.Context, _, _, _, _ int, language, _ string) error {
+	m.enqueuedLangs = append(m.enqueuedLangs, language)
+	return m.enqueueErr
 }
 
-func (m *mockGameHub) EnqueueTestTasks(_ context.Context, _, _, _ int, _, _ string) error {
+func (m *mockGameHub) EnqueueValidationTasks(_ context.Context, _ int, _ db.Problem, _ []db.ReferenceSolution, _ []db.Testcase) error {
 	return m.enqueueErr
 }
 
+func (m *mockGameHub) PublishEvent(event game.Event) {
+	m.publishedEvents = append(m.publishedEvents, event)
+}
+
+func (m *mockGameHub) SubscribeEvents(_ int) (<-chan game.Event, func()) {
+	return m.events, func() {}
+}
+
+func (m *mockGameHub) RunCode(_ context.Context, _, _ int, _, _, stdin string, _, _ int) (game.RunResult, error) {
+	m.runStdins = append(m.runStdins, stdin)
+	return m.runResult, m.runErr
+}
+
 // mockAuthenticator implements AuthenticatorInterface for testing.
 type mockAuthenticator struct {
 	loginResult int
@@ -159,8 +348,10 @@ func (m *mockAuthenticator) Login(_ context.Context, _, _ string) (int, error) {
 func newTestHandler(q *mockQuerier) Handler {
 	hub := &mockGameHub{}
 	return Handler{
-		gameSvc:       game.NewService(q, &mockTxManager{}, hub),
-		tournamentSvc: tournament.NewService(q, &mockTxManager{}),
+		gameSvc:       game.NewService(q, &mockTxManager{q: q}, hub),
+		tournamentSvc: tournament.NewService(q, &mockTxManager{q: q}),
+		ratingSvc:     rating.NewService(q, &mockTxManager{q: q}),
+		qualifyingSvc: qualifying.NewService(q, &mockTxManager{q: q}),
 		auth:          &mockAuthenticator{},
 		conf:          &config.Config{},
 		q:             q,
@@ -169,8 +360,10 @@ func newTestHandler(q *mockQuerier) Handler {
 
 func newTestHandlerWithHub(q *mockQuerier, hub *mockGameHub) Handler {
 	return Handler{
-		gameSvc:       game.NewService(q, &mockTxManager{}, hub),
-		tournamentSvc: tournament.NewService(q, &mockTxManager{}),
+		gameSvc:       game.NewService(q, &mockTxManager{q: q}, hub),
+		tournamentSvc: tournament.NewService(q, &mockTxManager{q: q}),
+		ratingSvc:     rating.NewService(q, &mockTxManager{q: q}),
+		qualifyingSvc: qualifying.NewService(q, &mockTxManager{q: q}),
 		auth:          &mockAuthenticator{},
 		conf:          &config.Config{},
 		q:             q,
@@ -193,10 +386,9 @@ func TestGetGamePlaySubmissions_GameNotFound(t *testing.T) {
 
 func TestGetGamePlaySubmissions_Empty(t *testing.T) {
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
-				GameID:   1,
-				Language: "php",
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID: 1,
 			}, nil
 		},
 	})
@@ -219,15 +411,14 @@ func TestGetGamePlaySubmissions_Empty(t *testing.T) {
 func TestGetGamePlaySubmissions_WithSubmissions(t *testing.T) {
 	now := time.Now()
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
-				GameID:   1,
-				Language: "php",
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID: 1,
 			}, nil
 		},
-		getSubmissionsByGameIDAndUserIDFunc: func(_ context.Context, arg db.GetSubmissionsByGameIDAndUserIDParams) ([]db.Submission, error) {
-			if arg.GameID != 1 || arg.UserID != 42 {
-				t.Errorf("unexpected query params: game_id=%d, user_id=%d", arg.GameID, arg.UserID)
+		getSubmissionsByGameIDAndTeamIDFunc: func(_ context.Context, arg db.GetSubmissionsByGameIDAndTeamIDParams) ([]db.Submission, error) {
+			if arg.GameID != 1 || arg.TeamID != 42 {
+				t.Errorf("unexpected query params: game_id=%d, team_id=%d", arg.GameID, arg.TeamID)
 			}
 			return []db.Submission{
 				{
@@ -279,7 +470,7 @@ func TestGetGamePlaySubmissions_WithSubmissions(t *testing.T) {
 	if s0.CodeSize != 14 {
 		t.Errorf("expected code_size 14, got %d", s0.CodeSize)
 	}
-	if s0.Status != Success {
+	if s0.Status != ExecutionStatusSuccess {
 		t.Errorf("expected status 'success', got %q", s0.Status)
 	}
 	if s0.CreatedAt != now.Unix() {
@@ -290,17 +481,96 @@ func TestGetGamePlaySubmissions_WithSubmissions(t *testing.T) {
 	if s1.SubmissionID != 9 {
 		t.Errorf("expected submission_id 9, got %d", s1.SubmissionID)
 	}
-	if s1.Status != WrongAnswer {
+	if s1.Status != ExecutionStatusWrongAnswer {
 		t.Errorf("expected status 'wrong_answer', got %q", s1.Status)
 	}
 }
 
+func TestGetGamePlaySubmission_OtherUsersSubmission(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getSubmissionByIDFunc: func(_ context.Context, _ int32) (db.Submission, error) {
+			return db.Submission{SubmissionID: 10, GameID: 1, UserID: 7}, nil
+		},
+	})
+	user := &db.User{UserID: 42}
+	resp, err := h.GetGamePlaySubmission(context.Background(), GetGamePlaySubmissionRequestObject{
+		GameID:       1,
+		SubmissionID: 10,
+	}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGamePlaySubmission404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetGamePlaySubmission_TestcaseResults(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getSubmissionByIDFunc: func(_ context.Context, _ int32) (db.Submission, error) {
+			return db.Submission{SubmissionID: 10, GameID: 1, TeamID: 42, UserID: 42, Status: "wrong_answer"}, nil
+		},
+		listTestcaseResultsWithTestcaseFunc: func(_ context.Context, submissionID int32) ([]db.ListTestcaseResultsWithTestcaseBySubmissionIDRow, error) {
+			if submissionID != 10 {
+				t.Errorf("unexpected submission_id: %d", submissionID)
+			}
+			return []db.ListTestcaseResultsWithTestcaseBySubmissionIDRow{
+				{
+					TestcaseResult: db.TestcaseResult{TestcaseID: 1, Status: "success", Stdout: "3"},
+					Testcase:       db.Testcase{TestcaseID: 1, IsSample: true, Stdin: "1 2", Stdout: "3"},
+				},
+				{
+					TestcaseResult: db.TestcaseResult{TestcaseID: 2, Status: "wrong_answer", Stdout: "secret output"},
+					Testcase:       db.Testcase{TestcaseID: 2, Stdin: "secret input", Stdout: "secret expected"},
+				},
+			}, nil
+		},
+	})
+	user := &db.User{UserID: 42}
+	resp, err := h.GetGamePlaySubmission(context.Background(), GetGamePlaySubmissionRequestObject{
+		GameID:       1,
+		SubmissionID: 10,
+	}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGamePlaySubmission200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if okResp.Submission.SubmissionID != 10 {
+		t.Errorf("expected submission_id 10, got %d", okResp.Submission.SubmissionID)
+	}
+	if len(okResp.TestcaseResults) != 2 {
+		t.Fatalf("expected 2 testcase results, got %d", len(okResp.TestcaseResults))
+	}
+
+	sample := okResp.TestcaseResults[0]
+	if !sample.IsSample || !sample.Passed {
+		t.Errorf("expected passed sample, got is_sample=%v passed=%v", sample.IsSample, sample.Passed)
+	}
+	if sample.Stdin == nil || *sample.Stdin != "1 2" {
+		t.Errorf("expected sample stdin '1 2', got %v", sample.Stdin)
+	}
+	if sample.Status == nil || *sample.Status != ExecutionStatusSuccess {
+		t.Errorf("expected sample status 'success', got %v", sample.Status)
+	}
+
+	hidden := okResp.TestcaseResults[1]
+	if hidden.IsSample || hidden.Passed {
+		t.Errorf("expected failed hidden testcase, got is_sample=%v passed=%v", hidden.IsSample, hidden.Passed)
+	}
+	if hidden.Status != nil || hidden.Stdin != nil || hidden.ExpectedStdout != nil || hidden.Stdout != nil || hidden.Stderr != nil {
+		t.Errorf("expected hidden testcase details to be omitted, got %+v", hidden)
+	}
+}
+
 func TestPostGamePlaySubmit_GameNotFound(t *testing.T) {
 	h := newTestHandler(&mockQuerier{})
 	user := &db.User{UserID: 1}
 	resp, err := h.PostGamePlaySubmit(context.Background(), PostGamePlaySubmitRequestObject{
 		GameID: 999,
-		Body:   &PostGamePlaySubmitJSONRequestBody{Code: "test"},
+		Body:   &PostGamePlaySubmitJSONRequestBody{ProblemID: 10, Code: "test"},
 	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -312,20 +582,19 @@ func TestPostGamePlaySubmit_GameNotFound(t *testing.T) {
 
 func TestPostGamePlaySubmit_GameNotRunning(t *testing.T) {
 	h := newTestHandlerWithHub(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
-				GameID:   1,
-				Language: "php",
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID: 1,
 				StartedAt: pgtype.Timestamp{
 					Valid: false,
 				},
 			}, nil
 		},
-	}, &mockGameHub{calcCodeSizeResult: 10})
+	}, &mockGameHub{})
 	user := &db.User{UserID: 1}
 	resp, err := h.PostGamePlaySubmit(context.Background(), PostGamePlaySubmitRequestObject{
 		GameID: 1,
-		Body:   &PostGamePlaySubmitJSONRequestBody{Code: "<?php echo 1;"},
+		Body:   &PostGamePlaySubmitJSONRequestBody{ProblemID: 10, Code: "<?php echo 1;"},
 	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -337,6 +606,181 @@ func TestPostGamePlaySubmit_GameNotRunning(t *testing.T) {
 	}
 }
 
+func TestPostGamePlaySubmit_Practice(t *testing.T) {
+	finished := db.Game{
+		GameID:          1,
+		StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true},
+		DurationSeconds: 600,
+	}
+	var created []db.CreateSubmissionParams
+	q := &mockQuerier{
+		listGameProblemsFunc: func(_ context.Context, _ []int32) ([]db.ListGameProblemsRow, error) {
+			problem := testProblem
+			problem.Scoring = "bytes"
+			return []db.ListGameProblemsRow{{GameID: 1, Problem: problem}}, nil
+		},
+		createSubmissionFunc: func(_ context.Context, arg db.CreateSubmissionParams) (int32, error) {
+			created = append(created, arg)
+			return 1, nil
+		},
+	}
+	hub := &mockGameHub{}
+	h := newTestHandlerWithHub(q, hub)
+	submit := func() PostGamePlaySubmitResponseObject {
+		resp, err := h.PostGamePlaySubmit(context.Background(), PostGamePlaySubmitRequestObject{
+			GameID: 1,
+			Body:   &PostGamePlaySubmitJSONRequestBody{ProblemID: 10, Code: "<?php echo 1;"},
+		}, &db.User{UserID: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	q.getGameByIDFunc = func(_ context.Context, _ int32) (db.Game, error) {
+		return finished, nil
+	}
+	if _, ok := submit().(PostGamePlaySubmit403JSONResponse); !ok {
+		t.Error("expected finished games to reject submissions unless practice is allowed")
+	}
+
+	finished.AllowPractice = true
+	if _, ok := submit().(PostGamePlaySubmit200Response); !ok {
+		t.Fatal("expected finished games that allow practice to accept submissions")
+	}
+	if len(created) != 1 || !created[0].IsPractice {
+		t.Errorf("expected one practice submission, got %+v", created)
+	}
+	if len(hub.publishedEvents) != 0 {
+		t.Errorf("expected practice submissions not to publish events, got %d", len(hub.publishedEvents))
+	}
+}
+
+func TestPostGamePlaySubmit_Language(t *testing.T) {
+	practice := db.Game{
+		GameID:          1,
+		StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true},
+		DurationSeconds: 600,
+		AllowPractice:   true,
+	}
+	var created []db.CreateSubmissionParams
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return practice, nil
+		},
+		listGameProblemsFunc: func(_ context.Context, _ []int32) ([]db.ListGameProblemsRow, error) {
+			problem := testProblem
+			problem.Scoring = "bytes"
+			return []db.ListGameProblemsRow{{GameID: 1, Problem: problem}}, nil
+		},
+		createSubmissionFunc: func(_ context.Context, arg db.CreateSubmissionParams) (int32, error) {
+			created = append(created, arg)
+			return 1, nil
+		},
+	}
+	hub := &mockGameHub{}
+	h := newTestHandlerWithHub(q, hub)
+	submit := func(language ProblemLanguage) PostGamePlaySubmitResponseObject {
+		resp, err := h.PostGamePlaySubmit(context.Background(), PostGamePlaySubmitRequestObject{
+			GameID: 1,
+			Body:   &PostGamePlaySubmitJSONRequestBody{ProblemID: 10, Language: &language, Code: "print(1)"},
+		}, &db.User{UserID: 1})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	if r, ok := submit(Swift).(PostGamePlaySubmit400JSONResponse); !ok {
+		t.Error("expected a language the problem does not accept to be rejected")
+	} else if r.Message != "Language is not allowed for the problem" {
+		t.Errorf("unexpected message: %s", r.Message)
+	}
+	if len(created) != 0 {
+		t.Errorf("expected no submissions, got %+v", created)
+	}
+
+	q.listProblemLanguagesFunc = func(_ context.Context, _ []int32) ([]db.ProblemLanguage, error) {
+		return []db.ProblemLanguage{{ProblemID: 10, Language: "swift"}}, nil
+	}
+	if _, ok := submit(Swift).(PostGamePlaySubmit200Response); !ok {
+		t.Fatal("expected a language the problem accepts to be allowed")
+	}
+	if len(created) != 1 || created[0].Language != "swift" {
+		t.Errorf("expected one swift submission, got %+v", created)
+	}
+	if len(hub.enqueuedLangs) != 1 || hub.enqueuedLangs[0] != "swift" {
+		t.Errorf("expected the submission to be judged in swift, got %v", hub.enqueuedLangs)
+	}
+}
+
+func TestPostGamePlayRun_Success(t *testing.T) {
+	hub := &mockGameHub{
+		runResult: game.RunResult{Status: "runtime_error", Stdout: "partial", Stderr: "Fatal error"},
+	}
+	h := newTestHandlerWithHub(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				DurationSeconds: 600,
+				StartedAt: pgtype.Timestamp{
+					Time:  time.Now().Add(-time.Minute),
+					Valid: true,
+				},
+			}, nil
+		},
+	}, hub)
+	user := &db.User{UserID: 1}
+	resp, err := h.PostGamePlayRun(context.Background(), PostGamePlayRunRequestObject{
+		GameID: 1,
+		Body:   &PostGamePlayRunJSONRequestBody{ProblemID: 10, Code: "<?php echo fgets(STDIN);", Stdin: "hello"},
+	}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(PostGamePlayRun200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if okResp.Status != ExecutionStatusRuntimeError || okResp.Stdout != "partial" || okResp.Stderr != "Fatal error" {
+		t.Errorf("unexpected response: %+v", okResp)
+	}
+	if len(hub.runStdins) != 1 || hub.runStdins[0] != "hello" {
+		t.Errorf("expected one run with stdin 'hello', got %v", hub.runStdins)
+	}
+	if len(hub.publishedEvents) != 0 {
+		t.Errorf("expected no events, got %d", len(hub.publishedEvents))
+	}
+}
+
+func TestPostGamePlayRun_GameNotRunning(t *testing.T) {
+	hub := &mockGameHub{}
+	h := newTestHandlerWithHub(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID: 1,
+				StartedAt: pgtype.Timestamp{
+					Valid: false,
+				},
+			}, nil
+		},
+	}, hub)
+	user := &db.User{UserID: 1}
+	resp, err := h.PostGamePlayRun(context.Background(), PostGamePlayRunRequestObject{
+		GameID: 1,
+		Body:   &PostGamePlayRunJSONRequestBody{ProblemID: 10, Code: "<?php echo 1;", Stdin: ""},
+	}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(PostGamePlayRun403JSONResponse); !ok {
+		t.Errorf("expected 403 response, got %T", resp)
+	}
+	if len(hub.runStdins) != 0 {
+		t.Errorf("expected no runs, got %d", len(hub.runStdins))
+	}
+}
+
 func TestGetMe(t *testing.T) {
 	h := newTestHandler(&mockQuerier{})
 	user := &db.User{
@@ -378,11 +822,10 @@ func TestGetGame_NotFound(t *testing.T) {
 
 func TestGetGame_NonPublicAsNonAdmin(t *testing.T) {
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
 				GameID:   1,
 				IsPublic: false,
-				Language: "php",
 			}, nil
 		},
 	})
@@ -399,19 +842,14 @@ func TestGetGame_NonPublicAsNonAdmin(t *testing.T) {
 func TestGetGame_PublicGameSuccess(t *testing.T) {
 	now := time.Now()
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
 				GameID:          1,
 				IsPublic:        true,
-				Language:        "php",
 				DisplayName:     "Test Game",
 				DurationSeconds: 300,
 				StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
 				GameType:        "golf",
-				ProblemID:       10,
-				Title:           "Test Problem",
-				Description:     "desc",
-				SampleCode:      "<?php",
 			}, nil
 		},
 		listMainPlayersFunc: func(_ context.Context, _ []int32) ([]db.ListMainPlayersRow, error) {
@@ -497,8 +935,8 @@ func TestGetGames_Empty(t *testing.T) {
 func TestGetGames_WithGames(t *testing.T) {
 	now := time.Now()
 	h := newTestHandler(&mockQuerier{
-		listPublicGamesFunc: func(_ context.Context) ([]db.ListPublicGamesRow, error) {
-			return []db.ListPublicGamesRow{
+		listPublicGamesFunc: func(_ context.Context) ([]db.Game, error) {
+			return []db.Game{
 				{
 					GameID:          1,
 					GameType:        "golf",
@@ -506,11 +944,6 @@ func TestGetGames_WithGames(t *testing.T) {
 					DisplayName:     "Game 1",
 					DurationSeconds: 300,
 					StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
-					ProblemID:       10,
-					Title:           "Problem 1",
-					Description:     "desc",
-					Language:        "php",
-					SampleCode:      "<?php",
 				},
 			}, nil
 		},
@@ -536,12 +969,15 @@ func TestGetGames_WithGames(t *testing.T) {
 	if okResp.Games[0].StartedAt == nil {
 		t.Error("expected non-nil StartedAt")
 	}
+	if len(okResp.Games[0].Problems) != 1 || okResp.Games[0].Problems[0].ProblemID != 10 {
+		t.Errorf("expected problem 10, got %+v", okResp.Games[0].Problems)
+	}
 }
 
 func TestGetGamePlayLatestState_NoState(t *testing.T) {
 	h := newTestHandler(&mockQuerier{})
 	user := &db.User{UserID: 1}
-	resp, err := h.GetGamePlayLatestState(context.Background(), GetGamePlayLatestStateRequestObject{GameID: 1}, user)
+	resp, err := h.GetGamePlayLatestState(context.Background(), GetGamePlayLatestStateRequestObject{GameID: 1, Params: GetGamePlayLatestStateParams{ProblemID: 10}}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -552,7 +988,7 @@ func TestGetGamePlayLatestState_NoState(t *testing.T) {
 	if okResp.State.Code != "" {
 		t.Errorf("expected empty code, got %q", okResp.State.Code)
 	}
-	if okResp.State.Status != None {
+	if okResp.State.Status != ExecutionStatusNone {
 		t.Errorf("expected status 'none', got %q", okResp.State.Status)
 	}
 }
@@ -562,7 +998,7 @@ func TestPostGamePlayCode_GameNotFound(t *testing.T) {
 	user := &db.User{UserID: 1}
 	resp, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
 		GameID: 999,
-		Body:   &PostGamePlayCodeJSONRequestBody{Code: "test"},
+		Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 10, Code: "test"},
 	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -574,10 +1010,9 @@ func TestPostGamePlayCode_GameNotFound(t *testing.T) {
 
 func TestPostGamePlayCode_GameNotRunning(t *testing.T) {
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
-				GameID:   1,
-				Language: "php",
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID: 1,
 				StartedAt: pgtype.Timestamp{
 					Valid: false,
 				},
@@ -587,7 +1022,7 @@ func TestPostGamePlayCode_GameNotRunning(t *testing.T) {
 	user := &db.User{UserID: 1}
 	resp, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
 		GameID: 1,
-		Body:   &PostGamePlayCodeJSONRequestBody{Code: "<?php echo 1;"},
+		Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 10, Code: "<?php echo 1;"},
 	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -600,24 +1035,25 @@ func TestPostGamePlayCode_GameNotRunning(t *testing.T) {
 func TestPostGamePlayCode_Success(t *testing.T) {
 	now := time.Now()
 	var updatedCode string
+	var updatedProblemID int32
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
 				GameID:          1,
-				Language:        "php",
 				StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
 				DurationSeconds: 600,
 			}, nil
 		},
 		updateCodeFunc: func(_ context.Context, arg db.UpdateCodeParams) error {
 			updatedCode = arg.Code
+			updatedProblemID = arg.ProblemID
 			return nil
 		},
 	})
 	user := &db.User{UserID: 1}
 	resp, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
 		GameID: 1,
-		Body:   &PostGamePlayCodeJSONRequestBody{Code: "<?php echo 42;"},
+		Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 10, Code: "<?php echo 42;"},
 	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
@@ -628,56 +1064,656 @@ func TestPostGamePlayCode_Success(t *testing.T) {
 	if updatedCode != "<?php echo 42;" {
 		t.Errorf("expected code '<?php echo 42;', got %q", updatedCode)
 	}
+	if updatedProblemID != 10 {
+		t.Errorf("expected problem 10, got %d", updatedProblemID)
+	}
 }
 
-func TestGetGameWatchRanking_NotFound(t *testing.T) {
-	h := newTestHandler(&mockQuerier{})
+func TestPostGamePlayCode_RecordsSnapshot(t *testing.T) {
+	now := time.Now()
+	savedCode := "<?php echo 4;"
+	var snapshots []db.CreateCodeSnapshotParams
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+		getCodeForSnapshotFunc: func(_ context.Context, _ db.GetCodeForSnapshotParams) (db.GetCodeForSnapshotRow, error) {
+			return db.GetCodeForSnapshotRow{Code: savedCode, HasSnapshots: true}, nil
+		},
+		createCodeSnapshotFunc: func(_ context.Context, arg db.CreateCodeSnapshotParams) error {
+			snapshots = append(snapshots, arg)
+			return nil
+		},
+		updateCodeFunc: func(_ context.Context, arg db.UpdateCodeParams) error {
+			savedCode = arg.Code
+			return nil
+		},
+	})
 	user := &db.User{UserID: 1}
-	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 999}, user)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
+	// Saving the same code again is not recorded.
+	for range 2 {
+		_, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
+			GameID: 1,
+			Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 10, Code: "<?php echo 42;"},
+		}, user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
 	}
-	if _, ok := resp.(GetGameWatchRanking404JSONResponse); !ok {
-		t.Errorf("expected 404 response, got %T", resp)
+
+	want := []db.CreateCodeSnapshotParams{
+		{GameID: 1, TeamID: 1, UserID: 1, ProblemID: 10, KeepPrefix: 12, KeepSuffix: 1, Inserted: "2"},
+	}
+	if !slices.Equal(snapshots, want) {
+		t.Errorf("snapshots = %+v, want %+v", snapshots, want)
 	}
 }
 
-func TestGetGameWatchRanking_EmptyRanking(t *testing.T) {
+func TestPostGamePlayCode_ProblemNotInGame(t *testing.T) {
 	now := time.Now()
 	h := newTestHandler(&mockQuerier{
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
 				GameID:          1,
-				Language:        "php",
-				StartedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
-				DurationSeconds: 300,
+				StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
+				DurationSeconds: 600,
 			}, nil
 		},
-		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
-			return nil, nil
+		updateCodeFunc: func(_ context.Context, _ db.UpdateCodeParams) error {
+			t.Error("expected no code to be saved")
+			return nil
 		},
 	})
 	user := &db.User{UserID: 1}
-	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 1}, user)
+	resp, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
+		GameID: 1,
+		Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 99, Code: "<?php echo 42;"},
+	}, user)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-	okResp, ok := resp.(GetGameWatchRanking200JSONResponse)
-	if !ok {
-		t.Fatalf("expected 200 response, got %T", resp)
-	}
-	if len(okResp.Ranking) != 0 {
-		t.Errorf("expected empty ranking, got %d entries", len(okResp.Ranking))
+	if _, ok := resp.(PostGamePlayCode404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
 	}
 }
 
-func TestGetGameWatchLatestStates_Empty(t *testing.T) {
-	h := newTestHandler(&mockQuerier{})
-	user := &db.User{UserID: 1}
-	resp, err := h.GetGameWatchLatestStates(context.Background(), GetGameWatchLatestStatesRequestObject{GameID: 1}, user)
-	if err != nil {
-		t.Fatalf("unexpected error: %v", err)
-	}
+func TestPostGamePlayCode_PublishesCodeEvent(t *testing.T) {
+	now := time.Now()
+	hub := &mockGameHub{}
+	h := newTestHandlerWithHub(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now, Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+	}, hub)
+	user := &db.User{UserID: 7}
+	_, err := h.PostGamePlayCode(context.Background(), PostGamePlayCodeRequestObject{
+		GameID: 1,
+		Body:   &PostGamePlayCodeJSONRequestBody{ProblemID: 10, Code: "<?php echo 42;"},
+	}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(hub.publishedEvents) != 1 {
+		t.Fatalf("expected 1 event, got %d", len(hub.publishedEvents))
+	}
+	ev := hub.publishedEvents[0]
+	if ev.Type != game.EventTypeCode || ev.GameID != 1 || ev.UserID != 7 || ev.Code != "<?php echo 42;" {
+		t.Errorf("unexpected event: %+v", ev)
+	}
+}
+
+func TestGetGameWatchEvents_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+	resp, err := h.GetGameWatchEvents(context.Background(), GetGameWatchEventsRequestObject{GameID: 999}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGameWatchEvents404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetGameWatchEvents_MainPlayerForbidden(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1}, nil
+		},
+		listMainPlayersFunc: func(_ context.Context, _ []int32) ([]db.ListMainPlayersRow, error) {
+			return []db.ListMainPlayersRow{{GameID: 1, UserID: 5}}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	resp, err := h.GetGameWatchEvents(context.Background(), GetGameWatchEventsRequestObject{GameID: 1}, &db.User{UserID: 5})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGameWatchEvents403JSONResponse); !ok {
+		t.Errorf("expected 403 response, got %T", resp)
+	}
+
+	resp, err = h.GetGameWatchEvents(context.Background(), GetGameWatchEventsRequestObject{GameID: 1}, &db.User{UserID: 5, IsAdmin: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(eventStreamResponse); !ok {
+		t.Errorf("expected event stream for admin, got %T", resp)
+	}
+}
+
+func TestGetGamePlayEvents_StreamsTeamAndGameEvents(t *testing.T) {
+	events := make(chan game.Event, 5)
+	events <- game.Event{Type: game.EventTypeCode, GameID: 1, UserID: 2, TeamID: 2, Code: "other"}
+	events <- game.Event{Type: game.EventTypeCode, GameID: 1, UserID: 3, TeamID: 1, ProblemID: 10, Code: "mate"}
+	events <- game.Event{Type: game.EventTypeStatus, GameID: 1, UserID: 1, TeamID: 1, ProblemID: 10, Status: "running"}
+	score := 42
+	events <- game.Event{Type: game.EventTypeBestScore, GameID: 1, UserID: 1, TeamID: 1, ProblemID: 10, Score: &score}
+	events <- game.Event{Type: game.EventTypeGame, GameID: 1}
+	close(events)
+
+	hub := &mockGameHub{events: events}
+	h := newTestHandlerWithHub(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1}, nil
+		},
+	}, hub)
+
+	resp, err := h.GetGamePlayEvents(context.Background(), GetGamePlayEventsRequestObject{GameID: 1}, &db.User{UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	rec := httptest.NewRecorder()
+	if err := resp.VisitGetGamePlayEventsResponse(rec); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if ct := rec.Header().Get("Content-Type"); ct != "text/event-stream" {
+		t.Errorf("expected Content-Type text/event-stream, got %q", ct)
+	}
+	want := "event: code\ndata: {\"code\":\"mate\",\"problem_id\":10,\"team_id\":1,\"type\":\"code\",\"user_id\":3}\n\n" +
+		"event: status\ndata: {\"problem_id\":10,\"status\":\"running\",\"team_id\":1,\"type\":\"status\",\"user_id\":1}\n\n" +
+		"event: best_score\ndata: {\"problem_id\":10,\"score\":42,\"team_id\":1,\"type\":\"best_score\",\"user_id\":1}\n\n" +
+		"event: game\ndata: {\"problem_id\":0,\"team_id\":0,\"type\":\"game\",\"user_id\":0}\n\n"
+	if got := rec.Body.String(); got != want {
+		t.Errorf("unexpected body:\n got: %q\nwant: %q", got, want)
+	}
+}
+
+func TestGetGameWatchRanking_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+	user := &db.User{UserID: 1}
+	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 999}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGameWatchRanking404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetGameWatchRanking_EmptyRanking(t *testing.T) {
+	now := time.Now()
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
+				DurationSeconds: 300,
+				TieBreak:        ranking.EarliestSubmission,
+			}, nil
+		},
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			return nil, nil
+		},
+	})
+	user := &db.User{UserID: 1}
+	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 1}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameWatchRanking200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(okResp.Ranking) != 0 {
+		t.Errorf("expected empty ranking, got %d entries", len(okResp.Ranking))
+	}
+}
+
+func TestGetGameWatchRanking_Pagination(t *testing.T) {
+	now := time.Now()
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
+				DurationSeconds: 300,
+				TieBreak:        ranking.SharedRank,
+			}, nil
+		},
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			var rows []db.GetRankingRow
+			for i, score := range []int32{10, 10, 10, 20, 30} {
+				rows = append(rows, db.GetRankingRow{
+					Submission: db.Submission{
+						ProblemID: 10,
+						CodeSize:  score,
+						CreatedAt: pgtype.Timestamp{Time: now.Add(time.Duration(i-10) * time.Minute), Valid: true},
+					},
+					GameTeam:        db.GameTeam{TeamID: int32(i + 1)},
+					SubmissionCount: 1,
+				})
+			}
+			return rows, nil
+		},
+	}
+	h := newTestHandler(q)
+	user := &db.User{UserID: 1}
+
+	var ranks []int
+	var cursor *string
+	for range 3 {
+		limit := 2
+		resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{
+			GameID: 1,
+			Params: GetGameWatchRankingParams{Cursor: cursor, Limit: &limit},
+		}, user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		okResp, ok := resp.(GetGameWatchRanking200JSONResponse)
+		if !ok {
+			t.Fatalf("expected 200 response, got %T", resp)
+		}
+		if okResp.TieBreak != SharedRank {
+			t.Errorf("TieBreak = %q, want %q", okResp.TieBreak, SharedRank)
+		}
+		for _, entry := range okResp.Ranking {
+			ranks = append(ranks, entry.Rank)
+		}
+		if okResp.NextCursor.IsNull() {
+			cursor = nil
+			break
+		}
+		next := okResp.NextCursor.MustGet()
+		cursor = &next
+	}
+	if cursor != nil {
+		t.Error("expected the last page to have no next cursor")
+	}
+	if want := []int{1, 1, 1, 4, 5}; !slices.Equal(ranks, want) {
+		t.Errorf("ranks = %v, want %v", ranks, want)
+	}
+}
+
+func TestGetGameWatchRanking_InvalidCursor(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, TieBreak: ranking.EarliestSubmission}, nil
+		},
+	})
+	cursor := "!!!"
+	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{
+		GameID: 1,
+		Params: GetGameWatchRankingParams{Cursor: &cursor},
+	}, &db.User{UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGameWatchRanking400JSONResponse); !ok {
+		t.Errorf("expected 400 response, got %T", resp)
+	}
+}
+
+func TestGetGameWatchRanking_Practice(t *testing.T) {
+	now := time.Now()
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true},
+				DurationSeconds: 600,
+				TieBreak:        ranking.EarliestSubmission,
+				AllowPractice:   true,
+			}, nil
+		},
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			t.Error("expected the official ranking not to be queried")
+			return nil, nil
+		},
+		listBestPracticeSubmissionsFunc: func(_ context.Context, _ db.ListBestPracticeSubmissionsParams) ([]db.ListBestPracticeSubmissionsRow, error) {
+			return []db.ListBestPracticeSubmissionsRow{{
+				Submission: db.Submission{
+					ProblemID:  10,
+					Code:       "<?=1;",
+					CodeSize:   5,
+					IsPractice: true,
+					CreatedAt:  pgtype.Timestamp{Time: now.Add(-time.Minute), Valid: true},
+				},
+				GameTeam:        db.GameTeam{TeamID: 3},
+				SubmissionCount: 2,
+			}}, nil
+		},
+	})
+	practice := true
+	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{
+		GameID: 1,
+		Params: GetGameWatchRankingParams{Practice: &practice},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameWatchRanking200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(okResp.Ranking) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(okResp.Ranking))
+	}
+	entry := okResp.Ranking[0]
+	if entry.Team.TeamID != 3 || entry.Score != 5 || entry.SubmissionCount != 2 {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if code, err := entry.Code.Get(); err != nil || code != "<?=1;" {
+		t.Errorf("expected the practice code to be revealed, got %v", entry.Code)
+	}
+}
+
+func TestGetGameWatchRanking_Frozen(t *testing.T) {
+	now := time.Now()
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-8 * time.Minute), Valid: true},
+				DurationSeconds: 600,
+				FreezeSeconds:   300,
+				TieBreak:        ranking.EarliestSubmission,
+			}, nil
+		},
+		getRankingFunc: func(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+			return []db.GetRankingRow{
+				{Submission: db.Submission{ProblemID: 10, CodeSize: 10}, GameTeam: db.GameTeam{TeamID: 2}},
+				{Submission: db.Submission{ProblemID: 10, CodeSize: 20}, GameTeam: db.GameTeam{TeamID: 3}},
+			}, nil
+		},
+		listBestSubmissionsAtFunc: func(_ context.Context, arg db.ListBestSubmissionsAtParams) ([]db.ListBestSubmissionsAtRow, error) {
+			wantCutoff := now.Add(-3 * time.Minute)
+			if !arg.CreatedAt.Time.Equal(wantCutoff) {
+				t.Errorf("cutoff = %v, want %v", arg.CreatedAt.Time, wantCutoff)
+			}
+			return []db.ListBestSubmissionsAtRow{
+				{Submission: db.Submission{ProblemID: 10, CodeSize: 30}, GameTeam: db.GameTeam{TeamID: 3}},
+			}, nil
+		},
+	}
+	h := newTestHandler(q)
+
+	resp, err := h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 1}, &db.User{UserID: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameWatchRanking200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if !okResp.IsFrozen {
+		t.Error("expected frozen ranking")
+	}
+	if len(okResp.Ranking) != 1 || okResp.Ranking[0].Score != 30 {
+		t.Errorf("unexpected ranking: %+v", okResp.Ranking)
+	}
+
+	// Admins keep the live ranking.
+	resp, err = h.GetGameWatchRanking(context.Background(), GetGameWatchRankingRequestObject{GameID: 1}, &db.User{UserID: 1, IsAdmin: true})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp = resp.(GetGameWatchRanking200JSONResponse)
+	if okResp.IsFrozen || len(okResp.Ranking) != 2 {
+		t.Errorf("expected live ranking for admin, got %+v", okResp)
+	}
+}
+
+func TestGetGameWatchReplay_Finished(t *testing.T) {
+	at := func(minute int) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Date(2026, 3, 20, 10, minute, 0, 0, time.UTC), Valid: true}
+	}
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+		listCodeSnapshotsFunc: func(_ context.Context, arg db.ListCodeSnapshotsParams) ([]db.CodeSnapshot, error) {
+			if arg.TeamID != 5 || arg.ProblemID != 10 {
+				t.Errorf("unexpected query params: team_id=%d, problem_id=%d", arg.TeamID, arg.ProblemID)
+			}
+			return []db.CodeSnapshot{
+				{UserID: 5, Inserted: "<?php echo 1;", CreatedAt: at(1)},
+				{UserID: 6, KeepPrefix: 11, KeepSuffix: 1, Inserted: "42", CreatedAt: at(2)},
+				{UserID: 5, KeepPrefix: 14, IsSubmission: true, CreatedAt: at(3)},
+			}, nil
+		},
+	})
+
+	resp, err := h.GetGameWatchReplay(context.Background(), GetGameWatchReplayRequestObject{
+		GameID: 1,
+		Params: GetGameWatchReplayParams{UserID: 5, ProblemID: 10},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameWatchReplay200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	want := []CodeSnapshot{
+		{UserID: 5, Code: "<?php echo 1;", CreatedAt: at(1).Time.Unix()},
+		{UserID: 6, Code: "<?php echo 42;", CreatedAt: at(2).Time.Unix()},
+		{UserID: 5, Code: "<?php echo 42;", IsSubmission: true, CreatedAt: at(3).Time.Unix()},
+	}
+	if !slices.Equal(okResp.Snapshots, want) {
+		t.Errorf("snapshots = %+v, want %+v", okResp.Snapshots, want)
+	}
+}
+
+func TestGetGameWatchReplay_Running(t *testing.T) {
+	q := &mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+		listMainPlayersFunc: func(_ context.Context, _ []int32) ([]db.ListMainPlayersRow, error) {
+			return []db.ListMainPlayersRow{{GameID: 1, UserID: 5}, {GameID: 1, UserID: 6}}, nil
+		},
+	}
+	h := newTestHandler(q)
+	replay := func(playerID int, user *db.User) GetGameWatchReplayResponseObject {
+		resp, err := h.GetGameWatchReplay(context.Background(), GetGameWatchReplayRequestObject{
+			GameID: 1,
+			Params: GetGameWatchReplayParams{UserID: playerID, ProblemID: 10},
+		}, user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := replay(5, &db.User{UserID: 1}).(GetGameWatchReplay200JSONResponse); !ok {
+		t.Error("expected spectators to replay a main player")
+	}
+	if _, ok := replay(7, &db.User{UserID: 1}).(GetGameWatchReplay403JSONResponse); !ok {
+		t.Error("expected other players to be hidden until the game finishes")
+	}
+	if _, ok := replay(5, &db.User{UserID: 6}).(GetGameWatchReplay403JSONResponse); !ok {
+		t.Error("expected main players not to replay their opponents")
+	}
+	if _, ok := replay(7, &db.User{UserID: 6, IsAdmin: true}).(GetGameWatchReplay200JSONResponse); !ok {
+		t.Error("expected admins to replay anyone")
+	}
+}
+
+func diffTestSubmissions(_ context.Context, submissionID int32) (db.Submission, error) {
+	submissions := map[int32]db.Submission{
+		1: {SubmissionID: 1, GameID: 1, TeamID: 5, Code: "<?php\necho 1;\n", CodeSize: 14},
+		2: {SubmissionID: 2, GameID: 1, TeamID: 5, Code: "<?php\necho 42;\n", CodeSize: 15},
+		3: {SubmissionID: 3, GameID: 1, TeamID: 6, Code: "<?php\necho 4;\n", CodeSize: 14},
+		4: {SubmissionID: 4, GameID: 2, TeamID: 5, Code: "<?php\n", CodeSize: 6},
+	}
+	row, ok := submissions[submissionID]
+	if !ok {
+		return db.Submission{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func TestGetGameSubmissionDiff_Finished(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Hour), Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+		getSubmissionByIDFunc: diffTestSubmissions,
+	})
+
+	resp, err := h.GetGameSubmissionDiff(context.Background(), GetGameSubmissionDiffRequestObject{
+		GameID: 1,
+		Params: GetGameSubmissionDiffParams{From: 1, To: 2},
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameSubmissionDiff200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	wantUnified := "--- submission/1\n+++ submission/2\n@@ -1,2 +1,2 @@\n <?php\n-echo 1;\n+echo 42;\n"
+	if okResp.Unified != wantUnified {
+		t.Errorf("unified = %q, want %q", okResp.Unified, wantUnified)
+	}
+	if len(okResp.Lines) != 3 {
+		t.Fatalf("got %d lines, want 3", len(okResp.Lines))
+	}
+	if l := okResp.Lines[2]; l.Op != Insert || l.Text != "echo 42;" || l.OldLine != nil || l.NewLine == nil || *l.NewLine != 2 {
+		t.Errorf("unexpected inserted line: %+v", l)
+	}
+	if okResp.SizeDelta != 1 {
+		t.Errorf("size_delta = %d, want 1", okResp.SizeDelta)
+	}
+}
+
+func TestGetGameSubmissionDiff_Running(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
+				GameID:          1,
+				StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-time.Minute), Valid: true},
+				DurationSeconds: 600,
+			}, nil
+		},
+		getSubmissionByIDFunc: diffTestSubmissions,
+	})
+	diff := func(from, to int, user *db.User) GetGameSubmissionDiffResponseObject {
+		resp, err := h.GetGameSubmissionDiff(context.Background(), GetGameSubmissionDiffRequestObject{
+			GameID: 1,
+			Params: GetGameSubmissionDiffParams{From: from, To: to},
+		}, user)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	if _, ok := diff(1, 2, &db.User{UserID: 5}).(GetGameSubmissionDiff200JSONResponse); !ok {
+		t.Error("expected players to diff their own submissions")
+	}
+	if _, ok := diff(1, 3, &db.User{UserID: 5}).(GetGameSubmissionDiff403JSONResponse); !ok {
+		t.Error("expected submissions of other teams to be hidden until the game finishes")
+	}
+	if _, ok := diff(1, 2, nil).(GetGameSubmissionDiff403JSONResponse); !ok {
+		t.Error("expected spectators to wait until the game finishes")
+	}
+	if _, ok := diff(1, 3, &db.User{UserID: 7, IsAdmin: true}).(GetGameSubmissionDiff200JSONResponse); !ok {
+		t.Error("expected admins to diff any submissions")
+	}
+	if _, ok := diff(1, 4, &db.User{UserID: 5}).(GetGameSubmissionDiff404JSONResponse); !ok {
+		t.Error("expected submissions of other games not to be found")
+	}
+}
+
+func TestGetGameWatchTeams_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+	resp, err := h.GetGameWatchTeams(context.Background(), GetGameWatchTeamsRequestObject{GameID: 999}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetGameWatchTeams404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetGameWatchTeams_WithMembers(t *testing.T) {
+	h := newTestHandler(&mockQuerier{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{GameID: 1, GameType: "multiplayer"}, nil
+		},
+		listTeamsFunc: func(_ context.Context, _ int32) ([]db.GameTeam, error) {
+			return []db.GameTeam{
+				{TeamID: 1, GameID: 1, DisplayName: "Team AB"},
+				{TeamID: 2, GameID: 1, DisplayName: "Empty"},
+			}, nil
+		},
+		listTeamMembersFunc: func(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+			return []db.ListTeamMembersRow{
+				{TeamID: 1, User: db.User{UserID: 1, Username: "a"}},
+				{TeamID: 1, User: db.User{UserID: 2, Username: "b"}},
+			}, nil
+		},
+	})
+	resp, err := h.GetGameWatchTeams(context.Background(), GetGameWatchTeamsRequestObject{GameID: 1}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetGameWatchTeams200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(okResp.Teams) != 2 {
+		t.Fatalf("expected 2 teams, got %d", len(okResp.Teams))
+	}
+	team := okResp.Teams[0]
+	if team.TeamID != 1 || team.DisplayName != "Team AB" || len(team.Members) != 2 || team.Members[1].Username != "b" {
+		t.Errorf("unexpected team: %+v", team)
+	}
+	if len(okResp.Teams[1].Members) != 0 {
+		t.Errorf("expected no members, got %+v", okResp.Teams[1].Members)
+	}
+}
+
+func TestGetGameWatchLatestStates_Empty(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+	user := &db.User{UserID: 1}
+	resp, err := h.GetGameWatchLatestStates(context.Background(), GetGameWatchLatestStatesRequestObject{GameID: 1}, user)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
 	okResp, ok := resp.(GetGameWatchLatestStates200JSONResponse)
 	if !ok {
 		t.Fatalf("expected 200 response, got %T", resp)
@@ -806,8 +1842,8 @@ func TestGetTournament_WithEntriesAndMatches(t *testing.T) {
 				{TournamentMatchID: 3, TournamentID: 1, Round: 1, Position: 0, GameID: nil},
 			}, nil
 		},
-		getGameByIDFunc: func(_ context.Context, _ int32) (db.GetGameByIDRow, error) {
-			return db.GetGameByIDRow{
+		getGameByIDFunc: func(_ context.Context, _ int32) (db.Game, error) {
+			return db.Game{
 				GameID:    10,
 				StartedAt: pgtype.Timestamp{Valid: false},
 			}, nil
@@ -903,3 +1939,128 @@ func TestGetTournament_ByeAutoWinner(t *testing.T) {
 		t.Error("final player1: expected Alice (bye winner)")
 	}
 }
+
+func TestGetRatings(t *testing.T) {
+	first, second := int32(1), int32(2)
+	h := newTestHandler(&mockQuerier{
+		listRatedUsersFunc: func(_ context.Context) ([]db.User, error) {
+			return []db.User{
+				{UserID: 3, Username: "alice", Rating: 1620, RatingRank: &first},
+				{UserID: 4, Username: "bob", Rating: 1480, RatingRank: &second},
+			}, nil
+		},
+	})
+	resp, err := h.GetRatings(context.Background(), GetRatingsRequestObject{}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetRatings200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if len(okResp.Users) != 2 {
+		t.Fatalf("expected 2 users, got %d", len(okResp.Users))
+	}
+	if okResp.Users[0].Rating != 1620 {
+		t.Errorf("expected rating 1620, got %d", okResp.Users[0].Rating)
+	}
+	if rank, _ := okResp.Users[1].RatingRank.Get(); rank != 2 {
+		t.Errorf("expected rating rank 2, got %d", rank)
+	}
+}
+
+func TestGetUserRatingHistory(t *testing.T) {
+	ratedAt := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	h := newTestHandler(&mockQuerier{
+		getUserByIDFunc: func(_ context.Context, userID int32) (db.User, error) {
+			return db.User{UserID: userID, Username: "alice", Rating: 1516}, nil
+		},
+		listRatingHistoryByUserIDFunc: func(_ context.Context, _ int32) ([]db.ListRatingHistoryByUserIDRow, error) {
+			return []db.ListRatingHistoryByUserIDRow{
+				{
+					GameID:          7,
+					GameRank:        1,
+					RatingBefore:    1500,
+					RatingAfter:     1516,
+					CreatedAt:       pgtype.Timestamp{Time: ratedAt, Valid: true},
+					GameDisplayName: "Round 1",
+				},
+			}, nil
+		},
+	})
+	resp, err := h.GetUserRatingHistory(context.Background(), GetUserRatingHistoryRequestObject{UserID: 3}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	okResp, ok := resp.(GetUserRatingHistory200JSONResponse)
+	if !ok {
+		t.Fatalf("expected 200 response, got %T", resp)
+	}
+	if okResp.User.Rating != 1516 {
+		t.Errorf("expected rating 1516, got %d", okResp.User.Rating)
+	}
+	if !okResp.User.RatingRank.IsNull() {
+		t.Errorf("expected no rating rank, got %v", okResp.User.RatingRank)
+	}
+	if len(okResp.History) != 1 {
+		t.Fatalf("expected 1 change, got %d", len(okResp.History))
+	}
+	change := okResp.History[0]
+	if change.GameDisplayName != "Round 1" || change.RatingAfter != 1516 {
+		t.Errorf("unexpected change: %+v", change)
+	}
+	if change.RatedAt != ratedAt.Unix() {
+		t.Errorf("expected rated_at %d, got %d", ratedAt.Unix(), change.RatedAt)
+	}
+}
+
+func TestGetUserRatingHistory_NotFound(t *testing.T) {
+	h := newTestHandler(&mockQuerier{})
+	resp, err := h.GetUserRatingHistory(context.Background(), GetUserRatingHistoryRequestObject{UserID: 999}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := resp.(GetUserRatingHistory404JSONResponse); !ok {
+		t.Errorf("expected 404 response, got %T", resp)
+	}
+}
+
+func TestGetQualifyingStageRanking(t *testing.T) {
+	stage := func(isPublic bool) func(context.Context, int32) (db.QualifyingStage, error) {
+		return func(_ context.Context, stageID int32) (db.QualifyingStage, error) {
+			return db.QualifyingStage{
+				QualifyingStageID: stageID,
+				DisplayName:       "Online Qualifying",
+				IsPublic:          isPublic,
+				MissingPolicy:     "exclude",
+			}, nil
+		}
+	}
+	tests := []struct {
+		name     string
+		isPublic bool
+		user     *db.User
+		found    bool
+	}{
+		{name: "public stage, guest", isPublic: true, user: nil, found: true},
+		{name: "private stage, guest", isPublic: false, user: nil, found: false},
+		{name: "private stage, player", isPublic: false, user: &db.User{UserID: 1}, found: false},
+		{name: "private stage, admin", isPublic: false, user: &db.User{UserID: 1, IsAdmin: true}, found: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestHandler(&mockQuerier{getQualifyingStageByIDFunc: stage(tt.isPublic)})
+			resp, err := h.GetQualifyingStageRanking(context.Background(), GetQualifyingStageRankingRequestObject{StageID: 2}, tt.user)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			okResp, ok := resp.(GetQualifyingStageRanking200JSONResponse)
+			if ok != tt.found {
+				t.Fatalf("unexpected response %T", resp)
+			}
+			if ok && (okResp.Stage.StageID != 2 || okResp.Stage.MissingPolicy != Exclude) {
+				t.Errorf("unexpected stage: %+v", okResp.Stage)
+			}
+		})
+	}
+}
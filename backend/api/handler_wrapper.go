@@ -8,6 +8,8 @@ import (
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/session"
 	"albatross-2026-backend/tournament"
 )
@@ -18,11 +20,13 @@ type HandlerWrapper struct {
 	impl Handler
 }
 
-func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, auth AuthenticatorInterface, queries db.Querier, conf *config.Config) *HandlerWrapper {
+func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, ratingSvc *rating.Service, qualifyingSvc *qualifying.Service, auth AuthenticatorInterface, queries db.Querier, conf *config.Config) *HandlerWrapper {
 	return &HandlerWrapper{
 		impl: Handler{
 			gameSvc:       gameSvc,
 			tournamentSvc: tournamentSvc,
+			ratingSvc:     ratingSvc,
+			qualifyingSvc: qualifyingSvc,
 			auth:          auth,
 			conf:          conf,
 			q:             queries,
@@ -35,6 +39,16 @@ func (h *HandlerWrapper) GetGame(ctx context.Context, request GetGameRequestObje
 	return h.impl.GetGame(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetGamePlayEvents(ctx context.Context, request GetGamePlayEventsRequestObject) (GetGamePlayEventsResponseObject, error) {
+	user, ok := session.GetUserFromContext(ctx)
+	if !ok {
+		return GetGamePlayEvents401JSONResponse{
+			Message: "Unauthorized",
+		}, nil
+	}
+	return h.impl.GetGamePlayEvents(ctx, request, user)
+}
+
 func (h *HandlerWrapper) GetGamePlayLatestState(ctx context.Context, request GetGamePlayLatestStateRequestObject) (GetGamePlayLatestStateResponseObject, error) {
 	user, ok := session.GetUserFromContext(ctx)
 	if !ok {
@@ -45,6 +59,16 @@ func (h *HandlerWrapper) GetGamePlayLatestState(ctx context.Context, request Get
 	return h.impl.GetGamePlayLatestState(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetGamePlaySubmission(ctx context.Context, request GetGamePlaySubmissionRequestObject) (GetGamePlaySubmissionResponseObject, error) {
+	user, ok := session.GetUserFromContext(ctx)
+	if !ok {
+		return GetGamePlaySubmission401JSONResponse{
+			Message: "Unauthorized",
+		}, nil
+	}
+	return h.impl.GetGamePlaySubmission(ctx, request, user)
+}
+
 func (h *HandlerWrapper) GetGamePlaySubmissions(ctx context.Context, request GetGamePlaySubmissionsRequestObject) (GetGamePlaySubmissionsResponseObject, error) {
 	user, ok := session.GetUserFromContext(ctx)
 	if !ok {
@@ -55,6 +79,16 @@ func (h *HandlerWrapper) GetGamePlaySubmissions(ctx context.Context, request Get
 	return h.impl.GetGamePlaySubmissions(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetGameSubmissionDiff(ctx context.Context, request GetGameSubmissionDiffRequestObject) (GetGameSubmissionDiffResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetGameSubmissionDiff(ctx, request, user)
+}
+
+func (h *HandlerWrapper) GetGameWatchEvents(ctx context.Context, request GetGameWatchEventsRequestObject) (GetGameWatchEventsResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetGameWatchEvents(ctx, request, user)
+}
+
 func (h *HandlerWrapper) GetGameWatchLatestStates(ctx context.Context, request GetGameWatchLatestStatesRequestObject) (GetGameWatchLatestStatesResponseObject, error) {
 	user, _ := session.GetUserFromContext(ctx)
 	return h.impl.GetGameWatchLatestStates(ctx, request, user)
@@ -65,6 +99,16 @@ func (h *HandlerWrapper) GetGameWatchRanking(ctx context.Context, request GetGam
 	return h.impl.GetGameWatchRanking(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetGameWatchReplay(ctx context.Context, request GetGameWatchReplayRequestObject) (GetGameWatchReplayResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetGameWatchReplay(ctx, request, user)
+}
+
+func (h *HandlerWrapper) GetGameWatchTeams(ctx context.Context, request GetGameWatchTeamsRequestObject) (GetGameWatchTeamsResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetGameWatchTeams(ctx, request, user)
+}
+
 func (h *HandlerWrapper) GetGames(ctx context.Context, request GetGamesRequestObject) (GetGamesResponseObject, error) {
 	user, _ := session.GetUserFromContext(ctx)
 	return h.impl.GetGames(ctx, request, user)
@@ -80,11 +124,31 @@ func (h *HandlerWrapper) GetMe(ctx context.Context, request GetMeRequestObject)
 	return h.impl.GetMe(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetQualifyingStageRanking(ctx context.Context, request GetQualifyingStageRankingRequestObject) (GetQualifyingStageRankingResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetQualifyingStageRanking(ctx, request, user)
+}
+
+func (h *HandlerWrapper) GetQualifyingStages(ctx context.Context, request GetQualifyingStagesRequestObject) (GetQualifyingStagesResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetQualifyingStages(ctx, request, user)
+}
+
+func (h *HandlerWrapper) GetRatings(ctx context.Context, request GetRatingsRequestObject) (GetRatingsResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetRatings(ctx, request, user)
+}
+
 func (h *HandlerWrapper) GetTournament(ctx context.Context, request GetTournamentRequestObject) (GetTournamentResponseObject, error) {
 	user, _ := session.GetUserFromContext(ctx)
 	return h.impl.GetTournament(ctx, request, user)
 }
 
+func (h *HandlerWrapper) GetUserRatingHistory(ctx context.Context, request GetUserRatingHistoryRequestObject) (GetUserRatingHistoryResponseObject, error) {
+	user, _ := session.GetUserFromContext(ctx)
+	return h.impl.GetUserRatingHistory(ctx, request, user)
+}
+
 func (h *HandlerWrapper) PostGamePlayCode(ctx context.Context, request PostGamePlayCodeRequestObject) (PostGamePlayCodeResponseObject, error) {
 	user, ok := session.GetUserFromContext(ctx)
 	if !ok {
@@ -95,6 +159,16 @@ func (h *HandlerWrapper) PostGamePlayCode(ctx context.Context, request PostGameP
 	return h.impl.PostGamePlayCode(ctx, request, user)
 }
 
+func (h *HandlerWrapper) PostGamePlayRun(ctx context.Context, request PostGamePlayRunRequestObject) (PostGamePlayRunResponseObject, error) {
+	user, ok := session.GetUserFromContext(ctx)
+	if !ok {
+		return PostGamePlayRun401JSONResponse{
+			Message: "Unauthorized",
+		}, nil
+	}
+	return h.impl.PostGamePlayRun(ctx, request, user)
+}
+
 func (h *HandlerWrapper) PostGamePlaySubmit(ctx context.Context, request PostGamePlaySubmitRequestObject) (PostGamePlaySubmitResponseObject, error) {
 	user, ok := session.GetUserFromContext(ctx)
 	if !ok {
@@ -0,0 +1,158 @@
+// Package checker judges whether the output of a submission is correct. Each
+// problem chooses one of the checkers by name.
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+const (
+	Exact          = "exact"
+	Tokens         = "tokens"
+	Float          = "float"
+	UnorderedLines = "unordered_lines"
+	// Special is a checker program written by the problem setter. It is run
+	// by the worker instead of in process, so New does not support it.
+	Special = "special"
+)
+
+// Default is the checker used when a problem does not specify one.
+const Default = Exact
+
+// Names lists all the checkers in the order shown to admins.
+var Names = []string{Exact, Tokens, Float, UnorderedLines, Special}
+
+var ErrUnknownChecker = errors.New("unknown checker")
+
+type Checker interface {
+	Check(expected, actual string) bool
+}
+
+// New returns the checker of the name. epsilon is the tolerance used by the
+// float checker and is ignored by the others.
+func New(name string, epsilon float64) (Checker, error) {
+	switch name {
+	case Exact:
+		return exactChecker{}, nil
+	case Tokens:
+		return tokensChecker{}, nil
+	case Float:
+		return floatChecker{epsilon: epsilon}, nil
+	case UnorderedLines:
+		return unorderedLinesChecker{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownChecker, name)
+	}
+}
+
+// IsValid reports whether name is one of Names.
+func IsValid(name string) bool {
+	return slices.Contains(Names, name)
+}
+
+var newlineRe = regexp.MustCompile(`\r\n|\r`)
+
+// normalizeOutput trims the output and unifies its newlines to LF.
+func normalizeOutput(s string) string {
+	return newlineRe.ReplaceAllString(strings.TrimSpace(s), "\n")
+}
+
+// exactChecker compares the outputs as text, ignoring leading and trailing
+// whitespace and the kind of newlines.
+type exactChecker struct{}
+
+func (exactChecker) Check(expected, actual string) bool {
+	return normalizeOutput(expected) == normalizeOutput(actual)
+}
+
+// tokensChecker compares the outputs token by token, ignoring the amount and
+// kind of whitespace between them.
+type tokensChecker struct{}
+
+func (tokensChecker) Check(expected, actual string) bool {
+	return slices.Equal(strings.Fields(expected), strings.Fields(actual))
+}
+
+// floatChecker compares the outputs token by token like tokensChecker, but
+// numeric tokens are accepted if either the absolute or the relative error is
+// within epsilon.
+type floatChecker struct {
+	epsilon float64
+}
+
+func (c floatChecker) Check(expected, actual string) bool {
+	expectedTokens := strings.Fields(expected)
+	actualTokens := strings.Fields(actual)
+	if len(expectedTokens) != len(actualTokens) {
+		return false
+	}
+	for i, e := range expectedTokens {
+		a := actualTokens[i]
+		if e == a {
+			continue
+		}
+		ev, err := strconv.ParseFloat(e, 64)
+		if err != nil {
+			return false
+		}
+		av, err := strconv.ParseFloat(a, 64)
+		if err != nil || math.IsNaN(av) {
+			return false
+		}
+		diff := math.Abs(ev - av)
+		if diff > c.epsilon && diff > c.epsilon*math.Abs(ev) {
+			return false
+		}
+	}
+	return true
+}
+
+// unorderedLinesChecker compares the outputs as multisets of lines. Trailing
+// whitespace of each line is ignored.
+type unorderedLinesChecker struct{}
+
+func (unorderedLinesChecker) Check(expected, actual string) bool {
+	return slices.Equal(sortedLines(expected), sortedLines(actual))
+}
+
+func sortedLines(s string) []string {
+	lines := strings.Split(normalizeOutput(s), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " \t")
+	}
+	slices.Sort(lines)
+	return lines
+}
+
+type specialJudgeInput struct {
+	Input    string `json:"input"`
+	Expected string `json:"expected"`
+	Actual   string `json:"actual"`
+}
+
+// SpecialJudgeStdin builds the stdin passed to a special judge program: a JSON
+// object with "input", "expected" and "actual" keys.
+func SpecialJudgeStdin(input, expected, actual string) (string, error) {
+	b, err := json.Marshal(specialJudgeInput{
+		Input:    input,
+		Expected: expected,
+		Actual:   actual,
+	})
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// IsSpecialJudgeAccepted reports whether a special judge program accepted the
+// output. It does so by printing "AC".
+func IsSpecialJudgeAccepted(stdout string) bool {
+	return strings.TrimSpace(stdout) == "AC"
+}
@@ -0,0 +1,114 @@
+package checker
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestNew_UnknownChecker(t *testing.T) {
+	_, err := New("regex", 0)
+	if !errors.Is(err, ErrUnknownChecker) {
+		t.Errorf("expected ErrUnknownChecker, got %v", err)
+	}
+}
+
+func TestNew_Special(t *testing.T) {
+	if _, err := New(Special, 0); !errors.Is(err, ErrUnknownChecker) {
+		t.Errorf("expected ErrUnknownChecker, got %v", err)
+	}
+	if !IsValid(Special) {
+		t.Error("IsValid(Special) = false, want true")
+	}
+}
+
+func TestNormalizeOutput(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"empty", "", ""},
+		{"no changes needed", "hello", "hello"},
+		{"trim spaces", "  hello  ", "hello"},
+		{"CRLF to LF", "line1\r\nline2", "line1\nline2"},
+		{"CR to LF", "line1\rline2", "line1\nline2"},
+		{"mixed", "  line1\r\nline2\r  ", "line1\nline2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := normalizeOutput(tt.input)
+			if got != tt.want {
+				t.Errorf("normalizeOutput(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		name     string
+		checker  string
+		epsilon  float64
+		expected string
+		actual   string
+		want     bool
+	}{
+		{name: "exact match", checker: Exact, expected: "hello", actual: "hello", want: true},
+		{name: "exact trailing newline ignored", checker: Exact, expected: "hello\n", actual: "hello", want: true},
+		{name: "exact CRLF normalized", checker: Exact, expected: "hello\r\n", actual: "hello\n", want: true},
+		{name: "exact mismatch", checker: Exact, expected: "hello", actual: "world", want: false},
+		{name: "exact multiline match", checker: Exact, expected: "line1\nline2", actual: "line1\nline2\n", want: true},
+		{name: "exact inner spaces matter", checker: Exact, expected: "1 2", actual: "1  2", want: false},
+
+		{name: "tokens inner spaces ignored", checker: Tokens, expected: "1 2\n3", actual: "1  2 3\n", want: true},
+		{name: "tokens mismatch", checker: Tokens, expected: "1 2 3", actual: "1 2", want: false},
+
+		{name: "float absolute error", checker: Float, epsilon: 1e-6, expected: "0.0000001", actual: "0", want: true},
+		{name: "float relative error", checker: Float, epsilon: 1e-6, expected: "1000000000", actual: "1000000500", want: true},
+		{name: "float too far", checker: Float, epsilon: 1e-6, expected: "1.5", actual: "1.501", want: false},
+		{name: "float non-numeric tokens exact", checker: Float, epsilon: 1e-6, expected: "Yes 1.0", actual: "Yes 1", want: true},
+		{name: "float non-numeric mismatch", checker: Float, epsilon: 1e-6, expected: "Yes 1.0", actual: "No 1.0", want: false},
+		{name: "float NaN rejected", checker: Float, epsilon: 1e-6, expected: "1.0", actual: "NaN", want: false},
+		{name: "float token count mismatch", checker: Float, epsilon: 1e-6, expected: "1 2", actual: "1", want: false},
+
+		{name: "unordered lines shuffled", checker: UnorderedLines, expected: "a\nb\nc", actual: "c\na\nb\n", want: true},
+		{name: "unordered lines trailing spaces", checker: UnorderedLines, expected: "a\nb", actual: "b  \na", want: true},
+		{name: "unordered lines duplicates matter", checker: UnorderedLines, expected: "a\na\nb", actual: "a\nb\nb", want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := New(tt.checker, tt.epsilon)
+			if err != nil {
+				t.Fatalf("New(%q) returned error: %v", tt.checker, err)
+			}
+			got := c.Check(tt.expected, tt.actual)
+			if got != tt.want {
+				t.Errorf("Check(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSpecialJudgeStdin(t *testing.T) {
+	stdin, err := SpecialJudgeStdin("3\n", "1 2\n", "2 1\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal([]byte(stdin), &got); err != nil {
+		t.Fatalf("stdin is not valid JSON: %v", err)
+	}
+	if got["input"] != "3\n" || got["expected"] != "1 2\n" || got["actual"] != "2 1\n" {
+		t.Errorf("unexpected stdin: %v", got)
+	}
+}
+
+func TestIsSpecialJudgeAccepted(t *testing.T) {
+	if !IsSpecialJudgeAccepted("AC\n") {
+		t.Error(`IsSpecialJudgeAccepted("AC\n") = false, want true`)
+	}
+	if IsSpecialJudgeAccepted("WA\n") {
+		t.Error(`IsSpecialJudgeAccepted("WA\n") = true, want false`)
+	}
+}
@@ -0,0 +1,122 @@
+// Command problempkg imports and exports problem packages against the database
+// configured by the same environment variables as the server.
+//
+//	problempkg export -id 3 -o problems/hello      writes a directory
+//	problempkg export -id 3 -o hello.zip           writes a zip
+//	problempkg import problems/hello               reads a directory or a zip
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+
+	"albatross-2026-backend/config"
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/problempkg"
+)
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: problempkg export -id <problem_id> -o <dir or .zip>")
+	fmt.Fprintln(os.Stderr, "       problempkg import <dir or .zip>")
+	os.Exit(2)
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+	}
+
+	conf, err := config.NewConfigFromEnv()
+	if err != nil {
+		slog.Error("failed to load env", "error", err)
+		os.Exit(1)
+	}
+	ctx := context.Background()
+	dbDSN := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", conf.DBHost, conf.DBPort, conf.DBUser, conf.DBPassword, conf.DBName)
+	connPool, err := pgxpool.New(ctx, dbDSN)
+	if err != nil {
+		slog.Error("failed to connect to db", "error", err)
+		os.Exit(1)
+	}
+	defer connPool.Close()
+	queries := db.New(connPool)
+	svc := problempkg.NewService(queries, db.NewTxManager(connPool, queries))
+
+	switch os.Args[1] {
+	case "export":
+		err = runExport(ctx, svc, os.Args[2:])
+	case "import":
+		err = runImport(ctx, svc, os.Args[2:])
+	default:
+		usage()
+	}
+	if err != nil {
+		slog.Error("failed to "+os.Args[1]+" problem", "error", err)
+		os.Exit(1)
+	}
+}
+
+func runExport(ctx context.Context, svc *problempkg.Service, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	problemID := fs.Int("id", 0, "ID of the problem to export")
+	out := fs.String("o", "", "directory to write, or a path ending with .zip")
+	_ = fs.Parse(args)
+	if *problemID == 0 || *out == "" {
+		usage()
+	}
+
+	pkg, err := svc.Export(ctx, *problemID)
+	if err != nil {
+		return err
+	}
+	if !strings.HasSuffix(*out, ".zip") {
+		return pkg.WriteDir(*out)
+	}
+	f, err := os.Create(*out)
+	if err != nil {
+		return err
+	}
+	if err := pkg.WriteZip(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func runImport(ctx context.Context, svc *problempkg.Service, args []string) error {
+	if len(args) != 1 {
+		usage()
+	}
+	pkg, err := readPackage(args[0])
+	if err != nil {
+		return err
+	}
+	problemID, err := svc.Import(ctx, pkg)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("imported %q as problem %d with %d testcases\n", pkg.Title, problemID, len(pkg.Testcases))
+	return nil
+}
+
+func readPackage(path string) (problempkg.Package, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return problempkg.Package{}, err
+	}
+	if info.IsDir() {
+		return problempkg.Read(os.DirFS(path))
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return problempkg.Package{}, err
+	}
+	defer f.Close()
+	return problempkg.ReadZip(f, info.Size())
+}
@@ -0,0 +1,61 @@
+// Package codediff compares versions of code. Patch stores successive versions
+// compactly as the part that changed from the previous one, and Lines and
+// Unified show the changes line by line.
+package codediff
+
+import (
+	"errors"
+	"unicode/utf8"
+)
+
+// ErrMismatch tells that a patch does not fit the code it is applied to.
+var ErrMismatch = errors.New("patch does not fit the code")
+
+// Patch turns one version of code into the next. The next version is the
+// first Prefix bytes of the previous one, followed by Insert, followed by its
+// last Suffix bytes.
+type Patch struct {
+	Prefix int
+	Suffix int
+	Insert string
+}
+
+// Diff returns the patch from old to new. Editing code usually touches a
+// single region, so only the bytes between the common prefix and the common
+// suffix are kept. The region never splits a UTF-8 sequence, so Insert is
+// valid UTF-8 as long as new is.
+func Diff(old, new string) Patch {
+	prefix := 0
+	for prefix < len(old) && prefix < len(new) && old[prefix] == new[prefix] {
+		prefix++
+	}
+	for prefix > 0 && (!runeBoundary(old, prefix) || !runeBoundary(new, prefix)) {
+		prefix--
+	}
+
+	suffix := 0
+	for suffix < len(old)-prefix && suffix < len(new)-prefix && old[len(old)-1-suffix] == new[len(new)-1-suffix] {
+		suffix++
+	}
+	for suffix > 0 && !runeBoundary(new, len(new)-suffix) {
+		suffix--
+	}
+
+	return Patch{
+		Prefix: prefix,
+		Suffix: suffix,
+		Insert: new[prefix : len(new)-suffix],
+	}
+}
+
+// Apply returns the version of code that follows old.
+func (p Patch) Apply(old string) (string, error) {
+	if p.Prefix < 0 || p.Suffix < 0 || p.Prefix+p.Suffix > len(old) {
+		return "", ErrMismatch
+	}
+	return old[:p.Prefix] + p.Insert + old[len(old)-p.Suffix:], nil
+}
+
+func runeBoundary(s string, i int) bool {
+	return i == len(s) || utf8.RuneStart(s[i])
+}
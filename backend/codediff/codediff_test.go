@@ -0,0 +1,98 @@
+package codediff
+
+import (
+	"errors"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDiff(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want Patch
+	}{
+		{
+			name: "from empty",
+			old:  "",
+			new:  "<?php echo 1;",
+			want: Patch{Insert: "<?php echo 1;"},
+		},
+		{
+			name: "insertion",
+			old:  "<?php echo 1;",
+			new:  "<?php echo 12;",
+			want: Patch{Prefix: 12, Suffix: 1, Insert: "2"},
+		},
+		{
+			name: "deletion",
+			old:  "<?php echo 12;",
+			new:  "<?php echo 1;",
+			want: Patch{Prefix: 12, Suffix: 1},
+		},
+		{
+			name: "unchanged",
+			old:  "<?php echo 1;",
+			new:  "<?php echo 1;",
+			want: Patch{Prefix: 13},
+		},
+		{
+			name: "repeated characters",
+			old:  "aaa",
+			new:  "aaaa",
+			want: Patch{Prefix: 3, Insert: "a"},
+		},
+		{
+			name: "does not split multi-byte characters",
+			old:  "print(\"あ\")",
+			new:  "print(\"い\")",
+			want: Patch{Prefix: 7, Suffix: 2, Insert: "い"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Diff(tt.old, tt.new)
+			if got != tt.want {
+				t.Errorf("Diff() = %+v, want %+v", got, tt.want)
+			}
+			if !utf8.ValidString(got.Insert) {
+				t.Errorf("Insert %q is not valid UTF-8", got.Insert)
+			}
+			applied, err := got.Apply(tt.old)
+			if err != nil {
+				t.Fatalf("Apply() returned error: %v", err)
+			}
+			if applied != tt.new {
+				t.Errorf("Apply() = %q, want %q", applied, tt.new)
+			}
+		})
+	}
+}
+
+func TestApply_Sequence(t *testing.T) {
+	versions := []string{"", "<?php", "<?php echo 1;", "<?php\necho 1;", "<?=1;", ""}
+	var patches []Patch
+	for i := 1; i < len(versions); i++ {
+		patches = append(patches, Diff(versions[i-1], versions[i]))
+	}
+
+	code := ""
+	for i, p := range patches {
+		var err error
+		code, err = p.Apply(code)
+		if err != nil {
+			t.Fatalf("patch %d: unexpected error: %v", i, err)
+		}
+		if code != versions[i+1] {
+			t.Errorf("after patch %d: got %q, want %q", i, code, versions[i+1])
+		}
+	}
+}
+
+func TestApply_Mismatch(t *testing.T) {
+	p := Patch{Prefix: 3, Suffix: 3, Insert: "x"}
+	if _, err := p.Apply("abcd"); !errors.Is(err, ErrMismatch) {
+		t.Errorf("expected ErrMismatch, got %v", err)
+	}
+}
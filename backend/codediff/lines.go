@@ -0,0 +1,205 @@
+package codediff
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Op tells how a line changed between two versions of code.
+type Op string
+
+const (
+	OpEqual  Op = "equal"
+	OpInsert Op = "insert"
+	OpDelete Op = "delete"
+)
+
+// Line is a line of a line-by-line diff. Text does not include the line
+// break. OldLine and NewLine are 1-based line numbers in the old and the new
+// code, and 0 for lines that do not appear in it.
+type Line struct {
+	Op      Op
+	Text    string
+	OldLine int
+	NewLine int
+}
+
+// unifiedContext is the number of unchanged lines shown around changes in a
+// unified diff.
+const unifiedContext = 3
+
+// Lines returns the shortest line-by-line diff from old to new. A last line
+// without a line break differs from the same line with one.
+func Lines(old, new string) []Line {
+	a, b := splitLines(old), splitLines(new)
+	var lines []Line
+	for _, e := range shortestEdit(a, b) {
+		var text string
+		if e.op == OpInsert {
+			text = b[e.y]
+		} else {
+			text = a[e.x]
+		}
+		line := Line{Op: e.op, Text: strings.TrimSuffix(text, "\n")}
+		if e.op != OpInsert {
+			line.OldLine = e.x + 1
+		}
+		if e.op != OpDelete {
+			line.NewLine = e.y + 1
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+// Unified returns the diff from old to new in the unified format, labeling
+// the versions with oldName and newName. It is empty if nothing changed.
+func Unified(oldName, newName, old, new string) string {
+	lines := Lines(old, new)
+	if !slices.ContainsFunc(lines, func(l Line) bool { return l.Op != OpEqual }) {
+		return ""
+	}
+	oldCount, newCount := len(splitLines(old)), len(splitLines(new))
+	oldNoEOL := old != "" && !strings.HasSuffix(old, "\n")
+	newNoEOL := new != "" && !strings.HasSuffix(new, "\n")
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- %s\n+++ %s\n", oldName, newName)
+	for i := 0; i < len(lines); {
+		if lines[i].Op == OpEqual {
+			i++
+			continue
+		}
+		// Changes closer than twice the context share a hunk.
+		start := max(0, i-unifiedContext)
+		end := i + 1
+		for j := i + 1; j < len(lines) && j-end < 2*unifiedContext; j++ {
+			if lines[j].Op != OpEqual {
+				end = j + 1
+			}
+		}
+		end = min(len(lines), end+unifiedContext)
+
+		hunk := lines[start:end]
+		oldStart, oldLen := hunkRange(lines[:start], hunk, OpInsert)
+		newStart, newLen := hunkRange(lines[:start], hunk, OpDelete)
+		fmt.Fprintf(&b, "@@ -%d,%d +%d,%d @@\n", oldStart, oldLen, newStart, newLen)
+		for _, l := range hunk {
+			switch l.Op {
+			case OpEqual:
+				b.WriteString(" ")
+			case OpDelete:
+				b.WriteString("-")
+			case OpInsert:
+				b.WriteString("+")
+			}
+			b.WriteString(l.Text)
+			b.WriteString("\n")
+			if (l.Op != OpInsert && oldNoEOL && l.OldLine == oldCount) ||
+				(l.Op == OpInsert && newNoEOL && l.NewLine == newCount) {
+				b.WriteString("\\ No newline at end of file\n")
+			}
+		}
+		i = end
+	}
+	return b.String()
+}
+
+// hunkRange returns the start and the length of a hunk in one of the versions,
+// skipping the lines of the other one. As in diff(1), an empty range starts at
+// the line before it.
+func hunkRange(before, hunk []Line, skip Op) (int, int) {
+	count := func(lines []Line) int {
+		n := 0
+		for _, l := range lines {
+			if l.Op != skip {
+				n++
+			}
+		}
+		return n
+	}
+	start, length := count(before), count(hunk)
+	if length > 0 {
+		start++
+	}
+	return start, length
+}
+
+func splitLines(s string) []string {
+	if s == "" {
+		return nil
+	}
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+type edit struct {
+	op   Op
+	x, y int
+}
+
+// shortestEdit finds the shortest edit script from a to b with the algorithm
+// of Myers, "An O(ND) Difference Algorithm and Its Variations".
+func shortestEdit(a, b []string) []edit {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	var edits []edit
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		var prevK int
+		if k == -d || (k != d && v[offset+k-1] < v[offset+k+1]) {
+			prevK = k + 1
+		} else {
+			prevK = k - 1
+		}
+		prevX := v[offset+prevK]
+		prevY := prevX - prevK
+		for x > prevX && y > prevY {
+			x--
+			y--
+			edits = append(edits, edit{op: OpEqual, x: x, y: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			edits = append(edits, edit{op: OpInsert, x: x, y: y})
+		} else {
+			x--
+			edits = append(edits, edit{op: OpDelete, x: x, y: y})
+		}
+	}
+	slices.Reverse(edits)
+	return edits
+}
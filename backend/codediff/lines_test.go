@@ -0,0 +1,144 @@
+package codediff
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestLines(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want []Line
+	}{
+		{
+			name: "both empty",
+			old:  "",
+			new:  "",
+			want: nil,
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "a\nb\n",
+			want: []Line{
+				{Op: OpInsert, Text: "a", NewLine: 1},
+				{Op: OpInsert, Text: "b", NewLine: 2},
+			},
+		},
+		{
+			name: "changed line",
+			old:  "a\nb\nc\n",
+			new:  "a\nx\nc\n",
+			want: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpDelete, Text: "b", OldLine: 2},
+				{Op: OpInsert, Text: "x", NewLine: 2},
+				{Op: OpEqual, Text: "c", OldLine: 3, NewLine: 3},
+			},
+		},
+		{
+			name: "inserted and deleted lines",
+			old:  "a\nb\nc\nd\n",
+			new:  "b\nc\ne\nd\n",
+			want: []Line{
+				{Op: OpDelete, Text: "a", OldLine: 1},
+				{Op: OpEqual, Text: "b", OldLine: 2, NewLine: 1},
+				{Op: OpEqual, Text: "c", OldLine: 3, NewLine: 2},
+				{Op: OpInsert, Text: "e", NewLine: 3},
+				{Op: OpEqual, Text: "d", OldLine: 4, NewLine: 4},
+			},
+		},
+		{
+			name: "missing line break at the end",
+			old:  "a\nb",
+			new:  "a\nb\n",
+			want: []Line{
+				{Op: OpEqual, Text: "a", OldLine: 1, NewLine: 1},
+				{Op: OpDelete, Text: "b", OldLine: 2},
+				{Op: OpInsert, Text: "b", NewLine: 2},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Lines(tt.old, tt.new)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Lines() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "unchanged",
+			old:  "a\nb\n",
+			new:  "a\nb\n",
+			want: "",
+		},
+		{
+			name: "from empty",
+			old:  "",
+			new:  "a\n",
+			want: "--- old\n+++ new\n@@ -0,0 +1,1 @@\n+a\n",
+		},
+		{
+			name: "changed line with context",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			new:  "1\n2\n3\n4\nx\n6\n7\n8\n9\n",
+			want: "--- old\n+++ new\n@@ -2,7 +2,7 @@\n 2\n 3\n 4\n-5\n+x\n 6\n 7\n 8\n",
+		},
+		{
+			name: "distant changes in separate hunks",
+			old:  "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n",
+			new:  "x\n2\n3\n4\n5\n6\n7\n8\n9\ny\n",
+			want: "--- old\n+++ new\n" +
+				"@@ -1,4 +1,4 @@\n-1\n+x\n 2\n 3\n 4\n" +
+				"@@ -7,4 +7,4 @@\n 7\n 8\n 9\n-10\n+y\n",
+		},
+		{
+			name: "missing line break at the end",
+			old:  "a\nb",
+			new:  "a\nc",
+			want: "--- old\n+++ new\n@@ -1,2 +1,2 @@\n a\n-b\n\\ No newline at end of file\n+c\n\\ No newline at end of file\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Unified("old", "new", tt.old, tt.new)
+			if got != tt.want {
+				t.Errorf("Unified() =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLines_Large(t *testing.T) {
+	var old, new strings.Builder
+	for i := range 2000 {
+		old.WriteString("line\n")
+		if i%100 == 0 {
+			new.WriteString("changed\n")
+		} else {
+			new.WriteString("line\n")
+		}
+	}
+	deleted := 0
+	for _, l := range Lines(old.String(), new.String()) {
+		if l.Op == OpDelete {
+			deleted++
+		}
+	}
+	if deleted != 20 {
+		t.Errorf("deleted = %d, want 20", deleted)
+	}
+}
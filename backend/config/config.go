@@ -3,8 +3,12 @@ package config
 import (
 	"fmt"
 	"os"
+	"strings"
 )
 
+// defaultWorkers are the workers started by the compose files.
+const defaultWorkers = "php=http://worker-php:80;swift=http://worker-swift:80"
+
 type Config struct {
 	DBHost     string
 	DBPort     string
@@ -13,6 +17,10 @@ type Config struct {
 	DBName     string
 	BasePath   string
 	IsLocal    bool
+	// Workers maps each language to the base URLs of the workers that run it.
+	Workers map[string][]string
+	// WorkerSelection is how a worker is chosen among those for a language.
+	WorkerSelection string
 }
 
 func NewConfigFromEnv() (*Config, error) {
@@ -42,13 +50,53 @@ func NewConfigFromEnv() (*Config, error) {
 	}
 	isLocalStr, exists := os.LookupEnv("ALBATROSS_IS_LOCAL")
 	isLocal := exists && isLocalStr == "1"
+	workersStr, exists := os.LookupEnv("ALBATROSS_WORKERS")
+	if !exists {
+		workersStr = defaultWorkers
+	}
+	workers, err := parseWorkers(workersStr)
+	if err != nil {
+		return nil, fmt.Errorf("ALBATROSS_WORKERS: %w", err)
+	}
+	workerSelection := os.Getenv("ALBATROSS_WORKER_SELECTION")
 	return &Config{
-		DBHost:     dbHost,
-		DBPort:     dbPort,
-		DBUser:     dbUser,
-		DBPassword: dbPassword,
-		DBName:     dbName,
-		BasePath:   basePath,
-		IsLocal:    isLocal,
+		DBHost:          dbHost,
+		DBPort:          dbPort,
+		DBUser:          dbUser,
+		DBPassword:      dbPassword,
+		DBName:          dbName,
+		BasePath:        basePath,
+		IsLocal:         isLocal,
+		Workers:         workers,
+		WorkerSelection: workerSelection,
 	}, nil
 }
+
+// parseWorkers parses the workers given as "php=URL,URL;swift=URL", i.e. the
+// languages separated by semicolons, each with its comma-separated URLs.
+func parseWorkers(s string) (map[string][]string, error) {
+	workers := make(map[string][]string)
+	for entry := range strings.SplitSeq(s, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		language, urls, ok := strings.Cut(entry, "=")
+		language = strings.TrimSpace(language)
+		if !ok || language == "" {
+			return nil, fmt.Errorf("invalid entry %q", entry)
+		}
+		if _, dup := workers[language]; dup {
+			return nil, fmt.Errorf("%s is given twice", language)
+		}
+		for url := range strings.SplitSeq(urls, ",") {
+			if url = strings.TrimSpace(url); url != "" {
+				workers[language] = append(workers[language], url)
+			}
+		}
+		if len(workers[language]) == 0 {
+			return nil, fmt.Errorf("no workers for %s", language)
+		}
+	}
+	return workers, nil
+}
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"reflect"
 	"testing"
 )
 
@@ -137,3 +138,54 @@ func TestNewConfigFromEnv_MissingRequired(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConfigFromEnv_Workers(t *testing.T) {
+	t.Setenv("ALBATROSS_DB_HOST", "localhost")
+	t.Setenv("ALBATROSS_DB_PORT", "5432")
+	t.Setenv("ALBATROSS_DB_USER", "user")
+	t.Setenv("ALBATROSS_DB_PASSWORD", "pass")
+	t.Setenv("ALBATROSS_DB_NAME", "testdb")
+	t.Setenv("ALBATROSS_BASE_PATH", "/app")
+
+	conf, err := NewConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{
+		"php":   {"http://worker-php:80"},
+		"swift": {"http://worker-swift:80"},
+	}
+	if !reflect.DeepEqual(conf.Workers, want) {
+		t.Errorf("expected default Workers %v, got %v", want, conf.Workers)
+	}
+
+	t.Setenv("ALBATROSS_WORKERS", "php=http://a:8080, http://b:8080 ;swift=http://localhost:9000")
+	t.Setenv("ALBATROSS_WORKER_SELECTION", "least_busy")
+	conf, err = NewConfigFromEnv()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = map[string][]string{
+		"php":   {"http://a:8080", "http://b:8080"},
+		"swift": {"http://localhost:9000"},
+	}
+	if !reflect.DeepEqual(conf.Workers, want) {
+		t.Errorf("expected Workers %v, got %v", want, conf.Workers)
+	}
+	if conf.WorkerSelection != "least_busy" {
+		t.Errorf("expected WorkerSelection 'least_busy', got %q", conf.WorkerSelection)
+	}
+}
+
+func TestParseWorkers_Invalid(t *testing.T) {
+	for _, s := range []string{
+		"http://worker-php:80",
+		"=http://worker-php:80",
+		"php=",
+		"php=http://a;php=http://b",
+	} {
+		if _, err := parseWorkers(s); err == nil {
+			t.Errorf("parseWorkers(%q): expected error", s)
+		}
+	}
+}
@@ -8,6 +8,19 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+type CodeSnapshot struct {
+	CodeSnapshotID int32
+	GameID         int32
+	TeamID         int32
+	UserID         int32
+	ProblemID      int32
+	IsSubmission   bool
+	KeepPrefix     int32
+	KeepSuffix     int32
+	Inserted       string
+	CreatedAt      pgtype.Timestamp
+}
+
 type Game struct {
 	GameID          int32
 	GameType        string
@@ -16,7 +29,26 @@ type Game struct {
 	DurationSeconds int32
 	CreatedAt       pgtype.Timestamp
 	StartedAt       pgtype.Timestamp
-	ProblemID       int32
+	PausedAt        pgtype.Timestamp
+	OverrideState   *string
+	FreezeSeconds   int32
+	RevealedUntil   pgtype.Timestamp
+	TieBreak        string
+	UnsolvedPenalty int32
+	AllowPractice   bool
+	RatedAt         pgtype.Timestamp
+}
+
+type GameLifecycleEvent struct {
+	GameLifecycleEventID int32
+	GameID               int32
+	Action               string
+	FromState            string
+	ToState              string
+	StartedAt            pgtype.Timestamp
+	DurationSeconds      int32
+	UserID               *int32
+	CreatedAt            pgtype.Timestamp
 }
 
 type GameMainPlayer struct {
@@ -24,20 +56,130 @@ type GameMainPlayer struct {
 	UserID int32
 }
 
+type GameProblem struct {
+	GameID    int32
+	ProblemID int32
+	Position  int32
+}
+
 type GameState struct {
 	GameID                int32
-	UserID                int32
+	TeamID                int32
+	ProblemID             int32
+	Language              string
 	Code                  string
 	Status                string
 	BestScoreSubmissionID *int32
 }
 
+type GameTeam struct {
+	TeamID      int32
+	GameID      int32
+	DisplayName string
+}
+
+type GameTeamMember struct {
+	TeamID int32
+	GameID int32
+	UserID int32
+}
+
 type Problem struct {
-	ProblemID   int32
-	Title       string
-	Description string
-	Language    string
-	SampleCode  string
+	ProblemID      int32
+	Title          string
+	Description    string
+	Language       string
+	SampleCode     string
+	Scoring        string
+	Checker        string
+	CheckerEpsilon float64
+	CheckerCode    string
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
+	JudgeChangedAt pgtype.Timestamp
+}
+
+type ProblemLanguage struct {
+	ProblemID  int32
+	Language   string
+	SampleCode string
+}
+
+type ProblemValidation struct {
+	ProblemValidationID int32
+	ProblemID           int32
+	Status              string
+	TaskCount           int32
+	UserID              *int32
+	CreatedAt           pgtype.Timestamp
+	FinishedAt          pgtype.Timestamp
+}
+
+type ProblemValidationResult struct {
+	ProblemValidationID int32
+	ReferenceSolutionID int32
+	TestcaseID          int32
+	Status              string
+	Stdout              string
+	Stderr              string
+}
+
+type QualifyingStage struct {
+	QualifyingStageID int32
+	DisplayName       string
+	IsPublic          bool
+	MissingPolicy     string
+	MissingPenalty    int32
+	CreatedAt         pgtype.Timestamp
+}
+
+type QualifyingStageGame struct {
+	QualifyingStageID int32
+	GameID            int32
+	Position          int32
+	Weight            float64
+}
+
+type RatingHistory struct {
+	RatingHistoryID int32
+	UserID          int32
+	GameID          int32
+	GameRank        int32
+	RatingBefore    int32
+	RatingAfter     int32
+	CreatedAt       pgtype.Timestamp
+}
+
+type ReferenceSolution struct {
+	ReferenceSolutionID int32
+	ProblemID           int32
+	Name                string
+	Code                string
+	CreatedAt           pgtype.Timestamp
+}
+
+type Rejudge struct {
+	RejudgeID  int32
+	ProblemID  int32
+	Reason     string
+	UserID     *int32
+	CreatedAt  pgtype.Timestamp
+	FinishedAt pgtype.Timestamp
+}
+
+type RejudgeRanking struct {
+	RejudgeID int32
+	GameID    int32
+	TeamID    int32
+	Phase     string
+	Rank      int32
+	Score     int32
+}
+
+type RejudgeSubmission struct {
+	RejudgeID    int32
+	SubmissionID int32
+	StatusBefore string
 }
 
 type Session struct {
@@ -50,18 +192,25 @@ type Session struct {
 type Submission struct {
 	SubmissionID int32
 	GameID       int32
+	TeamID       int32
 	UserID       int32
+	ProblemID    int32
+	Language     string
 	Code         string
 	CodeSize     int32
 	Status       string
+	IsPractice   bool
 	CreatedAt    pgtype.Timestamp
 }
 
 type Testcase struct {
-	TestcaseID int32
-	ProblemID  int32
-	Stdin      string
-	Stdout     string
+	TestcaseID     int32
+	ProblemID      int32
+	Stdin          string
+	Stdout         string
+	IsSample       bool
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
 }
 
 type TestcaseResult struct {
@@ -71,9 +220,24 @@ type TestcaseResult struct {
 	Status           string
 	Stdout           string
 	Stderr           string
+	TimeMs           *int32
+	MemoryBytes      *int64
 	CreatedAt        pgtype.Timestamp
 }
 
+type TestcaseRevision struct {
+	TestcaseRevisionID int32
+	TestcaseID         int32
+	ProblemID          int32
+	Action             string
+	Stdin              string
+	Stdout             string
+	IsSample           bool
+	AffectsResults     bool
+	UserID             *int32
+	CreatedAt          pgtype.Timestamp
+}
+
 type Tournament struct {
 	TournamentID int32
 	DisplayName  string
@@ -104,6 +268,8 @@ type User struct {
 	IconPath    *string
 	IsAdmin     bool
 	Label       *string
+	Rating      int32
+	RatingRank  *int32
 	CreatedAt   pgtype.Timestamp
 }
 
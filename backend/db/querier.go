@@ -6,38 +6,72 @@ package db
 
 import (
 	"context"
+
+	"github.com/jackc/pgx/v5/pgtype"
 )
 
 type Querier interface {
+	AddGameProblem(ctx context.Context, arg AddGameProblemParams) error
 	AddMainPlayer(ctx context.Context, arg AddMainPlayerParams) error
+	AddQualifyingStageGame(ctx context.Context, arg AddQualifyingStageGameParams) error
+	AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (int64, error)
 	AggregateTestcaseResults(ctx context.Context, submissionID int32) (string, error)
+	CreateCodeSnapshot(ctx context.Context, arg CreateCodeSnapshotParams) error
 	CreateGame(ctx context.Context, arg CreateGameParams) (int32, error)
+	CreateGameLifecycleEvent(ctx context.Context, arg CreateGameLifecycleEventParams) error
 	CreateProblem(ctx context.Context, arg CreateProblemParams) (int32, error)
+	CreateProblemLanguage(ctx context.Context, arg CreateProblemLanguageParams) error
+	CreateProblemValidation(ctx context.Context, arg CreateProblemValidationParams) (int32, error)
+	CreateProblemValidationResult(ctx context.Context, arg CreateProblemValidationResultParams) error
+	CreateQualifyingStage(ctx context.Context, arg CreateQualifyingStageParams) (int32, error)
+	CreateRatingHistory(ctx context.Context, arg CreateRatingHistoryParams) error
+	CreateReferenceSolution(ctx context.Context, arg CreateReferenceSolutionParams) (int32, error)
+	CreateRejudge(ctx context.Context, arg CreateRejudgeParams) (int32, error)
+	CreateRejudgeRanking(ctx context.Context, arg CreateRejudgeRankingParams) error
+	CreateRejudgeSubmission(ctx context.Context, arg CreateRejudgeSubmissionParams) error
 	CreateSession(ctx context.Context, arg CreateSessionParams) error
 	CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (int32, error)
+	CreateTeam(ctx context.Context, arg CreateTeamParams) (int32, error)
 	CreateTestcase(ctx context.Context, arg CreateTestcaseParams) (int32, error)
 	CreateTestcaseResult(ctx context.Context, arg CreateTestcaseResultParams) error
+	CreateTestcaseRevision(ctx context.Context, arg CreateTestcaseRevisionParams) error
 	CreateTournament(ctx context.Context, arg CreateTournamentParams) (int32, error)
 	CreateTournamentEntry(ctx context.Context, arg CreateTournamentEntryParams) error
 	CreateTournamentMatch(ctx context.Context, arg CreateTournamentMatchParams) error
 	CreateUser(ctx context.Context, username string) (int32, error)
 	CreateUserAuth(ctx context.Context, arg CreateUserAuthParams) error
+	DeleteAllRatingHistory(ctx context.Context) error
 	DeleteExpiredSessions(ctx context.Context) error
+	DeleteProblemLanguages(ctx context.Context, problemID int32) error
+	DeleteReferenceSolution(ctx context.Context, referenceSolutionID int32) error
 	DeleteSession(ctx context.Context, sessionID string) error
 	DeleteTestcase(ctx context.Context, testcaseID int32) error
 	DeleteTestcaseResultsBySubmissionID(ctx context.Context, submissionID int32) error
+	DeleteTestcaseResultsByTestcaseID(ctx context.Context, testcaseID int32) error
 	DeleteTournamentEntries(ctx context.Context, tournamentID int32) error
 	DeleteTournamentMatches(ctx context.Context, tournamentID int32) error
-	GetGameByID(ctx context.Context, gameID int32) (GetGameByIDRow, error)
+	FailProblemValidation(ctx context.Context, problemValidationID int32) error
+	FinishProblemValidationIfJudged(ctx context.Context, problemValidationID int32) (int64, error)
+	FinishRejudge(ctx context.Context, rejudgeID int32) error
+	GetCodeForSnapshot(ctx context.Context, arg GetCodeForSnapshotParams) (GetCodeForSnapshotRow, error)
+	GetGameByID(ctx context.Context, gameID int32) (Game, error)
+	GetGameLifecycleForUpdate(ctx context.Context, gameID int32) (GetGameLifecycleForUpdateRow, error)
+	GetGameProblem(ctx context.Context, arg GetGameProblemParams) (Problem, error)
+	GetLatestProblemValidation(ctx context.Context, problemID int32) (ProblemValidation, error)
 	GetLatestState(ctx context.Context, arg GetLatestStateParams) (GetLatestStateRow, error)
-	GetLatestStatesOfMainPlayers(ctx context.Context, gameID int32) ([]GetLatestStatesOfMainPlayersRow, error)
+	GetLatestStatesOfMainPlayers(ctx context.Context, arg GetLatestStatesOfMainPlayersParams) ([]GetLatestStatesOfMainPlayersRow, error)
 	GetLatestSubmissionsByGameID(ctx context.Context, gameID int32) ([]Submission, error)
 	GetProblemByID(ctx context.Context, problemID int32) (Problem, error)
-	GetQualifyingRanking(ctx context.Context, arg GetQualifyingRankingParams) ([]GetQualifyingRankingRow, error)
+	GetProblemBySubmissionID(ctx context.Context, submissionID int32) (Problem, error)
+	GetProblemJudgeChangedAt(ctx context.Context, problemID int32) (pgtype.Timestamp, error)
+	GetQualifyingStageByID(ctx context.Context, qualifyingStageID int32) (QualifyingStage, error)
 	GetRanking(ctx context.Context, gameID int32) ([]GetRankingRow, error)
+	GetReferenceSolutionByID(ctx context.Context, referenceSolutionID int32) (ReferenceSolution, error)
+	GetRejudgeByID(ctx context.Context, rejudgeID int32) (Rejudge, error)
 	GetSubmissionByID(ctx context.Context, submissionID int32) (Submission, error)
 	GetSubmissionsByGameID(ctx context.Context, gameID int32) ([]Submission, error)
-	GetSubmissionsByGameIDAndUserID(ctx context.Context, arg GetSubmissionsByGameIDAndUserIDParams) ([]Submission, error)
+	GetSubmissionsByGameIDAndTeamID(ctx context.Context, arg GetSubmissionsByGameIDAndTeamIDParams) ([]Submission, error)
+	GetTeamByUserID(ctx context.Context, arg GetTeamByUserIDParams) (GameTeam, error)
 	GetTestcaseByID(ctx context.Context, testcaseID int32) (Testcase, error)
 	GetTestcaseResultsBySubmissionID(ctx context.Context, submissionID int32) ([]TestcaseResult, error)
 	GetTournamentByID(ctx context.Context, tournamentID int32) (Tournament, error)
@@ -46,32 +80,71 @@ type Querier interface {
 	GetUserBySession(ctx context.Context, sessionID string) (User, error)
 	GetUserIDByUsername(ctx context.Context, username string) (int32, error)
 	ListAllGames(ctx context.Context) ([]Game, error)
+	ListBestPracticeSubmissions(ctx context.Context, arg ListBestPracticeSubmissionsParams) ([]ListBestPracticeSubmissionsRow, error)
+	ListBestSubmissionsAt(ctx context.Context, arg ListBestSubmissionsAtParams) ([]ListBestSubmissionsAtRow, error)
+	ListCodeSnapshots(ctx context.Context, arg ListCodeSnapshotsParams) ([]CodeSnapshot, error)
+	ListFinishedRejudgeIDsBySubmissionID(ctx context.Context, submissionID int32) ([]int32, error)
+	ListGameLifecycleEvents(ctx context.Context, gameID int32) ([]GameLifecycleEvent, error)
+	ListGameProblems(ctx context.Context, dollar_1 []int32) ([]ListGameProblemsRow, error)
 	ListGameStateIDs(ctx context.Context) ([]ListGameStateIDsRow, error)
+	ListGameStateIDsByProblemID(ctx context.Context, problemID int32) ([]ListGameStateIDsByProblemIDRow, error)
 	ListMainPlayers(ctx context.Context, dollar_1 []int32) ([]ListMainPlayersRow, error)
+	ListProblemLanguages(ctx context.Context, dollar_1 []int32) ([]ProblemLanguage, error)
+	ListProblemValidationResults(ctx context.Context, problemValidationID int32) ([]ProblemValidationResult, error)
 	ListProblems(ctx context.Context) ([]Problem, error)
-	ListPublicGames(ctx context.Context) ([]ListPublicGamesRow, error)
+	ListPublicGames(ctx context.Context) ([]Game, error)
+	ListPublicQualifyingStages(ctx context.Context) ([]QualifyingStage, error)
+	ListQualifyingStageGames(ctx context.Context, qualifyingStageID int32) ([]ListQualifyingStageGamesRow, error)
+	ListQualifyingStages(ctx context.Context) ([]QualifyingStage, error)
+	ListRatedGames(ctx context.Context) ([]Game, error)
+	ListRatedUsers(ctx context.Context) ([]User, error)
+	ListRatingHistoryByUserID(ctx context.Context, userID int32) ([]ListRatingHistoryByUserIDRow, error)
+	ListReferenceSolutionsByProblemID(ctx context.Context, problemID int32) ([]ReferenceSolution, error)
+	ListRejudgeRankings(ctx context.Context, rejudgeID int32) ([]ListRejudgeRankingsRow, error)
+	ListRejudgeSubmissions(ctx context.Context, rejudgeID int32) ([]ListRejudgeSubmissionsRow, error)
+	ListRejudges(ctx context.Context) ([]Rejudge, error)
+	ListStaleSubmissionsByProblemID(ctx context.Context, problemID int32) ([]Submission, error)
 	ListSubmissionIDs(ctx context.Context) ([]int32, error)
+	ListSubmissionsByGameIDAfter(ctx context.Context, arg ListSubmissionsByGameIDAfterParams) ([]Submission, error)
+	ListSubmissionsByProblemID(ctx context.Context, problemID int32) ([]Submission, error)
+	ListSuccessfulSubmissionsAfter(ctx context.Context, arg ListSuccessfulSubmissionsAfterParams) ([]Submission, error)
+	ListTeamMembers(ctx context.Context, gameID int32) ([]ListTeamMembersRow, error)
+	ListTeams(ctx context.Context, gameID int32) ([]GameTeam, error)
+	ListTestcaseResultsByGameIDAfter(ctx context.Context, arg ListTestcaseResultsByGameIDAfterParams) ([]ListTestcaseResultsByGameIDAfterRow, error)
+	ListTestcaseResultsWithTestcaseBySubmissionID(ctx context.Context, submissionID int32) ([]ListTestcaseResultsWithTestcaseBySubmissionIDRow, error)
+	ListTestcaseRevisionsByProblemID(ctx context.Context, problemID int32) ([]TestcaseRevision, error)
 	ListTestcases(ctx context.Context) ([]Testcase, error)
-	ListTestcasesByGameID(ctx context.Context, gameID int32) ([]Testcase, error)
 	ListTestcasesByProblemID(ctx context.Context, problemID int32) ([]Testcase, error)
 	ListTournamentEntries(ctx context.Context, tournamentID int32) ([]ListTournamentEntriesRow, error)
 	ListTournamentMatches(ctx context.Context, tournamentID int32) ([]TournamentMatch, error)
 	ListTournaments(ctx context.Context) ([]Tournament, error)
+	ListUnratedGames(ctx context.Context) ([]Game, error)
 	ListUsers(ctx context.Context) ([]User, error)
+	RemoveAllGameProblems(ctx context.Context, gameID int32) error
 	RemoveAllMainPlayers(ctx context.Context, gameID int32) error
+	RemoveAllQualifyingStageGames(ctx context.Context, qualifyingStageID int32) error
+	RemoveAllTeamMembers(ctx context.Context, gameID int32) error
+	RemoveAllTeams(ctx context.Context, gameID int32) error
+	ResetUserRatings(ctx context.Context, rating int32) error
 	SyncGameStateBestScoreSubmission(ctx context.Context, arg SyncGameStateBestScoreSubmissionParams) error
 	UpdateCode(ctx context.Context, arg UpdateCodeParams) error
 	UpdateCodeAndStatus(ctx context.Context, arg UpdateCodeAndStatusParams) error
 	UpdateGame(ctx context.Context, arg UpdateGameParams) error
-	UpdateGameStartedAt(ctx context.Context, arg UpdateGameStartedAtParams) error
+	UpdateGameLifecycle(ctx context.Context, arg UpdateGameLifecycleParams) error
+	UpdateGameRatedAt(ctx context.Context, gameID int32) error
+	UpdateGameRevealedUntil(ctx context.Context, arg UpdateGameRevealedUntilParams) error
 	UpdateGameStateStatus(ctx context.Context, arg UpdateGameStateStatusParams) error
 	UpdateProblem(ctx context.Context, arg UpdateProblemParams) error
+	UpdateQualifyingStage(ctx context.Context, arg UpdateQualifyingStageParams) error
+	UpdateRatingRanks(ctx context.Context) error
+	UpdateSubmissionCodeSize(ctx context.Context, arg UpdateSubmissionCodeSizeParams) error
 	UpdateSubmissionStatus(ctx context.Context, arg UpdateSubmissionStatusParams) error
 	UpdateTestcase(ctx context.Context, arg UpdateTestcaseParams) error
 	UpdateTournament(ctx context.Context, arg UpdateTournamentParams) error
 	UpdateTournamentMatchGame(ctx context.Context, arg UpdateTournamentMatchGameParams) error
 	UpdateUser(ctx context.Context, arg UpdateUserParams) error
 	UpdateUserIconPath(ctx context.Context, arg UpdateUserIconPathParams) error
+	UpdateUserRating(ctx context.Context, arg UpdateUserRatingParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -11,6 +11,22 @@ import (
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
+const addGameProblem = `-- name: AddGameProblem :exec
+INSERT INTO game_problems (game_id, problem_id, position)
+VALUES ($1, $2, $3)
+`
+
+type AddGameProblemParams struct {
+	GameID    int32
+	ProblemID int32
+	Position  int32
+}
+
+func (q *Queries) AddGameProblem(ctx context.Context, arg AddGameProblemParams) error {
+	_, err := q.db.Exec(ctx, addGameProblem, arg.GameID, arg.ProblemID, arg.Position)
+	return err
+}
+
 const addMainPlayer = `-- name: AddMainPlayer :exec
 INSERT INTO game_main_players (game_id, user_id)
 VALUES ($1, $2)
@@ -26,12 +42,53 @@ func (q *Queries) AddMainPlayer(ctx context.Context, arg AddMainPlayerParams) er
 	return err
 }
 
+const addQualifyingStageGame = `-- name: AddQualifyingStageGame :exec
+INSERT INTO qualifying_stage_games (qualifying_stage_id, game_id, position, weight)
+VALUES ($1, $2, $3, $4)
+`
+
+type AddQualifyingStageGameParams struct {
+	QualifyingStageID int32
+	GameID            int32
+	Position          int32
+	Weight            float64
+}
+
+func (q *Queries) AddQualifyingStageGame(ctx context.Context, arg AddQualifyingStageGameParams) error {
+	_, err := q.db.Exec(ctx, addQualifyingStageGame,
+		arg.QualifyingStageID,
+		arg.GameID,
+		arg.Position,
+		arg.Weight,
+	)
+	return err
+}
+
+const addTeamMember = `-- name: AddTeamMember :execrows
+INSERT INTO game_team_members (team_id, game_id, user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (game_id, user_id) DO NOTHING
+`
+
+type AddTeamMemberParams struct {
+	TeamID int32
+	GameID int32
+	UserID int32
+}
+
+func (q *Queries) AddTeamMember(ctx context.Context, arg AddTeamMemberParams) (int64, error) {
+	result, err := q.db.Exec(ctx, addTeamMember, arg.TeamID, arg.GameID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const aggregateTestcaseResults = `-- name: AggregateTestcaseResults :one
 SELECT
     CASE
         WHEN COUNT(*) < (SELECT COUNT(*) FROM testcases WHERE problem_id =
-                         (SELECT problem_id FROM games WHERE game_id =
-                          (SELECT game_id FROM submissions AS s WHERE s.submission_id = $1)))
+                         (SELECT problem_id FROM submissions AS s WHERE s.submission_id = $1))
         THEN 'running'
         WHEN COUNT(CASE WHEN r.status = 'internal_error' THEN 1 END) > 0 THEN 'internal_error'
         WHEN COUNT(CASE WHEN r.status = 'timeout'        THEN 1 END) > 0 THEN 'timeout'
@@ -51,8 +108,38 @@ func (q *Queries) AggregateTestcaseResults(ctx context.Context, submissionID int
 	return status, err
 }
 
+const createCodeSnapshot = `-- name: CreateCodeSnapshot :exec
+INSERT INTO code_snapshots (game_id, team_id, user_id, problem_id, is_submission, keep_prefix, keep_suffix, inserted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateCodeSnapshotParams struct {
+	GameID       int32
+	TeamID       int32
+	UserID       int32
+	ProblemID    int32
+	IsSubmission bool
+	KeepPrefix   int32
+	KeepSuffix   int32
+	Inserted     string
+}
+
+func (q *Queries) CreateCodeSnapshot(ctx context.Context, arg CreateCodeSnapshotParams) error {
+	_, err := q.db.Exec(ctx, createCodeSnapshot,
+		arg.GameID,
+		arg.TeamID,
+		arg.UserID,
+		arg.ProblemID,
+		arg.IsSubmission,
+		arg.KeepPrefix,
+		arg.KeepSuffix,
+		arg.Inserted,
+	)
+	return err
+}
+
 const createGame = `-- name: CreateGame :one
-INSERT INTO games (game_type, is_public, display_name, duration_seconds, problem_id)
+INSERT INTO games (game_type, is_public, display_name, duration_seconds, unsolved_penalty)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING game_id
 `
@@ -62,7 +149,7 @@ type CreateGameParams struct {
 	IsPublic        bool
 	DisplayName     string
 	DurationSeconds int32
-	ProblemID       int32
+	UnsolvedPenalty int32
 }
 
 func (q *Queries) CreateGame(ctx context.Context, arg CreateGameParams) (int32, error) {
@@ -71,24 +158,58 @@ func (q *Queries) CreateGame(ctx context.Context, arg CreateGameParams) (int32,
 		arg.IsPublic,
 		arg.DisplayName,
 		arg.DurationSeconds,
-		arg.ProblemID,
+		arg.UnsolvedPenalty,
 	)
 	var game_id int32
 	err := row.Scan(&game_id)
 	return game_id, err
 }
 
+const createGameLifecycleEvent = `-- name: CreateGameLifecycleEvent :exec
+INSERT INTO game_lifecycle_events (game_id, action, from_state, to_state, started_at, duration_seconds, user_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
+`
+
+type CreateGameLifecycleEventParams struct {
+	GameID          int32
+	Action          string
+	FromState       string
+	ToState         string
+	StartedAt       pgtype.Timestamp
+	DurationSeconds int32
+	UserID          *int32
+}
+
+func (q *Queries) CreateGameLifecycleEvent(ctx context.Context, arg CreateGameLifecycleEventParams) error {
+	_, err := q.db.Exec(ctx, createGameLifecycleEvent,
+		arg.GameID,
+		arg.Action,
+		arg.FromState,
+		arg.ToState,
+		arg.StartedAt,
+		arg.DurationSeconds,
+		arg.UserID,
+	)
+	return err
+}
+
 const createProblem = `-- name: CreateProblem :one
-INSERT INTO problems (title, description, language, sample_code)
-VALUES ($1, $2, $3, $4)
+INSERT INTO problems (title, description, language, sample_code, scoring, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING problem_id
 `
 
 type CreateProblemParams struct {
-	Title       string
-	Description string
-	Language    string
-	SampleCode  string
+	Title          string
+	Description    string
+	Language       string
+	SampleCode     string
+	Scoring        string
+	Checker        string
+	CheckerEpsilon float64
+	CheckerCode    string
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
 }
 
 func (q *Queries) CreateProblem(ctx context.Context, arg CreateProblemParams) (int32, error) {
@@ -97,12 +218,209 @@ func (q *Queries) CreateProblem(ctx context.Context, arg CreateProblemParams) (i
 		arg.Description,
 		arg.Language,
 		arg.SampleCode,
+		arg.Scoring,
+		arg.Checker,
+		arg.CheckerEpsilon,
+		arg.CheckerCode,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMib,
 	)
 	var problem_id int32
 	err := row.Scan(&problem_id)
 	return problem_id, err
 }
 
+const createProblemLanguage = `-- name: CreateProblemLanguage :exec
+INSERT INTO problem_languages (problem_id, language, sample_code)
+VALUES ($1, $2, $3)
+`
+
+type CreateProblemLanguageParams struct {
+	ProblemID  int32
+	Language   string
+	SampleCode string
+}
+
+func (q *Queries) CreateProblemLanguage(ctx context.Context, arg CreateProblemLanguageParams) error {
+	_, err := q.db.Exec(ctx, createProblemLanguage, arg.ProblemID, arg.Language, arg.SampleCode)
+	return err
+}
+
+const createProblemValidation = `-- name: CreateProblemValidation :one
+INSERT INTO problem_validations (problem_id, task_count, user_id)
+VALUES ($1, $2, $3)
+RETURNING problem_validation_id
+`
+
+type CreateProblemValidationParams struct {
+	ProblemID int32
+	TaskCount int32
+	UserID    *int32
+}
+
+func (q *Queries) CreateProblemValidation(ctx context.Context, arg CreateProblemValidationParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createProblemValidation, arg.ProblemID, arg.TaskCount, arg.UserID)
+	var problem_validation_id int32
+	err := row.Scan(&problem_validation_id)
+	return problem_validation_id, err
+}
+
+const createProblemValidationResult = `-- name: CreateProblemValidationResult :exec
+INSERT INTO problem_validation_results (problem_validation_id, reference_solution_id, testcase_id, status, stdout, stderr)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (problem_validation_id, reference_solution_id, testcase_id) DO NOTHING
+`
+
+type CreateProblemValidationResultParams struct {
+	ProblemValidationID int32
+	ReferenceSolutionID int32
+	TestcaseID          int32
+	Status              string
+	Stdout              string
+	Stderr              string
+}
+
+func (q *Queries) CreateProblemValidationResult(ctx context.Context, arg CreateProblemValidationResultParams) error {
+	_, err := q.db.Exec(ctx, createProblemValidationResult,
+		arg.ProblemValidationID,
+		arg.ReferenceSolutionID,
+		arg.TestcaseID,
+		arg.Status,
+		arg.Stdout,
+		arg.Stderr,
+	)
+	return err
+}
+
+const createQualifyingStage = `-- name: CreateQualifyingStage :one
+INSERT INTO qualifying_stages (display_name, is_public, missing_policy, missing_penalty)
+VALUES ($1, $2, $3, $4)
+RETURNING qualifying_stage_id
+`
+
+type CreateQualifyingStageParams struct {
+	DisplayName    string
+	IsPublic       bool
+	MissingPolicy  string
+	MissingPenalty int32
+}
+
+func (q *Queries) CreateQualifyingStage(ctx context.Context, arg CreateQualifyingStageParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createQualifyingStage,
+		arg.DisplayName,
+		arg.IsPublic,
+		arg.MissingPolicy,
+		arg.MissingPenalty,
+	)
+	var qualifying_stage_id int32
+	err := row.Scan(&qualifying_stage_id)
+	return qualifying_stage_id, err
+}
+
+const createRatingHistory = `-- name: CreateRatingHistory :exec
+INSERT INTO rating_history (user_id, game_id, game_rank, rating_before, rating_after)
+VALUES ($1, $2, $3, $4, $5)
+`
+
+type CreateRatingHistoryParams struct {
+	UserID       int32
+	GameID       int32
+	GameRank     int32
+	RatingBefore int32
+	RatingAfter  int32
+}
+
+func (q *Queries) CreateRatingHistory(ctx context.Context, arg CreateRatingHistoryParams) error {
+	_, err := q.db.Exec(ctx, createRatingHistory,
+		arg.UserID,
+		arg.GameID,
+		arg.GameRank,
+		arg.RatingBefore,
+		arg.RatingAfter,
+	)
+	return err
+}
+
+const createReferenceSolution = `-- name: CreateReferenceSolution :one
+INSERT INTO reference_solutions (problem_id, name, code)
+VALUES ($1, $2, $3)
+RETURNING reference_solution_id
+`
+
+type CreateReferenceSolutionParams struct {
+	ProblemID int32
+	Name      string
+	Code      string
+}
+
+func (q *Queries) CreateReferenceSolution(ctx context.Context, arg CreateReferenceSolutionParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createReferenceSolution, arg.ProblemID, arg.Name, arg.Code)
+	var reference_solution_id int32
+	err := row.Scan(&reference_solution_id)
+	return reference_solution_id, err
+}
+
+const createRejudge = `-- name: CreateRejudge :one
+INSERT INTO rejudges (problem_id, reason, user_id)
+VALUES ($1, $2, $3)
+RETURNING rejudge_id
+`
+
+type CreateRejudgeParams struct {
+	ProblemID int32
+	Reason    string
+	UserID    *int32
+}
+
+func (q *Queries) CreateRejudge(ctx context.Context, arg CreateRejudgeParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createRejudge, arg.ProblemID, arg.Reason, arg.UserID)
+	var rejudge_id int32
+	err := row.Scan(&rejudge_id)
+	return rejudge_id, err
+}
+
+const createRejudgeRanking = `-- name: CreateRejudgeRanking :exec
+INSERT INTO rejudge_rankings (rejudge_id, game_id, team_id, phase, rank, score)
+VALUES ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateRejudgeRankingParams struct {
+	RejudgeID int32
+	GameID    int32
+	TeamID    int32
+	Phase     string
+	Rank      int32
+	Score     int32
+}
+
+func (q *Queries) CreateRejudgeRanking(ctx context.Context, arg CreateRejudgeRankingParams) error {
+	_, err := q.db.Exec(ctx, createRejudgeRanking,
+		arg.RejudgeID,
+		arg.GameID,
+		arg.TeamID,
+		arg.Phase,
+		arg.Rank,
+		arg.Score,
+	)
+	return err
+}
+
+const createRejudgeSubmission = `-- name: CreateRejudgeSubmission :exec
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, status_before)
+VALUES ($1, $2, $3)
+`
+
+type CreateRejudgeSubmissionParams struct {
+	RejudgeID    int32
+	SubmissionID int32
+	StatusBefore string
+}
+
+func (q *Queries) CreateRejudgeSubmission(ctx context.Context, arg CreateRejudgeSubmissionParams) error {
+	_, err := q.db.Exec(ctx, createRejudgeSubmission, arg.RejudgeID, arg.SubmissionID, arg.StatusBefore)
+	return err
+}
+
 const createSession = `-- name: CreateSession :exec
 INSERT INTO sessions (session_id, user_id, expires_at) VALUES ($1, $2, $3)
 `
@@ -119,52 +437,88 @@ func (q *Queries) CreateSession(ctx context.Context, arg CreateSessionParams) er
 }
 
 const createSubmission = `-- name: CreateSubmission :one
-INSERT INTO submissions (game_id, user_id, code, code_size, status)
-VALUES ($1, $2, $3, $4, 'running')
+INSERT INTO submissions (game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'running', $8)
 RETURNING submission_id
 `
 
 type CreateSubmissionParams struct {
-	GameID   int32
-	UserID   int32
-	Code     string
-	CodeSize int32
+	GameID     int32
+	TeamID     int32
+	UserID     int32
+	ProblemID  int32
+	Language   string
+	Code       string
+	CodeSize   int32
+	IsPractice bool
 }
 
 func (q *Queries) CreateSubmission(ctx context.Context, arg CreateSubmissionParams) (int32, error) {
 	row := q.db.QueryRow(ctx, createSubmission,
 		arg.GameID,
+		arg.TeamID,
 		arg.UserID,
+		arg.ProblemID,
+		arg.Language,
 		arg.Code,
 		arg.CodeSize,
+		arg.IsPractice,
 	)
 	var submission_id int32
 	err := row.Scan(&submission_id)
 	return submission_id, err
 }
 
+const createTeam = `-- name: CreateTeam :one
+INSERT INTO game_teams (game_id, display_name)
+VALUES ($1, $2)
+RETURNING team_id
+`
+
+type CreateTeamParams struct {
+	GameID      int32
+	DisplayName string
+}
+
+func (q *Queries) CreateTeam(ctx context.Context, arg CreateTeamParams) (int32, error) {
+	row := q.db.QueryRow(ctx, createTeam, arg.GameID, arg.DisplayName)
+	var team_id int32
+	err := row.Scan(&team_id)
+	return team_id, err
+}
+
 const createTestcase = `-- name: CreateTestcase :one
-INSERT INTO testcases (problem_id, stdin, stdout)
-VALUES ($1, $2, $3)
+INSERT INTO testcases (problem_id, stdin, stdout, is_sample, time_limit_ms, memory_limit_mib)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING testcase_id
 `
 
 type CreateTestcaseParams struct {
-	ProblemID int32
-	Stdin     string
-	Stdout    string
+	ProblemID      int32
+	Stdin          string
+	Stdout         string
+	IsSample       bool
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
 }
 
 func (q *Queries) CreateTestcase(ctx context.Context, arg CreateTestcaseParams) (int32, error) {
-	row := q.db.QueryRow(ctx, createTestcase, arg.ProblemID, arg.Stdin, arg.Stdout)
+	row := q.db.QueryRow(ctx, createTestcase,
+		arg.ProblemID,
+		arg.Stdin,
+		arg.Stdout,
+		arg.IsSample,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMib,
+	)
 	var testcase_id int32
 	err := row.Scan(&testcase_id)
 	return testcase_id, err
 }
 
 const createTestcaseResult = `-- name: CreateTestcaseResult :exec
-INSERT INTO testcase_results (submission_id, testcase_id, status, stdout, stderr)
-VALUES ($1, $2, $3, $4, $5)
+INSERT INTO testcase_results (submission_id, testcase_id, status, stdout, stderr, time_ms, memory_bytes)
+VALUES ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type CreateTestcaseResultParams struct {
@@ -173,6 +527,8 @@ type CreateTestcaseResultParams struct {
 	Status       string
 	Stdout       string
 	Stderr       string
+	TimeMs       *int32
+	MemoryBytes  *int64
 }
 
 func (q *Queries) CreateTestcaseResult(ctx context.Context, arg CreateTestcaseResultParams) error {
@@ -182,6 +538,38 @@ func (q *Queries) CreateTestcaseResult(ctx context.Context, arg CreateTestcaseRe
 		arg.Status,
 		arg.Stdout,
 		arg.Stderr,
+		arg.TimeMs,
+		arg.MemoryBytes,
+	)
+	return err
+}
+
+const createTestcaseRevision = `-- name: CreateTestcaseRevision :exec
+INSERT INTO testcase_revisions (testcase_id, problem_id, action, stdin, stdout, is_sample, affects_results, user_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+`
+
+type CreateTestcaseRevisionParams struct {
+	TestcaseID     int32
+	ProblemID      int32
+	Action         string
+	Stdin          string
+	Stdout         string
+	IsSample       bool
+	AffectsResults bool
+	UserID         *int32
+}
+
+func (q *Queries) CreateTestcaseRevision(ctx context.Context, arg CreateTestcaseRevisionParams) error {
+	_, err := q.db.Exec(ctx, createTestcaseRevision,
+		arg.TestcaseID,
+		arg.ProblemID,
+		arg.Action,
+		arg.Stdin,
+		arg.Stdout,
+		arg.IsSample,
+		arg.AffectsResults,
+		arg.UserID,
 	)
 	return err
 }
@@ -265,6 +653,15 @@ func (q *Queries) CreateUserAuth(ctx context.Context, arg CreateUserAuthParams)
 	return err
 }
 
+const deleteAllRatingHistory = `-- name: DeleteAllRatingHistory :exec
+DELETE FROM rating_history
+`
+
+func (q *Queries) DeleteAllRatingHistory(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, deleteAllRatingHistory)
+	return err
+}
+
 const deleteExpiredSessions = `-- name: DeleteExpiredSessions :exec
 DELETE FROM sessions WHERE expires_at < NOW()
 `
@@ -274,6 +671,26 @@ func (q *Queries) DeleteExpiredSessions(ctx context.Context) error {
 	return err
 }
 
+const deleteProblemLanguages = `-- name: DeleteProblemLanguages :exec
+DELETE FROM problem_languages
+WHERE problem_id = $1
+`
+
+func (q *Queries) DeleteProblemLanguages(ctx context.Context, problemID int32) error {
+	_, err := q.db.Exec(ctx, deleteProblemLanguages, problemID)
+	return err
+}
+
+const deleteReferenceSolution = `-- name: DeleteReferenceSolution :exec
+DELETE FROM reference_solutions
+WHERE reference_solution_id = $1
+`
+
+func (q *Queries) DeleteReferenceSolution(ctx context.Context, referenceSolutionID int32) error {
+	_, err := q.db.Exec(ctx, deleteReferenceSolution, referenceSolutionID)
+	return err
+}
+
 const deleteSession = `-- name: DeleteSession :exec
 DELETE FROM sessions WHERE session_id = $1
 `
@@ -302,6 +719,15 @@ func (q *Queries) DeleteTestcaseResultsBySubmissionID(ctx context.Context, submi
 	return err
 }
 
+const deleteTestcaseResultsByTestcaseID = `-- name: DeleteTestcaseResultsByTestcaseID :exec
+DELETE FROM testcase_results WHERE testcase_id = $1
+`
+
+func (q *Queries) DeleteTestcaseResultsByTestcaseID(ctx context.Context, testcaseID int32) error {
+	_, err := q.db.Exec(ctx, deleteTestcaseResultsByTestcaseID, testcaseID)
+	return err
+}
+
 const deleteTournamentEntries = `-- name: DeleteTournamentEntries :exec
 DELETE FROM tournament_entries
 WHERE tournament_id = $1
@@ -322,32 +748,93 @@ func (q *Queries) DeleteTournamentMatches(ctx context.Context, tournamentID int3
 	return err
 }
 
+const failProblemValidation = `-- name: FailProblemValidation :exec
+UPDATE problem_validations
+SET status = 'failure', finished_at = NOW()
+WHERE problem_validation_id = $1 AND status = 'running'
+`
+
+func (q *Queries) FailProblemValidation(ctx context.Context, problemValidationID int32) error {
+	_, err := q.db.Exec(ctx, failProblemValidation, problemValidationID)
+	return err
+}
+
+const finishProblemValidationIfJudged = `-- name: FinishProblemValidationIfJudged :execrows
+UPDATE problem_validations AS v
+SET
+    status = CASE
+        WHEN EXISTS (
+            SELECT 1 FROM problem_validation_results AS r
+            WHERE r.problem_validation_id = v.problem_validation_id AND r.status <> 'success'
+        ) THEN 'failure'
+        ELSE 'success'
+    END,
+    finished_at = NOW()
+WHERE v.problem_validation_id = $1 AND v.status = 'running'
+  AND v.task_count = (
+      SELECT COUNT(*) FROM problem_validation_results AS r
+      WHERE r.problem_validation_id = v.problem_validation_id
+  )
+`
+
+func (q *Queries) FinishProblemValidationIfJudged(ctx context.Context, problemValidationID int32) (int64, error) {
+	result, err := q.db.Exec(ctx, finishProblemValidationIfJudged, problemValidationID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
+const finishRejudge = `-- name: FinishRejudge :exec
+UPDATE rejudges
+SET finished_at = NOW()
+WHERE rejudge_id = $1
+`
+
+func (q *Queries) FinishRejudge(ctx context.Context, rejudgeID int32) error {
+	_, err := q.db.Exec(ctx, finishRejudge, rejudgeID)
+	return err
+}
+
+const getCodeForSnapshot = `-- name: GetCodeForSnapshot :one
+SELECT
+    gs.code,
+    EXISTS (
+        SELECT 1 FROM code_snapshots AS cs
+        WHERE cs.game_id = gs.game_id AND cs.team_id = gs.team_id AND cs.problem_id = gs.problem_id
+    ) AS has_snapshots
+FROM game_states AS gs
+WHERE gs.game_id = $1 AND gs.team_id = $2 AND gs.problem_id = $3
+FOR UPDATE OF gs
+`
+
+type GetCodeForSnapshotParams struct {
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+}
+
+type GetCodeForSnapshotRow struct {
+	Code         string
+	HasSnapshots bool
+}
+
+func (q *Queries) GetCodeForSnapshot(ctx context.Context, arg GetCodeForSnapshotParams) (GetCodeForSnapshotRow, error) {
+	row := q.db.QueryRow(ctx, getCodeForSnapshot, arg.GameID, arg.TeamID, arg.ProblemID)
+	var i GetCodeForSnapshotRow
+	err := row.Scan(&i.Code, &i.HasSnapshots)
+	return i, err
+}
+
 const getGameByID = `-- name: GetGameByID :one
-SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, games.problem_id, problems.problem_id, title, description, language, sample_code FROM games
-JOIN problems ON games.problem_id = problems.problem_id
+SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, paused_at, override_state, freeze_seconds, revealed_until, tie_break, unsolved_penalty, allow_practice, rated_at FROM games
 WHERE games.game_id = $1
 LIMIT 1
 `
 
-type GetGameByIDRow struct {
-	GameID          int32
-	GameType        string
-	IsPublic        bool
-	DisplayName     string
-	DurationSeconds int32
-	CreatedAt       pgtype.Timestamp
-	StartedAt       pgtype.Timestamp
-	ProblemID       int32
-	ProblemID_2     int32
-	Title           string
-	Description     string
-	Language        string
-	SampleCode      string
-}
-
-func (q *Queries) GetGameByID(ctx context.Context, gameID int32) (GetGameByIDRow, error) {
+func (q *Queries) GetGameByID(ctx context.Context, gameID int32) (Game, error) {
 	row := q.db.QueryRow(ctx, getGameByID, gameID)
-	var i GetGameByIDRow
+	var i Game
 	err := row.Scan(
 		&i.GameID,
 		&i.GameType,
@@ -356,89 +843,188 @@ func (q *Queries) GetGameByID(ctx context.Context, gameID int32) (GetGameByIDRow
 		&i.DurationSeconds,
 		&i.CreatedAt,
 		&i.StartedAt,
-		&i.ProblemID,
-		&i.ProblemID_2,
-		&i.Title,
-		&i.Description,
-		&i.Language,
-		&i.SampleCode,
+		&i.PausedAt,
+		&i.OverrideState,
+		&i.FreezeSeconds,
+		&i.RevealedUntil,
+		&i.TieBreak,
+		&i.UnsolvedPenalty,
+		&i.AllowPractice,
+		&i.RatedAt,
 	)
 	return i, err
 }
 
-const getLatestState = `-- name: GetLatestState :one
-SELECT game_states.game_id, game_states.user_id, game_states.code, game_states.status, best_score_submission_id, submission_id, submissions.game_id, submissions.user_id, submissions.code, code_size, submissions.status, created_at FROM game_states
-LEFT JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
-WHERE game_states.game_id = $1 AND game_states.user_id = $2
-LIMIT 1
+const getGameLifecycleForUpdate = `-- name: GetGameLifecycleForUpdate :one
+SELECT started_at, duration_seconds, paused_at, override_state FROM games
+WHERE game_id = $1
+FOR UPDATE
 `
 
-type GetLatestStateParams struct {
-	GameID int32
-	UserID int32
+type GetGameLifecycleForUpdateRow struct {
+	StartedAt       pgtype.Timestamp
+	DurationSeconds int32
+	PausedAt        pgtype.Timestamp
+	OverrideState   *string
 }
 
-type GetLatestStateRow struct {
+func (q *Queries) GetGameLifecycleForUpdate(ctx context.Context, gameID int32) (GetGameLifecycleForUpdateRow, error) {
+	row := q.db.QueryRow(ctx, getGameLifecycleForUpdate, gameID)
+	var i GetGameLifecycleForUpdateRow
+	err := row.Scan(
+		&i.StartedAt,
+		&i.DurationSeconds,
+		&i.PausedAt,
+		&i.OverrideState,
+	)
+	return i, err
+}
+
+const getGameProblem = `-- name: GetGameProblem :one
+SELECT problems.problem_id, problems.title, problems.description, problems.language, problems.sample_code, problems.scoring, problems.checker, problems.checker_epsilon, problems.checker_code, problems.time_limit_ms, problems.memory_limit_mib, problems.judge_changed_at FROM game_problems
+JOIN problems ON game_problems.problem_id = problems.problem_id
+WHERE game_problems.game_id = $1 AND game_problems.problem_id = $2
+LIMIT 1
+`
+
+type GetGameProblemParams struct {
+	GameID    int32
+	ProblemID int32
+}
+
+func (q *Queries) GetGameProblem(ctx context.Context, arg GetGameProblemParams) (Problem, error) {
+	row := q.db.QueryRow(ctx, getGameProblem, arg.GameID, arg.ProblemID)
+	var i Problem
+	err := row.Scan(
+		&i.ProblemID,
+		&i.Title,
+		&i.Description,
+		&i.Language,
+		&i.SampleCode,
+		&i.Scoring,
+		&i.Checker,
+		&i.CheckerEpsilon,
+		&i.CheckerCode,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMib,
+		&i.JudgeChangedAt,
+	)
+	return i, err
+}
+
+const getLatestProblemValidation = `-- name: GetLatestProblemValidation :one
+SELECT problem_validation_id, problem_id, status, task_count, user_id, created_at, finished_at FROM problem_validations
+WHERE problem_id = $1
+ORDER BY problem_validation_id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestProblemValidation(ctx context.Context, problemID int32) (ProblemValidation, error) {
+	row := q.db.QueryRow(ctx, getLatestProblemValidation, problemID)
+	var i ProblemValidation
+	err := row.Scan(
+		&i.ProblemValidationID,
+		&i.ProblemID,
+		&i.Status,
+		&i.TaskCount,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
+const getLatestState = `-- name: GetLatestState :one
+SELECT game_states.game_id, game_states.team_id, game_states.problem_id, game_states.language, game_states.code, game_states.status, best_score_submission_id, submission_id, submissions.game_id, submissions.team_id, user_id, submissions.problem_id, submissions.language, submissions.code, code_size, submissions.status, is_practice, created_at FROM game_states
+LEFT JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
+WHERE game_states.game_id = $1 AND game_states.team_id = $2 AND game_states.problem_id = $3
+LIMIT 1
+`
+
+type GetLatestStateParams struct {
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+}
+
+type GetLatestStateRow struct {
 	GameID                int32
-	UserID                int32
+	TeamID                int32
+	ProblemID             int32
+	Language              string
 	Code                  string
 	Status                string
 	BestScoreSubmissionID *int32
 	SubmissionID          *int32
 	GameID_2              *int32
-	UserID_2              *int32
+	TeamID_2              *int32
+	UserID                *int32
+	ProblemID_2           *int32
+	Language_2            *string
 	Code_2                *string
 	CodeSize              *int32
 	Status_2              *string
+	IsPractice            *bool
 	CreatedAt             pgtype.Timestamp
 }
 
 func (q *Queries) GetLatestState(ctx context.Context, arg GetLatestStateParams) (GetLatestStateRow, error) {
-	row := q.db.QueryRow(ctx, getLatestState, arg.GameID, arg.UserID)
+	row := q.db.QueryRow(ctx, getLatestState, arg.GameID, arg.TeamID, arg.ProblemID)
 	var i GetLatestStateRow
 	err := row.Scan(
 		&i.GameID,
-		&i.UserID,
+		&i.TeamID,
+		&i.ProblemID,
+		&i.Language,
 		&i.Code,
 		&i.Status,
 		&i.BestScoreSubmissionID,
 		&i.SubmissionID,
 		&i.GameID_2,
-		&i.UserID_2,
+		&i.TeamID_2,
+		&i.UserID,
+		&i.ProblemID_2,
+		&i.Language_2,
 		&i.Code_2,
 		&i.CodeSize,
 		&i.Status_2,
+		&i.IsPractice,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getLatestStatesOfMainPlayers = `-- name: GetLatestStatesOfMainPlayers :many
-SELECT game_main_players.game_id, game_main_players.user_id, game_states.game_id, game_states.user_id, game_states.code, game_states.status, best_score_submission_id, submission_id, submissions.game_id, submissions.user_id, submissions.code, code_size, submissions.status, created_at FROM game_main_players
-LEFT JOIN game_states ON game_main_players.game_id = game_states.game_id AND game_main_players.user_id = game_states.user_id
+SELECT
+    game_main_players.user_id,
+    game_states.language,
+    game_states.code,
+    game_states.status,
+    submissions.code_size,
+    submissions.created_at
+FROM game_main_players
+LEFT JOIN game_team_members ON game_main_players.game_id = game_team_members.game_id AND game_main_players.user_id = game_team_members.user_id
+LEFT JOIN game_states ON game_main_players.game_id = game_states.game_id AND game_team_members.team_id = game_states.team_id AND game_states.problem_id = $2
 LEFT JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
 WHERE game_main_players.game_id = $1
 `
 
+type GetLatestStatesOfMainPlayersParams struct {
+	GameID    int32
+	ProblemID int32
+}
+
 type GetLatestStatesOfMainPlayersRow struct {
-	GameID                int32
-	UserID                int32
-	GameID_2              *int32
-	UserID_2              *int32
-	Code                  *string
-	Status                *string
-	BestScoreSubmissionID *int32
-	SubmissionID          *int32
-	GameID_3              *int32
-	UserID_3              *int32
-	Code_2                *string
-	CodeSize              *int32
-	Status_2              *string
-	CreatedAt             pgtype.Timestamp
+	UserID    int32
+	Language  *string
+	Code      *string
+	Status    *string
+	CodeSize  *int32
+	CreatedAt pgtype.Timestamp
 }
 
-func (q *Queries) GetLatestStatesOfMainPlayers(ctx context.Context, gameID int32) ([]GetLatestStatesOfMainPlayersRow, error) {
-	rows, err := q.db.Query(ctx, getLatestStatesOfMainPlayers, gameID)
+func (q *Queries) GetLatestStatesOfMainPlayers(ctx context.Context, arg GetLatestStatesOfMainPlayersParams) ([]GetLatestStatesOfMainPlayersRow, error) {
+	rows, err := q.db.Query(ctx, getLatestStatesOfMainPlayers, arg.GameID, arg.ProblemID)
 	if err != nil {
 		return nil, err
 	}
@@ -447,19 +1033,11 @@ func (q *Queries) GetLatestStatesOfMainPlayers(ctx context.Context, gameID int32
 	for rows.Next() {
 		var i GetLatestStatesOfMainPlayersRow
 		if err := rows.Scan(
-			&i.GameID,
 			&i.UserID,
-			&i.GameID_2,
-			&i.UserID_2,
+			&i.Language,
 			&i.Code,
 			&i.Status,
-			&i.BestScoreSubmissionID,
-			&i.SubmissionID,
-			&i.GameID_3,
-			&i.UserID_3,
-			&i.Code_2,
 			&i.CodeSize,
-			&i.Status_2,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -473,10 +1051,10 @@ func (q *Queries) GetLatestStatesOfMainPlayers(ctx context.Context, gameID int32
 }
 
 const getLatestSubmissionsByGameID = `-- name: GetLatestSubmissionsByGameID :many
-SELECT DISTINCT ON (user_id) submission_id, game_id, user_id, code, code_size, status, created_at
+SELECT DISTINCT ON (team_id, problem_id) submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at
 FROM submissions
-WHERE game_id = $1
-ORDER BY user_id, created_at DESC
+WHERE game_id = $1 AND NOT is_practice
+ORDER BY team_id, problem_id, created_at DESC
 `
 
 func (q *Queries) GetLatestSubmissionsByGameID(ctx context.Context, gameID int32) ([]Submission, error) {
@@ -491,10 +1069,14 @@ func (q *Queries) GetLatestSubmissionsByGameID(ctx context.Context, gameID int32
 		if err := rows.Scan(
 			&i.SubmissionID,
 			&i.GameID,
+			&i.TeamID,
 			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
 			&i.Code,
 			&i.CodeSize,
 			&i.Status,
+			&i.IsPractice,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -508,7 +1090,7 @@ func (q *Queries) GetLatestSubmissionsByGameID(ctx context.Context, gameID int32
 }
 
 const getProblemByID = `-- name: GetProblemByID :one
-SELECT problem_id, title, description, language, sample_code FROM problems
+SELECT problem_id, title, description, language, sample_code, scoring, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib, judge_changed_at FROM problems
 WHERE problem_id = $1
 LIMIT 1
 `
@@ -522,95 +1104,98 @@ func (q *Queries) GetProblemByID(ctx context.Context, problemID int32) (Problem,
 		&i.Description,
 		&i.Language,
 		&i.SampleCode,
+		&i.Scoring,
+		&i.Checker,
+		&i.CheckerEpsilon,
+		&i.CheckerCode,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMib,
+		&i.JudgeChangedAt,
 	)
 	return i, err
 }
 
-const getQualifyingRanking = `-- name: GetQualifyingRanking :many
-SELECT
-    u.username AS username,
-    u.label AS user_label,
-    s1.code_size AS code_size_1,
-    s2.code_size AS code_size_2,
-    s3.code_size AS code_size_3,
-    (COALESCE(s1.code_size, 0) + COALESCE(s2.code_size, 0) + COALESCE(s3.code_size, 0)) AS total_code_size,
-    s1.created_at AS submitted_at_1,
-    s2.created_at AS submitted_at_2,
-    s3.created_at AS submitted_at_3
-FROM game_states gs1
-JOIN submissions s1 ON gs1.best_score_submission_id = s1.submission_id
-JOIN game_states gs2 ON gs1.user_id = gs2.user_id
-JOIN submissions s2 ON gs2.best_score_submission_id = s2.submission_id
-JOIN game_states gs3 ON gs1.user_id = gs3.user_id
-JOIN submissions s3 ON gs3.best_score_submission_id = s3.submission_id
-JOIN users u ON gs1.user_id = u.user_id
-WHERE gs1.game_id = $1 AND gs2.game_id = $2 AND gs3.game_id = $3
-ORDER BY total_code_size ASC
-`
-
-type GetQualifyingRankingParams struct {
-	GameID   int32
-	GameID_2 int32
-	GameID_3 int32
+const getProblemBySubmissionID = `-- name: GetProblemBySubmissionID :one
+SELECT problems.problem_id, problems.title, problems.description, problems.language, problems.sample_code, problems.scoring, problems.checker, problems.checker_epsilon, problems.checker_code, problems.time_limit_ms, problems.memory_limit_mib, problems.judge_changed_at FROM submissions
+JOIN problems ON submissions.problem_id = problems.problem_id
+WHERE submissions.submission_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetProblemBySubmissionID(ctx context.Context, submissionID int32) (Problem, error) {
+	row := q.db.QueryRow(ctx, getProblemBySubmissionID, submissionID)
+	var i Problem
+	err := row.Scan(
+		&i.ProblemID,
+		&i.Title,
+		&i.Description,
+		&i.Language,
+		&i.SampleCode,
+		&i.Scoring,
+		&i.Checker,
+		&i.CheckerEpsilon,
+		&i.CheckerCode,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMib,
+		&i.JudgeChangedAt,
+	)
+	return i, err
 }
 
-type GetQualifyingRankingRow struct {
-	Username      string
-	UserLabel     *string
-	CodeSize1     int32
-	CodeSize2     int32
-	CodeSize3     int32
-	TotalCodeSize int32
-	SubmittedAt1  pgtype.Timestamp
-	SubmittedAt2  pgtype.Timestamp
-	SubmittedAt3  pgtype.Timestamp
+const getProblemJudgeChangedAt = `-- name: GetProblemJudgeChangedAt :one
+SELECT GREATEST(
+    p.judge_changed_at,
+    (SELECT MAX(v.created_at) FROM testcase_revisions AS v WHERE v.problem_id = p.problem_id AND v.affects_results),
+    (SELECT MAX(r.created_at) FROM reference_solutions AS r WHERE r.problem_id = p.problem_id)
+)::timestamp AS changed_at
+FROM problems AS p
+WHERE p.problem_id = $1
+`
+
+func (q *Queries) GetProblemJudgeChangedAt(ctx context.Context, problemID int32) (pgtype.Timestamp, error) {
+	row := q.db.QueryRow(ctx, getProblemJudgeChangedAt, problemID)
+	var changed_at pgtype.Timestamp
+	err := row.Scan(&changed_at)
+	return changed_at, err
 }
 
-func (q *Queries) GetQualifyingRanking(ctx context.Context, arg GetQualifyingRankingParams) ([]GetQualifyingRankingRow, error) {
-	rows, err := q.db.Query(ctx, getQualifyingRanking, arg.GameID, arg.GameID_2, arg.GameID_3)
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-	var items []GetQualifyingRankingRow
-	for rows.Next() {
-		var i GetQualifyingRankingRow
-		if err := rows.Scan(
-			&i.Username,
-			&i.UserLabel,
-			&i.CodeSize1,
-			&i.CodeSize2,
-			&i.CodeSize3,
-			&i.TotalCodeSize,
-			&i.SubmittedAt1,
-			&i.SubmittedAt2,
-			&i.SubmittedAt3,
-		); err != nil {
-			return nil, err
-		}
-		items = append(items, i)
-	}
-	if err := rows.Err(); err != nil {
-		return nil, err
-	}
-	return items, nil
+const getQualifyingStageByID = `-- name: GetQualifyingStageByID :one
+SELECT qualifying_stage_id, display_name, is_public, missing_policy, missing_penalty, created_at FROM qualifying_stages
+WHERE qualifying_stage_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetQualifyingStageByID(ctx context.Context, qualifyingStageID int32) (QualifyingStage, error) {
+	row := q.db.QueryRow(ctx, getQualifyingStageByID, qualifyingStageID)
+	var i QualifyingStage
+	err := row.Scan(
+		&i.QualifyingStageID,
+		&i.DisplayName,
+		&i.IsPublic,
+		&i.MissingPolicy,
+		&i.MissingPenalty,
+		&i.CreatedAt,
+	)
+	return i, err
 }
 
 const getRanking = `-- name: GetRanking :many
 SELECT
-    submissions.submission_id, submissions.game_id, submissions.user_id, submissions.code, submissions.code_size, submissions.status, submissions.created_at,
-    users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.created_at
+    submissions.submission_id, submissions.game_id, submissions.team_id, submissions.user_id, submissions.problem_id, submissions.language, submissions.code, submissions.code_size, submissions.status, submissions.is_practice, submissions.created_at,
+    game_teams.team_id, game_teams.game_id, game_teams.display_name,
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND NOT s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
 FROM game_states
-JOIN users ON game_states.user_id = users.user_id
+JOIN game_teams ON game_states.team_id = game_teams.team_id
 JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
 WHERE game_states.game_id = $1
 ORDER BY submissions.code_size ASC, submissions.created_at ASC
-LIMIT 30
 `
 
 type GetRankingRow struct {
-	Submission Submission
-	User       User
+	Submission      Submission
+	GameTeam        GameTeam
+	SubmissionCount int64
 }
 
 func (q *Queries) GetRanking(ctx context.Context, gameID int32) ([]GetRankingRow, error) {
@@ -625,18 +1210,19 @@ func (q *Queries) GetRanking(ctx context.Context, gameID int32) ([]GetRankingRow
 		if err := rows.Scan(
 			&i.Submission.SubmissionID,
 			&i.Submission.GameID,
+			&i.Submission.TeamID,
 			&i.Submission.UserID,
+			&i.Submission.ProblemID,
+			&i.Submission.Language,
 			&i.Submission.Code,
 			&i.Submission.CodeSize,
 			&i.Submission.Status,
+			&i.Submission.IsPractice,
 			&i.Submission.CreatedAt,
-			&i.User.UserID,
-			&i.User.Username,
-			&i.User.DisplayName,
-			&i.User.IconPath,
-			&i.User.IsAdmin,
-			&i.User.Label,
-			&i.User.CreatedAt,
+			&i.GameTeam.TeamID,
+			&i.GameTeam.GameID,
+			&i.GameTeam.DisplayName,
+			&i.SubmissionCount,
 		); err != nil {
 			return nil, err
 		}
@@ -648,8 +1234,47 @@ func (q *Queries) GetRanking(ctx context.Context, gameID int32) ([]GetRankingRow
 	return items, nil
 }
 
+const getReferenceSolutionByID = `-- name: GetReferenceSolutionByID :one
+SELECT reference_solution_id, problem_id, name, code, created_at FROM reference_solutions
+WHERE reference_solution_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetReferenceSolutionByID(ctx context.Context, referenceSolutionID int32) (ReferenceSolution, error) {
+	row := q.db.QueryRow(ctx, getReferenceSolutionByID, referenceSolutionID)
+	var i ReferenceSolution
+	err := row.Scan(
+		&i.ReferenceSolutionID,
+		&i.ProblemID,
+		&i.Name,
+		&i.Code,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getRejudgeByID = `-- name: GetRejudgeByID :one
+SELECT rejudge_id, problem_id, reason, user_id, created_at, finished_at FROM rejudges
+WHERE rejudge_id = $1
+LIMIT 1
+`
+
+func (q *Queries) GetRejudgeByID(ctx context.Context, rejudgeID int32) (Rejudge, error) {
+	row := q.db.QueryRow(ctx, getRejudgeByID, rejudgeID)
+	var i Rejudge
+	err := row.Scan(
+		&i.RejudgeID,
+		&i.ProblemID,
+		&i.Reason,
+		&i.UserID,
+		&i.CreatedAt,
+		&i.FinishedAt,
+	)
+	return i, err
+}
+
 const getSubmissionByID = `-- name: GetSubmissionByID :one
-SELECT submission_id, game_id, user_id, code, code_size, status, created_at
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at
 FROM submissions
 WHERE submission_id = $1
 LIMIT 1
@@ -661,17 +1286,21 @@ func (q *Queries) GetSubmissionByID(ctx context.Context, submissionID int32) (Su
 	err := row.Scan(
 		&i.SubmissionID,
 		&i.GameID,
+		&i.TeamID,
 		&i.UserID,
+		&i.ProblemID,
+		&i.Language,
 		&i.Code,
 		&i.CodeSize,
 		&i.Status,
+		&i.IsPractice,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getSubmissionsByGameID = `-- name: GetSubmissionsByGameID :many
-SELECT submission_id, game_id, user_id, code, code_size, status, created_at
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at
 FROM submissions
 WHERE game_id = $1
 ORDER BY created_at DESC
@@ -689,10 +1318,14 @@ func (q *Queries) GetSubmissionsByGameID(ctx context.Context, gameID int32) ([]S
 		if err := rows.Scan(
 			&i.SubmissionID,
 			&i.GameID,
+			&i.TeamID,
 			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
 			&i.Code,
 			&i.CodeSize,
 			&i.Status,
+			&i.IsPractice,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -705,19 +1338,19 @@ func (q *Queries) GetSubmissionsByGameID(ctx context.Context, gameID int32) ([]S
 	return items, nil
 }
 
-const getSubmissionsByGameIDAndUserID = `-- name: GetSubmissionsByGameIDAndUserID :many
-SELECT submission_id, game_id, user_id, code, code_size, status, created_at FROM submissions
-WHERE game_id = $1 AND user_id = $2
+const getSubmissionsByGameIDAndTeamID = `-- name: GetSubmissionsByGameIDAndTeamID :many
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at FROM submissions
+WHERE game_id = $1 AND team_id = $2
 ORDER BY created_at DESC
 `
 
-type GetSubmissionsByGameIDAndUserIDParams struct {
+type GetSubmissionsByGameIDAndTeamIDParams struct {
 	GameID int32
-	UserID int32
+	TeamID int32
 }
 
-func (q *Queries) GetSubmissionsByGameIDAndUserID(ctx context.Context, arg GetSubmissionsByGameIDAndUserIDParams) ([]Submission, error) {
-	rows, err := q.db.Query(ctx, getSubmissionsByGameIDAndUserID, arg.GameID, arg.UserID)
+func (q *Queries) GetSubmissionsByGameIDAndTeamID(ctx context.Context, arg GetSubmissionsByGameIDAndTeamIDParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, getSubmissionsByGameIDAndTeamID, arg.GameID, arg.TeamID)
 	if err != nil {
 		return nil, err
 	}
@@ -728,10 +1361,14 @@ func (q *Queries) GetSubmissionsByGameIDAndUserID(ctx context.Context, arg GetSu
 		if err := rows.Scan(
 			&i.SubmissionID,
 			&i.GameID,
+			&i.TeamID,
 			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
 			&i.Code,
 			&i.CodeSize,
 			&i.Status,
+			&i.IsPractice,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -744,8 +1381,27 @@ func (q *Queries) GetSubmissionsByGameIDAndUserID(ctx context.Context, arg GetSu
 	return items, nil
 }
 
+const getTeamByUserID = `-- name: GetTeamByUserID :one
+SELECT game_teams.team_id, game_teams.game_id, game_teams.display_name FROM game_team_members
+JOIN game_teams ON game_team_members.team_id = game_teams.team_id
+WHERE game_team_members.game_id = $1 AND game_team_members.user_id = $2
+LIMIT 1
+`
+
+type GetTeamByUserIDParams struct {
+	GameID int32
+	UserID int32
+}
+
+func (q *Queries) GetTeamByUserID(ctx context.Context, arg GetTeamByUserIDParams) (GameTeam, error) {
+	row := q.db.QueryRow(ctx, getTeamByUserID, arg.GameID, arg.UserID)
+	var i GameTeam
+	err := row.Scan(&i.TeamID, &i.GameID, &i.DisplayName)
+	return i, err
+}
+
 const getTestcaseByID = `-- name: GetTestcaseByID :one
-SELECT testcase_id, problem_id, stdin, stdout FROM testcases
+SELECT testcase_id, problem_id, stdin, stdout, is_sample, time_limit_ms, memory_limit_mib FROM testcases
 WHERE testcase_id = $1
 LIMIT 1
 `
@@ -758,12 +1414,15 @@ func (q *Queries) GetTestcaseByID(ctx context.Context, testcaseID int32) (Testca
 		&i.ProblemID,
 		&i.Stdin,
 		&i.Stdout,
+		&i.IsSample,
+		&i.TimeLimitMs,
+		&i.MemoryLimitMib,
 	)
 	return i, err
 }
 
 const getTestcaseResultsBySubmissionID = `-- name: GetTestcaseResultsBySubmissionID :many
-SELECT testcase_result_id, submission_id, testcase_id, status, stdout, stderr, created_at
+SELECT testcase_result_id, submission_id, testcase_id, status, stdout, stderr, time_ms, memory_bytes, created_at
 FROM testcase_results
 WHERE submission_id = $1
 ORDER BY created_at
@@ -785,6 +1444,8 @@ func (q *Queries) GetTestcaseResultsBySubmissionID(ctx context.Context, submissi
 			&i.Status,
 			&i.Stdout,
 			&i.Stderr,
+			&i.TimeMs,
+			&i.MemoryBytes,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -817,7 +1478,7 @@ func (q *Queries) GetTournamentByID(ctx context.Context, tournamentID int32) (To
 }
 
 const getUserAuthByUsername = `-- name: GetUserAuthByUsername :one
-SELECT users.user_id, username, display_name, icon_path, is_admin, label, created_at, user_auth_id, user_auths.user_id, auth_type, password_hash FROM users
+SELECT users.user_id, username, display_name, icon_path, is_admin, label, rating, rating_rank, created_at, user_auth_id, user_auths.user_id, auth_type, password_hash FROM users
 JOIN user_auths ON users.user_id = user_auths.user_id
 WHERE users.username = $1
 LIMIT 1
@@ -830,6 +1491,8 @@ type GetUserAuthByUsernameRow struct {
 	IconPath     *string
 	IsAdmin      bool
 	Label        *string
+	Rating       int32
+	RatingRank   *int32
 	CreatedAt    pgtype.Timestamp
 	UserAuthID   int32
 	UserID_2     int32
@@ -847,6 +1510,8 @@ func (q *Queries) GetUserAuthByUsername(ctx context.Context, username string) (G
 		&i.IconPath,
 		&i.IsAdmin,
 		&i.Label,
+		&i.Rating,
+		&i.RatingRank,
 		&i.CreatedAt,
 		&i.UserAuthID,
 		&i.UserID_2,
@@ -857,7 +1522,7 @@ func (q *Queries) GetUserAuthByUsername(ctx context.Context, username string) (G
 }
 
 const getUserByID = `-- name: GetUserByID :one
-SELECT user_id, username, display_name, icon_path, is_admin, label, created_at FROM users
+SELECT user_id, username, display_name, icon_path, is_admin, label, rating, rating_rank, created_at FROM users
 WHERE users.user_id = $1
 LIMIT 1
 `
@@ -872,13 +1537,15 @@ func (q *Queries) GetUserByID(ctx context.Context, userID int32) (User, error) {
 		&i.IconPath,
 		&i.IsAdmin,
 		&i.Label,
+		&i.Rating,
+		&i.RatingRank,
 		&i.CreatedAt,
 	)
 	return i, err
 }
 
 const getUserBySession = `-- name: GetUserBySession :one
-SELECT users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.created_at FROM sessions
+SELECT users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.rating, users.rating_rank, users.created_at FROM sessions
 JOIN users ON sessions.user_id = users.user_id
 WHERE sessions.session_id = $1 AND sessions.expires_at > NOW()
 `
@@ -893,6 +1560,8 @@ func (q *Queries) GetUserBySession(ctx context.Context, sessionID string) (User,
 		&i.IconPath,
 		&i.IsAdmin,
 		&i.Label,
+		&i.Rating,
+		&i.RatingRank,
 		&i.CreatedAt,
 	)
 	return i, err
@@ -912,7 +1581,7 @@ func (q *Queries) GetUserIDByUsername(ctx context.Context, username string) (int
 }
 
 const listAllGames = `-- name: ListAllGames :many
-SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, problem_id FROM games
+SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, paused_at, override_state, freeze_seconds, revealed_until, tie_break, unsolved_penalty, allow_practice, rated_at FROM games
 ORDER BY games.game_id
 `
 
@@ -933,7 +1602,14 @@ func (q *Queries) ListAllGames(ctx context.Context) ([]Game, error) {
 			&i.DurationSeconds,
 			&i.CreatedAt,
 			&i.StartedAt,
-			&i.ProblemID,
+			&i.PausedAt,
+			&i.OverrideState,
+			&i.FreezeSeconds,
+			&i.RevealedUntil,
+			&i.TieBreak,
+			&i.UnsolvedPenalty,
+			&i.AllowPractice,
+			&i.RatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -945,25 +1621,60 @@ func (q *Queries) ListAllGames(ctx context.Context) ([]Game, error) {
 	return items, nil
 }
 
-const listGameStateIDs = `-- name: ListGameStateIDs :many
-SELECT game_id, user_id FROM game_states
+const listBestPracticeSubmissions = `-- name: ListBestPracticeSubmissions :many
+SELECT
+    submissions.submission_id, submissions.game_id, submissions.team_id, submissions.user_id, submissions.problem_id, submissions.language, submissions.code, submissions.code_size, submissions.status, submissions.is_practice, submissions.created_at,
+    game_teams.team_id, game_teams.game_id, game_teams.display_name,
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
+FROM submissions
+JOIN game_teams ON submissions.team_id = game_teams.team_id
+WHERE submissions.submission_id IN (
+    SELECT DISTINCT ON (s.team_id, s.problem_id) s.submission_id FROM submissions AS s
+    WHERE s.game_id = $1 AND s.status = 'success' AND s.is_practice
+      AND ($2::text IS NULL OR s.language = $2)
+    ORDER BY s.team_id, s.problem_id, s.code_size ASC, s.created_at ASC
+)
+ORDER BY submissions.code_size ASC, submissions.created_at ASC
 `
 
-type ListGameStateIDsRow struct {
-	GameID int32
-	UserID int32
+type ListBestPracticeSubmissionsParams struct {
+	GameID   int32
+	Language *string
 }
 
-func (q *Queries) ListGameStateIDs(ctx context.Context) ([]ListGameStateIDsRow, error) {
-	rows, err := q.db.Query(ctx, listGameStateIDs)
+type ListBestPracticeSubmissionsRow struct {
+	Submission      Submission
+	GameTeam        GameTeam
+	SubmissionCount int64
+}
+
+func (q *Queries) ListBestPracticeSubmissions(ctx context.Context, arg ListBestPracticeSubmissionsParams) ([]ListBestPracticeSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, listBestPracticeSubmissions, arg.GameID, arg.Language)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListGameStateIDsRow
+	var items []ListBestPracticeSubmissionsRow
 	for rows.Next() {
-		var i ListGameStateIDsRow
-		if err := rows.Scan(&i.GameID, &i.UserID); err != nil {
+		var i ListBestPracticeSubmissionsRow
+		if err := rows.Scan(
+			&i.Submission.SubmissionID,
+			&i.Submission.GameID,
+			&i.Submission.TeamID,
+			&i.Submission.UserID,
+			&i.Submission.ProblemID,
+			&i.Submission.Language,
+			&i.Submission.Code,
+			&i.Submission.CodeSize,
+			&i.Submission.Status,
+			&i.Submission.IsPractice,
+			&i.Submission.CreatedAt,
+			&i.GameTeam.TeamID,
+			&i.GameTeam.GameID,
+			&i.GameTeam.DisplayName,
+			&i.SubmissionCount,
+		); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -974,44 +1685,1103 @@ func (q *Queries) ListGameStateIDs(ctx context.Context) ([]ListGameStateIDsRow,
 	return items, nil
 }
 
-const listMainPlayers = `-- name: ListMainPlayers :many
-SELECT game_id, game_main_players.user_id, users.user_id, username, display_name, icon_path, is_admin, label, created_at FROM game_main_players
-JOIN users ON game_main_players.user_id = users.user_id
-WHERE game_main_players.game_id = ANY($1::INT[])
-ORDER BY game_main_players.user_id
+const listBestSubmissionsAt = `-- name: ListBestSubmissionsAt :many
+SELECT
+    submissions.submission_id, submissions.game_id, submissions.team_id, submissions.user_id, submissions.problem_id, submissions.language, submissions.code, submissions.code_size, submissions.status, submissions.is_practice, submissions.created_at,
+    game_teams.team_id, game_teams.game_id, game_teams.display_name,
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND NOT s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
+FROM submissions
+JOIN game_teams ON submissions.team_id = game_teams.team_id
+WHERE submissions.submission_id IN (
+    SELECT DISTINCT ON (s.team_id, s.problem_id) s.submission_id FROM submissions AS s
+    WHERE s.game_id = $1 AND s.status = 'success' AND NOT s.is_practice AND s.created_at <= $2
+      AND ($3::text IS NULL OR s.language = $3)
+    ORDER BY s.team_id, s.problem_id, s.code_size ASC, s.created_at ASC
+)
+ORDER BY submissions.code_size ASC, submissions.created_at ASC
 `
 
-type ListMainPlayersRow struct {
-	GameID      int32
-	UserID      int32
-	UserID_2    int32
-	Username    string
-	DisplayName string
-	IconPath    *string
-	IsAdmin     bool
-	Label       *string
-	CreatedAt   pgtype.Timestamp
+type ListBestSubmissionsAtParams struct {
+	GameID    int32
+	CreatedAt pgtype.Timestamp
+	Language  *string
+}
+
+type ListBestSubmissionsAtRow struct {
+	Submission      Submission
+	GameTeam        GameTeam
+	SubmissionCount int64
+}
+
+func (q *Queries) ListBestSubmissionsAt(ctx context.Context, arg ListBestSubmissionsAtParams) ([]ListBestSubmissionsAtRow, error) {
+	rows, err := q.db.Query(ctx, listBestSubmissionsAt, arg.GameID, arg.CreatedAt, arg.Language)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListBestSubmissionsAtRow
+	for rows.Next() {
+		var i ListBestSubmissionsAtRow
+		if err := rows.Scan(
+			&i.Submission.SubmissionID,
+			&i.Submission.GameID,
+			&i.Submission.TeamID,
+			&i.Submission.UserID,
+			&i.Submission.ProblemID,
+			&i.Submission.Language,
+			&i.Submission.Code,
+			&i.Submission.CodeSize,
+			&i.Submission.Status,
+			&i.Submission.IsPractice,
+			&i.Submission.CreatedAt,
+			&i.GameTeam.TeamID,
+			&i.GameTeam.GameID,
+			&i.GameTeam.DisplayName,
+			&i.SubmissionCount,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listCodeSnapshots = `-- name: ListCodeSnapshots :many
+SELECT code_snapshot_id, game_id, team_id, user_id, problem_id, is_submission, keep_prefix, keep_suffix, inserted, created_at FROM code_snapshots
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3
+ORDER BY code_snapshot_id
+`
+
+type ListCodeSnapshotsParams struct {
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+}
+
+func (q *Queries) ListCodeSnapshots(ctx context.Context, arg ListCodeSnapshotsParams) ([]CodeSnapshot, error) {
+	rows, err := q.db.Query(ctx, listCodeSnapshots, arg.GameID, arg.TeamID, arg.ProblemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []CodeSnapshot
+	for rows.Next() {
+		var i CodeSnapshot
+		if err := rows.Scan(
+			&i.CodeSnapshotID,
+			&i.GameID,
+			&i.TeamID,
+			&i.UserID,
+			&i.ProblemID,
+			&i.IsSubmission,
+			&i.KeepPrefix,
+			&i.KeepSuffix,
+			&i.Inserted,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listFinishedRejudgeIDsBySubmissionID = `-- name: ListFinishedRejudgeIDsBySubmissionID :many
+SELECT rejudges.rejudge_id FROM rejudges
+JOIN rejudge_submissions ON rejudges.rejudge_id = rejudge_submissions.rejudge_id
+WHERE rejudge_submissions.submission_id = $1 AND rejudges.finished_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM rejudge_submissions AS rs
+      JOIN submissions AS s ON rs.submission_id = s.submission_id
+      WHERE rs.rejudge_id = rejudges.rejudge_id AND s.status = 'running'
+  )
+`
+
+func (q *Queries) ListFinishedRejudgeIDsBySubmissionID(ctx context.Context, submissionID int32) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listFinishedRejudgeIDsBySubmissionID, submissionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var rejudge_id int32
+		if err := rows.Scan(&rejudge_id); err != nil {
+			return nil, err
+		}
+		items = append(items, rejudge_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameLifecycleEvents = `-- name: ListGameLifecycleEvents :many
+SELECT game_lifecycle_event_id, game_id, action, from_state, to_state, started_at, duration_seconds, user_id, created_at FROM game_lifecycle_events
+WHERE game_id = $1
+ORDER BY game_lifecycle_event_id
+`
+
+func (q *Queries) ListGameLifecycleEvents(ctx context.Context, gameID int32) ([]GameLifecycleEvent, error) {
+	rows, err := q.db.Query(ctx, listGameLifecycleEvents, gameID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GameLifecycleEvent
+	for rows.Next() {
+		var i GameLifecycleEvent
+		if err := rows.Scan(
+			&i.GameLifecycleEventID,
+			&i.GameID,
+			&i.Action,
+			&i.FromState,
+			&i.ToState,
+			&i.StartedAt,
+			&i.DurationSeconds,
+			&i.UserID,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameProblems = `-- name: ListGameProblems :many
+SELECT game_problems.game_id, problems.problem_id, problems.title, problems.description, problems.language, problems.sample_code, problems.scoring, problems.checker, problems.checker_epsilon, problems.checker_code, problems.time_limit_ms, problems.memory_limit_mib, problems.judge_changed_at FROM game_problems
+JOIN problems ON game_problems.problem_id = problems.problem_id
+WHERE game_problems.game_id = ANY($1::INT[])
+ORDER BY game_problems.game_id, game_problems.position
+`
+
+type ListGameProblemsRow struct {
+	GameID  int32
+	Problem Problem
+}
+
+func (q *Queries) ListGameProblems(ctx context.Context, dollar_1 []int32) ([]ListGameProblemsRow, error) {
+	rows, err := q.db.Query(ctx, listGameProblems, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGameProblemsRow
+	for rows.Next() {
+		var i ListGameProblemsRow
+		if err := rows.Scan(
+			&i.GameID,
+			&i.Problem.ProblemID,
+			&i.Problem.Title,
+			&i.Problem.Description,
+			&i.Problem.Language,
+			&i.Problem.SampleCode,
+			&i.Problem.Scoring,
+			&i.Problem.Checker,
+			&i.Problem.CheckerEpsilon,
+			&i.Problem.CheckerCode,
+			&i.Problem.TimeLimitMs,
+			&i.Problem.MemoryLimitMib,
+			&i.Problem.JudgeChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameStateIDs = `-- name: ListGameStateIDs :many
+SELECT game_id, team_id, problem_id FROM game_states
+`
+
+type ListGameStateIDsRow struct {
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+}
+
+func (q *Queries) ListGameStateIDs(ctx context.Context) ([]ListGameStateIDsRow, error) {
+	rows, err := q.db.Query(ctx, listGameStateIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGameStateIDsRow
+	for rows.Next() {
+		var i ListGameStateIDsRow
+		if err := rows.Scan(&i.GameID, &i.TeamID, &i.ProblemID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGameStateIDsByProblemID = `-- name: ListGameStateIDsByProblemID :many
+SELECT game_id, team_id, problem_id FROM game_states
+WHERE problem_id = $1
+`
+
+type ListGameStateIDsByProblemIDRow struct {
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+}
+
+func (q *Queries) ListGameStateIDsByProblemID(ctx context.Context, problemID int32) ([]ListGameStateIDsByProblemIDRow, error) {
+	rows, err := q.db.Query(ctx, listGameStateIDsByProblemID, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGameStateIDsByProblemIDRow
+	for rows.Next() {
+		var i ListGameStateIDsByProblemIDRow
+		if err := rows.Scan(&i.GameID, &i.TeamID, &i.ProblemID); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listMainPlayers = `-- name: ListMainPlayers :many
+SELECT game_id, game_main_players.user_id, users.user_id, username, display_name, icon_path, is_admin, label, rating, rating_rank, created_at FROM game_main_players
+JOIN users ON game_main_players.user_id = users.user_id
+WHERE game_main_players.game_id = ANY($1::INT[])
+ORDER BY game_main_players.user_id
+`
+
+type ListMainPlayersRow struct {
+	GameID      int32
+	UserID      int32
+	UserID_2    int32
+	Username    string
+	DisplayName string
+	IconPath    *string
+	IsAdmin     bool
+	Label       *string
+	Rating      int32
+	RatingRank  *int32
+	CreatedAt   pgtype.Timestamp
+}
+
+func (q *Queries) ListMainPlayers(ctx context.Context, dollar_1 []int32) ([]ListMainPlayersRow, error) {
+	rows, err := q.db.Query(ctx, listMainPlayers, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListMainPlayersRow
+	for rows.Next() {
+		var i ListMainPlayersRow
+		if err := rows.Scan(
+			&i.GameID,
+			&i.UserID,
+			&i.UserID_2,
+			&i.Username,
+			&i.DisplayName,
+			&i.IconPath,
+			&i.IsAdmin,
+			&i.Label,
+			&i.Rating,
+			&i.RatingRank,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProblemLanguages = `-- name: ListProblemLanguages :many
+SELECT problem_id, language, sample_code FROM problem_languages
+WHERE problem_id = ANY($1::INT[])
+ORDER BY problem_id, language
+`
+
+func (q *Queries) ListProblemLanguages(ctx context.Context, dollar_1 []int32) ([]ProblemLanguage, error) {
+	rows, err := q.db.Query(ctx, listProblemLanguages, dollar_1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemLanguage
+	for rows.Next() {
+		var i ProblemLanguage
+		if err := rows.Scan(&i.ProblemID, &i.Language, &i.SampleCode); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProblemValidationResults = `-- name: ListProblemValidationResults :many
+SELECT problem_validation_id, reference_solution_id, testcase_id, status, stdout, stderr FROM problem_validation_results
+WHERE problem_validation_id = $1
+ORDER BY reference_solution_id, testcase_id
+`
+
+func (q *Queries) ListProblemValidationResults(ctx context.Context, problemValidationID int32) ([]ProblemValidationResult, error) {
+	rows, err := q.db.Query(ctx, listProblemValidationResults, problemValidationID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ProblemValidationResult
+	for rows.Next() {
+		var i ProblemValidationResult
+		if err := rows.Scan(
+			&i.ProblemValidationID,
+			&i.ReferenceSolutionID,
+			&i.TestcaseID,
+			&i.Status,
+			&i.Stdout,
+			&i.Stderr,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listProblems = `-- name: ListProblems :many
+SELECT problem_id, title, description, language, sample_code, scoring, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib, judge_changed_at FROM problems
+ORDER BY problem_id
+`
+
+func (q *Queries) ListProblems(ctx context.Context) ([]Problem, error) {
+	rows, err := q.db.Query(ctx, listProblems)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Problem
+	for rows.Next() {
+		var i Problem
+		if err := rows.Scan(
+			&i.ProblemID,
+			&i.Title,
+			&i.Description,
+			&i.Language,
+			&i.SampleCode,
+			&i.Scoring,
+			&i.Checker,
+			&i.CheckerEpsilon,
+			&i.CheckerCode,
+			&i.TimeLimitMs,
+			&i.MemoryLimitMib,
+			&i.JudgeChangedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicGames = `-- name: ListPublicGames :many
+SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, paused_at, override_state, freeze_seconds, revealed_until, tie_break, unsolved_penalty, allow_practice, rated_at FROM games
+WHERE is_public = true
+ORDER BY games.game_id
+`
+
+func (q *Queries) ListPublicGames(ctx context.Context) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listPublicGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Game
+	for rows.Next() {
+		var i Game
+		if err := rows.Scan(
+			&i.GameID,
+			&i.GameType,
+			&i.IsPublic,
+			&i.DisplayName,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.PausedAt,
+			&i.OverrideState,
+			&i.FreezeSeconds,
+			&i.RevealedUntil,
+			&i.TieBreak,
+			&i.UnsolvedPenalty,
+			&i.AllowPractice,
+			&i.RatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPublicQualifyingStages = `-- name: ListPublicQualifyingStages :many
+SELECT qualifying_stage_id, display_name, is_public, missing_policy, missing_penalty, created_at FROM qualifying_stages
+WHERE is_public = true
+ORDER BY qualifying_stage_id
+`
+
+func (q *Queries) ListPublicQualifyingStages(ctx context.Context) ([]QualifyingStage, error) {
+	rows, err := q.db.Query(ctx, listPublicQualifyingStages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QualifyingStage
+	for rows.Next() {
+		var i QualifyingStage
+		if err := rows.Scan(
+			&i.QualifyingStageID,
+			&i.DisplayName,
+			&i.IsPublic,
+			&i.MissingPolicy,
+			&i.MissingPenalty,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQualifyingStageGames = `-- name: ListQualifyingStageGames :many
+SELECT qualifying_stage_games.weight, games.game_id, games.game_type, games.is_public, games.display_name, games.duration_seconds, games.created_at, games.started_at, games.paused_at, games.override_state, games.freeze_seconds, games.revealed_until, games.tie_break, games.unsolved_penalty, games.allow_practice, games.rated_at FROM qualifying_stage_games
+JOIN games ON qualifying_stage_games.game_id = games.game_id
+WHERE qualifying_stage_games.qualifying_stage_id = $1
+ORDER BY qualifying_stage_games.position
+`
+
+type ListQualifyingStageGamesRow struct {
+	Weight float64
+	Game   Game
+}
+
+func (q *Queries) ListQualifyingStageGames(ctx context.Context, qualifyingStageID int32) ([]ListQualifyingStageGamesRow, error) {
+	rows, err := q.db.Query(ctx, listQualifyingStageGames, qualifyingStageID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListQualifyingStageGamesRow
+	for rows.Next() {
+		var i ListQualifyingStageGamesRow
+		if err := rows.Scan(
+			&i.Weight,
+			&i.Game.GameID,
+			&i.Game.GameType,
+			&i.Game.IsPublic,
+			&i.Game.DisplayName,
+			&i.Game.DurationSeconds,
+			&i.Game.CreatedAt,
+			&i.Game.StartedAt,
+			&i.Game.PausedAt,
+			&i.Game.OverrideState,
+			&i.Game.FreezeSeconds,
+			&i.Game.RevealedUntil,
+			&i.Game.TieBreak,
+			&i.Game.UnsolvedPenalty,
+			&i.Game.AllowPractice,
+			&i.Game.RatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listQualifyingStages = `-- name: ListQualifyingStages :many
+SELECT qualifying_stage_id, display_name, is_public, missing_policy, missing_penalty, created_at FROM qualifying_stages
+ORDER BY qualifying_stage_id
+`
+
+func (q *Queries) ListQualifyingStages(ctx context.Context) ([]QualifyingStage, error) {
+	rows, err := q.db.Query(ctx, listQualifyingStages)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []QualifyingStage
+	for rows.Next() {
+		var i QualifyingStage
+		if err := rows.Scan(
+			&i.QualifyingStageID,
+			&i.DisplayName,
+			&i.IsPublic,
+			&i.MissingPolicy,
+			&i.MissingPenalty,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRatedGames = `-- name: ListRatedGames :many
+SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, paused_at, override_state, freeze_seconds, revealed_until, tie_break, unsolved_penalty, allow_practice, rated_at FROM games
+WHERE rated_at IS NOT NULL AND is_public
+ORDER BY rated_at, game_id
+`
+
+func (q *Queries) ListRatedGames(ctx context.Context) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listRatedGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Game
+	for rows.Next() {
+		var i Game
+		if err := rows.Scan(
+			&i.GameID,
+			&i.GameType,
+			&i.IsPublic,
+			&i.DisplayName,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.PausedAt,
+			&i.OverrideState,
+			&i.FreezeSeconds,
+			&i.RevealedUntil,
+			&i.TieBreak,
+			&i.UnsolvedPenalty,
+			&i.AllowPractice,
+			&i.RatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRatedUsers = `-- name: ListRatedUsers :many
+SELECT user_id, username, display_name, icon_path, is_admin, label, rating, rating_rank, created_at FROM users
+WHERE rating_rank IS NOT NULL
+ORDER BY rating_rank, user_id
+`
+
+func (q *Queries) ListRatedUsers(ctx context.Context) ([]User, error) {
+	rows, err := q.db.Query(ctx, listRatedUsers)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []User
+	for rows.Next() {
+		var i User
+		if err := rows.Scan(
+			&i.UserID,
+			&i.Username,
+			&i.DisplayName,
+			&i.IconPath,
+			&i.IsAdmin,
+			&i.Label,
+			&i.Rating,
+			&i.RatingRank,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRatingHistoryByUserID = `-- name: ListRatingHistoryByUserID :many
+SELECT rating_history.rating_history_id, rating_history.user_id, rating_history.game_id, rating_history.game_rank, rating_history.rating_before, rating_history.rating_after, rating_history.created_at, games.display_name AS game_display_name FROM rating_history
+JOIN games ON rating_history.game_id = games.game_id
+WHERE rating_history.user_id = $1
+ORDER BY rating_history.rating_history_id
+`
+
+type ListRatingHistoryByUserIDRow struct {
+	RatingHistoryID int32
+	UserID          int32
+	GameID          int32
+	GameRank        int32
+	RatingBefore    int32
+	RatingAfter     int32
+	CreatedAt       pgtype.Timestamp
+	GameDisplayName string
+}
+
+func (q *Queries) ListRatingHistoryByUserID(ctx context.Context, userID int32) ([]ListRatingHistoryByUserIDRow, error) {
+	rows, err := q.db.Query(ctx, listRatingHistoryByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRatingHistoryByUserIDRow
+	for rows.Next() {
+		var i ListRatingHistoryByUserIDRow
+		if err := rows.Scan(
+			&i.RatingHistoryID,
+			&i.UserID,
+			&i.GameID,
+			&i.GameRank,
+			&i.RatingBefore,
+			&i.RatingAfter,
+			&i.CreatedAt,
+			&i.GameDisplayName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listReferenceSolutionsByProblemID = `-- name: ListReferenceSolutionsByProblemID :many
+SELECT reference_solution_id, problem_id, name, code, created_at FROM reference_solutions
+WHERE problem_id = $1
+ORDER BY reference_solution_id
+`
+
+func (q *Queries) ListReferenceSolutionsByProblemID(ctx context.Context, problemID int32) ([]ReferenceSolution, error) {
+	rows, err := q.db.Query(ctx, listReferenceSolutionsByProblemID, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ReferenceSolution
+	for rows.Next() {
+		var i ReferenceSolution
+		if err := rows.Scan(
+			&i.ReferenceSolutionID,
+			&i.ProblemID,
+			&i.Name,
+			&i.Code,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRejudgeRankings = `-- name: ListRejudgeRankings :many
+SELECT rejudge_rankings.rejudge_id, rejudge_rankings.game_id, rejudge_rankings.team_id, rejudge_rankings.phase, rejudge_rankings.rank, rejudge_rankings.score, game_teams.display_name AS team_name
+FROM rejudge_rankings
+JOIN game_teams ON rejudge_rankings.team_id = game_teams.team_id
+WHERE rejudge_rankings.rejudge_id = $1
+ORDER BY rejudge_rankings.game_id, rejudge_rankings.team_id
+`
+
+type ListRejudgeRankingsRow struct {
+	RejudgeID int32
+	GameID    int32
+	TeamID    int32
+	Phase     string
+	Rank      int32
+	Score     int32
+	TeamName  string
+}
+
+func (q *Queries) ListRejudgeRankings(ctx context.Context, rejudgeID int32) ([]ListRejudgeRankingsRow, error) {
+	rows, err := q.db.Query(ctx, listRejudgeRankings, rejudgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRejudgeRankingsRow
+	for rows.Next() {
+		var i ListRejudgeRankingsRow
+		if err := rows.Scan(
+			&i.RejudgeID,
+			&i.GameID,
+			&i.TeamID,
+			&i.Phase,
+			&i.Rank,
+			&i.Score,
+			&i.TeamName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRejudgeSubmissions = `-- name: ListRejudgeSubmissions :many
+SELECT rejudge_submissions.status_before, submissions.submission_id, submissions.game_id, submissions.team_id, submissions.user_id, submissions.problem_id, submissions.language, submissions.code, submissions.code_size, submissions.status, submissions.is_practice, submissions.created_at
+FROM rejudge_submissions
+JOIN submissions ON rejudge_submissions.submission_id = submissions.submission_id
+WHERE rejudge_submissions.rejudge_id = $1
+ORDER BY submissions.submission_id
+`
+
+type ListRejudgeSubmissionsRow struct {
+	StatusBefore string
+	Submission   Submission
+}
+
+func (q *Queries) ListRejudgeSubmissions(ctx context.Context, rejudgeID int32) ([]ListRejudgeSubmissionsRow, error) {
+	rows, err := q.db.Query(ctx, listRejudgeSubmissions, rejudgeID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListRejudgeSubmissionsRow
+	for rows.Next() {
+		var i ListRejudgeSubmissionsRow
+		if err := rows.Scan(
+			&i.StatusBefore,
+			&i.Submission.SubmissionID,
+			&i.Submission.GameID,
+			&i.Submission.TeamID,
+			&i.Submission.UserID,
+			&i.Submission.ProblemID,
+			&i.Submission.Language,
+			&i.Submission.Code,
+			&i.Submission.CodeSize,
+			&i.Submission.Status,
+			&i.Submission.IsPractice,
+			&i.Submission.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRejudges = `-- name: ListRejudges :many
+SELECT rejudge_id, problem_id, reason, user_id, created_at, finished_at FROM rejudges
+ORDER BY rejudge_id DESC
+`
+
+func (q *Queries) ListRejudges(ctx context.Context) ([]Rejudge, error) {
+	rows, err := q.db.Query(ctx, listRejudges)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Rejudge
+	for rows.Next() {
+		var i Rejudge
+		if err := rows.Scan(
+			&i.RejudgeID,
+			&i.ProblemID,
+			&i.Reason,
+			&i.UserID,
+			&i.CreatedAt,
+			&i.FinishedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listStaleSubmissionsByProblemID = `-- name: ListStaleSubmissionsByProblemID :many
+SELECT s.submission_id, s.game_id, s.team_id, s.user_id, s.problem_id, s.language, s.code, s.code_size, s.status, s.is_practice, s.created_at FROM submissions AS s
+WHERE s.problem_id = $1 AND s.status <> 'running'
+  AND COALESCE(
+      (SELECT MAX(r.created_at) FROM testcase_results AS r WHERE r.submission_id = s.submission_id),
+      s.created_at
+  ) < (
+      SELECT MAX(v.created_at) FROM testcase_revisions AS v
+      WHERE v.problem_id = s.problem_id AND v.affects_results
+  )
+ORDER BY s.submission_id
+`
+
+func (q *Queries) ListStaleSubmissionsByProblemID(ctx context.Context, problemID int32) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listStaleSubmissionsByProblemID, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.GameID,
+			&i.TeamID,
+			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
+			&i.Code,
+			&i.CodeSize,
+			&i.Status,
+			&i.IsPractice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionIDs = `-- name: ListSubmissionIDs :many
+SELECT submission_id FROM submissions
+`
+
+func (q *Queries) ListSubmissionIDs(ctx context.Context) ([]int32, error) {
+	rows, err := q.db.Query(ctx, listSubmissionIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int32
+	for rows.Next() {
+		var submission_id int32
+		if err := rows.Scan(&submission_id); err != nil {
+			return nil, err
+		}
+		items = append(items, submission_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionsByGameIDAfter = `-- name: ListSubmissionsByGameIDAfter :many
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at
+FROM submissions
+WHERE game_id = $1 AND submission_id > $2
+ORDER BY submission_id
+LIMIT $3
+`
+
+type ListSubmissionsByGameIDAfterParams struct {
+	GameID       int32
+	SubmissionID int32
+	Limit        int32
+}
+
+func (q *Queries) ListSubmissionsByGameIDAfter(ctx context.Context, arg ListSubmissionsByGameIDAfterParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmissionsByGameIDAfter, arg.GameID, arg.SubmissionID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.GameID,
+			&i.TeamID,
+			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
+			&i.Code,
+			&i.CodeSize,
+			&i.Status,
+			&i.IsPractice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSubmissionsByProblemID = `-- name: ListSubmissionsByProblemID :many
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at FROM submissions
+WHERE problem_id = $1
+ORDER BY submission_id
+`
+
+func (q *Queries) ListSubmissionsByProblemID(ctx context.Context, problemID int32) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSubmissionsByProblemID, problemID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.GameID,
+			&i.TeamID,
+			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
+			&i.Code,
+			&i.CodeSize,
+			&i.Status,
+			&i.IsPractice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listSuccessfulSubmissionsAfter = `-- name: ListSuccessfulSubmissionsAfter :many
+SELECT submission_id, game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice, created_at FROM submissions
+WHERE game_id = $1 AND status = 'success' AND NOT is_practice AND created_at > $2
+ORDER BY created_at
+`
+
+type ListSuccessfulSubmissionsAfterParams struct {
+	GameID    int32
+	CreatedAt pgtype.Timestamp
+}
+
+func (q *Queries) ListSuccessfulSubmissionsAfter(ctx context.Context, arg ListSuccessfulSubmissionsAfterParams) ([]Submission, error) {
+	rows, err := q.db.Query(ctx, listSuccessfulSubmissionsAfter, arg.GameID, arg.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Submission
+	for rows.Next() {
+		var i Submission
+		if err := rows.Scan(
+			&i.SubmissionID,
+			&i.GameID,
+			&i.TeamID,
+			&i.UserID,
+			&i.ProblemID,
+			&i.Language,
+			&i.Code,
+			&i.CodeSize,
+			&i.Status,
+			&i.IsPractice,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) ListMainPlayers(ctx context.Context, dollar_1 []int32) ([]ListMainPlayersRow, error) {
-	rows, err := q.db.Query(ctx, listMainPlayers, dollar_1)
+const listTeamMembers = `-- name: ListTeamMembers :many
+SELECT game_team_members.team_id, users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.rating, users.rating_rank, users.created_at FROM game_team_members
+JOIN users ON game_team_members.user_id = users.user_id
+WHERE game_team_members.game_id = $1
+ORDER BY game_team_members.team_id, users.user_id
+`
+
+type ListTeamMembersRow struct {
+	TeamID int32
+	User   User
+}
+
+func (q *Queries) ListTeamMembers(ctx context.Context, gameID int32) ([]ListTeamMembersRow, error) {
+	rows, err := q.db.Query(ctx, listTeamMembers, gameID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListMainPlayersRow
+	var items []ListTeamMembersRow
 	for rows.Next() {
-		var i ListMainPlayersRow
+		var i ListTeamMembersRow
 		if err := rows.Scan(
-			&i.GameID,
-			&i.UserID,
-			&i.UserID_2,
-			&i.Username,
-			&i.DisplayName,
-			&i.IconPath,
-			&i.IsAdmin,
-			&i.Label,
-			&i.CreatedAt,
+			&i.TeamID,
+			&i.User.UserID,
+			&i.User.Username,
+			&i.User.DisplayName,
+			&i.User.IconPath,
+			&i.User.IsAdmin,
+			&i.User.Label,
+			&i.User.Rating,
+			&i.User.RatingRank,
+			&i.User.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1023,27 +2793,22 @@ func (q *Queries) ListMainPlayers(ctx context.Context, dollar_1 []int32) ([]List
 	return items, nil
 }
 
-const listProblems = `-- name: ListProblems :many
-SELECT problem_id, title, description, language, sample_code FROM problems
-ORDER BY problem_id
+const listTeams = `-- name: ListTeams :many
+SELECT team_id, game_id, display_name FROM game_teams
+WHERE game_id = $1
+ORDER BY team_id
 `
 
-func (q *Queries) ListProblems(ctx context.Context) ([]Problem, error) {
-	rows, err := q.db.Query(ctx, listProblems)
+func (q *Queries) ListTeams(ctx context.Context, gameID int32) ([]GameTeam, error) {
+	rows, err := q.db.Query(ctx, listTeams, gameID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Problem
+	var items []GameTeam
 	for rows.Next() {
-		var i Problem
-		if err := rows.Scan(
-			&i.ProblemID,
-			&i.Title,
-			&i.Description,
-			&i.Language,
-			&i.SampleCode,
-		); err != nil {
+		var i GameTeam
+		if err := rows.Scan(&i.TeamID, &i.GameID, &i.DisplayName); err != nil {
 			return nil, err
 		}
 		items = append(items, i)
@@ -1054,52 +2819,50 @@ func (q *Queries) ListProblems(ctx context.Context) ([]Problem, error) {
 	return items, nil
 }
 
-const listPublicGames = `-- name: ListPublicGames :many
-SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, games.problem_id, problems.problem_id, title, description, language, sample_code FROM games
-JOIN problems ON games.problem_id = problems.problem_id
-WHERE is_public = true
-ORDER BY games.game_id
+const listTestcaseResultsByGameIDAfter = `-- name: ListTestcaseResultsByGameIDAfter :many
+SELECT
+    testcase_results.testcase_result_id,
+    testcase_results.submission_id,
+    testcase_results.testcase_id,
+    testcase_results.status,
+    testcases.is_sample
+FROM testcase_results
+JOIN submissions ON testcase_results.submission_id = submissions.submission_id
+JOIN testcases ON testcase_results.testcase_id = testcases.testcase_id
+WHERE submissions.game_id = $1 AND testcase_results.testcase_result_id > $2
+ORDER BY testcase_results.testcase_result_id
+LIMIT $3
 `
 
-type ListPublicGamesRow struct {
-	GameID          int32
-	GameType        string
-	IsPublic        bool
-	DisplayName     string
-	DurationSeconds int32
-	CreatedAt       pgtype.Timestamp
-	StartedAt       pgtype.Timestamp
-	ProblemID       int32
-	ProblemID_2     int32
-	Title           string
-	Description     string
-	Language        string
-	SampleCode      string
+type ListTestcaseResultsByGameIDAfterParams struct {
+	GameID           int32
+	TestcaseResultID int32
+	Limit            int32
 }
 
-func (q *Queries) ListPublicGames(ctx context.Context) ([]ListPublicGamesRow, error) {
-	rows, err := q.db.Query(ctx, listPublicGames)
+type ListTestcaseResultsByGameIDAfterRow struct {
+	TestcaseResultID int32
+	SubmissionID     int32
+	TestcaseID       int32
+	Status           string
+	IsSample         bool
+}
+
+func (q *Queries) ListTestcaseResultsByGameIDAfter(ctx context.Context, arg ListTestcaseResultsByGameIDAfterParams) ([]ListTestcaseResultsByGameIDAfterRow, error) {
+	rows, err := q.db.Query(ctx, listTestcaseResultsByGameIDAfter, arg.GameID, arg.TestcaseResultID, arg.Limit)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []ListPublicGamesRow
+	var items []ListTestcaseResultsByGameIDAfterRow
 	for rows.Next() {
-		var i ListPublicGamesRow
+		var i ListTestcaseResultsByGameIDAfterRow
 		if err := rows.Scan(
-			&i.GameID,
-			&i.GameType,
-			&i.IsPublic,
-			&i.DisplayName,
-			&i.DurationSeconds,
-			&i.CreatedAt,
-			&i.StartedAt,
-			&i.ProblemID,
-			&i.ProblemID_2,
-			&i.Title,
-			&i.Description,
-			&i.Language,
-			&i.SampleCode,
+			&i.TestcaseResultID,
+			&i.SubmissionID,
+			&i.TestcaseID,
+			&i.Status,
+			&i.IsSample,
 		); err != nil {
 			return nil, err
 		}
@@ -1111,23 +2874,51 @@ func (q *Queries) ListPublicGames(ctx context.Context) ([]ListPublicGamesRow, er
 	return items, nil
 }
 
-const listSubmissionIDs = `-- name: ListSubmissionIDs :many
-SELECT submission_id FROM submissions
+const listTestcaseResultsWithTestcaseBySubmissionID = `-- name: ListTestcaseResultsWithTestcaseBySubmissionID :many
+SELECT
+    testcase_results.testcase_result_id, testcase_results.submission_id, testcase_results.testcase_id, testcase_results.status, testcase_results.stdout, testcase_results.stderr, testcase_results.time_ms, testcase_results.memory_bytes, testcase_results.created_at,
+    testcases.testcase_id, testcases.problem_id, testcases.stdin, testcases.stdout, testcases.is_sample, testcases.time_limit_ms, testcases.memory_limit_mib
+FROM testcase_results
+JOIN testcases ON testcase_results.testcase_id = testcases.testcase_id
+WHERE testcase_results.submission_id = $1
+ORDER BY testcases.testcase_id
 `
 
-func (q *Queries) ListSubmissionIDs(ctx context.Context) ([]int32, error) {
-	rows, err := q.db.Query(ctx, listSubmissionIDs)
+type ListTestcaseResultsWithTestcaseBySubmissionIDRow struct {
+	TestcaseResult TestcaseResult
+	Testcase       Testcase
+}
+
+func (q *Queries) ListTestcaseResultsWithTestcaseBySubmissionID(ctx context.Context, submissionID int32) ([]ListTestcaseResultsWithTestcaseBySubmissionIDRow, error) {
+	rows, err := q.db.Query(ctx, listTestcaseResultsWithTestcaseBySubmissionID, submissionID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []int32
+	var items []ListTestcaseResultsWithTestcaseBySubmissionIDRow
 	for rows.Next() {
-		var submission_id int32
-		if err := rows.Scan(&submission_id); err != nil {
+		var i ListTestcaseResultsWithTestcaseBySubmissionIDRow
+		if err := rows.Scan(
+			&i.TestcaseResult.TestcaseResultID,
+			&i.TestcaseResult.SubmissionID,
+			&i.TestcaseResult.TestcaseID,
+			&i.TestcaseResult.Status,
+			&i.TestcaseResult.Stdout,
+			&i.TestcaseResult.Stderr,
+			&i.TestcaseResult.TimeMs,
+			&i.TestcaseResult.MemoryBytes,
+			&i.TestcaseResult.CreatedAt,
+			&i.Testcase.TestcaseID,
+			&i.Testcase.ProblemID,
+			&i.Testcase.Stdin,
+			&i.Testcase.Stdout,
+			&i.Testcase.IsSample,
+			&i.Testcase.TimeLimitMs,
+			&i.Testcase.MemoryLimitMib,
+		); err != nil {
 			return nil, err
 		}
-		items = append(items, submission_id)
+		items = append(items, i)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -1135,25 +2926,32 @@ func (q *Queries) ListSubmissionIDs(ctx context.Context) ([]int32, error) {
 	return items, nil
 }
 
-const listTestcases = `-- name: ListTestcases :many
-SELECT testcase_id, problem_id, stdin, stdout FROM testcases
-ORDER BY testcase_id
+const listTestcaseRevisionsByProblemID = `-- name: ListTestcaseRevisionsByProblemID :many
+SELECT testcase_revision_id, testcase_id, problem_id, action, stdin, stdout, is_sample, affects_results, user_id, created_at FROM testcase_revisions
+WHERE problem_id = $1
+ORDER BY testcase_revision_id DESC
 `
 
-func (q *Queries) ListTestcases(ctx context.Context) ([]Testcase, error) {
-	rows, err := q.db.Query(ctx, listTestcases)
+func (q *Queries) ListTestcaseRevisionsByProblemID(ctx context.Context, problemID int32) ([]TestcaseRevision, error) {
+	rows, err := q.db.Query(ctx, listTestcaseRevisionsByProblemID, problemID)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	var items []Testcase
+	var items []TestcaseRevision
 	for rows.Next() {
-		var i Testcase
+		var i TestcaseRevision
 		if err := rows.Scan(
+			&i.TestcaseRevisionID,
 			&i.TestcaseID,
 			&i.ProblemID,
+			&i.Action,
 			&i.Stdin,
 			&i.Stdout,
+			&i.IsSample,
+			&i.AffectsResults,
+			&i.UserID,
+			&i.CreatedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -1165,14 +2963,13 @@ func (q *Queries) ListTestcases(ctx context.Context) ([]Testcase, error) {
 	return items, nil
 }
 
-const listTestcasesByGameID = `-- name: ListTestcasesByGameID :many
-SELECT testcase_id, problem_id, stdin, stdout FROM testcases
-WHERE testcases.problem_id = (SELECT problem_id FROM games WHERE game_id = $1)
-ORDER BY testcases.testcase_id
+const listTestcases = `-- name: ListTestcases :many
+SELECT testcase_id, problem_id, stdin, stdout, is_sample, time_limit_ms, memory_limit_mib FROM testcases
+ORDER BY testcase_id
 `
 
-func (q *Queries) ListTestcasesByGameID(ctx context.Context, gameID int32) ([]Testcase, error) {
-	rows, err := q.db.Query(ctx, listTestcasesByGameID, gameID)
+func (q *Queries) ListTestcases(ctx context.Context) ([]Testcase, error) {
+	rows, err := q.db.Query(ctx, listTestcases)
 	if err != nil {
 		return nil, err
 	}
@@ -1185,6 +2982,9 @@ func (q *Queries) ListTestcasesByGameID(ctx context.Context, gameID int32) ([]Te
 			&i.ProblemID,
 			&i.Stdin,
 			&i.Stdout,
+			&i.IsSample,
+			&i.TimeLimitMs,
+			&i.MemoryLimitMib,
 		); err != nil {
 			return nil, err
 		}
@@ -1197,7 +2997,7 @@ func (q *Queries) ListTestcasesByGameID(ctx context.Context, gameID int32) ([]Te
 }
 
 const listTestcasesByProblemID = `-- name: ListTestcasesByProblemID :many
-SELECT testcase_id, problem_id, stdin, stdout FROM testcases
+SELECT testcase_id, problem_id, stdin, stdout, is_sample, time_limit_ms, memory_limit_mib FROM testcases
 WHERE problem_id = $1
 ORDER BY testcase_id
 `
@@ -1216,6 +3016,9 @@ func (q *Queries) ListTestcasesByProblemID(ctx context.Context, problemID int32)
 			&i.ProblemID,
 			&i.Stdin,
 			&i.Stdout,
+			&i.IsSample,
+			&i.TimeLimitMs,
+			&i.MemoryLimitMib,
 		); err != nil {
 			return nil, err
 		}
@@ -1228,7 +3031,7 @@ func (q *Queries) ListTestcasesByProblemID(ctx context.Context, problemID int32)
 }
 
 const listTournamentEntries = `-- name: ListTournamentEntries :many
-SELECT tournament_entries.tournament_entry_id, tournament_entries.tournament_id, tournament_entries.user_id, tournament_entries.seed, users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.created_at
+SELECT tournament_entries.tournament_entry_id, tournament_entries.tournament_id, tournament_entries.user_id, tournament_entries.seed, users.user_id, users.username, users.display_name, users.icon_path, users.is_admin, users.label, users.rating, users.rating_rank, users.created_at
 FROM tournament_entries
 JOIN users ON tournament_entries.user_id = users.user_id
 WHERE tournament_entries.tournament_id = $1
@@ -1246,6 +3049,8 @@ type ListTournamentEntriesRow struct {
 	IconPath          *string
 	IsAdmin           bool
 	Label             *string
+	Rating            int32
+	RatingRank        *int32
 	CreatedAt         pgtype.Timestamp
 }
 
@@ -1269,6 +3074,8 @@ func (q *Queries) ListTournamentEntries(ctx context.Context, tournamentID int32)
 			&i.IconPath,
 			&i.IsAdmin,
 			&i.Label,
+			&i.Rating,
+			&i.RatingRank,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -1344,8 +3151,50 @@ func (q *Queries) ListTournaments(ctx context.Context) ([]Tournament, error) {
 	return items, nil
 }
 
+const listUnratedGames = `-- name: ListUnratedGames :many
+SELECT game_id, game_type, is_public, display_name, duration_seconds, created_at, started_at, paused_at, override_state, freeze_seconds, revealed_until, tie_break, unsolved_penalty, allow_practice, rated_at FROM games
+WHERE rated_at IS NULL AND started_at IS NOT NULL AND is_public
+ORDER BY started_at, game_id
+`
+
+func (q *Queries) ListUnratedGames(ctx context.Context) ([]Game, error) {
+	rows, err := q.db.Query(ctx, listUnratedGames)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Game
+	for rows.Next() {
+		var i Game
+		if err := rows.Scan(
+			&i.GameID,
+			&i.GameType,
+			&i.IsPublic,
+			&i.DisplayName,
+			&i.DurationSeconds,
+			&i.CreatedAt,
+			&i.StartedAt,
+			&i.PausedAt,
+			&i.OverrideState,
+			&i.FreezeSeconds,
+			&i.RevealedUntil,
+			&i.TieBreak,
+			&i.UnsolvedPenalty,
+			&i.AllowPractice,
+			&i.RatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listUsers = `-- name: ListUsers :many
-SELECT user_id, username, display_name, icon_path, is_admin, label, created_at FROM users
+SELECT user_id, username, display_name, icon_path, is_admin, label, rating, rating_rank, created_at FROM users
 ORDER BY users.user_id
 `
 
@@ -1365,6 +3214,8 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 			&i.IconPath,
 			&i.IsAdmin,
 			&i.Label,
+			&i.Rating,
+			&i.RatingRank,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -1377,6 +3228,16 @@ func (q *Queries) ListUsers(ctx context.Context) ([]User, error) {
 	return items, nil
 }
 
+const removeAllGameProblems = `-- name: RemoveAllGameProblems :exec
+DELETE FROM game_problems
+WHERE game_id = $1
+`
+
+func (q *Queries) RemoveAllGameProblems(ctx context.Context, gameID int32) error {
+	_, err := q.db.Exec(ctx, removeAllGameProblems, gameID)
+	return err
+}
+
 const removeAllMainPlayers = `-- name: RemoveAllMainPlayers :exec
 DELETE FROM game_main_players
 WHERE game_id = $1
@@ -1387,45 +3248,90 @@ func (q *Queries) RemoveAllMainPlayers(ctx context.Context, gameID int32) error
 	return err
 }
 
+const removeAllQualifyingStageGames = `-- name: RemoveAllQualifyingStageGames :exec
+DELETE FROM qualifying_stage_games
+WHERE qualifying_stage_id = $1
+`
+
+func (q *Queries) RemoveAllQualifyingStageGames(ctx context.Context, qualifyingStageID int32) error {
+	_, err := q.db.Exec(ctx, removeAllQualifyingStageGames, qualifyingStageID)
+	return err
+}
+
+const removeAllTeamMembers = `-- name: RemoveAllTeamMembers :exec
+DELETE FROM game_team_members
+WHERE game_id = $1
+`
+
+func (q *Queries) RemoveAllTeamMembers(ctx context.Context, gameID int32) error {
+	_, err := q.db.Exec(ctx, removeAllTeamMembers, gameID)
+	return err
+}
+
+const removeAllTeams = `-- name: RemoveAllTeams :exec
+DELETE FROM game_teams
+WHERE game_id = $1
+`
+
+func (q *Queries) RemoveAllTeams(ctx context.Context, gameID int32) error {
+	_, err := q.db.Exec(ctx, removeAllTeams, gameID)
+	return err
+}
+
+const resetUserRatings = `-- name: ResetUserRatings :exec
+UPDATE users
+SET rating = $1, rating_rank = NULL
+`
+
+func (q *Queries) ResetUserRatings(ctx context.Context, rating int32) error {
+	_, err := q.db.Exec(ctx, resetUserRatings, rating)
+	return err
+}
+
 const syncGameStateBestScoreSubmission = `-- name: SyncGameStateBestScoreSubmission :exec
 UPDATE game_states
 SET best_score_submission_id = (
     SELECT submission_id FROM submissions AS s
-    WHERE s.game_id = $1 AND s.user_id = $2 AND s.status = 'success'
+    WHERE s.game_id = $1 AND s.team_id = $2 AND s.problem_id = $3 AND s.status = 'success' AND NOT s.is_practice
     ORDER BY s.code_size ASC, s.created_at ASC
     LIMIT 1
 )
-WHERE game_id = $1 AND user_id = $2
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3
 `
 
 type SyncGameStateBestScoreSubmissionParams struct {
-	GameID int32
-	UserID int32
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
 }
 
 func (q *Queries) SyncGameStateBestScoreSubmission(ctx context.Context, arg SyncGameStateBestScoreSubmissionParams) error {
-	_, err := q.db.Exec(ctx, syncGameStateBestScoreSubmission, arg.GameID, arg.UserID)
+	_, err := q.db.Exec(ctx, syncGameStateBestScoreSubmission, arg.GameID, arg.TeamID, arg.ProblemID)
 	return err
 }
 
 const updateCode = `-- name: UpdateCode :exec
-INSERT INTO game_states (game_id, user_id, code, status)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (game_id, user_id)
-DO UPDATE SET code = EXCLUDED.code
+INSERT INTO game_states (game_id, team_id, problem_id, language, code, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (game_id, team_id, problem_id)
+DO UPDATE SET language = EXCLUDED.language, code = EXCLUDED.code
 `
 
 type UpdateCodeParams struct {
-	GameID int32
-	UserID int32
-	Code   string
-	Status string
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+	Language  string
+	Code      string
+	Status    string
 }
 
 func (q *Queries) UpdateCode(ctx context.Context, arg UpdateCodeParams) error {
 	_, err := q.db.Exec(ctx, updateCode,
 		arg.GameID,
-		arg.UserID,
+		arg.TeamID,
+		arg.ProblemID,
+		arg.Language,
 		arg.Code,
 		arg.Status,
 	)
@@ -1433,23 +3339,27 @@ func (q *Queries) UpdateCode(ctx context.Context, arg UpdateCodeParams) error {
 }
 
 const updateCodeAndStatus = `-- name: UpdateCodeAndStatus :exec
-INSERT INTO game_states (game_id, user_id, code, status)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (game_id, user_id)
-DO UPDATE SET code = EXCLUDED.code, status = EXCLUDED.status
+INSERT INTO game_states (game_id, team_id, problem_id, language, code, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (game_id, team_id, problem_id)
+DO UPDATE SET language = EXCLUDED.language, code = EXCLUDED.code, status = EXCLUDED.status
 `
 
 type UpdateCodeAndStatusParams struct {
-	GameID int32
-	UserID int32
-	Code   string
-	Status string
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+	Language  string
+	Code      string
+	Status    string
 }
 
 func (q *Queries) UpdateCodeAndStatus(ctx context.Context, arg UpdateCodeAndStatusParams) error {
 	_, err := q.db.Exec(ctx, updateCodeAndStatus,
 		arg.GameID,
-		arg.UserID,
+		arg.TeamID,
+		arg.ProblemID,
+		arg.Language,
 		arg.Code,
 		arg.Status,
 	)
@@ -1462,9 +3372,10 @@ SET
     game_type = $2,
     is_public = $3,
     display_name = $4,
-    duration_seconds = $5,
-    started_at = $6,
-    problem_id = $7
+    freeze_seconds = $5,
+    tie_break = $6,
+    unsolved_penalty = $7,
+    allow_practice = $8
 WHERE game_id = $1
 `
 
@@ -1473,9 +3384,10 @@ type UpdateGameParams struct {
 	GameType        string
 	IsPublic        bool
 	DisplayName     string
-	DurationSeconds int32
-	StartedAt       pgtype.Timestamp
-	ProblemID       int32
+	FreezeSeconds   int32
+	TieBreak        string
+	UnsolvedPenalty int32
+	AllowPractice   bool
 }
 
 func (q *Queries) UpdateGame(ctx context.Context, arg UpdateGameParams) error {
@@ -1484,43 +3396,90 @@ func (q *Queries) UpdateGame(ctx context.Context, arg UpdateGameParams) error {
 		arg.GameType,
 		arg.IsPublic,
 		arg.DisplayName,
-		arg.DurationSeconds,
+		arg.FreezeSeconds,
+		arg.TieBreak,
+		arg.UnsolvedPenalty,
+		arg.AllowPractice,
+	)
+	return err
+}
+
+const updateGameLifecycle = `-- name: UpdateGameLifecycle :exec
+UPDATE games
+SET
+    started_at = $2,
+    duration_seconds = $3,
+    paused_at = $4,
+    override_state = $5
+WHERE game_id = $1
+`
+
+type UpdateGameLifecycleParams struct {
+	GameID          int32
+	StartedAt       pgtype.Timestamp
+	DurationSeconds int32
+	PausedAt        pgtype.Timestamp
+	OverrideState   *string
+}
+
+func (q *Queries) UpdateGameLifecycle(ctx context.Context, arg UpdateGameLifecycleParams) error {
+	_, err := q.db.Exec(ctx, updateGameLifecycle,
+		arg.GameID,
 		arg.StartedAt,
-		arg.ProblemID,
+		arg.DurationSeconds,
+		arg.PausedAt,
+		arg.OverrideState,
 	)
 	return err
 }
 
-const updateGameStartedAt = `-- name: UpdateGameStartedAt :exec
+const updateGameRatedAt = `-- name: UpdateGameRatedAt :exec
 UPDATE games
-SET started_at = $2
+SET rated_at = NOW()
 WHERE game_id = $1
 `
 
-type UpdateGameStartedAtParams struct {
-	GameID    int32
-	StartedAt pgtype.Timestamp
+func (q *Queries) UpdateGameRatedAt(ctx context.Context, gameID int32) error {
+	_, err := q.db.Exec(ctx, updateGameRatedAt, gameID)
+	return err
+}
+
+const updateGameRevealedUntil = `-- name: UpdateGameRevealedUntil :exec
+UPDATE games
+SET revealed_until = $2
+WHERE game_id = $1
+`
+
+type UpdateGameRevealedUntilParams struct {
+	GameID        int32
+	RevealedUntil pgtype.Timestamp
 }
 
-func (q *Queries) UpdateGameStartedAt(ctx context.Context, arg UpdateGameStartedAtParams) error {
-	_, err := q.db.Exec(ctx, updateGameStartedAt, arg.GameID, arg.StartedAt)
+func (q *Queries) UpdateGameRevealedUntil(ctx context.Context, arg UpdateGameRevealedUntilParams) error {
+	_, err := q.db.Exec(ctx, updateGameRevealedUntil, arg.GameID, arg.RevealedUntil)
 	return err
 }
 
 const updateGameStateStatus = `-- name: UpdateGameStateStatus :exec
 UPDATE game_states
-SET status = $3
-WHERE game_id = $1 AND user_id = $2
+SET status = $4
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3
 `
 
 type UpdateGameStateStatusParams struct {
-	GameID int32
-	UserID int32
-	Status string
+	GameID    int32
+	TeamID    int32
+	ProblemID int32
+	Status    string
 }
 
 func (q *Queries) UpdateGameStateStatus(ctx context.Context, arg UpdateGameStateStatusParams) error {
-	_, err := q.db.Exec(ctx, updateGameStateStatus, arg.GameID, arg.UserID, arg.Status)
+	_, err := q.db.Exec(ctx, updateGameStateStatus,
+		arg.GameID,
+		arg.TeamID,
+		arg.ProblemID,
+		arg.Status,
+	)
 	return err
 }
 
@@ -1530,16 +3489,33 @@ SET
     title = $2,
     description = $3,
     language = $4,
-    sample_code = $5
+    sample_code = $5,
+    scoring = $6,
+    checker = $7,
+    checker_epsilon = $8,
+    checker_code = $9,
+    time_limit_ms = $10,
+    memory_limit_mib = $11,
+    judge_changed_at = CASE
+        WHEN (language, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib)
+            IS DISTINCT FROM ($4, $7, $8, $9, $10::INT, $11::INT) THEN NOW()
+        ELSE judge_changed_at
+    END
 WHERE problem_id = $1
 `
 
 type UpdateProblemParams struct {
-	ProblemID   int32
-	Title       string
-	Description string
-	Language    string
-	SampleCode  string
+	ProblemID      int32
+	Title          string
+	Description    string
+	Language       string
+	SampleCode     string
+	Scoring        string
+	Checker        string
+	CheckerEpsilon float64
+	CheckerCode    string
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
 }
 
 func (q *Queries) UpdateProblem(ctx context.Context, arg UpdateProblemParams) error {
@@ -1549,10 +3525,80 @@ func (q *Queries) UpdateProblem(ctx context.Context, arg UpdateProblemParams) er
 		arg.Description,
 		arg.Language,
 		arg.SampleCode,
+		arg.Scoring,
+		arg.Checker,
+		arg.CheckerEpsilon,
+		arg.CheckerCode,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMib,
+	)
+	return err
+}
+
+const updateQualifyingStage = `-- name: UpdateQualifyingStage :exec
+UPDATE qualifying_stages
+SET
+    display_name = $2,
+    is_public = $3,
+    missing_policy = $4,
+    missing_penalty = $5
+WHERE qualifying_stage_id = $1
+`
+
+type UpdateQualifyingStageParams struct {
+	QualifyingStageID int32
+	DisplayName       string
+	IsPublic          bool
+	MissingPolicy     string
+	MissingPenalty    int32
+}
+
+func (q *Queries) UpdateQualifyingStage(ctx context.Context, arg UpdateQualifyingStageParams) error {
+	_, err := q.db.Exec(ctx, updateQualifyingStage,
+		arg.QualifyingStageID,
+		arg.DisplayName,
+		arg.IsPublic,
+		arg.MissingPolicy,
+		arg.MissingPenalty,
 	)
 	return err
 }
 
+const updateRatingRanks = `-- name: UpdateRatingRanks :exec
+UPDATE users
+SET rating_rank = ranked.rating_rank
+FROM (
+    SELECT users.user_id, RANK() OVER (ORDER BY users.rating DESC) AS rating_rank
+    FROM users
+    WHERE EXISTS (
+        SELECT 1 FROM rating_history
+        WHERE rating_history.user_id = users.user_id
+    )
+) AS ranked
+WHERE users.user_id = ranked.user_id
+`
+
+func (q *Queries) UpdateRatingRanks(ctx context.Context) error {
+	_, err := q.db.Exec(ctx, updateRatingRanks)
+	return err
+}
+
+const updateSubmissionCodeSize = `-- name: UpdateSubmissionCodeSize :exec
+UPDATE submissions
+SET code_size = $2
+WHERE submission_id = $1
+`
+
+type UpdateSubmissionCodeSizeParams struct {
+	SubmissionID int32
+	CodeSize     int32
+}
+
+func (q *Queries) UpdateSubmissionCodeSize(ctx context.Context, arg UpdateSubmissionCodeSizeParams) error {
+	_, err := q.db.Exec(ctx, updateSubmissionCodeSize, arg.SubmissionID, arg.CodeSize)
+	return err
+}
+
 const updateSubmissionStatus = `-- name: UpdateSubmissionStatus :exec
 UPDATE submissions
 SET status = $2
@@ -1574,15 +3620,21 @@ UPDATE testcases
 SET
     problem_id = $2,
     stdin = $3,
-    stdout = $4
+    stdout = $4,
+    is_sample = $5,
+    time_limit_ms = $6,
+    memory_limit_mib = $7
 WHERE testcase_id = $1
 `
 
 type UpdateTestcaseParams struct {
-	TestcaseID int32
-	ProblemID  int32
-	Stdin      string
-	Stdout     string
+	TestcaseID     int32
+	ProblemID      int32
+	Stdin          string
+	Stdout         string
+	IsSample       bool
+	TimeLimitMs    *int32
+	MemoryLimitMib *int32
 }
 
 func (q *Queries) UpdateTestcase(ctx context.Context, arg UpdateTestcaseParams) error {
@@ -1591,6 +3643,9 @@ func (q *Queries) UpdateTestcase(ctx context.Context, arg UpdateTestcaseParams)
 		arg.ProblemID,
 		arg.Stdin,
 		arg.Stdout,
+		arg.IsSample,
+		arg.TimeLimitMs,
+		arg.MemoryLimitMib,
 	)
 	return err
 }
@@ -1678,3 +3733,19 @@ func (q *Queries) UpdateUserIconPath(ctx context.Context, arg UpdateUserIconPath
 	_, err := q.db.Exec(ctx, updateUserIconPath, arg.UserID, arg.IconPath)
 	return err
 }
+
+const updateUserRating = `-- name: UpdateUserRating :exec
+UPDATE users
+SET rating = $2
+WHERE user_id = $1
+`
+
+type UpdateUserRatingParams struct {
+	UserID int32
+	Rating int32
+}
+
+func (q *Queries) UpdateUserRating(ctx context.Context, arg UpdateUserRatingParams) error {
+	_, err := q.db.Exec(ctx, updateUserRating, arg.UserID, arg.Rating)
+	return err
+}
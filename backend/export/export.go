@@ -0,0 +1,239 @@
+// Package export dumps the results of games for use outside the app, e.g. the
+// award ceremony or a report of the event. An archive is either a zip of CSV
+// files, one per table, or a single JSON document. Submissions are read in
+// batches while the archive is written, so that large games are not held in
+// memory.
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/game"
+)
+
+const (
+	// FormatCSV is a zip archive of CSV files.
+	FormatCSV = "csv"
+	// FormatJSON is a single JSON document.
+	FormatJSON = "json"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// batchSize is the number of rows read at once while streaming a table.
+const batchSize = 500
+
+type Service struct {
+	q db.Querier
+}
+
+func NewService(q db.Querier) *Service {
+	return &Service{q: q}
+}
+
+// FileType returns the content type and the file extension of an archive in
+// the format.
+func FileType(format string) (contentType, ext string, err error) {
+	switch format {
+	case FormatCSV:
+		return "application/zip", ".zip", nil
+	case FormatJSON:
+		return "application/json", ".json", nil
+	default:
+		return "", "", ErrUnknownFormat
+	}
+}
+
+// Archive is the set of games to export, with the tournament they are part of
+// if any. The rankings and the players are loaded up front, while the
+// submissions are read when the archive is written.
+type Archive struct {
+	// Name is the base name of the file to download.
+	Name string
+
+	q          db.Querier
+	tournament *db.Tournament
+	entries    []db.ListTournamentEntriesRow
+	matches    []db.TournamentMatch
+	games      []gameData
+}
+
+type gameData struct {
+	game     db.Game
+	problems []db.Problem
+	members  []db.ListTeamMembersRow
+	teams    []game.RankedTeam
+	ranks    []int
+}
+
+// Game returns the archive of a game. The ranking is the live one even during
+// the freeze.
+func (s *Service) Game(ctx context.Context, gameID int) (*Archive, error) {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, game.ErrNotFound
+		}
+		return nil, err
+	}
+	a := &Archive{Name: fmt.Sprintf("game-%d", gameID), q: s.q}
+	if err := a.addGame(ctx, gameRow); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// Tournament returns the archive of a tournament, made of the games of its
+// matches in bracket order.
+func (s *Service) Tournament(ctx context.Context, tournamentID int) (*Archive, error) {
+	t, err := s.q.GetTournamentByID(ctx, int32(tournamentID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, game.ErrNotFound
+		}
+		return nil, err
+	}
+	entries, err := s.q.ListTournamentEntries(ctx, t.TournamentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	matches, err := s.q.ListTournamentMatches(ctx, t.TournamentID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	a := &Archive{
+		Name:       fmt.Sprintf("tournament-%d", tournamentID),
+		q:          s.q,
+		tournament: &t,
+		entries:    entries,
+		matches:    matches,
+	}
+	for _, m := range matches {
+		if m.GameID == nil {
+			continue
+		}
+		gameRow, err := s.q.GetGameByID(ctx, *m.GameID)
+		if err != nil {
+			return nil, err
+		}
+		if err := a.addGame(ctx, gameRow); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (a *Archive) addGame(ctx context.Context, gameRow db.Game) error {
+	problemRows, err := a.q.ListGameProblems(ctx, []int32{gameRow.GameID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	problems := make([]db.Problem, len(problemRows))
+	for i, row := range problemRows {
+		problems[i] = row.Problem
+	}
+	members, err := a.q.ListTeamMembers(ctx, gameRow.GameID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	teams, ranks, err := game.RankedRows(ctx, a.q, gameRow, time.Time{}, false)
+	if err != nil {
+		return err
+	}
+	a.games = append(a.games, gameData{
+		game:     gameRow,
+		problems: problems,
+		members:  members,
+		teams:    teams,
+		ranks:    ranks,
+	})
+	return nil
+}
+
+// Write writes the archive to w in the format. Once it has started writing, an
+// error leaves w with a truncated archive.
+func (a *Archive) Write(ctx context.Context, w io.Writer, format string) error {
+	switch format {
+	case FormatCSV:
+		return a.writeCSV(ctx, w)
+	case FormatJSON:
+		return a.writeJSON(ctx, w)
+	default:
+		return ErrUnknownFormat
+	}
+}
+
+// maxProblems is the number of problems of the game with the most of them.
+func (a *Archive) maxProblems() int {
+	n := 0
+	for _, g := range a.games {
+		n = max(n, len(g.problems))
+	}
+	return n
+}
+
+// eachSubmission calls fn for the submissions of the game in order, reading
+// them batchSize at a time.
+func eachSubmission(ctx context.Context, q db.Querier, gameID int32, fn func(db.Submission) error) error {
+	var after int32
+	for {
+		rows, err := q.ListSubmissionsByGameIDAfter(ctx, db.ListSubmissionsByGameIDAfterParams{
+			GameID:       gameID,
+			SubmissionID: after,
+			Limit:        batchSize,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].SubmissionID
+	}
+}
+
+// eachTestcaseResult calls fn for the testcase results of the submissions to
+// the game in order, reading them batchSize at a time.
+func eachTestcaseResult(ctx context.Context, q db.Querier, gameID int32, fn func(db.ListTestcaseResultsByGameIDAfterRow) error) error {
+	var after int32
+	for {
+		rows, err := q.ListTestcaseResultsByGameIDAfter(ctx, db.ListTestcaseResultsByGameIDAfterParams{
+			GameID:           gameID,
+			TestcaseResultID: after,
+			Limit:            batchSize,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		for _, row := range rows {
+			if err := fn(row); err != nil {
+				return err
+			}
+		}
+		if len(rows) < batchSize {
+			return nil
+		}
+		after = rows[len(rows)-1].TestcaseResultID
+	}
+}
+
+func timePtr(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
+	}
+	t := ts.Time.UTC()
+	return &t
+}
@@ -0,0 +1,224 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+type mockQuerier struct {
+	db.Querier
+	ranking     []db.GetRankingRow
+	members     []db.ListTeamMembersRow
+	submissions []db.Submission
+	results     []db.ListTestcaseResultsByGameIDAfterRow
+	// batches counts the calls that read submissions.
+	batches int
+}
+
+func (m *mockQuerier) GetGameByID(_ context.Context, gameID int32) (db.Game, error) {
+	return db.Game{GameID: gameID, DisplayName: "Final", GameType: "multiplayer", TieBreak: "earliest_submission"}, nil
+}
+
+func (m *mockQuerier) GetRanking(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+	return m.ranking, nil
+}
+
+func (m *mockQuerier) ListGameProblems(_ context.Context, _ []int32) ([]db.ListGameProblemsRow, error) {
+	return []db.ListGameProblemsRow{{Problem: db.Problem{ProblemID: 1, Title: "Hello"}}}, nil
+}
+
+func (m *mockQuerier) ListTeamMembers(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+	return m.members, nil
+}
+
+func (m *mockQuerier) ListSubmissionsByGameIDAfter(_ context.Context, arg db.ListSubmissionsByGameIDAfterParams) ([]db.Submission, error) {
+	m.batches++
+	var rows []db.Submission
+	for _, s := range m.submissions {
+		if s.SubmissionID > arg.SubmissionID && len(rows) < int(arg.Limit) {
+			rows = append(rows, s)
+		}
+	}
+	return rows, nil
+}
+
+func (m *mockQuerier) ListTestcaseResultsByGameIDAfter(_ context.Context, arg db.ListTestcaseResultsByGameIDAfterParams) ([]db.ListTestcaseResultsByGameIDAfterRow, error) {
+	var rows []db.ListTestcaseResultsByGameIDAfterRow
+	for _, r := range m.results {
+		if r.TestcaseResultID > arg.TestcaseResultID && len(rows) < int(arg.Limit) {
+			rows = append(rows, r)
+		}
+	}
+	return rows, nil
+}
+
+func newTestQuerier() *mockQuerier {
+	at := pgtype.Timestamp{Time: time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC), Valid: true}
+	q := &mockQuerier{
+		ranking: []db.GetRankingRow{
+			{
+				Submission:      db.Submission{SubmissionID: 2, TeamID: 10, ProblemID: 1, CodeSize: 42, CreatedAt: at},
+				GameTeam:        db.GameTeam{TeamID: 10, GameID: 1, DisplayName: "alice"},
+				SubmissionCount: 2,
+			},
+		},
+		members: []db.ListTeamMembersRow{
+			{TeamID: 10, User: db.User{UserID: 1, Username: "alice", DisplayName: "Alice", Rating: 1500}},
+		},
+		results: []db.ListTestcaseResultsByGameIDAfterRow{
+			{TestcaseResultID: 1, SubmissionID: 2, TestcaseID: 7, Status: "success", IsSample: true},
+		},
+	}
+	// More than a batch, so that the submissions are read in several queries.
+	for i := range batchSize + 1 {
+		q.submissions = append(q.submissions, db.Submission{
+			SubmissionID: int32(i + 1),
+			GameID:       1,
+			TeamID:       10,
+			UserID:       1,
+			ProblemID:    1,
+			Language:     "php",
+			Code:         "<?php\necho 1;",
+			CodeSize:     42,
+			Status:       "success",
+			CreatedAt:    at,
+		})
+	}
+	return q
+}
+
+func TestArchive_WriteJSON(t *testing.T) {
+	q := newTestQuerier()
+	a, err := NewService(q).Game(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Game: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := a.Write(context.Background(), &buf, FormatJSON); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	var doc struct {
+		Tournament *tournamentRecord `json:"tournament"`
+		Games      []struct {
+			Game            gameRecord             `json:"game"`
+			Problems        []problemRecord        `json:"problems"`
+			Users           []userRecord           `json:"users"`
+			Ranking         []rankingRecord        `json:"ranking"`
+			Submissions     []submissionRecord     `json:"submissions"`
+			TestcaseResults []testcaseResultRecord `json:"testcase_results"`
+		} `json:"games"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Tournament != nil {
+		t.Errorf("tournament = %+v, want nil", doc.Tournament)
+	}
+	if len(doc.Games) != 1 {
+		t.Fatalf("len(games) = %d, want 1", len(doc.Games))
+	}
+	g := doc.Games[0]
+	if g.Game.GameID != 1 || g.Game.DisplayName != "Final" {
+		t.Errorf("game = %+v", g.Game)
+	}
+	if len(g.Problems) != 1 || g.Problems[0].Title != "Hello" {
+		t.Errorf("problems = %+v", g.Problems)
+	}
+	if len(g.Users) != 1 || g.Users[0].Username != "alice" {
+		t.Errorf("users = %+v", g.Users)
+	}
+	if len(g.Ranking) != 1 || g.Ranking[0].Rank != 1 || g.Ranking[0].Score != 42 {
+		t.Errorf("ranking = %+v", g.Ranking)
+	}
+	if len(g.Submissions) != batchSize+1 {
+		t.Errorf("len(submissions) = %d, want %d", len(g.Submissions), batchSize+1)
+	}
+	if q.batches != 2 {
+		t.Errorf("submission batches = %d, want 2", q.batches)
+	}
+	if len(g.TestcaseResults) != 1 || g.TestcaseResults[0].Status != "success" {
+		t.Errorf("testcase_results = %+v", g.TestcaseResults)
+	}
+}
+
+func TestArchive_WriteCSV(t *testing.T) {
+	q := newTestQuerier()
+	a, err := NewService(q).Game(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Game: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := a.Write(context.Background(), &buf, FormatCSV); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("invalid zip: %v", err)
+	}
+	records := make(map[string][][]string)
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("open %s: %v", f.Name, err)
+		}
+		records[f.Name], err = csv.NewReader(r).ReadAll()
+		r.Close()
+		if err != nil {
+			t.Fatalf("read %s: %v", f.Name, err)
+		}
+	}
+
+	wantRows := map[string]int{
+		"games.csv":            1,
+		"problems.csv":         1,
+		"users.csv":            1,
+		"ranking.csv":          1,
+		"submissions.csv":      batchSize + 1,
+		"testcase_results.csv": 1,
+	}
+	if len(records) != len(wantRows) {
+		t.Errorf("files = %d, want %d", len(records), len(wantRows))
+	}
+	for name, want := range wantRows {
+		// The first record is the header.
+		if got := len(records[name]) - 1; got != want {
+			t.Errorf("%s: rows = %d, want %d", name, got, want)
+		}
+	}
+	wantRanking := []string{"1", "1", "10", "alice", "1", "42", "2", "2026-03-01T10:00:00Z", "42"}
+	if got := records["ranking.csv"][1]; !slices.Equal(got, wantRanking) {
+		t.Errorf("ranking row = %q, want %q", got, wantRanking)
+	}
+	if got := records["submissions.csv"][1][5]; got != "php" {
+		t.Errorf("language = %q", got)
+	}
+	if got := records["submissions.csv"][1][10]; got != "<?php\necho 1;" {
+		t.Errorf("code = %q", got)
+	}
+}
+
+func TestArchive_WriteUnknownFormat(t *testing.T) {
+	a, err := NewService(newTestQuerier()).Game(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("Game: %v", err)
+	}
+	var buf bytes.Buffer
+	if err := a.Write(context.Background(), &buf, "xml"); err != ErrUnknownFormat {
+		t.Errorf("err = %v, want %v", err, ErrUnknownFormat)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("wrote %d bytes", buf.Len())
+	}
+}
@@ -0,0 +1,426 @@
+package export
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"time"
+
+	"albatross-2026-backend/db"
+)
+
+// record is a row of a table. It is written as the fields in CSV and as the
+// struct itself in JSON.
+type record interface {
+	fields() []string
+}
+
+// table is a table with a part for each game of the archive.
+type table struct {
+	name   string
+	header func(a *Archive) []string
+	rows   func(ctx context.Context, a *Archive, g *gameData, emit func(record) error) error
+}
+
+// gameTables are the tables of the games, in the order they are written.
+var gameTables = []table{
+	{name: "problems", header: fixedHeader(problemHeader), rows: problemRows},
+	{name: "users", header: fixedHeader(userHeader), rows: userRows},
+	{name: "ranking", header: rankingHeader, rows: rankingRows},
+	{name: "submissions", header: fixedHeader(submissionHeader), rows: submissionRows},
+	{name: "testcase_results", header: fixedHeader(testcaseResultHeader), rows: testcaseResultRows},
+}
+
+func fixedHeader(header []string) func(*Archive) []string {
+	return func(*Archive) []string { return header }
+}
+
+type tournamentRecord struct {
+	TournamentID int    `json:"tournament_id"`
+	DisplayName  string `json:"display_name"`
+	BracketSize  int    `json:"bracket_size"`
+	NumRounds    int    `json:"num_rounds"`
+	// Entries and Matches are separate files in CSV.
+	Entries []entryRecord `json:"entries"`
+	Matches []matchRecord `json:"matches"`
+}
+
+var tournamentHeader = []string{"tournament_id", "display_name", "bracket_size", "num_rounds"}
+
+func (r tournamentRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.TournamentID),
+		r.DisplayName,
+		strconv.Itoa(r.BracketSize),
+		strconv.Itoa(r.NumRounds),
+	}
+}
+
+type entryRecord struct {
+	Seed        int     `json:"seed"`
+	UserID      int     `json:"user_id"`
+	Username    string  `json:"username"`
+	DisplayName string  `json:"display_name"`
+	Label       *string `json:"label"`
+}
+
+var entryHeader = []string{"seed", "user_id", "username", "display_name", "label"}
+
+func (r entryRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.Seed),
+		strconv.Itoa(r.UserID),
+		r.Username,
+		r.DisplayName,
+		stringField(r.Label),
+	}
+}
+
+type matchRecord struct {
+	Round    int  `json:"round"`
+	Position int  `json:"position"`
+	GameID   *int `json:"game_id"`
+}
+
+var matchHeader = []string{"round", "position", "game_id"}
+
+func (r matchRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.Round),
+		strconv.Itoa(r.Position),
+		intField(r.GameID),
+	}
+}
+
+func (a *Archive) tournamentRecord() tournamentRecord {
+	t := a.tournament
+	entries := make([]entryRecord, len(a.entries))
+	for i, e := range a.entries {
+		entries[i] = entryRecord{
+			Seed:        int(e.Seed),
+			UserID:      int(e.UserID),
+			Username:    e.Username,
+			DisplayName: e.DisplayName,
+			Label:       e.Label,
+		}
+	}
+	matches := make([]matchRecord, len(a.matches))
+	for i, m := range a.matches {
+		matches[i] = matchRecord{
+			Round:    int(m.Round),
+			Position: int(m.Position),
+		}
+		if m.GameID != nil {
+			gameID := int(*m.GameID)
+			matches[i].GameID = &gameID
+		}
+	}
+	return tournamentRecord{
+		TournamentID: int(t.TournamentID),
+		DisplayName:  t.DisplayName,
+		BracketSize:  int(t.BracketSize),
+		NumRounds:    int(t.NumRounds),
+		Entries:      entries,
+		Matches:      matches,
+	}
+}
+
+type gameRecord struct {
+	GameID          int        `json:"game_id"`
+	DisplayName     string     `json:"display_name"`
+	GameType        string     `json:"game_type"`
+	StartedAt       *time.Time `json:"started_at"`
+	DurationSeconds int        `json:"duration_seconds"`
+	TieBreak        string     `json:"tie_break"`
+	UnsolvedPenalty int        `json:"unsolved_penalty"`
+}
+
+var gameHeader = []string{"game_id", "display_name", "game_type", "started_at", "duration_seconds", "tie_break", "unsolved_penalty"}
+
+func (r gameRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.GameID),
+		r.DisplayName,
+		r.GameType,
+		timeField(r.StartedAt),
+		strconv.Itoa(r.DurationSeconds),
+		r.TieBreak,
+		strconv.Itoa(r.UnsolvedPenalty),
+	}
+}
+
+func newGameRecord(g db.Game) gameRecord {
+	return gameRecord{
+		GameID:          int(g.GameID),
+		DisplayName:     g.DisplayName,
+		GameType:        g.GameType,
+		StartedAt:       timePtr(g.StartedAt),
+		DurationSeconds: int(g.DurationSeconds),
+		TieBreak:        g.TieBreak,
+		UnsolvedPenalty: int(g.UnsolvedPenalty),
+	}
+}
+
+type problemRecord struct {
+	GameID    int    `json:"game_id"`
+	Position  int    `json:"position"`
+	ProblemID int    `json:"problem_id"`
+	Title     string `json:"title"`
+}
+
+var problemHeader = []string{"game_id", "position", "problem_id", "title"}
+
+func (r problemRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.GameID),
+		strconv.Itoa(r.Position),
+		strconv.Itoa(r.ProblemID),
+		r.Title,
+	}
+}
+
+func problemRows(_ context.Context, _ *Archive, g *gameData, emit func(record) error) error {
+	for i, p := range g.problems {
+		err := emit(problemRecord{
+			GameID:    int(g.game.GameID),
+			Position:  i + 1,
+			ProblemID: int(p.ProblemID),
+			Title:     p.Title,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type userRecord struct {
+	GameID      int     `json:"game_id"`
+	TeamID      int     `json:"team_id"`
+	UserID      int     `json:"user_id"`
+	Username    string  `json:"username"`
+	DisplayName string  `json:"display_name"`
+	Label       *string `json:"label"`
+	Rating      int     `json:"rating"`
+}
+
+var userHeader = []string{"game_id", "team_id", "user_id", "username", "display_name", "label", "rating"}
+
+func (r userRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.GameID),
+		strconv.Itoa(r.TeamID),
+		strconv.Itoa(r.UserID),
+		r.Username,
+		r.DisplayName,
+		stringField(r.Label),
+		strconv.Itoa(r.Rating),
+	}
+}
+
+func userRows(_ context.Context, _ *Archive, g *gameData, emit func(record) error) error {
+	for _, m := range g.members {
+		err := emit(userRecord{
+			GameID:      int(g.game.GameID),
+			TeamID:      int(m.TeamID),
+			UserID:      int(m.User.UserID),
+			Username:    m.User.Username,
+			DisplayName: m.User.DisplayName,
+			Label:       m.User.Label,
+			Rating:      int(m.User.Rating),
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type rankingRecord struct {
+	GameID   int    `json:"game_id"`
+	Rank     int    `json:"rank"`
+	TeamID   int    `json:"team_id"`
+	TeamName string `json:"team_name"`
+	UserIDs  []int  `json:"user_ids"`
+	Score    int    `json:"score"`
+	// ProblemScores are in the order of the problems of the game, nil for the
+	// unsolved ones.
+	ProblemScores   []*int    `json:"problem_scores"`
+	SubmissionCount int       `json:"submission_count"`
+	SubmittedAt     time.Time `json:"submitted_at"`
+
+	// width is the number of problem score columns in CSV, which are shared by
+	// all the games of the archive.
+	width int
+}
+
+func rankingHeader(a *Archive) []string {
+	header := []string{"game_id", "rank", "team_id", "team_name", "user_ids", "score", "submission_count", "submitted_at"}
+	for i := range a.maxProblems() {
+		header = append(header, "problem_"+strconv.Itoa(i+1)+"_score")
+	}
+	return header
+}
+
+func (r rankingRecord) fields() []string {
+	userIDs := make([]string, len(r.UserIDs))
+	for i, id := range r.UserIDs {
+		userIDs[i] = strconv.Itoa(id)
+	}
+	fields := []string{
+		strconv.Itoa(r.GameID),
+		strconv.Itoa(r.Rank),
+		strconv.Itoa(r.TeamID),
+		r.TeamName,
+		strings.Join(userIDs, " "),
+		strconv.Itoa(r.Score),
+		strconv.Itoa(r.SubmissionCount),
+		timeField(&r.SubmittedAt),
+	}
+	for i := range r.width {
+		var score *int
+		if i < len(r.ProblemScores) {
+			score = r.ProblemScores[i]
+		}
+		fields = append(fields, intField(score))
+	}
+	return fields
+}
+
+func rankingRows(_ context.Context, a *Archive, g *gameData, emit func(record) error) error {
+	width := a.maxProblems()
+	for i, t := range g.teams {
+		userIDs := make([]int, len(t.Members))
+		for j, u := range t.Members {
+			userIDs[j] = int(u.UserID)
+		}
+		problemScores := make([]*int, len(t.ProblemIDs))
+		for j, problemID := range t.ProblemIDs {
+			if best, ok := t.Bests[problemID]; ok {
+				score := int(best.CodeSize)
+				problemScores[j] = &score
+			}
+		}
+		err := emit(rankingRecord{
+			GameID:          int(g.game.GameID),
+			Rank:            g.ranks[i],
+			TeamID:          int(t.Team.TeamID),
+			TeamName:        t.Team.DisplayName,
+			UserIDs:         userIDs,
+			Score:           t.Score,
+			ProblemScores:   problemScores,
+			SubmissionCount: t.SubmissionCount,
+			SubmittedAt:     t.SubmittedAt.UTC(),
+			width:           width,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type submissionRecord struct {
+	GameID       int        `json:"game_id"`
+	SubmissionID int        `json:"submission_id"`
+	TeamID       int        `json:"team_id"`
+	UserID       int        `json:"user_id"`
+	ProblemID    int        `json:"problem_id"`
+	Language     string     `json:"language"`
+	Status       string     `json:"status"`
+	CodeSize     int        `json:"code_size"`
+	IsPractice   bool       `json:"is_practice"`
+	CreatedAt    *time.Time `json:"created_at"`
+	Code         string     `json:"code"`
+}
+
+var submissionHeader = []string{"game_id", "submission_id", "team_id", "user_id", "problem_id", "language", "status", "code_size", "is_practice", "created_at", "code"}
+
+func (r submissionRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.GameID),
+		strconv.Itoa(r.SubmissionID),
+		strconv.Itoa(r.TeamID),
+		strconv.Itoa(r.UserID),
+		strconv.Itoa(r.ProblemID),
+		r.Language,
+		r.Status,
+		strconv.Itoa(r.CodeSize),
+		strconv.FormatBool(r.IsPractice),
+		timeField(r.CreatedAt),
+		r.Code,
+	}
+}
+
+func submissionRows(ctx context.Context, a *Archive, g *gameData, emit func(record) error) error {
+	return eachSubmission(ctx, a.q, g.game.GameID, func(s db.Submission) error {
+		return emit(submissionRecord{
+			GameID:       int(s.GameID),
+			SubmissionID: int(s.SubmissionID),
+			TeamID:       int(s.TeamID),
+			UserID:       int(s.UserID),
+			ProblemID:    int(s.ProblemID),
+			Language:     s.Language,
+			Status:       s.Status,
+			CodeSize:     int(s.CodeSize),
+			IsPractice:   s.IsPractice,
+			CreatedAt:    timePtr(s.CreatedAt),
+			Code:         s.Code,
+		})
+	})
+}
+
+type testcaseResultRecord struct {
+	GameID       int    `json:"game_id"`
+	SubmissionID int    `json:"submission_id"`
+	TestcaseID   int    `json:"testcase_id"`
+	IsSample     bool   `json:"is_sample"`
+	Status       string `json:"status"`
+}
+
+var testcaseResultHeader = []string{"game_id", "submission_id", "testcase_id", "is_sample", "status"}
+
+func (r testcaseResultRecord) fields() []string {
+	return []string{
+		strconv.Itoa(r.GameID),
+		strconv.Itoa(r.SubmissionID),
+		strconv.Itoa(r.TestcaseID),
+		strconv.FormatBool(r.IsSample),
+		r.Status,
+	}
+}
+
+func testcaseResultRows(ctx context.Context, a *Archive, g *gameData, emit func(record) error) error {
+	return eachTestcaseResult(ctx, a.q, g.game.GameID, func(r db.ListTestcaseResultsByGameIDAfterRow) error {
+		return emit(testcaseResultRecord{
+			GameID:       int(g.game.GameID),
+			SubmissionID: int(r.SubmissionID),
+			TestcaseID:   int(r.TestcaseID),
+			IsSample:     r.IsSample,
+			Status:       r.Status,
+		})
+	})
+}
+
+// Empty fields stand for nil values in CSV.
+
+func stringField(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+func intField(n *int) string {
+	if n == nil {
+		return ""
+	}
+	return strconv.Itoa(*n)
+}
+
+func timeField(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
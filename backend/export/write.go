@@ -0,0 +1,164 @@
+package export
+
+import (
+	"archive/zip"
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+)
+
+// writeCSV writes the archive as a zip with a CSV file for each table. The
+// parts of a table for the games are concatenated in one file, told apart by
+// the game_id column.
+func (a *Archive) writeCSV(ctx context.Context, w io.Writer) error {
+	zw := zip.NewWriter(w)
+	if a.tournament != nil {
+		t := a.tournamentRecord()
+		err := writeCSVFile(zw, "tournament.csv", tournamentHeader, func(emit func(record) error) error {
+			return emit(t)
+		})
+		if err != nil {
+			return err
+		}
+		err = writeCSVFile(zw, "entries.csv", entryHeader, func(emit func(record) error) error {
+			for _, e := range t.Entries {
+				if err := emit(e); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		err = writeCSVFile(zw, "matches.csv", matchHeader, func(emit func(record) error) error {
+			for _, m := range t.Matches {
+				if err := emit(m); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	err := writeCSVFile(zw, "games.csv", gameHeader, func(emit func(record) error) error {
+		for _, g := range a.games {
+			if err := emit(newGameRecord(g.game)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, t := range gameTables {
+		err := writeCSVFile(zw, t.name+".csv", t.header(a), func(emit func(record) error) error {
+			for i := range a.games {
+				if err := t.rows(ctx, a, &a.games[i], emit); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeCSVFile(zw *zip.Writer, name string, header []string, rows func(emit func(record) error) error) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	cw := csv.NewWriter(f)
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+	err = rows(func(r record) error {
+		return cw.Write(r.fields())
+	})
+	if err != nil {
+		return err
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeJSON writes the archive as a single JSON object. The tournament, if
+// any, is under "tournament", and each game under "games" holds its row under
+// "game" and its tables under their names.
+func (a *Archive) writeJSON(ctx context.Context, w io.Writer) error {
+	jw := &jsonWriter{w: bufio.NewWriter(w)}
+	jw.raw(`{`)
+	if a.tournament != nil {
+		jw.raw(`"tournament":`)
+		jw.value(a.tournamentRecord())
+		jw.raw(`,`)
+	}
+	jw.raw(`"games":[`)
+	for i := range a.games {
+		g := &a.games[i]
+		if i > 0 {
+			jw.raw(`,`)
+		}
+		jw.raw(`{"game":`)
+		jw.value(newGameRecord(g.game))
+		for _, t := range gameTables {
+			jw.raw(`,`)
+			jw.value(t.name)
+			jw.raw(`:[`)
+			n := 0
+			err := t.rows(ctx, a, g, func(r record) error {
+				if n > 0 {
+					jw.raw(`,`)
+				}
+				n++
+				jw.value(r)
+				return jw.err
+			})
+			if err != nil {
+				return err
+			}
+			jw.raw(`]`)
+		}
+		jw.raw(`}`)
+	}
+	jw.raw("]}\n")
+	if jw.err != nil {
+		return jw.err
+	}
+	return jw.w.Flush()
+}
+
+// jsonWriter writes a JSON document piece by piece. After the first error, it
+// writes nothing and keeps the error.
+type jsonWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (jw *jsonWriter) raw(s string) {
+	if jw.err != nil {
+		return
+	}
+	_, jw.err = jw.w.WriteString(s)
+}
+
+func (jw *jsonWriter) value(v any) {
+	if jw.err != nil {
+		return
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		jw.err = err
+		return
+	}
+	_, jw.err = jw.w.Write(b)
+}
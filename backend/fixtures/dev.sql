@@ -23,16 +23,48 @@ VALUES
     ('TEST problem 6', 'This is TEST problem 6', 'php', 'sample code'),
     ('TEST problem 7', 'This is TEST problem 7', 'php', 'sample code');
 
+-- Problem 4 may also be solved in Swift.
+INSERT INTO problem_languages
+(problem_id, language, sample_code)
+VALUES
+    (4, 'swift', 'sample code');
+
 INSERT INTO games
-(game_type, is_public, display_name, duration_seconds, problem_id)
+(game_type, is_public, display_name, duration_seconds, unsolved_penalty)
+VALUES
+    ('1v1',         true,  'TEST game 1', 180, 0),
+    ('1v1',         false, 'TEST game 2', 180, 0),
+    ('1v1',         false, 'TEST game 3', 180, 0),
+    ('multiplayer', true,  'TEST game 4', 180, 0),
+    ('multiplayer', false, 'TEST game 5', 180, 0),
+    ('multiplayer', false, 'TEST game 6', 180, 0),
+    ('multiplayer', true,  'TEST game 7', 180, 1000);
+
+-- Game 7 is a qualifier with three problems.
+INSERT INTO game_problems
+(game_id, problem_id, position)
+VALUES
+    (1, 1, 0),
+    (2, 2, 0),
+    (3, 3, 0),
+    (4, 4, 0),
+    (5, 5, 0),
+    (6, 6, 0),
+    (7, 5, 0),
+    (7, 6, 1),
+    (7, 7, 2);
+
+-- Game 4 is played by a team of a and b.
+INSERT INTO game_teams
+(game_id, display_name)
+VALUES
+    (4, 'TEST team AB');
+
+INSERT INTO game_team_members
+(team_id, game_id, user_id)
 VALUES
-    ('1v1',         true,  'TEST game 1', 180, 1),
-    ('1v1',         false, 'TEST game 2', 180, 2),
-    ('1v1',         false, 'TEST game 3', 180, 3),
-    ('multiplayer', true,  'TEST game 4', 180, 4),
-    ('multiplayer', false, 'TEST game 5', 180, 5),
-    ('multiplayer', false, 'TEST game 6', 180, 6),
-    ('multiplayer', true,  'TEST game 7', 180, 7);
+    (1, 4, 1),
+    (1, 4, 2);
 
 INSERT INTO testcases
 (problem_id, stdin, stdout)
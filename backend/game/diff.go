@@ -0,0 +1,96 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/codediff"
+	"albatross-2026-backend/db"
+)
+
+// SubmissionDiff is the change from one submission to another.
+type SubmissionDiff struct {
+	Unified   string
+	Lines     []codediff.Line
+	SizeDelta int
+}
+
+// DiffSubmissions compares two submissions of the game. Admins can compare any
+// submissions. Others can compare the submissions of their own team, and, once
+// the game finishes, the submissions GetRanking reveals the code of: any
+// submissions, or during the freeze, those made before the cutoff.
+func (s *Service) DiffSubmissions(ctx context.Context, gameID, fromID, toID int, userID *int32, isAdmin bool) (SubmissionDiff, error) {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SubmissionDiff{}, ErrNotFound
+		}
+		return SubmissionDiff{}, err
+	}
+	from, err := s.getGameSubmission(ctx, gameID, fromID)
+	if err != nil {
+		return SubmissionDiff{}, err
+	}
+	to, err := s.getGameSubmission(ctx, gameID, toID)
+	if err != nil {
+		return SubmissionDiff{}, err
+	}
+
+	if !isAdmin {
+		finished := IsGameFinished(LifecycleFromGame(gameRow))
+		cutoff, frozen := RankingCutoff(gameRow, time.Now())
+		revealed := func(sub db.Submission) bool {
+			return finished && (!frozen || !sub.CreatedAt.Time.After(cutoff))
+		}
+		if !revealed(from) || !revealed(to) {
+			if userID == nil {
+				return SubmissionDiff{}, ErrForbidden
+			}
+			team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
+				GameID: int32(gameID),
+				UserID: *userID,
+			})
+			if err != nil {
+				if errors.Is(err, pgx.ErrNoRows) {
+					return SubmissionDiff{}, ErrForbidden
+				}
+				return SubmissionDiff{}, err
+			}
+			for _, sub := range []db.Submission{from, to} {
+				if !revealed(sub) && sub.TeamID != team.TeamID {
+					return SubmissionDiff{}, ErrForbidden
+				}
+			}
+		}
+	}
+
+	return SubmissionDiff{
+		Unified: codediff.Unified(
+			fmt.Sprintf("submission/%d", from.SubmissionID),
+			fmt.Sprintf("submission/%d", to.SubmissionID),
+			from.Code,
+			to.Code,
+		),
+		Lines:     codediff.Lines(from.Code, to.Code),
+		SizeDelta: int(to.CodeSize - from.CodeSize),
+	}, nil
+}
+
+// getGameSubmission returns the submission if it belongs to the game.
+func (s *Service) getGameSubmission(ctx context.Context, gameID, submissionID int) (db.Submission, error) {
+	row, err := s.q.GetSubmissionByID(ctx, int32(submissionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Submission{}, ErrNotFound
+		}
+		return db.Submission{}, err
+	}
+	if int(row.GameID) != gameID {
+		return db.Submission{}, ErrNotFound
+	}
+	return row, nil
+}
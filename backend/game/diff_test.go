@@ -0,0 +1,83 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+// diffQuerier returns a finished game whose ranking is still frozen, and its
+// submissions by ID.
+type diffQuerier struct {
+	db.Querier
+	submissions map[int32]db.Submission
+}
+
+func (m *diffQuerier) GetGameByID(_ context.Context, _ int32) (db.Game, error) {
+	// The game ended a minute ago, and the freeze of 5 minutes started 6
+	// minutes ago.
+	return db.Game{
+		GameID:          1,
+		GameType:        "multiplayer",
+		StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-11 * time.Minute), Valid: true},
+		DurationSeconds: 600,
+		FreezeSeconds:   300,
+	}, nil
+}
+
+func (m *diffQuerier) GetSubmissionByID(_ context.Context, submissionID int32) (db.Submission, error) {
+	row, ok := m.submissions[submissionID]
+	if !ok {
+		return db.Submission{}, pgx.ErrNoRows
+	}
+	return row, nil
+}
+
+func (m *diffQuerier) GetTeamByUserID(_ context.Context, arg db.GetTeamByUserIDParams) (db.GameTeam, error) {
+	return db.GameTeam{TeamID: arg.UserID, GameID: arg.GameID}, nil
+}
+
+func TestDiffSubmissions_Frozen(t *testing.T) {
+	at := func(d time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Now().Add(d), Valid: true}
+	}
+	q := &diffQuerier{submissions: map[int32]db.Submission{
+		// Before the freeze.
+		1: {SubmissionID: 1, GameID: 1, TeamID: 1, Code: "a", CreatedAt: at(-8 * time.Minute)},
+		2: {SubmissionID: 2, GameID: 1, TeamID: 2, Code: "b", CreatedAt: at(-8 * time.Minute)},
+		// During the freeze.
+		3: {SubmissionID: 3, GameID: 1, TeamID: 2, Code: "c", CreatedAt: at(-2 * time.Minute)},
+		// A practice submission after the game.
+		4: {SubmissionID: 4, GameID: 1, TeamID: 2, Code: "d", CreatedAt: at(-30 * time.Second)},
+	}}
+	s := &Service{q: q}
+	userID := int32(1)
+
+	tests := []struct {
+		name     string
+		from, to int
+		userID   *int32
+		isAdmin  bool
+		wantErr  error
+	}{
+		{name: "before the freeze", from: 1, to: 2, userID: &userID},
+		{name: "during the freeze", from: 2, to: 3, userID: &userID, wantErr: ErrForbidden},
+		{name: "practice", from: 2, to: 4, wantErr: ErrForbidden},
+		{name: "own team", from: 1, to: 3, userID: func() *int32 { id := int32(2); return &id }()},
+		{name: "admin", from: 2, to: 4, isAdmin: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := s.DiffSubmissions(context.Background(), 1, tt.from, tt.to, tt.userID, tt.isAdmin)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("DiffSubmissions() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
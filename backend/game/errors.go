@@ -6,5 +6,20 @@ var (
 	ErrNotFound       = errors.New("not found")
 	ErrGameNotRunning = errors.New("game is not running")
 	ErrForbidden      = errors.New("forbidden")
+	ErrNoProblems     = errors.New("no problems")
 	ErrNoTestcases    = errors.New("no testcases")
+	ErrRunTimedOut    = errors.New("run timed out")
+
+	ErrNotMultiplayer      = errors.New("game is not multiplayer")
+	ErrGameStarted         = errors.New("game has already started")
+	ErrDuplicateTeamMember = errors.New("user is in more than one team")
+
+	ErrInvalidTransition = errors.New("invalid game state transition")
+	ErrGameNotFinished   = errors.New("game is not finished")
+	ErrNotFrozen         = errors.New("ranking is not frozen")
+
+	ErrNoReferenceSolutions = errors.New("no reference solutions")
+	ErrNotValidated         = errors.New("problem is not validated")
+
+	ErrLanguageNotAllowed = errors.New("language is not allowed for the problem")
 )
@@ -0,0 +1,90 @@
+package game
+
+import (
+	"sync"
+)
+
+type EventType string
+
+const (
+	EventTypeCode      EventType = "code"
+	EventTypeStatus    EventType = "status"
+	EventTypeBestScore EventType = "best_score"
+	// EventTypeGame tells that the state of the game itself has changed. It
+	// is not tied to a player, and clients are expected to fetch the game
+	// again.
+	EventTypeGame EventType = "game"
+)
+
+// Event is a change of a team's state in a game, pushed to streaming clients.
+// Only the fields relevant to Type are set. UserID is the member of the team
+// who saved or submitted the code. UserID, TeamID and ProblemID are zero for
+// EventTypeGame.
+type Event struct {
+	Type                 EventType
+	GameID               int
+	UserID               int
+	TeamID               int
+	ProblemID            int
+	Language             string
+	Code                 string
+	Status               string
+	Score                *int
+	BestScoreSubmittedAt *int64
+}
+
+// eventBufferSize is the number of undelivered events kept per subscriber.
+// Events for slow subscribers beyond this are dropped.
+const eventBufferSize = 64
+
+type EventBroker struct {
+	mu          sync.Mutex
+	subscribers map[int]map[chan Event]struct{}
+}
+
+func NewEventBroker() *EventBroker {
+	return &EventBroker{
+		subscribers: make(map[int]map[chan Event]struct{}),
+	}
+}
+
+// Subscribe registers a subscriber for events of the given game. The returned
+// function must be called to release the subscription.
+func (b *EventBroker) Subscribe(gameID int) (<-chan Event, func()) {
+	ch := make(chan Event, eventBufferSize)
+
+	b.mu.Lock()
+	subs, ok := b.subscribers[gameID]
+	if !ok {
+		subs = make(map[chan Event]struct{})
+		b.subscribers[gameID] = subs
+	}
+	subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	unsubscribe := func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+			delete(b.subscribers[gameID], ch)
+			if len(b.subscribers[gameID]) == 0 {
+				delete(b.subscribers, gameID)
+			}
+			close(ch)
+		})
+	}
+	return ch, unsubscribe
+}
+
+// Publish delivers the event to all subscribers of its game without blocking.
+func (b *EventBroker) Publish(event Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers[event.GameID] {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package game
+
+import (
+	"testing"
+)
+
+func TestEventBroker_PublishToSubscribersOfGame(t *testing.T) {
+	b := NewEventBroker()
+	events1, unsubscribe1 := b.Subscribe(1)
+	defer unsubscribe1()
+	events2, unsubscribe2 := b.Subscribe(2)
+	defer unsubscribe2()
+
+	b.Publish(Event{Type: EventTypeCode, GameID: 1, UserID: 10, Code: "x"})
+
+	select {
+	case ev := <-events1:
+		if ev.UserID != 10 || ev.Code != "x" {
+			t.Errorf("unexpected event: %+v", ev)
+		}
+	default:
+		t.Fatal("expected an event for game 1")
+	}
+	select {
+	case ev := <-events2:
+		t.Errorf("expected no event for game 2, got %+v", ev)
+	default:
+	}
+}
+
+func TestEventBroker_Unsubscribe(t *testing.T) {
+	b := NewEventBroker()
+	events, unsubscribe := b.Subscribe(1)
+	unsubscribe()
+	// Calling it twice must be safe.
+	unsubscribe()
+
+	b.Publish(Event{Type: EventTypeCode, GameID: 1})
+
+	if _, ok := <-events; ok {
+		t.Error("expected channel to be closed")
+	}
+	if len(b.subscribers) != 0 {
+		t.Errorf("expected no subscribers, got %d", len(b.subscribers))
+	}
+}
+
+func TestEventBroker_DoesNotBlockOnSlowSubscriber(t *testing.T) {
+	b := NewEventBroker()
+	events, unsubscribe := b.Subscribe(1)
+	defer unsubscribe()
+
+	for range eventBufferSize + 10 {
+		b.Publish(Event{Type: EventTypeStatus, GameID: 1, Status: "running"})
+	}
+
+	if len(events) != eventBufferSize {
+		t.Errorf("expected %d buffered events, got %d", eventBufferSize, len(events))
+	}
+}
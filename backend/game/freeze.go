@@ -0,0 +1,48 @@
+package game
+
+import (
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+// RankingCutoff returns the time as of which non-admins see the ranking of the
+// game. It returns false if they see the live ranking.
+//
+// A game with a positive freeze_seconds freezes its ranking that many seconds
+// before the end. After the game, admins reveal the changes made during the
+// freeze one by one, moving revealed_until forward. The ranking is live again
+// once revealed_until reaches the end of the game.
+func RankingCutoff(row db.Game, now time.Time) (time.Time, bool) {
+	return rankingCutoff(LifecycleFromGame(row), row.FreezeSeconds, row.RevealedUntil, now)
+}
+
+func rankingCutoff(l Lifecycle, freezeSeconds int32, revealedUntil pgtype.Timestamp, now time.Time) (time.Time, bool) {
+	if freezeSeconds <= 0 || !l.StartedAt.Valid {
+		return time.Time{}, false
+	}
+	switch l.StateAt(now) {
+	case StateWaiting, StateScheduled, StateCancelled:
+		return time.Time{}, false
+	}
+	endsAt := l.endsAt()
+	freezeAt := endsAt.Add(-time.Duration(freezeSeconds) * time.Second)
+	// The clock stops while the game is paused.
+	if l.PausedAt.Valid {
+		now = l.PausedAt.Time
+	}
+	if now.Before(freezeAt) {
+		return time.Time{}, false
+	}
+	if revealedUntil.Valid {
+		if !revealedUntil.Time.Before(endsAt) {
+			return time.Time{}, false
+		}
+		if revealedUntil.Time.After(freezeAt) {
+			return revealedUntil.Time, true
+		}
+	}
+	return freezeAt, true
+}
@@ -0,0 +1,167 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+func TestRankingCutoff(t *testing.T) {
+	now := time.Now()
+	ts := func(d time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: now.Add(d), Valid: true}
+	}
+	// The game ends in a minute, so the freeze of 5 minutes started 4 minutes
+	// ago.
+	running := Lifecycle{StartedAt: ts(-9 * time.Minute), DurationSeconds: 600}
+	freezeAt := now.Add(-4 * time.Minute)
+
+	tests := []struct {
+		name          string
+		lifecycle     Lifecycle
+		freezeSeconds int32
+		revealedUntil pgtype.Timestamp
+		wantFrozen    bool
+		wantCutoff    time.Time
+	}{
+		{
+			name:          "no freeze",
+			lifecycle:     running,
+			freezeSeconds: 0,
+			wantFrozen:    false,
+		},
+		{
+			name:          "before freeze",
+			lifecycle:     running,
+			freezeSeconds: 30,
+			wantFrozen:    false,
+		},
+		{
+			name:          "frozen",
+			lifecycle:     running,
+			freezeSeconds: 300,
+			wantFrozen:    true,
+			wantCutoff:    freezeAt,
+		},
+		{
+			name: "paused before freeze",
+			lifecycle: Lifecycle{
+				StartedAt:       running.StartedAt,
+				DurationSeconds: 600,
+				PausedAt:        ts(-5 * time.Minute),
+				OverrideState:   stateOverride(StatePaused),
+			},
+			freezeSeconds: 300,
+			wantFrozen:    false,
+		},
+		{
+			name:          "partially revealed",
+			lifecycle:     Lifecycle{StartedAt: ts(-20 * time.Minute), DurationSeconds: 600},
+			freezeSeconds: 300,
+			revealedUntil: ts(-12 * time.Minute),
+			wantFrozen:    true,
+			wantCutoff:    now.Add(-12 * time.Minute),
+		},
+		{
+			name:          "fully revealed",
+			lifecycle:     Lifecycle{StartedAt: ts(-20 * time.Minute), DurationSeconds: 600},
+			freezeSeconds: 300,
+			revealedUntil: ts(-10 * time.Minute),
+			wantFrozen:    false,
+		},
+		{
+			name: "cancelled",
+			lifecycle: Lifecycle{
+				StartedAt:       running.StartedAt,
+				DurationSeconds: 600,
+				OverrideState:   stateOverride(StateCancelled),
+			},
+			freezeSeconds: 300,
+			wantFrozen:    false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cutoff, frozen := rankingCutoff(tt.lifecycle, tt.freezeSeconds, tt.revealedUntil, now)
+			if frozen != tt.wantFrozen {
+				t.Fatalf("frozen = %v, want %v", frozen, tt.wantFrozen)
+			}
+			if frozen && !cutoff.Equal(tt.wantCutoff) {
+				t.Errorf("cutoff = %v, want %v", cutoff, tt.wantCutoff)
+			}
+		})
+	}
+}
+
+// frozenStatesQuerier returns the latest states of two main players of a game
+// in its freeze, of which only the first has a submission before it.
+type frozenStatesQuerier struct {
+	db.Querier
+}
+
+func (m *frozenStatesQuerier) GetGameByID(_ context.Context, _ int32) (db.Game, error) {
+	// The game ends in a minute, within the freeze of 5 minutes.
+	return db.Game{
+		GameID:          1,
+		GameType:        "1v1",
+		StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-9 * time.Minute), Valid: true},
+		DurationSeconds: 600,
+		FreezeSeconds:   300,
+	}, nil
+}
+
+func (m *frozenStatesQuerier) GetLatestStatesOfMainPlayers(_ context.Context, _ db.GetLatestStatesOfMainPlayersParams) ([]db.GetLatestStatesOfMainPlayersRow, error) {
+	language, code, status := "php", "live", "success"
+	size := int32(4)
+	submittedAt := pgtype.Timestamp{Time: time.Now(), Valid: true}
+	return []db.GetLatestStatesOfMainPlayersRow{
+		{UserID: 1, Language: &language, Code: &code, Status: &status, CodeSize: &size, CreatedAt: submittedAt},
+		{UserID: 2, Language: &language, Code: &code, Status: &status, CodeSize: &size, CreatedAt: submittedAt},
+	}, nil
+}
+
+func (m *frozenStatesQuerier) ListBestSubmissionsAt(_ context.Context, _ db.ListBestSubmissionsAtParams) ([]db.ListBestSubmissionsAtRow, error) {
+	return []db.ListBestSubmissionsAtRow{{
+		Submission: db.Submission{
+			ProblemID: 1,
+			Language:  "php",
+			Code:      "frozen",
+			CodeSize:  6,
+			CreatedAt: pgtype.Timestamp{Time: time.Now().Add(-5 * time.Minute), Valid: true},
+		},
+		GameTeam: db.GameTeam{TeamID: 1, GameID: 1},
+	}}, nil
+}
+
+func (m *frozenStatesQuerier) ListTeamMembers(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+	return []db.ListTeamMembersRow{
+		{TeamID: 1, User: db.User{UserID: 1}},
+		{TeamID: 2, User: db.User{UserID: 2}},
+	}, nil
+}
+
+func TestGetWatchLatestStates_Frozen(t *testing.T) {
+	s := &Service{q: &frozenStatesQuerier{}}
+	states, err := s.GetWatchLatestStates(context.Background(), 1, 1, nil, false)
+	if err != nil {
+		t.Fatalf("GetWatchLatestStates: %v", err)
+	}
+	if got := states[1]; got.Code != "frozen" || got.Score == nil || *got.Score != 6 || got.Status != "none" {
+		t.Errorf("expected the best code at the freeze, got %+v", got)
+	}
+	if got := states[2]; got.Code != "" || got.Language != "" || got.Score != nil {
+		t.Errorf("expected no code without a submission before the freeze, got %+v", got)
+	}
+
+	states, err = s.GetWatchLatestStates(context.Background(), 1, 1, nil, true)
+	if err != nil {
+		t.Fatalf("GetWatchLatestStates: %v", err)
+	}
+	if got := states[2]; got.Code != "live" {
+		t.Errorf("expected the live code for admins, got %+v", got)
+	}
+}
@@ -2,17 +2,23 @@ package game
 
 import (
 	"context"
+	"crypto/rand"
+	"errors"
 	"log/slog"
 	"os"
-	"regexp"
-	"strings"
+	"sync"
+	"time"
 
+	"albatross-2026-backend/checker"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/taskqueue"
 )
 
 type TaskQueueInterface interface {
-	EnqueueTaskRunTestcase(gameID, userID, submissionID, testcaseID int, language, code, stdin, stdout string) error
+	EnqueueTaskRunSubmission(gameID, userID, submissionID int, language, code string, testcases []taskqueue.SubmissionTestcase) error
+	EnqueueTaskRunChecker(gameID, userID, submissionID, testcaseID int, language, checkerCode, stdin, submissionStdout, submissionStderr string, submissionTimeMs *int, submissionMemoryBytes *int64, timeLimitMs, memoryLimitMiB int) error
+	EnqueueTaskRunCustom(runID string, gameID, userID int, language, code, stdin string, timeLimitMs, memoryLimitMiB int) error
+	EnqueueTaskRunValidation(validationID, problemID, referenceSolutionID, testcaseID int, language, code, stdin, stdout, checkerCode string, timeLimitMs, memoryLimitMiB int) error
 }
 
 type TaskWorkerInterface interface {
@@ -20,14 +26,16 @@ type TaskWorkerInterface interface {
 	Results() chan taskqueue.TaskResult
 }
 
-var whitespaceRe = regexp.MustCompile(`\s+`)
-
 type Hub struct {
 	q          db.Querier
 	txm        db.TxManager
 	ctx        context.Context
 	taskQueue  TaskQueueInterface
 	taskWorker TaskWorkerInterface
+	events     *EventBroker
+
+	pendingRunsMu sync.Mutex
+	pendingRuns   map[string]chan *taskqueue.TaskResultRunCustom
 }
 
 func NewGameHub(q db.Querier, txm db.TxManager, taskQueue TaskQueueInterface, taskWorker TaskWorkerInterface) *Hub {
@@ -37,6 +45,9 @@ func NewGameHub(q db.Querier, txm db.TxManager, taskQueue TaskQueueInterface, ta
 		ctx:        context.Background(),
 		taskQueue:  taskQueue,
 		taskWorker: taskWorker,
+		events:     NewEventBroker(),
+
+		pendingRuns: make(map[string]chan *taskqueue.TaskResultRunCustom),
 	}
 }
 
@@ -51,32 +62,75 @@ func (hub *Hub) Run() {
 	go hub.processTaskResults()
 }
 
-func (hub *Hub) CalcCodeSize(code string, language string) int {
-	trimmed := whitespaceRe.ReplaceAllString(code, "")
-	if language == "php" {
-		return len(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(trimmed, "<?php"), "<?"), "?>"))
-	}
-	return len(trimmed)
+func (hub *Hub) PublishEvent(event Event) {
+	hub.events.Publish(event)
 }
 
-func (hub *Hub) EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID int, language, code string) error {
-	rows, err := hub.q.ListTestcasesByGameID(ctx, int32(gameID))
+func (hub *Hub) SubscribeEvents(gameID int) (<-chan Event, func()) {
+	return hub.events.Subscribe(gameID)
+}
+
+// EnqueueTestTasks runs the submission on all the testcases of the problem in
+// a single task, so that the code is compiled only once.
+func (hub *Hub) EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID, problemID int, language, code string) error {
+	rows, err := hub.q.ListTestcasesByProblemID(ctx, int32(problemID))
 	if err != nil {
 		return err
 	}
-	for _, row := range rows {
-		err := hub.taskQueue.EnqueueTaskRunTestcase(
-			gameID,
-			userID,
-			submissionID,
-			int(row.TestcaseID),
-			language,
-			code,
-			row.Stdin,
-			row.Stdout,
-		)
-		if err != nil {
-			return err
+	if len(rows) == 0 {
+		return nil
+	}
+	problem, err := hub.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		return err
+	}
+	testcases := make([]taskqueue.SubmissionTestcase, len(rows))
+	for i, row := range rows {
+		timeLimitMs, memoryLimitMiB := testcaseLimits(problem, row)
+		testcases[i] = taskqueue.SubmissionTestcase{
+			TestcaseID:     int(row.TestcaseID),
+			Stdin:          row.Stdin,
+			Stdout:         row.Stdout,
+			TimeLimitMs:    timeLimitMs,
+			MemoryLimitMiB: memoryLimitMiB,
+		}
+	}
+	return hub.taskQueue.EnqueueTaskRunSubmission(
+		gameID,
+		userID,
+		submissionID,
+		language,
+		code,
+		testcases,
+	)
+}
+
+// EnqueueValidationTasks runs each of the reference solutions on each of the
+// testcases of the problem.
+func (hub *Hub) EnqueueValidationTasks(_ context.Context, validationID int, problem db.Problem, solutions []db.ReferenceSolution, testcases []db.Testcase) error {
+	var checkerCode string
+	if problem.Checker == checker.Special {
+		checkerCode = problem.CheckerCode
+	}
+	for _, sol := range solutions {
+		for _, tc := range testcases {
+			timeLimitMs, memoryLimitMiB := testcaseLimits(problem, tc)
+			err := hub.taskQueue.EnqueueTaskRunValidation(
+				validationID,
+				int(problem.ProblemID),
+				int(sol.ReferenceSolutionID),
+				int(tc.TestcaseID),
+				problem.Language,
+				sol.Code,
+				tc.Stdin,
+				tc.Stdout,
+				checkerCode,
+				timeLimitMs,
+				memoryLimitMiB,
+			)
+			if err != nil {
+				return err
+			}
 		}
 	}
 	return nil
@@ -85,24 +139,39 @@ func (hub *Hub) EnqueueTestTasks(ctx context.Context, submissionID, gameID, user
 func (hub *Hub) processTaskResults() {
 	for taskResult := range hub.taskWorker.Results() {
 		switch taskResult := taskResult.(type) {
-		case *taskqueue.TaskResultRunTestcase:
-			if err := hub.processTaskResultRunTestcase(taskResult); err != nil {
-				slog.Error("failed to process testcase result", "error", err, "submissionID", taskResult.TaskPayload.SubmissionID)
+		case *taskqueue.TaskResultRunSubmission:
+			// Tasks whose payload cannot be decoded have no submission to
+			// update.
+			payload := taskResult.TaskPayload
+			if payload == nil {
+				slog.Error("failed to process submission result", "error", taskResult.Err)
 				continue
 			}
-			aggregatedStatus, err := hub.q.AggregateTestcaseResults(hub.ctx, int32(taskResult.TaskPayload.SubmissionID))
-			if err != nil {
-				slog.Error("failed to aggregate testcase results", "error", err, "submissionID", taskResult.TaskPayload.SubmissionID)
-				continue
+			if err := hub.processTaskResultRunSubmission(taskResult); err != nil {
+				slog.Error("failed to process submission result", "error", err, "submissionID", payload.SubmissionID)
 			}
-			if aggregatedStatus == "running" {
+			// Results that could not be judged are recorded as internal
+			// errors, so the submission may be judged even after an error.
+			hub.updateSubmissionIfJudged(payload.SubmissionID, payload.GameID, payload.UserID)
+		case *taskqueue.TaskResultRunChecker:
+			payload := taskResult.TaskPayload
+			if payload == nil {
+				slog.Error("failed to process checker result", "error", taskResult.Err)
 				continue
 			}
-
-			if err := hub.updateSubmissionAndGameState(taskResult, aggregatedStatus); err != nil {
-				slog.Error("failed to update submission and game state", "error", err, "submissionID", taskResult.TaskPayload.SubmissionID)
+			if err := hub.processTaskResultRunChecker(taskResult); err != nil {
+				slog.Error("failed to process checker result", "error", err, "submissionID", payload.SubmissionID)
 				continue
 			}
+			hub.updateSubmissionIfJudged(payload.SubmissionID, payload.GameID, payload.UserID)
+		case *taskqueue.TaskResultRunValidation:
+			if err := hub.processTaskResultRunValidation(taskResult); err != nil {
+				slog.Error("failed to process validation result", "error", err)
+			}
+		case *taskqueue.TaskResultRunCustom:
+			if err := hub.processTaskResultRunCustom(taskResult); err != nil {
+				slog.Error("failed to process custom run result", "error", err)
+			}
 		default:
 			slog.Error("unexpected task result type", "type", taskResult.Type())
 			continue
@@ -110,78 +179,382 @@ func (hub *Hub) processTaskResults() {
 	}
 }
 
-func (hub *Hub) updateSubmissionAndGameState(taskResult *taskqueue.TaskResultRunTestcase, aggregatedStatus string) error {
-	return hub.txm.RunInTx(hub.ctx, func(qtx db.Querier) error {
+// customRunTimeout is how long RunCode waits for the result. It is longer than
+// the execution time limit of the workers to allow for the time in the queue.
+const customRunTimeout = 60 * time.Second
+
+type RunResult struct {
+	Status string
+	Stdout string
+	Stderr string
+}
+
+// RunCode runs code with the given stdin on the worker within the limits and
+// waits for the result. Zero limits mean the defaults of the worker. Nothing
+// is recorded in the database.
+func (hub *Hub) RunCode(ctx context.Context, gameID, userID int, language, code, stdin string, timeLimitMs, memoryLimitMiB int) (RunResult, error) {
+	runID := rand.Text()
+	ch := make(chan *taskqueue.TaskResultRunCustom, 1)
+	hub.pendingRunsMu.Lock()
+	hub.pendingRuns[runID] = ch
+	hub.pendingRunsMu.Unlock()
+	defer func() {
+		hub.pendingRunsMu.Lock()
+		delete(hub.pendingRuns, runID)
+		hub.pendingRunsMu.Unlock()
+	}()
+
+	if err := hub.taskQueue.EnqueueTaskRunCustom(runID, gameID, userID, language, code, stdin, timeLimitMs, memoryLimitMiB); err != nil {
+		return RunResult{}, err
+	}
+
+	timer := time.NewTimer(customRunTimeout)
+	defer timer.Stop()
+	select {
+	case result := <-ch:
+		if result.Err != nil {
+			return RunResult{}, result.Err
+		}
+		return RunResult{
+			Status: result.Status,
+			Stdout: result.Stdout,
+			Stderr: result.Stderr,
+		}, nil
+	case <-timer.C:
+		return RunResult{}, ErrRunTimedOut
+	case <-ctx.Done():
+		return RunResult{}, ctx.Err()
+	}
+}
+
+// processTaskResultRunCustom hands the result to RunCode waiting for it. The
+// result is dropped if RunCode has already given up.
+func (hub *Hub) processTaskResultRunCustom(
+	taskResult *taskqueue.TaskResultRunCustom,
+) error {
+	if taskResult.TaskPayload == nil {
+		return taskResult.Err
+	}
+
+	hub.pendingRunsMu.Lock()
+	ch, ok := hub.pendingRuns[taskResult.TaskPayload.RunID]
+	hub.pendingRunsMu.Unlock()
+	if !ok {
+		return nil
+	}
+	select {
+	case ch <- taskResult:
+	default:
+	}
+	return nil
+}
+
+// updateSubmissionIfJudged updates the submission status once all the
+// testcases have their results.
+func (hub *Hub) updateSubmissionIfJudged(submissionID, gameID, userID int) {
+	aggregatedStatus, err := hub.q.AggregateTestcaseResults(hub.ctx, int32(submissionID))
+	if err != nil {
+		slog.Error("failed to aggregate testcase results", "error", err, "submissionID", submissionID)
+		return
+	}
+	if aggregatedStatus == "running" {
+		return
+	}
+	submission, err := hub.q.GetSubmissionByID(hub.ctx, int32(submissionID))
+	if err != nil {
+		slog.Error("failed to get submission", "error", err, "submissionID", submissionID)
+		return
+	}
+
+	// Practice submissions are judged as usual but leave the game state, and
+	// so the official ranking, as it is.
+	if submission.IsPractice {
+		if err := hub.q.UpdateSubmissionStatus(hub.ctx, db.UpdateSubmissionStatusParams{
+			SubmissionID: int32(submissionID),
+			Status:       aggregatedStatus,
+		}); err != nil {
+			slog.Error("failed to update submission", "error", err, "submissionID", submissionID)
+		}
+	} else if err := hub.updateSubmissionAndGameState(submissionID, gameID, userID, int(submission.TeamID), int(submission.ProblemID), aggregatedStatus); err != nil {
+		slog.Error("failed to update submission and game state", "error", err, "submissionID", submissionID)
+	}
+	finishRejudges(hub.ctx, hub.txm, hub.q, int32(submissionID))
+}
+
+func (hub *Hub) updateSubmissionAndGameState(submissionID, gameID, userID, teamID, problemID int, aggregatedStatus string) error {
+	err := hub.txm.RunInTx(hub.ctx, func(qtx db.Querier) error {
 		if err := qtx.UpdateSubmissionStatus(hub.ctx, db.UpdateSubmissionStatusParams{
-			SubmissionID: int32(taskResult.TaskPayload.SubmissionID),
+			SubmissionID: int32(submissionID),
 			Status:       aggregatedStatus,
 		}); err != nil {
 			return err
 		}
 		if err := qtx.UpdateGameStateStatus(hub.ctx, db.UpdateGameStateStatusParams{
-			GameID: int32(taskResult.TaskPayload.GameID),
-			UserID: int32(taskResult.TaskPayload.UserID),
-			Status: aggregatedStatus,
+			GameID:    int32(gameID),
+			TeamID:    int32(teamID),
+			ProblemID: int32(problemID),
+			Status:    aggregatedStatus,
 		}); err != nil {
 			return err
 		}
-		if aggregatedStatus == "success" {
-			if err := qtx.SyncGameStateBestScoreSubmission(hub.ctx, db.SyncGameStateBestScoreSubmissionParams{
-				GameID: int32(taskResult.TaskPayload.GameID),
-				UserID: int32(taskResult.TaskPayload.UserID),
-			}); err != nil {
-				return err
-			}
-		}
-		return nil
+		// A rejudged submission may also lose its place as the best one.
+		return qtx.SyncGameStateBestScoreSubmission(hub.ctx, db.SyncGameStateBestScoreSubmissionParams{
+			GameID:    int32(gameID),
+			TeamID:    int32(teamID),
+			ProblemID: int32(problemID),
+		})
+	})
+	if err != nil {
+		return err
+	}
+
+	hub.PublishEvent(Event{
+		Type:      EventTypeStatus,
+		GameID:    gameID,
+		UserID:    userID,
+		TeamID:    teamID,
+		ProblemID: problemID,
+		Status:    aggregatedStatus,
 	})
+	if aggregatedStatus == "success" {
+		hub.publishBestScore(gameID, userID, teamID, problemID)
+	}
+	return nil
 }
 
-func (hub *Hub) processTaskResultRunTestcase(
-	taskResult *taskqueue.TaskResultRunTestcase,
+func (hub *Hub) publishBestScore(gameID, userID, teamID, problemID int) {
+	row, err := hub.q.GetLatestState(hub.ctx, db.GetLatestStateParams{
+		GameID:    int32(gameID),
+		TeamID:    int32(teamID),
+		ProblemID: int32(problemID),
+	})
+	if err != nil {
+		slog.Error("failed to get latest state", "error", err, "gameID", gameID, "teamID", teamID, "problemID", problemID)
+		return
+	}
+	if row.CodeSize == nil || !row.CreatedAt.Valid {
+		return
+	}
+	score := int(*row.CodeSize)
+	submittedAt := row.CreatedAt.Time.Unix()
+	hub.PublishEvent(Event{
+		Type:                 EventTypeBestScore,
+		GameID:               gameID,
+		UserID:               userID,
+		TeamID:               teamID,
+		ProblemID:            problemID,
+		Score:                &score,
+		BestScoreSubmittedAt: &submittedAt,
+	})
+}
+
+// processTaskResultRunSubmission judges the results of a submission on all
+// the testcases at once. If the task has failed for good, or a result cannot
+// be judged, the testcases left are recorded as internal errors so that the
+// submission does not stay running and can be rejudged.
+func (hub *Hub) processTaskResultRunSubmission(
+	taskResult *taskqueue.TaskResultRunSubmission,
 ) error {
-	if taskResult.Err != nil {
+	payload := taskResult.TaskPayload
+	if payload == nil {
 		return taskResult.Err
 	}
+	testcaseIDs := make([]int, len(payload.Testcases))
+	for i, tc := range payload.Testcases {
+		testcaseIDs[i] = tc.TestcaseID
+	}
+	if taskResult.Err != nil {
+		return errors.Join(taskResult.Err, hub.recordInternalErrors(payload.SubmissionID, testcaseIDs))
+	}
+
+	problem, err := hub.q.GetProblemBySubmissionID(hub.ctx, int32(payload.SubmissionID))
+	if err != nil {
+		return errors.Join(err, hub.recordInternalErrors(payload.SubmissionID, testcaseIDs))
+	}
+	for i, result := range taskResult.Results {
+		if err := hub.judgeTestcaseRun(problem, payload.GameID, payload.UserID, payload.SubmissionID, payload.Testcases[i], result); err != nil {
+			return errors.Join(err, hub.recordInternalErrors(payload.SubmissionID, testcaseIDs[i:]))
+		}
+	}
+	return nil
+}
 
-	if taskResult.Status != "success" {
+// recordInternalErrors records internal errors as the verdicts of the
+// submission on the testcases. The cause is only logged, since the output of
+// sample testcases is shown to players.
+func (hub *Hub) recordInternalErrors(submissionID int, testcaseIDs []int) error {
+	var errs []error
+	for _, testcaseID := range testcaseIDs {
 		if err := hub.q.CreateTestcaseResult(hub.ctx, db.CreateTestcaseResultParams{
-			SubmissionID: int32(taskResult.TaskPayload.SubmissionID),
-			TestcaseID:   int32(taskResult.TaskPayload.TestcaseID),
-			Status:       taskResult.Status,
-			Stdout:       taskResult.Stdout,
-			Stderr:       taskResult.Stderr,
+			SubmissionID: int32(submissionID),
+			TestcaseID:   int32(testcaseID),
+			Status:       "internal_error",
 		}); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// judgeTestcaseRun records the verdict of a submission on a testcase. For
+// problems with a special judge, the verdict of a successful run is recorded
+// when the checker program finishes.
+func (hub *Hub) judgeTestcaseRun(
+	problem db.Problem,
+	gameID, userID, submissionID int,
+	testcase taskqueue.SubmissionTestcase,
+	result taskqueue.TestcaseRunResult,
+) error {
+	status := result.Status
+	if status == "success" {
+		if problem.Checker == checker.Special {
+			// The checker program is written in the language of the problem,
+			// not of the submission.
+			stdin, err := checker.SpecialJudgeStdin(testcase.Stdin, testcase.Stdout, result.Stdout)
+			if err != nil {
+				return err
+			}
+			return hub.taskQueue.EnqueueTaskRunChecker(
+				gameID,
+				userID,
+				submissionID,
+				testcase.TestcaseID,
+				problem.Language,
+				problem.CheckerCode,
+				stdin,
+				result.Stdout,
+				result.Stderr,
+				result.TimeMs,
+				result.MemoryBytes,
+				testcase.TimeLimitMs,
+				testcase.MemoryLimitMiB,
+			)
+		}
+
+		c, err := checker.New(problem.Checker, problem.CheckerEpsilon)
+		if err != nil {
 			return err
 		}
-		return nil
+		if !c.Check(testcase.Stdout, result.Stdout) {
+			status = "wrong_answer"
+		}
+	}
+	return hub.q.CreateTestcaseResult(hub.ctx, db.CreateTestcaseResultParams{
+		SubmissionID: int32(submissionID),
+		TestcaseID:   int32(testcase.TestcaseID),
+		Status:       status,
+		Stdout:       result.Stdout,
+		Stderr:       result.Stderr,
+		TimeMs:       int32Ptr(result.TimeMs),
+		MemoryBytes:  result.MemoryBytes,
+	})
+}
+
+func (hub *Hub) processTaskResultRunChecker(
+	taskResult *taskqueue.TaskResultRunChecker,
+) error {
+	if taskResult.TaskPayload == nil {
+		return taskResult.Err
 	}
 
 	var status string
-	if isTestcaseResultCorrect(taskResult.TaskPayload.Stdout, taskResult.Stdout) {
+	switch {
+	case taskResult.Err != nil:
+		// The checker task has failed for good. Record a verdict anyway so
+		// that the submission does not stay running.
+		slog.Error("checker task failed", "error", taskResult.Err, "submissionID", taskResult.TaskPayload.SubmissionID)
+		status = "internal_error"
+	case taskResult.Status != "success":
+		// The checker program itself failed, which is not the submitter's fault.
+		slog.Error("checker program failed", "status", taskResult.Status, "stderr", taskResult.Stderr, "submissionID", taskResult.TaskPayload.SubmissionID)
+		status = "internal_error"
+	case checker.IsSpecialJudgeAccepted(taskResult.Stdout):
 		status = "success"
-	} else {
+	default:
 		status = "wrong_answer"
 	}
 	if err := hub.q.CreateTestcaseResult(hub.ctx, db.CreateTestcaseResultParams{
 		SubmissionID: int32(taskResult.TaskPayload.SubmissionID),
 		TestcaseID:   int32(taskResult.TaskPayload.TestcaseID),
 		Status:       status,
-		Stdout:       taskResult.Stdout,
-		Stderr:       taskResult.Stderr,
+		Stdout:       taskResult.TaskPayload.SubmissionStdout,
+		Stderr:       taskResult.TaskPayload.SubmissionStderr,
+		TimeMs:       int32Ptr(taskResult.TaskPayload.SubmissionTimeMs),
+		MemoryBytes:  taskResult.TaskPayload.SubmissionMemoryBytes,
 	}); err != nil {
 		return err
 	}
 	return nil
 }
 
-func normalizeTestcaseResultOutput(s string) string {
-	re := regexp.MustCompile(`\r\n|\r`)
-	return re.ReplaceAllString(strings.TrimSpace(s), "\n")
+// processTaskResultRunValidation records the verdict of a reference solution
+// and finishes the validation once all the verdicts are in.
+func (hub *Hub) processTaskResultRunValidation(
+	taskResult *taskqueue.TaskResultRunValidation,
+) error {
+	payload := taskResult.TaskPayload
+	if payload == nil {
+		return taskResult.Err
+	}
+
+	status, err := hub.validationVerdict(taskResult)
+	if err != nil {
+		return err
+	}
+	stderr := taskResult.Stderr
+	if taskResult.Err != nil {
+		stderr = taskResult.Err.Error()
+	}
+	if err := hub.q.CreateProblemValidationResult(hub.ctx, db.CreateProblemValidationResultParams{
+		ProblemValidationID: int32(payload.ValidationID),
+		ReferenceSolutionID: int32(payload.ReferenceSolutionID),
+		TestcaseID:          int32(payload.TestcaseID),
+		Status:              status,
+		Stdout:              taskResult.Stdout,
+		Stderr:              stderr,
+	}); err != nil {
+		return err
+	}
+	finished, err := hub.q.FinishProblemValidationIfJudged(hub.ctx, int32(payload.ValidationID))
+	if err != nil {
+		return err
+	}
+	if finished > 0 {
+		slog.Info("problem validation finished", "validationID", payload.ValidationID, "problemID", payload.ProblemID)
+	}
+	return nil
 }
 
-func isTestcaseResultCorrect(expectedStdout, actualStdout string) bool {
-	expectedStdout = normalizeTestcaseResultOutput(expectedStdout)
-	actualStdout = normalizeTestcaseResultOutput(actualStdout)
-	return actualStdout == expectedStdout
+// validationVerdict judges the output of a reference solution as that of a
+// submission. As for submissions, a task that failed for good is recorded as
+// an internal error so that the validation can finish.
+func (hub *Hub) validationVerdict(taskResult *taskqueue.TaskResultRunValidation) (string, error) {
+	payload := taskResult.TaskPayload
+	switch {
+	case taskResult.Err != nil:
+		return "internal_error", nil
+	case taskResult.Status != "success":
+		return taskResult.Status, nil
+	case payload.CheckerCode != "":
+		if taskResult.CheckerStatus != "success" {
+			return "internal_error", nil
+		}
+		if checker.IsSpecialJudgeAccepted(taskResult.CheckerStdout) {
+			return "success", nil
+		}
+		return "wrong_answer", nil
+	}
+
+	problem, err := hub.q.GetProblemByID(hub.ctx, int32(payload.ProblemID))
+	if err != nil {
+		return "", err
+	}
+	c, err := checker.New(problem.Checker, problem.CheckerEpsilon)
+	if err != nil {
+		return "", err
+	}
+	if c.Check(payload.Stdout, taskResult.Stdout) {
+		return "success", nil
+	}
+	return "wrong_answer", nil
 }
@@ -3,31 +3,99 @@ package game
 import (
 	"context"
 	"errors"
+	"slices"
 	"testing"
+	"time"
 
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/checker"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/taskqueue"
 )
 
 // mockTaskQueue implements TaskQueueInterface for testing.
 type mockTaskQueue struct {
-	enqueued []taskqueue.TaskPayloadRunTestcase
-	err      error
+	enqueued            []taskqueue.TaskPayloadRunSubmission
+	enqueuedCheckers    []taskqueue.TaskPayloadRunChecker
+	enqueuedValidations []taskqueue.TaskPayloadRunValidation
+	enqueueCustomFunc   func(payload taskqueue.TaskPayloadRunCustom)
+	err                 error
 }
 
-func (m *mockTaskQueue) EnqueueTaskRunTestcase(gameID, userID, submissionID, testcaseID int, language, code, stdin, stdout string) error {
+func (m *mockTaskQueue) EnqueueTaskRunSubmission(gameID, userID, submissionID int, language, code string, testcases []taskqueue.SubmissionTestcase) error {
 	if m.err != nil {
 		return m.err
 	}
-	m.enqueued = append(m.enqueued, taskqueue.TaskPayloadRunTestcase{
+	m.enqueued = append(m.enqueued, taskqueue.TaskPayloadRunSubmission{
 		GameID:       gameID,
 		UserID:       userID,
 		SubmissionID: submissionID,
-		TestcaseID:   testcaseID,
 		Language:     language,
 		Code:         code,
-		Stdin:        stdin,
-		Stdout:       stdout,
+		Testcases:    testcases,
+	})
+	return nil
+}
+
+func (m *mockTaskQueue) EnqueueTaskRunChecker(gameID, userID, submissionID, testcaseID int, language, checkerCode, stdin, submissionStdout, submissionStderr string, submissionTimeMs *int, submissionMemoryBytes *int64, timeLimitMs, memoryLimitMiB int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueuedCheckers = append(m.enqueuedCheckers, taskqueue.TaskPayloadRunChecker{
+		GameID:                gameID,
+		UserID:                userID,
+		SubmissionID:          submissionID,
+		TestcaseID:            testcaseID,
+		Language:              language,
+		CheckerCode:           checkerCode,
+		Stdin:                 stdin,
+		SubmissionStdout:      submissionStdout,
+		SubmissionStderr:      submissionStderr,
+		SubmissionTimeMs:      submissionTimeMs,
+		SubmissionMemoryBytes: submissionMemoryBytes,
+		TimeLimitMs:           timeLimitMs,
+		MemoryLimitMiB:        memoryLimitMiB,
+	})
+	return nil
+}
+
+func (m *mockTaskQueue) EnqueueTaskRunCustom(runID string, gameID, userID int, language, code, stdin string, timeLimitMs, memoryLimitMiB int) error {
+	if m.err != nil {
+		return m.err
+	}
+	if m.enqueueCustomFunc != nil {
+		m.enqueueCustomFunc(taskqueue.TaskPayloadRunCustom{
+			RunID:          runID,
+			GameID:         gameID,
+			UserID:         userID,
+			Language:       language,
+			Code:           code,
+			Stdin:          stdin,
+			TimeLimitMs:    timeLimitMs,
+			MemoryLimitMiB: memoryLimitMiB,
+		})
+	}
+	return nil
+}
+
+func (m *mockTaskQueue) EnqueueTaskRunValidation(validationID, problemID, referenceSolutionID, testcaseID int, language, code, stdin, stdout, checkerCode string, timeLimitMs, memoryLimitMiB int) error {
+	if m.err != nil {
+		return m.err
+	}
+	m.enqueuedValidations = append(m.enqueuedValidations, taskqueue.TaskPayloadRunValidation{
+		ValidationID:        validationID,
+		ProblemID:           problemID,
+		ReferenceSolutionID: referenceSolutionID,
+		TestcaseID:          testcaseID,
+		Language:            language,
+		Code:                code,
+		Stdin:               stdin,
+		Stdout:              stdout,
+		CheckerCode:         checkerCode,
+		TimeLimitMs:         timeLimitMs,
+		MemoryLimitMiB:      memoryLimitMiB,
 	})
 	return nil
 }
@@ -35,14 +103,43 @@ func (m *mockTaskQueue) EnqueueTaskRunTestcase(gameID, userID, submissionID, tes
 // mockQuerier implements db.Querier for testing.
 type mockQuerier struct {
 	db.Querier
-	listTestcasesByGameIDFunc func(ctx context.Context, gameID int32) ([]db.Testcase, error)
-	createTestcaseResultFunc  func(ctx context.Context, arg db.CreateTestcaseResultParams) error
-	createTestcaseResultCalls []db.CreateTestcaseResultParams
+	listTestcasesByProblemIDFunc func(ctx context.Context, problemID int32) ([]db.Testcase, error)
+	createTestcaseResultFunc     func(ctx context.Context, arg db.CreateTestcaseResultParams) error
+	createTestcaseResultCalls    []db.CreateTestcaseResultParams
+	getLatestStateFunc           func(ctx context.Context, arg db.GetLatestStateParams) (db.GetLatestStateRow, error)
+	getProblemBySubmissionIDFunc func(ctx context.Context, submissionID int32) (db.Problem, error)
+	getProblemByIDFunc           func(ctx context.Context, problemID int32) (db.Problem, error)
+	aggregateTestcaseResultsFunc func(ctx context.Context, submissionID int32) (string, error)
+	getSubmissionByIDFunc        func(ctx context.Context, submissionID int32) (db.Submission, error)
+	updateSubmissionStatusCalls  []db.UpdateSubmissionStatusParams
+}
+
+func (m *mockQuerier) AggregateTestcaseResults(ctx context.Context, submissionID int32) (string, error) {
+	if m.aggregateTestcaseResultsFunc != nil {
+		return m.aggregateTestcaseResultsFunc(ctx, submissionID)
+	}
+	return "running", nil
+}
+
+func (m *mockQuerier) GetSubmissionByID(ctx context.Context, submissionID int32) (db.Submission, error) {
+	if m.getSubmissionByIDFunc != nil {
+		return m.getSubmissionByIDFunc(ctx, submissionID)
+	}
+	return db.Submission{}, pgx.ErrNoRows
 }
 
-func (m *mockQuerier) ListTestcasesByGameID(ctx context.Context, gameID int32) ([]db.Testcase, error) {
-	if m.listTestcasesByGameIDFunc != nil {
-		return m.listTestcasesByGameIDFunc(ctx, gameID)
+func (m *mockQuerier) UpdateSubmissionStatus(_ context.Context, arg db.UpdateSubmissionStatusParams) error {
+	m.updateSubmissionStatusCalls = append(m.updateSubmissionStatusCalls, arg)
+	return nil
+}
+
+func (m *mockQuerier) ListFinishedRejudgeIDsBySubmissionID(_ context.Context, _ int32) ([]int32, error) {
+	return nil, nil
+}
+
+func (m *mockQuerier) ListTestcasesByProblemID(ctx context.Context, problemID int32) ([]db.Testcase, error) {
+	if m.listTestcasesByProblemIDFunc != nil {
+		return m.listTestcasesByProblemIDFunc(ctx, problemID)
 	}
 	return nil, nil
 }
@@ -55,6 +152,29 @@ func (m *mockQuerier) CreateTestcaseResult(_ context.Context, arg db.CreateTestc
 	return nil
 }
 
+func (m *mockQuerier) GetLatestState(ctx context.Context, arg db.GetLatestStateParams) (db.GetLatestStateRow, error) {
+	if m.getLatestStateFunc != nil {
+		return m.getLatestStateFunc(ctx, arg)
+	}
+	return db.GetLatestStateRow{}, pgx.ErrNoRows
+}
+
+// GetProblemBySubmissionID returns a problem that uses the default checker
+// unless overridden.
+func (m *mockQuerier) GetProblemBySubmissionID(ctx context.Context, submissionID int32) (db.Problem, error) {
+	if m.getProblemBySubmissionIDFunc != nil {
+		return m.getProblemBySubmissionIDFunc(ctx, submissionID)
+	}
+	return db.Problem{Checker: checker.Default}, nil
+}
+
+func (m *mockQuerier) GetProblemByID(ctx context.Context, problemID int32) (db.Problem, error) {
+	if m.getProblemByIDFunc != nil {
+		return m.getProblemByIDFunc(ctx, problemID)
+	}
+	return db.Problem{ProblemID: problemID, Checker: checker.Default}, nil
+}
+
 func TestEnqueueTestTasks(t *testing.T) {
 	testcases := []db.Testcase{
 		{TestcaseID: 1, ProblemID: 10, Stdin: "input1", Stdout: "output1"},
@@ -62,27 +182,84 @@ func TestEnqueueTestTasks(t *testing.T) {
 	}
 
 	tq := &mockTaskQueue{}
+	var gotProblemID int32
 	mq := &mockQuerier{
-		listTestcasesByGameIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+		listTestcasesByProblemIDFunc: func(_ context.Context, problemID int32) ([]db.Testcase, error) {
+			gotProblemID = problemID
 			return testcases, nil
 		},
 	}
 
 	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
 
-	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, "php", "<?php echo 1;")
+	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, 10, "php", "<?php echo 1;")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(tq.enqueued) != 2 {
-		t.Fatalf("expected 2 enqueued tasks, got %d", len(tq.enqueued))
+	if gotProblemID != 10 {
+		t.Errorf("expected testcases of problem 10, got %d", gotProblemID)
+	}
+	if len(tq.enqueued) != 1 {
+		t.Fatalf("expected 1 enqueued task, got %d", len(tq.enqueued))
+	}
+	got := tq.enqueued[0]
+	if got.SubmissionID != 100 || got.GameID != 1 || got.UserID != 42 {
+		t.Errorf("unexpected task: %+v", got)
+	}
+	want := []taskqueue.SubmissionTestcase{
+		{TestcaseID: 1, Stdin: "input1", Stdout: "output1"},
+		{TestcaseID: 2, Stdin: "input2", Stdout: "output2"},
+	}
+	if !slices.Equal(got.Testcases, want) {
+		t.Errorf("testcases = %+v, want %+v", got.Testcases, want)
+	}
+}
+
+func TestEnqueueTestTasks_Limits(t *testing.T) {
+	timeLimit, memoryLimit, override := int32(2000), int32(64), int32(5000)
+	tq := &mockTaskQueue{}
+	mq := &mockQuerier{
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return []db.Testcase{
+				{TestcaseID: 1},
+				{TestcaseID: 2, TimeLimitMs: &override},
+			}, nil
+		},
+		getProblemByIDFunc: func(_ context.Context, problemID int32) (db.Problem, error) {
+			return db.Problem{ProblemID: problemID, TimeLimitMs: &timeLimit, MemoryLimitMib: &memoryLimit}, nil
+		},
+	}
+
+	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
+
+	if err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, 10, "php", "code"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []taskqueue.SubmissionTestcase{
+		{TestcaseID: 1, TimeLimitMs: 2000, MemoryLimitMiB: 64},
+		{TestcaseID: 2, TimeLimitMs: 5000, MemoryLimitMiB: 64},
+	}
+	if !slices.Equal(tq.enqueued[0].Testcases, want) {
+		t.Errorf("testcases = %+v, want %+v", tq.enqueued[0].Testcases, want)
+	}
+}
+
+func TestEnqueueTestTasks_NoTestcases(t *testing.T) {
+	tq := &mockTaskQueue{}
+	mq := &mockQuerier{
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+			return nil, nil
+		},
 	}
-	if tq.enqueued[0].TestcaseID != 1 || tq.enqueued[1].TestcaseID != 2 {
-		t.Errorf("unexpected testcase IDs: %d, %d", tq.enqueued[0].TestcaseID, tq.enqueued[1].TestcaseID)
+
+	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
+
+	if err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, 10, "php", "code"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
-	if tq.enqueued[0].SubmissionID != 100 {
-		t.Errorf("expected submission ID 100, got %d", tq.enqueued[0].SubmissionID)
+	if len(tq.enqueued) != 0 {
+		t.Errorf("expected no enqueued tasks, got %d", len(tq.enqueued))
 	}
 }
 
@@ -90,14 +267,14 @@ func TestEnqueueTestTasks_QueueError(t *testing.T) {
 	queueErr := errors.New("queue full")
 	tq := &mockTaskQueue{err: queueErr}
 	mq := &mockQuerier{
-		listTestcasesByGameIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
 			return []db.Testcase{{TestcaseID: 1, Stdin: "in", Stdout: "out"}}, nil
 		},
 	}
 
 	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
 
-	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, "php", "code")
+	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, 10, "php", "code")
 	if !errors.Is(err, queueErr) {
 		t.Errorf("expected queue error, got: %v", err)
 	}
@@ -107,192 +284,305 @@ func TestEnqueueTestTasks_DBError(t *testing.T) {
 	dbErr := errors.New("db error")
 	tq := &mockTaskQueue{}
 	mq := &mockQuerier{
-		listTestcasesByGameIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
+		listTestcasesByProblemIDFunc: func(_ context.Context, _ int32) ([]db.Testcase, error) {
 			return nil, dbErr
 		},
 	}
 
 	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
 
-	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, "php", "code")
+	err := hub.EnqueueTestTasks(context.Background(), 100, 1, 42, 10, "php", "code")
 	if !errors.Is(err, dbErr) {
 		t.Errorf("expected db error, got: %v", err)
 	}
 }
 
-func TestProcessTaskResultRunTestcase_Success_Correct(t *testing.T) {
+func TestProcessTaskResultRunSubmission(t *testing.T) {
+	timeMs, memoryBytes := 15, int64(1<<20)
 	mq := &mockQuerier{}
 	hub := &Hub{q: mq, ctx: context.Background()}
 
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
+	result := &taskqueue.TaskResultRunSubmission{
+		TaskPayload: &taskqueue.TaskPayloadRunSubmission{
 			SubmissionID: 1,
-			TestcaseID:   2,
-			Stdout:       "expected output",
+			Testcases: []taskqueue.SubmissionTestcase{
+				{TestcaseID: 10, Stdout: "1"},
+				{TestcaseID: 11, Stdout: "2"},
+				{TestcaseID: 12, Stdout: "3"},
+			},
+		},
+		Results: []taskqueue.TestcaseRunResult{
+			{TestcaseID: 10, Status: "success", Stdout: "1", TimeMs: &timeMs, MemoryBytes: &memoryBytes},
+			{TestcaseID: 11, Status: "success", Stdout: "0"},
+			{TestcaseID: 12, Status: "timeout", Stderr: "execution timed out"},
 		},
-		Status: "success",
-		Stdout: "expected output",
-		Stderr: "",
 	}
 
-	err := hub.processTaskResultRunTestcase(result)
-	if err != nil {
+	if err := hub.processTaskResultRunSubmission(result); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(mq.createTestcaseResultCalls) != 1 {
-		t.Fatalf("expected 1 call, got %d", len(mq.createTestcaseResultCalls))
+	if len(mq.createTestcaseResultCalls) != 3 {
+		t.Fatalf("expected 3 calls, got %d", len(mq.createTestcaseResultCalls))
+	}
+	want := []struct {
+		testcaseID int32
+		status     string
+	}{
+		{10, "success"},
+		{11, "wrong_answer"},
+		{12, "timeout"},
+	}
+	for i, w := range want {
+		got := mq.createTestcaseResultCalls[i]
+		if got.SubmissionID != 1 || got.TestcaseID != w.testcaseID || got.Status != w.status {
+			t.Errorf("result %d = %+v, want testcase %d with %q", i, got, w.testcaseID, w.status)
+		}
 	}
-	if mq.createTestcaseResultCalls[0].Status != "success" {
-		t.Errorf("expected status 'success', got %q", mq.createTestcaseResultCalls[0].Status)
+	first := mq.createTestcaseResultCalls[0]
+	if first.TimeMs == nil || *first.TimeMs != 15 || first.MemoryBytes == nil || *first.MemoryBytes != 1<<20 {
+		t.Errorf("expected the resource usage to be recorded, got %+v", first)
+	}
+	if second := mq.createTestcaseResultCalls[1]; second.TimeMs != nil || second.MemoryBytes != nil {
+		t.Errorf("expected no resource usage when not measured, got %+v", second)
 	}
 }
 
-func TestProcessTaskResultRunTestcase_Success_WrongAnswer(t *testing.T) {
-	mq := &mockQuerier{}
-	hub := &Hub{q: mq, ctx: context.Background()}
+func TestProcessTaskResultRunSubmission_SpecialJudge(t *testing.T) {
+	mq := &mockQuerier{
+		getProblemBySubmissionIDFunc: func(_ context.Context, _ int32) (db.Problem, error) {
+			return db.Problem{Language: "php", Checker: checker.Special, CheckerCode: "<?php echo 'AC';"}, nil
+		},
+	}
+	tq := &mockTaskQueue{}
+	hub := &Hub{q: mq, taskQueue: tq, ctx: context.Background()}
 
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
-			SubmissionID: 1,
-			TestcaseID:   2,
-			Stdout:       "expected",
+	result := &taskqueue.TaskResultRunSubmission{
+		TaskPayload: &taskqueue.TaskPayloadRunSubmission{
+			SubmissionID: 3,
+			Language:     "swift",
+			Testcases: []taskqueue.SubmissionTestcase{
+				{TestcaseID: 4, Stdin: "input", Stdout: "expected", TimeLimitMs: 2000, MemoryLimitMiB: 64},
+				{TestcaseID: 5, Stdin: "input", Stdout: "expected"},
+			},
+		},
+		Results: []taskqueue.TestcaseRunResult{
+			{TestcaseID: 4, Status: "success", Stdout: "actual", Stderr: "warning"},
+			{TestcaseID: 5, Status: "runtime_error", Stderr: "crash"},
 		},
-		Status: "success",
-		Stdout: "wrong",
-		Stderr: "",
 	}
 
-	err := hub.processTaskResultRunTestcase(result)
-	if err != nil {
+	if err := hub.processTaskResultRunSubmission(result); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
-	if len(mq.createTestcaseResultCalls) != 1 {
-		t.Fatalf("expected 1 call, got %d", len(mq.createTestcaseResultCalls))
+	if len(tq.enqueuedCheckers) != 1 || tq.enqueuedCheckers[0].TestcaseID != 4 {
+		t.Fatalf("expected a checker task for testcase 4, got %+v", tq.enqueuedCheckers)
+	}
+	// The checker runs in the language of the problem, even for a submission
+	// in another one.
+	got := tq.enqueuedCheckers[0]
+	wantStdin, _ := checker.SpecialJudgeStdin("input", "expected", "actual")
+	if got.CheckerCode != "<?php echo 'AC';" || got.Language != "php" || got.Stdin != wantStdin {
+		t.Errorf("unexpected checker task: %+v", got)
+	}
+	if got.SubmissionStdout != "actual" || got.SubmissionStderr != "warning" {
+		t.Errorf("expected submission output to be carried, got %+v", got)
 	}
-	if mq.createTestcaseResultCalls[0].Status != "wrong_answer" {
-		t.Errorf("expected status 'wrong_answer', got %q", mq.createTestcaseResultCalls[0].Status)
+	if got.TimeLimitMs != 2000 || got.MemoryLimitMiB != 64 {
+		t.Errorf("expected the limits of the testcase, got %+v", got)
+	}
+	if len(mq.createTestcaseResultCalls) != 1 || mq.createTestcaseResultCalls[0].Status != "runtime_error" {
+		t.Errorf("expected only the runtime error to be recorded, got %+v", mq.createTestcaseResultCalls)
 	}
 }
 
-func TestProcessTaskResultRunTestcase_NonSuccess(t *testing.T) {
-	mq := &mockQuerier{}
+func TestProcessTaskResultRunSubmission_Checker(t *testing.T) {
+	mq := &mockQuerier{
+		getProblemBySubmissionIDFunc: func(_ context.Context, _ int32) (db.Problem, error) {
+			return db.Problem{Checker: checker.Float, CheckerEpsilon: 1e-6}, nil
+		},
+	}
 	hub := &Hub{q: mq, ctx: context.Background()}
 
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
+	result := &taskqueue.TaskResultRunSubmission{
+		TaskPayload: &taskqueue.TaskPayloadRunSubmission{
+			GameID:       1,
 			SubmissionID: 1,
-			TestcaseID:   2,
-			Stdout:       "expected",
+			Testcases:    []taskqueue.SubmissionTestcase{{TestcaseID: 2, Stdout: "0.333333"}},
 		},
-		Status: "timeout",
-		Stdout: "",
-		Stderr: "execution timed out",
+		Results: []taskqueue.TestcaseRunResult{{TestcaseID: 2, Status: "success", Stdout: "0.3333333333"}},
 	}
 
-	err := hub.processTaskResultRunTestcase(result)
-	if err != nil {
+	if err := hub.processTaskResultRunSubmission(result); err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
-
-	if len(mq.createTestcaseResultCalls) != 1 {
-		t.Fatalf("expected 1 call, got %d", len(mq.createTestcaseResultCalls))
-	}
-	if mq.createTestcaseResultCalls[0].Status != "timeout" {
-		t.Errorf("expected status 'timeout', got %q", mq.createTestcaseResultCalls[0].Status)
+	if len(mq.createTestcaseResultCalls) != 1 || mq.createTestcaseResultCalls[0].Status != "success" {
+		t.Errorf("expected the output to be accepted within the epsilon, got %+v", mq.createTestcaseResultCalls)
 	}
 }
 
-func TestProcessTaskResultRunTestcase_TaskError(t *testing.T) {
+func TestProcessTaskResultRunSubmission_TaskError(t *testing.T) {
 	mq := &mockQuerier{}
 	hub := &Hub{q: mq, ctx: context.Background()}
 
 	taskErr := errors.New("worker crashed")
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
+	result := &taskqueue.TaskResultRunSubmission{
+		TaskPayload: &taskqueue.TaskPayloadRunSubmission{
 			SubmissionID: 1,
-			TestcaseID:   2,
+			Testcases: []taskqueue.SubmissionTestcase{
+				{TestcaseID: 10},
+				{TestcaseID: 11},
+			},
 		},
 		Err: taskErr,
 	}
 
-	err := hub.processTaskResultRunTestcase(result)
-	if !errors.Is(err, taskErr) {
+	if err := hub.processTaskResultRunSubmission(result); !errors.Is(err, taskErr) {
 		t.Errorf("expected task error, got: %v", err)
 	}
-	if len(mq.createTestcaseResultCalls) != 0 {
-		t.Error("expected no DB calls when task has error")
+	if len(mq.createTestcaseResultCalls) != 2 {
+		t.Fatalf("expected 2 calls, got %d", len(mq.createTestcaseResultCalls))
+	}
+	for i, got := range mq.createTestcaseResultCalls {
+		if got.TestcaseID != int32(10+i) || got.Status != "internal_error" {
+			t.Errorf("result %d = %+v, want an internal error for testcase %d", i, got, 10+i)
+		}
 	}
 }
 
-func TestNormalizeTestcaseResultOutput(t *testing.T) {
-	tests := []struct {
-		name  string
-		input string
-		want  string
+func TestProcessTaskResultRunSubmission_JudgeError(t *testing.T) {
+	mq := &mockQuerier{
+		getProblemBySubmissionIDFunc: func(_ context.Context, _ int32) (db.Problem, error) {
+			return db.Problem{Language: "php", Checker: checker.Special, CheckerCode: "<?php echo 'AC';"}, nil
+		},
+	}
+	queueErr := errors.New("queue unavailable")
+	hub := &Hub{q: mq, taskQueue: &mockTaskQueue{err: queueErr}, ctx: context.Background()}
+
+	result := &taskqueue.TaskResultRunSubmission{
+		TaskPayload: &taskqueue.TaskPayloadRunSubmission{
+			SubmissionID: 1,
+			Testcases: []taskqueue.SubmissionTestcase{
+				{TestcaseID: 10},
+				{TestcaseID: 11},
+				{TestcaseID: 12},
+			},
+		},
+		Results: []taskqueue.TestcaseRunResult{
+			{TestcaseID: 10, Status: "runtime_error"},
+			{TestcaseID: 11, Status: "success"},
+			{TestcaseID: 12, Status: "success"},
+		},
+	}
+
+	if err := hub.processTaskResultRunSubmission(result); !errors.Is(err, queueErr) {
+		t.Errorf("expected queue error, got: %v", err)
+	}
+	want := []struct {
+		testcaseID int32
+		status     string
 	}{
-		{"empty", "", ""},
-		{"no changes needed", "hello", "hello"},
-		{"trim spaces", "  hello  ", "hello"},
-		{"CRLF to LF", "line1\r\nline2", "line1\nline2"},
-		{"CR to LF", "line1\rline2", "line1\nline2"},
-		{"mixed", "  line1\r\nline2\r  ", "line1\nline2"},
+		{10, "runtime_error"},
+		{11, "internal_error"},
+		{12, "internal_error"},
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := normalizeTestcaseResultOutput(tt.input)
-			if got != tt.want {
-				t.Errorf("normalizeTestcaseResultOutput(%q) = %q, want %q", tt.input, got, tt.want)
-			}
-		})
+	if len(mq.createTestcaseResultCalls) != len(want) {
+		t.Fatalf("expected %d calls, got %d", len(want), len(mq.createTestcaseResultCalls))
+	}
+	for i, w := range want {
+		got := mq.createTestcaseResultCalls[i]
+		if got.TestcaseID != w.testcaseID || got.Status != w.status {
+			t.Errorf("result %d = %+v, want testcase %d with %q", i, got, w.testcaseID, w.status)
+		}
 	}
 }
 
-func TestCalcCodeSize_PHP(t *testing.T) {
-	hub := &Hub{}
+// mockTaskWorker delivers the results sent to it.
+type mockTaskWorker struct {
+	results chan taskqueue.TaskResult
+}
+
+func (m *mockTaskWorker) Run() error                         { return nil }
+func (m *mockTaskWorker) Results() chan taskqueue.TaskResult { return m.results }
+
+func TestProcessTaskResults_NoPayload(t *testing.T) {
+	mq := &mockQuerier{}
+	worker := &mockTaskWorker{results: make(chan taskqueue.TaskResult, 2)}
+	hub := &Hub{q: mq, taskWorker: worker, ctx: context.Background()}
+
+	// Results of tasks that failed without an error have no payload either.
+	worker.results <- &taskqueue.TaskResultRunSubmission{}
+	worker.results <- &taskqueue.TaskResultRunChecker{}
+	close(worker.results)
+	hub.processTaskResults()
+
+	if len(mq.createTestcaseResultCalls) != 0 {
+		t.Errorf("expected no testcase results, got %+v", mq.createTestcaseResultCalls)
+	}
+}
+
+func TestProcessTaskResultRunChecker_TaskError(t *testing.T) {
+	mq := &mockQuerier{}
+	hub := &Hub{q: mq, ctx: context.Background()}
+
+	result := &taskqueue.TaskResultRunChecker{
+		TaskPayload: &taskqueue.TaskPayloadRunChecker{SubmissionID: 1, TestcaseID: 2},
+		Err:         errors.New("worker crashed"),
+	}
+
+	if err := hub.processTaskResultRunChecker(result); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(mq.createTestcaseResultCalls) != 1 || mq.createTestcaseResultCalls[0].Status != "internal_error" {
+		t.Errorf("expected an internal error to be recorded, got %+v", mq.createTestcaseResultCalls)
+	}
+}
+
+func TestProcessTaskResultRunChecker(t *testing.T) {
 	tests := []struct {
-		name     string
-		code     string
-		language string
-		want     int
+		name   string
+		status string
+		stdout string
+		want   string
 	}{
-		{
-			name:     "simple php code",
-			code:     "<?php echo 1;",
-			language: "php",
-			want:     6, // "echo1;" after stripping whitespace and "<?php"
-		},
-		{
-			name:     "php with short open tag",
-			code:     "<? echo 1;",
-			language: "php",
-			want:     6, // "echo1;" after stripping whitespace and "<?"
-		},
-		{
-			name:     "php with closing tag",
-			code:     "<?php echo 1; ?>",
-			language: "php",
-			want:     6, // "echo1;" after stripping whitespace, "<?php", and "?>"
-		},
-		{
-			name:     "php with whitespace",
-			code:     "<?php echo   1 ;  ?>",
-			language: "php",
-			want:     6,
-		},
-		{
-			name:     "non-php language",
-			code:     "print(1)",
-			language: "swift",
-			want:     8,
-		},
+		{name: "accepted", status: "success", stdout: "AC\n", want: "success"},
+		{name: "rejected", status: "success", stdout: "WA\n", want: "wrong_answer"},
+		{name: "checker failed", status: "runtime_error", stdout: "", want: "internal_error"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := hub.CalcCodeSize(tt.code, tt.language)
-			if got != tt.want {
-				t.Errorf("CalcCodeSize(%q, %q) = %d, want %d", tt.code, tt.language, got, tt.want)
+			mq := &mockQuerier{}
+			hub := &Hub{q: mq, ctx: context.Background()}
+
+			timeMs := 120
+			result := &taskqueue.TaskResultRunChecker{
+				TaskPayload: &taskqueue.TaskPayloadRunChecker{
+					SubmissionID:     1,
+					TestcaseID:       2,
+					SubmissionStdout: "actual",
+					SubmissionStderr: "warning",
+					SubmissionTimeMs: &timeMs,
+				},
+				Status: tt.status,
+				Stdout: tt.stdout,
+			}
+
+			if err := hub.processTaskResultRunChecker(result); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(mq.createTestcaseResultCalls) != 1 {
+				t.Fatalf("expected 1 call, got %d", len(mq.createTestcaseResultCalls))
+			}
+			call := mq.createTestcaseResultCalls[0]
+			if call.Status != tt.want {
+				t.Errorf("expected status %q, got %q", tt.want, call.Status)
+			}
+			if call.Stdout != "actual" || call.Stderr != "warning" {
+				t.Errorf("expected the submission output to be recorded, got %q, %q", call.Stdout, call.Stderr)
+			}
+			if call.TimeMs == nil || *call.TimeMs != 120 {
+				t.Errorf("expected the submission time to be recorded, got %v", call.TimeMs)
 			}
 		})
 	}
@@ -344,23 +634,76 @@ func (m *recordingTxManager) RunInTx(_ context.Context, fn func(q db.Querier) er
 	return fn(q)
 }
 
+func TestRunCode(t *testing.T) {
+	tq := &mockTaskQueue{}
+	hub := NewGameHub(&mockQuerier{}, &mockTxManager{}, tq, nil)
+	tq.enqueueCustomFunc = func(payload taskqueue.TaskPayloadRunCustom) {
+		if payload.Stdin != "hello" {
+			t.Errorf("expected stdin 'hello', got %q", payload.Stdin)
+		}
+		if payload.TimeLimitMs != 2000 || payload.MemoryLimitMiB != 64 {
+			t.Errorf("expected the limits to be passed, got %+v", payload)
+		}
+		err := hub.processTaskResultRunCustom(&taskqueue.TaskResultRunCustom{
+			TaskPayload: &payload,
+			Status:      "success",
+			Stdout:      "hello",
+		})
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	result, err := hub.RunCode(context.Background(), 1, 42, "php", "<?php echo fgets(STDIN);", "hello", 2000, 64)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Status != "success" || result.Stdout != "hello" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+	if len(hub.pendingRuns) != 0 {
+		t.Errorf("expected no pending runs, got %d", len(hub.pendingRuns))
+	}
+}
+
+func TestRunCode_TaskError(t *testing.T) {
+	taskErr := errors.New("worker down")
+	tq := &mockTaskQueue{}
+	hub := NewGameHub(&mockQuerier{}, &mockTxManager{}, tq, nil)
+	tq.enqueueCustomFunc = func(payload taskqueue.TaskPayloadRunCustom) {
+		_ = hub.processTaskResultRunCustom(&taskqueue.TaskResultRunCustom{
+			TaskPayload: &payload,
+			Err:         taskErr,
+		})
+	}
+
+	_, err := hub.RunCode(context.Background(), 1, 42, "php", "<?php echo 1;", "", 0, 0)
+	if !errors.Is(err, taskErr) {
+		t.Errorf("expected task error, got %v", err)
+	}
+}
+
+func TestProcessTaskResultRunCustom_NoWaiter(t *testing.T) {
+	hub := NewGameHub(&mockQuerier{}, &mockTxManager{}, &mockTaskQueue{}, nil)
+	err := hub.processTaskResultRunCustom(&taskqueue.TaskResultRunCustom{
+		TaskPayload: &taskqueue.TaskPayloadRunCustom{RunID: "gone"},
+		Status:      "success",
+	})
+	if err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
 func TestUpdateSubmissionAndGameState_Success(t *testing.T) {
 	txm := &recordingTxManager{}
 	hub := &Hub{
-		q:   &mockQuerier{},
-		txm: txm,
-		ctx: context.Background(),
-	}
-
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
-			GameID:       1,
-			UserID:       2,
-			SubmissionID: 3,
-		},
+		q:      &mockQuerier{},
+		txm:    txm,
+		ctx:    context.Background(),
+		events: NewEventBroker(),
 	}
 
-	err := hub.updateSubmissionAndGameState(result, "success")
+	err := hub.updateSubmissionAndGameState(3, 1, 2, 5, 10, "success")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -375,23 +718,55 @@ func TestUpdateSubmissionAndGameState_Success(t *testing.T) {
 	}
 }
 
-func TestUpdateSubmissionAndGameState_Failure(t *testing.T) {
-	txm := &recordingTxManager{}
+func TestUpdateSubmissionAndGameState_PublishesEvents(t *testing.T) {
+	codeSize := int32(10)
+	submittedAt := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
 	hub := &Hub{
-		q:   &mockQuerier{},
-		txm: txm,
-		ctx: context.Background(),
+		q: &mockQuerier{
+			getLatestStateFunc: func(_ context.Context, _ db.GetLatestStateParams) (db.GetLatestStateRow, error) {
+				return db.GetLatestStateRow{
+					CodeSize:  &codeSize,
+					CreatedAt: pgtype.Timestamp{Time: submittedAt, Valid: true},
+				}, nil
+			},
+		},
+		txm:    &recordingTxManager{},
+		ctx:    context.Background(),
+		events: NewEventBroker(),
 	}
+	events, unsubscribe := hub.SubscribeEvents(1)
+	defer unsubscribe()
 
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
-			GameID:       1,
-			UserID:       2,
-			SubmissionID: 3,
-		},
+	if err := hub.updateSubmissionAndGameState(3, 1, 2, 5, 10, "success"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
 	}
 
-	err := hub.updateSubmissionAndGameState(result, "wrong_answer")
+	statusEvent := <-events
+	if statusEvent.Type != EventTypeStatus || statusEvent.UserID != 2 || statusEvent.TeamID != 5 || statusEvent.ProblemID != 10 || statusEvent.Status != "success" {
+		t.Errorf("unexpected status event: %+v", statusEvent)
+	}
+	scoreEvent := <-events
+	if scoreEvent.Type != EventTypeBestScore || scoreEvent.UserID != 2 || scoreEvent.TeamID != 5 || scoreEvent.ProblemID != 10 {
+		t.Fatalf("unexpected best score event: %+v", scoreEvent)
+	}
+	if scoreEvent.Score == nil || *scoreEvent.Score != 10 {
+		t.Errorf("expected score 10, got %v", scoreEvent.Score)
+	}
+	if scoreEvent.BestScoreSubmittedAt == nil || *scoreEvent.BestScoreSubmittedAt != submittedAt.Unix() {
+		t.Errorf("expected submitted at %d, got %v", submittedAt.Unix(), scoreEvent.BestScoreSubmittedAt)
+	}
+}
+
+func TestUpdateSubmissionAndGameState_Failure(t *testing.T) {
+	txm := &recordingTxManager{}
+	hub := &Hub{
+		q:      &mockQuerier{},
+		txm:    txm,
+		ctx:    context.Background(),
+		events: NewEventBroker(),
+	}
+
+	err := hub.updateSubmissionAndGameState(3, 1, 2, 5, 10, "wrong_answer")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -401,78 +776,59 @@ func TestUpdateSubmissionAndGameState_Failure(t *testing.T) {
 	if !txm.lastQuerier.updateGameStateStatusCalled {
 		t.Error("expected UpdateGameStateStatus to be called")
 	}
-	if txm.lastQuerier.syncGameStateBestScoreSubmissionCalled {
-		t.Error("expected SyncGameStateBestScoreSubmission NOT to be called for 'wrong_answer' status")
+	// A rejudge may turn the best submission into a failure.
+	if !txm.lastQuerier.syncGameStateBestScoreSubmissionCalled {
+		t.Error("expected SyncGameStateBestScoreSubmission to be called for 'wrong_answer' status")
 	}
 }
 
-func TestUpdateSubmissionAndGameState_TxError(t *testing.T) {
-	txErr := errors.New("tx failed")
-	txm := &mockTxManager{err: txErr}
+func TestUpdateSubmissionIfJudged_Practice(t *testing.T) {
+	q := &mockQuerier{
+		aggregateTestcaseResultsFunc: func(_ context.Context, _ int32) (string, error) {
+			return "success", nil
+		},
+		getSubmissionByIDFunc: func(_ context.Context, submissionID int32) (db.Submission, error) {
+			return db.Submission{SubmissionID: submissionID, GameID: 1, TeamID: 5, ProblemID: 10, IsPractice: true}, nil
+		},
+	}
+	txm := &recordingTxManager{}
 	hub := &Hub{
-		q:   &mockQuerier{},
-		txm: txm,
-		ctx: context.Background(),
+		q:      q,
+		txm:    txm,
+		ctx:    context.Background(),
+		events: NewEventBroker(),
 	}
+	events, unsubscribe := hub.SubscribeEvents(1)
+	defer unsubscribe()
 
-	result := &taskqueue.TaskResultRunTestcase{
-		TaskPayload: &taskqueue.TaskPayloadRunTestcase{
-			GameID:       1,
-			UserID:       2,
-			SubmissionID: 3,
-		},
-	}
+	hub.updateSubmissionIfJudged(3, 1, 2)
 
-	err := hub.updateSubmissionAndGameState(result, "success")
-	if !errors.Is(err, txErr) {
-		t.Errorf("expected tx error, got: %v", err)
+	want := []db.UpdateSubmissionStatusParams{{SubmissionID: 3, Status: "success"}}
+	if !slices.Equal(q.updateSubmissionStatusCalls, want) {
+		t.Errorf("UpdateSubmissionStatus calls = %+v, want %+v", q.updateSubmissionStatusCalls, want)
+	}
+	if txm.lastQuerier != nil {
+		t.Error("expected the game state to be left as it is")
+	}
+	select {
+	case e := <-events:
+		t.Errorf("expected no events, got %+v", e)
+	default:
 	}
 }
 
-func TestIsTestcaseResultCorrect(t *testing.T) {
-	tests := []struct {
-		name     string
-		expected string
-		actual   string
-		want     bool
-	}{
-		{
-			name:     "exact match",
-			expected: "hello",
-			actual:   "hello",
-			want:     true,
-		},
-		{
-			name:     "trailing newline ignored",
-			expected: "hello\n",
-			actual:   "hello",
-			want:     true,
-		},
-		{
-			name:     "CRLF normalized",
-			expected: "hello\r\n",
-			actual:   "hello\n",
-			want:     true,
-		},
-		{
-			name:     "mismatch",
-			expected: "hello",
-			actual:   "world",
-			want:     false,
-		},
-		{
-			name:     "multiline match",
-			expected: "line1\nline2",
-			actual:   "line1\nline2\n",
-			want:     true,
-		},
+func TestUpdateSubmissionAndGameState_TxError(t *testing.T) {
+	txErr := errors.New("tx failed")
+	txm := &mockTxManager{err: txErr}
+	hub := &Hub{
+		q:      &mockQuerier{},
+		txm:    txm,
+		ctx:    context.Background(),
+		events: NewEventBroker(),
 	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := isTestcaseResultCorrect(tt.expected, tt.actual)
-			if got != tt.want {
-				t.Errorf("isTestcaseResultCorrect(%q, %q) = %v, want %v", tt.expected, tt.actual, got, tt.want)
-			}
-		})
+
+	err := hub.updateSubmissionAndGameState(3, 1, 2, 5, 10, "success")
+	if !errors.Is(err, txErr) {
+		t.Errorf("expected tx error, got: %v", err)
 	}
 }
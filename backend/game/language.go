@@ -0,0 +1,122 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/scoring"
+)
+
+// Languages lists the languages that the workers can run.
+var Languages = []string{"php", "swift"}
+
+// ProblemLanguage is a language that players can choose for a problem, with
+// the code they start from.
+type ProblemLanguage struct {
+	Language   string
+	SampleCode string
+}
+
+// problemLanguagesByID returns the languages the problems accept besides their
+// own, keyed by problem ID.
+func problemLanguagesByID(ctx context.Context, q db.Querier, problemIDs []int32) (map[int32][]db.ProblemLanguage, error) {
+	rows, err := q.ListProblemLanguages(ctx, problemIDs)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	languages := make(map[int32][]db.ProblemLanguage)
+	for _, row := range rows {
+		languages[row.ProblemID] = append(languages[row.ProblemID], row)
+	}
+	return languages, nil
+}
+
+// problemLanguages returns all the languages of the problem, its own first.
+func problemLanguages(problem db.Problem, others []db.ProblemLanguage) []ProblemLanguage {
+	languages := []ProblemLanguage{{Language: problem.Language, SampleCode: problem.SampleCode}}
+	for _, row := range others {
+		languages = append(languages, ProblemLanguage{Language: row.Language, SampleCode: row.SampleCode})
+	}
+	return languages
+}
+
+// resolveLanguage returns the language that code for the problem is written
+// in. An empty language means the problem's own one.
+func resolveLanguage(ctx context.Context, q db.Querier, problem db.Problem, language string) (string, error) {
+	if language == "" || language == problem.Language {
+		return problem.Language, nil
+	}
+	others, err := problemLanguagesByID(ctx, q, []int32{problem.ProblemID})
+	if err != nil {
+		return "", err
+	}
+	if !slices.ContainsFunc(others[problem.ProblemID], func(row db.ProblemLanguage) bool {
+		return row.Language == language
+	}) {
+		return "", ErrLanguageNotAllowed
+	}
+	return language, nil
+}
+
+// ListProblemLanguages returns all the languages of the problem, its own
+// first.
+func (s *Service) ListProblemLanguages(ctx context.Context, problemID int) ([]ProblemLanguage, error) {
+	problem, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	others, err := problemLanguagesByID(ctx, s.q, []int32{problem.ProblemID})
+	if err != nil {
+		return nil, err
+	}
+	return problemLanguages(problem, others[problem.ProblemID]), nil
+}
+
+// SetProblemLanguages replaces the languages the problem accepts besides its
+// own. The scoring strategy of the problem must support all of them.
+func (s *Service) SetProblemLanguages(ctx context.Context, problemID int, others []ProblemLanguage) error {
+	problem, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		return setProblemLanguages(ctx, qtx, problem.ProblemID, problem.Language, problem.Scoring, others)
+	})
+}
+
+// setProblemLanguages replaces the languages the problem accepts besides
+// language, its own, all of which scoringName must support.
+func setProblemLanguages(ctx context.Context, qtx db.Querier, problemID int32, language, scoringName string, others []ProblemLanguage) error {
+	for _, l := range others {
+		if !scoring.SupportsLanguage(scoringName, l.Language) {
+			return fmt.Errorf("%w: scoring %s does not support %s", ErrLanguageNotAllowed, scoringName, l.Language)
+		}
+	}
+	if err := qtx.DeleteProblemLanguages(ctx, problemID); err != nil {
+		return err
+	}
+	for _, l := range others {
+		if l.Language == language {
+			continue
+		}
+		if err := qtx.CreateProblemLanguage(ctx, db.CreateProblemLanguageParams{
+			ProblemID:  problemID,
+			Language:   l.Language,
+			SampleCode: l.SampleCode,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/scoring"
+)
+
+// languageQuerier returns fixed languages of problems for testing.
+type languageQuerier struct {
+	db.Querier
+	languages []db.ProblemLanguage
+}
+
+func (m *languageQuerier) ListProblemLanguages(_ context.Context, _ []int32) ([]db.ProblemLanguage, error) {
+	return m.languages, nil
+}
+
+func TestResolveLanguage(t *testing.T) {
+	problem := db.Problem{ProblemID: 1, Language: "php"}
+	q := &languageQuerier{languages: []db.ProblemLanguage{{ProblemID: 1, Language: "swift"}}}
+	tests := []struct {
+		name      string
+		q         *languageQuerier
+		language  string
+		want      string
+		wantError error
+	}{
+		{name: "default", q: q, language: "", want: "php"},
+		{name: "own language", q: q, language: "php", want: "php"},
+		{name: "other language", q: q, language: "swift", want: "swift"},
+		{name: "not allowed", q: &languageQuerier{}, language: "swift", wantError: ErrLanguageNotAllowed},
+		{name: "unknown", q: q, language: "ruby", wantError: ErrLanguageNotAllowed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := resolveLanguage(context.Background(), tt.q, problem, tt.language)
+			if !errors.Is(err, tt.wantError) {
+				t.Fatalf("err = %v, want %v", err, tt.wantError)
+			}
+			if got != tt.want {
+				t.Errorf("resolveLanguage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// problemTxQuerier serves a problem inside RunInTx and records the writes.
+type problemTxQuerier struct {
+	db.Querier
+	problem          db.Problem
+	submissions      []db.Submission
+	writes           []string
+	updatedCodeSizes []db.UpdateSubmissionCodeSizeParams
+	createdLanguages []db.CreateProblemLanguageParams
+}
+
+func (m *problemTxQuerier) GetProblemByID(_ context.Context, _ int32) (db.Problem, error) {
+	return m.problem, nil
+}
+
+func (m *problemTxQuerier) UpdateProblem(_ context.Context, _ db.UpdateProblemParams) error {
+	m.writes = append(m.writes, "UpdateProblem")
+	return nil
+}
+
+func (m *problemTxQuerier) DeleteProblemLanguages(_ context.Context, _ int32) error {
+	m.writes = append(m.writes, "DeleteProblemLanguages")
+	return nil
+}
+
+func (m *problemTxQuerier) CreateProblemLanguage(_ context.Context, arg db.CreateProblemLanguageParams) error {
+	m.writes = append(m.writes, "CreateProblemLanguage")
+	m.createdLanguages = append(m.createdLanguages, arg)
+	return nil
+}
+
+func (m *problemTxQuerier) ListSubmissionsByProblemID(_ context.Context, _ int32) ([]db.Submission, error) {
+	return m.submissions, nil
+}
+
+func (m *problemTxQuerier) UpdateSubmissionCodeSize(_ context.Context, arg db.UpdateSubmissionCodeSizeParams) error {
+	m.writes = append(m.writes, "UpdateSubmissionCodeSize")
+	m.updatedCodeSizes = append(m.updatedCodeSizes, arg)
+	return nil
+}
+
+func (m *problemTxQuerier) ListGameStateIDsByProblemID(_ context.Context, _ int32) ([]db.ListGameStateIDsByProblemIDRow, error) {
+	return nil, nil
+}
+
+// countingTxManager runs fn with q and counts the transactions.
+type countingTxManager struct {
+	q     db.Querier
+	count int
+}
+
+func (m *countingTxManager) RunInTx(_ context.Context, fn func(q db.Querier) error) error {
+	m.count++
+	return fn(m.q)
+}
+
+func TestUpdateProblem_InOneTransaction(t *testing.T) {
+	qtx := &problemTxQuerier{
+		problem: db.Problem{ProblemID: 1, Language: "php", Scoring: scoring.StrippedBytes},
+		submissions: []db.Submission{
+			{SubmissionID: 10, ProblemID: 1, Language: "php", Code: "<?php echo 1;", CodeSize: 6},
+		},
+	}
+	txm := &countingTxManager{q: qtx}
+	// Any query outside the transaction panics on the nil Querier.
+	s := NewService(&languageQuerier{}, txm, nil)
+
+	err := s.UpdateProblem(context.Background(), UpdateProblemParams{
+		ProblemID:      1,
+		Title:          "Title",
+		Language:       "php",
+		Scoring:        scoring.PHPTokens,
+		Checker:        "exact",
+		OtherLanguages: []ProblemLanguage{{Language: "php"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if txm.count != 1 {
+		t.Errorf("ran %d transactions, want 1", txm.count)
+	}
+	want := []string{"UpdateProblem", "DeleteProblemLanguages", "UpdateSubmissionCodeSize"}
+	if !slices.Equal(qtx.writes, want) {
+		t.Errorf("writes = %v, want %v", qtx.writes, want)
+	}
+	if len(qtx.updatedCodeSizes) != 1 || qtx.updatedCodeSizes[0].CodeSize != 3 {
+		t.Errorf("updatedCodeSizes = %+v, want code size 3", qtx.updatedCodeSizes)
+	}
+}
+
+func TestUpdateProblem_LanguageNotSupported(t *testing.T) {
+	qtx := &problemTxQuerier{
+		problem: db.Problem{ProblemID: 1, Language: "php", Scoring: scoring.StrippedBytes},
+	}
+	s := NewService(&languageQuerier{}, &countingTxManager{q: qtx}, nil)
+
+	err := s.UpdateProblem(context.Background(), UpdateProblemParams{
+		ProblemID:      1,
+		Language:       "php",
+		Scoring:        scoring.PHPTokens,
+		Checker:        "exact",
+		OtherLanguages: []ProblemLanguage{{Language: "swift"}},
+	})
+	if !errors.Is(err, ErrLanguageNotAllowed) {
+		t.Fatalf("err = %v, want %v", err, ErrLanguageNotAllowed)
+	}
+	if len(qtx.createdLanguages) != 0 {
+		t.Errorf("created languages %+v, want none", qtx.createdLanguages)
+	}
+}
@@ -0,0 +1,168 @@
+package game
+
+import (
+	"math"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+// State is the state of a game in its lifecycle.
+type State string
+
+const (
+	// StateWaiting is a game whose start time is not set yet.
+	StateWaiting   State = "waiting"
+	StateScheduled State = "scheduled"
+	StateRunning   State = "running"
+	StatePaused    State = "paused"
+	StateFinished  State = "finished"
+	StateCancelled State = "cancelled"
+)
+
+// Action is a transition of a game state triggered by an admin.
+type Action string
+
+const (
+	ActionSchedule Action = "schedule"
+	ActionStart    Action = "start"
+	ActionPause    Action = "pause"
+	ActionResume   Action = "resume"
+	ActionExtend   Action = "extend"
+	ActionEnd      Action = "end"
+	ActionCancel   Action = "cancel"
+	// ActionSetDuration changes the duration of a game that has not started
+	// yet, from the edit form. Once it has, ActionExtend is used instead.
+	ActionSetDuration Action = "set_duration"
+)
+
+// startDelay is the countdown before a game started by ActionStart.
+const startDelay = 10 * time.Second
+
+// Lifecycle holds the columns of a game that determine its state.
+//
+// Most of the time the state follows the clock: a game is scheduled until
+// StartedAt, running for DurationSeconds and then finished. OverrideState, if
+// set, takes precedence over the clock; it is one of StatePaused,
+// StateFinished (ended early) and StateCancelled.
+type Lifecycle struct {
+	StartedAt       pgtype.Timestamp
+	DurationSeconds int32
+	PausedAt        pgtype.Timestamp
+	OverrideState   *string
+}
+
+// LifecycleFromGame returns the lifecycle of a game row.
+func LifecycleFromGame(row db.Game) Lifecycle {
+	return Lifecycle{
+		StartedAt:       row.StartedAt,
+		DurationSeconds: row.DurationSeconds,
+		PausedAt:        row.PausedAt,
+		OverrideState:   row.OverrideState,
+	}
+}
+
+// StateAt returns the state of the game at the time.
+func (l Lifecycle) StateAt(now time.Time) State {
+	if l.OverrideState != nil {
+		return State(*l.OverrideState)
+	}
+	if !l.StartedAt.Valid {
+		return StateWaiting
+	}
+	if now.Before(l.StartedAt.Time) {
+		return StateScheduled
+	}
+	if now.Before(l.endsAt()) {
+		return StateRunning
+	}
+	return StateFinished
+}
+
+func (l Lifecycle) endsAt() time.Time {
+	return l.StartedAt.Time.Add(time.Duration(l.DurationSeconds) * time.Second)
+}
+
+// Apply returns the lifecycle after the action. at is the start time for
+// ActionSchedule, which must be in the future, and seconds is the length added
+// by ActionExtend or the duration set by ActionSetDuration; they are ignored
+// by the other actions. It fails with ErrInvalidTransition if the action is
+// not allowed in the current state or with these arguments.
+func (l Lifecycle) Apply(action Action, now time.Time, at time.Time, seconds int) (Lifecycle, error) {
+	state := l.StateAt(now)
+	if !slices.Contains(allowedStates[action], state) {
+		return l, ErrInvalidTransition
+	}
+
+	next := l
+	switch action {
+	case ActionSchedule:
+		// A start time in the past would start the game at once, or even
+		// finish it, without the delay of ActionStart.
+		if !at.After(now) {
+			return l, ErrInvalidTransition
+		}
+		next.StartedAt = pgtype.Timestamp{Time: at, Valid: true}
+	case ActionStart:
+		next.StartedAt = pgtype.Timestamp{Time: now.Add(startDelay), Valid: true}
+	case ActionPause:
+		next.PausedAt = pgtype.Timestamp{Time: now, Valid: true}
+		next.OverrideState = stateOverride(StatePaused)
+	case ActionResume:
+		// The paused time is added to the duration so that players do not
+		// lose it.
+		paused := now.Sub(l.PausedAt.Time).Seconds()
+		next.DurationSeconds += int32(math.Ceil(paused))
+		next.PausedAt = pgtype.Timestamp{}
+		next.OverrideState = nil
+	case ActionExtend:
+		if seconds <= 0 {
+			return l, ErrInvalidTransition
+		}
+		next.DurationSeconds += int32(seconds)
+	case ActionSetDuration:
+		if seconds <= 0 {
+			return l, ErrInvalidTransition
+		}
+		next.DurationSeconds = int32(seconds)
+	case ActionEnd:
+		next.OverrideState = stateOverride(StateFinished)
+	case ActionCancel:
+		next.OverrideState = stateOverride(StateCancelled)
+	default:
+		return l, ErrInvalidTransition
+	}
+	return next, nil
+}
+
+var allowedStates = map[Action][]State{
+	ActionSchedule: {StateWaiting, StateScheduled},
+	ActionStart:    {StateWaiting, StateScheduled},
+	ActionPause:    {StateRunning},
+	ActionResume:   {StatePaused},
+	ActionExtend:   {StateScheduled, StateRunning, StatePaused},
+	ActionEnd:      {StateRunning, StatePaused},
+	ActionCancel:   {StateWaiting, StateScheduled, StateRunning, StatePaused},
+
+	ActionSetDuration: {StateWaiting, StateScheduled},
+}
+
+// AllowedActions returns the actions allowed in the state, in the order shown
+// to admins. ActionSetDuration is left out, as it is taken from the edit form.
+func AllowedActions(state State) []Action {
+	var actions []Action
+	for _, action := range []Action{ActionSchedule, ActionStart, ActionPause, ActionResume, ActionExtend, ActionEnd, ActionCancel} {
+		if slices.Contains(allowedStates[action], state) {
+			actions = append(actions, action)
+		}
+	}
+	return actions
+}
+
+func stateOverride(state State) *string {
+	s := string(state)
+	return &s
+}
@@ -0,0 +1,140 @@
+package game
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+func TestLifecycleApply_Start(t *testing.T) {
+	now := time.Now()
+	l := Lifecycle{DurationSeconds: 300}
+
+	next, err := l.Apply(ActionStart, now, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := next.StateAt(now); got != StateScheduled {
+		t.Errorf("state = %s, want %s", got, StateScheduled)
+	}
+	if got := next.StateAt(now.Add(startDelay)); got != StateRunning {
+		t.Errorf("state after delay = %s, want %s", got, StateRunning)
+	}
+}
+
+func TestLifecycleApply_Schedule(t *testing.T) {
+	now := time.Now()
+	l := Lifecycle{DurationSeconds: 300}
+
+	next, err := l.Apply(ActionSchedule, now, now.Add(time.Hour), 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := next.StateAt(now); got != StateScheduled {
+		t.Errorf("state = %s, want %s", got, StateScheduled)
+	}
+
+	for _, at := range []time.Time{now, now.Add(-time.Minute)} {
+		if _, err := l.Apply(ActionSchedule, now, at, 0); !errors.Is(err, ErrInvalidTransition) {
+			t.Errorf("schedule at %s: expected ErrInvalidTransition, got %v", at.Sub(now), err)
+		}
+	}
+}
+
+func TestLifecycleApply_PauseAndResume(t *testing.T) {
+	now := time.Now()
+	l := Lifecycle{
+		StartedAt:       pgtype.Timestamp{Time: now.Add(-time.Minute), Valid: true},
+		DurationSeconds: 300,
+	}
+
+	paused, err := l.Apply(ActionPause, now, time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("pause: unexpected error: %v", err)
+	}
+	// The game stays paused even after its original end time.
+	if got := paused.StateAt(now.Add(time.Hour)); got != StatePaused {
+		t.Errorf("state = %s, want %s", got, StatePaused)
+	}
+
+	resumed, err := paused.Apply(ActionResume, now.Add(90*time.Second), time.Time{}, 0)
+	if err != nil {
+		t.Fatalf("resume: unexpected error: %v", err)
+	}
+	if resumed.DurationSeconds != 390 {
+		t.Errorf("duration = %d, want 390", resumed.DurationSeconds)
+	}
+	if resumed.PausedAt.Valid || resumed.OverrideState != nil {
+		t.Errorf("expected pause to be cleared, got %+v", resumed)
+	}
+	if got := resumed.StateAt(now.Add(90 * time.Second)); got != StateRunning {
+		t.Errorf("state = %s, want %s", got, StateRunning)
+	}
+}
+
+func TestLifecycleApply_SetDuration(t *testing.T) {
+	now := time.Now()
+	l := Lifecycle{DurationSeconds: 300}
+
+	next, err := l.Apply(ActionSetDuration, now, time.Time{}, 600)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if next.DurationSeconds != 600 {
+		t.Errorf("duration = %d, want 600", next.DurationSeconds)
+	}
+	if got := next.StateAt(now); got != StateWaiting {
+		t.Errorf("state = %s, want %s", got, StateWaiting)
+	}
+}
+
+func TestLifecycleApply_InvalidTransitions(t *testing.T) {
+	now := time.Now()
+	running := Lifecycle{
+		StartedAt:       pgtype.Timestamp{Time: now.Add(-time.Minute), Valid: true},
+		DurationSeconds: 300,
+	}
+	cancelled := running
+	cancelled.OverrideState = stateOverride(StateCancelled)
+
+	tests := []struct {
+		name          string
+		lifecycle     Lifecycle
+		action        Action
+		extendSeconds int
+	}{
+		{"resume running game", running, ActionResume, 0},
+		{"start running game", running, ActionStart, 0},
+		{"pause waiting game", Lifecycle{DurationSeconds: 300}, ActionPause, 0},
+		{"extend by zero", running, ActionExtend, 0},
+		{"set duration of running game", running, ActionSetDuration, 600},
+		{"set duration to zero", Lifecycle{DurationSeconds: 300}, ActionSetDuration, 0},
+		{"end cancelled game", cancelled, ActionEnd, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.lifecycle.Apply(tt.action, now, time.Time{}, tt.extendSeconds)
+			if !errors.Is(err, ErrInvalidTransition) {
+				t.Errorf("expected ErrInvalidTransition, got %v", err)
+			}
+		})
+	}
+}
+
+func TestAllowedActions(t *testing.T) {
+	got := AllowedActions(StatePaused)
+	want := []Action{ActionResume, ActionExtend, ActionEnd, ActionCancel}
+	if len(got) != len(want) {
+		t.Fatalf("AllowedActions(paused) = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("AllowedActions(paused)[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+	if got := AllowedActions(StateCancelled); len(got) != 0 {
+		t.Errorf("AllowedActions(cancelled) = %v, want none", got)
+	}
+}
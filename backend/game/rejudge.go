@@ -0,0 +1,315 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+)
+
+// Phases of the rankings recorded for a rejudge.
+const (
+	rankingBefore = "before"
+	rankingAfter  = "after"
+)
+
+// Rejudge is a rejudge of the submissions to a problem that were judged with
+// older testcases.
+type Rejudge struct {
+	RejudgeID int
+	ProblemID int
+	Reason    string
+	UserID    *int
+	CreatedAt time.Time
+	// FinishedAt is nil while some of the submissions are being judged.
+	FinishedAt *time.Time
+}
+
+// RejudgedSubmission is a submission judged again by a rejudge.
+type RejudgedSubmission struct {
+	SubmissionID int
+	GameID       int
+	TeamID       int
+	IsPractice   bool
+	StatusBefore string
+	// Status is the verdict of the rejudge, or "running" until it is judged.
+	Status string
+}
+
+// RankingChange is a team whose rank or score in a game was changed by a
+// rejudge.
+type RankingChange struct {
+	GameID   int
+	TeamID   int
+	TeamName string
+	// RankBefore and ScoreBefore are nil if the team was not ranked before
+	// the rejudge, and RankAfter and ScoreAfter if it is no longer ranked.
+	RankBefore  *int
+	ScoreBefore *int
+	RankAfter   *int
+	ScoreAfter  *int
+}
+
+// CountStaleSubmissions returns the number of submissions to the problem that
+// were judged before the last change to its testcases.
+func (s *Service) CountStaleSubmissions(ctx context.Context, problemID int) (int, error) {
+	submissions, err := s.q.ListStaleSubmissionsByProblemID(ctx, int32(problemID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	return len(submissions), nil
+}
+
+// RejudgeStaleSubmissions rejudges the submissions to the problem that were
+// judged before the last change to its testcases, and returns the ID of the
+// rejudge, or 0 if there is nothing to rejudge. The rankings of the games are
+// recorded before the rejudge and again once all the submissions are judged.
+func (s *Service) RejudgeStaleSubmissions(ctx context.Context, problemID int, reason string, adminID int32) (int, error) {
+	problem, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	testcases, err := s.q.ListTestcasesByProblemID(ctx, problem.ProblemID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	if len(testcases) == 0 {
+		return 0, ErrNoTestcases
+	}
+
+	var rejudgeID int32
+	var submissions []db.Submission
+	err = s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		submissions, err = qtx.ListStaleSubmissionsByProblemID(ctx, problem.ProblemID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if len(submissions) == 0 {
+			return nil
+		}
+		rejudgeID, err = qtx.CreateRejudge(ctx, db.CreateRejudgeParams{
+			ProblemID: problem.ProblemID,
+			Reason:    reason,
+			UserID:    &adminID,
+		})
+		if err != nil {
+			return err
+		}
+		if err := snapshotRankings(ctx, qtx, rejudgeID, rankedGameIDs(submissions), rankingBefore); err != nil {
+			return err
+		}
+		for _, sub := range submissions {
+			if err := qtx.CreateRejudgeSubmission(ctx, db.CreateRejudgeSubmissionParams{
+				RejudgeID:    rejudgeID,
+				SubmissionID: sub.SubmissionID,
+				StatusBefore: sub.Status,
+			}); err != nil {
+				return err
+			}
+			if err := resetSubmission(ctx, qtx, sub.SubmissionID); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	for _, sub := range submissions {
+		if err := s.hub.EnqueueTestTasks(ctx, int(sub.SubmissionID), int(sub.GameID), int(sub.UserID), int(sub.ProblemID), sub.Language, sub.Code); err != nil {
+			return 0, err
+		}
+	}
+	return int(rejudgeID), nil
+}
+
+// resetSubmission clears the results of the submission to judge it again.
+func resetSubmission(ctx context.Context, qtx db.Querier, submissionID int32) error {
+	if err := qtx.DeleteTestcaseResultsBySubmissionID(ctx, submissionID); err != nil {
+		return err
+	}
+	return qtx.UpdateSubmissionStatus(ctx, db.UpdateSubmissionStatusParams{
+		SubmissionID: submissionID,
+		Status:       "running",
+	})
+}
+
+// rankedGameIDs returns the games whose rankings the submissions count for.
+func rankedGameIDs(submissions []db.Submission) []int32 {
+	var gameIDs []int32
+	for _, sub := range submissions {
+		if !sub.IsPractice && !slices.Contains(gameIDs, sub.GameID) {
+			gameIDs = append(gameIDs, sub.GameID)
+		}
+	}
+	return gameIDs
+}
+
+// snapshotRankings records the current rankings of the games for the rejudge.
+func snapshotRankings(ctx context.Context, qtx db.Querier, rejudgeID int32, gameIDs []int32, phase string) error {
+	for _, gameID := range gameIDs {
+		gameRow, err := qtx.GetGameByID(ctx, gameID)
+		if err != nil {
+			return err
+		}
+		teams, ranks, err := RankedRows(ctx, qtx, gameRow, time.Time{}, false)
+		if err != nil {
+			return err
+		}
+		for i, t := range teams {
+			if err := qtx.CreateRejudgeRanking(ctx, db.CreateRejudgeRankingParams{
+				RejudgeID: rejudgeID,
+				GameID:    gameID,
+				TeamID:    t.Team.TeamID,
+				Phase:     phase,
+				Rank:      int32(ranks[i]),
+				Score:     int32(t.Score),
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// finishRejudges completes the rejudges whose last submission has just been
+// judged, recording the rankings after them.
+func finishRejudges(ctx context.Context, txm db.TxManager, q db.Querier, submissionID int32) {
+	rejudgeIDs, err := q.ListFinishedRejudgeIDsBySubmissionID(ctx, submissionID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		slog.Error("failed to list rejudges", "error", err, "submissionID", submissionID)
+		return
+	}
+	for _, rejudgeID := range rejudgeIDs {
+		err := txm.RunInTx(ctx, func(qtx db.Querier) error {
+			rows, err := qtx.ListRejudgeSubmissions(ctx, rejudgeID)
+			if err != nil {
+				return err
+			}
+			submissions := make([]db.Submission, len(rows))
+			for i, row := range rows {
+				submissions[i] = row.Submission
+			}
+			if err := snapshotRankings(ctx, qtx, rejudgeID, rankedGameIDs(submissions), rankingAfter); err != nil {
+				return err
+			}
+			return qtx.FinishRejudge(ctx, rejudgeID)
+		})
+		if err != nil {
+			slog.Error("failed to finish rejudge", "error", err, "rejudgeID", rejudgeID)
+			continue
+		}
+		rows, err := q.ListRejudgeRankings(ctx, rejudgeID)
+		if err != nil {
+			slog.Error("failed to list rejudge rankings", "error", err, "rejudgeID", rejudgeID)
+			continue
+		}
+		slog.Info("rejudge finished", "rejudgeID", rejudgeID, "rankingChanges", len(rankingChanges(rows)))
+	}
+}
+
+// ListRejudges returns the rejudges, the latest first.
+func (s *Service) ListRejudges(ctx context.Context) ([]Rejudge, error) {
+	rows, err := s.q.ListRejudges(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	rejudges := make([]Rejudge, len(rows))
+	for i, row := range rows {
+		rejudges[i] = toRejudge(row)
+	}
+	return rejudges, nil
+}
+
+// GetRejudge returns the rejudge with its submissions and the changes to the
+// rankings it caused. The changes are empty until the rejudge finishes.
+func (s *Service) GetRejudge(ctx context.Context, rejudgeID int) (Rejudge, []RejudgedSubmission, []RankingChange, error) {
+	row, err := s.q.GetRejudgeByID(ctx, int32(rejudgeID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Rejudge{}, nil, nil, ErrNotFound
+		}
+		return Rejudge{}, nil, nil, err
+	}
+	subRows, err := s.q.ListRejudgeSubmissions(ctx, row.RejudgeID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Rejudge{}, nil, nil, err
+	}
+	submissions := make([]RejudgedSubmission, len(subRows))
+	for i, r := range subRows {
+		submissions[i] = RejudgedSubmission{
+			SubmissionID: int(r.Submission.SubmissionID),
+			GameID:       int(r.Submission.GameID),
+			TeamID:       int(r.Submission.TeamID),
+			IsPractice:   r.Submission.IsPractice,
+			StatusBefore: r.StatusBefore,
+			Status:       r.Submission.Status,
+		}
+	}
+	var changes []RankingChange
+	if row.FinishedAt.Valid {
+		rankingRows, err := s.q.ListRejudgeRankings(ctx, row.RejudgeID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return Rejudge{}, nil, nil, err
+		}
+		changes = rankingChanges(rankingRows)
+	}
+	return toRejudge(row), submissions, changes, nil
+}
+
+func toRejudge(row db.Rejudge) Rejudge {
+	r := Rejudge{
+		RejudgeID: int(row.RejudgeID),
+		ProblemID: int(row.ProblemID),
+		Reason:    row.Reason,
+		UserID:    intPtr(row.UserID),
+		CreatedAt: row.CreatedAt.Time,
+	}
+	if row.FinishedAt.Valid {
+		r.FinishedAt = &row.FinishedAt.Time
+	}
+	return r
+}
+
+// rankingChanges compares the rankings recorded before and after a rejudge,
+// which are sorted by game and team, and returns the teams whose rank or score
+// differs.
+func rankingChanges(rows []db.ListRejudgeRankingsRow) []RankingChange {
+	var changes []RankingChange
+	for i := 0; i < len(rows); {
+		c := RankingChange{
+			GameID:   int(rows[i].GameID),
+			TeamID:   int(rows[i].TeamID),
+			TeamName: rows[i].TeamName,
+		}
+		for ; i < len(rows) && int(rows[i].GameID) == c.GameID && int(rows[i].TeamID) == c.TeamID; i++ {
+			rank, score := int(rows[i].Rank), int(rows[i].Score)
+			switch rows[i].Phase {
+			case rankingBefore:
+				c.RankBefore, c.ScoreBefore = &rank, &score
+			case rankingAfter:
+				c.RankAfter, c.ScoreAfter = &rank, &score
+			}
+		}
+		if !equalIntPtr(c.RankBefore, c.RankAfter) || !equalIntPtr(c.ScoreBefore, c.ScoreAfter) {
+			changes = append(changes, c)
+		}
+	}
+	return changes
+}
+
+func equalIntPtr(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
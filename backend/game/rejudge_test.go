@@ -0,0 +1,46 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+
+	"albatross-2026-backend/db"
+)
+
+func TestRankingChanges(t *testing.T) {
+	ptr := func(v int) *int { return &v }
+	rows := []db.ListRejudgeRankingsRow{
+		// Unchanged.
+		{GameID: 1, TeamID: 1, TeamName: "a", Phase: rankingBefore, Rank: 1, Score: 10},
+		{GameID: 1, TeamID: 1, TeamName: "a", Phase: rankingAfter, Rank: 1, Score: 10},
+		// Lost its best submission.
+		{GameID: 1, TeamID: 2, TeamName: "b", Phase: rankingAfter, Rank: 2, Score: 30},
+		{GameID: 1, TeamID: 2, TeamName: "b", Phase: rankingBefore, Rank: 2, Score: 20},
+		// No longer ranked.
+		{GameID: 1, TeamID: 3, TeamName: "c", Phase: rankingBefore, Rank: 3, Score: 25},
+		// Same team ID in another game, ranked only after.
+		{GameID: 2, TeamID: 4, TeamName: "d", Phase: rankingAfter, Rank: 1, Score: 5},
+	}
+	want := []RankingChange{
+		{GameID: 1, TeamID: 2, TeamName: "b", RankBefore: ptr(2), ScoreBefore: ptr(20), RankAfter: ptr(2), ScoreAfter: ptr(30)},
+		{GameID: 1, TeamID: 3, TeamName: "c", RankBefore: ptr(3), ScoreBefore: ptr(25)},
+		{GameID: 2, TeamID: 4, TeamName: "d", RankAfter: ptr(1), ScoreAfter: ptr(5)},
+	}
+	if got := rankingChanges(rows); !reflect.DeepEqual(got, want) {
+		t.Errorf("rankingChanges() = %+v, want %+v", got, want)
+	}
+}
+
+func TestRankedGameIDs(t *testing.T) {
+	submissions := []db.Submission{
+		{SubmissionID: 1, GameID: 2},
+		{SubmissionID: 2, GameID: 3, IsPractice: true},
+		{SubmissionID: 3, GameID: 2},
+		{SubmissionID: 4, GameID: 1},
+	}
+	got := rankedGameIDs(submissions)
+	want := []int32{2, 1}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("rankedGameIDs() = %v, want %v", got, want)
+	}
+}
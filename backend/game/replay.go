@@ -0,0 +1,132 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/codediff"
+	"albatross-2026-backend/db"
+)
+
+// CodeSnapshot is a version of the code of a team for a problem, saved or
+// submitted by one of its members.
+type CodeSnapshot struct {
+	UserID       int
+	Code         string
+	IsSubmission bool
+	CreatedAt    time.Time
+}
+
+// recordCodeSnapshot appends the code to the history of the team. It must be
+// called in the transaction that updates the code, before the update, as each
+// snapshot is stored as the change from the code in the game state. Saves that
+// do not change the code are not recorded.
+func recordCodeSnapshot(ctx context.Context, qtx db.Querier, gameID int, teamID, userID int32, problemID int, code string, isSubmission bool) error {
+	prev, err := qtx.GetCodeForSnapshot(ctx, db.GetCodeForSnapshotParams{
+		GameID:    int32(gameID),
+		TeamID:    teamID,
+		ProblemID: int32(problemID),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	// States saved before the history was kept have no snapshots to build on,
+	// so their history starts over from the empty code.
+	var base string
+	if prev.HasSnapshots {
+		base = prev.Code
+		if base == code && !isSubmission {
+			return nil
+		}
+	}
+	patch := codediff.Diff(base, code)
+	return qtx.CreateCodeSnapshot(ctx, db.CreateCodeSnapshotParams{
+		GameID:       int32(gameID),
+		TeamID:       teamID,
+		UserID:       userID,
+		ProblemID:    int32(problemID),
+		IsSubmission: isSubmission,
+		KeepPrefix:   int32(patch.Prefix),
+		KeepSuffix:   int32(patch.Suffix),
+		Inserted:     patch.Insert,
+	})
+}
+
+// GetReplay returns the history of the code of the team of the player for a
+// problem of the game, oldest first. Until the game finishes, non-admins can
+// only replay the main players, and the main players themselves cannot, as in
+// GetWatchLatestStates. During the freeze, non-admins only get the history up
+// to the cutoff; see RankingCutoff.
+func (s *Service) GetReplay(ctx context.Context, gameID, problemID int, playerID int32, userID *int32, isAdmin bool) ([]CodeSnapshot, error) {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	if !isAdmin && !IsGameFinished(LifecycleFromGame(gameRow)) {
+		mainPlayerRows, err := s.q.ListMainPlayers(ctx, []int32{int32(gameID)})
+		if err != nil {
+			return nil, err
+		}
+		isMainPlayer := func(id int32) bool {
+			return slices.ContainsFunc(mainPlayerRows, func(row db.ListMainPlayersRow) bool {
+				return row.UserID == id
+			})
+		}
+		if !isMainPlayer(playerID) || (userID != nil && isMainPlayer(*userID)) {
+			return nil, ErrForbidden
+		}
+	}
+
+	team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
+		GameID: int32(gameID),
+		UserID: playerID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []CodeSnapshot{}, nil
+		}
+		return nil, err
+	}
+	rows, err := s.q.ListCodeSnapshots(ctx, db.ListCodeSnapshotsParams{
+		GameID:    int32(gameID),
+		TeamID:    team.TeamID,
+		ProblemID: int32(problemID),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	if !isAdmin {
+		if cutoff, frozen := RankingCutoff(gameRow, time.Now()); frozen {
+			rows = slices.DeleteFunc(rows, func(row db.CodeSnapshot) bool {
+				return row.CreatedAt.Time.After(cutoff)
+			})
+		}
+	}
+	snapshots := make([]CodeSnapshot, len(rows))
+	var code string
+	for i, row := range rows {
+		patch := codediff.Patch{
+			Prefix: int(row.KeepPrefix),
+			Suffix: int(row.KeepSuffix),
+			Insert: row.Inserted,
+		}
+		code, err = patch.Apply(code)
+		if err != nil {
+			return nil, err
+		}
+		snapshots[i] = CodeSnapshot{
+			UserID:       int(row.UserID),
+			Code:         code,
+			IsSubmission: row.IsSubmission,
+			CreatedAt:    row.CreatedAt.Time,
+		}
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,62 @@
+package game
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+)
+
+// replayQuerier returns the history of a main player of a game in its freeze,
+// saved before and after the freeze.
+type replayQuerier struct {
+	watchQuerier
+}
+
+func (m *replayQuerier) GetTeamByUserID(_ context.Context, arg db.GetTeamByUserIDParams) (db.GameTeam, error) {
+	return db.GameTeam{TeamID: arg.UserID, GameID: arg.GameID}, nil
+}
+
+func (m *replayQuerier) ListCodeSnapshots(_ context.Context, _ db.ListCodeSnapshotsParams) ([]db.CodeSnapshot, error) {
+	at := func(d time.Duration) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Now().Add(d), Valid: true}
+	}
+	return []db.CodeSnapshot{
+		{UserID: 1, Inserted: "a", CreatedAt: at(-6 * time.Minute)},
+		{UserID: 1, KeepPrefix: 1, Inserted: "b", CreatedAt: at(-2 * time.Minute)},
+	}, nil
+}
+
+func TestGetReplay_Frozen(t *testing.T) {
+	// The game ends in a minute, within the freeze of 5 minutes.
+	q := &replayQuerier{watchQuerier{
+		game: db.Game{
+			GameID:          1,
+			GameType:        "1v1",
+			StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-9 * time.Minute), Valid: true},
+			DurationSeconds: 600,
+			FreezeSeconds:   300,
+		},
+		mainPlayers: []int32{1, 2},
+	}}
+	s := &Service{q: q}
+
+	got, err := s.GetReplay(context.Background(), 1, 1, 1, nil, false)
+	if err != nil {
+		t.Fatalf("GetReplay: %v", err)
+	}
+	if len(got) != 1 || got[0].Code != "a" {
+		t.Errorf("expected the history up to the freeze, got %+v", got)
+	}
+
+	got, err = s.GetReplay(context.Background(), 1, 1, 1, nil, true)
+	if err != nil {
+		t.Fatalf("GetReplay: %v", err)
+	}
+	if len(got) != 2 || got[1].Code != "ab" {
+		t.Errorf("expected the whole history for admins, got %+v", got)
+	}
+}
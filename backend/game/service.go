@@ -3,17 +3,24 @@ package game
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgtype"
 
 	"albatross-2026-backend/db"
+	"albatross-2026-backend/ranking"
+	"albatross-2026-backend/scoring"
 )
 
 type HubInterface interface {
-	CalcCodeSize(code string, language string) int
-	EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID int, language, code string) error
+	EnqueueTestTasks(ctx context.Context, submissionID, gameID, userID, problemID int, language, code string) error
+	EnqueueValidationTasks(ctx context.Context, validationID int, problem db.Problem, solutions []db.ReferenceSolution, testcases []db.Testcase) error
+	PublishEvent(event Event)
+	SubscribeEvents(gameID int) (<-chan Event, func())
+	RunCode(ctx context.Context, gameID, userID int, language, code, stdin string, timeLimitMs, memoryLimitMiB int) (RunResult, error)
 }
 
 type Service struct {
@@ -35,6 +42,10 @@ type Player struct {
 	IconPath    *string
 	IsAdmin     bool
 	Label       *string
+	Rating      int
+	// RatingRank is the place of the player among the rated players, or nil
+	// if they have not played a rated game.
+	RatingRank *int
 }
 
 type ProblemDetail struct {
@@ -43,6 +54,9 @@ type ProblemDetail struct {
 	Description string
 	Language    string
 	SampleCode  string
+	Scoring     string
+	// Languages are those players can choose, Language first.
+	Languages []ProblemLanguage
 }
 
 type Detail struct {
@@ -52,11 +66,19 @@ type Detail struct {
 	DisplayName     string
 	DurationSeconds int
 	StartedAt       *time.Time
-	Problem         ProblemDetail
-	MainPlayers     []Player
+	State           State
+	PausedAt        *time.Time
+	// AllowPractice is set if the game accepts practice submissions once it
+	// finishes.
+	AllowPractice bool
+	// Problems are in the order the players are expected to solve them.
+	Problems    []ProblemDetail
+	MainPlayers []Player
 }
 
 type LatestState struct {
+	// Language is that of Code. It is empty if no code has been saved.
+	Language             string
 	Code                 string
 	Score                *int
 	BestScoreSubmittedAt *int64
@@ -64,37 +86,87 @@ type LatestState struct {
 }
 
 type RankingEntry struct {
-	Player      Player
-	Score       int
-	SubmittedAt int64
-	Code        *string
+	Rank int
+	Team Team
+	// Score is the total of ProblemScores, with the penalty of the game for
+	// each unsolved problem.
+	Score           int
+	ProblemScores   []ProblemScore
+	SubmissionCount int
+	SubmittedAt     int64
+	// Code is the best submission after the game, only for single-problem
+	// games.
+	Code *string
+}
+
+// ProblemScore is the best score of a team for a problem of the game.
+type ProblemScore struct {
+	ProblemID int
+	// Score is nil if the team has not solved the problem.
+	Score *int
+}
+
+// Ranking is a page of the ranking of a game.
+type Ranking struct {
+	Entries  []RankingEntry
+	Finished bool
+	// Frozen is set if the entries are as of the freeze rather than live.
+	Frozen   bool
+	TieBreak string
+	// NextCursor points to the next page. It is empty on the last page.
+	NextCursor string
 }
 
 type SubmissionDetail struct {
 	SubmissionID int
 	GameID       int
+	ProblemID    int
+	Language     string
 	Code         string
 	CodeSize     int
 	Status       string
-	CreatedAt    int64
+	// IsPractice is set for submissions made after the game finished, which
+	// are ranked apart from the official results.
+	IsPractice bool
+	CreatedAt  int64
+}
+
+// TestcaseVerdict is the result of a submission for a testcase, as shown to
+// the submitter. Hidden testcases only tell whether they passed.
+type TestcaseVerdict struct {
+	TestcaseID int
+	IsSample   bool
+	Passed     bool
+	Sample     *SampleVerdict
+}
+
+// SampleVerdict is the detail of a result for a public sample testcase.
+type SampleVerdict struct {
+	Status         string
+	Stdin          string
+	ExpectedStdout string
+	Stdout         string
+	Stderr         string
 }
 
 // Helper functions
 
-func IsGameRunning(startedAt pgtype.Timestamp, durationSeconds int32) bool {
-	if !startedAt.Valid {
-		return false
-	}
-	endTime := startedAt.Time.Add(time.Duration(durationSeconds) * time.Second)
-	return time.Now().Before(endTime)
+func IsGameRunning(l Lifecycle) bool {
+	return l.StateAt(time.Now()) == StateRunning
+}
+
+// IsGameFinished reports whether the game has ended, either by time or by an
+// admin. Cancelled games are not finished.
+func IsGameFinished(l Lifecycle) bool {
+	return l.StateAt(time.Now()) == StateFinished
 }
 
-func IsGameFinished(startedAt pgtype.Timestamp, durationSeconds int32) bool {
-	if !startedAt.Valid {
-		return false
+func timePtr(ts pgtype.Timestamp) *time.Time {
+	if !ts.Valid {
+		return nil
 	}
-	endTime := startedAt.Time.Add(time.Duration(durationSeconds) * time.Second)
-	return !time.Now().Before(endTime)
+	t := ts.Time
+	return &t
 }
 
 func playerFromMainPlayerRow(row db.ListMainPlayersRow) Player {
@@ -105,52 +177,58 @@ func playerFromMainPlayerRow(row db.ListMainPlayersRow) Player {
 		IconPath:    row.IconPath,
 		IsAdmin:     row.IsAdmin,
 		Label:       row.Label,
+		Rating:      int(row.Rating),
+		RatingRank:  intPtr(row.RatingRank),
 	}
 }
 
-func gameDetailFromPublicRow(row db.ListPublicGamesRow) Detail {
-	var startedAt *time.Time
-	if row.StartedAt.Valid {
-		t := row.StartedAt.Time
-		startedAt = &t
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
 	}
-	return Detail{
-		GameID:          int(row.GameID),
-		GameType:        row.GameType,
-		IsPublic:        row.IsPublic,
-		DisplayName:     row.DisplayName,
-		DurationSeconds: int(row.DurationSeconds),
-		StartedAt:       startedAt,
-		Problem: ProblemDetail{
-			ProblemID:   int(row.ProblemID),
-			Title:       row.Title,
-			Description: row.Description,
-			Language:    row.Language,
-			SampleCode:  row.SampleCode,
-		},
+	i := int(*v)
+	return &i
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
 	}
+	i := int32(*v)
+	return &i
 }
 
-func gameDetailFromGetRow(row db.GetGameByIDRow) Detail {
-	var startedAt *time.Time
-	if row.StartedAt.Valid {
-		t := row.StartedAt.Time
-		startedAt = &t
+func problemDetailFromRow(row db.Problem, others []db.ProblemLanguage) ProblemDetail {
+	return ProblemDetail{
+		ProblemID:   int(row.ProblemID),
+		Title:       row.Title,
+		Description: row.Description,
+		Language:    row.Language,
+		SampleCode:  row.SampleCode,
+		Scoring:     row.Scoring,
+		Languages:   problemLanguages(row, others),
 	}
+}
+
+func gameProblemIDs(rows []db.ListGameProblemsRow) []int32 {
+	problemIDs := make([]int32, len(rows))
+	for i, row := range rows {
+		problemIDs[i] = row.Problem.ProblemID
+	}
+	return problemIDs
+}
+
+func gameDetailFromRow(row db.Game) Detail {
 	return Detail{
 		GameID:          int(row.GameID),
 		GameType:        row.GameType,
 		IsPublic:        row.IsPublic,
 		DisplayName:     row.DisplayName,
 		DurationSeconds: int(row.DurationSeconds),
-		StartedAt:       startedAt,
-		Problem: ProblemDetail{
-			ProblemID:   int(row.ProblemID),
-			Title:       row.Title,
-			Description: row.Description,
-			Language:    row.Language,
-			SampleCode:  row.SampleCode,
-		},
+		StartedAt:       timePtr(row.StartedAt),
+		State:           LifecycleFromGame(row).StateAt(time.Now()),
+		PausedAt:        timePtr(row.PausedAt),
+		AllowPractice:   row.AllowPractice,
 	}
 }
 
@@ -165,10 +243,22 @@ func (s *Service) ListPublicGames(ctx context.Context) ([]Detail, error) {
 	gameIDs := make([]int32, len(gameRows))
 	gameID2Index := make(map[int32]int, len(gameRows))
 	for i, row := range gameRows {
-		games[i] = gameDetailFromPublicRow(row)
+		games[i] = gameDetailFromRow(row)
 		gameIDs[i] = row.GameID
 		gameID2Index[row.GameID] = i
 	}
+	problemRows, err := s.q.ListGameProblems(ctx, gameIDs)
+	if err != nil {
+		return nil, err
+	}
+	languages, err := problemLanguagesByID(ctx, s.q, gameProblemIDs(problemRows))
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range problemRows {
+		idx := gameID2Index[row.GameID]
+		games[idx].Problems = append(games[idx].Problems, problemDetailFromRow(row.Problem, languages[row.Problem.ProblemID]))
+	}
 	mainPlayerRows, err := s.q.ListMainPlayers(ctx, gameIDs)
 	if err != nil {
 		return nil, err
@@ -191,7 +281,18 @@ func (s *Service) GetGameByID(ctx context.Context, gameID int, isAdmin bool) (De
 	if !row.IsPublic && !isAdmin {
 		return Detail{}, ErrNotFound
 	}
-	game := gameDetailFromGetRow(row)
+	game := gameDetailFromRow(row)
+	problemRows, err := s.q.ListGameProblems(ctx, []int32{int32(gameID)})
+	if err != nil {
+		return Detail{}, err
+	}
+	languages, err := problemLanguagesByID(ctx, s.q, gameProblemIDs(problemRows))
+	if err != nil {
+		return Detail{}, err
+	}
+	for _, problemRow := range problemRows {
+		game.Problems = append(game.Problems, problemDetailFromRow(problemRow.Problem, languages[problemRow.Problem.ProblemID]))
+	}
 	mainPlayerRows, err := s.q.ListMainPlayers(ctx, []int32{int32(gameID)})
 	if err != nil {
 		return Detail{}, err
@@ -202,57 +303,158 @@ func (s *Service) GetGameByID(ctx context.Context, gameID int, isAdmin bool) (De
 	return game, nil
 }
 
-func (s *Service) SaveCode(ctx context.Context, gameID int, userID int32, code string) error {
+// runningGameProblem returns the problem of the game for a player to work on.
+// It fails with ErrNotFound if the problem is not one of the game.
+func (s *Service) runningGameProblem(ctx context.Context, gameID, problemID int) (db.Problem, error) {
+	problem, practice, err := s.playableGameProblem(ctx, gameID, problemID)
+	if err != nil {
+		return db.Problem{}, err
+	}
+	if practice {
+		return db.Problem{}, ErrGameNotRunning
+	}
+	return problem, nil
+}
+
+// playableGameProblem is like runningGameProblem, but also accepts finished
+// games that allow practice, telling so with practice.
+func (s *Service) playableGameProblem(ctx context.Context, gameID, problemID int) (problem db.Problem, practice bool, err error) {
 	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrNotFound
+			return db.Problem{}, false, ErrNotFound
 		}
+		return db.Problem{}, false, err
+	}
+	problem, err = s.q.GetGameProblem(ctx, db.GetGameProblemParams{
+		GameID:    int32(gameID),
+		ProblemID: int32(problemID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return db.Problem{}, false, ErrNotFound
+		}
+		return db.Problem{}, false, err
+	}
+	lifecycle := LifecycleFromGame(gameRow)
+	if IsGameRunning(lifecycle) {
+		return problem, false, nil
+	}
+	if gameRow.AllowPractice && IsGameFinished(lifecycle) {
+		return problem, true, nil
+	}
+	return db.Problem{}, false, ErrGameNotRunning
+}
+
+// SaveCode saves the code of the team of the player. Any member of the team
+// can save the code. An empty language means that of the problem.
+func (s *Service) SaveCode(ctx context.Context, gameID int, userID int32, problemID int, language, code string) error {
+	problem, err := s.runningGameProblem(ctx, gameID, problemID)
+	if err != nil {
 		return err
 	}
-	if !IsGameRunning(gameRow.StartedAt, gameRow.DurationSeconds) {
-		return ErrGameNotRunning
+	language, err = resolveLanguage(ctx, s.q, problem, language)
+	if err != nil {
+		return err
 	}
-	return s.q.UpdateCode(ctx, db.UpdateCodeParams{
-		GameID: int32(gameID),
-		UserID: userID,
-		Code:   code,
-		Status: "none",
+	teamID, err := s.playerTeamID(ctx, gameID, userID)
+	if err != nil {
+		return err
+	}
+	err = s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		if err := recordCodeSnapshot(ctx, qtx, gameID, teamID, userID, problemID, code, false); err != nil {
+			return err
+		}
+		return qtx.UpdateCode(ctx, db.UpdateCodeParams{
+			GameID:    int32(gameID),
+			TeamID:    teamID,
+			ProblemID: int32(problemID),
+			Language:  language,
+			Code:      code,
+			Status:    "none",
+		})
+	})
+	if err != nil {
+		return err
+	}
+	s.hub.PublishEvent(Event{
+		Type:      EventTypeCode,
+		GameID:    gameID,
+		UserID:    int(userID),
+		TeamID:    int(teamID),
+		ProblemID: problemID,
+		Language:  language,
+		Code:      code,
 	})
+	return nil
 }
 
-func (s *Service) SubmitCode(ctx context.Context, gameID int, userID int32, code string) error {
-	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+// SubmitCode submits the code on behalf of the team of the player. Once the
+// game finishes, games that allow practice take practice submissions, which
+// leave the game state and the official ranking as they are. An empty
+// language means that of the problem.
+func (s *Service) SubmitCode(ctx context.Context, gameID int, userID int32, problemID int, language, code string) error {
+	problem, practice, err := s.playableGameProblem(ctx, gameID, problemID)
+	if err != nil {
+		return err
+	}
+	language, err = resolveLanguage(ctx, s.q, problem, language)
 	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return ErrNotFound
-		}
 		return err
 	}
 
-	language := gameRow.Language
-	codeSize := s.hub.CalcCodeSize(code, language)
+	strategy, err := scoring.New(problem.Scoring)
+	if err != nil {
+		return err
+	}
+	codeSize := strategy.Score(code, language)
+
+	teamID, err := s.playerTeamID(ctx, gameID, userID)
+	if err != nil {
+		return err
+	}
 
-	if !IsGameRunning(gameRow.StartedAt, gameRow.DurationSeconds) {
-		return ErrGameNotRunning
+	if practice {
+		submissionID, err := s.q.CreateSubmission(ctx, db.CreateSubmissionParams{
+			GameID:     int32(gameID),
+			TeamID:     teamID,
+			UserID:     userID,
+			ProblemID:  int32(problemID),
+			Language:   language,
+			Code:       code,
+			CodeSize:   int32(codeSize),
+			IsPractice: true,
+		})
+		if err != nil {
+			return err
+		}
+		return s.hub.EnqueueTestTasks(ctx, int(submissionID), gameID, int(userID), problemID, language, code)
 	}
 
 	var submissionID int32
 	err = s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		if err := recordCodeSnapshot(ctx, qtx, gameID, teamID, userID, problemID, code, true); err != nil {
+			return err
+		}
 		if err := qtx.UpdateCodeAndStatus(ctx, db.UpdateCodeAndStatusParams{
-			GameID: int32(gameID),
-			UserID: userID,
-			Code:   code,
-			Status: "running",
+			GameID:    int32(gameID),
+			TeamID:    teamID,
+			ProblemID: int32(problemID),
+			Language:  language,
+			Code:      code,
+			Status:    "running",
 		}); err != nil {
 			return err
 		}
 		var err error
 		submissionID, err = qtx.CreateSubmission(ctx, db.CreateSubmissionParams{
-			GameID:   int32(gameID),
-			UserID:   userID,
-			Code:     code,
-			CodeSize: int32(codeSize),
+			GameID:    int32(gameID),
+			TeamID:    teamID,
+			UserID:    userID,
+			ProblemID: int32(problemID),
+			Language:  language,
+			Code:      code,
+			CodeSize:  int32(codeSize),
 		})
 		return err
 	})
@@ -260,11 +462,47 @@ func (s *Service) SubmitCode(ctx context.Context, gameID int, userID int32, code
 		return err
 	}
 
-	return s.hub.EnqueueTestTasks(ctx, int(submissionID), gameID, int(userID), language, code)
+	s.hub.PublishEvent(Event{
+		Type:      EventTypeCode,
+		GameID:    gameID,
+		UserID:    int(userID),
+		TeamID:    int(teamID),
+		ProblemID: problemID,
+		Language:  language,
+		Code:      code,
+	})
+	s.hub.PublishEvent(Event{
+		Type:      EventTypeStatus,
+		GameID:    gameID,
+		UserID:    int(userID),
+		TeamID:    int(teamID),
+		ProblemID: problemID,
+		Status:    "running",
+	})
+
+	return s.hub.EnqueueTestTasks(ctx, int(submissionID), gameID, int(userID), problemID, language, code)
 }
 
-func (s *Service) GetLatestState(ctx context.Context, gameID int, userID int32) (LatestState, error) {
-	row, err := s.q.GetLatestState(ctx, db.GetLatestStateParams{
+// RunCode runs code with custom stdin for a player, within the limits of the
+// problem. Unlike SubmitCode, it does not touch the game state or the
+// submissions.
+func (s *Service) RunCode(ctx context.Context, gameID int, userID int32, problemID int, language, code, stdin string) (RunResult, error) {
+	problem, _, err := s.playableGameProblem(ctx, gameID, problemID)
+	if err != nil {
+		return RunResult{}, err
+	}
+	language, err = resolveLanguage(ctx, s.q, problem, language)
+	if err != nil {
+		return RunResult{}, err
+	}
+	timeLimitMs, memoryLimitMiB := testcaseLimits(problem, db.Testcase{})
+	return s.hub.RunCode(ctx, gameID, int(userID), language, code, stdin, timeLimitMs, memoryLimitMiB)
+}
+
+// GetLatestState returns the state of the team of the player for a problem of
+// the game.
+func (s *Service) GetLatestState(ctx context.Context, gameID int, userID int32, problemID int) (LatestState, error) {
+	team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
 		GameID: int32(gameID),
 		UserID: userID,
 	})
@@ -274,6 +512,17 @@ func (s *Service) GetLatestState(ctx context.Context, gameID int, userID int32)
 		}
 		return LatestState{}, err
 	}
+	row, err := s.q.GetLatestState(ctx, db.GetLatestStateParams{
+		GameID:    int32(gameID),
+		TeamID:    team.TeamID,
+		ProblemID: int32(problemID),
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return LatestState{Status: "none"}, nil
+		}
+		return LatestState{}, err
+	}
 	var score *int
 	if row.CodeSize != nil {
 		s := int(*row.CodeSize)
@@ -285,6 +534,7 @@ func (s *Service) GetLatestState(ctx context.Context, gameID int, userID int32)
 		submittedAt = &ts
 	}
 	return LatestState{
+		Language:             row.Language,
 		Code:                 row.Code,
 		Score:                score,
 		BestScoreSubmittedAt: submittedAt,
@@ -292,14 +542,22 @@ func (s *Service) GetLatestState(ctx context.Context, gameID int, userID int32)
 	}, nil
 }
 
-func (s *Service) GetWatchLatestStates(ctx context.Context, gameID int, userID *int32, isAdmin bool) (map[int]LatestState, error) {
-	rows, err := s.q.GetLatestStatesOfMainPlayers(ctx, int32(gameID))
+// GetWatchLatestStates returns the states of the main players for a problem
+// of the game.
+func (s *Service) GetWatchLatestStates(ctx context.Context, gameID, problemID int, userID *int32, isAdmin bool) (map[int]LatestState, error) {
+	rows, err := s.q.GetLatestStatesOfMainPlayers(ctx, db.GetLatestStatesOfMainPlayersParams{
+		GameID:    int32(gameID),
+		ProblemID: int32(problemID),
+	})
 	if err != nil {
 		return nil, err
 	}
 	states := make(map[int]LatestState, len(rows))
 	for _, row := range rows {
-		var code string
+		var language, code string
+		if row.Language != nil {
+			language = *row.Language
+		}
 		if row.Code != nil {
 			code = *row.Code
 		}
@@ -325,106 +583,789 @@ func (s *Service) GetWatchLatestStates(ctx context.Context, gameID int, userID *
 		}
 
 		states[int(row.UserID)] = LatestState{
+			Language:             language,
 			Code:                 code,
 			Score:                score,
 			BestScoreSubmittedAt: submittedAt,
 			Status:               status,
 		}
 	}
+	if !isAdmin {
+		if err := s.freezeLatestStates(ctx, gameID, problemID, states); err != nil {
+			return nil, err
+		}
+	}
 	return states, nil
 }
 
-func (s *Service) GetRanking(ctx context.Context, gameID int) ([]RankingEntry, bool, error) {
+// freezeLatestStates replaces the scores and the code in states with those of
+// the best submissions at the freeze during the freeze, as the size of the
+// live code is its score. The statuses are hidden, as they would tell the
+// results of the submissions made after it.
+func (s *Service) freezeLatestStates(ctx context.Context, gameID, problemID int, states map[int]LatestState) error {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+	cutoff, frozen := RankingCutoff(gameRow, time.Now())
+	if !frozen {
+		return nil
+	}
+	bestRows, err := s.q.ListBestSubmissionsAt(ctx, db.ListBestSubmissionsAtParams{
+		GameID:    int32(gameID),
+		CreatedAt: pgtype.Timestamp{Time: cutoff, Valid: true},
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	bests := make(map[int32]db.Submission, len(bestRows))
+	for _, row := range bestRows {
+		if int(row.Submission.ProblemID) == problemID {
+			bests[row.GameTeam.TeamID] = row.Submission
+		}
+	}
+	members, err := teamMembers(ctx, s.q, int32(gameID))
+	if err != nil {
+		return err
+	}
+	userTeams := make(map[int]int32)
+	for teamID, users := range members {
+		for _, u := range users {
+			userTeams[int(u.UserID)] = teamID
+		}
+	}
+	for userID, state := range states {
+		state.Language = ""
+		state.Code = ""
+		state.Score = nil
+		state.BestScoreSubmittedAt = nil
+		state.Status = "none"
+		if best, ok := bests[userTeams[userID]]; ok {
+			score := int(best.CodeSize)
+			submittedAt := best.CreatedAt.Time.Unix()
+			state.Language = best.Language
+			state.Code = best.Code
+			state.Score = &score
+			state.BestScoreSubmittedAt = &submittedAt
+		}
+		states[userID] = state
+	}
+	return nil
+}
+
+// SubscribePlayEvents subscribes to the events of the team of the player and
+// those of the game itself.
+func (s *Service) SubscribePlayEvents(ctx context.Context, gameID int, userID int32) (<-chan Event, func(), error) {
+	if _, err := s.q.GetGameByID(ctx, int32(gameID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	var teamID int
+	team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
+		GameID: int32(gameID),
+		UserID: userID,
+	})
+	if err == nil {
+		teamID = int(team.TeamID)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, err
+	}
+	events, unsubscribe := s.hub.SubscribeEvents(gameID)
+	return teamEvents(events, teamID, int(userID)), unsubscribe, nil
+}
+
+// teamEvents picks the events of the team from events. If teamID is zero, the
+// player has no team yet and the team is learned from the first event caused
+// by the player, as it comes from the team of their own created on demand. The
+// returned channel is closed when events is closed.
+func teamEvents(events <-chan Event, teamID, userID int) <-chan Event {
+	filtered := make(chan Event, eventBufferSize)
+	go func() {
+		defer close(filtered)
+		for event := range events {
+			if event.Type != EventTypeGame {
+				if teamID == 0 && event.UserID == userID {
+					teamID = event.TeamID
+				}
+				if event.TeamID != teamID {
+					continue
+				}
+			}
+			select {
+			case filtered <- event:
+			default:
+			}
+		}
+	}()
+	return filtered
+}
+
+// SubscribeWatchEvents subscribes to the events of the game for spectators.
+// Like GetWatchLatestStates, main players cannot watch their own game unless
+// they are admins. Non-admins do not receive the events that would reveal
+// the frozen ranking or the code of players they cannot watch.
+func (s *Service) SubscribeWatchEvents(ctx context.Context, gameID int, userID *int32, isAdmin bool) (<-chan Event, func(), error) {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, err
+	}
+	if isAdmin {
+		events, unsubscribe := s.hub.SubscribeEvents(gameID)
+		return events, unsubscribe, nil
+	}
+	mainPlayers, err := mainPlayerIDs(ctx, s.q, gameRow.GameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if userID != nil && mainPlayers[int(*userID)] {
+		return nil, nil, ErrForbidden
+	}
+	events, unsubscribe := s.hub.SubscribeEvents(gameID)
+	return s.spectatorEvents(ctx, gameRow, mainPlayers, events), unsubscribe, nil
+}
+
+func mainPlayerIDs(ctx context.Context, q db.Querier, gameID int32) (map[int]bool, error) {
+	rows, err := q.ListMainPlayers(ctx, []int32{gameID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	ids := make(map[int]bool, len(rows))
+	for _, row := range rows {
+		ids[int(row.UserID)] = true
+	}
+	return ids, nil
+}
+
+// spectatorEvents drops the events that non-admins may not see. Like
+// GetWatchLatestStates, only the code of the main players is shown, and none
+// in multiplayer games, where spectators may be competitors too. During the
+// freeze, the code and the results are dropped, as they would reveal the
+// ranking. The game and its main players are fetched again on EventTypeGame,
+// as they may have changed. The returned channel is closed when events is
+// closed.
+func (s *Service) spectatorEvents(ctx context.Context, gameRow db.Game, mainPlayers map[int]bool, events <-chan Event) <-chan Event {
+	filtered := make(chan Event, eventBufferSize)
+	go func() {
+		defer close(filtered)
+		for event := range events {
+			switch event.Type {
+			case EventTypeGame:
+				if row, err := s.q.GetGameByID(ctx, gameRow.GameID); err == nil {
+					gameRow = row
+				}
+				if ids, err := mainPlayerIDs(ctx, s.q, gameRow.GameID); err == nil {
+					mainPlayers = ids
+				}
+			case EventTypeCode:
+				if gameRow.GameType == "multiplayer" || !mainPlayers[event.UserID] {
+					continue
+				}
+				if _, frozen := RankingCutoff(gameRow, time.Now()); frozen {
+					continue
+				}
+			case EventTypeStatus, EventTypeBestScore:
+				if _, frozen := RankingCutoff(gameRow, time.Now()); frozen {
+					continue
+				}
+			}
+			select {
+			case filtered <- event:
+			default:
+			}
+		}
+	}()
+	return filtered
+}
+
+const (
+	defaultRankingPageSize = 30
+	maxRankingPageSize     = 100
+)
+
+// GetRanking returns a page of the ranking of the game, starting after cursor.
+// An empty cursor returns the first page, and a non-positive limit means the
+// default page size. Non-admins get the frozen ranking during the freeze; see
+// RankingCutoff. If language is not empty, only the submissions in it are
+// ranked.
+func (s *Service) GetRanking(ctx context.Context, gameID int, isAdmin bool, language, cursor string, limit int) (Ranking, error) {
 	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, false, ErrNotFound
+			return Ranking{}, ErrNotFound
 		}
-		return nil, false, err
+		return Ranking{}, err
 	}
-	finished := IsGameFinished(gameRow.StartedAt, gameRow.DurationSeconds)
+	finished := IsGameFinished(LifecycleFromGame(gameRow))
+	cutoff, frozen := RankingCutoff(gameRow, time.Now())
+	frozen = frozen && !isAdmin
 
-	rows, err := s.q.GetRanking(ctx, int32(gameID))
+	var teams []RankedTeam
+	var ranks []int
+	if language == "" {
+		teams, ranks, err = RankedRows(ctx, s.q, gameRow, cutoff, frozen)
+	} else {
+		teams, ranks, err = rankedRowsInLanguage(ctx, s.q, gameRow, language, cutoff, frozen)
+	}
+	if err != nil {
+		return Ranking{}, err
+	}
+	page, err := rankingPage(gameRow, teams, ranks, cursor, limit, finished)
+	if err != nil {
+		return Ranking{}, err
+	}
+	page.Finished = finished
+	page.Frozen = frozen
+	return page, nil
+}
+
+// GetPracticeRanking returns a page of the ranking of the practice submissions
+// made after the game finished, ordered by the tie-break policy of the game
+// like the official one. If language is not empty, only the submissions in it
+// are ranked.
+func (s *Service) GetPracticeRanking(ctx context.Context, gameID int, language, cursor string, limit int) (Ranking, error) {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
-			return nil, finished, nil
+			return Ranking{}, ErrNotFound
 		}
-		return nil, false, err
+		return Ranking{}, err
+	}
+	rows, err := s.q.ListBestPracticeSubmissions(ctx, db.ListBestPracticeSubmissionsParams{
+		GameID:   gameRow.GameID,
+		Language: languageFilter(language),
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Ranking{}, err
 	}
-	ranking := make([]RankingEntry, len(rows))
+	rankingRows := make([]db.GetRankingRow, len(rows))
 	for i, row := range rows {
+		rankingRows[i] = db.GetRankingRow(row)
+	}
+	teams, ranks, err := rankTeams(ctx, s.q, gameRow, rankingRows)
+	if err != nil {
+		return Ranking{}, err
+	}
+	// Practice starts once the game finishes, when the code is revealed.
+	page, err := rankingPage(gameRow, teams, ranks, cursor, limit, true)
+	if err != nil {
+		return Ranking{}, err
+	}
+	page.Finished = IsGameFinished(LifecycleFromGame(gameRow))
+	return page, nil
+}
+
+// rankingPage returns the page of the ranking that starts after cursor. The
+// code of the best submissions is included if revealCode is set.
+func rankingPage(gameRow db.Game, teams []RankedTeam, ranks []int, cursor string, limit int, revealCode bool) (Ranking, error) {
+	policy, err := ranking.New(gameRow.TieBreak)
+	if err != nil {
+		return Ranking{}, err
+	}
+	start, err := ranking.Seek(teams, rankingKey, policy, cursor)
+	if err != nil {
+		return Ranking{}, err
+	}
+	if limit <= 0 {
+		limit = defaultRankingPageSize
+	}
+	end := min(start+min(limit, maxRankingPageSize), len(teams))
+
+	entries := make([]RankingEntry, 0, end-start)
+	for i := start; i < end; i++ {
+		t := teams[i]
+		problemScores := make([]ProblemScore, len(t.ProblemIDs))
+		for j, problemID := range t.ProblemIDs {
+			problemScores[j].ProblemID = int(problemID)
+			if best, ok := t.Bests[problemID]; ok {
+				score := int(best.CodeSize)
+				problemScores[j].Score = &score
+			}
+		}
 		var code *string
-		if finished {
-			code = &row.Submission.Code
-		}
-		ranking[i] = RankingEntry{
-			Player: Player{
-				UserID:      int(row.User.UserID),
-				Username:    row.User.Username,
-				DisplayName: row.User.DisplayName,
-				IconPath:    row.User.IconPath,
-				IsAdmin:     row.User.IsAdmin,
-				Label:       row.User.Label,
+		if revealCode && len(t.ProblemIDs) == 1 {
+			if best, ok := t.Bests[t.ProblemIDs[0]]; ok {
+				code = &best.Code
+			}
+		}
+		members := make([]Player, len(t.Members))
+		for j, u := range t.Members {
+			members[j] = PlayerFromUser(u)
+		}
+		entries = append(entries, RankingEntry{
+			Rank: ranks[i],
+			Team: Team{
+				TeamID:      int(t.Team.TeamID),
+				DisplayName: t.Team.DisplayName,
+				Members:     members,
 			},
-			Score:       int(row.Submission.CodeSize),
-			SubmittedAt: row.Submission.CreatedAt.Time.Unix(),
-			Code:        code,
+			Score:           t.Score,
+			ProblemScores:   problemScores,
+			SubmissionCount: t.SubmissionCount,
+			SubmittedAt:     t.SubmittedAt.Unix(),
+			Code:            code,
+		})
+	}
+	var nextCursor string
+	if end < len(teams) {
+		nextCursor = ranking.EncodeCursor(rankingKey(teams[end-1]))
+	}
+	return Ranking{
+		Entries:    entries,
+		TieBreak:   gameRow.TieBreak,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// RankedTeam is an entry of the ranking of a game, made of the best
+// submissions of a team to the problems of the game.
+type RankedTeam struct {
+	Team    db.GameTeam
+	Members []db.User
+	// ProblemIDs are the problems of the game in order.
+	ProblemIDs []int32
+	// Bests holds the best submission for each problem the team has solved.
+	Bests map[int32]db.Submission
+	// Score is the total size of Bests plus the penalty of the game for each
+	// unsolved problem.
+	Score int
+	// SubmissionCount is the number of submissions the team made up to the
+	// last of Bests.
+	SubmissionCount int
+	// SubmittedAt is the time of the last of Bests.
+	SubmittedAt time.Time
+}
+
+// RankedRows returns the whole ranking of the game sorted by its tie-break
+// policy, and the rank of each team. If frozen is set, it is the ranking as of
+// cutoff. Teams that have solved none of the problems are not ranked.
+func RankedRows(ctx context.Context, q db.Querier, gameRow db.Game, cutoff time.Time, frozen bool) ([]RankedTeam, []int, error) {
+	var rows []db.GetRankingRow
+	if frozen {
+		bestRows, err := q.ListBestSubmissionsAt(ctx, db.ListBestSubmissionsAtParams{
+			GameID:    gameRow.GameID,
+			CreatedAt: pgtype.Timestamp{Time: cutoff, Valid: true},
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, err
+		}
+		for _, row := range bestRows {
+			rows = append(rows, db.GetRankingRow(row))
+		}
+	} else {
+		var err error
+		rows, err = q.GetRanking(ctx, gameRow.GameID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, err
 		}
 	}
-	return ranking, finished, nil
+	return rankTeams(ctx, q, gameRow, rows)
 }
 
-// UpdateGameParams holds parameters for updating a game with its players.
-type UpdateGameParams struct {
-	GameID          int
+// rankedRowsInLanguage is like RankedRows, but only ranks the submissions in
+// the language, as code sizes in different languages do not compare well.
+func rankedRowsInLanguage(ctx context.Context, q db.Querier, gameRow db.Game, language string, cutoff time.Time, frozen bool) ([]RankedTeam, []int, error) {
+	if !frozen {
+		cutoff = time.Now()
+	}
+	bestRows, err := q.ListBestSubmissionsAt(ctx, db.ListBestSubmissionsAtParams{
+		GameID:    gameRow.GameID,
+		CreatedAt: pgtype.Timestamp{Time: cutoff, Valid: true},
+		Language:  &language,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil, err
+	}
+	rows := make([]db.GetRankingRow, len(bestRows))
+	for i, row := range bestRows {
+		rows[i] = db.GetRankingRow(row)
+	}
+	return rankTeams(ctx, q, gameRow, rows)
+}
+
+// languageFilter returns the language to filter submissions by, or nil for
+// all languages.
+func languageFilter(language string) *string {
+	if language == "" {
+		return nil
+	}
+	return &language
+}
+
+// rankTeams groups the best submissions by team and sorts the teams by the
+// tie-break policy of the game.
+func rankTeams(ctx context.Context, q db.Querier, gameRow db.Game, rows []db.GetRankingRow) ([]RankedTeam, []int, error) {
+	policy, err := ranking.New(gameRow.TieBreak)
+	if err != nil {
+		return nil, nil, err
+	}
+	problemRows, err := q.ListGameProblems(ctx, []int32{gameRow.GameID})
+	if err != nil {
+		return nil, nil, err
+	}
+	problemIDs := make([]int32, len(problemRows))
+	for i, row := range problemRows {
+		problemIDs[i] = row.Problem.ProblemID
+	}
+	members, err := teamMembers(ctx, q, gameRow.GameID)
+	if err != nil {
+		return nil, nil, err
+	}
+	teams := aggregateRanking(rows, members, problemIDs, int(gameRow.UnsolvedPenalty))
+	ranks := ranking.Sort(teams, rankingKey, policy)
+	return teams, ranks, nil
+}
+
+// aggregateRanking groups the best submissions by team. Submissions to
+// problems that are no longer in the game are ignored.
+func aggregateRanking(rows []db.GetRankingRow, members map[int32][]db.User, problemIDs []int32, unsolvedPenalty int) []RankedTeam {
+	var teams []RankedTeam
+	index := make(map[int32]int)
+	for _, row := range rows {
+		if !slices.Contains(problemIDs, row.Submission.ProblemID) {
+			continue
+		}
+		i, ok := index[row.GameTeam.TeamID]
+		if !ok {
+			i = len(teams)
+			index[row.GameTeam.TeamID] = i
+			teams = append(teams, RankedTeam{
+				Team:       row.GameTeam,
+				Members:    members[row.GameTeam.TeamID],
+				ProblemIDs: problemIDs,
+				Bests:      make(map[int32]db.Submission),
+			})
+		}
+		t := &teams[i]
+		t.Bests[row.Submission.ProblemID] = row.Submission
+		t.Score += int(row.Submission.CodeSize)
+		if submittedAt := row.Submission.CreatedAt.Time; submittedAt.After(t.SubmittedAt) {
+			t.SubmittedAt = submittedAt
+			t.SubmissionCount = int(row.SubmissionCount)
+		}
+	}
+	for i := range teams {
+		teams[i].Score += unsolvedPenalty * (len(problemIDs) - len(teams[i].Bests))
+	}
+	return teams
+}
+
+func rankingKey(t RankedTeam) ranking.Key {
+	return ranking.Key{
+		Score:           t.Score,
+		SubmissionCount: t.SubmissionCount,
+		SubmittedAt:     t.SubmittedAt,
+		TeamID:          int(t.Team.TeamID),
+	}
+}
+
+// RevealRanking moves the frozen ranking of a finished game forward. If all is
+// false, it reveals up to the next submission that changes the ranking;
+// otherwise it reveals the final ranking.
+func (s *Service) RevealRanking(ctx context.Context, gameID int, all bool) error {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	lifecycle := LifecycleFromGame(gameRow)
+	now := time.Now()
+	if lifecycle.StateAt(now) != StateFinished {
+		return ErrGameNotFinished
+	}
+	cutoff, frozen := RankingCutoff(gameRow, now)
+	if !frozen {
+		return ErrNotFrozen
+	}
+
+	revealedUntil := lifecycle.endsAt()
+	if !all {
+		next, err := s.nextRankingChange(ctx, gameRow.GameID, cutoff)
+		if err != nil {
+			return err
+		}
+		if next.Valid {
+			revealedUntil = next.Time
+		}
+	}
+	if err := s.q.UpdateGameRevealedUntil(ctx, db.UpdateGameRevealedUntilParams{
+		GameID:        gameRow.GameID,
+		RevealedUntil: pgtype.Timestamp{Time: revealedUntil, Valid: true},
+	}); err != nil {
+		return err
+	}
+
+	s.hub.PublishEvent(Event{
+		Type:   EventTypeGame,
+		GameID: gameID,
+	})
+	return nil
+}
+
+// nextRankingChange returns the time of the first submission after cutoff that
+// improves the best score of its team for the problem. It returns an invalid
+// timestamp if there is none.
+func (s *Service) nextRankingChange(ctx context.Context, gameID int32, cutoff time.Time) (pgtype.Timestamp, error) {
+	at := pgtype.Timestamp{Time: cutoff, Valid: true}
+	bestRows, err := s.q.ListBestSubmissionsAt(ctx, db.ListBestSubmissionsAtParams{
+		GameID:    gameID,
+		CreatedAt: at,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Timestamp{}, err
+	}
+	type bestKey struct{ teamID, problemID int32 }
+	bestScores := make(map[bestKey]int32, len(bestRows))
+	for _, row := range bestRows {
+		bestScores[bestKey{row.GameTeam.TeamID, row.Submission.ProblemID}] = row.Submission.CodeSize
+	}
+	submissions, err := s.q.ListSuccessfulSubmissionsAfter(ctx, db.ListSuccessfulSubmissionsAfterParams{
+		GameID:    gameID,
+		CreatedAt: at,
+	})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return pgtype.Timestamp{}, err
+	}
+	for _, sub := range submissions {
+		if best, ok := bestScores[bestKey{sub.TeamID, sub.ProblemID}]; !ok || sub.CodeSize < best {
+			return sub.CreatedAt, nil
+		}
+	}
+	return pgtype.Timestamp{}, nil
+}
+
+// CreateGameParams holds parameters for creating a game with its problems.
+type CreateGameParams struct {
 	GameType        string
 	IsPublic        bool
 	DisplayName     string
 	DurationSeconds int
-	StartedAt       pgtype.Timestamp
-	ProblemID       int
-	MainPlayerIDs   []int
+	UnsolvedPenalty int
+	ProblemIDs      []int
 }
 
-func (s *Service) StartGame(ctx context.Context, gameID int) error {
-	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+// UpdateGameParams holds parameters for updating a game with its players.
+type UpdateGameParams struct {
+	GameID      int
+	GameType    string
+	IsPublic    bool
+	DisplayName string
+	// DurationSeconds is changed by ActionSetDuration, which is recorded in
+	// the history like other transitions. Once the game has started, it must
+	// be left as it is and ActionExtend used instead.
+	DurationSeconds int
+	FreezeSeconds   int
+	TieBreak        string
+	UnsolvedPenalty int
+	AllowPractice   bool
+	// ProblemIDs replaces the problems of the game, in order.
+	ProblemIDs    []int
+	MainPlayerIDs []int
+	// AdminID is the admin who changed the game, recorded in the history if
+	// the duration is changed.
+	AdminID int32
+}
+
+// TransitionGameParams holds parameters for changing the state of a game.
+type TransitionGameParams struct {
+	GameID int
+	Action Action
+	// StartAt is the start time for ActionSchedule.
+	StartAt time.Time
+	// ExtendSeconds is the length for ActionExtend.
+	ExtendSeconds int
+	// AdminID is the admin who triggered the transition, recorded in the
+	// history.
+	AdminID int32
+	// SkipValidation lets ActionSchedule and ActionStart proceed even if some
+	// of the problems have not passed their validation.
+	SkipValidation bool
+}
+
+// TransitionGame changes the state of the game and records it in the history.
+// It fails with ErrInvalidTransition if the action is not allowed in the
+// current state.
+func (s *Service) TransitionGame(ctx context.Context, params TransitionGameParams) error {
+	gameID := int32(params.GameID)
+	gameRow, err := s.q.GetGameByID(ctx, gameID)
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrNotFound
 		}
 		return err
 	}
-	testcases, err := s.q.ListTestcasesByProblemID(ctx, gameRow.ProblemID)
-	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+	if params.Action == ActionSchedule || params.Action == ActionStart {
+		if err := s.checkGameReady(ctx, gameRow.GameID, params.SkipValidation); err != nil {
+			return err
+		}
+	}
+
+	err = s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		return transitionGameInTx(ctx, qtx, gameID, params.Action, params.StartAt, params.ExtendSeconds, params.AdminID)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.hub.PublishEvent(Event{
+		Type:   EventTypeGame,
+		GameID: params.GameID,
+	})
+	return nil
+}
+
+// transitionGameInTx applies the action to the game locked for update and
+// records it in the history.
+func transitionGameInTx(ctx context.Context, qtx db.Querier, gameID int32, action Action, at time.Time, seconds int, adminID int32) error {
+	row, err := qtx.GetGameLifecycleForUpdate(ctx, gameID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	current := Lifecycle{
+		StartedAt:       row.StartedAt,
+		DurationSeconds: row.DurationSeconds,
+		PausedAt:        row.PausedAt,
+		OverrideState:   row.OverrideState,
+	}
+	now := time.Now()
+	next, err := current.Apply(action, now, at, seconds)
+	if err != nil {
 		return err
 	}
-	if len(testcases) == 0 {
-		return ErrNoTestcases
+	if err := qtx.UpdateGameLifecycle(ctx, db.UpdateGameLifecycleParams{
+		GameID:          gameID,
+		StartedAt:       next.StartedAt,
+		DurationSeconds: next.DurationSeconds,
+		PausedAt:        next.PausedAt,
+		OverrideState:   next.OverrideState,
+	}); err != nil {
+		return err
 	}
+	return qtx.CreateGameLifecycleEvent(ctx, db.CreateGameLifecycleEventParams{
+		GameID:          gameID,
+		Action:          string(action),
+		FromState:       string(current.StateAt(now)),
+		ToState:         string(next.StateAt(now)),
+		StartedAt:       next.StartedAt,
+		DurationSeconds: next.DurationSeconds,
+		UserID:          &adminID,
+	})
+}
 
-	startedAt := time.Now().Add(10 * time.Second)
-	return s.q.UpdateGameStartedAt(ctx, db.UpdateGameStartedAtParams{
-		GameID: int32(gameID),
-		StartedAt: pgtype.Timestamp{
-			Time:  startedAt,
-			Valid: true,
-		},
+// checkGameReady fails with ErrNoProblems or ErrNoTestcases unless the game
+// has problems and all of them have testcases, and with ErrNotValidated unless
+// all of them have passed their validation or skipValidation is set.
+func (s *Service) checkGameReady(ctx context.Context, gameID int32, skipValidation bool) error {
+	problemRows, err := s.q.ListGameProblems(ctx, []int32{gameID})
+	if err != nil {
+		return err
+	}
+	if len(problemRows) == 0 {
+		return ErrNoProblems
+	}
+	for _, row := range problemRows {
+		testcases, err := s.q.ListTestcasesByProblemID(ctx, row.Problem.ProblemID)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		if len(testcases) == 0 {
+			return ErrNoTestcases
+		}
+		if skipValidation {
+			continue
+		}
+		state, _, err := validationState(ctx, s.q, row.Problem.ProblemID)
+		if err != nil {
+			return err
+		}
+		if state != ValidationPassed {
+			return fmt.Errorf("%w: validation of %q is %s", ErrNotValidated, row.Problem.Title, state)
+		}
+	}
+	return nil
+}
+
+func (s *Service) CreateGame(ctx context.Context, params CreateGameParams) (int, error) {
+	var gameID int32
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		gameID, err = qtx.CreateGame(ctx, db.CreateGameParams{
+			GameType:        params.GameType,
+			IsPublic:        params.IsPublic,
+			DisplayName:     params.DisplayName,
+			DurationSeconds: int32(params.DurationSeconds),
+			UnsolvedPenalty: int32(params.UnsolvedPenalty),
+		})
+		if err != nil {
+			return err
+		}
+		return addGameProblems(ctx, qtx, gameID, params.ProblemIDs)
 	})
+	return int(gameID), err
 }
 
+func addGameProblems(ctx context.Context, qtx db.Querier, gameID int32, problemIDs []int) error {
+	for i, problemID := range problemIDs {
+		if err := qtx.AddGameProblem(ctx, db.AddGameProblemParams{
+			GameID:    gameID,
+			ProblemID: int32(problemID),
+			Position:  int32(i),
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UpdateGameWithPlayers updates the game with its problems and players. It
+// fails with ErrInvalidTransition if the duration is changed after the game
+// has started.
 func (s *Service) UpdateGameWithPlayers(ctx context.Context, params UpdateGameParams) error {
-	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+	durationChanged := false
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		row, err := qtx.GetGameLifecycleForUpdate(ctx, int32(params.GameID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if int(row.DurationSeconds) != params.DurationSeconds {
+			durationChanged = true
+			if err := transitionGameInTx(ctx, qtx, int32(params.GameID), ActionSetDuration, time.Time{}, params.DurationSeconds, params.AdminID); err != nil {
+				return err
+			}
+		}
 		if err := qtx.UpdateGame(ctx, db.UpdateGameParams{
 			GameID:          int32(params.GameID),
 			GameType:        params.GameType,
 			IsPublic:        params.IsPublic,
 			DisplayName:     params.DisplayName,
-			DurationSeconds: int32(params.DurationSeconds),
-			StartedAt:       params.StartedAt,
-			ProblemID:       int32(params.ProblemID),
+			FreezeSeconds:   int32(params.FreezeSeconds),
+			TieBreak:        params.TieBreak,
+			UnsolvedPenalty: int32(params.UnsolvedPenalty),
+			AllowPractice:   params.AllowPractice,
 		}); err != nil {
 			return err
 		}
+		if err := qtx.RemoveAllGameProblems(ctx, int32(params.GameID)); err != nil {
+			return err
+		}
+		if err := addGameProblems(ctx, qtx, int32(params.GameID), params.ProblemIDs); err != nil {
+			return err
+		}
 		if err := qtx.RemoveAllMainPlayers(ctx, int32(params.GameID)); err != nil {
 			return err
 		}
@@ -438,27 +1379,30 @@ func (s *Service) UpdateGameWithPlayers(ctx context.Context, params UpdateGamePa
 		}
 		return nil
 	})
+	if err != nil {
+		return err
+	}
+	if durationChanged {
+		s.hub.PublishEvent(Event{
+			Type:   EventTypeGame,
+			GameID: params.GameID,
+		})
+	}
+	return nil
 }
 
-func (s *Service) RejudgeSubmission(ctx context.Context, submissionID int32, gameID int, userID int, language, code string) error {
+func (s *Service) RejudgeSubmission(ctx context.Context, submissionID int32, gameID, userID, problemID int, language, code string) error {
 	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
-		if err := qtx.DeleteTestcaseResultsBySubmissionID(ctx, submissionID); err != nil {
-			return err
-		}
-		return qtx.UpdateSubmissionStatus(ctx, db.UpdateSubmissionStatusParams{
-			SubmissionID: submissionID,
-			Status:       "running",
-		})
+		return resetSubmission(ctx, qtx, submissionID)
 	})
 	if err != nil {
 		return err
 	}
-	return s.hub.EnqueueTestTasks(ctx, int(submissionID), gameID, userID, language, code)
+	return s.hub.EnqueueTestTasks(ctx, int(submissionID), gameID, userID, problemID, language, code)
 }
 
 func (s *Service) RejudgeLatestSubmissionsByGame(ctx context.Context, gameID int) error {
-	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
-	if err != nil {
+	if _, err := s.q.GetGameByID(ctx, int32(gameID)); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrNotFound
 		}
@@ -469,18 +1413,11 @@ func (s *Service) RejudgeLatestSubmissionsByGame(ctx context.Context, gameID int
 	if err != nil {
 		return err
 	}
-
-	for _, sub := range submissions {
-		if err := s.RejudgeSubmission(ctx, sub.SubmissionID, int(sub.GameID), int(sub.UserID), gameRow.Language, sub.Code); err != nil {
-			return err
-		}
-	}
-	return nil
+	return s.rejudgeSubmissions(ctx, submissions)
 }
 
 func (s *Service) RejudgeAllSubmissionsByGame(ctx context.Context, gameID int) error {
-	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
-	if err != nil {
+	if _, err := s.q.GetGameByID(ctx, int32(gameID)); err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return ErrNotFound
 		}
@@ -491,9 +1428,14 @@ func (s *Service) RejudgeAllSubmissionsByGame(ctx context.Context, gameID int) e
 	if err != nil {
 		return err
 	}
+	return s.rejudgeSubmissions(ctx, submissions)
+}
 
+// rejudgeSubmissions rejudges the submissions in the languages they were
+// written in.
+func (s *Service) rejudgeSubmissions(ctx context.Context, submissions []db.Submission) error {
 	for _, sub := range submissions {
-		if err := s.RejudgeSubmission(ctx, sub.SubmissionID, int(sub.GameID), int(sub.UserID), gameRow.Language, sub.Code); err != nil {
+		if err := s.RejudgeSubmission(ctx, sub.SubmissionID, int(sub.GameID), int(sub.UserID), int(sub.ProblemID), sub.Language, sub.Code); err != nil {
 			return err
 		}
 	}
@@ -530,6 +1472,122 @@ func (s *Service) FixSubmissionStatuses(ctx context.Context) error {
 	return nil
 }
 
+// UpdateProblemParams holds parameters for updating a problem.
+type UpdateProblemParams struct {
+	ProblemID      int
+	Title          string
+	Description    string
+	Language       string
+	SampleCode     string
+	Scoring        string
+	Checker        string
+	CheckerEpsilon float64
+	CheckerCode    string
+	TimeLimitMs    *int32
+	MemoryLimitMiB *int32
+	// OtherLanguages replaces the languages the problem accepts besides its
+	// own.
+	OtherLanguages []ProblemLanguage
+}
+
+// UpdateProblem updates the problem with its languages in one transaction.
+// If the scoring strategy changes, the existing submissions are rescored with
+// the new one, each in its own language.
+func (s *Service) UpdateProblem(ctx context.Context, params UpdateProblemParams) error {
+	strategy, err := scoring.New(params.Scoring)
+	if err != nil {
+		return err
+	}
+	problemID := int32(params.ProblemID)
+
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		current, err := qtx.GetProblemByID(ctx, problemID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := qtx.UpdateProblem(ctx, db.UpdateProblemParams{
+			ProblemID:      problemID,
+			Title:          params.Title,
+			Description:    params.Description,
+			Language:       params.Language,
+			SampleCode:     params.SampleCode,
+			Scoring:        params.Scoring,
+			Checker:        params.Checker,
+			CheckerEpsilon: params.CheckerEpsilon,
+			CheckerCode:    params.CheckerCode,
+			TimeLimitMs:    params.TimeLimitMs,
+			MemoryLimitMib: params.MemoryLimitMiB,
+		}); err != nil {
+			return err
+		}
+		if err := setProblemLanguages(ctx, qtx, problemID, params.Language, params.Scoring, params.OtherLanguages); err != nil {
+			return err
+		}
+		if params.Scoring == current.Scoring {
+			return nil
+		}
+		return rescoreSubmissions(ctx, qtx, problemID, strategy)
+	})
+}
+
+// RescoreSubmissionsByProblem recalculates the code sizes of all the
+// submissions to the problem with its current scoring strategy, and then
+// re-selects the best submissions of the affected teams.
+func (s *Service) RescoreSubmissionsByProblem(ctx context.Context, problemID int) error {
+	problem, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	strategy, err := scoring.New(problem.Scoring)
+	if err != nil {
+		return err
+	}
+
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		return rescoreSubmissions(ctx, qtx, problem.ProblemID, strategy)
+	})
+}
+
+// rescoreSubmissions recalculates the code sizes of all the submissions to the
+// problem with strategy, and then re-selects the best submissions of the
+// affected teams.
+func rescoreSubmissions(ctx context.Context, qtx db.Querier, problemID int32, strategy scoring.Strategy) error {
+	submissions, err := qtx.ListSubmissionsByProblemID(ctx, problemID)
+	if err != nil {
+		return err
+	}
+	for _, sub := range submissions {
+		codeSize := int32(strategy.Score(sub.Code, sub.Language))
+		if codeSize == sub.CodeSize {
+			continue
+		}
+		if err := qtx.UpdateSubmissionCodeSize(ctx, db.UpdateSubmissionCodeSizeParams{
+			SubmissionID: sub.SubmissionID,
+			CodeSize:     codeSize,
+		}); err != nil {
+			return err
+		}
+	}
+
+	gameStates, err := qtx.ListGameStateIDsByProblemID(ctx, problemID)
+	if err != nil {
+		return err
+	}
+	for _, r := range gameStates {
+		if err := qtx.SyncGameStateBestScoreSubmission(ctx, db.SyncGameStateBestScoreSubmissionParams(r)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// GetSubmissions returns the submissions of the team of the player.
 func (s *Service) GetSubmissions(ctx context.Context, gameID int, userID int32) ([]SubmissionDetail, error) {
 	_, err := s.q.GetGameByID(ctx, int32(gameID))
 	if err != nil {
@@ -539,24 +1597,92 @@ func (s *Service) GetSubmissions(ctx context.Context, gameID int, userID int32)
 		return nil, err
 	}
 
-	rows, err := s.q.GetSubmissionsByGameIDAndUserID(ctx, db.GetSubmissionsByGameIDAndUserIDParams{
+	team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
 		GameID: int32(gameID),
 		UserID: userID,
 	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return []SubmissionDetail{}, nil
+		}
+		return nil, err
+	}
+	rows, err := s.q.GetSubmissionsByGameIDAndTeamID(ctx, db.GetSubmissionsByGameIDAndTeamIDParams{
+		GameID: int32(gameID),
+		TeamID: team.TeamID,
+	})
 	if err != nil {
 		return nil, err
 	}
 
 	submissions := make([]SubmissionDetail, len(rows))
 	for i, row := range rows {
-		submissions[i] = SubmissionDetail{
-			SubmissionID: int(row.SubmissionID),
-			GameID:       int(row.GameID),
-			Code:         row.Code,
-			CodeSize:     int(row.CodeSize),
-			Status:       row.Status,
-			CreatedAt:    row.CreatedAt.Time.Unix(),
-		}
+		submissions[i] = submissionDetailFromRow(row)
 	}
 	return submissions, nil
 }
+
+// GetSubmission returns the submission of the team of the player with its
+// per-testcase verdicts. Submissions of other teams are reported as not found.
+func (s *Service) GetSubmission(ctx context.Context, gameID int, userID int32, submissionID int) (SubmissionDetail, []TestcaseVerdict, error) {
+	row, err := s.q.GetSubmissionByID(ctx, int32(submissionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SubmissionDetail{}, nil, ErrNotFound
+		}
+		return SubmissionDetail{}, nil, err
+	}
+	if int(row.GameID) != gameID {
+		return SubmissionDetail{}, nil, ErrNotFound
+	}
+	team, err := s.q.GetTeamByUserID(ctx, db.GetTeamByUserIDParams{
+		GameID: row.GameID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return SubmissionDetail{}, nil, ErrNotFound
+		}
+		return SubmissionDetail{}, nil, err
+	}
+	if row.TeamID != team.TeamID {
+		return SubmissionDetail{}, nil, ErrNotFound
+	}
+
+	resultRows, err := s.q.ListTestcaseResultsWithTestcaseBySubmissionID(ctx, row.SubmissionID)
+	if err != nil {
+		return SubmissionDetail{}, nil, err
+	}
+	verdicts := make([]TestcaseVerdict, len(resultRows))
+	for i, r := range resultRows {
+		verdicts[i] = TestcaseVerdict{
+			TestcaseID: int(r.Testcase.TestcaseID),
+			IsSample:   r.Testcase.IsSample,
+			Passed:     r.TestcaseResult.Status == "success",
+		}
+		if r.Testcase.IsSample {
+			verdicts[i].Sample = &SampleVerdict{
+				Status:         r.TestcaseResult.Status,
+				Stdin:          r.Testcase.Stdin,
+				ExpectedStdout: r.Testcase.Stdout,
+				Stdout:         r.TestcaseResult.Stdout,
+				Stderr:         r.TestcaseResult.Stderr,
+			}
+		}
+	}
+	return submissionDetailFromRow(row), verdicts, nil
+}
+
+func submissionDetailFromRow(row db.Submission) SubmissionDetail {
+	return SubmissionDetail{
+		SubmissionID: int(row.SubmissionID),
+		GameID:       int(row.GameID),
+		ProblemID:    int(row.ProblemID),
+		Language:     row.Language,
+		Code:         row.Code,
+		CodeSize:     int(row.CodeSize),
+		Status:       row.Status,
+		IsPractice:   row.IsPractice,
+		CreatedAt:    row.CreatedAt.Time.Unix(),
+	}
+}
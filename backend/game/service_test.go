@@ -1,42 +1,51 @@
 package game
 
 import (
+	"context"
 	"testing"
 	"time"
 
 	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
 )
 
 func TestIsGameRunning(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
-		name            string
-		startedAt       pgtype.Timestamp
-		durationSeconds int32
-		want            bool
+		name      string
+		lifecycle Lifecycle
+		want      bool
 	}{
 		{
-			name:            "not started",
-			startedAt:       pgtype.Timestamp{Valid: false},
-			durationSeconds: 300,
-			want:            false,
+			name:      "not started",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Valid: false}, DurationSeconds: 300},
+			want:      false,
+		},
+		{
+			name:      "running",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true}, DurationSeconds: 300},
+			want:      true,
 		},
 		{
-			name:            "running",
-			startedAt:       pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true},
-			durationSeconds: 300,
-			want:            true,
+			name:      "finished",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true}, DurationSeconds: 300},
+			want:      false,
 		},
 		{
-			name:            "finished",
-			startedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
-			durationSeconds: 300,
-			want:            false,
+			name: "paused",
+			lifecycle: Lifecycle{
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true},
+				DurationSeconds: 300,
+				PausedAt:        pgtype.Timestamp{Time: now, Valid: true},
+				OverrideState:   stateOverride(StatePaused),
+			},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := IsGameRunning(tt.startedAt, tt.durationSeconds)
+			got := IsGameRunning(tt.lifecycle)
 			if got != tt.want {
 				t.Errorf("IsGameRunning() = %v, want %v", got, tt.want)
 			}
@@ -47,36 +56,209 @@ func TestIsGameRunning(t *testing.T) {
 func TestIsGameFinished(t *testing.T) {
 	now := time.Now()
 	tests := []struct {
-		name            string
-		startedAt       pgtype.Timestamp
-		durationSeconds int32
-		want            bool
+		name      string
+		lifecycle Lifecycle
+		want      bool
 	}{
 		{
-			name:            "not started",
-			startedAt:       pgtype.Timestamp{Valid: false},
-			durationSeconds: 300,
-			want:            false,
+			name:      "not started",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Valid: false}, DurationSeconds: 300},
+			want:      false,
+		},
+		{
+			name:      "still running",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true}, DurationSeconds: 300},
+			want:      false,
+		},
+		{
+			name:      "finished",
+			lifecycle: Lifecycle{StartedAt: pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true}, DurationSeconds: 300},
+			want:      true,
 		},
 		{
-			name:            "still running",
-			startedAt:       pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true},
-			durationSeconds: 300,
-			want:            false,
+			name: "ended early",
+			lifecycle: Lifecycle{
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-1 * time.Minute), Valid: true},
+				DurationSeconds: 300,
+				OverrideState:   stateOverride(StateFinished),
+			},
+			want: true,
 		},
 		{
-			name:            "finished",
-			startedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
-			durationSeconds: 300,
-			want:            true,
+			name: "cancelled",
+			lifecycle: Lifecycle{
+				StartedAt:       pgtype.Timestamp{Time: now.Add(-10 * time.Minute), Valid: true},
+				DurationSeconds: 300,
+				OverrideState:   stateOverride(StateCancelled),
+			},
+			want: false,
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := IsGameFinished(tt.startedAt, tt.durationSeconds)
+			got := IsGameFinished(tt.lifecycle)
 			if got != tt.want {
 				t.Errorf("IsGameFinished() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestAggregateRanking(t *testing.T) {
+	at := func(minute int) pgtype.Timestamp {
+		return pgtype.Timestamp{Time: time.Date(2026, 3, 20, 10, minute, 0, 0, time.UTC), Valid: true}
+	}
+	rows := []db.GetRankingRow{
+		{Submission: db.Submission{TeamID: 1, UserID: 1, ProblemID: 10, CodeSize: 30, CreatedAt: at(5)}, GameTeam: db.GameTeam{TeamID: 1}, SubmissionCount: 2},
+		{Submission: db.Submission{TeamID: 2, UserID: 3, ProblemID: 10, CodeSize: 40, CreatedAt: at(3)}, GameTeam: db.GameTeam{TeamID: 2}, SubmissionCount: 1},
+		{Submission: db.Submission{TeamID: 1, UserID: 2, ProblemID: 20, CodeSize: 50, CreatedAt: at(9)}, GameTeam: db.GameTeam{TeamID: 1}, SubmissionCount: 4},
+		{Submission: db.Submission{TeamID: 2, UserID: 3, ProblemID: 99, CodeSize: 1, CreatedAt: at(7)}, GameTeam: db.GameTeam{TeamID: 2}, SubmissionCount: 3},
+	}
+	members := map[int32][]db.User{
+		1: {{UserID: 1}, {UserID: 2}},
+		2: {{UserID: 3}},
+	}
+
+	teams := aggregateRanking(rows, members, []int32{10, 20}, 100)
+
+	if len(teams) != 2 {
+		t.Fatalf("expected 2 teams, got %d", len(teams))
+	}
+	t1, t2 := teams[0], teams[1]
+	if t1.Team.TeamID != 1 || t1.Score != 80 || len(t1.Bests) != 2 || len(t1.Members) != 2 {
+		t.Errorf("unexpected team 1: score %d, %d bests, %d members", t1.Score, len(t1.Bests), len(t1.Members))
+	}
+	if t1.SubmissionCount != 4 || !t1.SubmittedAt.Equal(at(9).Time) {
+		t.Errorf("expected team 1 to be ranked by the last best, got %d at %v", t1.SubmissionCount, t1.SubmittedAt)
+	}
+	if t2.Team.TeamID != 2 || t2.Score != 140 || len(t2.Bests) != 1 {
+		t.Errorf("expected the penalty for the unsolved problem of team 2, got score %d, %d bests", t2.Score, len(t2.Bests))
+	}
+	if _, ok := t2.Bests[99]; ok {
+		t.Error("expected submissions to problems out of the game to be ignored")
+	}
+}
+
+func TestTeamEvents(t *testing.T) {
+	events := make(chan Event, 5)
+	events <- Event{Type: EventTypeCode, UserID: 3, TeamID: 2}
+	events <- Event{Type: EventTypeCode, UserID: 1, TeamID: 1}
+	events <- Event{Type: EventTypeStatus, UserID: 2, TeamID: 1}
+	events <- Event{Type: EventTypeGame}
+	events <- Event{Type: EventTypeStatus, UserID: 3, TeamID: 2}
+	close(events)
+
+	var got []Event
+	for event := range teamEvents(events, 1, 1) {
+		got = append(got, event)
+	}
+	if len(got) != 3 || got[0].UserID != 1 || got[1].UserID != 2 || got[2].Type != EventTypeGame {
+		t.Errorf("expected the events of team 1 and the game, got %+v", got)
+	}
+}
+
+func TestTeamEvents_LearnsTeamOfOwn(t *testing.T) {
+	events := make(chan Event, 3)
+	events <- Event{Type: EventTypeCode, UserID: 3, TeamID: 2}
+	events <- Event{Type: EventTypeCode, UserID: 1, TeamID: 4}
+	events <- Event{Type: EventTypeStatus, UserID: 1, TeamID: 4}
+	close(events)
+
+	var got []Event
+	for event := range teamEvents(events, 0, 1) {
+		got = append(got, event)
+	}
+	if len(got) != 2 || got[0].TeamID != 4 || got[1].TeamID != 4 {
+		t.Errorf("expected the events of the team created for the player, got %+v", got)
+	}
+}
+
+// watchQuerier returns a fixed game and main players for testing.
+type watchQuerier struct {
+	db.Querier
+	game        db.Game
+	mainPlayers []int32
+}
+
+func (m *watchQuerier) GetGameByID(_ context.Context, _ int32) (db.Game, error) {
+	return m.game, nil
+}
+
+func (m *watchQuerier) ListMainPlayers(_ context.Context, _ []int32) ([]db.ListMainPlayersRow, error) {
+	rows := make([]db.ListMainPlayersRow, len(m.mainPlayers))
+	for i, userID := range m.mainPlayers {
+		rows[i] = db.ListMainPlayersRow{GameID: m.game.GameID, UserID: userID}
+	}
+	return rows, nil
+}
+
+// watchedEvents returns the events spectators of the game receive.
+func watchedEvents(t *testing.T, q *watchQuerier, sent []Event) []Event {
+	t.Helper()
+	s := &Service{q: q}
+	mainPlayers, err := mainPlayerIDs(context.Background(), q, q.game.GameID)
+	if err != nil {
+		t.Fatalf("mainPlayerIDs: %v", err)
+	}
+	events := make(chan Event, len(sent))
+	for _, event := range sent {
+		events <- event
+	}
+	close(events)
+	var got []Event
+	for event := range s.spectatorEvents(context.Background(), q.game, mainPlayers, events) {
+		got = append(got, event)
+	}
+	return got
+}
+
+func TestSpectatorEvents_CodeOfMainPlayers(t *testing.T) {
+	q := &watchQuerier{
+		game:        db.Game{GameID: 1, GameType: "1v1"},
+		mainPlayers: []int32{1, 2},
+	}
+	got := watchedEvents(t, q, []Event{
+		{Type: EventTypeCode, UserID: 1, TeamID: 1, Code: "a"},
+		{Type: EventTypeCode, UserID: 3, TeamID: 3, Code: "b"},
+		{Type: EventTypeStatus, UserID: 3, TeamID: 3},
+	})
+	if len(got) != 2 || got[0].Code != "a" || got[1].Type != EventTypeStatus {
+		t.Errorf("expected the code of the main player and the status, got %+v", got)
+	}
+}
+
+func TestSpectatorEvents_NoCodeInMultiplayer(t *testing.T) {
+	q := &watchQuerier{
+		game:        db.Game{GameID: 1, GameType: "multiplayer"},
+		mainPlayers: []int32{1},
+	}
+	got := watchedEvents(t, q, []Event{
+		{Type: EventTypeCode, UserID: 1, TeamID: 1, Code: "a"},
+		{Type: EventTypeStatus, UserID: 1, TeamID: 1},
+	})
+	if len(got) != 1 || got[0].Type != EventTypeStatus {
+		t.Errorf("expected only the status, got %+v", got)
+	}
+}
+
+func TestSpectatorEvents_NoCodeDuringFreeze(t *testing.T) {
+	// The game ends in a minute, within the freeze of 5 minutes.
+	q := &watchQuerier{
+		game: db.Game{
+			GameID:          1,
+			GameType:        "1v1",
+			StartedAt:       pgtype.Timestamp{Time: time.Now().Add(-9 * time.Minute), Valid: true},
+			DurationSeconds: 600,
+			FreezeSeconds:   300,
+		},
+		mainPlayers: []int32{1, 2},
+	}
+	got := watchedEvents(t, q, []Event{
+		{Type: EventTypeCode, UserID: 1, TeamID: 1, Code: "a"},
+		{Type: EventTypeStatus, UserID: 1, TeamID: 1},
+		{Type: EventTypeGame},
+	})
+	if len(got) != 1 || got[0].Type != EventTypeGame {
+		t.Errorf("expected only the game event, got %+v", got)
+	}
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+)
+
+// Team is a group of players who share the code and the submissions in a
+// game. Players who are not in any team play in a team of their own.
+type Team struct {
+	TeamID      int
+	DisplayName string
+	Members     []Player
+}
+
+// TeamParams holds parameters for forming a team.
+type TeamParams struct {
+	DisplayName string
+	UserIDs     []int
+}
+
+// errTeamTaken tells that the user has joined another team while creating a
+// team of their own.
+var errTeamTaken = errors.New("user has already joined a team")
+
+// PlayerFromUser returns the player of a user row.
+func PlayerFromUser(u db.User) Player {
+	return Player{
+		UserID:      int(u.UserID),
+		Username:    u.Username,
+		DisplayName: u.DisplayName,
+		IconPath:    u.IconPath,
+		IsAdmin:     u.IsAdmin,
+		Label:       u.Label,
+		Rating:      int(u.Rating),
+		RatingRank:  intPtr(u.RatingRank),
+	}
+}
+
+// playerTeamID returns the team of the user in the game. If the user is not in
+// any team yet, a team of their own is created.
+func (s *Service) playerTeamID(ctx context.Context, gameID int, userID int32) (int32, error) {
+	params := db.GetTeamByUserIDParams{
+		GameID: int32(gameID),
+		UserID: userID,
+	}
+	team, err := s.q.GetTeamByUserID(ctx, params)
+	if err == nil {
+		return team.TeamID, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+
+	user, err := s.q.GetUserByID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+	var teamID int32
+	err = s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		teamID, err = qtx.CreateTeam(ctx, db.CreateTeamParams{
+			GameID:      int32(gameID),
+			DisplayName: user.DisplayName,
+		})
+		if err != nil {
+			return err
+		}
+		added, err := qtx.AddTeamMember(ctx, db.AddTeamMemberParams{
+			TeamID: teamID,
+			GameID: int32(gameID),
+			UserID: userID,
+		})
+		if err != nil {
+			return err
+		}
+		if added == 0 {
+			return errTeamTaken
+		}
+		return nil
+	})
+	if errors.Is(err, errTeamTaken) {
+		// A concurrent request has created the team first.
+		team, err := s.q.GetTeamByUserID(ctx, params)
+		if err != nil {
+			return 0, err
+		}
+		return team.TeamID, nil
+	}
+	return teamID, err
+}
+
+// ListTeams returns the teams of the game with their members.
+func (s *Service) ListTeams(ctx context.Context, gameID int) ([]Team, error) {
+	if _, err := s.q.GetGameByID(ctx, int32(gameID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, ErrNotFound
+		}
+		return nil, err
+	}
+	teamRows, err := s.q.ListTeams(ctx, int32(gameID))
+	if err != nil {
+		return nil, err
+	}
+	members, err := teamMembers(ctx, s.q, int32(gameID))
+	if err != nil {
+		return nil, err
+	}
+	teams := make([]Team, len(teamRows))
+	for i, row := range teamRows {
+		teams[i] = Team{
+			TeamID:      int(row.TeamID),
+			DisplayName: row.DisplayName,
+		}
+		for _, u := range members[row.TeamID] {
+			teams[i].Members = append(teams[i].Members, PlayerFromUser(u))
+		}
+	}
+	return teams, nil
+}
+
+// teamMembers returns the members of the teams of the game by team.
+func teamMembers(ctx context.Context, q db.Querier, gameID int32) (map[int32][]db.User, error) {
+	rows, err := q.ListTeamMembers(ctx, gameID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	members := make(map[int32][]db.User)
+	for _, row := range rows {
+		members[row.TeamID] = append(members[row.TeamID], row.User)
+	}
+	return members, nil
+}
+
+// ReplaceTeams replaces the teams of a multiplayer game. Teams can only be
+// formed before the game starts, as the code and the submissions belong to
+// them.
+func (s *Service) ReplaceTeams(ctx context.Context, gameID int, teams []TeamParams) error {
+	gameRow, err := s.q.GetGameByID(ctx, int32(gameID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if gameRow.GameType != "multiplayer" {
+		return ErrNotMultiplayer
+	}
+	switch LifecycleFromGame(gameRow).StateAt(time.Now()) {
+	case StateWaiting, StateScheduled:
+	default:
+		return ErrGameStarted
+	}
+
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		if err := qtx.RemoveAllTeamMembers(ctx, int32(gameID)); err != nil {
+			return err
+		}
+		if err := qtx.RemoveAllTeams(ctx, int32(gameID)); err != nil {
+			return err
+		}
+		for _, team := range teams {
+			teamID, err := qtx.CreateTeam(ctx, db.CreateTeamParams{
+				GameID:      int32(gameID),
+				DisplayName: team.DisplayName,
+			})
+			if err != nil {
+				return err
+			}
+			for _, userID := range team.UserIDs {
+				added, err := qtx.AddTeamMember(ctx, db.AddTeamMemberParams{
+					TeamID: teamID,
+					GameID: int32(gameID),
+					UserID: int32(userID),
+				})
+				if err != nil {
+					return err
+				}
+				if added == 0 {
+					return ErrDuplicateTeamMember
+				}
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,177 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+)
+
+// Actions recorded in the revisions of testcases.
+const (
+	TestcaseCreated = "create"
+	TestcaseUpdated = "update"
+	TestcaseDeleted = "delete"
+)
+
+// TestcaseParams holds the content of a testcase. Nil limits mean those of
+// the problem.
+type TestcaseParams struct {
+	Stdin          string
+	Stdout         string
+	IsSample       bool
+	TimeLimitMs    *int
+	MemoryLimitMiB *int
+}
+
+// TestcaseRevision is a change to a testcase of a problem, with the content
+// of the testcase after it, or before it for a deletion.
+type TestcaseRevision struct {
+	TestcaseID int
+	Action     string
+	Stdin      string
+	Stdout     string
+	IsSample   bool
+	// AffectsResults is set if the change can alter the verdicts of the
+	// submissions judged before it.
+	AffectsResults bool
+	UserID         *int
+	CreatedAt      time.Time
+}
+
+// CreateTestcase adds a testcase to the problem and returns its ID.
+func (s *Service) CreateTestcase(ctx context.Context, problemID int, params TestcaseParams, adminID int32) (int, error) {
+	var testcaseID int32
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		testcaseID, err = qtx.CreateTestcase(ctx, db.CreateTestcaseParams{
+			ProblemID:      int32(problemID),
+			Stdin:          params.Stdin,
+			Stdout:         params.Stdout,
+			IsSample:       params.IsSample,
+			TimeLimitMs:    int32Ptr(params.TimeLimitMs),
+			MemoryLimitMib: int32Ptr(params.MemoryLimitMiB),
+		})
+		if err != nil {
+			return err
+		}
+		return createTestcaseRevision(ctx, qtx, testcaseID, int32(problemID), TestcaseCreated, params, true, adminID)
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(testcaseID), nil
+}
+
+// UpdateTestcase changes the content of a testcase. The results judged with
+// the old content are kept until the submissions are rejudged.
+func (s *Service) UpdateTestcase(ctx context.Context, testcaseID int, params TestcaseParams, adminID int32) error {
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		current, err := qtx.GetTestcaseByID(ctx, int32(testcaseID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := qtx.UpdateTestcase(ctx, db.UpdateTestcaseParams{
+			TestcaseID:     current.TestcaseID,
+			ProblemID:      current.ProblemID,
+			Stdin:          params.Stdin,
+			Stdout:         params.Stdout,
+			IsSample:       params.IsSample,
+			TimeLimitMs:    int32Ptr(params.TimeLimitMs),
+			MemoryLimitMib: int32Ptr(params.MemoryLimitMiB),
+		}); err != nil {
+			return err
+		}
+		// Whether a testcase is a sample only changes what players see.
+		affectsResults := params.Stdin != current.Stdin || params.Stdout != current.Stdout ||
+			!equalIntPtr(params.TimeLimitMs, intPtr(current.TimeLimitMs)) ||
+			!equalIntPtr(params.MemoryLimitMiB, intPtr(current.MemoryLimitMib))
+		return createTestcaseRevision(ctx, qtx, current.TestcaseID, current.ProblemID, TestcaseUpdated, params, affectsResults, adminID)
+	})
+}
+
+// DeleteTestcase deletes a testcase along with its results.
+func (s *Service) DeleteTestcase(ctx context.Context, testcaseID int, adminID int32) error {
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		current, err := qtx.GetTestcaseByID(ctx, int32(testcaseID))
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return ErrNotFound
+			}
+			return err
+		}
+		if err := qtx.DeleteTestcaseResultsByTestcaseID(ctx, current.TestcaseID); err != nil {
+			return err
+		}
+		if err := qtx.DeleteTestcase(ctx, current.TestcaseID); err != nil {
+			return err
+		}
+		params := TestcaseParams{
+			Stdin:    current.Stdin,
+			Stdout:   current.Stdout,
+			IsSample: current.IsSample,
+		}
+		return createTestcaseRevision(ctx, qtx, current.TestcaseID, current.ProblemID, TestcaseDeleted, params, true, adminID)
+	})
+}
+
+// testcaseLimits returns the limits to run code on the testcase with, zero
+// meaning the defaults of the workers.
+func testcaseLimits(problem db.Problem, testcase db.Testcase) (timeLimitMs, memoryLimitMiB int) {
+	timeLimit, memoryLimit := problem.TimeLimitMs, problem.MemoryLimitMib
+	if testcase.TimeLimitMs != nil {
+		timeLimit = testcase.TimeLimitMs
+	}
+	if testcase.MemoryLimitMib != nil {
+		memoryLimit = testcase.MemoryLimitMib
+	}
+	if timeLimit != nil {
+		timeLimitMs = int(*timeLimit)
+	}
+	if memoryLimit != nil {
+		memoryLimitMiB = int(*memoryLimit)
+	}
+	return timeLimitMs, memoryLimitMiB
+}
+
+func createTestcaseRevision(ctx context.Context, qtx db.Querier, testcaseID, problemID int32, action string, params TestcaseParams, affectsResults bool, adminID int32) error {
+	return qtx.CreateTestcaseRevision(ctx, db.CreateTestcaseRevisionParams{
+		TestcaseID:     testcaseID,
+		ProblemID:      problemID,
+		Action:         action,
+		Stdin:          params.Stdin,
+		Stdout:         params.Stdout,
+		IsSample:       params.IsSample,
+		AffectsResults: affectsResults,
+		UserID:         &adminID,
+	})
+}
+
+// ListTestcaseRevisions returns the changes to the testcases of the problem,
+// the latest first.
+func (s *Service) ListTestcaseRevisions(ctx context.Context, problemID int) ([]TestcaseRevision, error) {
+	rows, err := s.q.ListTestcaseRevisionsByProblemID(ctx, int32(problemID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	revisions := make([]TestcaseRevision, len(rows))
+	for i, row := range rows {
+		revisions[i] = TestcaseRevision{
+			TestcaseID:     int(row.TestcaseID),
+			Action:         row.Action,
+			Stdin:          row.Stdin,
+			Stdout:         row.Stdout,
+			IsSample:       row.IsSample,
+			AffectsResults: row.AffectsResults,
+			UserID:         intPtr(row.UserID),
+			CreatedAt:      row.CreatedAt.Time,
+		}
+	}
+	return revisions, nil
+}
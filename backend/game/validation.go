@@ -0,0 +1,219 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+)
+
+// ValidationState tells whether the testcases of a problem are known to be
+// right, i.e. whether all its reference solutions pass all of them.
+type ValidationState string
+
+const (
+	// ValidationMissing means the problem has no reference solutions or has
+	// never been validated.
+	ValidationMissing ValidationState = "missing"
+	ValidationRunning ValidationState = "running"
+	ValidationPassed  ValidationState = "passed"
+	ValidationFailed  ValidationState = "failed"
+	// ValidationOutdated means the testcases, the reference solutions or the
+	// checker have changed since the last validation.
+	ValidationOutdated ValidationState = "outdated"
+)
+
+// ReferenceSolution is a solution of a problem written by its author, which
+// must pass all the testcases.
+type ReferenceSolution struct {
+	ReferenceSolutionID int
+	Name                string
+	Code                string
+	CreatedAt           time.Time
+}
+
+// Validation is the state of a problem and its latest validation. The fields
+// other than State are zero if the problem has never been validated.
+type Validation struct {
+	State        ValidationState
+	ValidationID int
+	UserID       *int
+	CreatedAt    time.Time
+	FinishedAt   *time.Time
+	Results      []ValidationResult
+}
+
+// ValidationResult is the verdict of a reference solution on a testcase.
+type ValidationResult struct {
+	ReferenceSolutionID int
+	TestcaseID          int
+	Status              string
+	Stdout              string
+	Stderr              string
+}
+
+// ListReferenceSolutions returns the reference solutions of the problem.
+func (s *Service) ListReferenceSolutions(ctx context.Context, problemID int) ([]ReferenceSolution, error) {
+	rows, err := s.q.ListReferenceSolutionsByProblemID(ctx, int32(problemID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	solutions := make([]ReferenceSolution, len(rows))
+	for i, row := range rows {
+		solutions[i] = ReferenceSolution{
+			ReferenceSolutionID: int(row.ReferenceSolutionID),
+			Name:                row.Name,
+			Code:                row.Code,
+			CreatedAt:           row.CreatedAt.Time,
+		}
+	}
+	return solutions, nil
+}
+
+// CreateReferenceSolution adds a reference solution to the problem and
+// returns its ID. The problem needs to be validated again.
+func (s *Service) CreateReferenceSolution(ctx context.Context, problemID int, name, code string) (int, error) {
+	if _, err := s.q.GetProblemByID(ctx, int32(problemID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	id, err := s.q.CreateReferenceSolution(ctx, db.CreateReferenceSolutionParams{
+		ProblemID: int32(problemID),
+		Name:      name,
+		Code:      code,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(id), nil
+}
+
+// DeleteReferenceSolution deletes a reference solution of the problem.
+func (s *Service) DeleteReferenceSolution(ctx context.Context, problemID, referenceSolutionID int) error {
+	row, err := s.q.GetReferenceSolutionByID(ctx, int32(referenceSolutionID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNotFound
+		}
+		return err
+	}
+	if row.ProblemID != int32(problemID) {
+		return ErrNotFound
+	}
+	return s.q.DeleteReferenceSolution(ctx, row.ReferenceSolutionID)
+}
+
+// ValidateProblem judges all the reference solutions of the problem against
+// all its testcases on the workers, and returns the ID of the validation. The
+// validation finishes when the last of the results arrives, or fails at once
+// if the tasks cannot be enqueued.
+func (s *Service) ValidateProblem(ctx context.Context, problemID int, adminID int32) (int, error) {
+	problem, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, ErrNotFound
+		}
+		return 0, err
+	}
+	testcases, err := s.q.ListTestcasesByProblemID(ctx, problem.ProblemID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	if len(testcases) == 0 {
+		return 0, ErrNoTestcases
+	}
+	solutions, err := s.q.ListReferenceSolutionsByProblemID(ctx, problem.ProblemID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	if len(solutions) == 0 {
+		return 0, ErrNoReferenceSolutions
+	}
+
+	validationID, err := s.q.CreateProblemValidation(ctx, db.CreateProblemValidationParams{
+		ProblemID: problem.ProblemID,
+		TaskCount: int32(len(solutions) * len(testcases)),
+		UserID:    &adminID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if err := s.hub.EnqueueValidationTasks(ctx, int(validationID), problem, solutions, testcases); err != nil {
+		// The tasks that were not enqueued never report, so the validation
+		// would otherwise stay running.
+		return 0, errors.Join(err, s.q.FailProblemValidation(ctx, validationID))
+	}
+	return int(validationID), nil
+}
+
+// GetValidation returns the validation state of the problem along with the
+// results of its latest validation.
+func (s *Service) GetValidation(ctx context.Context, problemID int) (Validation, error) {
+	state, latest, err := validationState(ctx, s.q, int32(problemID))
+	if err != nil {
+		return Validation{}, err
+	}
+	v := Validation{State: state}
+	if latest == nil {
+		return v, nil
+	}
+	v.ValidationID = int(latest.ProblemValidationID)
+	v.UserID = intPtr(latest.UserID)
+	v.CreatedAt = latest.CreatedAt.Time
+	if latest.FinishedAt.Valid {
+		v.FinishedAt = &latest.FinishedAt.Time
+	}
+	rows, err := s.q.ListProblemValidationResults(ctx, latest.ProblemValidationID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Validation{}, err
+	}
+	v.Results = make([]ValidationResult, len(rows))
+	for i, row := range rows {
+		v.Results[i] = ValidationResult{
+			ReferenceSolutionID: int(row.ReferenceSolutionID),
+			TestcaseID:          int(row.TestcaseID),
+			Status:              row.Status,
+			Stdout:              row.Stdout,
+			Stderr:              row.Stderr,
+		}
+	}
+	return v, nil
+}
+
+// validationState returns the validation state of the problem and its latest
+// validation, or nil if there is none.
+func validationState(ctx context.Context, q db.Querier, problemID int32) (ValidationState, *db.ProblemValidation, error) {
+	latest, err := q.GetLatestProblemValidation(ctx, problemID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ValidationMissing, nil, nil
+		}
+		return "", nil, err
+	}
+	solutions, err := q.ListReferenceSolutionsByProblemID(ctx, problemID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", nil, err
+	}
+	if len(solutions) == 0 {
+		return ValidationMissing, &latest, nil
+	}
+	if latest.Status == "running" {
+		return ValidationRunning, &latest, nil
+	}
+	changedAt, err := q.GetProblemJudgeChangedAt(ctx, problemID)
+	if err != nil {
+		return "", nil, err
+	}
+	if changedAt.Valid && changedAt.Time.After(latest.CreatedAt.Time) {
+		return ValidationOutdated, &latest, nil
+	}
+	if latest.Status != "success" {
+		return ValidationFailed, &latest, nil
+	}
+	return ValidationPassed, &latest, nil
+}
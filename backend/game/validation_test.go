@@ -0,0 +1,253 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/checker"
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/taskqueue"
+)
+
+// validationQuerier implements the queries about validations for testing.
+type validationQuerier struct {
+	db.Querier
+	problem    db.Problem
+	latest     *db.ProblemValidation
+	solutions  []db.ReferenceSolution
+	changedAt  pgtype.Timestamp
+	results    []db.CreateProblemValidationResultParams
+	finishRows int64
+	testcases  []db.Testcase
+	failed     []int32
+}
+
+func (m *validationQuerier) GetProblemByID(_ context.Context, _ int32) (db.Problem, error) {
+	return m.problem, nil
+}
+
+func (m *validationQuerier) GetLatestProblemValidation(_ context.Context, _ int32) (db.ProblemValidation, error) {
+	if m.latest == nil {
+		return db.ProblemValidation{}, pgx.ErrNoRows
+	}
+	return *m.latest, nil
+}
+
+func (m *validationQuerier) ListReferenceSolutionsByProblemID(_ context.Context, _ int32) ([]db.ReferenceSolution, error) {
+	return m.solutions, nil
+}
+
+func (m *validationQuerier) GetProblemJudgeChangedAt(_ context.Context, _ int32) (pgtype.Timestamp, error) {
+	return m.changedAt, nil
+}
+
+func (m *validationQuerier) CreateProblemValidationResult(_ context.Context, arg db.CreateProblemValidationResultParams) error {
+	m.results = append(m.results, arg)
+	return nil
+}
+
+func (m *validationQuerier) FinishProblemValidationIfJudged(_ context.Context, _ int32) (int64, error) {
+	return m.finishRows, nil
+}
+
+func (m *validationQuerier) ListTestcasesByProblemID(_ context.Context, _ int32) ([]db.Testcase, error) {
+	return m.testcases, nil
+}
+
+func (m *validationQuerier) CreateProblemValidation(_ context.Context, _ db.CreateProblemValidationParams) (int32, error) {
+	return 5, nil
+}
+
+func (m *validationQuerier) FailProblemValidation(_ context.Context, problemValidationID int32) error {
+	m.failed = append(m.failed, problemValidationID)
+	return nil
+}
+
+func TestValidationState(t *testing.T) {
+	validatedAt := time.Date(2026, 3, 20, 10, 0, 0, 0, time.UTC)
+	validation := func(status string) *db.ProblemValidation {
+		return &db.ProblemValidation{
+			ProblemValidationID: 1,
+			Status:              status,
+			CreatedAt:           pgtype.Timestamp{Time: validatedAt, Valid: true},
+		}
+	}
+	solutions := []db.ReferenceSolution{{ReferenceSolutionID: 1}}
+	tests := []struct {
+		name string
+		q    *validationQuerier
+		want ValidationState
+	}{
+		{
+			name: "never validated",
+			q:    &validationQuerier{solutions: solutions},
+			want: ValidationMissing,
+		},
+		{
+			name: "no reference solutions",
+			q:    &validationQuerier{latest: validation("success")},
+			want: ValidationMissing,
+		},
+		{
+			name: "running",
+			q:    &validationQuerier{latest: validation("running"), solutions: solutions},
+			want: ValidationRunning,
+		},
+		{
+			name: "passed",
+			q: &validationQuerier{
+				latest:    validation("success"),
+				solutions: solutions,
+				changedAt: pgtype.Timestamp{Time: validatedAt.Add(-time.Minute), Valid: true},
+			},
+			want: ValidationPassed,
+		},
+		{
+			name: "failed",
+			q:    &validationQuerier{latest: validation("failure"), solutions: solutions},
+			want: ValidationFailed,
+		},
+		{
+			name: "changed since",
+			q: &validationQuerier{
+				latest:    validation("success"),
+				solutions: solutions,
+				changedAt: pgtype.Timestamp{Time: validatedAt.Add(time.Minute), Valid: true},
+			},
+			want: ValidationOutdated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, _, err := validationState(context.Background(), tt.q, 1)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("validationState() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestProcessTaskResultRunValidation(t *testing.T) {
+	payload := func(checkerCode string) *taskqueue.TaskPayloadRunValidation {
+		return &taskqueue.TaskPayloadRunValidation{
+			ValidationID:        1,
+			ProblemID:           2,
+			ReferenceSolutionID: 3,
+			TestcaseID:          4,
+			Stdout:              "42\n",
+			CheckerCode:         checkerCode,
+		}
+	}
+	tests := []struct {
+		name   string
+		result *taskqueue.TaskResultRunValidation
+		want   string
+	}{
+		{
+			name:   "accepted",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload(""), Status: "success", Stdout: "42"},
+			want:   "success",
+		},
+		{
+			name:   "wrong answer",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload(""), Status: "success", Stdout: "41"},
+			want:   "wrong_answer",
+		},
+		{
+			name:   "timeout",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload(""), Status: "timeout"},
+			want:   "timeout",
+		},
+		{
+			name:   "worker failure",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload(""), Err: errors.New("unreachable")},
+			want:   "internal_error",
+		},
+		{
+			name:   "special judge accepted",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload("<?php"), Status: "success", Stdout: "x", CheckerStatus: "success", CheckerStdout: "AC\n"},
+			want:   "success",
+		},
+		{
+			name:   "special judge failed",
+			result: &taskqueue.TaskResultRunValidation{TaskPayload: payload("<?php"), Status: "success", Stdout: "x", CheckerStatus: "runtime_error"},
+			want:   "internal_error",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := &validationQuerier{problem: db.Problem{ProblemID: 2, Checker: checker.Default}}
+			hub := &Hub{q: q, ctx: context.Background(), events: NewEventBroker()}
+
+			if err := hub.processTaskResultRunValidation(tt.result); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(q.results) != 1 {
+				t.Fatalf("recorded %d results, want 1", len(q.results))
+			}
+			got := q.results[0]
+			if got.ProblemValidationID != 1 || got.ReferenceSolutionID != 3 || got.TestcaseID != 4 {
+				t.Errorf("result = %+v", got)
+			}
+			if got.Status != tt.want {
+				t.Errorf("status = %q, want %q", got.Status, tt.want)
+			}
+		})
+	}
+}
+
+func TestEnqueueValidationTasks(t *testing.T) {
+	tq := &mockTaskQueue{}
+	hub := NewGameHub(&mockQuerier{}, &mockTxManager{}, tq, nil)
+	problem := db.Problem{ProblemID: 2, Language: "php", Checker: checker.Special, CheckerCode: "<?php // check"}
+	solutions := []db.ReferenceSolution{{ReferenceSolutionID: 1, Code: "a"}, {ReferenceSolutionID: 2, Code: "b"}}
+	testcases := []db.Testcase{{TestcaseID: 10, Stdin: "in", Stdout: "out"}, {TestcaseID: 11}}
+
+	if err := hub.EnqueueValidationTasks(context.Background(), 5, problem, solutions, testcases); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(tq.enqueuedValidations) != 4 {
+		t.Fatalf("enqueued %d tasks, want 4", len(tq.enqueuedValidations))
+	}
+	first := tq.enqueuedValidations[0]
+	want := taskqueue.TaskPayloadRunValidation{
+		ValidationID:        5,
+		ProblemID:           2,
+		ReferenceSolutionID: 1,
+		TestcaseID:          10,
+		Language:            "php",
+		Code:                "a",
+		Stdin:               "in",
+		Stdout:              "out",
+		CheckerCode:         "<?php // check",
+	}
+	if first != want {
+		t.Errorf("first task = %+v, want %+v", first, want)
+	}
+}
+
+func TestValidateProblem_EnqueueError(t *testing.T) {
+	q := &validationQuerier{
+		problem:   db.Problem{ProblemID: 2, Language: "php"},
+		solutions: []db.ReferenceSolution{{ReferenceSolutionID: 1, Code: "a"}},
+		testcases: []db.Testcase{{TestcaseID: 10}},
+	}
+	queueErr := errors.New("queue unavailable")
+	hub := NewGameHub(q, &mockTxManager{}, &mockTaskQueue{err: queueErr}, nil)
+	s := &Service{q: q, hub: hub}
+
+	if _, err := s.ValidateProblem(context.Background(), 2, 1); !errors.Is(err, queueErr) {
+		t.Errorf("expected queue error, got %v", err)
+	}
+	if len(q.failed) != 1 || q.failed[0] != 5 {
+		t.Errorf("expected validation 5 to be marked failed, got %v", q.failed)
+	}
+}
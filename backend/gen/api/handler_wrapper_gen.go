@@ -62,11 +62,19 @@ func main() {
 	slices.Sort(methods)
 
 	loginOptionalMethods := map[string]bool{
-		"GetGames":                 true,
-		"GetGame":                  true,
-		"GetGameWatchLatestStates": true,
-		"GetGameWatchRanking":      true,
-		"GetTournament":            true,
+		"GetGames":                  true,
+		"GetGame":                   true,
+		"GetGameSubmissionDiff":     true,
+		"GetGameWatchEvents":        true,
+		"GetGameWatchLatestStates":  true,
+		"GetGameWatchRanking":       true,
+		"GetGameWatchReplay":        true,
+		"GetGameWatchTeams":         true,
+		"GetQualifyingStageRanking": true,
+		"GetQualifyingStages":       true,
+		"GetRatings":                true,
+		"GetTournament":             true,
+		"GetUserRatingHistory":      true,
 	}
 
 	type TemplateParameter struct {
@@ -118,6 +126,8 @@ import (
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/qualifying"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/session"
 	"albatross-2026-backend/tournament"
 )
@@ -128,11 +138,13 @@ type HandlerWrapper struct {
 	impl Handler
 }
 
-func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, auth AuthenticatorInterface, queries db.Querier, conf *config.Config) *HandlerWrapper {
+func NewHandler(gameSvc *game.Service, tournamentSvc *tournament.Service, ratingSvc *rating.Service, qualifyingSvc *qualifying.Service, auth AuthenticatorInterface, queries db.Querier, conf *config.Config) *HandlerWrapper {
 	return &HandlerWrapper{
 		impl: Handler{
 			gameSvc:       gameSvc,
 			tournamentSvc: tournamentSvc,
+			ratingSvc:     ratingSvc,
+			qualifyingSvc: qualifyingSvc,
 			auth:          auth,
 			conf:          conf,
 			q:             queries,
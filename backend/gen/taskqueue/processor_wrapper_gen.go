@@ -121,7 +121,7 @@ func newProcessorWrapper(impl processor) *processorWrapper {
 			maxRetry, _ := asynq.GetMaxRetry(ctx)
 			isRecoverable := !errors.Is(err, asynq.SkipRetry) && retryCount < maxRetry
 			if !isRecoverable {
-				p.results <- &TaskResult{{ . }}{Err: err}
+				p.results <- &TaskResult{{ . }}{TaskPayload: &payload, Err: err}
 			}
 			return err
 		}
@@ -21,8 +21,12 @@ import (
 	"albatross-2026-backend/auth"
 	"albatross-2026-backend/config"
 	"albatross-2026-backend/db"
+	"albatross-2026-backend/export"
 	"albatross-2026-backend/game"
+	"albatross-2026-backend/problempkg"
+	"albatross-2026-backend/qualifying"
 	"albatross-2026-backend/ratelimit"
+	"albatross-2026-backend/rating"
 	"albatross-2026-backend/taskqueue"
 	"albatross-2026-backend/tournament"
 )
@@ -95,23 +99,34 @@ func main() {
 	e.Use(middleware.Recover())
 
 	taskQueue := taskqueue.NewQueue("task-db:6379")
-	workerServer := taskqueue.NewWorkerServer("task-db:6379")
+	workers, err := taskqueue.NewWorkerRegistry(conf.Workers, conf.WorkerSelection)
+	if err != nil {
+		slog.Error("failed to load workers", "error", err)
+		os.Exit(1)
+	}
+	workerServer := taskqueue.NewWorkerServer("task-db:6379", workers)
 
 	gameHub := game.NewGameHub(queries, txm, taskQueue, workerServer)
 
 	loginRL := ratelimit.NewIPRateLimiter(rate.Every(time.Minute/5), 5)
+	runRL := ratelimit.NewIPRateLimiter(rate.Every(time.Minute/10), 5)
 
 	apiGroup := e.Group(conf.BasePath + "api")
 	apiGroup.Use(api.ClientIPMiddleware())
 	apiGroup.Use(ratelimit.LoginRateLimitMiddleware(loginRL))
 	apiGroup.Use(api.SessionCookieMiddleware(queries))
+	apiGroup.Use(ratelimit.RunRateLimitMiddleware(runRL))
 	apiGroup.Use(oapimiddleware.OapiRequestValidator(openAPISpec))
 	gameSvc := game.NewService(queries, txm, gameHub)
 	tournamentSvc := tournament.NewService(queries, txm)
-	apiHandler := api.NewHandler(gameSvc, tournamentSvc, authenticator, queries, conf)
+	ratingSvc := rating.NewService(queries, txm)
+	qualifyingSvc := qualifying.NewService(queries, txm)
+	apiHandler := api.NewHandler(gameSvc, tournamentSvc, ratingSvc, qualifyingSvc, authenticator, queries, conf)
 	api.RegisterHandlers(apiGroup, api.NewStrictHandler(apiHandler, nil))
 
-	adminHandler := admin.NewHandler(gameSvc, tournamentSvc, queries, conf)
+	exportSvc := export.NewService(queries)
+	problemPkgSvc := problempkg.NewService(queries, txm)
+	adminHandler := admin.NewHandler(gameSvc, tournamentSvc, ratingSvc, qualifyingSvc, exportSvc, problemPkgSvc, workers, queries, conf)
 	adminGroup := e.Group(conf.BasePath + "admin")
 	adminGroup.Use(api.SessionCookieMiddleware(queries))
 	adminHandler.RegisterHandlers(adminGroup)
@@ -162,6 +177,27 @@ func main() {
 		}
 	}()
 
+	ratingCtx, cancelRating := context.WithCancel(context.Background())
+	defer cancelRating()
+	go func() {
+		ticker := time.NewTicker(time.Minute)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ratingCtx.Done():
+				return
+			case <-ticker.C:
+				if _, err := ratingSvc.RateFinishedGames(ratingCtx); err != nil {
+					slog.Error("failed to rate finished games", "error", err)
+				}
+			}
+		}
+	}()
+
+	healthCheckCtx, cancelHealthCheck := context.WithCancel(context.Background())
+	defer cancelHealthCheck()
+	go workers.RunHealthChecks(healthCheckCtx, 10*time.Second)
+
 	go gameHub.Run()
 
 	if err := e.Start(":80"); err != http.ErrServerClosed {
@@ -0,0 +1,48 @@
+-- Moves the problem of each game to game_problems, and sets it as the problem
+-- of the submissions and the states of the game, before games.problem_id is
+-- dropped. Does nothing once it has been dropped.
+DO $$
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'games' AND column_name = 'problem_id'
+    ) THEN
+        RETURN;
+    END IF;
+
+    CREATE TABLE IF NOT EXISTS game_problems (
+        game_id    INT NOT NULL,
+        problem_id INT NOT NULL,
+        position   INT NOT NULL,
+        PRIMARY KEY (game_id, problem_id),
+        CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+        CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
+        CONSTRAINT uq_game_id_position UNIQUE(game_id, position)
+    );
+    CREATE INDEX IF NOT EXISTS idx_game_problems_problem_id ON game_problems(problem_id);
+    INSERT INTO game_problems (game_id, problem_id, position)
+    SELECT game_id, problem_id, 0 FROM games
+    ON CONFLICT DO NOTHING;
+
+    ALTER TABLE submissions ADD COLUMN IF NOT EXISTS problem_id INT;
+    UPDATE submissions
+    SET problem_id = games.problem_id
+    FROM games
+    WHERE submissions.game_id = games.game_id AND submissions.problem_id IS NULL;
+    ALTER TABLE submissions ALTER COLUMN problem_id SET NOT NULL;
+    ALTER TABLE submissions ADD CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id);
+    CREATE INDEX IF NOT EXISTS idx_submissions_problem_id ON submissions(problem_id);
+
+    ALTER TABLE game_states ADD COLUMN IF NOT EXISTS problem_id INT;
+    UPDATE game_states
+    SET problem_id = games.problem_id
+    FROM games
+    WHERE game_states.game_id = games.game_id AND game_states.problem_id IS NULL;
+    ALTER TABLE game_states ALTER COLUMN problem_id SET NOT NULL;
+    ALTER TABLE game_states ADD CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id);
+    ALTER TABLE game_states DROP CONSTRAINT game_states_pkey;
+    ALTER TABLE game_states ADD PRIMARY KEY (game_id, user_id, problem_id);
+
+    ALTER TABLE games DROP COLUMN problem_id;
+END
+$$;
@@ -0,0 +1,81 @@
+-- Puts each player who has played a game in a team of their own, as the
+-- server does when they first open it, and keys the submissions and the
+-- states of the game by the team before game_states.user_id is dropped. Does
+-- nothing once it has been dropped.
+DO $$
+DECLARE
+    player RECORD;
+    new_team_id INT;
+BEGIN
+    IF NOT EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'game_states' AND column_name = 'user_id'
+    ) THEN
+        RETURN;
+    END IF;
+
+    CREATE TABLE IF NOT EXISTS game_teams (
+        team_id      SERIAL       PRIMARY KEY,
+        game_id      INT          NOT NULL,
+        display_name VARCHAR(255) NOT NULL,
+        CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id)
+    );
+    CREATE INDEX IF NOT EXISTS idx_game_teams_game_id ON game_teams(game_id);
+    CREATE TABLE IF NOT EXISTS game_team_members (
+        team_id INT NOT NULL,
+        game_id INT NOT NULL,
+        user_id INT NOT NULL,
+        PRIMARY KEY (team_id, user_id),
+        CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id),
+        CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+        CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+        CONSTRAINT uq_game_id_user_id UNIQUE(game_id, user_id)
+    );
+
+    FOR player IN
+        SELECT players.game_id, players.user_id, users.display_name
+        FROM (
+            SELECT game_id, user_id FROM submissions
+            UNION
+            SELECT game_id, user_id FROM game_states
+        ) AS players
+        JOIN users ON users.user_id = players.user_id
+        WHERE NOT EXISTS (
+            SELECT 1 FROM game_team_members
+            WHERE game_team_members.game_id = players.game_id AND game_team_members.user_id = players.user_id
+        )
+        ORDER BY players.game_id, players.user_id
+    LOOP
+        INSERT INTO game_teams (game_id, display_name)
+        VALUES (player.game_id, player.display_name)
+        RETURNING team_id INTO new_team_id;
+        INSERT INTO game_team_members (team_id, game_id, user_id)
+        VALUES (new_team_id, player.game_id, player.user_id);
+    END LOOP;
+
+    ALTER TABLE submissions ADD COLUMN IF NOT EXISTS team_id INT;
+    UPDATE submissions
+    SET team_id = game_team_members.team_id
+    FROM game_team_members
+    WHERE submissions.game_id = game_team_members.game_id
+        AND submissions.user_id = game_team_members.user_id
+        AND submissions.team_id IS NULL;
+    ALTER TABLE submissions ALTER COLUMN team_id SET NOT NULL;
+    ALTER TABLE submissions ADD CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id);
+    DROP INDEX IF EXISTS idx_submissions_game_id_user_id;
+    CREATE INDEX IF NOT EXISTS idx_submissions_game_id_team_id ON submissions(game_id, team_id);
+
+    ALTER TABLE game_states ADD COLUMN IF NOT EXISTS team_id INT;
+    UPDATE game_states
+    SET team_id = game_team_members.team_id
+    FROM game_team_members
+    WHERE game_states.game_id = game_team_members.game_id
+        AND game_states.user_id = game_team_members.user_id
+        AND game_states.team_id IS NULL;
+    ALTER TABLE game_states ALTER COLUMN team_id SET NOT NULL;
+    ALTER TABLE game_states ADD CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id);
+    ALTER TABLE game_states DROP CONSTRAINT game_states_pkey;
+    ALTER TABLE game_states DROP COLUMN user_id;
+    ALTER TABLE game_states ADD PRIMARY KEY (game_id, team_id, problem_id);
+END
+$$;
@@ -0,0 +1,27 @@
+-- Sets the language of the existing submissions and states of games to that
+-- of their problem, which was the only one accepted before. Does nothing once
+-- submissions.language exists.
+DO $$
+BEGIN
+    IF EXISTS (
+        SELECT 1 FROM information_schema.columns
+        WHERE table_schema = current_schema() AND table_name = 'submissions' AND column_name = 'language'
+    ) THEN
+        RETURN;
+    END IF;
+
+    ALTER TABLE submissions ADD COLUMN language VARCHAR(8);
+    UPDATE submissions
+    SET language = problems.language
+    FROM problems
+    WHERE submissions.problem_id = problems.problem_id;
+    ALTER TABLE submissions ALTER COLUMN language SET NOT NULL;
+
+    ALTER TABLE game_states ADD COLUMN IF NOT EXISTS language VARCHAR(8);
+    UPDATE game_states
+    SET language = problems.language
+    FROM problems
+    WHERE game_states.problem_id = problems.problem_id AND game_states.language IS NULL;
+    ALTER TABLE game_states ALTER COLUMN language SET NOT NULL;
+END
+$$;
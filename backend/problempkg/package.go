@@ -0,0 +1,459 @@
+// Package problempkg reads and writes problem packages, a portable form of a
+// problem and its testcases that can be kept under version control and moved
+// between deployments. A package is a directory, or a zip of one, laid out as
+//
+//	manifest.json    title and judge settings, see Manifest
+//	statement.md     description shown to players
+//	sample.php       sample code, named after the language
+//	sample.swift     sample code for each of the other languages, if any
+//	checker.php      checker program, only for the special checker
+//	testcases/1.in   input of testcase 1
+//	testcases/1.out  expected output of testcase 1
+//
+// The files other than the manifest and the testcases are named by the
+// manifest. Testcases are numbered from 1 in the order they are judged, and
+// their numbers may be zero-padded, e.g. 01.in.
+package problempkg
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"albatross-2026-backend/checker"
+	"albatross-2026-backend/game"
+	"albatross-2026-backend/scoring"
+)
+
+const (
+	manifestName = "manifest.json"
+	testcaseDir  = "testcases"
+	// maxFileSize bounds the size of each file read from a package, so that
+	// a malformed zip cannot exhaust the memory.
+	maxFileSize = 64 << 20
+)
+
+var ErrInvalidPackage = errors.New("invalid problem package")
+
+// Manifest is the content of manifest.json.
+type Manifest struct {
+	Title          string  `json:"title"`
+	Language       string  `json:"language"`
+	Scoring        string  `json:"scoring"`
+	Checker        string  `json:"checker"`
+	CheckerEpsilon float64 `json:"checker_epsilon,omitempty"`
+	// TimeLimitMs and MemoryLimitMiB bound running the code for each
+	// testcase. If omitted, the defaults of the workers apply.
+	TimeLimitMs    *int `json:"time_limit_ms,omitempty"`
+	MemoryLimitMiB *int `json:"memory_limit_mib,omitempty"`
+	// Statement, SampleCode and CheckerCode are the paths of the files in the
+	// package, relative to its root.
+	Statement   string `json:"statement"`
+	SampleCode  string `json:"sample_code"`
+	CheckerCode string `json:"checker_code,omitempty"`
+	// Samples are the numbers of the testcases shown to players as samples.
+	Samples []int `json:"samples"`
+	// OtherLanguages are the languages players may choose instead of
+	// Language.
+	OtherLanguages []ManifestLanguage `json:"other_languages,omitempty"`
+	// TestcaseLimits override the limits of the problem for some testcases.
+	TestcaseLimits []ManifestTestcaseLimits `json:"testcase_limits,omitempty"`
+}
+
+// ManifestTestcaseLimits are the limits of the testcase numbered Testcase in
+// manifest.json. An omitted limit is that of the problem.
+type ManifestTestcaseLimits struct {
+	Testcase       int  `json:"testcase"`
+	TimeLimitMs    *int `json:"time_limit_ms,omitempty"`
+	MemoryLimitMiB *int `json:"memory_limit_mib,omitempty"`
+}
+
+// ManifestLanguage is a language of the problem other than its own one in
+// manifest.json. SampleCode is the path of the file.
+type ManifestLanguage struct {
+	Language   string `json:"language"`
+	SampleCode string `json:"sample_code"`
+}
+
+// Package is a problem with its testcases.
+type Package struct {
+	Title          string
+	Description    string
+	Language       string
+	SampleCode     string
+	Scoring        string
+	Checker        string
+	CheckerEpsilon float64
+	CheckerCode    string
+	// TimeLimitMs and MemoryLimitMiB are nil for the defaults of the workers.
+	TimeLimitMs    *int
+	MemoryLimitMiB *int
+	// OtherLanguages are the languages players may choose instead of
+	// Language, with the sample code for each.
+	OtherLanguages []Language
+	Testcases      []Testcase
+}
+
+type Language struct {
+	Language   string
+	SampleCode string
+}
+
+type Testcase struct {
+	Stdin    string
+	Stdout   string
+	IsSample bool
+	// TimeLimitMs and MemoryLimitMiB are nil for the limits of the problem.
+	TimeLimitMs    *int
+	MemoryLimitMiB *int
+}
+
+// Validate reports whether the settings of the problem are ones the judge
+// supports.
+func (p Package) Validate() error {
+	if p.Title == "" {
+		return fmt.Errorf("%w: title is empty", ErrInvalidPackage)
+	}
+	if !slices.Contains(game.Languages, p.Language) {
+		return fmt.Errorf("%w: unsupported language %q", ErrInvalidPackage, p.Language)
+	}
+	if _, err := scoring.New(p.Scoring); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	if !scoring.SupportsLanguage(p.Scoring, p.Language) {
+		return fmt.Errorf("%w: scoring %s does not support %s", ErrInvalidPackage, p.Scoring, p.Language)
+	}
+	for i, l := range p.OtherLanguages {
+		if !slices.Contains(game.Languages, l.Language) {
+			return fmt.Errorf("%w: unsupported language %q", ErrInvalidPackage, l.Language)
+		}
+		if l.Language == p.Language || slices.ContainsFunc(p.OtherLanguages[:i], func(o Language) bool { return o.Language == l.Language }) {
+			return fmt.Errorf("%w: language %s is given twice", ErrInvalidPackage, l.Language)
+		}
+		if !scoring.SupportsLanguage(p.Scoring, l.Language) {
+			return fmt.Errorf("%w: scoring %s does not support %s", ErrInvalidPackage, p.Scoring, l.Language)
+		}
+	}
+	if !checker.IsValid(p.Checker) {
+		return fmt.Errorf("%w: unknown checker %q", ErrInvalidPackage, p.Checker)
+	}
+	if !(p.CheckerEpsilon >= 0) {
+		return fmt.Errorf("%w: invalid checker_epsilon", ErrInvalidPackage)
+	}
+	if p.Checker == checker.Special && p.CheckerCode == "" {
+		return fmt.Errorf("%w: checker code is required for the special checker", ErrInvalidPackage)
+	}
+	if !validLimit(p.TimeLimitMs) || !validLimit(p.MemoryLimitMiB) {
+		return fmt.Errorf("%w: limits must be positive", ErrInvalidPackage)
+	}
+	for i, t := range p.Testcases {
+		if !validLimit(t.TimeLimitMs) || !validLimit(t.MemoryLimitMiB) {
+			return fmt.Errorf("%w: limits of testcase %d must be positive", ErrInvalidPackage, i+1)
+		}
+	}
+	return nil
+}
+
+func validLimit(limit *int) bool {
+	return limit == nil || *limit > 0
+}
+
+// Read reads the package at the root of fsys. If the root holds nothing but a
+// directory, as zips made from a directory do, the package is read from it.
+func Read(fsys fs.FS) (Package, error) {
+	root, err := packageRoot(fsys)
+	if err != nil {
+		return Package{}, err
+	}
+	fsys, err = fs.Sub(fsys, root)
+	if err != nil {
+		return Package{}, err
+	}
+
+	var m Manifest
+	raw, err := readFile(fsys, manifestName)
+	if err != nil {
+		return Package{}, err
+	}
+	if err := json.Unmarshal([]byte(raw), &m); err != nil {
+		return Package{}, fmt.Errorf("%w: %s: %w", ErrInvalidPackage, manifestName, err)
+	}
+	if m.Scoring == "" {
+		m.Scoring = scoring.Default
+	}
+	if m.Checker == "" {
+		m.Checker = checker.Default
+	}
+	p := Package{
+		Title:          m.Title,
+		Language:       m.Language,
+		Scoring:        m.Scoring,
+		Checker:        m.Checker,
+		CheckerEpsilon: m.CheckerEpsilon,
+		TimeLimitMs:    m.TimeLimitMs,
+		MemoryLimitMiB: m.MemoryLimitMiB,
+	}
+	if p.Description, err = readFile(fsys, m.Statement); err != nil {
+		return Package{}, err
+	}
+	if p.SampleCode, err = readFile(fsys, m.SampleCode); err != nil {
+		return Package{}, err
+	}
+	if m.CheckerCode != "" {
+		if p.CheckerCode, err = readFile(fsys, m.CheckerCode); err != nil {
+			return Package{}, err
+		}
+	}
+	for _, l := range m.OtherLanguages {
+		sampleCode, err := readFile(fsys, l.SampleCode)
+		if err != nil {
+			return Package{}, err
+		}
+		p.OtherLanguages = append(p.OtherLanguages, Language{Language: l.Language, SampleCode: sampleCode})
+	}
+	if p.Testcases, err = readTestcases(fsys, m.Samples); err != nil {
+		return Package{}, err
+	}
+	for i, l := range m.TestcaseLimits {
+		if l.Testcase < 1 || l.Testcase > len(p.Testcases) {
+			return Package{}, fmt.Errorf("%w: limits for %d, which is not a testcase", ErrInvalidPackage, l.Testcase)
+		}
+		if slices.ContainsFunc(m.TestcaseLimits[:i], func(o ManifestTestcaseLimits) bool { return o.Testcase == l.Testcase }) {
+			return Package{}, fmt.Errorf("%w: limits for testcase %d are given twice", ErrInvalidPackage, l.Testcase)
+		}
+		t := &p.Testcases[l.Testcase-1]
+		t.TimeLimitMs, t.MemoryLimitMiB = l.TimeLimitMs, l.MemoryLimitMiB
+	}
+	if err := p.Validate(); err != nil {
+		return Package{}, err
+	}
+	return p, nil
+}
+
+// ReadZip reads a package from a zip of its directory.
+func ReadZip(r io.ReaderAt, size int64) (Package, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return Package{}, fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	return Read(zr)
+}
+
+func packageRoot(fsys fs.FS) (string, error) {
+	if _, err := fs.Stat(fsys, manifestName); err == nil {
+		return ".", nil
+	}
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	if len(entries) == 1 && entries[0].IsDir() {
+		if _, err := fs.Stat(fsys, path.Join(entries[0].Name(), manifestName)); err == nil {
+			return entries[0].Name(), nil
+		}
+	}
+	return "", fmt.Errorf("%w: %s not found", ErrInvalidPackage, manifestName)
+}
+
+func readFile(fsys fs.FS, name string) (string, error) {
+	if name == "" || !fs.ValidPath(name) {
+		return "", fmt.Errorf("%w: invalid path %q", ErrInvalidPackage, name)
+	}
+	f, err := fsys.Open(name)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	defer f.Close()
+	b, err := io.ReadAll(io.LimitReader(f, maxFileSize+1))
+	if err != nil {
+		return "", fmt.Errorf("%w: %s: %w", ErrInvalidPackage, name, err)
+	}
+	if len(b) > maxFileSize {
+		return "", fmt.Errorf("%w: %s is too large", ErrInvalidPackage, name)
+	}
+	return string(b), nil
+}
+
+// readTestcases reads the numbered input and output files. The numbers may
+// be zero-padded, but must run from 1 without gaps, and every input needs an
+// output.
+func readTestcases(fsys fs.FS, samples []int) ([]Testcase, error) {
+	entries, err := fs.ReadDir(fsys, testcaseDir)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidPackage, err)
+	}
+	inputs := make(map[int]string)
+	outputs := make(map[int]string)
+	for _, e := range entries {
+		name := e.Name()
+		files := inputs
+		num, ok := strings.CutSuffix(name, ".in")
+		if !ok {
+			files = outputs
+			num, ok = strings.CutSuffix(name, ".out")
+		}
+		n, err := strconv.Atoi(num)
+		if !ok || err != nil || n < 1 {
+			return nil, fmt.Errorf("%w: unexpected file %s/%s", ErrInvalidPackage, testcaseDir, name)
+		}
+		if _, dup := files[n]; dup {
+			return nil, fmt.Errorf("%w: testcase %d is given twice", ErrInvalidPackage, n)
+		}
+		files[n] = path.Join(testcaseDir, name)
+	}
+	count := max(len(inputs), len(outputs))
+	for _, n := range samples {
+		if n < 1 || n > count {
+			return nil, fmt.Errorf("%w: sample %d is not a testcase", ErrInvalidPackage, n)
+		}
+	}
+
+	testcases := make([]Testcase, count)
+	for i := range testcases {
+		n := i + 1
+		in, okIn := inputs[n]
+		out, okOut := outputs[n]
+		if !okIn || !okOut {
+			return nil, fmt.Errorf("%w: testcase %d lacks its input or output", ErrInvalidPackage, n)
+		}
+		stdin, err := readFile(fsys, in)
+		if err != nil {
+			return nil, err
+		}
+		stdout, err := readFile(fsys, out)
+		if err != nil {
+			return nil, err
+		}
+		testcases[i] = Testcase{
+			Stdin:    stdin,
+			Stdout:   stdout,
+			IsSample: slices.Contains(samples, n),
+		}
+	}
+	return testcases, nil
+}
+
+// files returns the files of the package by their path, in the order they are
+// written.
+func (p Package) files() ([]string, map[string]string, error) {
+	ext := languageExt(p.Language)
+	m := Manifest{
+		Title:          p.Title,
+		Language:       p.Language,
+		Scoring:        p.Scoring,
+		Checker:        p.Checker,
+		CheckerEpsilon: p.CheckerEpsilon,
+		TimeLimitMs:    p.TimeLimitMs,
+		MemoryLimitMiB: p.MemoryLimitMiB,
+		Statement:      "statement.md",
+		SampleCode:     "sample" + ext,
+		Samples:        []int{},
+	}
+	if p.CheckerCode != "" {
+		m.CheckerCode = "checker" + ext
+	}
+	for _, l := range p.OtherLanguages {
+		m.OtherLanguages = append(m.OtherLanguages, ManifestLanguage{
+			Language:   l.Language,
+			SampleCode: "sample" + languageExt(l.Language),
+		})
+	}
+	for i, t := range p.Testcases {
+		if t.IsSample {
+			m.Samples = append(m.Samples, i+1)
+		}
+		if t.TimeLimitMs != nil || t.MemoryLimitMiB != nil {
+			m.TestcaseLimits = append(m.TestcaseLimits, ManifestTestcaseLimits{
+				Testcase:       i + 1,
+				TimeLimitMs:    t.TimeLimitMs,
+				MemoryLimitMiB: t.MemoryLimitMiB,
+			})
+		}
+	}
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	names := []string{manifestName, m.Statement, m.SampleCode}
+	files := map[string]string{
+		manifestName: string(manifest) + "\n",
+		m.Statement:  p.Description,
+		m.SampleCode: p.SampleCode,
+	}
+	for i, l := range m.OtherLanguages {
+		names = append(names, l.SampleCode)
+		files[l.SampleCode] = p.OtherLanguages[i].SampleCode
+	}
+	if m.CheckerCode != "" {
+		names = append(names, m.CheckerCode)
+		files[m.CheckerCode] = p.CheckerCode
+	}
+	for i, t := range p.Testcases {
+		n := strconv.Itoa(i + 1)
+		in := path.Join(testcaseDir, n+".in")
+		out := path.Join(testcaseDir, n+".out")
+		names = append(names, in, out)
+		files[in] = t.Stdin
+		files[out] = t.Stdout
+	}
+	return names, files, nil
+}
+
+// WriteZip writes the package to w as a zip.
+func (p Package) WriteZip(w io.Writer) error {
+	names, files, err := p.files()
+	if err != nil {
+		return err
+	}
+	zw := zip.NewWriter(w)
+	for _, name := range names {
+		f, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(f, files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// WriteDir writes the package to the directory, creating it if needed. The
+// files of the package already in the directory are overwritten.
+func (p Package) WriteDir(dir string) error {
+	names, files, err := p.files()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		dst := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(dst, []byte(files[name]), 0o644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func languageExt(language string) string {
+	switch language {
+	case "php":
+		return ".php"
+	case "swift":
+		return ".swift"
+	default:
+		return ".txt"
+	}
+}
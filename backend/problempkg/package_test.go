@@ -0,0 +1,247 @@
+package problempkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"errors"
+	"os"
+	"reflect"
+	"testing"
+	"testing/fstest"
+)
+
+func testPackage() Package {
+	timeLimitMs, memoryLimitMiB := 2000, 64
+	return Package{
+		Title:          "Hello",
+		Description:    "Print `Hello`.",
+		Language:       "php",
+		SampleCode:     "<?php\necho 'Hello';\n",
+		Scoring:        "stripped_bytes",
+		Checker:        "float",
+		CheckerEpsilon: 1e-6,
+		TimeLimitMs:    &timeLimitMs,
+		Testcases: []Testcase{
+			{Stdin: "", Stdout: "Hello\n", IsSample: true},
+			{Stdin: "1\n", Stdout: "Hello\n", MemoryLimitMiB: &memoryLimitMiB},
+		},
+	}
+}
+
+func TestWriteZip_RoundTrip(t *testing.T) {
+	want := testPackage()
+	var buf bytes.Buffer
+	if err := want.WriteZip(&buf); err != nil {
+		t.Fatalf("WriteZip: %v", err)
+	}
+	got, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadZip: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestWriteDir_RoundTrip(t *testing.T) {
+	want := testPackage()
+	want.Checker = "special"
+	want.CheckerEpsilon = 0
+	want.CheckerCode = "<?php\n// check\n"
+	want.OtherLanguages = []Language{{Language: "swift", SampleCode: "print(\"Hello\")\n"}}
+	dir := t.TempDir()
+	if err := want.WriteDir(dir); err != nil {
+		t.Fatalf("WriteDir: %v", err)
+	}
+	got, err := Read(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("Read: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
+
+func TestReadZip_NestedDirectory(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	files := map[string]string{
+		"hello/manifest.json":    `{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "samples": [1]}`,
+		"hello/statement.md":     "Print Hello.",
+		"hello/sample.php":       "<?php",
+		"hello/testcases/1.in":   "",
+		"hello/testcases/1.out":  "Hello",
+		"hello/testcases/02.in":  "2",
+		"hello/testcases/02.out": "Hello",
+	}
+	for name, content := range files {
+		f, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := f.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ReadZip(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("ReadZip: %v", err)
+	}
+	want := []Testcase{
+		{Stdin: "", Stdout: "Hello", IsSample: true},
+		{Stdin: "2", Stdout: "Hello"},
+	}
+	if !reflect.DeepEqual(got.Testcases, want) {
+		t.Errorf("testcases = %+v, want %+v", got.Testcases, want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	manifest := `{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "samples": [1]}`
+	tests := []struct {
+		name    string
+		files   fstest.MapFS
+		wantErr bool
+	}{
+		{
+			name: "defaults",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(manifest)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+		},
+		{
+			name: "missing output",
+			files: fstest.MapFS{
+				"manifest.json":  {Data: []byte(manifest)},
+				"statement.md":   {Data: []byte("Print Hello.")},
+				"sample.php":     {Data: []byte("<?php")},
+				"testcases/1.in": {Data: []byte("")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "gap in numbers",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(manifest)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+				"testcases/3.in":  {Data: []byte("")},
+				"testcases/3.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "sample out of range",
+			files: fstest.MapFS{
+				"manifest.json": {Data: []byte(manifest)},
+				"statement.md":  {Data: []byte("Print Hello.")},
+				"sample.php":    {Data: []byte("<?php")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "path outside the package",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "php", "statement": "../statement.md", "sample_code": "sample.php"}`)},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "language given twice",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "other_languages": [{"language": "php", "sample_code": "sample.php"}]}`)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported language",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "ruby", "statement": "statement.md", "sample_code": "sample.php"}`)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported other language",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "other_languages": [{"language": "ruby", "sample_code": "sample.php"}]}`)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "limits for unknown testcase",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "testcase_limits": [{"testcase": 2, "time_limit_ms": 1000}]}`)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "zero limit",
+			files: fstest.MapFS{
+				"manifest.json":   {Data: []byte(`{"title": "Hello", "language": "php", "statement": "statement.md", "sample_code": "sample.php", "memory_limit_mib": 0}`)},
+				"statement.md":    {Data: []byte("Print Hello.")},
+				"sample.php":      {Data: []byte("<?php")},
+				"testcases/1.in":  {Data: []byte("")},
+				"testcases/1.out": {Data: []byte("Hello")},
+			},
+			wantErr: true,
+		},
+		{
+			name: "unsupported scoring",
+			files: fstest.MapFS{
+				"manifest.json": {Data: []byte(`{"title": "Hello", "language": "swift", "scoring": "php_tokens", "statement": "statement.md", "sample_code": "sample.php"}`)},
+				"statement.md":  {Data: []byte("Print Hello.")},
+				"sample.php":    {Data: []byte("<?php")},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p, err := Read(tt.files)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidPackage) {
+					t.Errorf("err = %v, want ErrInvalidPackage", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Read: %v", err)
+			}
+			if p.Scoring != "stripped_bytes" || p.Checker != "exact" {
+				t.Errorf("scoring, checker = %q, %q, want defaults", p.Scoring, p.Checker)
+			}
+			if len(p.Testcases) != 1 || !p.Testcases[0].IsSample {
+				t.Errorf("testcases = %+v", p.Testcases)
+			}
+		})
+	}
+}
@@ -0,0 +1,136 @@
+package problempkg
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/game"
+)
+
+type Service struct {
+	q   db.Querier
+	txm db.TxManager
+}
+
+func NewService(q db.Querier, txm db.TxManager) *Service {
+	return &Service{q: q, txm: txm}
+}
+
+// Export returns the package of the problem.
+func (s *Service) Export(ctx context.Context, problemID int) (Package, error) {
+	row, err := s.q.GetProblemByID(ctx, int32(problemID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Package{}, game.ErrNotFound
+		}
+		return Package{}, err
+	}
+	testcases, err := s.q.ListTestcasesByProblemID(ctx, row.ProblemID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Package{}, err
+	}
+	others, err := s.q.ListProblemLanguages(ctx, []int32{row.ProblemID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Package{}, err
+	}
+	p := Package{
+		Title:          row.Title,
+		Description:    row.Description,
+		Language:       row.Language,
+		SampleCode:     row.SampleCode,
+		Scoring:        row.Scoring,
+		Checker:        row.Checker,
+		CheckerEpsilon: row.CheckerEpsilon,
+		CheckerCode:    row.CheckerCode,
+		TimeLimitMs:    intPtr(row.TimeLimitMs),
+		MemoryLimitMiB: intPtr(row.MemoryLimitMib),
+		Testcases:      make([]Testcase, len(testcases)),
+	}
+	for _, l := range others {
+		p.OtherLanguages = append(p.OtherLanguages, Language{Language: l.Language, SampleCode: l.SampleCode})
+	}
+	for i, t := range testcases {
+		p.Testcases[i] = Testcase{
+			Stdin:          t.Stdin,
+			Stdout:         t.Stdout,
+			IsSample:       t.IsSample,
+			TimeLimitMs:    intPtr(t.TimeLimitMs),
+			MemoryLimitMiB: intPtr(t.MemoryLimitMib),
+		}
+	}
+	return p, nil
+}
+
+// Import creates a new problem from the package and returns its ID. Existing
+// problems are never overwritten, as their testcases may have been judged
+// already.
+func (s *Service) Import(ctx context.Context, p Package) (int, error) {
+	if err := p.Validate(); err != nil {
+		return 0, err
+	}
+	var problemID int32
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		problemID, err = qtx.CreateProblem(ctx, db.CreateProblemParams{
+			Title:          p.Title,
+			Description:    p.Description,
+			Language:       p.Language,
+			SampleCode:     p.SampleCode,
+			Scoring:        p.Scoring,
+			Checker:        p.Checker,
+			CheckerEpsilon: p.CheckerEpsilon,
+			CheckerCode:    p.CheckerCode,
+			TimeLimitMs:    int32Ptr(p.TimeLimitMs),
+			MemoryLimitMib: int32Ptr(p.MemoryLimitMiB),
+		})
+		if err != nil {
+			return err
+		}
+		for _, l := range p.OtherLanguages {
+			if err := qtx.CreateProblemLanguage(ctx, db.CreateProblemLanguageParams{
+				ProblemID:  problemID,
+				Language:   l.Language,
+				SampleCode: l.SampleCode,
+			}); err != nil {
+				return err
+			}
+		}
+		for _, t := range p.Testcases {
+			_, err := qtx.CreateTestcase(ctx, db.CreateTestcaseParams{
+				ProblemID:      problemID,
+				Stdin:          t.Stdin,
+				Stdout:         t.Stdout,
+				IsSample:       t.IsSample,
+				TimeLimitMs:    int32Ptr(t.TimeLimitMs),
+				MemoryLimitMib: int32Ptr(t.MemoryLimitMiB),
+			})
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return int(problemID), nil
+}
+
+func intPtr(v *int32) *int {
+	if v == nil {
+		return nil
+	}
+	i := int(*v)
+	return &i
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	i := int32(*v)
+	return &i
+}
@@ -0,0 +1,139 @@
+// Package qualifying ranks players over a stage of several games, e.g. the
+// online rounds that decide who advances to the finals. A player's total is
+// the weighted sum of their scores in the games, lower being better.
+package qualifying
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"albatross-2026-backend/game"
+)
+
+const (
+	// MissingPenalty counts a game the player has no score in as the
+	// penalty of the stage.
+	MissingPenalty = "penalty"
+	// MissingExclude leaves out the players who have no score in some game.
+	MissingExclude = "exclude"
+)
+
+// MissingPolicies lists all the missing-score policies in the order shown to
+// admins.
+var MissingPolicies = []string{MissingPenalty, MissingExclude}
+
+var ErrUnknownMissingPolicy = errors.New("unknown missing-score policy")
+
+// ErrGameNotPublic tells that a game that is not public was added to a public
+// stage, which would show its ranking to everyone.
+var ErrGameNotPublic = errors.New("game is not public")
+
+func validateMissingPolicy(name string) error {
+	if !slices.Contains(MissingPolicies, name) {
+		return fmt.Errorf("%w: %q", ErrUnknownMissingPolicy, name)
+	}
+	return nil
+}
+
+// Stage is a list of games ranked together.
+type Stage struct {
+	StageID        int
+	DisplayName    string
+	IsPublic       bool
+	MissingPolicy  string
+	MissingPenalty int
+	Games          []StageGame
+}
+
+// StageGame is a game of a stage. Its scores are multiplied by Weight.
+type StageGame struct {
+	GameID      int
+	DisplayName string
+	Weight      float64
+}
+
+// GameResult is the score of a player in a game: the score of their team.
+type GameResult struct {
+	Score       int
+	SubmittedAt time.Time
+}
+
+// Entry is a player in the ranking of a stage.
+type Entry struct {
+	// Players with the same total and the same SubmittedAt share a rank.
+	Rank   int
+	Player game.Player
+	// Results are in the order of the games of the stage. They are nil for
+	// the games the player has no score in.
+	Results []*GameResult
+	Total   float64
+	// SubmittedAt is the time of the last submission among Results.
+	SubmittedAt time.Time
+}
+
+// aggregate ranks the players of the stage. teams holds the ranking of each
+// game of the stage in order.
+func aggregate(stage Stage, teams [][]game.RankedTeam) []Entry {
+	var entries []Entry
+	index := make(map[int32]int)
+	for i, ranked := range teams {
+		for _, t := range ranked {
+			for _, u := range t.Members {
+				j, ok := index[u.UserID]
+				if !ok {
+					j = len(entries)
+					index[u.UserID] = j
+					entries = append(entries, Entry{
+						Player:  game.PlayerFromUser(u),
+						Results: make([]*GameResult, len(stage.Games)),
+					})
+				}
+				entries[j].Results[i] = &GameResult{
+					Score:       t.Score,
+					SubmittedAt: t.SubmittedAt,
+				}
+			}
+		}
+	}
+
+	kept := entries[:0]
+	for _, e := range entries {
+		missing := false
+		for i, r := range e.Results {
+			weight := stage.Games[i].Weight
+			if r == nil {
+				missing = true
+				e.Total += weight * float64(stage.MissingPenalty)
+				continue
+			}
+			e.Total += weight * float64(r.Score)
+			if r.SubmittedAt.After(e.SubmittedAt) {
+				e.SubmittedAt = r.SubmittedAt
+			}
+		}
+		if missing && stage.MissingPolicy == MissingExclude {
+			continue
+		}
+		kept = append(kept, e)
+	}
+	entries = kept
+
+	slices.SortFunc(entries, func(a, b Entry) int {
+		return cmp.Or(
+			cmp.Compare(a.Total, b.Total),
+			a.SubmittedAt.Compare(b.SubmittedAt),
+			cmp.Compare(a.Player.UserID, b.Player.UserID),
+		)
+	})
+	for i := range entries {
+		if i > 0 && entries[i].Total == entries[i-1].Total && entries[i].SubmittedAt.Equal(entries[i-1].SubmittedAt) {
+			entries[i].Rank = entries[i-1].Rank
+		} else {
+			entries[i].Rank = i + 1
+		}
+	}
+	return entries
+}
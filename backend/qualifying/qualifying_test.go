@@ -0,0 +1,103 @@
+package qualifying
+
+import (
+	"testing"
+	"time"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/game"
+)
+
+func rankedTeam(score int, submittedAt time.Time, userIDs ...int32) game.RankedTeam {
+	t := game.RankedTeam{Score: score, SubmittedAt: submittedAt}
+	for _, id := range userIDs {
+		t.Members = append(t.Members, db.User{UserID: id})
+	}
+	return t
+}
+
+func TestAggregate(t *testing.T) {
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	teams := [][]game.RankedTeam{
+		{
+			rankedTeam(100, base, 1),
+			rankedTeam(120, base.Add(time.Minute), 2),
+			rankedTeam(150, base, 3),
+		},
+		{
+			rankedTeam(50, base.Add(2*time.Minute), 1),
+			rankedTeam(40, base.Add(time.Minute), 2),
+		},
+	}
+	games := []StageGame{{GameID: 1, Weight: 1}, {GameID: 2, Weight: 2}}
+
+	tests := []struct {
+		name     string
+		stage    Stage
+		expected []int32
+		totals   []float64
+	}{
+		{
+			name:     "penalty",
+			stage:    Stage{Games: games, MissingPolicy: MissingPenalty, MissingPenalty: 100},
+			expected: []int32{2, 1, 3},
+			totals:   []float64{200, 200, 350},
+		},
+		{
+			name:     "exclude",
+			stage:    Stage{Games: games, MissingPolicy: MissingExclude},
+			expected: []int32{2, 1},
+			totals:   []float64{200, 200},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entries := aggregate(tt.stage, teams)
+			if len(entries) != len(tt.expected) {
+				t.Fatalf("expected %d entries, got %d", len(tt.expected), len(entries))
+			}
+			for i, e := range entries {
+				if int32(e.Player.UserID) != tt.expected[i] {
+					t.Errorf("position %d: expected user %d, got %d", i, tt.expected[i], e.Player.UserID)
+				}
+				if e.Total != tt.totals[i] {
+					t.Errorf("position %d: expected total %v, got %v", i, tt.totals[i], e.Total)
+				}
+				if e.Rank != i+1 {
+					t.Errorf("position %d: expected rank %d, got %d", i, i+1, e.Rank)
+				}
+			}
+		})
+	}
+}
+
+func TestAggregate_MissingResultIsNil(t *testing.T) {
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	stage := Stage{
+		Games:         []StageGame{{GameID: 1, Weight: 1}, {GameID: 2, Weight: 1}},
+		MissingPolicy: MissingPenalty,
+	}
+	entries := aggregate(stage, [][]game.RankedTeam{{}, {rankedTeam(30, base, 5)}})
+	if len(entries) != 1 {
+		t.Fatalf("expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Results[0] != nil {
+		t.Errorf("expected no result in the first game, got %+v", entries[0].Results[0])
+	}
+	if entries[0].Results[1] == nil || entries[0].Results[1].Score != 30 {
+		t.Errorf("expected a score of 30 in the second game, got %+v", entries[0].Results[1])
+	}
+}
+
+func TestAggregate_SharedRank(t *testing.T) {
+	base := time.Date(2026, 4, 1, 12, 0, 0, 0, time.UTC)
+	stage := Stage{Games: []StageGame{{GameID: 1, Weight: 1}}, MissingPolicy: MissingPenalty}
+	entries := aggregate(stage, [][]game.RankedTeam{{
+		rankedTeam(10, base, 1, 2),
+		rankedTeam(20, base, 3),
+	}})
+	ranks := []int{entries[0].Rank, entries[1].Rank, entries[2].Rank}
+	if ranks[0] != 1 || ranks[1] != 1 || ranks[2] != 3 {
+		t.Errorf("expected ranks [1 1 3], got %v", ranks)
+	}
+}
@@ -0,0 +1,206 @@
+package qualifying
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/game"
+)
+
+type Service struct {
+	q   db.Querier
+	txm db.TxManager
+}
+
+func NewService(q db.Querier, txm db.TxManager) *Service {
+	return &Service{q: q, txm: txm}
+}
+
+// StageParams holds the settings of a stage.
+type StageParams struct {
+	DisplayName    string
+	IsPublic       bool
+	MissingPolicy  string
+	MissingPenalty int
+	Games          []StageGameParams
+}
+
+// StageGameParams holds a game of a stage.
+type StageGameParams struct {
+	GameID int
+	Weight float64
+}
+
+// ListStages returns the stages. Non-admins only see the public ones.
+func (s *Service) ListStages(ctx context.Context, isAdmin bool) ([]Stage, error) {
+	var rows []db.QualifyingStage
+	var err error
+	if isAdmin {
+		rows, err = s.q.ListQualifyingStages(ctx)
+	} else {
+		rows, err = s.q.ListPublicQualifyingStages(ctx)
+	}
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	stages := make([]Stage, len(rows))
+	for i, row := range rows {
+		stages[i], _, err = s.loadStage(ctx, row, isAdmin)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stages, nil
+}
+
+// GetStage returns the stage. Non-admins cannot see a stage that is not
+// public.
+func (s *Service) GetStage(ctx context.Context, stageID int, isAdmin bool) (Stage, error) {
+	stage, _, err := s.getStage(ctx, stageID, isAdmin)
+	return stage, err
+}
+
+// GetRanking returns the stage and the ranking of its players. The games are
+// ranked as GetRanking of the game package would show them, so a frozen game
+// counts with its frozen ranking for non-admins.
+func (s *Service) GetRanking(ctx context.Context, stageID int, isAdmin bool) (Stage, []Entry, error) {
+	stage, games, err := s.getStage(ctx, stageID, isAdmin)
+	if err != nil {
+		return Stage{}, nil, err
+	}
+	now := time.Now()
+	teams := make([][]game.RankedTeam, len(games))
+	for i, g := range games {
+		cutoff, frozen := game.RankingCutoff(g, now)
+		teams[i], _, err = game.RankedRows(ctx, s.q, g, cutoff, frozen && !isAdmin)
+		if err != nil {
+			return Stage{}, nil, err
+		}
+	}
+	return stage, aggregate(stage, teams), nil
+}
+
+// CreateStage creates a stage and returns its ID.
+func (s *Service) CreateStage(ctx context.Context, params StageParams) (int, error) {
+	if err := validateMissingPolicy(params.MissingPolicy); err != nil {
+		return 0, err
+	}
+	var stageID int32
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		stageID, err = qtx.CreateQualifyingStage(ctx, db.CreateQualifyingStageParams{
+			DisplayName:    params.DisplayName,
+			IsPublic:       params.IsPublic,
+			MissingPolicy:  params.MissingPolicy,
+			MissingPenalty: int32(params.MissingPenalty),
+		})
+		if err != nil {
+			return err
+		}
+		return addStageGames(ctx, qtx, stageID, params.IsPublic, params.Games)
+	})
+	return int(stageID), err
+}
+
+// UpdateStage replaces the settings and the games of a stage.
+func (s *Service) UpdateStage(ctx context.Context, stageID int, params StageParams) error {
+	if err := validateMissingPolicy(params.MissingPolicy); err != nil {
+		return err
+	}
+	if _, err := s.q.GetQualifyingStageByID(ctx, int32(stageID)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return game.ErrNotFound
+		}
+		return err
+	}
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		if err := qtx.UpdateQualifyingStage(ctx, db.UpdateQualifyingStageParams{
+			QualifyingStageID: int32(stageID),
+			DisplayName:       params.DisplayName,
+			IsPublic:          params.IsPublic,
+			MissingPolicy:     params.MissingPolicy,
+			MissingPenalty:    int32(params.MissingPenalty),
+		}); err != nil {
+			return err
+		}
+		if err := qtx.RemoveAllQualifyingStageGames(ctx, int32(stageID)); err != nil {
+			return err
+		}
+		return addStageGames(ctx, qtx, int32(stageID), params.IsPublic, params.Games)
+	})
+}
+
+// addStageGames adds the games to the stage in order. A public stage fails
+// with ErrGameNotPublic unless all of them are public.
+func addStageGames(ctx context.Context, q db.Querier, stageID int32, isPublic bool, games []StageGameParams) error {
+	for i, g := range games {
+		if isPublic {
+			row, err := q.GetGameByID(ctx, int32(g.GameID))
+			if err != nil {
+				return err
+			}
+			if !row.IsPublic {
+				return fmt.Errorf("%w: %d", ErrGameNotPublic, g.GameID)
+			}
+		}
+		if err := q.AddQualifyingStageGame(ctx, db.AddQualifyingStageGameParams{
+			QualifyingStageID: stageID,
+			GameID:            int32(g.GameID),
+			Position:          int32(i),
+			Weight:            g.Weight,
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// getStage returns the stage and the rows of its games in order.
+func (s *Service) getStage(ctx context.Context, stageID int, isAdmin bool) (Stage, []db.Game, error) {
+	row, err := s.q.GetQualifyingStageByID(ctx, int32(stageID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return Stage{}, nil, game.ErrNotFound
+		}
+		return Stage{}, nil, err
+	}
+	if !row.IsPublic && !isAdmin {
+		return Stage{}, nil, game.ErrNotFound
+	}
+	return s.loadStage(ctx, row, isAdmin)
+}
+
+// loadStage returns the stage and the rows of its games in order. Games that
+// have been made private since are left out for non-admins.
+func (s *Service) loadStage(ctx context.Context, row db.QualifyingStage, isAdmin bool) (Stage, []db.Game, error) {
+	gameRows, err := s.q.ListQualifyingStageGames(ctx, row.QualifyingStageID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return Stage{}, nil, err
+	}
+	stage := Stage{
+		StageID:        int(row.QualifyingStageID),
+		DisplayName:    row.DisplayName,
+		IsPublic:       row.IsPublic,
+		MissingPolicy:  row.MissingPolicy,
+		MissingPenalty: int(row.MissingPenalty),
+		Games:          []StageGame{},
+	}
+	var games []db.Game
+	for _, g := range gameRows {
+		if !g.Game.IsPublic && !isAdmin {
+			continue
+		}
+		stage.Games = append(stage.Games, StageGame{
+			GameID:      int(g.Game.GameID),
+			DisplayName: g.Game.DisplayName,
+			Weight:      g.Weight,
+		})
+		games = append(games, g.Game)
+	}
+	return stage, games, nil
+}
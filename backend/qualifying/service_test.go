@@ -0,0 +1,59 @@
+package qualifying
+
+import (
+	"context"
+	"testing"
+
+	"albatross-2026-backend/db"
+)
+
+// stageQuerier returns a fixed stage with its games for testing.
+type stageQuerier struct {
+	db.Querier
+	stage db.QualifyingStage
+	games []db.ListQualifyingStageGamesRow
+}
+
+func (m *stageQuerier) GetQualifyingStageByID(_ context.Context, _ int32) (db.QualifyingStage, error) {
+	return m.stage, nil
+}
+
+func (m *stageQuerier) ListQualifyingStageGames(_ context.Context, _ int32) ([]db.ListQualifyingStageGamesRow, error) {
+	return m.games, nil
+}
+
+func TestGetStage_PrivateGamesHiddenFromNonAdmins(t *testing.T) {
+	q := &stageQuerier{
+		stage: db.QualifyingStage{QualifyingStageID: 1, IsPublic: true, MissingPolicy: MissingPenalty},
+		games: []db.ListQualifyingStageGamesRow{
+			{Weight: 1, Game: db.Game{GameID: 3, IsPublic: true}},
+			{Weight: 1, Game: db.Game{GameID: 7}},
+		},
+	}
+	s := NewService(q, nil)
+
+	tests := []struct {
+		name    string
+		isAdmin bool
+		want    []int
+	}{
+		{name: "admin", isAdmin: true, want: []int{3, 7}},
+		{name: "non-admin", isAdmin: false, want: []int{3}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stage, games, err := s.getStage(context.Background(), 1, tt.isAdmin)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(stage.Games) != len(tt.want) || len(games) != len(tt.want) {
+				t.Fatalf("got %d games and %d rows, want %d", len(stage.Games), len(games), len(tt.want))
+			}
+			for i, id := range tt.want {
+				if stage.Games[i].GameID != id || int(games[i].GameID) != id {
+					t.Errorf("game %d = %d, want %d", i, stage.Games[i].GameID, id)
+				}
+			}
+		})
+	}
+}
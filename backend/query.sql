@@ -22,6 +22,33 @@ WHERE user_id = $1;
 SELECT * FROM users
 ORDER BY users.user_id;
 
+-- name: ListRatedUsers :many
+SELECT * FROM users
+WHERE rating_rank IS NOT NULL
+ORDER BY rating_rank, user_id;
+
+-- name: UpdateUserRating :exec
+UPDATE users
+SET rating = $2
+WHERE user_id = $1;
+
+-- name: UpdateRatingRanks :exec
+UPDATE users
+SET rating_rank = ranked.rating_rank
+FROM (
+    SELECT users.user_id, RANK() OVER (ORDER BY users.rating DESC) AS rating_rank
+    FROM users
+    WHERE EXISTS (
+        SELECT 1 FROM rating_history
+        WHERE rating_history.user_id = users.user_id
+    )
+) AS ranked
+WHERE users.user_id = ranked.user_id;
+
+-- name: ResetUserRatings :exec
+UPDATE users
+SET rating = $1, rating_rank = NULL;
+
 -- name: GetUserAuthByUsername :one
 SELECT * FROM users
 JOIN user_auths ON users.user_id = user_auths.user_id
@@ -43,7 +70,6 @@ VALUES ($1, $2);
 
 -- name: ListPublicGames :many
 SELECT * FROM games
-JOIN problems ON games.problem_id = problems.problem_id
 WHERE is_public = true
 ORDER BY games.game_id;
 
@@ -52,18 +78,40 @@ SELECT * FROM games
 ORDER BY games.game_id;
 
 -- name: CreateGame :one
-INSERT INTO games (game_type, is_public, display_name, duration_seconds, problem_id)
+INSERT INTO games (game_type, is_public, display_name, duration_seconds, unsolved_penalty)
 VALUES ($1, $2, $3, $4, $5)
 RETURNING game_id;
 
--- name: UpdateGameStartedAt :exec
+-- name: GetGameLifecycleForUpdate :one
+SELECT started_at, duration_seconds, paused_at, override_state FROM games
+WHERE game_id = $1
+FOR UPDATE;
+
+-- name: UpdateGameLifecycle :exec
+UPDATE games
+SET
+    started_at = $2,
+    duration_seconds = $3,
+    paused_at = $4,
+    override_state = $5
+WHERE game_id = $1;
+
+-- name: UpdateGameRevealedUntil :exec
 UPDATE games
-SET started_at = $2
+SET revealed_until = $2
 WHERE game_id = $1;
 
+-- name: CreateGameLifecycleEvent :exec
+INSERT INTO game_lifecycle_events (game_id, action, from_state, to_state, started_at, duration_seconds, user_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListGameLifecycleEvents :many
+SELECT * FROM game_lifecycle_events
+WHERE game_id = $1
+ORDER BY game_lifecycle_event_id;
+
 -- name: GetGameByID :one
 SELECT * FROM games
-JOIN problems ON games.problem_id = problems.problem_id
 WHERE games.game_id = $1
 LIMIT 1;
 
@@ -73,9 +121,10 @@ SET
     game_type = $2,
     is_public = $3,
     display_name = $4,
-    duration_seconds = $5,
-    started_at = $6,
-    problem_id = $7
+    freeze_seconds = $5,
+    tie_break = $6,
+    unsolved_penalty = $7,
+    allow_practice = $8
 WHERE game_id = $1;
 
 -- name: ListMainPlayers :many
@@ -92,21 +141,70 @@ VALUES ($1, $2);
 DELETE FROM game_main_players
 WHERE game_id = $1;
 
--- name: ListTestcasesByGameID :many
-SELECT * FROM testcases
-WHERE testcases.problem_id = (SELECT problem_id FROM games WHERE game_id = $1)
-ORDER BY testcases.testcase_id;
+-- name: ListGameProblems :many
+SELECT game_problems.game_id, sqlc.embed(problems) FROM game_problems
+JOIN problems ON game_problems.problem_id = problems.problem_id
+WHERE game_problems.game_id = ANY($1::INT[])
+ORDER BY game_problems.game_id, game_problems.position;
+
+-- name: GetGameProblem :one
+SELECT problems.* FROM game_problems
+JOIN problems ON game_problems.problem_id = problems.problem_id
+WHERE game_problems.game_id = $1 AND game_problems.problem_id = $2
+LIMIT 1;
+
+-- name: AddGameProblem :exec
+INSERT INTO game_problems (game_id, problem_id, position)
+VALUES ($1, $2, $3);
+
+-- name: RemoveAllGameProblems :exec
+DELETE FROM game_problems
+WHERE game_id = $1;
+
+-- name: ListTeams :many
+SELECT * FROM game_teams
+WHERE game_id = $1
+ORDER BY team_id;
+
+-- name: ListTeamMembers :many
+SELECT game_team_members.team_id, sqlc.embed(users) FROM game_team_members
+JOIN users ON game_team_members.user_id = users.user_id
+WHERE game_team_members.game_id = $1
+ORDER BY game_team_members.team_id, users.user_id;
+
+-- name: GetTeamByUserID :one
+SELECT game_teams.* FROM game_team_members
+JOIN game_teams ON game_team_members.team_id = game_teams.team_id
+WHERE game_team_members.game_id = $1 AND game_team_members.user_id = $2
+LIMIT 1;
+
+-- name: CreateTeam :one
+INSERT INTO game_teams (game_id, display_name)
+VALUES ($1, $2)
+RETURNING team_id;
+
+-- name: AddTeamMember :execrows
+INSERT INTO game_team_members (team_id, game_id, user_id)
+VALUES ($1, $2, $3)
+ON CONFLICT (game_id, user_id) DO NOTHING;
+
+-- name: RemoveAllTeamMembers :exec
+DELETE FROM game_team_members
+WHERE game_id = $1;
+
+-- name: RemoveAllTeams :exec
+DELETE FROM game_teams
+WHERE game_id = $1;
 
 -- name: CreateTestcaseResult :exec
-INSERT INTO testcase_results (submission_id, testcase_id, status, stdout, stderr)
-VALUES ($1, $2, $3, $4, $5);
+INSERT INTO testcase_results (submission_id, testcase_id, status, stdout, stderr, time_ms, memory_bytes)
+VALUES ($1, $2, $3, $4, $5, $6, $7);
 
 -- name: AggregateTestcaseResults :one
 SELECT
     CASE
         WHEN COUNT(*) < (SELECT COUNT(*) FROM testcases WHERE problem_id =
-                         (SELECT problem_id FROM games WHERE game_id =
-                          (SELECT game_id FROM submissions AS s WHERE s.submission_id = $1)))
+                         (SELECT problem_id FROM submissions AS s WHERE s.submission_id = $1))
         THEN 'running'
         WHEN COUNT(CASE WHEN r.status = 'internal_error' THEN 1 END) > 0 THEN 'internal_error'
         WHEN COUNT(CASE WHEN r.status = 'timeout'        THEN 1 END) > 0 THEN 'timeout'
@@ -121,62 +219,107 @@ WHERE r.submission_id = $1;
 -- name: GetLatestState :one
 SELECT * FROM game_states
 LEFT JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
-WHERE game_states.game_id = $1 AND game_states.user_id = $2
+WHERE game_states.game_id = $1 AND game_states.team_id = $2 AND game_states.problem_id = $3
 LIMIT 1;
 
 -- name: GetLatestStatesOfMainPlayers :many
-SELECT * FROM game_main_players
-LEFT JOIN game_states ON game_main_players.game_id = game_states.game_id AND game_main_players.user_id = game_states.user_id
+SELECT
+    game_main_players.user_id,
+    game_states.language,
+    game_states.code,
+    game_states.status,
+    submissions.code_size,
+    submissions.created_at
+FROM game_main_players
+LEFT JOIN game_team_members ON game_main_players.game_id = game_team_members.game_id AND game_main_players.user_id = game_team_members.user_id
+LEFT JOIN game_states ON game_main_players.game_id = game_states.game_id AND game_team_members.team_id = game_states.team_id AND game_states.problem_id = $2
 LEFT JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
 WHERE game_main_players.game_id = $1;
 
 -- name: GetRanking :many
 SELECT
     sqlc.embed(submissions),
-    sqlc.embed(users)
+    sqlc.embed(game_teams),
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND NOT s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
 FROM game_states
-JOIN users ON game_states.user_id = users.user_id
+JOIN game_teams ON game_states.team_id = game_teams.team_id
 JOIN submissions ON game_states.best_score_submission_id = submissions.submission_id
 WHERE game_states.game_id = $1
-ORDER BY submissions.code_size ASC, submissions.created_at ASC
-LIMIT 30;
+ORDER BY submissions.code_size ASC, submissions.created_at ASC;
+
+-- name: ListBestSubmissionsAt :many
+SELECT
+    sqlc.embed(submissions),
+    sqlc.embed(game_teams),
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND NOT s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
+FROM submissions
+JOIN game_teams ON submissions.team_id = game_teams.team_id
+WHERE submissions.submission_id IN (
+    SELECT DISTINCT ON (s.team_id, s.problem_id) s.submission_id FROM submissions AS s
+    WHERE s.game_id = $1 AND s.status = 'success' AND NOT s.is_practice AND s.created_at <= $2
+      AND (sqlc.narg(language)::text IS NULL OR s.language = sqlc.narg(language))
+    ORDER BY s.team_id, s.problem_id, s.code_size ASC, s.created_at ASC
+)
+ORDER BY submissions.code_size ASC, submissions.created_at ASC;
 
--- name: GetQualifyingRanking :many
+-- name: ListBestPracticeSubmissions :many
 SELECT
-    u.username AS username,
-    u.label AS user_label,
-    s1.code_size AS code_size_1,
-    s2.code_size AS code_size_2,
-    s3.code_size AS code_size_3,
-    (COALESCE(s1.code_size, 0) + COALESCE(s2.code_size, 0) + COALESCE(s3.code_size, 0)) AS total_code_size,
-    s1.created_at AS submitted_at_1,
-    s2.created_at AS submitted_at_2,
-    s3.created_at AS submitted_at_3
-FROM game_states gs1
-JOIN submissions s1 ON gs1.best_score_submission_id = s1.submission_id
-JOIN game_states gs2 ON gs1.user_id = gs2.user_id
-JOIN submissions s2 ON gs2.best_score_submission_id = s2.submission_id
-JOIN game_states gs3 ON gs1.user_id = gs3.user_id
-JOIN submissions s3 ON gs3.best_score_submission_id = s3.submission_id
-JOIN users u ON gs1.user_id = u.user_id
-WHERE gs1.game_id = $1 AND gs2.game_id = $2 AND gs3.game_id = $3
-ORDER BY total_code_size ASC;
+    sqlc.embed(submissions),
+    sqlc.embed(game_teams),
+    (SELECT COUNT(*) FROM submissions AS s
+     WHERE s.game_id = submissions.game_id AND s.team_id = submissions.team_id AND s.is_practice AND s.created_at <= submissions.created_at) AS submission_count
+FROM submissions
+JOIN game_teams ON submissions.team_id = game_teams.team_id
+WHERE submissions.submission_id IN (
+    SELECT DISTINCT ON (s.team_id, s.problem_id) s.submission_id FROM submissions AS s
+    WHERE s.game_id = $1 AND s.status = 'success' AND s.is_practice
+      AND (sqlc.narg(language)::text IS NULL OR s.language = sqlc.narg(language))
+    ORDER BY s.team_id, s.problem_id, s.code_size ASC, s.created_at ASC
+)
+ORDER BY submissions.code_size ASC, submissions.created_at ASC;
+
+-- name: ListSuccessfulSubmissionsAfter :many
+SELECT * FROM submissions
+WHERE game_id = $1 AND status = 'success' AND NOT is_practice AND created_at > $2
+ORDER BY created_at;
 
 -- name: UpdateCode :exec
-INSERT INTO game_states (game_id, user_id, code, status)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (game_id, user_id)
-DO UPDATE SET code = EXCLUDED.code;
+INSERT INTO game_states (game_id, team_id, problem_id, language, code, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (game_id, team_id, problem_id)
+DO UPDATE SET language = EXCLUDED.language, code = EXCLUDED.code;
 
 -- name: UpdateCodeAndStatus :exec
-INSERT INTO game_states (game_id, user_id, code, status)
-VALUES ($1, $2, $3, $4)
-ON CONFLICT (game_id, user_id)
-DO UPDATE SET code = EXCLUDED.code, status = EXCLUDED.status;
+INSERT INTO game_states (game_id, team_id, problem_id, language, code, status)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (game_id, team_id, problem_id)
+DO UPDATE SET language = EXCLUDED.language, code = EXCLUDED.code, status = EXCLUDED.status;
+
+-- name: GetCodeForSnapshot :one
+SELECT
+    gs.code,
+    EXISTS (
+        SELECT 1 FROM code_snapshots AS cs
+        WHERE cs.game_id = gs.game_id AND cs.team_id = gs.team_id AND cs.problem_id = gs.problem_id
+    ) AS has_snapshots
+FROM game_states AS gs
+WHERE gs.game_id = $1 AND gs.team_id = $2 AND gs.problem_id = $3
+FOR UPDATE OF gs;
+
+-- name: CreateCodeSnapshot :exec
+INSERT INTO code_snapshots (game_id, team_id, user_id, problem_id, is_submission, keep_prefix, keep_suffix, inserted)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListCodeSnapshots :many
+SELECT * FROM code_snapshots
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3
+ORDER BY code_snapshot_id;
 
 -- name: CreateSubmission :one
-INSERT INTO submissions (game_id, user_id, code, code_size, status)
-VALUES ($1, $2, $3, $4, 'running')
+INSERT INTO submissions (game_id, team_id, user_id, problem_id, language, code, code_size, status, is_practice)
+VALUES ($1, $2, $3, $4, $5, $6, $7, 'running', $8)
 RETURNING submission_id;
 
 -- name: UpdateSubmissionStatus :exec
@@ -186,24 +329,38 @@ WHERE submission_id = $1;
 
 -- name: UpdateGameStateStatus :exec
 UPDATE game_states
-SET status = $3
-WHERE game_id = $1 AND user_id = $2;
+SET status = $4
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3;
 
 -- name: SyncGameStateBestScoreSubmission :exec
 UPDATE game_states
 SET best_score_submission_id = (
     SELECT submission_id FROM submissions AS s
-    WHERE s.game_id = $1 AND s.user_id = $2 AND s.status = 'success'
+    WHERE s.game_id = $1 AND s.team_id = $2 AND s.problem_id = $3 AND s.status = 'success' AND NOT s.is_practice
     ORDER BY s.code_size ASC, s.created_at ASC
     LIMIT 1
 )
-WHERE game_id = $1 AND user_id = $2;
+WHERE game_id = $1 AND team_id = $2 AND problem_id = $3;
 
 -- name: ListSubmissionIDs :many
 SELECT submission_id FROM submissions;
 
 -- name: ListGameStateIDs :many
-SELECT game_id, user_id FROM game_states;
+SELECT game_id, team_id, problem_id FROM game_states;
+
+-- name: ListSubmissionsByProblemID :many
+SELECT * FROM submissions
+WHERE problem_id = $1
+ORDER BY submission_id;
+
+-- name: UpdateSubmissionCodeSize :exec
+UPDATE submissions
+SET code_size = $2
+WHERE submission_id = $1;
+
+-- name: ListGameStateIDsByProblemID :many
+SELECT game_id, team_id, problem_id FROM game_states
+WHERE problem_id = $1;
 
 -- name: ListProblems :many
 SELECT * FROM problems
@@ -214,9 +371,15 @@ SELECT * FROM problems
 WHERE problem_id = $1
 LIMIT 1;
 
+-- name: GetProblemBySubmissionID :one
+SELECT problems.* FROM submissions
+JOIN problems ON submissions.problem_id = problems.problem_id
+WHERE submissions.submission_id = $1
+LIMIT 1;
+
 -- name: CreateProblem :one
-INSERT INTO problems (title, description, language, sample_code)
-VALUES ($1, $2, $3, $4)
+INSERT INTO problems (title, description, language, sample_code, scoring, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
 RETURNING problem_id;
 
 -- name: UpdateProblem :exec
@@ -225,7 +388,31 @@ SET
     title = $2,
     description = $3,
     language = $4,
-    sample_code = $5
+    sample_code = $5,
+    scoring = $6,
+    checker = $7,
+    checker_epsilon = $8,
+    checker_code = $9,
+    time_limit_ms = $10,
+    memory_limit_mib = $11,
+    judge_changed_at = CASE
+        WHEN (language, checker, checker_epsilon, checker_code, time_limit_ms, memory_limit_mib)
+            IS DISTINCT FROM ($4, $7, $8, $9, $10::INT, $11::INT) THEN NOW()
+        ELSE judge_changed_at
+    END
+WHERE problem_id = $1;
+
+-- name: ListProblemLanguages :many
+SELECT * FROM problem_languages
+WHERE problem_id = ANY($1::INT[])
+ORDER BY problem_id, language;
+
+-- name: CreateProblemLanguage :exec
+INSERT INTO problem_languages (problem_id, language, sample_code)
+VALUES ($1, $2, $3);
+
+-- name: DeleteProblemLanguages :exec
+DELETE FROM problem_languages
 WHERE problem_id = $1;
 
 -- name: ListTestcases :many
@@ -243,8 +430,8 @@ WHERE testcase_id = $1
 LIMIT 1;
 
 -- name: CreateTestcase :one
-INSERT INTO testcases (problem_id, stdin, stdout)
-VALUES ($1, $2, $3)
+INSERT INTO testcases (problem_id, stdin, stdout, is_sample, time_limit_ms, memory_limit_mib)
+VALUES ($1, $2, $3, $4, $5, $6)
 RETURNING testcase_id;
 
 -- name: UpdateTestcase :exec
@@ -252,16 +439,165 @@ UPDATE testcases
 SET
     problem_id = $2,
     stdin = $3,
-    stdout = $4
+    stdout = $4,
+    is_sample = $5,
+    time_limit_ms = $6,
+    memory_limit_mib = $7
 WHERE testcase_id = $1;
 
 -- name: DeleteTestcase :exec
 DELETE FROM testcases
 WHERE testcase_id = $1;
 
--- name: GetSubmissionsByGameIDAndUserID :many
+-- name: DeleteTestcaseResultsByTestcaseID :exec
+DELETE FROM testcase_results WHERE testcase_id = $1;
+
+-- name: CreateTestcaseRevision :exec
+INSERT INTO testcase_revisions (testcase_id, problem_id, action, stdin, stdout, is_sample, affects_results, user_id)
+VALUES ($1, $2, $3, $4, $5, $6, $7, $8);
+
+-- name: ListTestcaseRevisionsByProblemID :many
+SELECT * FROM testcase_revisions
+WHERE problem_id = $1
+ORDER BY testcase_revision_id DESC;
+
+-- name: ListStaleSubmissionsByProblemID :many
+SELECT s.* FROM submissions AS s
+WHERE s.problem_id = $1 AND s.status <> 'running'
+  AND COALESCE(
+      (SELECT MAX(r.created_at) FROM testcase_results AS r WHERE r.submission_id = s.submission_id),
+      s.created_at
+  ) < (
+      SELECT MAX(v.created_at) FROM testcase_revisions AS v
+      WHERE v.problem_id = s.problem_id AND v.affects_results
+  )
+ORDER BY s.submission_id;
+
+-- name: CreateRejudge :one
+INSERT INTO rejudges (problem_id, reason, user_id)
+VALUES ($1, $2, $3)
+RETURNING rejudge_id;
+
+-- name: GetRejudgeByID :one
+SELECT * FROM rejudges
+WHERE rejudge_id = $1
+LIMIT 1;
+
+-- name: ListRejudges :many
+SELECT * FROM rejudges
+ORDER BY rejudge_id DESC;
+
+-- name: FinishRejudge :exec
+UPDATE rejudges
+SET finished_at = NOW()
+WHERE rejudge_id = $1;
+
+-- name: CreateRejudgeSubmission :exec
+INSERT INTO rejudge_submissions (rejudge_id, submission_id, status_before)
+VALUES ($1, $2, $3);
+
+-- name: ListRejudgeSubmissions :many
+SELECT rejudge_submissions.status_before, sqlc.embed(submissions)
+FROM rejudge_submissions
+JOIN submissions ON rejudge_submissions.submission_id = submissions.submission_id
+WHERE rejudge_submissions.rejudge_id = $1
+ORDER BY submissions.submission_id;
+
+-- name: ListFinishedRejudgeIDsBySubmissionID :many
+SELECT rejudges.rejudge_id FROM rejudges
+JOIN rejudge_submissions ON rejudges.rejudge_id = rejudge_submissions.rejudge_id
+WHERE rejudge_submissions.submission_id = $1 AND rejudges.finished_at IS NULL
+  AND NOT EXISTS (
+      SELECT 1 FROM rejudge_submissions AS rs
+      JOIN submissions AS s ON rs.submission_id = s.submission_id
+      WHERE rs.rejudge_id = rejudges.rejudge_id AND s.status = 'running'
+  );
+
+-- name: CreateRejudgeRanking :exec
+INSERT INTO rejudge_rankings (rejudge_id, game_id, team_id, phase, rank, score)
+VALUES ($1, $2, $3, $4, $5, $6);
+
+-- name: ListRejudgeRankings :many
+SELECT rejudge_rankings.*, game_teams.display_name AS team_name
+FROM rejudge_rankings
+JOIN game_teams ON rejudge_rankings.team_id = game_teams.team_id
+WHERE rejudge_rankings.rejudge_id = $1
+ORDER BY rejudge_rankings.game_id, rejudge_rankings.team_id;
+
+-- name: CreateReferenceSolution :one
+INSERT INTO reference_solutions (problem_id, name, code)
+VALUES ($1, $2, $3)
+RETURNING reference_solution_id;
+
+-- name: GetReferenceSolutionByID :one
+SELECT * FROM reference_solutions
+WHERE reference_solution_id = $1
+LIMIT 1;
+
+-- name: ListReferenceSolutionsByProblemID :many
+SELECT * FROM reference_solutions
+WHERE problem_id = $1
+ORDER BY reference_solution_id;
+
+-- name: DeleteReferenceSolution :exec
+DELETE FROM reference_solutions
+WHERE reference_solution_id = $1;
+
+-- name: GetProblemJudgeChangedAt :one
+SELECT GREATEST(
+    p.judge_changed_at,
+    (SELECT MAX(v.created_at) FROM testcase_revisions AS v WHERE v.problem_id = p.problem_id AND v.affects_results),
+    (SELECT MAX(r.created_at) FROM reference_solutions AS r WHERE r.problem_id = p.problem_id)
+)::timestamp AS changed_at
+FROM problems AS p
+WHERE p.problem_id = $1;
+
+-- name: CreateProblemValidation :one
+INSERT INTO problem_validations (problem_id, task_count, user_id)
+VALUES ($1, $2, $3)
+RETURNING problem_validation_id;
+
+-- name: GetLatestProblemValidation :one
+SELECT * FROM problem_validations
+WHERE problem_id = $1
+ORDER BY problem_validation_id DESC
+LIMIT 1;
+
+-- name: CreateProblemValidationResult :exec
+INSERT INTO problem_validation_results (problem_validation_id, reference_solution_id, testcase_id, status, stdout, stderr)
+VALUES ($1, $2, $3, $4, $5, $6)
+ON CONFLICT (problem_validation_id, reference_solution_id, testcase_id) DO NOTHING;
+
+-- name: ListProblemValidationResults :many
+SELECT * FROM problem_validation_results
+WHERE problem_validation_id = $1
+ORDER BY reference_solution_id, testcase_id;
+
+-- name: FinishProblemValidationIfJudged :execrows
+UPDATE problem_validations AS v
+SET
+    status = CASE
+        WHEN EXISTS (
+            SELECT 1 FROM problem_validation_results AS r
+            WHERE r.problem_validation_id = v.problem_validation_id AND r.status <> 'success'
+        ) THEN 'failure'
+        ELSE 'success'
+    END,
+    finished_at = NOW()
+WHERE v.problem_validation_id = $1 AND v.status = 'running'
+  AND v.task_count = (
+      SELECT COUNT(*) FROM problem_validation_results AS r
+      WHERE r.problem_validation_id = v.problem_validation_id
+  );
+
+-- name: FailProblemValidation :exec
+UPDATE problem_validations
+SET status = 'failure', finished_at = NOW()
+WHERE problem_validation_id = $1 AND status = 'running';
+
+-- name: GetSubmissionsByGameIDAndTeamID :many
 SELECT * FROM submissions
-WHERE game_id = $1 AND user_id = $2
+WHERE game_id = $1 AND team_id = $2
 ORDER BY created_at DESC;
 
 -- name: GetSubmissionsByGameID :many
@@ -271,10 +607,10 @@ WHERE game_id = $1
 ORDER BY created_at DESC;
 
 -- name: GetLatestSubmissionsByGameID :many
-SELECT DISTINCT ON (user_id) *
+SELECT DISTINCT ON (team_id, problem_id) *
 FROM submissions
-WHERE game_id = $1
-ORDER BY user_id, created_at DESC;
+WHERE game_id = $1 AND NOT is_practice
+ORDER BY team_id, problem_id, created_at DESC;
 
 -- name: GetSubmissionByID :one
 SELECT *
@@ -291,6 +627,106 @@ FROM testcase_results
 WHERE submission_id = $1
 ORDER BY created_at;
 
+-- name: ListTestcaseResultsWithTestcaseBySubmissionID :many
+SELECT
+    sqlc.embed(testcase_results),
+    sqlc.embed(testcases)
+FROM testcase_results
+JOIN testcases ON testcase_results.testcase_id = testcases.testcase_id
+WHERE testcase_results.submission_id = $1
+ORDER BY testcases.testcase_id;
+
+-- name: ListSubmissionsByGameIDAfter :many
+SELECT *
+FROM submissions
+WHERE game_id = $1 AND submission_id > $2
+ORDER BY submission_id
+LIMIT $3;
+
+-- name: ListTestcaseResultsByGameIDAfter :many
+SELECT
+    testcase_results.testcase_result_id,
+    testcase_results.submission_id,
+    testcase_results.testcase_id,
+    testcase_results.status,
+    testcases.is_sample
+FROM testcase_results
+JOIN submissions ON testcase_results.submission_id = submissions.submission_id
+JOIN testcases ON testcase_results.testcase_id = testcases.testcase_id
+WHERE submissions.game_id = $1 AND testcase_results.testcase_result_id > $2
+ORDER BY testcase_results.testcase_result_id
+LIMIT $3;
+
+-- name: ListUnratedGames :many
+SELECT * FROM games
+WHERE rated_at IS NULL AND started_at IS NOT NULL AND is_public
+ORDER BY started_at, game_id;
+
+-- name: ListRatedGames :many
+SELECT * FROM games
+WHERE rated_at IS NOT NULL AND is_public
+ORDER BY rated_at, game_id;
+
+-- name: UpdateGameRatedAt :exec
+UPDATE games
+SET rated_at = NOW()
+WHERE game_id = $1;
+
+-- name: CreateRatingHistory :exec
+INSERT INTO rating_history (user_id, game_id, game_rank, rating_before, rating_after)
+VALUES ($1, $2, $3, $4, $5);
+
+-- name: ListRatingHistoryByUserID :many
+SELECT rating_history.*, games.display_name AS game_display_name FROM rating_history
+JOIN games ON rating_history.game_id = games.game_id
+WHERE rating_history.user_id = $1
+ORDER BY rating_history.rating_history_id;
+
+-- name: DeleteAllRatingHistory :exec
+DELETE FROM rating_history;
+
+-- name: ListQualifyingStages :many
+SELECT * FROM qualifying_stages
+ORDER BY qualifying_stage_id;
+
+-- name: ListPublicQualifyingStages :many
+SELECT * FROM qualifying_stages
+WHERE is_public = true
+ORDER BY qualifying_stage_id;
+
+-- name: GetQualifyingStageByID :one
+SELECT * FROM qualifying_stages
+WHERE qualifying_stage_id = $1
+LIMIT 1;
+
+-- name: CreateQualifyingStage :one
+INSERT INTO qualifying_stages (display_name, is_public, missing_policy, missing_penalty)
+VALUES ($1, $2, $3, $4)
+RETURNING qualifying_stage_id;
+
+-- name: UpdateQualifyingStage :exec
+UPDATE qualifying_stages
+SET
+    display_name = $2,
+    is_public = $3,
+    missing_policy = $4,
+    missing_penalty = $5
+WHERE qualifying_stage_id = $1;
+
+-- name: ListQualifyingStageGames :many
+SELECT qualifying_stage_games.weight, sqlc.embed(games) FROM qualifying_stage_games
+JOIN games ON qualifying_stage_games.game_id = games.game_id
+WHERE qualifying_stage_games.qualifying_stage_id = $1
+ORDER BY qualifying_stage_games.position;
+
+-- name: AddQualifyingStageGame :exec
+INSERT INTO qualifying_stage_games (qualifying_stage_id, game_id, position, weight)
+VALUES ($1, $2, $3, $4);
+
+-- name: RemoveAllQualifyingStageGames :exec
+DELETE FROM qualifying_stage_games
+WHERE qualifying_stage_id = $1;
+
 -- name: CreateSession :exec
 INSERT INTO sessions (session_id, user_id, expires_at) VALUES ($1, $2, $3);
 
@@ -0,0 +1,170 @@
+// Package ranking orders the entries of a game ranking and pages through it.
+// Each game chooses one of the tie-break policies by name.
+package ranking
+
+import (
+	"cmp"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+const (
+	// EarliestSubmission ranks the team that reached the score first higher.
+	EarliestSubmission = "earliest_submission"
+	// FewestSubmissions ranks the team that reached the score in fewer
+	// submissions higher. Teams with the same count are ordered as
+	// EarliestSubmission.
+	FewestSubmissions = "fewest_submissions"
+	// SharedRank gives the same rank to all the teams with the same score.
+	SharedRank = "shared_rank"
+)
+
+// Default is the policy used when a game does not specify one.
+const Default = EarliestSubmission
+
+// Names lists all the policies in the order shown to admins.
+var Names = []string{EarliestSubmission, FewestSubmissions, SharedRank}
+
+var (
+	ErrUnknownTieBreak = errors.New("unknown tie-break policy")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+)
+
+// Key is what an entry of a ranking is ordered by. A lower score is better.
+type Key struct {
+	Score int
+	// SubmissionCount is the number of submissions the team made up to the
+	// one that scored Score.
+	SubmissionCount int
+	SubmittedAt     time.Time
+	TeamID          int
+}
+
+// Policy decides the order of entries with the same score.
+type Policy interface {
+	// Compare orders the keys from the best. It does not look at TeamID.
+	Compare(a, b Key) int
+	// SameRank reports whether the keys share a rank.
+	SameRank(a, b Key) bool
+}
+
+func New(name string) (Policy, error) {
+	switch name {
+	case EarliestSubmission:
+		return earliestSubmissionPolicy{}, nil
+	case FewestSubmissions:
+		return fewestSubmissionsPolicy{}, nil
+	case SharedRank:
+		return sharedRankPolicy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownTieBreak, name)
+	}
+}
+
+// IsValid reports whether name is one of Names.
+func IsValid(name string) bool {
+	return slices.Contains(Names, name)
+}
+
+type earliestSubmissionPolicy struct{}
+
+func (earliestSubmissionPolicy) Compare(a, b Key) int {
+	return cmp.Or(cmp.Compare(a.Score, b.Score), a.SubmittedAt.Compare(b.SubmittedAt))
+}
+
+func (p earliestSubmissionPolicy) SameRank(a, b Key) bool {
+	return p.Compare(a, b) == 0
+}
+
+type fewestSubmissionsPolicy struct{}
+
+func (fewestSubmissionsPolicy) Compare(a, b Key) int {
+	return cmp.Or(
+		cmp.Compare(a.Score, b.Score),
+		cmp.Compare(a.SubmissionCount, b.SubmissionCount),
+		a.SubmittedAt.Compare(b.SubmittedAt),
+	)
+}
+
+func (p fewestSubmissionsPolicy) SameRank(a, b Key) bool {
+	return p.Compare(a, b) == 0
+}
+
+// sharedRankPolicy still lists the teams with the same score in the order
+// of their submissions so that the order is stable.
+type sharedRankPolicy struct{}
+
+func (sharedRankPolicy) Compare(a, b Key) int {
+	return cmp.Or(cmp.Compare(a.Score, b.Score), a.SubmittedAt.Compare(b.SubmittedAt))
+}
+
+func (sharedRankPolicy) SameRank(a, b Key) bool {
+	return a.Score == b.Score
+}
+
+// compare is the total order of keys under the policy. Entries the policy
+// cannot tell apart are ordered by TeamID so that cursors are unambiguous.
+func compare(p Policy, a, b Key) int {
+	return cmp.Or(p.Compare(a, b), cmp.Compare(a.TeamID, b.TeamID))
+}
+
+// Sort sorts the entries from the best and returns the rank of each of them.
+// Entries that share a rank get the same number, and the next one skips as
+// many numbers (1, 2, 2, 4).
+func Sort[E any](entries []E, key func(E) Key, p Policy) []int {
+	slices.SortFunc(entries, func(a, b E) int {
+		return compare(p, key(a), key(b))
+	})
+	ranks := make([]int, len(entries))
+	for i := range entries {
+		if i > 0 && p.SameRank(key(entries[i-1]), key(entries[i])) {
+			ranks[i] = ranks[i-1]
+		} else {
+			ranks[i] = i + 1
+		}
+	}
+	return ranks
+}
+
+// Seek returns the index of the first entry after the cursor in entries
+// sorted by Sort. An empty cursor points to the beginning.
+func Seek[E any](entries []E, key func(E) Key, p Policy, cursor string) (int, error) {
+	if cursor == "" {
+		return 0, nil
+	}
+	after, err := DecodeCursor(cursor)
+	if err != nil {
+		return 0, err
+	}
+	i, _ := slices.BinarySearchFunc(entries, after, func(e E, k Key) int {
+		if compare(p, key(e), k) <= 0 {
+			return -1
+		}
+		return 1
+	})
+	return i, nil
+}
+
+// EncodeCursor returns an opaque cursor pointing after the entry of the key.
+func EncodeCursor(k Key) string {
+	s := fmt.Sprintf("%d:%d:%d:%d", k.Score, k.SubmissionCount, k.SubmittedAt.UnixNano(), k.TeamID)
+	return base64.RawURLEncoding.EncodeToString([]byte(s))
+}
+
+// DecodeCursor parses a cursor returned by EncodeCursor.
+func DecodeCursor(cursor string) (Key, error) {
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return Key{}, ErrInvalidCursor
+	}
+	var k Key
+	var submittedAt int64
+	if _, err := fmt.Sscanf(string(b), "%d:%d:%d:%d", &k.Score, &k.SubmissionCount, &submittedAt, &k.TeamID); err != nil {
+		return Key{}, ErrInvalidCursor
+	}
+	k.SubmittedAt = time.Unix(0, submittedAt)
+	return k, nil
+}
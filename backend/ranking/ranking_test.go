@@ -0,0 +1,125 @@
+package ranking
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestNew_UnknownTieBreak(t *testing.T) {
+	_, err := New("random")
+	if !errors.Is(err, ErrUnknownTieBreak) {
+		t.Errorf("expected ErrUnknownTieBreak, got %v", err)
+	}
+}
+
+func TestNew_AllNames(t *testing.T) {
+	for _, name := range Names {
+		if _, err := New(name); err != nil {
+			t.Errorf("New(%q) returned error: %v", name, err)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	base := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	keys := []Key{
+		{Score: 20, SubmissionCount: 1, SubmittedAt: base.Add(4 * time.Minute), TeamID: 5},
+		{Score: 10, SubmissionCount: 5, SubmittedAt: base.Add(1 * time.Minute), TeamID: 1},
+		{Score: 10, SubmissionCount: 2, SubmittedAt: base.Add(2 * time.Minute), TeamID: 2},
+		{Score: 10, SubmissionCount: 2, SubmittedAt: base.Add(3 * time.Minute), TeamID: 3},
+		{Score: 15, SubmissionCount: 1, SubmittedAt: base.Add(1 * time.Minute), TeamID: 4},
+	}
+	tests := []struct {
+		tieBreak  string
+		wantTeams []int
+		wantRanks []int
+	}{
+		{EarliestSubmission, []int{1, 2, 3, 4, 5}, []int{1, 2, 3, 4, 5}},
+		{FewestSubmissions, []int{2, 3, 1, 4, 5}, []int{1, 2, 3, 4, 5}},
+		{SharedRank, []int{1, 2, 3, 4, 5}, []int{1, 1, 1, 4, 5}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tieBreak, func(t *testing.T) {
+			p, err := New(tt.tieBreak)
+			if err != nil {
+				t.Fatal(err)
+			}
+			entries := slices.Clone(keys)
+			ranks := Sort(entries, func(k Key) Key { return k }, p)
+			var teams []int
+			for _, k := range entries {
+				teams = append(teams, k.TeamID)
+			}
+			if !slices.Equal(teams, tt.wantTeams) {
+				t.Errorf("order = %v, want %v", teams, tt.wantTeams)
+			}
+			if !slices.Equal(ranks, tt.wantRanks) {
+				t.Errorf("ranks = %v, want %v", ranks, tt.wantRanks)
+			}
+		})
+	}
+}
+
+func TestSort_SameSubmissionCountShareRank(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Key{
+		{Score: 10, SubmissionCount: 2, SubmittedAt: at, TeamID: 2},
+		{Score: 10, SubmissionCount: 2, SubmittedAt: at, TeamID: 1},
+	}
+	ranks := Sort(entries, func(k Key) Key { return k }, fewestSubmissionsPolicy{})
+	if !slices.Equal(ranks, []int{1, 1}) {
+		t.Errorf("ranks = %v, want [1 1]", ranks)
+	}
+	if entries[0].TeamID != 1 {
+		t.Errorf("expected ties to be ordered by team id, got %v", entries)
+	}
+}
+
+func TestSeek(t *testing.T) {
+	at := time.Date(2026, 3, 1, 10, 0, 0, 0, time.UTC)
+	entries := []Key{
+		{Score: 10, SubmittedAt: at, TeamID: 1},
+		{Score: 10, SubmittedAt: at, TeamID: 2},
+		{Score: 12, SubmittedAt: at, TeamID: 3},
+	}
+	identity := func(k Key) Key { return k }
+	p := sharedRankPolicy{}
+
+	i, err := Seek(entries, identity, p, "")
+	if err != nil || i != 0 {
+		t.Errorf("Seek(\"\") = %d, %v, want 0", i, err)
+	}
+	i, err = Seek(entries, identity, p, EncodeCursor(entries[0]))
+	if err != nil || i != 1 {
+		t.Errorf("Seek(after first) = %d, %v, want 1", i, err)
+	}
+	i, err = Seek(entries, identity, p, EncodeCursor(entries[2]))
+	if err != nil || i != 3 {
+		t.Errorf("Seek(after last) = %d, %v, want 3", i, err)
+	}
+	// The entry of the cursor may have moved or gone; the page starts after
+	// where it would be.
+	i, err = Seek(entries, identity, p, EncodeCursor(Key{Score: 11, SubmittedAt: at, TeamID: 9}))
+	if err != nil || i != 2 {
+		t.Errorf("Seek(missing key) = %d, %v, want 2", i, err)
+	}
+}
+
+func TestDecodeCursor(t *testing.T) {
+	k := Key{Score: 42, SubmissionCount: 3, SubmittedAt: time.Unix(1700000000, 123), TeamID: 7}
+	got, err := DecodeCursor(EncodeCursor(k))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.Score != k.Score || got.SubmissionCount != k.SubmissionCount || !got.SubmittedAt.Equal(k.SubmittedAt) || got.TeamID != k.TeamID {
+		t.Errorf("DecodeCursor(EncodeCursor(%+v)) = %+v", k, got)
+	}
+
+	for _, cursor := range []string{"!!!", "bm90IGEgY3Vyc29y"} {
+		if _, err := DecodeCursor(cursor); !errors.Is(err, ErrInvalidCursor) {
+			t.Errorf("DecodeCursor(%q) = %v, want ErrInvalidCursor", cursor, err)
+		}
+	}
+}
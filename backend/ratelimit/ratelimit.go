@@ -2,12 +2,15 @@ package ratelimit
 
 import (
 	"net/http"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/time/rate"
+
+	"albatross-2026-backend/session"
 )
 
 type entry struct {
@@ -71,3 +74,26 @@ func LoginRateLimitMiddleware(rl *IPRateLimiter) echo.MiddlewareFunc {
 		}
 	}
 }
+
+// RunRateLimitMiddleware limits the runs with custom input. Players at the
+// venue may share an IP address, so it counts per user when logged in. It must
+// be placed after the session middleware.
+func RunRateLimitMiddleware(rl *IPRateLimiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if c.Request().Method != http.MethodPost || !strings.HasSuffix(c.Path(), "/play/run") {
+				return next(c)
+			}
+			key := c.RealIP()
+			if user, ok := session.GetUserFromContext(c.Request().Context()); ok {
+				key = "user:" + strconv.Itoa(int(user.UserID))
+			}
+			if !rl.getLimiter(key).Allow() {
+				return c.JSON(http.StatusTooManyRequests, map[string]string{
+					"message": "Too many runs. Please try again later.",
+				})
+			}
+			return next(c)
+		}
+	}
+}
@@ -7,6 +7,9 @@ import (
 
 	"github.com/labstack/echo/v4"
 	"golang.org/x/time/rate"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/session"
 )
 
 func TestGetLimiter_SameIP(t *testing.T) {
@@ -121,3 +124,61 @@ func TestLoginRateLimitMiddleware_AllowsNonPostLogin(t *testing.T) {
 		t.Errorf("expected 200 for GET /login, got %d", rec.Code)
 	}
 }
+
+func TestRunRateLimitMiddleware_PerUser(t *testing.T) {
+	rl := &IPRateLimiter{
+		rate:  rate.Limit(0.001),
+		burst: 1,
+	}
+
+	e := echo.New()
+	handler := RunRateLimitMiddleware(rl)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+	run := func(userID int32) int {
+		req := httptest.NewRequest(http.MethodPost, "/api/games/1/play/run", nil)
+		req = req.WithContext(session.SetUserInContext(req.Context(), &db.User{UserID: userID}))
+		rec := httptest.NewRecorder()
+		c := e.NewContext(req, rec)
+		c.SetPath("/api/games/:game_id/play/run")
+		if err := handler(c); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return rec.Code
+	}
+
+	if code := run(1); code != http.StatusOK {
+		t.Errorf("first run of user 1: expected 200, got %d", code)
+	}
+	if code := run(1); code != http.StatusTooManyRequests {
+		t.Errorf("second run of user 1: expected 429, got %d", code)
+	}
+	// Another user from the same IP address is not affected.
+	if code := run(2); code != http.StatusOK {
+		t.Errorf("first run of user 2: expected 200, got %d", code)
+	}
+}
+
+func TestRunRateLimitMiddleware_AllowsSubmit(t *testing.T) {
+	rl := &IPRateLimiter{
+		rate:  rate.Limit(0),
+		burst: 0,
+	}
+
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodPost, "/api/games/1/play/submit", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+	c.SetPath("/api/games/:game_id/play/submit")
+
+	handler := RunRateLimitMiddleware(rl)(func(c echo.Context) error {
+		return c.NoContent(http.StatusOK)
+	})
+
+	if err := handler(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("expected 200 for submit, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,73 @@
+// Package rating keeps a skill rating of each player across games. The rating
+// is an Elo rating: a game with n participants counts as a match between every
+// pair of them, the ranking of the game deciding who won each match.
+package rating
+
+import "math"
+
+const (
+	// Initial is the rating of a player who has not played a rated game.
+	Initial = 1500
+	// K is the largest change of a rating in a single game.
+	K = 32
+)
+
+// Participant is a team in a rated game.
+type Participant struct {
+	// Ratings are the ratings of the members before the game. The team plays
+	// with the average of them.
+	Ratings []int
+	// Rank is the rank of the team in the game. Teams with the same rank
+	// drew.
+	Rank int
+}
+
+// Changes returns the change of the ratings of each participant. Every
+// member of a team gets the same change.
+func Changes(participants []Participant) []int {
+	changes := make([]int, len(participants))
+	if len(participants) < 2 {
+		return changes
+	}
+	ratings := make([]float64, len(participants))
+	for i, p := range participants {
+		ratings[i] = average(p.Ratings)
+	}
+	// The matches are weighted so that a game moves the rating at most K
+	// regardless of the number of participants.
+	weight := float64(K) / float64(len(participants)-1)
+	for i, p := range participants {
+		var actual, expected float64
+		for j, q := range participants {
+			if i == j {
+				continue
+			}
+			switch {
+			case p.Rank < q.Rank:
+				actual += 1
+			case p.Rank == q.Rank:
+				actual += 0.5
+			}
+			expected += expectedScore(ratings[i], ratings[j])
+		}
+		changes[i] = int(math.Round(weight * (actual - expected)))
+	}
+	return changes
+}
+
+// expectedScore is the probability that a player rated a beats a player rated
+// b.
+func expectedScore(a, b float64) float64 {
+	return 1 / (1 + math.Pow(10, (b-a)/400))
+}
+
+func average(ratings []int) float64 {
+	if len(ratings) == 0 {
+		return Initial
+	}
+	sum := 0
+	for _, r := range ratings {
+		sum += r
+	}
+	return float64(sum) / float64(len(ratings))
+}
@@ -0,0 +1,73 @@
+package rating
+
+import "testing"
+
+func TestChanges(t *testing.T) {
+	tests := []struct {
+		name         string
+		participants []Participant
+		expected     []int
+	}{
+		{
+			name: "equal ratings, win",
+			participants: []Participant{
+				{Ratings: []int{1500}, Rank: 1},
+				{Ratings: []int{1500}, Rank: 2},
+			},
+			expected: []int{16, -16},
+		},
+		{
+			name: "equal ratings, draw",
+			participants: []Participant{
+				{Ratings: []int{1500}, Rank: 1},
+				{Ratings: []int{1500}, Rank: 1},
+			},
+			expected: []int{0, 0},
+		},
+		{
+			name: "upset",
+			participants: []Participant{
+				{Ratings: []int{1400}, Rank: 1},
+				{Ratings: []int{1800}, Rank: 2},
+			},
+			expected: []int{29, -29},
+		},
+		{
+			name: "three players",
+			participants: []Participant{
+				{Ratings: []int{1500}, Rank: 2},
+				{Ratings: []int{1500}, Rank: 1},
+				{Ratings: []int{1500}, Rank: 3},
+			},
+			expected: []int{0, 16, -16},
+		},
+		{
+			name: "teams play with the average rating",
+			participants: []Participant{
+				{Ratings: []int{1300, 1700}, Rank: 1},
+				{Ratings: []int{1500}, Rank: 2},
+			},
+			expected: []int{16, -16},
+		},
+		{
+			name: "single player",
+			participants: []Participant{
+				{Ratings: []int{1500}, Rank: 1},
+			},
+			expected: []int{0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Changes(tt.participants)
+			if len(got) != len(tt.expected) {
+				t.Fatalf("expected %d changes, got %d", len(tt.expected), len(got))
+			}
+			for i, v := range tt.expected {
+				if got[i] != v {
+					t.Errorf("participant %d: expected %d, got %d", i, v, got[i])
+				}
+			}
+		})
+	}
+}
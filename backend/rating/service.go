@@ -0,0 +1,247 @@
+package rating
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/game"
+)
+
+type Service struct {
+	q   db.Querier
+	txm db.TxManager
+	// mu keeps a game from being rated twice by concurrent passes.
+	mu sync.Mutex
+}
+
+func NewService(q db.Querier, txm db.TxManager) *Service {
+	return &Service{q: q, txm: txm}
+}
+
+// Change is the change of the rating of a player by a game.
+type Change struct {
+	GameID          int
+	GameDisplayName string
+	// GameRank is the rank of the team of the player in the game.
+	GameRank     int
+	RatingBefore int
+	RatingAfter  int
+	RatedAt      time.Time
+}
+
+// Leaderboard returns the players who have played a rated game, from the
+// highest rating.
+func (s *Service) Leaderboard(ctx context.Context) ([]game.Player, error) {
+	rows, err := s.q.ListRatedUsers(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return nil, err
+	}
+	players := make([]game.Player, len(rows))
+	for i, row := range rows {
+		players[i] = game.PlayerFromUser(row)
+	}
+	return players, nil
+}
+
+// History returns the player and the changes of their rating, oldest first.
+func (s *Service) History(ctx context.Context, userID int) (game.Player, []Change, error) {
+	user, err := s.q.GetUserByID(ctx, int32(userID))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return game.Player{}, nil, game.ErrNotFound
+		}
+		return game.Player{}, nil, err
+	}
+	rows, err := s.q.ListRatingHistoryByUserID(ctx, int32(userID))
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return game.Player{}, nil, err
+	}
+	changes := make([]Change, len(rows))
+	for i, row := range rows {
+		changes[i] = Change{
+			GameID:          int(row.GameID),
+			GameDisplayName: row.GameDisplayName,
+			GameRank:        int(row.GameRank),
+			RatingBefore:    int(row.RatingBefore),
+			RatingAfter:     int(row.RatingAfter),
+			RatedAt:         row.CreatedAt.Time,
+		}
+	}
+	return game.PlayerFromUser(user), changes, nil
+}
+
+// RateFinishedGames rates the games that have finished since the last call,
+// in the order they started. It returns the number of games rated.
+func (s *Service) RateFinishedGames(ctx context.Context) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var rated int
+	err := s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		var err error
+		rated, err = rateFinishedGames(ctx, qtx, time.Now())
+		return err
+	})
+	return rated, err
+}
+
+// Recompute rates all the public games again from the initial ratings. The
+// games are replayed in the order they were first rated, so that the ratings
+// come out the same unless the results of a game have changed, e.g. by a
+// rejudge.
+func (s *Service) Recompute(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.txm.RunInTx(ctx, func(qtx db.Querier) error {
+		if err := qtx.DeleteAllRatingHistory(ctx); err != nil {
+			return err
+		}
+		if err := qtx.ResetUserRatings(ctx, Initial); err != nil {
+			return err
+		}
+		games, err := qtx.ListRatedGames(ctx)
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return err
+		}
+		for _, g := range games {
+			if err := rateGame(ctx, qtx, g); err != nil {
+				return err
+			}
+		}
+		if _, err := rateFinishedGames(ctx, qtx, time.Now()); err != nil {
+			return err
+		}
+		return qtx.UpdateRatingRanks(ctx)
+	})
+}
+
+// rateFinishedGames rates the unrated public games whose final ranking is
+// public. Private games, such as test games, never change ratings.
+func rateFinishedGames(ctx context.Context, q db.Querier, now time.Time) (int, error) {
+	games, err := q.ListUnratedGames(ctx)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return 0, err
+	}
+	rated := 0
+	for _, g := range games {
+		if !isRatable(g, now) {
+			continue
+		}
+		if err := rateGame(ctx, q, g); err != nil {
+			return rated, err
+		}
+		if err := q.UpdateGameRatedAt(ctx, g.GameID); err != nil {
+			return rated, err
+		}
+		rated++
+	}
+	if rated > 0 {
+		if err := q.UpdateRatingRanks(ctx); err != nil {
+			return rated, err
+		}
+	}
+	return rated, nil
+}
+
+// isRatable reports whether the game is public, has finished and its ranking
+// is no longer frozen. Rating a frozen game would give away the hidden results.
+func isRatable(g db.Game, now time.Time) bool {
+	if !g.IsPublic {
+		return false
+	}
+	if game.LifecycleFromGame(g).StateAt(now) != game.StateFinished {
+		return false
+	}
+	_, frozen := game.RankingCutoff(g, now)
+	return !frozen
+}
+
+// rateGame applies the final ranking of the game to the ratings of its
+// players. Teams that have solved nothing, and main players who never joined
+// a team, share the last place.
+func rateGame(ctx context.Context, q db.Querier, g db.Game) error {
+	ranked, ranks, err := game.RankedRows(ctx, q, g, time.Time{}, false)
+	if err != nil {
+		return err
+	}
+	teamRows, err := q.ListTeams(ctx, g.GameID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	memberRows, err := q.ListTeamMembers(ctx, g.GameID)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+	mainPlayers, err := q.ListMainPlayers(ctx, []int32{g.GameID})
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return err
+	}
+
+	lastRank := len(ranked) + 1
+	teamRank := make(map[int32]int, len(teamRows))
+	for _, t := range teamRows {
+		teamRank[t.TeamID] = lastRank
+	}
+	for i, r := range ranked {
+		teamRank[r.Team.TeamID] = ranks[i]
+	}
+	members := make(map[int32][]db.User)
+	inTeam := make(map[int32]bool)
+	for _, row := range memberRows {
+		members[row.TeamID] = append(members[row.TeamID], row.User)
+		inTeam[row.User.UserID] = true
+	}
+
+	var teams [][]db.User
+	var participants []Participant
+	add := func(users []db.User, rank int) {
+		ratings := make([]int, len(users))
+		for i, u := range users {
+			ratings[i] = int(u.Rating)
+		}
+		teams = append(teams, users)
+		participants = append(participants, Participant{Ratings: ratings, Rank: rank})
+	}
+	for _, t := range teamRows {
+		if len(members[t.TeamID]) > 0 {
+			add(members[t.TeamID], teamRank[t.TeamID])
+		}
+	}
+	for _, p := range mainPlayers {
+		if !inTeam[p.UserID] {
+			add([]db.User{{UserID: p.UserID, Rating: p.Rating}}, lastRank)
+		}
+	}
+	if len(participants) < 2 {
+		return nil
+	}
+
+	changes := Changes(participants)
+	for i, users := range teams {
+		for _, u := range users {
+			after := u.Rating + int32(changes[i])
+			if err := q.CreateRatingHistory(ctx, db.CreateRatingHistoryParams{
+				UserID:       u.UserID,
+				GameID:       g.GameID,
+				GameRank:     int32(participants[i].Rank),
+				RatingBefore: u.Rating,
+				RatingAfter:  after,
+			}); err != nil {
+				return err
+			}
+			if err := q.UpdateUserRating(ctx, db.UpdateUserRatingParams{
+				UserID: u.UserID,
+				Rating: after,
+			}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,167 @@
+package rating
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgtype"
+
+	"albatross-2026-backend/db"
+	"albatross-2026-backend/ranking"
+)
+
+type mockQuerier struct {
+	db.Querier
+	ranking     []db.GetRankingRow
+	teams       []db.GameTeam
+	members     []db.ListTeamMembersRow
+	mainPlayers []db.ListMainPlayersRow
+	history     []db.CreateRatingHistoryParams
+	ratings     map[int32]int32
+}
+
+func (m *mockQuerier) GetRanking(_ context.Context, _ int32) ([]db.GetRankingRow, error) {
+	return m.ranking, nil
+}
+
+func (m *mockQuerier) ListGameProblems(_ context.Context, _ []int32) ([]db.ListGameProblemsRow, error) {
+	return []db.ListGameProblemsRow{{Problem: db.Problem{ProblemID: 1}}}, nil
+}
+
+func (m *mockQuerier) ListTeams(_ context.Context, _ int32) ([]db.GameTeam, error) {
+	return m.teams, nil
+}
+
+func (m *mockQuerier) ListTeamMembers(_ context.Context, _ int32) ([]db.ListTeamMembersRow, error) {
+	return m.members, nil
+}
+
+func (m *mockQuerier) ListMainPlayers(_ context.Context, _ []int32) ([]db.ListMainPlayersRow, error) {
+	return m.mainPlayers, nil
+}
+
+func (m *mockQuerier) CreateRatingHistory(_ context.Context, arg db.CreateRatingHistoryParams) error {
+	m.history = append(m.history, arg)
+	return nil
+}
+
+func (m *mockQuerier) UpdateUserRating(_ context.Context, arg db.UpdateUserRatingParams) error {
+	if m.ratings == nil {
+		m.ratings = make(map[int32]int32)
+	}
+	m.ratings[arg.UserID] = arg.Rating
+	return nil
+}
+
+func TestRateGame_MainPlayerWithoutTeamLoses(t *testing.T) {
+	now := time.Now()
+	q := &mockQuerier{
+		ranking: []db.GetRankingRow{
+			{
+				Submission: db.Submission{
+					TeamID:    10,
+					ProblemID: 1,
+					CodeSize:  42,
+					CreatedAt: pgtype.Timestamp{Time: now, Valid: true},
+				},
+				GameTeam:        db.GameTeam{TeamID: 10, GameID: 1},
+				SubmissionCount: 1,
+			},
+		},
+		teams: []db.GameTeam{{TeamID: 10, GameID: 1}},
+		members: []db.ListTeamMembersRow{
+			{TeamID: 10, User: db.User{UserID: 1, Rating: 1500}},
+		},
+		mainPlayers: []db.ListMainPlayersRow{
+			{GameID: 1, UserID: 1, Rating: 1500},
+			{GameID: 1, UserID: 2, Rating: 1500},
+		},
+	}
+	g := db.Game{GameID: 1, TieBreak: ranking.Default}
+
+	if err := rateGame(context.Background(), q, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(q.history) != 2 {
+		t.Fatalf("expected 2 history rows, got %d", len(q.history))
+	}
+	if q.ratings[1] != 1516 {
+		t.Errorf("expected the winner to be rated 1516, got %d", q.ratings[1])
+	}
+	if q.ratings[2] != 1484 {
+		t.Errorf("expected the loser to be rated 1484, got %d", q.ratings[2])
+	}
+	for _, h := range q.history {
+		if h.UserID == 2 && h.GameRank != 2 {
+			t.Errorf("expected the loser to be ranked 2, got %d", h.GameRank)
+		}
+	}
+}
+
+func TestRateGame_SinglePlayerIsNotRated(t *testing.T) {
+	q := &mockQuerier{
+		teams: []db.GameTeam{{TeamID: 10, GameID: 1}},
+		members: []db.ListTeamMembersRow{
+			{TeamID: 10, User: db.User{UserID: 1, Rating: 1500}},
+		},
+	}
+	g := db.Game{GameID: 1, TieBreak: ranking.Default}
+
+	if err := rateGame(context.Background(), q, g); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(q.history) != 0 {
+		t.Errorf("expected no history, got %d rows", len(q.history))
+	}
+}
+
+func TestIsRatable(t *testing.T) {
+	now := time.Now()
+	started := pgtype.Timestamp{Time: now.Add(-2 * time.Hour), Valid: true}
+	tests := []struct {
+		name     string
+		game     db.Game
+		expected bool
+	}{
+		{
+			name:     "running",
+			game:     db.Game{IsPublic: true, StartedAt: started, DurationSeconds: 3 * 3600},
+			expected: false,
+		},
+		{
+			name:     "finished",
+			game:     db.Game{IsPublic: true, StartedAt: started, DurationSeconds: 3600},
+			expected: true,
+		},
+		{
+			name:     "finished but private",
+			game:     db.Game{StartedAt: started, DurationSeconds: 3600},
+			expected: false,
+		},
+		{
+			name:     "finished but frozen",
+			game:     db.Game{IsPublic: true, StartedAt: started, DurationSeconds: 3600, FreezeSeconds: 600},
+			expected: false,
+		},
+		{
+			name: "finished and revealed",
+			game: db.Game{
+				IsPublic:        true,
+				StartedAt:       started,
+				DurationSeconds: 3600,
+				FreezeSeconds:   600,
+				RevealedUntil:   pgtype.Timestamp{Time: now.Add(-time.Hour), Valid: true},
+			},
+			expected: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRatable(tt.game, now); got != tt.expected {
+				t.Errorf("expected %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
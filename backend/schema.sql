@@ -5,6 +5,8 @@ CREATE TABLE users (
     icon_path    VARCHAR(255),
     is_admin     BOOLEAN     NOT NULL,
     label        VARCHAR(16),
+    rating       INT         NOT NULL DEFAULT 1500,
+    rating_rank  INT,
     created_at   TIMESTAMP   NOT NULL DEFAULT NOW()
 );
 CREATE INDEX idx_users_username ON users(username);
@@ -19,11 +21,32 @@ CREATE TABLE user_auths (
 CREATE INDEX idx_user_auths_user_id ON user_auths(user_id);
 
 CREATE TABLE problems (
-    problem_id  SERIAL       PRIMARY KEY,
-    title       VARCHAR(255) NOT NULL,
-    description TEXT         NOT NULL,
-    language    VARCHAR(8)   NOT NULL,
-    sample_code TEXT         NOT NULL
+    problem_id      SERIAL           PRIMARY KEY,
+    title           VARCHAR(255)     NOT NULL,
+    description     TEXT             NOT NULL,
+    language        VARCHAR(8)       NOT NULL,
+    sample_code     TEXT             NOT NULL,
+    scoring         VARCHAR(32)      NOT NULL DEFAULT 'stripped_bytes',
+    checker         VARCHAR(32)      NOT NULL DEFAULT 'exact',
+    checker_epsilon DOUBLE PRECISION NOT NULL DEFAULT 0,
+    checker_code    TEXT             NOT NULL DEFAULT '',
+    -- time_limit_ms and memory_limit_mib limit each run of a submission. NULL
+    -- means the defaults of the workers.
+    time_limit_ms    INT,
+    memory_limit_mib INT,
+    -- judge_changed_at is when the language, the checker or the limits last
+    -- changed, which outdates the validation of the problem.
+    judge_changed_at TIMESTAMP       NOT NULL DEFAULT NOW()
+);
+
+-- problem_languages lists the languages a problem accepts besides its own
+-- language, which the checker and the reference solutions are written in.
+CREATE TABLE problem_languages (
+    problem_id  INT        NOT NULL,
+    language    VARCHAR(8) NOT NULL,
+    sample_code TEXT       NOT NULL,
+    PRIMARY KEY (problem_id, language),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
 );
 
 CREATE TABLE games (
@@ -34,10 +57,26 @@ CREATE TABLE games (
     duration_seconds INT          NOT NULL,
     created_at       TIMESTAMP    NOT NULL DEFAULT NOW(),
     started_at       TIMESTAMP,
-    problem_id       INT          NOT NULL,
-    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
+    paused_at        TIMESTAMP,
+    override_state   VARCHAR(16),
+    freeze_seconds   INT          NOT NULL DEFAULT 0,
+    revealed_until   TIMESTAMP,
+    tie_break        VARCHAR(32)  NOT NULL DEFAULT 'earliest_submission',
+    unsolved_penalty INT          NOT NULL DEFAULT 0,
+    allow_practice   BOOLEAN      NOT NULL DEFAULT false,
+    rated_at         TIMESTAMP
 );
-CREATE INDEX idx_games_problem_id ON games(problem_id);
+
+CREATE TABLE game_problems (
+    game_id    INT NOT NULL,
+    problem_id INT NOT NULL,
+    position   INT NOT NULL,
+    PRIMARY KEY (game_id, problem_id),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
+    CONSTRAINT uq_game_id_position UNIQUE(game_id, position)
+);
+CREATE INDEX idx_game_problems_problem_id ON game_problems(problem_id);
 
 CREATE TABLE game_main_players (
     game_id INT NOT NULL,
@@ -47,36 +86,103 @@ CREATE TABLE game_main_players (
     CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
 );
 
+CREATE TABLE game_teams (
+    team_id      SERIAL       PRIMARY KEY,
+    game_id      INT          NOT NULL,
+    display_name VARCHAR(255) NOT NULL,
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id)
+);
+CREATE INDEX idx_game_teams_game_id ON game_teams(game_id);
+
+CREATE TABLE game_team_members (
+    team_id INT NOT NULL,
+    game_id INT NOT NULL,
+    user_id INT NOT NULL,
+    PRIMARY KEY (team_id, user_id),
+    CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+    CONSTRAINT uq_game_id_user_id UNIQUE(game_id, user_id)
+);
+
+CREATE TABLE game_lifecycle_events (
+    game_lifecycle_event_id SERIAL      PRIMARY KEY,
+    game_id                 INT         NOT NULL,
+    action                  VARCHAR(16) NOT NULL,
+    from_state              VARCHAR(16) NOT NULL,
+    to_state                VARCHAR(16) NOT NULL,
+    started_at              TIMESTAMP,
+    duration_seconds        INT         NOT NULL,
+    user_id                 INT,
+    created_at              TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
+);
+CREATE INDEX idx_game_lifecycle_events_game_id ON game_lifecycle_events(game_id);
+
 CREATE TABLE submissions (
     submission_id SERIAL      PRIMARY KEY,
     game_id       INT         NOT NULL,
+    team_id       INT         NOT NULL,
     user_id       INT         NOT NULL,
+    problem_id    INT         NOT NULL,
+    language      VARCHAR(8)  NOT NULL,
     code          TEXT        NOT NULL,
     code_size     INT         NOT NULL,
     status        VARCHAR(16) NOT NULL,
+    is_practice   BOOLEAN     NOT NULL DEFAULT false,
     created_at    TIMESTAMP   NOT NULL DEFAULT NOW(),
     CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
-    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
+    CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
 );
-CREATE INDEX idx_submissions_game_id_user_id ON submissions(game_id, user_id);
+CREATE INDEX idx_submissions_game_id_team_id ON submissions(game_id, team_id);
+CREATE INDEX idx_submissions_problem_id ON submissions(problem_id);
 
 CREATE TABLE game_states (
     game_id INT NOT NULL,
-    user_id INT NOT NULL,
+    team_id INT NOT NULL,
+    problem_id INT NOT NULL,
+    language VARCHAR(8) NOT NULL,
     code TEXT NOT NULL,
     status VARCHAR(16) NOT NULL,
     best_score_submission_id INT,
-    PRIMARY KEY (game_id, user_id),
+    PRIMARY KEY (game_id, team_id, problem_id),
     CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
-    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
     CONSTRAINT fk_best_score_submission_id FOREIGN KEY(best_score_submission_id) REFERENCES submissions(submission_id)
 );
 
+CREATE TABLE code_snapshots (
+    code_snapshot_id SERIAL    PRIMARY KEY,
+    game_id          INT       NOT NULL,
+    team_id          INT       NOT NULL,
+    user_id          INT       NOT NULL,
+    problem_id       INT       NOT NULL,
+    is_submission    BOOLEAN   NOT NULL,
+    keep_prefix      INT       NOT NULL,
+    keep_suffix      INT       NOT NULL,
+    inserted         TEXT      NOT NULL,
+    created_at       TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
+);
+CREATE INDEX idx_code_snapshots_game_id_team_id_problem_id ON code_snapshots(game_id, team_id, problem_id);
+
 CREATE TABLE testcases (
-    testcase_id SERIAL PRIMARY KEY,
-    problem_id  INT    NOT NULL,
-    stdin       TEXT   NOT NULL,
-    stdout      TEXT   NOT NULL,
+    testcase_id SERIAL  PRIMARY KEY,
+    problem_id  INT     NOT NULL,
+    stdin       TEXT    NOT NULL,
+    stdout      TEXT    NOT NULL,
+    is_sample   BOOLEAN NOT NULL DEFAULT false,
+    -- time_limit_ms and memory_limit_mib override those of the problem unless
+    -- NULL.
+    time_limit_ms    INT,
+    memory_limit_mib INT,
     CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
 );
 CREATE INDEX idx_testcases_problem_id ON testcases(problem_id);
@@ -88,6 +194,10 @@ CREATE TABLE testcase_results (
     status             VARCHAR(16) NOT NULL,
     stdout             TEXT        NOT NULL,
     stderr             TEXT        NOT NULL,
+    -- time_ms and memory_bytes are the wall time and the peak memory measured
+    -- by the worker, NULL if it did not report them.
+    time_ms            INT,
+    memory_bytes       BIGINT,
     created_at         TIMESTAMP   NOT NULL DEFAULT NOW(),
     CONSTRAINT fk_submission_id FOREIGN KEY(submission_id) REFERENCES submissions(submission_id),
     CONSTRAINT fk_testcase_id FOREIGN KEY(testcase_id) REFERENCES testcases(testcase_id),
@@ -95,6 +205,129 @@ CREATE TABLE testcase_results (
 );
 CREATE INDEX idx_testcase_results_submission_id ON testcase_results(submission_id);
 
+CREATE TABLE testcase_revisions (
+    testcase_revision_id SERIAL      PRIMARY KEY,
+    testcase_id          INT         NOT NULL,
+    problem_id           INT         NOT NULL,
+    action               VARCHAR(16) NOT NULL,
+    stdin                TEXT        NOT NULL,
+    stdout               TEXT        NOT NULL,
+    is_sample            BOOLEAN     NOT NULL,
+    affects_results      BOOLEAN     NOT NULL,
+    user_id              INT,
+    created_at           TIMESTAMP   NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
+);
+CREATE INDEX idx_testcase_revisions_problem_id ON testcase_revisions(problem_id);
+
+CREATE TABLE rejudges (
+    rejudge_id  SERIAL       PRIMARY KEY,
+    problem_id  INT          NOT NULL,
+    reason      VARCHAR(255) NOT NULL,
+    user_id     INT,
+    created_at  TIMESTAMP    NOT NULL DEFAULT NOW(),
+    finished_at TIMESTAMP,
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
+);
+
+CREATE TABLE rejudge_submissions (
+    rejudge_id    INT         NOT NULL,
+    submission_id INT         NOT NULL,
+    status_before VARCHAR(16) NOT NULL,
+    PRIMARY KEY (rejudge_id, submission_id),
+    CONSTRAINT fk_rejudge_id FOREIGN KEY(rejudge_id) REFERENCES rejudges(rejudge_id),
+    CONSTRAINT fk_submission_id FOREIGN KEY(submission_id) REFERENCES submissions(submission_id)
+);
+CREATE INDEX idx_rejudge_submissions_submission_id ON rejudge_submissions(submission_id);
+
+CREATE TABLE rejudge_rankings (
+    rejudge_id INT        NOT NULL,
+    game_id    INT        NOT NULL,
+    team_id    INT        NOT NULL,
+    phase      VARCHAR(8) NOT NULL,
+    rank       INT        NOT NULL,
+    score      INT        NOT NULL,
+    PRIMARY KEY (rejudge_id, game_id, team_id, phase),
+    CONSTRAINT fk_rejudge_id FOREIGN KEY(rejudge_id) REFERENCES rejudges(rejudge_id),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT fk_team_id FOREIGN KEY(team_id) REFERENCES game_teams(team_id)
+);
+
+CREATE TABLE reference_solutions (
+    reference_solution_id SERIAL       PRIMARY KEY,
+    problem_id            INT          NOT NULL,
+    name                  VARCHAR(255) NOT NULL,
+    code                  TEXT         NOT NULL,
+    created_at            TIMESTAMP    NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id)
+);
+CREATE INDEX idx_reference_solutions_problem_id ON reference_solutions(problem_id);
+
+-- problem_validations are runs of the reference solutions of a problem
+-- against all its testcases. status is one of 'running', 'success' and
+-- 'failure'.
+CREATE TABLE problem_validations (
+    problem_validation_id SERIAL      PRIMARY KEY,
+    problem_id            INT         NOT NULL,
+    status                VARCHAR(16) NOT NULL DEFAULT 'running',
+    task_count            INT         NOT NULL,
+    user_id               INT,
+    created_at            TIMESTAMP   NOT NULL DEFAULT NOW(),
+    finished_at           TIMESTAMP,
+    CONSTRAINT fk_problem_id FOREIGN KEY(problem_id) REFERENCES problems(problem_id),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id)
+);
+CREATE INDEX idx_problem_validations_problem_id ON problem_validations(problem_id);
+
+-- The reference solutions and the testcases may be deleted after the
+-- validation, so they are not foreign keys.
+CREATE TABLE problem_validation_results (
+    problem_validation_id INT         NOT NULL,
+    reference_solution_id INT         NOT NULL,
+    testcase_id           INT         NOT NULL,
+    status                VARCHAR(16) NOT NULL,
+    stdout                TEXT        NOT NULL,
+    stderr                TEXT        NOT NULL,
+    PRIMARY KEY (problem_validation_id, reference_solution_id, testcase_id),
+    CONSTRAINT fk_problem_validation_id FOREIGN KEY(problem_validation_id) REFERENCES problem_validations(problem_validation_id)
+);
+
+CREATE TABLE qualifying_stages (
+    qualifying_stage_id SERIAL       PRIMARY KEY,
+    display_name        VARCHAR(255) NOT NULL,
+    is_public           BOOLEAN      NOT NULL DEFAULT false,
+    missing_policy      VARCHAR(16)  NOT NULL DEFAULT 'penalty',
+    missing_penalty     INT          NOT NULL DEFAULT 0,
+    created_at          TIMESTAMP    NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE qualifying_stage_games (
+    qualifying_stage_id INT              NOT NULL,
+    game_id             INT              NOT NULL,
+    position            INT              NOT NULL,
+    weight              DOUBLE PRECISION NOT NULL DEFAULT 1,
+    PRIMARY KEY (qualifying_stage_id, game_id),
+    CONSTRAINT fk_qualifying_stage_id FOREIGN KEY(qualifying_stage_id) REFERENCES qualifying_stages(qualifying_stage_id),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT uq_qualifying_stage_id_position UNIQUE(qualifying_stage_id, position)
+);
+
+CREATE TABLE rating_history (
+    rating_history_id SERIAL    PRIMARY KEY,
+    user_id           INT       NOT NULL,
+    game_id           INT       NOT NULL,
+    game_rank         INT       NOT NULL,
+    rating_before     INT       NOT NULL,
+    rating_after      INT       NOT NULL,
+    created_at        TIMESTAMP NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_user_id FOREIGN KEY(user_id) REFERENCES users(user_id),
+    CONSTRAINT fk_game_id FOREIGN KEY(game_id) REFERENCES games(game_id),
+    CONSTRAINT uq_rating_history_user_id_game_id UNIQUE(user_id, game_id)
+);
+CREATE INDEX idx_rating_history_game_id ON rating_history(game_id);
+
 CREATE TABLE sessions (
     session_id  VARCHAR(64) PRIMARY KEY,
     user_id     INT         NOT NULL,
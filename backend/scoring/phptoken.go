@@ -0,0 +1,401 @@
+package scoring
+
+import (
+	"regexp"
+	"strings"
+)
+
+// phpLexer is a simplified port of PHP's lexer. It is only precise enough to
+// count tokens: it does not classify them, and it assumes the code is valid.
+type phpLexer struct {
+	src    string
+	pos    int
+	tokens []string
+}
+
+// tokenizePHP splits PHP code into tokens, dropping whitespace, comments, and
+// open and close tags.
+func tokenizePHP(src string) []string {
+	l := &phpLexer{src: src}
+	l.lexInlineHTML()
+	return l.tokens
+}
+
+func (l *phpLexer) emit(n int) {
+	end := min(l.pos+n, len(l.src))
+	l.tokens = append(l.tokens, l.src[l.pos:end])
+	l.pos = end
+}
+
+func (l *phpLexer) lexInlineHTML() {
+	for l.pos < len(l.src) {
+		idx := strings.Index(l.src[l.pos:], "<?")
+		if idx < 0 {
+			l.emit(len(l.src) - l.pos)
+			return
+		}
+		if idx > 0 {
+			l.emit(idx)
+		}
+		rest := l.src[l.pos:]
+		switch {
+		case len(rest) >= 5 && strings.EqualFold(rest[:5], "<?php") && (len(rest) == 5 || isSpace(rest[5])):
+			l.pos += min(6, len(rest))
+		case strings.HasPrefix(rest, "<?="):
+			l.emit(3)
+		default:
+			l.pos += 2
+		}
+		l.lexPHP(false)
+	}
+}
+
+// lexPHP lexes PHP code until a close tag. If untilBrace is set, it instead
+// stops after the "}" that closes an interpolation in a string.
+func (l *phpLexer) lexPHP(untilBrace bool) {
+	depth := 0
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		c := rest[0]
+		switch {
+		case isSpace(c):
+			l.pos++
+		case !untilBrace && strings.HasPrefix(rest, "?>"):
+			// A close tag swallows a single newline following it.
+			l.pos += 2
+			if strings.HasPrefix(l.src[l.pos:], "\r\n") {
+				l.pos += 2
+			} else if strings.HasPrefix(l.src[l.pos:], "\n") {
+				l.pos++
+			}
+			return
+		case strings.HasPrefix(rest, "#["):
+			l.emit(2)
+		case c == '#' || strings.HasPrefix(rest, "//"):
+			l.skipLineComment()
+		case strings.HasPrefix(rest, "/*"):
+			end := strings.Index(rest[2:], "*/")
+			if end < 0 {
+				l.pos = len(l.src)
+			} else {
+				l.pos += end + 4
+			}
+		case c == '$' && len(rest) > 1 && isIdentStart(rest[1]):
+			l.emit(1 + identLen(rest[1:]))
+		case isIdentStart(c) || (c == '\\' && len(rest) > 1 && isIdentStart(rest[1])):
+			l.emit(nameLen(rest))
+		case isDigit(c) || (c == '.' && len(rest) > 1 && isDigit(rest[1])):
+			l.emit(numberLen(rest))
+		case c == '\'':
+			l.emit(singleQuotedLen(rest))
+		case c == '"' || c == '`':
+			l.lexQuoted(c)
+		case strings.HasPrefix(rest, "<<<") && l.lexHeredoc():
+		case c == '(' && castRe.MatchString(rest):
+			l.emit(len(castRe.FindString(rest)))
+		case untilBrace && c == '{':
+			depth++
+			l.emit(1)
+		case untilBrace && c == '}':
+			l.emit(1)
+			if depth == 0 {
+				return
+			}
+			depth--
+		default:
+			l.emit(operatorLen(rest))
+		}
+	}
+}
+
+func (l *phpLexer) skipLineComment() {
+	for l.pos < len(l.src) {
+		rest := l.src[l.pos:]
+		if rest[0] == '\n' || strings.HasPrefix(rest, "?>") {
+			return
+		}
+		l.pos++
+	}
+}
+
+// lexQuoted lexes a double-quoted or backquoted string. A double-quoted string
+// without interpolation is a single token.
+func (l *phpLexer) lexQuoted(quote byte) {
+	end, interpolated := scanQuoted(l.src, l.pos+1, quote)
+	if !interpolated && quote == '"' {
+		l.emit(end - l.pos)
+		return
+	}
+	closed := end-1 > l.pos && l.src[end-1] == quote
+	l.emit(1)
+	limit := end
+	if closed {
+		limit = end - 1
+	}
+	l.lexEncapsed(limit)
+	if closed {
+		l.emit(1)
+	}
+}
+
+// scanQuoted returns the index right after the closing quote and whether the
+// string contains interpolation.
+func scanQuoted(src string, i int, quote byte) (int, bool) {
+	interpolated := false
+	for i < len(src) {
+		switch c := src[i]; {
+		case c == '\\':
+			i += 2
+			continue
+		case c == quote:
+			return i + 1, interpolated
+		case c == '$' && i+1 < len(src) && (isIdentStart(src[i+1]) || src[i+1] == '{'):
+			interpolated = true
+		case c == '{' && i+1 < len(src) && src[i+1] == '$':
+			interpolated = true
+		}
+		i++
+	}
+	return len(src), interpolated
+}
+
+// lexEncapsed lexes the contents of an interpolated string up to limit. The
+// code interpolated in it is lexed as if the source ended at limit, so that
+// malformed code cannot take the lexer past the end of the string and make it
+// lex the same code again.
+func (l *phpLexer) lexEncapsed(limit int) {
+	src := l.src
+	l.src = src[:limit]
+	defer func() { l.src = src }()
+
+	litStart := l.pos
+	flush := func() {
+		if l.pos > litStart {
+			l.tokens = append(l.tokens, l.src[litStart:l.pos])
+		}
+	}
+	for l.pos < limit {
+		rest := l.src[l.pos:]
+		switch {
+		case rest[0] == '\\':
+			l.pos = min(l.pos+2, limit)
+		case rest[0] == '$' && len(rest) > 1 && isIdentStart(rest[1]):
+			flush()
+			l.emit(1 + identLen(rest[1:]))
+			l.lexSimpleInterpolationTail()
+			litStart = l.pos
+		case strings.HasPrefix(rest, "{$"):
+			flush()
+			l.emit(1)
+			l.lexPHP(true)
+			litStart = l.pos
+		case strings.HasPrefix(rest, "${"):
+			flush()
+			l.emit(2)
+			r := l.src[l.pos:]
+			if n := identLen(r); n > 0 && n < len(r) && r[n] == '}' {
+				l.emit(n)
+				l.emit(1)
+			} else {
+				l.lexPHP(true)
+			}
+			litStart = l.pos
+		default:
+			l.pos++
+		}
+	}
+	flush()
+}
+
+// lexSimpleInterpolationTail lexes "[...]" or "->prop" following a variable
+// interpolated without braces.
+func (l *phpLexer) lexSimpleInterpolationTail() {
+	rest := l.src[l.pos:]
+	switch {
+	case strings.HasPrefix(rest, "["):
+		l.emit(1)
+		r := l.src[l.pos:]
+		switch {
+		case len(r) > 1 && r[0] == '-' && isDigit(r[1]):
+			l.emit(1)
+			l.emit(identLen(l.src[l.pos:]))
+		case len(r) > 0 && (isDigit(r[0]) || isIdentStart(r[0])):
+			l.emit(identLen(r))
+		case len(r) > 1 && r[0] == '$' && isIdentStart(r[1]):
+			l.emit(1 + identLen(r[1:]))
+		}
+		if strings.HasPrefix(l.src[l.pos:], "]") {
+			l.emit(1)
+		}
+	case strings.HasPrefix(rest, "->") && len(rest) > 2 && isIdentStart(rest[2]):
+		l.emit(2)
+		l.emit(identLen(l.src[l.pos:]))
+	case strings.HasPrefix(rest, "?->") && len(rest) > 3 && isIdentStart(rest[3]):
+		l.emit(3)
+		l.emit(identLen(l.src[l.pos:]))
+	}
+}
+
+var heredocStartRe = regexp.MustCompile(`^<<<[ \t]*(?:([A-Za-z_\x80-\xff][A-Za-z0-9_\x80-\xff]*)|"([A-Za-z_\x80-\xff][A-Za-z0-9_\x80-\xff]*)"|'([A-Za-z_\x80-\xff][A-Za-z0-9_\x80-\xff]*)')\r?\n`)
+
+// lexHeredoc lexes a heredoc or nowdoc. It returns false if the code at the
+// current position does not start one.
+func (l *phpLexer) lexHeredoc() bool {
+	m := heredocStartRe.FindStringSubmatch(l.src[l.pos:])
+	if m == nil {
+		return false
+	}
+	label := m[1] + m[2] + m[3]
+	isNowdoc := m[3] != ""
+	l.emit(len(m[0]))
+
+	bodyStart := l.pos
+	bodyEnd, closeStart, closeEnd := len(l.src), len(l.src), len(l.src)
+	for lineStart := bodyStart; lineStart < len(l.src); {
+		i := lineStart
+		for i < len(l.src) && (l.src[i] == ' ' || l.src[i] == '\t') {
+			i++
+		}
+		if strings.HasPrefix(l.src[i:], label) && (i+len(label) == len(l.src) || !isIdentChar(l.src[i+len(label)])) {
+			bodyEnd = max(bodyStart, lineStart-1)
+			closeStart = lineStart
+			closeEnd = i + len(label)
+			break
+		}
+		next := strings.IndexByte(l.src[lineStart:], '\n')
+		if next < 0 {
+			break
+		}
+		lineStart += next + 1
+	}
+
+	if isNowdoc {
+		if bodyEnd > bodyStart {
+			l.emit(bodyEnd - bodyStart)
+		}
+	} else {
+		l.lexEncapsed(bodyEnd)
+	}
+	l.pos = closeStart
+	if closeStart < len(l.src) {
+		l.emit(closeEnd - closeStart)
+	}
+	return true
+}
+
+var castRe = regexp.MustCompile(`^(?i)\([ \t]*(?:int|integer|bool|boolean|float|double|real|string|binary|array|object|unset)[ \t]*\)`)
+
+var operators = []string{
+	"<<=", ">>=", "**=", "...", "<=>", "===", "!==", "??=", "?->",
+	"++", "--", "->", "=>", "::", "==", "!=", "<>", "<=", ">=", "&&", "||", "??",
+	"+=", "-=", "*=", "/=", ".=", "%=", "&=", "|=", "^=", "<<", ">>", "**",
+}
+
+func operatorLen(s string) int {
+	for _, op := range operators {
+		if strings.HasPrefix(s, op) {
+			return len(op)
+		}
+	}
+	return 1
+}
+
+func numberLen(s string) int {
+	if len(s) > 2 && s[0] == '0' {
+		var isValid func(byte) bool
+		switch s[1] {
+		case 'x', 'X':
+			isValid = func(c byte) bool {
+				return isDigit(c) || ('a' <= c && c <= 'f') || ('A' <= c && c <= 'F')
+			}
+		case 'b', 'B':
+			isValid = func(c byte) bool { return c == '0' || c == '1' }
+		case 'o', 'O':
+			isValid = func(c byte) bool { return '0' <= c && c <= '7' }
+		}
+		if isValid != nil {
+			i := 2
+			for i < len(s) && (isValid(s[i]) || s[i] == '_') {
+				i++
+			}
+			return i
+		}
+	}
+	i := 0
+	for i < len(s) && (isDigit(s[i]) || s[i] == '_') {
+		i++
+	}
+	if i < len(s) && s[i] == '.' {
+		i++
+		for i < len(s) && (isDigit(s[i]) || s[i] == '_') {
+			i++
+		}
+	}
+	if i < len(s) && (s[i] == 'e' || s[i] == 'E') {
+		j := i + 1
+		if j < len(s) && (s[j] == '+' || s[j] == '-') {
+			j++
+		}
+		if j < len(s) && isDigit(s[j]) {
+			i = j
+			for i < len(s) && isDigit(s[i]) {
+				i++
+			}
+		}
+	}
+	return i
+}
+
+func singleQuotedLen(s string) int {
+	i := 1
+	for i < len(s) {
+		switch s[i] {
+		case '\\':
+			i += 2
+		case '\'':
+			return i + 1
+		default:
+			i++
+		}
+	}
+	return len(s)
+}
+
+// nameLen returns the length of a possibly namespaced name such as
+// "\Foo\bar".
+func nameLen(s string) int {
+	i := 0
+	if s[0] == '\\' {
+		i++
+	}
+	i += identLen(s[i:])
+	for i+1 < len(s) && s[i] == '\\' && isIdentStart(s[i+1]) {
+		i++
+		i += identLen(s[i:])
+	}
+	return i
+}
+
+func identLen(s string) int {
+	i := 0
+	for i < len(s) && isIdentChar(s[i]) {
+		i++
+	}
+	return i
+}
+
+func isSpace(c byte) bool {
+	return c == ' ' || c == '\t' || c == '\n' || c == '\r'
+}
+
+func isDigit(c byte) bool {
+	return '0' <= c && c <= '9'
+}
+
+func isIdentStart(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || c >= 0x80
+}
+
+func isIdentChar(c byte) bool {
+	return isIdentStart(c) || isDigit(c)
+}
@@ -0,0 +1,88 @@
+// Package scoring measures the size of submitted code. Each problem chooses
+// one of the strategies by name.
+package scoring
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	Bytes         = "bytes"
+	Codepoints    = "codepoints"
+	StrippedBytes = "stripped_bytes"
+	PHPTokens     = "php_tokens"
+)
+
+// Default is the strategy used when a problem does not specify one.
+const Default = StrippedBytes
+
+// Names lists all the strategies in the order shown to admins.
+var Names = []string{StrippedBytes, Bytes, Codepoints, PHPTokens}
+
+var ErrUnknownStrategy = errors.New("unknown scoring strategy")
+
+type Strategy interface {
+	Score(code, language string) int
+}
+
+func New(name string) (Strategy, error) {
+	switch name {
+	case Bytes:
+		return bytesStrategy{}, nil
+	case Codepoints:
+		return codepointsStrategy{}, nil
+	case StrippedBytes:
+		return strippedBytesStrategy{}, nil
+	case PHPTokens:
+		return phpTokensStrategy{}, nil
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnknownStrategy, name)
+	}
+}
+
+// SupportsLanguage reports whether the strategy can score code written in the
+// language.
+func SupportsLanguage(name, language string) bool {
+	if name == PHPTokens {
+		return language == "php"
+	}
+	return true
+}
+
+type bytesStrategy struct{}
+
+func (bytesStrategy) Score(code, _ string) int {
+	return len(code)
+}
+
+type codepointsStrategy struct{}
+
+func (codepointsStrategy) Score(code, _ string) int {
+	return utf8.RuneCountInString(code)
+}
+
+var whitespaceRe = regexp.MustCompile(`\s+`)
+
+// strippedBytesStrategy counts bytes except whitespace. For PHP, the open and
+// close tags are not counted either.
+type strippedBytesStrategy struct{}
+
+func (strippedBytesStrategy) Score(code, language string) int {
+	trimmed := whitespaceRe.ReplaceAllString(code, "")
+	if language == "php" {
+		return len(strings.TrimSuffix(strings.TrimPrefix(strings.TrimPrefix(trimmed, "<?php"), "<?"), "?>"))
+	}
+	return len(trimmed)
+}
+
+// phpTokensStrategy counts the tokens returned by PHP's token_get_all(),
+// except whitespace, comments, and open and close tags.
+type phpTokensStrategy struct{}
+
+func (phpTokensStrategy) Score(code, _ string) int {
+	return len(tokenizePHP(code))
+}
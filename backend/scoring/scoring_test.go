@@ -0,0 +1,245 @@
+package scoring
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestNew_UnknownStrategy(t *testing.T) {
+	_, err := New("lines")
+	if !errors.Is(err, ErrUnknownStrategy) {
+		t.Errorf("expected ErrUnknownStrategy, got %v", err)
+	}
+}
+
+func TestNew_AllNames(t *testing.T) {
+	for _, name := range Names {
+		if _, err := New(name); err != nil {
+			t.Errorf("New(%q) returned error: %v", name, err)
+		}
+	}
+}
+
+func TestScore(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy string
+		code     string
+		language string
+		want     int
+	}{
+		{
+			name:     "bytes counts everything",
+			strategy: Bytes,
+			code:     "<?php echo 1;\n",
+			language: "php",
+			want:     14,
+		},
+		{
+			name:     "bytes counts multi-byte characters as bytes",
+			strategy: Bytes,
+			code:     "あ",
+			language: "swift",
+			want:     3,
+		},
+		{
+			name:     "codepoints counts multi-byte characters once",
+			strategy: Codepoints,
+			code:     "print(\"あい\")",
+			language: "swift",
+			want:     11,
+		},
+		{
+			name:     "stripped bytes removes whitespace",
+			strategy: StrippedBytes,
+			code:     "print( 1 )\n",
+			language: "swift",
+			want:     8,
+		},
+		{
+			name:     "stripped bytes removes php tags",
+			strategy: StrippedBytes,
+			code:     "<?php echo   1 ;  ?>",
+			language: "php",
+			want:     6,
+		},
+		{
+			name:     "stripped bytes removes short open tag",
+			strategy: StrippedBytes,
+			code:     "<? echo 1;",
+			language: "php",
+			want:     6,
+		},
+		{
+			name:     "stripped bytes keeps tags for non-php",
+			strategy: StrippedBytes,
+			code:     "<?php",
+			language: "swift",
+			want:     5,
+		},
+		{
+			name:     "php tokens",
+			strategy: PHPTokens,
+			code:     "<?php echo 1 + 2;",
+			language: "php",
+			want:     5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, err := New(tt.strategy)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got := s.Score(tt.code, tt.language); got != tt.want {
+				t.Errorf("Score(%q, %q) = %d, want %d", tt.code, tt.language, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSupportsLanguage(t *testing.T) {
+	if !SupportsLanguage(PHPTokens, "php") {
+		t.Error("expected php_tokens to support php")
+	}
+	if SupportsLanguage(PHPTokens, "swift") {
+		t.Error("expected php_tokens not to support swift")
+	}
+	if !SupportsLanguage(Bytes, "swift") {
+		t.Error("expected bytes to support swift")
+	}
+}
+
+func TestTokenizePHP(t *testing.T) {
+	tests := []struct {
+		name string
+		code string
+		want []string
+	}{
+		{
+			name: "simple statement",
+			code: "<?php echo 1;",
+			want: []string{"echo", "1", ";"},
+		},
+		{
+			name: "comments and close tag are ignored",
+			code: "<?php // comment\necho 1; # another\n/* block */ ?>\n",
+			want: []string{"echo", "1", ";"},
+		},
+		{
+			name: "inline html",
+			code: "hello <?php echo 1;",
+			want: []string{"hello ", "echo", "1", ";"},
+		},
+		{
+			name: "open tag with echo",
+			code: "<?=$a?>",
+			want: []string{"<?=", "$a"},
+		},
+		{
+			name: "strings without interpolation",
+			code: `<?php echo 'a\'b' . "c\"d";`,
+			want: []string{"echo", `'a\'b'`, ".", `"c\"d"`, ";"},
+		},
+		{
+			name: "simple interpolation",
+			code: `<?php echo "x$a[0] $b->c y";`,
+			want: []string{"echo", `"`, "x", "$a", "[", "0", "]", " ", "$b", "->", "c", " y", `"`, ";"},
+		},
+		{
+			name: "complex interpolation",
+			code: `<?php "{$a['k']}";`,
+			want: []string{`"`, "{", "$a", "[", "'k'", "]", "}", `"`, ";"},
+		},
+		{
+			name: "dollar brace interpolation",
+			code: `<?php "${a}";`,
+			want: []string{`"`, "${", "a", "}", `"`, ";"},
+		},
+		{
+			name: "heredoc",
+			code: "<?php echo <<<EOT\nHi $name\n  EOT;",
+			want: []string{"echo", "<<<EOT\n", "Hi ", "$name", "  EOT", ";"},
+		},
+		{
+			name: "nowdoc",
+			code: "<?php echo <<<'EOT'\nHi $name\nEOT;",
+			want: []string{"echo", "<<<'EOT'\n", "Hi $name", "EOT", ";"},
+		},
+		{
+			name: "numbers",
+			code: "<?php 0x1F+1_000+.5+1.5e-3+0b101;",
+			want: []string{"0x1F", "+", "1_000", "+", ".5", "+", "1.5e-3", "+", "0b101", ";"},
+		},
+		{
+			name: "operators and casts",
+			code: "<?php $a ??= (int) $b <=> $c?->d;",
+			want: []string{"$a", "??=", "(int)", "$b", "<=>", "$c", "?->", "d", ";"},
+		},
+		{
+			name: "namespaced names",
+			code: `<?php \Foo\bar(namespace\baz);`,
+			want: []string{`\Foo\bar`, "(", `namespace\baz`, ")", ";"},
+		},
+		{
+			name: "attribute",
+			code: "<?php #[Pure] fn() => 1;",
+			want: []string{"#[", "Pure", "]", "fn", "(", ")", "=>", "1", ";"},
+		},
+		{
+			name: "short open tag",
+			code: "<? echo 1 ?>",
+			want: []string{"echo", "1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenizePHP(tt.code)
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("tokenizePHP(%q) = %q, want %q", tt.code, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTokenizePHP_UnclosedInterpolationInHeredoc(t *testing.T) {
+	// Each heredoc ends before the interpolation in it is closed. Lexing the
+	// interpolation past the end of the heredoc used to take exponential time.
+	code := "<?php " + strings.Repeat("<<<A\n{$x\nA\n", 40)
+	done := make(chan []string)
+	go func() { done <- tokenizePHP(code) }()
+	select {
+	case tokens := <-done:
+		if len(tokens) == 0 {
+			t.Error("expected tokens")
+		}
+	case <-time.After(time.Second):
+		t.Fatal("tokenizePHP did not finish in time")
+	}
+}
+
+func FuzzTokenizePHP(f *testing.F) {
+	f.Add("<?php echo \"x$a[0] {$b['k']} ${c}\";")
+	f.Add("<?php echo <<<EOT\nHi {$name}\n  EOT;")
+	f.Add("<?php <<<A\n{$x\nA\n<<<A\n{$x\nA\n")
+	f.Add("<?php \"{$a[\"{$b}\"]}\"; `ls $d`;")
+	f.Fuzz(func(t *testing.T, code string) {
+		start := time.Now()
+		tokens := tokenizePHP(code)
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Fatalf("tokenizePHP took %v for %d bytes", elapsed, len(code))
+		}
+		// Tokens are taken from the code in order, so together they cannot
+		// be longer than it.
+		size := 0
+		for _, token := range tokens {
+			size += len(token)
+		}
+		if size > len(code) {
+			t.Errorf("tokens have %d bytes, more than the %d of the code", size, len(code))
+		}
+	})
+}
@@ -5,70 +5,318 @@ import (
 	"context"
 	"crypto/md5"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
+	"slices"
+
+	"github.com/hibiken/asynq"
+
+	"albatross-2026-backend/checker"
 )
 
-type processor struct{}
+type processor struct {
+	workers *WorkerRegistry
+	client  *http.Client
+}
 
-func newProcessor() processor {
-	return processor{}
+func newProcessor(workers *WorkerRegistry) processor {
+	return processor{
+		workers: workers,
+		client:  &http.Client{},
+	}
 }
 
+// testrunRequestData runs code with stdin. MaxDuration bounds each step on the
+// worker, such as compiling. TimeLimit and MemoryLimit bound running the code;
+// zero means MaxDuration and the default of the worker respectively.
 type testrunRequestData struct {
 	Code        string `json:"code"`
 	CodeHash    string `json:"code_hash"`
 	Stdin       string `json:"stdin"`
 	MaxDuration int    `json:"max_duration_ms"`
+	TimeLimit   int    `json:"time_limit_ms,omitempty"`
+	MemoryLimit int64  `json:"memory_limit_bytes,omitempty"`
 }
 
+// testrunResponseData is the result of a run. TimeMs and MemoryBytes are nil
+// if the worker did not measure them; the Swift worker never reports
+// MemoryBytes.
 type testrunResponseData struct {
-	Status string `json:"status"`
-	Stdout string `json:"stdout"`
-	Stderr string `json:"stderr"`
+	Status      string `json:"status"`
+	Stdout      string `json:"stdout"`
+	Stderr      string `json:"stderr"`
+	TimeMs      *int   `json:"time_ms"`
+	MemoryBytes *int64 `json:"memory_bytes"`
+}
+
+type testrunBatchRequestData struct {
+	Code        string                 `json:"code"`
+	CodeHash    string                 `json:"code_hash"`
+	Testcases   []testrunBatchTestcase `json:"testcases"`
+	MaxDuration int                    `json:"max_duration_ms"`
+}
+
+type testrunBatchTestcase struct {
+	Stdin       string `json:"stdin"`
+	TimeLimit   int    `json:"time_limit_ms,omitempty"`
+	MemoryLimit int64  `json:"memory_limit_bytes,omitempty"`
+}
+
+type testrunBatchResponseData struct {
+	Results []testrunResponseData `json:"results"`
+}
+
+func (p *processor) doProcessTaskRunSubmission(
+	ctx context.Context,
+	payload *TaskPayloadRunSubmission,
+) (*TaskResultRunSubmission, error) {
+	testcases := make([]testrunBatchTestcase, len(payload.Testcases))
+	for i, tc := range payload.Testcases {
+		testcases[i] = testrunBatchTestcase{
+			Stdin:       tc.Stdin,
+			TimeLimit:   tc.TimeLimitMs,
+			MemoryLimit: mibToBytes(tc.MemoryLimitMiB),
+		}
+	}
+	var resData testrunBatchResponseData
+	err := p.post(ctx, payload.Language, "/exec_batch", testrunBatchRequestData{
+		Code:        payload.Code,
+		CodeHash:    calcSubmissionCodeHash(payload.Code, payload.SubmissionID),
+		Testcases:   testcases,
+		MaxDuration: 30 * 1000,
+	}, &resData)
+	if err != nil {
+		return nil, err
+	}
+	if len(resData.Results) != len(payload.Testcases) {
+		return nil, fmt.Errorf("worker returned %d results for %d testcases", len(resData.Results), len(payload.Testcases))
+	}
+	result := &TaskResultRunSubmission{
+		TaskPayload: payload,
+		Results:     make([]TestcaseRunResult, len(resData.Results)),
+	}
+	for i, r := range resData.Results {
+		if err := checkStatus(r.Status); err != nil {
+			return nil, err
+		}
+		result.Results[i] = TestcaseRunResult{
+			TestcaseID:  payload.Testcases[i].TestcaseID,
+			Status:      r.Status,
+			Stdout:      r.Stdout,
+			Stderr:      r.Stderr,
+			TimeMs:      r.TimeMs,
+			MemoryBytes: r.MemoryBytes,
+		}
+	}
+	return result, nil
 }
 
-func (p *processor) doProcessTaskRunTestcase(
-	_ context.Context,
-	payload *TaskPayloadRunTestcase,
-) (*TaskResultRunTestcase, error) {
-	reqData := testrunRequestData{
+func (p *processor) doProcessTaskRunChecker(
+	ctx context.Context,
+	payload *TaskPayloadRunChecker,
+) (*TaskResultRunChecker, error) {
+	resData, err := p.exec(ctx, payload.Language, testrunRequestData{
+		Code:        payload.CheckerCode,
+		CodeHash:    calcCodeHash(payload.CheckerCode, payload.TestcaseID),
+		Stdin:       payload.Stdin,
+		MaxDuration: 30 * 1000,
+		TimeLimit:   payload.TimeLimitMs,
+		MemoryLimit: mibToBytes(payload.MemoryLimitMiB),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &TaskResultRunChecker{
+		TaskPayload: payload,
+		Status:      resData.Status,
+		Stdout:      resData.Stdout,
+		Stderr:      resData.Stderr,
+	}, nil
+}
+
+func (p *processor) doProcessTaskRunCustom(
+	ctx context.Context,
+	payload *TaskPayloadRunCustom,
+) (*TaskResultRunCustom, error) {
+	resData, err := p.exec(ctx, payload.Language, testrunRequestData{
 		Code:        payload.Code,
-		CodeHash:    calcCodeHash(payload.Code, payload.TestcaseID),
+		CodeHash:    calcCustomRunCodeHash(payload.Code, payload.RunID),
 		Stdin:       payload.Stdin,
 		MaxDuration: 30 * 1000,
+		TimeLimit:   payload.TimeLimitMs,
+		MemoryLimit: mibToBytes(payload.MemoryLimitMiB),
+	})
+	if err != nil {
+		return nil, err
 	}
+	return &TaskResultRunCustom{
+		TaskPayload: payload,
+		Status:      resData.Status,
+		Stdout:      resData.Stdout,
+		Stderr:      resData.Stderr,
+	}, nil
+}
+
+func (p *processor) doProcessTaskRunValidation(
+	ctx context.Context,
+	payload *TaskPayloadRunValidation,
+) (*TaskResultRunValidation, error) {
+	resData, err := p.exec(ctx, payload.Language, testrunRequestData{
+		Code:        payload.Code,
+		CodeHash:    calcValidationCodeHash(payload.Code, payload.ValidationID, payload.TestcaseID),
+		Stdin:       payload.Stdin,
+		MaxDuration: 30 * 1000,
+		TimeLimit:   payload.TimeLimitMs,
+		MemoryLimit: mibToBytes(payload.MemoryLimitMiB),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result := &TaskResultRunValidation{
+		TaskPayload: payload,
+		Status:      resData.Status,
+		Stdout:      resData.Stdout,
+		Stderr:      resData.Stderr,
+	}
+	if payload.CheckerCode == "" || resData.Status != "success" {
+		return result, nil
+	}
+
+	stdin, err := checker.SpecialJudgeStdin(payload.Stdin, payload.Stdout, resData.Stdout)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", err, asynq.SkipRetry)
+	}
+	checkerData, err := p.exec(ctx, payload.Language, testrunRequestData{
+		Code:        payload.CheckerCode,
+		CodeHash:    calcCodeHash(payload.CheckerCode, payload.TestcaseID),
+		Stdin:       stdin,
+		MaxDuration: 30 * 1000,
+		TimeLimit:   payload.TimeLimitMs,
+		MemoryLimit: mibToBytes(payload.MemoryLimitMiB),
+	})
+	if err != nil {
+		return nil, err
+	}
+	result.CheckerStatus = checkerData.Status
+	result.CheckerStdout = checkerData.Stdout
+	return result, nil
+}
+
+// exec runs code on a worker for the language.
+func (p *processor) exec(
+	ctx context.Context,
+	language string,
+	reqData testrunRequestData,
+) (*testrunResponseData, error) {
+	resData := testrunResponseData{}
+	if err := p.post(ctx, language, "/exec", reqData, &resData); err != nil {
+		return nil, err
+	}
+	if err := checkStatus(resData.Status); err != nil {
+		return nil, err
+	}
+	return &resData, nil
+}
+
+// workerStatuses are the statuses workers report for a run.
+var workerStatuses = []string{"success", "compile_error", "runtime_error", "timeout", "internal_error"}
+
+// checkStatus returns an error if a worker reported an empty or unknown
+// status, which would otherwise be recorded as the verdict.
+func checkStatus(status string) error {
+	if !slices.Contains(workerStatuses, status) {
+		return fmt.Errorf("worker returned unknown status %q: %w", status, asynq.SkipRetry)
+	}
+	return nil
+}
+
+// post sends reqData to the path of a worker for the language and decodes the
+// response into resData.
+func (p *processor) post(
+	ctx context.Context,
+	language string,
+	path string,
+	reqData any,
+	resData any,
+) error {
+	baseURL, release, err := p.workers.acquire(language)
+	if err != nil {
+		if errors.Is(err, ErrNoWorker) {
+			// Retrying does not make a worker appear.
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+		return err
+	}
+	// A worker that cannot be reached gets no more requests until it passes
+	// a health check.
+	var unreachable error
+	defer func() { release(unreachable) }()
+
 	reqJSON, err := json.Marshal(reqData)
 	if err != nil {
-		return nil, fmt.Errorf("json.Marshal failed: %v", err)
+		return fmt.Errorf("json.Marshal failed: %v", err)
 	}
-	req, err := http.NewRequest("POST", "http://worker-"+payload.Language+":80/exec", bytes.NewBuffer(reqJSON))
+	req, err := http.NewRequestWithContext(ctx, "POST", baseURL+path, bytes.NewBuffer(reqJSON))
 	if err != nil {
-		return nil, fmt.Errorf("http.NewRequest failed: %v", err)
+		return fmt.Errorf("http.NewRequest failed: %v", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	res, err := p.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("client.Do failed: %v", err)
+		if ctx.Err() == nil {
+			unreachable = err
+		}
+		return fmt.Errorf("client.Do failed: %v", err)
 	}
 	defer res.Body.Close()
 
-	resData := testrunResponseData{}
-	if err := json.NewDecoder(res.Body).Decode(&resData); err != nil {
-		return nil, fmt.Errorf("json.Decode failed: %v", err)
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		err := fmt.Errorf("worker responded with %s: %s", res.Status, bytes.TrimSpace(body))
+		if res.StatusCode >= 400 && res.StatusCode < 500 {
+			// The worker rejected the request itself, so sending it again
+			// does not help.
+			return fmt.Errorf("%w: %w", err, asynq.SkipRetry)
+		}
+		return err
 	}
-	return &TaskResultRunTestcase{
-		TaskPayload: payload,
-		Status:      resData.Status,
-		Stdout:      resData.Stdout,
-		Stderr:      resData.Stderr,
-	}, nil
+	if err := json.NewDecoder(res.Body).Decode(resData); err != nil {
+		return fmt.Errorf("json.Decode failed: %v", err)
+	}
+	return nil
+}
+
+func mibToBytes(mib int) int64 {
+	return int64(mib) * 1024 * 1024
 }
 
 func calcCodeHash(code string, testcaseID int) string {
 	buf := make([]byte, 0, len(code)+10)
 	return fmt.Sprintf("%x", md5.Sum(fmt.Appendf(buf, "%s@%d", code, testcaseID)))
 }
+
+// calcSubmissionCodeHash is calcCodeHash for runs of a submission on all the
+// testcases at once.
+func calcSubmissionCodeHash(code string, submissionID int) string {
+	buf := make([]byte, 0, len(code)+30)
+	return fmt.Sprintf("%x", md5.Sum(fmt.Appendf(buf, "%s@submission:%d", code, submissionID)))
+}
+
+// calcCustomRunCodeHash is calcCodeHash for custom runs. Workers use the hash
+// as the name of the working directory, so it must differ from the ones of the
+// testcase runs of the same code.
+func calcCustomRunCodeHash(code string, runID string) string {
+	buf := make([]byte, 0, len(code)+len(runID)+5)
+	return fmt.Sprintf("%x", md5.Sum(fmt.Appendf(buf, "%s@run:%s", code, runID)))
+}
+
+// calcValidationCodeHash is calcCodeHash for validations, so that a reference
+// solution identical to a submission does not share its working directory.
+func calcValidationCodeHash(code string, validationID, testcaseID int) string {
+	buf := make([]byte, 0, len(code)+30)
+	return fmt.Sprintf("%x", md5.Sum(fmt.Appendf(buf, "%s@validation:%d:%d", code, validationID, testcaseID)))
+}
@@ -1,16 +1,21 @@
 package taskqueue
 
 import (
-	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/hibiken/asynq"
 )
 
-func TestDoProcessTaskRunTestcase_Success(t *testing.T) {
+func TestDoProcessTaskRunCustom_Success(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/exec" {
+			t.Errorf("expected path /exec, got %s", r.URL.Path)
+		}
 		if r.Method != http.MethodPost {
 			t.Errorf("expected POST, got %s", r.Method)
 		}
@@ -34,6 +39,9 @@ func TestDoProcessTaskRunTestcase_Success(t *testing.T) {
 		if reqData.MaxDuration != 30000 {
 			t.Errorf("expected max_duration 30000, got %d", reqData.MaxDuration)
 		}
+		if reqData.TimeLimit != 2000 || reqData.MemoryLimit != 64<<20 {
+			t.Errorf("expected the limits of the problem, got %d ms and %d bytes", reqData.TimeLimit, reqData.MemoryLimit)
+		}
 		if reqData.CodeHash == "" {
 			t.Error("expected non-empty code hash")
 		}
@@ -47,21 +55,19 @@ func TestDoProcessTaskRunTestcase_Success(t *testing.T) {
 	}))
 	defer server.Close()
 
-	p := newProcessor()
-	payload := &TaskPayloadRunTestcase{
-		GameID:       1,
-		UserID:       2,
-		SubmissionID: 3,
-		TestcaseID:   4,
-		Language:     "php",
-		Code:         "echo hello",
-		Stdin:        "input",
-		Stdout:       "hello\n",
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunCustom{
+		RunID:          "run",
+		GameID:         1,
+		UserID:         2,
+		Language:       "php",
+		Code:           "echo hello",
+		Stdin:          "input",
+		TimeLimitMs:    2000,
+		MemoryLimitMiB: 64,
 	}
 
-	// Override the URL by temporarily changing the request building
-	// We need to test with a real HTTP server, so we use a wrapper approach
-	result, err := doProcessWithURL(context.Background(), &p, payload, server.URL+"/exec")
+	result, err := p.doProcessTaskRunCustom(context.Background(), payload)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -79,7 +85,7 @@ func TestDoProcessTaskRunTestcase_Success(t *testing.T) {
 	}
 }
 
-func TestDoProcessTaskRunTestcase_ErrorResponse(t *testing.T) {
+func TestDoProcessTaskRunCustom_ErrorResponse(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_ = json.NewEncoder(w).Encode(testrunResponseData{
@@ -90,19 +96,14 @@ func TestDoProcessTaskRunTestcase_ErrorResponse(t *testing.T) {
 	}))
 	defer server.Close()
 
-	p := newProcessor()
-	payload := &TaskPayloadRunTestcase{
-		GameID:       1,
-		UserID:       2,
-		SubmissionID: 3,
-		TestcaseID:   4,
-		Language:     "php",
-		Code:         "while(true){}",
-		Stdin:        "",
-		Stdout:       "",
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunCustom{
+		RunID:    "run",
+		Language: "php",
+		Code:     "while(true){}",
 	}
 
-	result, err := doProcessWithURL(context.Background(), &p, payload, server.URL+"/exec")
+	result, err := p.doProcessTaskRunCustom(context.Background(), payload)
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -114,91 +115,218 @@ func TestDoProcessTaskRunTestcase_ErrorResponse(t *testing.T) {
 	}
 }
 
-func TestDoProcessTaskRunTestcase_ServerDown(t *testing.T) {
-	p := newProcessor()
-	payload := &TaskPayloadRunTestcase{
-		GameID:       1,
-		UserID:       2,
-		SubmissionID: 3,
-		TestcaseID:   4,
-		Language:     "php",
-		Code:         "echo 1",
-		Stdin:        "",
-		Stdout:       "",
+func TestDoProcessTaskRunCustom_ServerDown(t *testing.T) {
+	p := newTestProcessor(t, "http://localhost:1")
+	payload := &TaskPayloadRunCustom{
+		RunID:    "run",
+		Language: "php",
+		Code:     "echo 1",
 	}
 
-	_, err := doProcessWithURL(context.Background(), &p, payload, "http://localhost:1/exec")
+	_, err := p.doProcessTaskRunCustom(context.Background(), payload)
 	if err == nil {
 		t.Error("expected error when server is down")
 	}
+	// The worker is not tried again until it passes a health check, but the
+	// task may be retried.
+	_, err = p.doProcessTaskRunCustom(context.Background(), payload)
+	if !errors.Is(err, ErrNoHealthyWorker) || errors.Is(err, asynq.SkipRetry) {
+		t.Errorf("err = %v, want retryable ErrNoHealthyWorker", err)
+	}
 }
 
-func TestDoProcessTaskRunTestcase_InvalidJSON(t *testing.T) {
+func TestDoProcessTaskRunCustom_InvalidJSON(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 		_, _ = w.Write([]byte("not json"))
 	}))
 	defer server.Close()
 
-	p := newProcessor()
-	payload := &TaskPayloadRunTestcase{
-		GameID:       1,
-		UserID:       2,
-		SubmissionID: 3,
-		TestcaseID:   4,
-		Language:     "php",
-		Code:         "echo 1",
-		Stdin:        "",
-		Stdout:       "",
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunCustom{
+		RunID:    "run",
+		Language: "php",
+		Code:     "echo 1",
 	}
 
-	_, err := doProcessWithURL(context.Background(), &p, payload, server.URL+"/exec")
+	_, err := p.doProcessTaskRunCustom(context.Background(), payload)
 	if err == nil {
 		t.Error("expected error for invalid JSON response")
 	}
 }
 
-// doProcessWithURL is a test helper that sends the request to a custom URL
-// instead of the default worker URL.
-func doProcessWithURL(
-	_ context.Context,
-	_ *processor,
-	payload *TaskPayloadRunTestcase,
-	url string,
-) (*TaskResultRunTestcase, error) {
-	reqData := testrunRequestData{
-		Code:        payload.Code,
-		CodeHash:    calcCodeHash(payload.Code, payload.TestcaseID),
-		Stdin:       payload.Stdin,
-		MaxDuration: 30 * 1000,
-	}
-	reqJSON, err := json.Marshal(reqData)
-	if err != nil {
-		return nil, err
+func TestDoProcessTaskRunCustom_ErrorStatusCode(t *testing.T) {
+	tests := []struct {
+		name      string
+		code      int
+		skipRetry bool
+	}{
+		{name: "bad request", code: http.StatusBadRequest, skipRetry: true},
+		{name: "server error", code: http.StatusInternalServerError, skipRetry: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(tt.code)
+				_, _ = w.Write([]byte(`{"message":"Invalid request"}`))
+			}))
+			defer server.Close()
+
+			p := newTestProcessor(t, server.URL)
+			payload := &TaskPayloadRunCustom{RunID: "run", Language: "php", Code: "echo 1"}
+
+			_, err := p.doProcessTaskRunCustom(context.Background(), payload)
+			if err == nil {
+				t.Fatal("expected error for the status code")
+			}
+			if got := errors.Is(err, asynq.SkipRetry); got != tt.skipRetry {
+				t.Errorf("err = %v, want SkipRetry %v", err, tt.skipRetry)
+			}
+		})
 	}
+}
+
+func TestDoProcessTaskRunCustom_UnknownStatus(t *testing.T) {
+	for _, status := range []string{"", "pass"} {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(testrunResponseData{Status: status})
+		}))
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(reqJSON))
+		p := newTestProcessor(t, server.URL)
+		payload := &TaskPayloadRunCustom{RunID: "run", Language: "php", Code: "echo 1"}
+
+		if _, err := p.doProcessTaskRunCustom(context.Background(), payload); err == nil {
+			t.Errorf("expected error for status %q", status)
+		}
+		server.Close()
+	}
+}
+
+// newTestProcessor creates a processor whose php worker is at url.
+func newTestProcessor(t *testing.T, url string) processor {
+	t.Helper()
+	workers, err := NewWorkerRegistry(map[string][]string{"php": {url}}, "")
 	if err != nil {
-		return nil, err
+		t.Fatalf("NewWorkerRegistry: %v", err)
+	}
+	return newProcessor(workers)
+}
+
+func TestDoProcessTaskRunCustom_NoWorker(t *testing.T) {
+	p := newTestProcessor(t, "http://localhost:1")
+	payload := &TaskPayloadRunCustom{
+		RunID:    "run",
+		Language: "swift",
+		Code:     "print(1)",
+	}
+
+	_, err := p.doProcessTaskRunCustom(context.Background(), payload)
+	if !errors.Is(err, ErrNoWorker) || !errors.Is(err, asynq.SkipRetry) {
+		t.Errorf("err = %v, want ErrNoWorker without retries", err)
+	}
+}
+
+func TestDoProcessTaskRunSubmission_Success(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/exec_batch" {
+			t.Errorf("expected path /exec_batch, got %s", r.URL.Path)
+		}
+		var reqData testrunBatchRequestData
+		if err := json.NewDecoder(r.Body).Decode(&reqData); err != nil {
+			t.Fatalf("failed to decode request: %v", err)
+		}
+		if reqData.Code != "echo hello" {
+			t.Errorf("expected code 'echo hello', got %q", reqData.Code)
+		}
+		if len(reqData.Testcases) != 2 || reqData.Testcases[0].Stdin != "in1" || reqData.Testcases[1].Stdin != "in2" {
+			t.Errorf("unexpected testcases: %+v", reqData.Testcases)
+			return
+		}
+		if tc := reqData.Testcases[0]; tc.TimeLimit != 2000 || tc.MemoryLimit != 64*1024*1024 {
+			t.Errorf("unexpected limits: %+v", tc)
+		}
+		if tc := reqData.Testcases[1]; tc.TimeLimit != 0 || tc.MemoryLimit != 0 {
+			t.Errorf("expected no limits, got %+v", tc)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"results":[` +
+			`{"status":"success","stdout":"out1","stderr":"","time_ms":12,"memory_bytes":2048},` +
+			`{"status":"runtime_error","stdout":"","stderr":"error"}]}`))
+	}))
+	defer server.Close()
+
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunSubmission{
+		SubmissionID: 3,
+		Language:     "php",
+		Code:         "echo hello",
+		Testcases: []SubmissionTestcase{
+			{TestcaseID: 4, Stdin: "in1", TimeLimitMs: 2000, MemoryLimitMiB: 64},
+			{TestcaseID: 5, Stdin: "in2"},
+		},
 	}
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	res, err := client.Do(req)
+	result, err := p.doProcessTaskRunSubmission(context.Background(), payload)
 	if err != nil {
-		return nil, err
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.Results) != 2 {
+		t.Fatalf("got %d results, want 2", len(result.Results))
+	}
+	first, second := result.Results[0], result.Results[1]
+	if first.TestcaseID != 4 || first.Status != "success" || first.Stdout != "out1" {
+		t.Errorf("first result = %+v", first)
+	}
+	if first.TimeMs == nil || *first.TimeMs != 12 || first.MemoryBytes == nil || *first.MemoryBytes != 2048 {
+		t.Errorf("expected the resource usage of the first result, got %v, %v", first.TimeMs, first.MemoryBytes)
+	}
+	if second.TestcaseID != 5 || second.Status != "runtime_error" || second.Stderr != "error" {
+		t.Errorf("second result = %+v", second)
+	}
+	if second.TimeMs != nil || second.MemoryBytes != nil {
+		t.Errorf("expected no resource usage for the second result, got %v, %v", second.TimeMs, second.MemoryBytes)
+	}
+}
+
+func TestDoProcessTaskRunSubmission_MissingResults(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(testrunBatchResponseData{
+			Results: []testrunResponseData{{Status: "success"}},
+		})
+	}))
+	defer server.Close()
+
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunSubmission{
+		Language:  "php",
+		Testcases: []SubmissionTestcase{{TestcaseID: 4}, {TestcaseID: 5}},
+	}
+
+	if _, err := p.doProcessTaskRunSubmission(context.Background(), payload); err == nil {
+		t.Error("expected error for missing results")
+	}
+}
+
+func TestDoProcessTaskRunSubmission_UnknownStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(testrunBatchResponseData{
+			Results: []testrunResponseData{{Status: "success"}, {}},
+		})
+	}))
+	defer server.Close()
+
+	p := newTestProcessor(t, server.URL)
+	payload := &TaskPayloadRunSubmission{
+		Language:  "php",
+		Testcases: []SubmissionTestcase{{TestcaseID: 4}, {TestcaseID: 5}},
 	}
-	defer res.Body.Close()
 
-	resData := testrunResponseData{}
-	if err := json.NewDecoder(res.Body).Decode(&resData); err != nil {
-		return nil, err
+	if _, err := p.doProcessTaskRunSubmission(context.Background(), payload); err == nil {
+		t.Error("expected error for a result without a status")
 	}
-	return &TaskResultRunTestcase{
-		TaskPayload: payload,
-		Status:      resData.Status,
-		Stdout:      resData.Stdout,
-		Stderr:      resData.Stderr,
-	}, nil
 }
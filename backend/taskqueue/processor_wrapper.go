@@ -23,21 +23,87 @@ func newProcessorWrapper(impl processor) *processorWrapper {
 	}
 }
 
-func (p *processorWrapper) processTaskRunTestcase(ctx context.Context, t *asynq.Task) error {
-	var payload TaskPayloadRunTestcase
+func (p *processorWrapper) processTaskRunChecker(ctx context.Context, t *asynq.Task) error {
+	var payload TaskPayloadRunChecker
 	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
 		err := fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
-		p.results <- &TaskResultRunTestcase{Err: err}
+		p.results <- &TaskResultRunChecker{Err: err}
 		return err
 	}
 
-	result, err := p.impl.doProcessTaskRunTestcase(ctx, &payload)
+	result, err := p.impl.doProcessTaskRunChecker(ctx, &payload)
 	if err != nil {
 		retryCount, _ := asynq.GetRetryCount(ctx)
 		maxRetry, _ := asynq.GetMaxRetry(ctx)
 		isRecoverable := !errors.Is(err, asynq.SkipRetry) && retryCount < maxRetry
 		if !isRecoverable {
-			p.results <- &TaskResultRunTestcase{Err: err}
+			p.results <- &TaskResultRunChecker{TaskPayload: &payload, Err: err}
+		}
+		return err
+	}
+	p.results <- result
+	return nil
+}
+
+func (p *processorWrapper) processTaskRunCustom(ctx context.Context, t *asynq.Task) error {
+	var payload TaskPayloadRunCustom
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		err := fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+		p.results <- &TaskResultRunCustom{Err: err}
+		return err
+	}
+
+	result, err := p.impl.doProcessTaskRunCustom(ctx, &payload)
+	if err != nil {
+		retryCount, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		isRecoverable := !errors.Is(err, asynq.SkipRetry) && retryCount < maxRetry
+		if !isRecoverable {
+			p.results <- &TaskResultRunCustom{TaskPayload: &payload, Err: err}
+		}
+		return err
+	}
+	p.results <- result
+	return nil
+}
+
+func (p *processorWrapper) processTaskRunSubmission(ctx context.Context, t *asynq.Task) error {
+	var payload TaskPayloadRunSubmission
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		err := fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+		p.results <- &TaskResultRunSubmission{Err: err}
+		return err
+	}
+
+	result, err := p.impl.doProcessTaskRunSubmission(ctx, &payload)
+	if err != nil {
+		retryCount, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		isRecoverable := !errors.Is(err, asynq.SkipRetry) && retryCount < maxRetry
+		if !isRecoverable {
+			p.results <- &TaskResultRunSubmission{TaskPayload: &payload, Err: err}
+		}
+		return err
+	}
+	p.results <- result
+	return nil
+}
+
+func (p *processorWrapper) processTaskRunValidation(ctx context.Context, t *asynq.Task) error {
+	var payload TaskPayloadRunValidation
+	if err := json.Unmarshal(t.Payload(), &payload); err != nil {
+		err := fmt.Errorf("json.Unmarshal failed: %v: %w", err, asynq.SkipRetry)
+		p.results <- &TaskResultRunValidation{Err: err}
+		return err
+	}
+
+	result, err := p.impl.doProcessTaskRunValidation(ctx, &payload)
+	if err != nil {
+		retryCount, _ := asynq.GetRetryCount(ctx)
+		maxRetry, _ := asynq.GetMaxRetry(ctx)
+		isRecoverable := !errors.Is(err, asynq.SkipRetry) && retryCount < maxRetry
+		if !isRecoverable {
+			p.results <- &TaskResultRunValidation{TaskPayload: &payload, Err: err}
 		}
 		return err
 	}
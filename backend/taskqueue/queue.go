@@ -20,25 +20,118 @@ func (q *Queue) Close() {
 	q.client.Close()
 }
 
-func (q *Queue) EnqueueTaskRunTestcase(
+func (q *Queue) EnqueueTaskRunSubmission(
 	gameID int,
 	userID int,
 	submissionID int,
-	testcaseID int,
 	language string,
 	code string,
+	testcases []SubmissionTestcase,
+) error {
+	task, err := newTaskRunSubmission(
+		gameID,
+		userID,
+		submissionID,
+		language,
+		code,
+		testcases,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.Enqueue(task)
+	return err
+}
+
+func (q *Queue) EnqueueTaskRunChecker(
+	gameID int,
+	userID int,
+	submissionID int,
+	testcaseID int,
+	language string,
+	checkerCode string,
 	stdin string,
-	stdout string,
+	submissionStdout string,
+	submissionStderr string,
+	submissionTimeMs *int,
+	submissionMemoryBytes *int64,
+	timeLimitMs int,
+	memoryLimitMiB int,
 ) error {
-	task, err := newTaskRunTestcase(
+	task, err := newTaskRunChecker(
 		gameID,
 		userID,
 		submissionID,
 		testcaseID,
 		language,
+		checkerCode,
+		stdin,
+		submissionStdout,
+		submissionStderr,
+		submissionTimeMs,
+		submissionMemoryBytes,
+		timeLimitMs,
+		memoryLimitMiB,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.Enqueue(task)
+	return err
+}
+
+func (q *Queue) EnqueueTaskRunCustom(
+	runID string,
+	gameID int,
+	userID int,
+	language string,
+	code string,
+	stdin string,
+	timeLimitMs int,
+	memoryLimitMiB int,
+) error {
+	task, err := newTaskRunCustom(
+		runID,
+		gameID,
+		userID,
+		language,
+		code,
+		stdin,
+		timeLimitMs,
+		memoryLimitMiB,
+	)
+	if err != nil {
+		return err
+	}
+	_, err = q.client.Enqueue(task)
+	return err
+}
+
+func (q *Queue) EnqueueTaskRunValidation(
+	validationID int,
+	problemID int,
+	referenceSolutionID int,
+	testcaseID int,
+	language string,
+	code string,
+	stdin string,
+	stdout string,
+	checkerCode string,
+	timeLimitMs int,
+	memoryLimitMiB int,
+) error {
+	task, err := newTaskRunValidation(
+		validationID,
+		problemID,
+		referenceSolutionID,
+		testcaseID,
+		language,
 		code,
 		stdin,
 		stdout,
+		checkerCode,
+		timeLimitMs,
+		memoryLimitMiB,
 	)
 	if err != nil {
 		return err
@@ -0,0 +1,296 @@
+package taskqueue
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ways to choose a worker among those for a language.
+const (
+	SelectionRoundRobin = "round_robin"
+	SelectionLeastBusy  = "least_busy"
+)
+
+// healthCheckTimeout bounds each request to the info endpoint of a worker.
+const healthCheckTimeout = 5 * time.Second
+
+var (
+	ErrNoWorker = errors.New("no worker for the language")
+	// ErrNoHealthyWorker is returned while all the workers for the language
+	// are unhealthy. Unlike ErrNoWorker, the task can succeed once one of
+	// them recovers.
+	ErrNoHealthyWorker = errors.New("no healthy worker for the language")
+)
+
+// WorkerInfo is what a worker reports about itself at /info.
+type WorkerInfo struct {
+	Language       string `json:"language"`
+	Version        string `json:"version"`
+	MaxMemoryBytes int64  `json:"max_memory_bytes,omitempty"`
+	MaxOutputBytes int64  `json:"max_output_bytes,omitempty"`
+	// Running is the number of programs the worker is running.
+	Running int `json:"running"`
+}
+
+// WorkerStatus is the state of a worker as seen by the backend.
+type WorkerStatus struct {
+	Language string
+	URL      string
+	Healthy  bool
+	// Running is the number of requests sent to the worker and not yet
+	// answered.
+	Running int
+	// Info is nil until the worker answers a health check.
+	Info      *WorkerInfo
+	CheckedAt time.Time
+	// Error tells why the worker is unhealthy.
+	Error string
+}
+
+// WorkerRegistry holds the workers that run code, by language. Workers that
+// fail a request or a health check get no requests until they pass a health
+// check again.
+type WorkerRegistry struct {
+	selection string
+	pools     map[string]*workerPool
+	client    *http.Client
+}
+
+type workerPool struct {
+	mu        sync.Mutex
+	endpoints []*workerEndpoint
+	// next is the endpoint to try first in the next selection.
+	next int
+}
+
+type workerEndpoint struct {
+	url string
+	// running is the number of requests sent to the endpoint and not yet
+	// answered.
+	running   int
+	healthy   bool
+	info      *WorkerInfo
+	checkedAt time.Time
+	err       string
+}
+
+// NewWorkerRegistry creates a registry of the workers, given as the base URLs
+// by language. An empty selection means round-robin. Workers are assumed to be
+// healthy until checked.
+func NewWorkerRegistry(workers map[string][]string, selection string) (*WorkerRegistry, error) {
+	if selection == "" {
+		selection = SelectionRoundRobin
+	}
+	if selection != SelectionRoundRobin && selection != SelectionLeastBusy {
+		return nil, fmt.Errorf("unknown worker selection %q", selection)
+	}
+	r := &WorkerRegistry{
+		selection: selection,
+		pools:     make(map[string]*workerPool, len(workers)),
+		client:    &http.Client{Timeout: healthCheckTimeout},
+	}
+	for language, urls := range workers {
+		if len(urls) == 0 {
+			return nil, fmt.Errorf("no workers for %s", language)
+		}
+		pool := &workerPool{}
+		for _, url := range urls {
+			pool.endpoints = append(pool.endpoints, &workerEndpoint{
+				url:     strings.TrimSuffix(url, "/"),
+				healthy: true,
+			})
+		}
+		r.pools[language] = pool
+	}
+	return r, nil
+}
+
+// acquire chooses a healthy worker for the language and returns its base URL.
+// release must be called once the worker has answered, with the error if it
+// could not be reached.
+func (r *WorkerRegistry) acquire(language string) (url string, release func(error), err error) {
+	pool, ok := r.pools[language]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: %s", ErrNoWorker, language)
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+
+	// Ties in least-busy selection go to the endpoint after the last chosen
+	// one, so that idle workers share the load too.
+	var e *workerEndpoint
+	n := len(pool.endpoints)
+	chosen := 0
+	for i := range n {
+		j := (pool.next + i) % n
+		c := pool.endpoints[j]
+		if !c.healthy {
+			continue
+		}
+		if e == nil || c.running < e.running {
+			e, chosen = c, j
+		}
+		if r.selection == SelectionRoundRobin {
+			break
+		}
+	}
+	if e == nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrNoHealthyWorker, language)
+	}
+	pool.next = (chosen + 1) % n
+	e.running++
+
+	var once sync.Once
+	release = func(err error) {
+		once.Do(func() {
+			pool.mu.Lock()
+			defer pool.mu.Unlock()
+			e.running--
+			if err != nil {
+				e.markUnhealthy(err.Error())
+			}
+		})
+	}
+	return e.url, release, nil
+}
+
+// markUnhealthy must be called with the lock of the pool held.
+func (e *workerEndpoint) markUnhealthy(reason string) {
+	if e.healthy {
+		slog.Warn("worker is unhealthy", "url", e.url, "error", reason)
+	}
+	e.healthy = false
+	e.err = reason
+}
+
+// RunHealthChecks checks the workers every interval until ctx is done.
+func (r *WorkerRegistry) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		r.CheckHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckHealth asks all the workers for their info, and marks those that do
+// not answer or that run another language as unhealthy.
+func (r *WorkerRegistry) CheckHealth(ctx context.Context) {
+	var wg sync.WaitGroup
+	for language, pool := range r.pools {
+		for _, e := range pool.endpoints {
+			wg.Go(func() {
+				info, err := r.fetchInfo(ctx, e.url)
+				if err == nil && info.Language != language {
+					err = fmt.Errorf("runs %s instead of %s", info.Language, language)
+				}
+
+				pool.mu.Lock()
+				defer pool.mu.Unlock()
+				e.checkedAt = time.Now()
+				if err != nil {
+					e.markUnhealthy(err.Error())
+					return
+				}
+				if !e.healthy {
+					slog.Info("worker is healthy", "url", e.url)
+				}
+				e.healthy = true
+				e.info = info
+				e.err = ""
+			})
+		}
+	}
+	wg.Wait()
+}
+
+func (r *WorkerRegistry) fetchInfo(ctx context.Context, baseURL string) (*WorkerInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", baseURL+"/info", nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("info returned %s", res.Status)
+	}
+	var info WorkerInfo
+	if err := json.NewDecoder(res.Body).Decode(&info); err != nil {
+		return nil, fmt.Errorf("json.Decode failed: %v", err)
+	}
+	return &info, nil
+}
+
+// MaxMemoryBytes returns the largest memory limit that every worker for the
+// language accepts, as they report at /info. It returns 0 if none of them has
+// reported one.
+func (r *WorkerRegistry) MaxMemoryBytes(language string) int64 {
+	if r == nil {
+		return 0
+	}
+	pool, ok := r.pools[language]
+	if !ok {
+		return 0
+	}
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	var maxBytes int64
+	for _, e := range pool.endpoints {
+		if e.info == nil || e.info.MaxMemoryBytes <= 0 {
+			continue
+		}
+		if maxBytes == 0 || e.info.MaxMemoryBytes < maxBytes {
+			maxBytes = e.info.MaxMemoryBytes
+		}
+	}
+	return maxBytes
+}
+
+// Status returns the state of all the workers, sorted by language.
+func (r *WorkerRegistry) Status() []WorkerStatus {
+	if r == nil {
+		return nil
+	}
+	var statuses []WorkerStatus
+	for language, pool := range r.pools {
+		pool.mu.Lock()
+		for _, e := range pool.endpoints {
+			s := WorkerStatus{
+				Language:  language,
+				URL:       e.url,
+				Healthy:   e.healthy,
+				Running:   e.running,
+				CheckedAt: e.checkedAt,
+				Error:     e.err,
+			}
+			if e.info != nil {
+				info := *e.info
+				s.Info = &info
+			}
+			statuses = append(statuses, s)
+		}
+		pool.mu.Unlock()
+	}
+	// Workers for the same language keep the order in the config.
+	slices.SortStableFunc(statuses, func(a, b WorkerStatus) int {
+		return cmp.Compare(a.Language, b.Language)
+	})
+	return statuses
+}
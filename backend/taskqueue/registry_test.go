@@ -0,0 +1,197 @@
+package taskqueue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestNewWorkerRegistry_Invalid(t *testing.T) {
+	if _, err := NewWorkerRegistry(map[string][]string{"php": {"http://a"}}, "random"); err == nil {
+		t.Error("expected error for unknown selection")
+	}
+	if _, err := NewWorkerRegistry(map[string][]string{"php": {}}, ""); err == nil {
+		t.Error("expected error for a language without workers")
+	}
+}
+
+func TestWorkerRegistry_RoundRobin(t *testing.T) {
+	r, err := NewWorkerRegistry(map[string][]string{"php": {"http://a", "http://b/"}}, SelectionRoundRobin)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"http://a", "http://b", "http://a"}
+	for i, w := range want {
+		url, release, err := r.acquire("php")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Busy workers are still chosen in turn.
+		defer release(nil)
+		if url != w {
+			t.Errorf("acquire #%d = %q, want %q", i, url, w)
+		}
+	}
+}
+
+func TestWorkerRegistry_LeastBusy(t *testing.T) {
+	r, err := NewWorkerRegistry(map[string][]string{"php": {"http://a", "http://b", "http://c"}}, SelectionLeastBusy)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	acquire := func() (string, func(error)) {
+		url, release, err := r.acquire("php")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return url, release
+	}
+
+	a, releaseA := acquire()
+	b, _ := acquire()
+	c, releaseC := acquire()
+	if a != "http://a" || b != "http://b" || c != "http://c" {
+		t.Errorf("idle workers = %q, %q, %q, want each once", a, b, c)
+	}
+	releaseA(nil)
+	releaseC(nil)
+	// Releasing twice must not make the worker look idler than it is.
+	releaseC(nil)
+	if url, _ := acquire(); url != "http://a" && url != "http://c" {
+		t.Errorf("acquire = %q, want an idle worker", url)
+	}
+	if url, _ := acquire(); url != "http://a" && url != "http://c" {
+		t.Errorf("acquire = %q, want an idle worker", url)
+	}
+	// All the workers are busy with one request now.
+	if url, _ := acquire(); url == "" {
+		t.Error("expected a worker even if all are busy")
+	}
+}
+
+func TestWorkerRegistry_UnknownLanguage(t *testing.T) {
+	r, err := NewWorkerRegistry(map[string][]string{"php": {"http://a"}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := r.acquire("swift"); !errors.Is(err, ErrNoWorker) {
+		t.Errorf("err = %v, want ErrNoWorker", err)
+	}
+}
+
+func TestWorkerRegistry_SkipsUnreachableWorkers(t *testing.T) {
+	r, err := NewWorkerRegistry(map[string][]string{"php": {"http://a", "http://b"}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	url, release, err := r.acquire("php")
+	if err != nil || url != "http://a" {
+		t.Fatalf("acquire = %q, %v", url, err)
+	}
+	release(errors.New("connection refused"))
+
+	for range 2 {
+		url, release, err := r.acquire("php")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if url != "http://b" {
+			t.Errorf("acquire = %q, want the reachable worker", url)
+		}
+		release(nil)
+	}
+	_, release, _ = r.acquire("php")
+	release(errors.New("connection refused"))
+	if _, _, err := r.acquire("php"); !errors.Is(err, ErrNoHealthyWorker) {
+		t.Errorf("err = %v, want ErrNoHealthyWorker", err)
+	}
+}
+
+func TestWorkerRegistry_CheckHealth(t *testing.T) {
+	infoServer := func(info WorkerInfo) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != "/info" {
+				http.NotFound(w, r)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(info)
+		}))
+	}
+	healthy := infoServer(WorkerInfo{Language: "php", Version: "8.4.4", MaxOutputBytes: 10240, Running: 2})
+	defer healthy.Close()
+	wrong := infoServer(WorkerInfo{Language: "swift", Version: "6.1.2"})
+	defer wrong.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	r, err := NewWorkerRegistry(map[string][]string{"php": {healthy.URL, wrong.URL, down.URL}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// The worker comes back once it passes a health check.
+	_, release, err := r.acquire("php")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	release(errors.New("connection refused"))
+
+	r.CheckHealth(context.Background())
+
+	statuses := r.Status()
+	if len(statuses) != 3 {
+		t.Fatalf("got %d statuses, want 3", len(statuses))
+	}
+	if s := statuses[0]; !s.Healthy || s.Info == nil || s.Info.Version != "8.4.4" || s.Info.Running != 2 || s.CheckedAt.IsZero() {
+		t.Errorf("healthy worker = %+v", s)
+	}
+	if s := statuses[1]; s.Healthy || s.Error == "" {
+		t.Errorf("worker of another language = %+v, want unhealthy", s)
+	}
+	if s := statuses[2]; s.Healthy || s.Error == "" {
+		t.Errorf("worker that is down = %+v, want unhealthy", s)
+	}
+	for range 3 {
+		url, release, err := r.acquire("php")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release(nil)
+		if url != healthy.URL {
+			t.Errorf("acquire = %q, want the healthy worker", url)
+		}
+	}
+}
+
+func TestWorkerRegistry_MaxMemoryBytes(t *testing.T) {
+	infoServer := func(maxMemoryBytes int64) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+			w.Header().Set("Content-Type", "application/json")
+			_ = json.NewEncoder(w).Encode(WorkerInfo{Language: "php", MaxMemoryBytes: maxMemoryBytes})
+		}))
+	}
+	large := infoServer(16 << 20)
+	defer large.Close()
+	small := infoServer(10 << 20)
+	defer small.Close()
+	unknown := infoServer(0)
+	defer unknown.Close()
+
+	r, err := NewWorkerRegistry(map[string][]string{"php": {large.URL, small.URL, unknown.URL}}, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := r.MaxMemoryBytes("php"); got != 0 {
+		t.Errorf("MaxMemoryBytes before a health check = %d, want 0", got)
+	}
+	r.CheckHealth(context.Background())
+	if got := r.MaxMemoryBytes("php"); got != 10<<20 {
+		t.Errorf("MaxMemoryBytes = %d, want %d", got, 10<<20)
+	}
+	if got := r.MaxMemoryBytes("swift"); got != 0 {
+		t.Errorf("MaxMemoryBytes of a language without workers = %d, want 0", got)
+	}
+}
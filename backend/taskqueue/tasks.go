@@ -9,45 +9,218 @@ import (
 type TaskType string
 
 const (
-	TaskTypeRunTestcase TaskType = "run_testcase"
+	// TaskTypeRunSubmission is the type of the tasks that run a submission on
+	// all the testcases of the problem, compiling it only once.
+	TaskTypeRunSubmission TaskType = "run_submission"
+	TaskTypeRunChecker    TaskType = "run_checker"
+	TaskTypeRunCustom     TaskType = "run_custom"
+	// TaskTypeRunValidation is the type of the tasks that judge a reference
+	// solution of a problem before a game.
+	TaskTypeRunValidation TaskType = "run_validation"
 )
 
-type TaskPayloadRunTestcase struct {
+// SubmissionTestcase is a testcase to run a submission on. Zero limits mean
+// the defaults of the worker.
+type SubmissionTestcase struct {
+	TestcaseID     int
+	Stdin          string
+	Stdout         string
+	TimeLimitMs    int
+	MemoryLimitMiB int
+}
+
+// TaskPayloadRunSubmission runs a submission on all the testcases of the
+// problem in a single request to the worker.
+type TaskPayloadRunSubmission struct {
 	GameID       int
 	UserID       int
 	SubmissionID int
-	TestcaseID   int
 	Language     string
 	Code         string
-	Stdin        string
-	Stdout       string
+	Testcases    []SubmissionTestcase
 }
 
-func newTaskRunTestcase(
+func newTaskRunSubmission(
 	gameID int,
 	userID int,
 	submissionID int,
-	testcaseID int,
 	language string,
 	code string,
-	stdin string,
-	stdout string,
+	testcases []SubmissionTestcase,
 ) (*asynq.Task, error) {
-	payload, err := json.Marshal(TaskPayloadRunTestcase{
+	payload, err := json.Marshal(TaskPayloadRunSubmission{
 		GameID:       gameID,
 		UserID:       userID,
 		SubmissionID: submissionID,
-		TestcaseID:   testcaseID,
 		Language:     language,
 		Code:         code,
-		Stdin:        stdin,
-		Stdout:       stdout,
+		Testcases:    testcases,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(
+		string(TaskTypeRunSubmission),
+		payload,
+		asynq.MaxRetry(3),
+	), nil
+}
+
+// TaskPayloadRunChecker runs a special judge program for the output of a
+// testcase. SubmissionStdout, SubmissionStderr and the resource usage are
+// those of the run being judged; they are carried through so that they can be
+// recorded with the verdict. The limits are those of the testcase, as for the
+// submission.
+type TaskPayloadRunChecker struct {
+	GameID                int
+	UserID                int
+	SubmissionID          int
+	TestcaseID            int
+	Language              string
+	CheckerCode           string
+	Stdin                 string
+	SubmissionStdout      string
+	SubmissionStderr      string
+	SubmissionTimeMs      *int
+	SubmissionMemoryBytes *int64
+	TimeLimitMs           int
+	MemoryLimitMiB        int
+}
+
+func newTaskRunChecker(
+	gameID int,
+	userID int,
+	submissionID int,
+	testcaseID int,
+	language string,
+	checkerCode string,
+	stdin string,
+	submissionStdout string,
+	submissionStderr string,
+	submissionTimeMs *int,
+	submissionMemoryBytes *int64,
+	timeLimitMs int,
+	memoryLimitMiB int,
+) (*asynq.Task, error) {
+	payload, err := json.Marshal(TaskPayloadRunChecker{
+		GameID:                gameID,
+		UserID:                userID,
+		SubmissionID:          submissionID,
+		TestcaseID:            testcaseID,
+		Language:              language,
+		CheckerCode:           checkerCode,
+		Stdin:                 stdin,
+		SubmissionStdout:      submissionStdout,
+		SubmissionStderr:      submissionStderr,
+		SubmissionTimeMs:      submissionTimeMs,
+		SubmissionMemoryBytes: submissionMemoryBytes,
+		TimeLimitMs:           timeLimitMs,
+		MemoryLimitMiB:        memoryLimitMiB,
 	})
 	if err != nil {
 		return nil, err
 	}
 	return asynq.NewTask(
-		string(TaskTypeRunTestcase),
+		string(TaskTypeRunChecker),
+		payload,
+		asynq.MaxRetry(3),
+	), nil
+}
+
+// TaskPayloadRunCustom runs code with arbitrary stdin given by a player. It is
+// not tied to any submission; RunID identifies the request waiting for it.
+// The limits are those of the problem.
+type TaskPayloadRunCustom struct {
+	RunID          string
+	GameID         int
+	UserID         int
+	Language       string
+	Code           string
+	Stdin          string
+	TimeLimitMs    int
+	MemoryLimitMiB int
+}
+
+func newTaskRunCustom(
+	runID string,
+	gameID int,
+	userID int,
+	language string,
+	code string,
+	stdin string,
+	timeLimitMs int,
+	memoryLimitMiB int,
+) (*asynq.Task, error) {
+	payload, err := json.Marshal(TaskPayloadRunCustom{
+		RunID:          runID,
+		GameID:         gameID,
+		UserID:         userID,
+		Language:       language,
+		Code:           code,
+		Stdin:          stdin,
+		TimeLimitMs:    timeLimitMs,
+		MemoryLimitMiB: memoryLimitMiB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	// The player is waiting for the result, so it is not worth retrying.
+	return asynq.NewTask(
+		string(TaskTypeRunCustom),
+		payload,
+		asynq.MaxRetry(0),
+	), nil
+}
+
+// TaskPayloadRunValidation runs a reference solution of a problem on a
+// testcase. It is not tied to any game or submission. If CheckerCode is set,
+// the special judge program is run on the output in the same task. The limits
+// are those of the testcase, as for submissions.
+type TaskPayloadRunValidation struct {
+	ValidationID        int
+	ProblemID           int
+	ReferenceSolutionID int
+	TestcaseID          int
+	Language            string
+	Code                string
+	Stdin               string
+	Stdout              string
+	CheckerCode         string
+	TimeLimitMs         int
+	MemoryLimitMiB      int
+}
+
+func newTaskRunValidation(
+	validationID int,
+	problemID int,
+	referenceSolutionID int,
+	testcaseID int,
+	language string,
+	code string,
+	stdin string,
+	stdout string,
+	checkerCode string,
+	timeLimitMs int,
+	memoryLimitMiB int,
+) (*asynq.Task, error) {
+	payload, err := json.Marshal(TaskPayloadRunValidation{
+		ValidationID:        validationID,
+		ProblemID:           problemID,
+		ReferenceSolutionID: referenceSolutionID,
+		TestcaseID:          testcaseID,
+		Language:            language,
+		Code:                code,
+		Stdin:               stdin,
+		Stdout:              stdout,
+		CheckerCode:         checkerCode,
+		TimeLimitMs:         timeLimitMs,
+		MemoryLimitMiB:      memoryLimitMiB,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return asynq.NewTask(
+		string(TaskTypeRunValidation),
 		payload,
 		asynq.MaxRetry(3),
 	), nil
@@ -58,13 +231,65 @@ type TaskResult interface {
 	GameID() int
 }
 
-type TaskResultRunTestcase struct {
-	TaskPayload *TaskPayloadRunTestcase
+// TestcaseRunResult is the result of a submission on one of the testcases of
+// a TaskPayloadRunSubmission. TimeMs and MemoryBytes are nil if the worker
+// did not measure them.
+type TestcaseRunResult struct {
+	TestcaseID  int
+	Status      string
+	Stdout      string
+	Stderr      string
+	TimeMs      *int
+	MemoryBytes *int64
+}
+
+// TaskResultRunSubmission has the results in the order of the testcases of
+// the payload.
+type TaskResultRunSubmission struct {
+	TaskPayload *TaskPayloadRunSubmission
+	Results     []TestcaseRunResult
+	Err         error
+}
+
+func (r *TaskResultRunSubmission) Type() TaskType { return TaskTypeRunSubmission }
+func (r *TaskResultRunSubmission) GameID() int    { return r.TaskPayload.GameID }
+
+type TaskResultRunChecker struct {
+	TaskPayload *TaskPayloadRunChecker
 	Status      string
 	Stdout      string
 	Stderr      string
 	Err         error
 }
 
-func (r *TaskResultRunTestcase) Type() TaskType { return TaskTypeRunTestcase }
-func (r *TaskResultRunTestcase) GameID() int    { return r.TaskPayload.GameID }
+func (r *TaskResultRunChecker) Type() TaskType { return TaskTypeRunChecker }
+func (r *TaskResultRunChecker) GameID() int    { return r.TaskPayload.GameID }
+
+type TaskResultRunCustom struct {
+	TaskPayload *TaskPayloadRunCustom
+	Status      string
+	Stdout      string
+	Stderr      string
+	Err         error
+}
+
+func (r *TaskResultRunCustom) Type() TaskType { return TaskTypeRunCustom }
+func (r *TaskResultRunCustom) GameID() int    { return r.TaskPayload.GameID }
+
+// TaskResultRunValidation is the result of a reference solution. If the
+// special judge program was run, CheckerStatus and CheckerStdout are its
+// result.
+type TaskResultRunValidation struct {
+	TaskPayload   *TaskPayloadRunValidation
+	Status        string
+	Stdout        string
+	Stderr        string
+	CheckerStatus string
+	CheckerStdout string
+	Err           error
+}
+
+func (r *TaskResultRunValidation) Type() TaskType { return TaskTypeRunValidation }
+
+// GameID is always 0, as validations are not tied to any game.
+func (r *TaskResultRunValidation) GameID() int { return 0 }
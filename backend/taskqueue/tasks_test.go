@@ -2,62 +2,192 @@ package taskqueue
 
 import (
 	"encoding/json"
+	"slices"
 	"testing"
 )
 
-func TestNewTaskRunTestcase(t *testing.T) {
-	task, err := newTaskRunTestcase(1, 2, 3, 4, "php", "<?php echo 1;", "input", "output")
+func TestNewTaskRunSubmission(t *testing.T) {
+	testcases := []SubmissionTestcase{
+		{TestcaseID: 4, Stdin: "in1", Stdout: "out1"},
+		{TestcaseID: 5, Stdin: "in2", Stdout: "out2"},
+	}
+	task, err := newTaskRunSubmission(1, 2, 3, "swift", "print(1)", testcases)
 	if err != nil {
-		t.Fatalf("newTaskRunTestcase returned error: %v", err)
+		t.Fatalf("newTaskRunSubmission returned error: %v", err)
 	}
-	if task.Type() != string(TaskTypeRunTestcase) {
-		t.Errorf("task type = %q, want %q", task.Type(), TaskTypeRunTestcase)
+	if task.Type() != string(TaskTypeRunSubmission) {
+		t.Errorf("task type = %q, want %q", task.Type(), TaskTypeRunSubmission)
 	}
 
-	var payload TaskPayloadRunTestcase
+	var payload TaskPayloadRunSubmission
 	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
 		t.Fatalf("failed to unmarshal payload: %v", err)
 	}
-	if payload.GameID != 1 {
-		t.Errorf("GameID = %d, want 1", payload.GameID)
+	if payload.GameID != 1 || payload.UserID != 2 || payload.SubmissionID != 3 {
+		t.Errorf("payload = %+v", payload)
+	}
+	if payload.Language != "swift" || payload.Code != "print(1)" {
+		t.Errorf("payload = %+v", payload)
+	}
+	if !slices.Equal(payload.Testcases, testcases) {
+		t.Errorf("Testcases = %+v, want %+v", payload.Testcases, testcases)
+	}
+}
+
+func TestTaskResultRunSubmission_Interface(t *testing.T) {
+	result := &TaskResultRunSubmission{
+		TaskPayload: &TaskPayloadRunSubmission{GameID: 42},
 	}
-	if payload.UserID != 2 {
-		t.Errorf("UserID = %d, want 2", payload.UserID)
+
+	var _ TaskResult = result
+
+	if result.Type() != TaskTypeRunSubmission {
+		t.Errorf("Type() = %q, want %q", result.Type(), TaskTypeRunSubmission)
+	}
+	if result.GameID() != 42 {
+		t.Errorf("GameID() = %d, want 42", result.GameID())
+	}
+}
+
+func TestNewTaskRunChecker(t *testing.T) {
+	timeMs, memoryBytes := 12, int64(3456)
+	task, err := newTaskRunChecker(1, 2, 3, 4, "php", "<?php echo 'AC';", "{}", "out", "err", &timeMs, &memoryBytes, 2000, 64)
+	if err != nil {
+		t.Fatalf("newTaskRunChecker returned error: %v", err)
 	}
-	if payload.SubmissionID != 3 {
-		t.Errorf("SubmissionID = %d, want 3", payload.SubmissionID)
+	if task.Type() != string(TaskTypeRunChecker) {
+		t.Errorf("task type = %q, want %q", task.Type(), TaskTypeRunChecker)
 	}
-	if payload.TestcaseID != 4 {
-		t.Errorf("TestcaseID = %d, want 4", payload.TestcaseID)
+
+	var payload TaskPayloadRunChecker
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
 	}
-	if payload.Language != "php" {
-		t.Errorf("Language = %q, want %q", payload.Language, "php")
+	want := TaskPayloadRunChecker{
+		GameID:           1,
+		UserID:           2,
+		SubmissionID:     3,
+		TestcaseID:       4,
+		Language:         "php",
+		CheckerCode:      "<?php echo 'AC';",
+		Stdin:            "{}",
+		SubmissionStdout: "out",
+		SubmissionStderr: "err",
+		TimeLimitMs:      2000,
+		MemoryLimitMiB:   64,
 	}
-	if payload.Code != "<?php echo 1;" {
-		t.Errorf("Code = %q, want %q", payload.Code, "<?php echo 1;")
+	if payload.SubmissionTimeMs == nil || *payload.SubmissionTimeMs != 12 {
+		t.Errorf("SubmissionTimeMs = %v, want 12", payload.SubmissionTimeMs)
 	}
-	if payload.Stdin != "input" {
-		t.Errorf("Stdin = %q, want %q", payload.Stdin, "input")
+	if payload.SubmissionMemoryBytes == nil || *payload.SubmissionMemoryBytes != 3456 {
+		t.Errorf("SubmissionMemoryBytes = %v, want 3456", payload.SubmissionMemoryBytes)
 	}
-	if payload.Stdout != "output" {
-		t.Errorf("Stdout = %q, want %q", payload.Stdout, "output")
+	payload.SubmissionTimeMs, payload.SubmissionMemoryBytes = nil, nil
+	if payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
 	}
 }
 
-func TestTaskResultRunTestcase_Interface(t *testing.T) {
-	result := &TaskResultRunTestcase{
-		TaskPayload: &TaskPayloadRunTestcase{GameID: 42},
-		Status:      "pass",
-		Stdout:      "hello",
-		Stderr:      "",
+func TestTaskResultRunChecker_Interface(t *testing.T) {
+	result := &TaskResultRunChecker{
+		TaskPayload: &TaskPayloadRunChecker{GameID: 42},
 	}
 
 	var _ TaskResult = result
 
-	if result.Type() != TaskTypeRunTestcase {
-		t.Errorf("Type() = %q, want %q", result.Type(), TaskTypeRunTestcase)
+	if result.Type() != TaskTypeRunChecker {
+		t.Errorf("Type() = %q, want %q", result.Type(), TaskTypeRunChecker)
 	}
 	if result.GameID() != 42 {
 		t.Errorf("GameID() = %d, want 42", result.GameID())
 	}
 }
+
+func TestNewTaskRunCustom(t *testing.T) {
+	task, err := newTaskRunCustom("run-1", 1, 2, "php", "<?php echo fgets(STDIN);", "hello", 2000, 64)
+	if err != nil {
+		t.Fatalf("newTaskRunCustom returned error: %v", err)
+	}
+	if task.Type() != string(TaskTypeRunCustom) {
+		t.Errorf("task type = %q, want %q", task.Type(), TaskTypeRunCustom)
+	}
+
+	var payload TaskPayloadRunCustom
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	want := TaskPayloadRunCustom{
+		RunID:          "run-1",
+		GameID:         1,
+		UserID:         2,
+		Language:       "php",
+		Code:           "<?php echo fgets(STDIN);",
+		Stdin:          "hello",
+		TimeLimitMs:    2000,
+		MemoryLimitMiB: 64,
+	}
+	if payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+}
+
+func TestTaskResultRunCustom_Interface(t *testing.T) {
+	result := &TaskResultRunCustom{
+		TaskPayload: &TaskPayloadRunCustom{GameID: 42},
+	}
+
+	var _ TaskResult = result
+
+	if result.Type() != TaskTypeRunCustom {
+		t.Errorf("Type() = %q, want %q", result.Type(), TaskTypeRunCustom)
+	}
+	if result.GameID() != 42 {
+		t.Errorf("GameID() = %d, want 42", result.GameID())
+	}
+}
+
+func TestNewTaskRunValidation(t *testing.T) {
+	task, err := newTaskRunValidation(1, 2, 3, 4, "php", "<?php echo 1;", "input", "output", "<?php echo 'AC';", 2000, 64)
+	if err != nil {
+		t.Fatalf("newTaskRunValidation returned error: %v", err)
+	}
+	if task.Type() != string(TaskTypeRunValidation) {
+		t.Errorf("task type = %q, want %q", task.Type(), TaskTypeRunValidation)
+	}
+
+	var payload TaskPayloadRunValidation
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		t.Fatalf("failed to unmarshal payload: %v", err)
+	}
+	want := TaskPayloadRunValidation{
+		ValidationID:        1,
+		ProblemID:           2,
+		ReferenceSolutionID: 3,
+		TestcaseID:          4,
+		Language:            "php",
+		Code:                "<?php echo 1;",
+		Stdin:               "input",
+		Stdout:              "output",
+		CheckerCode:         "<?php echo 'AC';",
+		TimeLimitMs:         2000,
+		MemoryLimitMiB:      64,
+	}
+	if payload != want {
+		t.Errorf("payload = %+v, want %+v", payload, want)
+	}
+}
+
+func TestTaskResultRunValidation_Interface(t *testing.T) {
+	result := &TaskResultRunValidation{
+		TaskPayload: &TaskPayloadRunValidation{ValidationID: 42},
+	}
+
+	var _ TaskResult = result
+
+	if result.Type() != TaskTypeRunValidation {
+		t.Errorf("Type() = %q, want %q", result.Type(), TaskTypeRunValidation)
+	}
+	if result.GameID() != 0 {
+		t.Errorf("GameID() = %d, want 0", result.GameID())
+	}
+}
@@ -9,14 +9,14 @@ type WorkerServer struct {
 	processor *processorWrapper
 }
 
-func NewWorkerServer(redisAddr string) *WorkerServer {
+func NewWorkerServer(redisAddr string, workers *WorkerRegistry) *WorkerServer {
 	server := asynq.NewServer(
 		asynq.RedisClientOpt{
 			Addr: redisAddr,
 		},
 		asynq.Config{},
 	)
-	processor := newProcessorWrapper(newProcessor())
+	processor := newProcessorWrapper(newProcessor(workers))
 	return &WorkerServer{
 		server:    server,
 		processor: processor,
@@ -26,7 +26,10 @@ func NewWorkerServer(redisAddr string) *WorkerServer {
 func (s *WorkerServer) Run() error {
 	mux := asynq.NewServeMux()
 
-	mux.HandleFunc(string(TaskTypeRunTestcase), s.processor.processTaskRunTestcase)
+	mux.HandleFunc(string(TaskTypeRunSubmission), s.processor.processTaskRunSubmission)
+	mux.HandleFunc(string(TaskTypeRunChecker), s.processor.processTaskRunChecker)
+	mux.HandleFunc(string(TaskTypeRunCustom), s.processor.processTaskRunCustom)
+	mux.HandleFunc(string(TaskTypeRunValidation), s.processor.processTaskRunValidation)
 
 	return s.server.Run(mux)
 }
@@ -3,6 +3,7 @@ package tournament
 import (
 	"context"
 	"errors"
+	"time"
 
 	"github.com/jackc/pgx/v5"
 
@@ -45,6 +46,8 @@ type Player struct {
 	IconPath    *string
 	IsAdmin     bool
 	Label       *string
+	Rating      int
+	RatingRank  *int
 }
 
 type Entry struct {
@@ -123,6 +126,11 @@ func (s *Service) GetTournament(ctx context.Context, tournamentID int) (Bracket,
 			IconPath:    e.IconPath,
 			IsAdmin:     e.IsAdmin,
 			Label:       e.Label,
+			Rating:      int(e.Rating),
+		}
+		if e.RatingRank != nil {
+			rank := int(*e.RatingRank)
+			u.RatingRank = &rank
 		}
 		seedToUser[int(e.Seed)] = u
 		entries[i] = Entry{
@@ -169,15 +177,22 @@ func (s *Service) GetTournament(ctx context.Context, tournamentID int) (Bracket,
 		if !gameRow.StartedAt.Valid {
 			continue
 		}
-		rankingRows, err := s.q.GetRanking(ctx, gid)
+		// Cancelled games decide nothing.
+		if gameRow.OverrideState != nil && game.State(*gameRow.OverrideState) == game.StateCancelled {
+			continue
+		}
+		rankingRows, err := s.getBracketRanking(ctx, gameRow)
 		if err != nil || len(rankingRows) == 0 {
 			continue
 		}
 		rr := &rankingResult{scores: make(map[int]int)}
+		// The members of a team share its score.
 		for i, r := range rankingRows {
-			rr.scores[int(r.User.UserID)] = int(r.Submission.CodeSize)
-			if i == 0 {
-				rr.winnerID = int(r.User.UserID)
+			for _, u := range r.Members {
+				rr.scores[int(u.UserID)] = r.Score
+				if i == 0 && rr.winnerID == 0 {
+					rr.winnerID = int(u.UserID)
+				}
 			}
 		}
 		gameRankings[gid] = rr
@@ -320,6 +335,14 @@ func (s *Service) GetTournament(ctx context.Context, tournamentID int) (Bracket,
 	}, nil
 }
 
+// getBracketRanking returns the ranking of a match game shown in the bracket.
+// It is frozen like the ranking of the game itself.
+func (s *Service) getBracketRanking(ctx context.Context, gameRow db.Game) ([]game.RankedTeam, error) {
+	cutoff, frozen := game.RankingCutoff(gameRow, time.Now())
+	rows, _, err := game.RankedRows(ctx, s.q, gameRow, cutoff, frozen)
+	return rows, err
+}
+
 // CreateTournament creates a new tournament with the given number of participants.
 func (s *Service) CreateTournament(ctx context.Context, displayName string, numParticipants int) (int, error) {
 	if numParticipants < 2 {
@@ -8,6 +8,8 @@ import GolfProblemPreviewPage from "./pages/GolfProblemPreviewPage";
 import GolfWatchPage from "./pages/GolfWatchPage";
 import IndexPage from "./pages/IndexPage";
 import LoginPage from "./pages/LoginPage";
+import QualifyingRankingPage from "./pages/QualifyingRankingPage";
+import RatingsPage from "./pages/RatingsPage";
 import SubmissionsPage from "./pages/SubmissionsPage";
 import TournamentPage from "./pages/TournamentPage";
 
@@ -52,6 +54,12 @@ export default function App() {
 				<Route path="/golf/:gameId/watch">
 					{(params) => <GolfWatchPage gameId={params.gameId} />}
 				</Route>
+				<Route path="/qualifying/:stageId">
+					{(params) => <QualifyingRankingPage stageId={params.stageId} />}
+				</Route>
+				<Route path="/ratings">
+					<RatingsPage />
+				</Route>
 				<Route path="/tournament/:tournamentId">
 					{(params) => <TournamentPage tournamentId={params.tournamentId} />}
 				</Route>
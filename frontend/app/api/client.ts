@@ -1,7 +1,11 @@
 import createClient from "openapi-fetch";
 import { createContext } from "react";
 import { API_BASE_PATH } from "../config";
-import type { paths } from "./schema";
+import type { components, paths } from "./schema";
+
+type GameEvent = components["schemas"]["GameEvent"];
+type GameEventType = components["schemas"]["GameEventType"];
+type ProblemLanguage = components["schemas"]["ProblemLanguage"];
 
 const apiOrigin =
 	import.meta.env.VITE_API_BASE_URL ??
@@ -34,6 +38,31 @@ export async function apiGetMe() {
 	return data;
 }
 
+const gameEventTypes: GameEventType[] = [
+	"code",
+	"status",
+	"best_score",
+	"game",
+];
+
+function subscribeGameEvents(
+	path: string,
+	onEvent: (event: GameEvent) => void,
+): () => void {
+	const source = new EventSource(`${apiOrigin}${API_BASE_PATH}${path}`, {
+		withCredentials: true,
+	});
+	const listener = (e: MessageEvent<string>) => {
+		onEvent(JSON.parse(e.data) as GameEvent);
+	};
+	for (const type of gameEventTypes) {
+		source.addEventListener(type, listener);
+	}
+	return () => {
+		source.close();
+	};
+}
+
 class AuthenticatedApiClient {
 	async getGames() {
 		const { data, error } = await client.GET("/games");
@@ -51,12 +80,13 @@ class AuthenticatedApiClient {
 		return data;
 	}
 
-	async getGamePlayLatestState(gameId: number) {
+	async getGamePlayLatestState(gameId: number, problemId: number) {
 		const { data, error } = await client.GET(
 			"/games/{game_id}/play/latest_state",
 			{
 				params: {
 					path: { game_id: gameId },
+					query: { problem_id: problemId },
 				},
 			},
 		);
@@ -64,27 +94,61 @@ class AuthenticatedApiClient {
 		return data;
 	}
 
-	async postGamePlayCode(gameId: number, code: string) {
+	async postGamePlayCode(
+		gameId: number,
+		problemId: number,
+		language: ProblemLanguage,
+		code: string,
+	) {
 		const { error } = await client.POST("/games/{game_id}/play/code", {
 			params: {
 				path: { game_id: gameId },
 			},
-			body: { code },
+			body: { problem_id: problemId, language, code },
 		});
 		if (error) throw new Error(error.message);
 	}
 
-	async postGamePlaySubmit(gameId: number, code: string) {
+	async postGamePlaySubmit(
+		gameId: number,
+		problemId: number,
+		language: ProblemLanguage,
+		code: string,
+	) {
 		const { data, error } = await client.POST("/games/{game_id}/play/submit", {
 			params: {
 				path: { game_id: gameId },
 			},
-			body: { code },
+			body: { problem_id: problemId, language, code },
+		});
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async postGamePlayRun(
+		gameId: number,
+		problemId: number,
+		language: ProblemLanguage,
+		code: string,
+		stdin: string,
+	) {
+		const { data, error } = await client.POST("/games/{game_id}/play/run", {
+			params: {
+				path: { game_id: gameId },
+			},
+			body: { problem_id: problemId, language, code, stdin },
 		});
 		if (error) throw new Error(error.message);
 		return data;
 	}
 
+	subscribeGamePlayEvents(
+		gameId: number,
+		onEvent: (event: GameEvent) => void,
+	): () => void {
+		return subscribeGameEvents(`games/${gameId}/play/events`, onEvent);
+	}
+
 	async getGamePlaySubmissions(gameId: number) {
 		const { data, error } = await client.GET(
 			"/games/{game_id}/play/submissions",
@@ -98,22 +162,129 @@ class AuthenticatedApiClient {
 		return data;
 	}
 
-	async getGameWatchRanking(gameId: number) {
+	async getGamePlaySubmission(gameId: number, submissionId: number) {
+		const { data, error } = await client.GET(
+			"/games/{game_id}/play/submissions/{submission_id}",
+			{
+				params: {
+					path: { game_id: gameId, submission_id: submissionId },
+				},
+			},
+		);
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getGameWatchRanking(
+		gameId: number,
+		cursor?: string,
+		practice?: boolean,
+	) {
 		const { data, error } = await client.GET("/games/{game_id}/watch/ranking", {
 			params: {
 				path: { game_id: gameId },
+				query: { cursor, practice },
 			},
 		});
 		if (error) throw new Error(error.message);
 		return data;
 	}
 
-	async getGameWatchLatestStates(gameId: number) {
+	// Fetches all the pages of the ranking.
+	async getGameWatchFullRanking(gameId: number, practice?: boolean) {
+		let page = await this.getGameWatchRanking(gameId, undefined, practice);
+		const ranking = [...page.ranking];
+		while (page.next_cursor !== null) {
+			page = await this.getGameWatchRanking(
+				gameId,
+				page.next_cursor,
+				practice,
+			);
+			ranking.push(...page.ranking);
+		}
+		return { ...page, ranking };
+	}
+
+	async getGameWatchLatestStates(gameId: number, problemId: number) {
 		const { data, error } = await client.GET(
 			"/games/{game_id}/watch/latest_states",
 			{
 				params: {
 					path: { game_id: gameId },
+					query: { problem_id: problemId },
+				},
+			},
+		);
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getGameWatchReplay(gameId: number, userId: number, problemId: number) {
+		const { data, error } = await client.GET(
+			"/games/{game_id}/watch/replay",
+			{
+				params: {
+					path: { game_id: gameId },
+					query: { user_id: userId, problem_id: problemId },
+				},
+			},
+		);
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getGameSubmissionDiff(gameId: number, from: number, to: number) {
+		const { data, error } = await client.GET(
+			"/games/{game_id}/submissions/diff",
+			{
+				params: {
+					path: { game_id: gameId },
+					query: { from, to },
+				},
+			},
+		);
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	subscribeGameWatchEvents(
+		gameId: number,
+		onEvent: (event: GameEvent) => void,
+	): () => void {
+		return subscribeGameEvents(`games/${gameId}/watch/events`, onEvent);
+	}
+
+	async getQualifyingStages() {
+		const { data, error } = await client.GET("/qualifying_stages");
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getQualifyingStageRanking(stageId: number) {
+		const { data, error } = await client.GET(
+			"/qualifying_stages/{stage_id}/ranking",
+			{
+				params: {
+					path: { stage_id: stageId },
+				},
+			},
+		);
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getRatings() {
+		const { data, error } = await client.GET("/ratings");
+		if (error) throw new Error(error.message);
+		return data;
+	}
+
+	async getUserRatingHistory(userId: number) {
+		const { data, error } = await client.GET(
+			"/users/{user_id}/rating_history",
+			{
+				params: {
+					path: { user_id: userId },
 				},
 			},
 		);
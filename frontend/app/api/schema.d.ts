@@ -52,6 +52,22 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/games/{game_id}/play/events": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGamePlayEvents"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/games/{game_id}/play/latest_state": {
         parameters: {
             query?: never;
@@ -68,6 +84,22 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/games/{game_id}/play/run": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get?: never;
+        put?: never;
+        post: operations["postGamePlayRun"];
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/games/{game_id}/play/submissions": {
         parameters: {
             query?: never;
@@ -84,6 +116,22 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/games/{game_id}/play/submissions/{submission_id}": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGamePlaySubmission"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/games/{game_id}/play/submit": {
         parameters: {
             query?: never;
@@ -100,6 +148,38 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/games/{game_id}/submissions/diff": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGameSubmissionDiff"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/games/{game_id}/watch/events": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGameWatchEvents"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/games/{game_id}/watch/latest_states": {
         parameters: {
             query?: never;
@@ -132,6 +212,38 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/games/{game_id}/watch/replay": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGameWatchReplay"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/games/{game_id}/watch/teams": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getGameWatchTeams"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/login": {
         parameters: {
             query?: never;
@@ -180,6 +292,54 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/qualifying_stages": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getQualifyingStages"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/qualifying_stages/{stage_id}/ranking": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getQualifyingStageRanking"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
+    "/ratings": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getRatings"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
     "/tournaments/{tournament_id}": {
         parameters: {
             query?: never;
@@ -196,10 +356,40 @@ export interface paths {
         patch?: never;
         trace?: never;
     };
+    "/users/{user_id}/rating_history": {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        get: operations["getUserRatingHistory"];
+        put?: never;
+        post?: never;
+        delete?: never;
+        options?: never;
+        head?: never;
+        patch?: never;
+        trace?: never;
+    };
 }
 export type webhooks = Record<string, never>;
 export interface components {
     schemas: {
+        CodeSnapshot: {
+            user_id: number;
+            code: string;
+            is_submission: boolean;
+            created_at: number;
+        };
+        DiffLine: {
+            op: components["schemas"]["DiffOp"];
+            text: string;
+            old_line?: number;
+            new_line?: number;
+        };
+        /** @enum {string} */
+        DiffOp: "equal" | "insert" | "delete";
         Error: {
             message: string;
         };
@@ -212,40 +402,131 @@ export interface components {
             display_name: string;
             duration_seconds: number;
             started_at?: number;
-            problem: components["schemas"]["Problem"];
+            state: components["schemas"]["GameState"];
+            paused_at?: number;
+            allow_practice: boolean;
+            problems: components["schemas"]["Problem"][];
             main_players: components["schemas"]["User"][];
         };
+        /** @description Sent as the data of a server-sent event whose event name is the same as `type`. */
+        GameEvent: {
+            type: components["schemas"]["GameEventType"];
+            user_id: number;
+            team_id: number;
+            problem_id: number;
+            language?: components["schemas"]["ProblemLanguage"];
+            code?: string;
+            status?: components["schemas"]["ExecutionStatus"];
+            score?: number;
+            best_score_submitted_at?: number;
+        };
+        /** @enum {string} */
+        GameEventType: "code" | "status" | "best_score" | "game";
+        /** @enum {string} */
+        GameState: "waiting" | "scheduled" | "running" | "paused" | "finished" | "cancelled";
         /** @enum {string} */
         GameType: "1v1" | "multiplayer";
         LatestGameState: {
+            language?: components["schemas"]["ProblemLanguage"];
             code: string;
             score: number | null;
             best_score_submitted_at: number | null;
             status: components["schemas"]["ExecutionStatus"];
         };
+        /** @enum {string} */
+        MissingScorePolicy: "penalty" | "exclude";
         Problem: {
             problem_id: number;
             title: string;
             description: string;
             language: components["schemas"]["ProblemLanguage"];
             sample_code: string;
+            scoring: components["schemas"]["ScoringStrategy"];
+            languages: components["schemas"]["ProblemLanguageOption"][];
         };
         /** @enum {string} */
         ProblemLanguage: "php" | "swift";
+        ProblemLanguageOption: {
+            language: components["schemas"]["ProblemLanguage"];
+            sample_code: string;
+        };
+        ProblemScore: {
+            problem_id: number;
+            score: number | null;
+        };
+        QualifyingEntry: {
+            rank: number;
+            user: components["schemas"]["User"];
+            results: components["schemas"]["QualifyingResult"][];
+            /** Format: double */
+            total: number;
+            submitted_at: number;
+        };
+        QualifyingResult: {
+            game_id: number;
+            score: number;
+            submitted_at: number;
+        };
+        QualifyingStage: {
+            stage_id: number;
+            display_name: string;
+            missing_policy: components["schemas"]["MissingScorePolicy"];
+            missing_penalty: number;
+            games: components["schemas"]["QualifyingStageGame"][];
+        };
+        QualifyingStageGame: {
+            game_id: number;
+            display_name: string;
+            /** Format: double */
+            weight: number;
+        };
         RankingEntry: {
-            player: components["schemas"]["User"];
+            rank: number;
+            team: components["schemas"]["Team"];
             score: number;
+            problem_scores: components["schemas"]["ProblemScore"][];
+            submission_count: number;
             submitted_at: number;
             code: string | null;
         };
+        /** @enum {string} */
+        RatingChange: {
+            game_id: number;
+            game_display_name: string;
+            game_rank: number;
+            rating_before: number;
+            rating_after: number;
+            rated_at: number;
+        };
+        ScoringStrategy: "bytes" | "codepoints" | "stripped_bytes" | "php_tokens";
         Submission: {
             submission_id: number;
             game_id: number;
+            problem_id: number;
+            language: components["schemas"]["ProblemLanguage"];
             code: string;
             code_size: number;
             status: components["schemas"]["ExecutionStatus"];
+            is_practice: boolean;
             created_at: number;
         };
+        Team: {
+            team_id: number;
+            display_name: string;
+            members: components["schemas"]["User"][];
+        };
+        TestcaseResult: {
+            testcase_id: number;
+            is_sample: boolean;
+            passed: boolean;
+            status?: components["schemas"]["ExecutionStatus"];
+            stdin?: string;
+            expected_stdout?: string;
+            stdout?: string;
+            stderr?: string;
+        };
+        /** @enum {string} */
+        TieBreak: "earliest_submission" | "fewest_submissions" | "shared_rank";
         Tournament: {
             tournament_id: number;
             display_name: string;
@@ -277,6 +558,8 @@ export interface components {
             icon_path?: string;
             is_admin: boolean;
             label: string | null;
+            rating: number;
+            rating_rank: number | null;
         };
     };
     responses: never;
@@ -390,6 +673,8 @@ export interface operations {
         requestBody: {
             content: {
                 "application/json": {
+                    problem_id: number;
+                    language?: components["schemas"]["ProblemLanguage"];
                     code: string;
                 };
             };
@@ -402,6 +687,15 @@ export interface operations {
                 };
                 content?: never;
             };
+            /** @description The server could not understand the request due to invalid syntax. */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
             /** @description Access is unauthorized. */
             401: {
                 headers: {
@@ -431,7 +725,7 @@ export interface operations {
             };
         };
     };
-    getGamePlayLatestState: {
+    getGamePlayEvents: {
         parameters: {
             query?: never;
             header?: never;
@@ -448,9 +742,7 @@ export interface operations {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        state: components["schemas"]["LatestGameState"];
-                    };
+                    "text/event-stream": string;
                 };
             };
             /** @description Access is unauthorized. */
@@ -482,9 +774,11 @@ export interface operations {
             };
         };
     };
-    getGamePlaySubmissions: {
+    getGamePlayLatestState: {
         parameters: {
-            query?: never;
+            query: {
+                problem_id: number;
+            };
             header?: never;
             path: {
                 game_id: number;
@@ -500,7 +794,7 @@ export interface operations {
                 };
                 content: {
                     "application/json": {
-                        submissions: components["schemas"]["Submission"][];
+                        state: components["schemas"]["LatestGameState"];
                     };
                 };
             };
@@ -533,7 +827,7 @@ export interface operations {
             };
         };
     };
-    postGamePlaySubmit: {
+    postGamePlayRun: {
         parameters: {
             query?: never;
             header?: never;
@@ -545,7 +839,10 @@ export interface operations {
         requestBody: {
             content: {
                 "application/json": {
+                    problem_id: number;
+                    language?: components["schemas"]["ProblemLanguage"];
                     code: string;
+                    stdin: string;
                 };
             };
         };
@@ -555,7 +852,22 @@ export interface operations {
                 headers: {
                     [name: string]: unknown;
                 };
-                content?: never;
+                content: {
+                    "application/json": {
+                        status: components["schemas"]["ExecutionStatus"];
+                        stdout: string;
+                        stderr: string;
+                    };
+                };
+            };
+            /** @description The server could not understand the request due to invalid syntax. */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
             };
             /** @description Access is unauthorized. */
             401: {
@@ -586,7 +898,7 @@ export interface operations {
             };
         };
     };
-    getGameWatchLatestStates: {
+    getGamePlaySubmissions: {
         parameters: {
             query?: never;
             header?: never;
@@ -604,9 +916,7 @@ export interface operations {
                 };
                 content: {
                     "application/json": {
-                        states: {
-                            [key: string]: components["schemas"]["LatestGameState"];
-                        };
+                        submissions: components["schemas"]["Submission"][];
                     };
                 };
             };
@@ -639,12 +949,13 @@ export interface operations {
             };
         };
     };
-    getGameWatchRanking: {
+    getGamePlaySubmission: {
         parameters: {
             query?: never;
             header?: never;
             path: {
                 game_id: number;
+                submission_id: number;
             };
             cookie?: never;
         };
@@ -657,7 +968,8 @@ export interface operations {
                 };
                 content: {
                     "application/json": {
-                        ranking: components["schemas"]["RankingEntry"][];
+                        submission: components["schemas"]["Submission"];
+                        testcase_results: components["schemas"]["TestcaseResult"][];
                     };
                 };
             };
@@ -690,31 +1002,39 @@ export interface operations {
             };
         };
     };
-    postLogin: {
+    postGamePlaySubmit: {
         parameters: {
             query?: never;
             header?: never;
-            path?: never;
+            path: {
+                game_id: number;
+            };
             cookie?: never;
         };
         requestBody: {
             content: {
                 "application/json": {
-                    username: string;
-                    password: string;
+                    problem_id: number;
+                    language?: components["schemas"]["ProblemLanguage"];
+                    code: string;
                 };
             };
         };
         responses: {
             /** @description The request has succeeded. */
             200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content?: never;
+            };
+            /** @description The server could not understand the request due to invalid syntax. */
+            400: {
                 headers: {
                     [name: string]: unknown;
                 };
                 content: {
-                    "application/json": {
-                        user: components["schemas"]["User"];
-                    };
+                    "application/json": components["schemas"]["Error"];
                 };
             };
             /** @description Access is unauthorized. */
@@ -726,13 +1046,402 @@ export interface operations {
                     "application/json": components["schemas"]["Error"];
                 };
             };
-        };
-    };
-    postLogout: {
-        parameters: {
-            query?: never;
-            header?: never;
-            path?: never;
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameSubmissionDiff: {
+        parameters: {
+            query: {
+                from: number;
+                to: number;
+            };
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        unified: string;
+                        lines: components["schemas"]["DiffLine"][];
+                        size_delta: number;
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameWatchEvents: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "text/event-stream": string;
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameWatchLatestStates: {
+        parameters: {
+            query: {
+                problem_id: number;
+            };
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        states: {
+                            [key: string]: components["schemas"]["LatestGameState"];
+                        };
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameWatchRanking: {
+        parameters: {
+            query?: {
+                cursor?: string;
+                limit?: number;
+                practice?: boolean;
+                language?: components["schemas"]["ProblemLanguage"];
+            };
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        ranking: components["schemas"]["RankingEntry"][];
+                        is_frozen: boolean;
+                        tie_break: components["schemas"]["TieBreak"];
+                        next_cursor: string | null;
+                    };
+                };
+            };
+            /** @description The server could not understand the request due to invalid syntax. */
+            400: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameWatchReplay: {
+        parameters: {
+            query: {
+                user_id: number;
+                problem_id: number;
+            };
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        snapshots: components["schemas"]["CodeSnapshot"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getGameWatchTeams: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                game_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        teams: components["schemas"]["Team"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    postLogin: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody: {
+            content: {
+                "application/json": {
+                    username: string;
+                    password: string;
+                };
+            };
+        };
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        user: components["schemas"]["User"];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    postLogout: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
             cookie?: never;
         };
         requestBody?: never;
@@ -786,6 +1495,138 @@ export interface operations {
             };
         };
     };
+    getQualifyingStages: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        stages: components["schemas"]["QualifyingStage"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getQualifyingStageRanking: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                stage_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        stage: components["schemas"]["QualifyingStage"];
+                        ranking: components["schemas"]["QualifyingEntry"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
+    getRatings: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path?: never;
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        users: components["schemas"]["User"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
     getTournament: {
         parameters: {
             query?: never;
@@ -837,4 +1678,56 @@ export interface operations {
             };
         };
     };
+    getUserRatingHistory: {
+        parameters: {
+            query?: never;
+            header?: never;
+            path: {
+                user_id: number;
+            };
+            cookie?: never;
+        };
+        requestBody?: never;
+        responses: {
+            /** @description The request has succeeded. */
+            200: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": {
+                        user: components["schemas"]["User"];
+                        history: components["schemas"]["RatingChange"][];
+                    };
+                };
+            };
+            /** @description Access is unauthorized. */
+            401: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description Access is forbidden. */
+            403: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+            /** @description The server cannot find the requested resource. */
+            404: {
+                headers: {
+                    [name: string]: unknown;
+                };
+                content: {
+                    "application/json": components["schemas"]["Error"];
+                };
+            };
+        };
+    };
 }
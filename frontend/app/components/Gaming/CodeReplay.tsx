@@ -0,0 +1,89 @@
+import { useContext, useEffect, useState } from "react";
+import { ApiClientContext } from "../../api/client";
+import type { components } from "../../api/schema";
+import type { SupportedLanguage } from "../../types/SupportedLanguage";
+import CodeBlock from "./CodeBlock";
+
+type CodeSnapshot = components["schemas"]["CodeSnapshot"];
+
+function formatUnixTime(timestamp: number) {
+	const date = new Date(timestamp * 1000);
+
+	const hours = date.getHours().toString().padStart(2, "0");
+	const minutes = date.getMinutes().toString().padStart(2, "0");
+	const seconds = date.getSeconds().toString().padStart(2, "0");
+
+	return `${hours}:${minutes}:${seconds}`;
+}
+
+type Props = {
+	gameId: number;
+	userId: number;
+	problemId: number;
+	language: SupportedLanguage;
+};
+
+export default function CodeReplay({
+	gameId,
+	userId,
+	problemId,
+	language,
+}: Props) {
+	const apiClient = useContext(ApiClientContext)!;
+	const [snapshots, setSnapshots] = useState<CodeSnapshot[] | null>(null);
+	const [index, setIndex] = useState(0);
+
+	useEffect(() => {
+		let cancelled = false;
+		(async () => {
+			try {
+				const { snapshots } = await apiClient.getGameWatchReplay(
+					gameId,
+					userId,
+					problemId,
+				);
+				if (!cancelled) {
+					setSnapshots(snapshots);
+					// Start from the final code, like the end of the game.
+					setIndex(Math.max(0, snapshots.length - 1));
+				}
+			} catch (error) {
+				console.error(error);
+			}
+		})();
+		return () => {
+			cancelled = true;
+		};
+	}, [apiClient, gameId, userId, problemId]);
+
+	if (snapshots === null) {
+		return <p className="text-gray-500">読み込み中...</p>;
+	}
+	const snapshot = snapshots[index];
+	if (!snapshot) {
+		return <p className="text-gray-500">履歴がありません</p>;
+	}
+
+	return (
+		<div className="flex flex-col gap-2">
+			<input
+				type="range"
+				min={0}
+				max={snapshots.length - 1}
+				value={index}
+				onChange={(e) => setIndex(Number(e.target.value))}
+				aria-label="リプレイ位置"
+			/>
+			<div className="flex justify-between text-sm text-gray-600">
+				<span>
+					{index + 1} / {snapshots.length}
+				</span>
+				<span>
+					{formatUnixTime(snapshot.created_at)}
+					{snapshot.is_submission && " (提出)"}
+				</span>
+			</div>
+			<CodeBlock code={snapshot.code} language={language} />
+		</div>
+	);
+}
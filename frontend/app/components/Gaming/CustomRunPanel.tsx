@@ -0,0 +1,72 @@
+import { useState } from "react";
+import type { components } from "../../api/schema";
+import SubmitButton from "../SubmitButton";
+import SubmitStatusLabel from "../SubmitStatusLabel";
+
+export type RunResult = {
+	status: components["schemas"]["ExecutionStatus"];
+	stdout: string;
+	stderr: string;
+};
+
+type Props = {
+	onRun: (stdin: string) => Promise<RunResult>;
+	disabled: boolean;
+};
+
+export default function CustomRunPanel({ onRun, disabled }: Props) {
+	const [stdin, setStdin] = useState("");
+	const [result, setResult] = useState<RunResult | null>(null);
+	const [error, setError] = useState<string | null>(null);
+	const [isRunning, setIsRunning] = useState(false);
+
+	const handleRunButtonClick = async () => {
+		setIsRunning(true);
+		setError(null);
+		try {
+			setResult(await onRun(stdin));
+		} catch (e) {
+			setResult(null);
+			setError(e instanceof Error ? e.message : "実行に失敗しました");
+		} finally {
+			setIsRunning(false);
+		}
+	};
+
+	return (
+		<div className="flex flex-col gap-2">
+			<div className="flex flex-row gap-2 items-center">
+				<div className="grow font-semibold text-lg">カスタム入力で実行</div>
+				<SubmitButton
+					onClick={handleRunButtonClick}
+					disabled={disabled || isRunning}
+				>
+					{isRunning ? "実行中..." : "実行"}
+				</SubmitButton>
+			</div>
+			<textarea
+				value={stdin}
+				onChange={(e) => setStdin(e.target.value)}
+				placeholder="標準入力"
+				className="resize-y w-full p-2 bg-gray-50 rounded-lg border border-gray-300 focus:outline-hidden focus:ring-2 focus:ring-gray-400 transition duration-300"
+				rows={4}
+			/>
+			{error && <p className="text-red-600">{error}</p>}
+			{result && (
+				<div className="flex flex-col gap-2 text-sm">
+					<p className="font-semibold">
+						<SubmitStatusLabel status={result.status} />
+					</p>
+					<p className="text-gray-500">標準出力</p>
+					<pre className="p-2 bg-gray-800 text-gray-100 rounded overflow-x-auto">
+						{result.stdout}
+					</pre>
+					<p className="text-gray-500">標準エラー出力</p>
+					<pre className="p-2 bg-gray-800 text-gray-100 rounded overflow-x-auto">
+						{result.stderr}
+					</pre>
+				</div>
+			)}
+		</div>
+	);
+}
@@ -0,0 +1,47 @@
+import { useContext, useEffect, useState } from "react";
+import { ApiClientContext } from "../../api/client";
+import type { components } from "../../api/schema";
+import type { SupportedLanguage } from "../../types/SupportedLanguage";
+import RankingTable from "./RankingTable";
+
+type RankingEntry = components["schemas"]["RankingEntry"];
+
+type Props = {
+	gameId: number;
+	problemLanguage: SupportedLanguage;
+};
+
+// The ranking of the practice submissions made after the game finished, kept
+// apart from the official results.
+export default function PracticeRanking({ gameId, problemLanguage }: Props) {
+	const apiClient = useContext(ApiClientContext)!;
+	const [ranking, setRanking] = useState<RankingEntry[] | null>(null);
+
+	useEffect(() => {
+		let cancelled = false;
+		(async () => {
+			try {
+				const { ranking } = await apiClient.getGameWatchFullRanking(
+					gameId,
+					true,
+				);
+				if (!cancelled) {
+					setRanking(ranking);
+				}
+			} catch (error) {
+				console.error(error);
+			}
+		})();
+		return () => {
+			cancelled = true;
+		};
+	}, [apiClient, gameId]);
+
+	if (ranking === null) {
+		return <p className="text-gray-500">読み込み中...</p>;
+	}
+	if (ranking.length === 0) {
+		return <p className="text-gray-500">練習の提出はまだありません</p>;
+	}
+	return <RankingTable problemLanguage={problemLanguage} entries={ranking} />;
+}
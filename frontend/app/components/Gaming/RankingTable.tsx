@@ -1,6 +1,7 @@
 import { useAtomValue } from "jotai";
 import React from "react";
-import { rankingAtom } from "../../states/watch";
+import type { components } from "../../api/schema";
+import { rankingAtom, rankingFrozenAtom } from "../../states/watch";
 import type { SupportedLanguage } from "../../types/SupportedLanguage";
 import CodePopover from "./CodePopover";
 
@@ -30,34 +31,76 @@ function formatUnixTimestamp(timestamp: number) {
 	return `${year}-${month}-${day} ${hours}:${minutes}`;
 }
 
+type RankingEntry = components["schemas"]["RankingEntry"];
+
 type Props = {
 	problemLanguage: SupportedLanguage;
+	// Shown instead of the official ranking of the game being watched.
+	entries?: RankingEntry[];
 };
 
-export default function RankingTable({ problemLanguage }: Props) {
-	const ranking = useAtomValue(rankingAtom);
+export default function RankingTable({ problemLanguage, entries }: Props) {
+	const officialRanking = useAtomValue(rankingAtom);
+	const officialFrozen = useAtomValue(rankingFrozenAtom);
+	const ranking = entries ?? officialRanking;
+	const isFrozen = entries === undefined && officialFrozen;
+	// The scores of the individual problems are only shown for games with more
+	// than one problem. They are in the same order as the problems of the game.
+	const problemCount = ranking[0]?.problem_scores.length ?? 0;
+	const problemLabels =
+		problemCount > 1
+			? Array.from({ length: problemCount }, (_, i) =>
+					String.fromCharCode(65 + i),
+				)
+			: [];
 
 	return (
 		<div className="overflow-x-auto border-2 border-blue-600 rounded-xl">
+			{isFrozen && (
+				<p className="px-6 py-2 bg-blue-50 text-blue-800 font-medium">
+					ランキング凍結中
+				</p>
+			)}
 			<table className="min-w-full divide-y divide-gray-400 border-collapse">
 				<thead className="bg-gray-50">
 					<tr>
 						<TableHeaderCell>順位</TableHeaderCell>
-						<TableHeaderCell>プレイヤー</TableHeaderCell>
+						<TableHeaderCell>チーム</TableHeaderCell>
 						<TableHeaderCell>スコア</TableHeaderCell>
+						{problemLabels.map((label) => (
+							<TableHeaderCell key={label}>{label}</TableHeaderCell>
+						))}
 						<TableHeaderCell>提出時刻</TableHeaderCell>
 						<TableHeaderCell>コード</TableHeaderCell>
 					</tr>
 				</thead>
 				<tbody className="bg-white divide-y divide-gray-300">
-					{ranking.map((entry, index) => (
-						<tr key={entry.player.user_id}>
-							<TableBodyCell>{index + 1}</TableBodyCell>
+					{ranking.map((entry) => (
+						<tr key={entry.team.team_id}>
+							<TableBodyCell>{entry.rank}</TableBodyCell>
 							<TableBodyCell>
-								{entry.player.display_name}
-								{entry.player.label && ` (${entry.player.label})`}
+								{entry.team.display_name}
+								{entry.team.members.length === 1 &&
+									entry.team.members[0]?.label &&
+									` (${entry.team.members[0].label})`}
+								{entry.team.members.length > 1 && (
+									<div className="text-sm text-gray-600">
+										{entry.team.members
+											.map((member) =>
+												member.label
+													? `${member.display_name} (${member.label})`
+													: member.display_name,
+											)
+											.join(", ")}
+									</div>
+								)}
 							</TableBodyCell>
 							<TableBodyCell>{entry.score}</TableBodyCell>
+							{problemLabels.map((label, i) => (
+								<TableBodyCell key={label}>
+									{entry.problem_scores[i]?.score ?? "-"}
+								</TableBodyCell>
+							))}
 							<TableBodyCell>
 								{formatUnixTimestamp(entry.submitted_at)}
 							</TableBodyCell>
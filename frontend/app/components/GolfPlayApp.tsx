@@ -6,47 +6,66 @@ import { useDebouncedCallback } from "use-debounce";
 import { ApiClientContext } from "../api/client";
 import type { components } from "../api/schema";
 import {
+	applyGameEventAtom,
 	gameStateKindAtom,
 	handleSubmitCodePostAtom,
 	handleSubmitCodePreAtom,
 	setCurrentTimestampAtom,
-	setDurationSecondsAtom,
-	setGameStartedAtAtom,
+	setGameAtom,
 	setLatestGameStateAtom,
 } from "../states/play";
+import type { SupportedLanguage } from "../types/SupportedLanguage";
+import GolfPlayAppCancelled from "./GolfPlayApps/GolfPlayAppCancelled";
 import GolfPlayAppGaming from "./GolfPlayApps/GolfPlayAppGaming";
 import GolfPlayAppLoading from "./GolfPlayApps/GolfPlayAppLoading";
 import GolfPlayAppStarting from "./GolfPlayApps/GolfPlayAppStarting";
 import GolfPlayAppWaiting from "./GolfPlayApps/GolfPlayAppWaiting";
 
 type Game = components["schemas"]["Game"];
+type Problem = components["schemas"]["Problem"];
 type User = components["schemas"]["User"];
 type LatestGameState = components["schemas"]["LatestGameState"];
 
 type Props = {
 	game: Game;
+	problem: Problem;
 	player: User;
 	initialGameState: LatestGameState;
+	onProblemSelect: (problemId: number) => void;
 };
 
-export default function GolfPlayApp({ game, player, initialGameState }: Props) {
+export default function GolfPlayApp({
+	game,
+	problem,
+	player,
+	initialGameState,
+	onProblemSelect,
+}: Props) {
 	useHydrateAtoms([
-		[setDurationSecondsAtom, game.duration_seconds],
-		[setGameStartedAtAtom, game.started_at ?? null],
+		[setGameAtom, game],
 		[setLatestGameStateAtom, initialGameState],
 	]);
 
 	const apiClient = useContext(ApiClientContext)!;
 
 	const gameStateKind = useAtomValue(gameStateKindAtom);
-	const setGameStartedAt = useSetAtom(setGameStartedAtAtom);
+	const setGame = useSetAtom(setGameAtom);
 	const setCurrentTimestamp = useSetAtom(setCurrentTimestampAtom);
 	const handleSubmitCodePre = useSetAtom(handleSubmitCodePreAtom);
 	const handleSubmitCodePost = useSetAtom(handleSubmitCodePostAtom);
 	const setLatestGameState = useSetAtom(setLatestGameStateAtom);
+	const applyGameEvent = useSetAtom(applyGameEventAtom);
 
 	useTimer({ delay: 1000, startImmediately: true }, setCurrentTimestamp);
 
+	// Players keep the language they last saved code in.
+	const [language, setLanguage] = useState<SupportedLanguage>(
+		initialGameState.language ?? problem.language,
+	);
+	const sampleCode =
+		problem.languages.find((l) => l.language === language)?.sample_code ??
+		problem.sample_code;
+
 	const playerProfile = {
 		id: player.user_id,
 		displayName: player.display_name,
@@ -56,7 +75,12 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 	const onCodeChange = useDebouncedCallback(async (code: string) => {
 		if (game.game_type === "1v1") {
 			console.log("player:c2s:code");
-			await apiClient.postGamePlayCode(game.game_id, code);
+			await apiClient.postGamePlayCode(
+				game.game_id,
+				problem.problem_id,
+				language,
+				code,
+			);
 		}
 	}, 1000);
 
@@ -67,7 +91,12 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 			}
 			console.log("player:c2s:submit");
 			handleSubmitCodePre();
-			await apiClient.postGamePlaySubmit(game.game_id, code);
+			await apiClient.postGamePlaySubmit(
+				game.game_id,
+				problem.problem_id,
+				language,
+				code,
+			);
 			await new Promise((resolve) => setTimeout(resolve, 1000));
 			handleSubmitCodePost();
 		},
@@ -75,10 +104,31 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 		{ leading: true },
 	);
 
+	const onCodeRun = async (code: string, stdin: string) => {
+		console.log("player:c2s:run");
+		return await apiClient.postGamePlayRun(
+			game.game_id,
+			problem.problem_id,
+			language,
+			code,
+			stdin,
+		);
+	};
+
+	const handleProblemSelect = (problemId: number) => {
+		// Save the code being edited before leaving the problem.
+		onCodeChange.flush();
+		onProblemSelect(problemId);
+	};
+
 	const [isDataPolling, setIsDataPolling] = useState(false);
 
 	useEffect(() => {
-		if (isDataPolling) {
+		// Poll until the game starts, as it may be rescheduled or cancelled.
+		if (
+			isDataPolling ||
+			(gameStateKind !== "waiting" && gameStateKind !== "starting")
+		) {
 			return;
 		}
 		const timerId = setInterval(async () => {
@@ -88,17 +138,8 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 			setIsDataPolling(true);
 
 			try {
-				if (gameStateKind === "waiting") {
-					const { game: g } = await apiClient.getGame(game.game_id);
-					if (g.started_at != null) {
-						setGameStartedAt(g.started_at);
-					}
-				} else if (gameStateKind === "gaming") {
-					const { state } = await apiClient.getGamePlayLatestState(
-						game.game_id,
-					);
-					setLatestGameState(state);
-				}
+				const { game: g } = await apiClient.getGame(game.game_id);
+				setGame(g);
 			} catch (error) {
 				console.error(error);
 			} finally {
@@ -109,12 +150,54 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 		return () => {
 			clearInterval(timerId);
 		};
+	}, [isDataPolling, apiClient, game.game_id, gameStateKind, setGame]);
+
+	const isLive = gameStateKind === "gaming" || gameStateKind === "paused";
+
+	useEffect(() => {
+		if (!isLive) {
+			return;
+		}
+
+		const unsubscribe = apiClient.subscribeGamePlayEvents(
+			game.game_id,
+			async (event) => {
+				if (event.type !== "game") {
+					if (event.problem_id === problem.problem_id) {
+						applyGameEvent(event);
+					}
+					return;
+				}
+				try {
+					const { game: g } = await apiClient.getGame(game.game_id);
+					setGame(g);
+				} catch (error) {
+					console.error(error);
+				}
+			},
+		);
+
+		// Catch up with the changes made before the subscription started.
+		(async () => {
+			try {
+				const { state } = await apiClient.getGamePlayLatestState(
+					game.game_id,
+					problem.problem_id,
+				);
+				setLatestGameState(state);
+			} catch (error) {
+				console.error(error);
+			}
+		})();
+
+		return unsubscribe;
 	}, [
-		isDataPolling,
+		isLive,
 		apiClient,
 		game.game_id,
-		gameStateKind,
-		setGameStartedAt,
+		problem.problem_id,
+		applyGameEvent,
+		setGame,
 		setLatestGameState,
 	]);
 
@@ -129,19 +212,29 @@ export default function GolfPlayApp({ game, player, initialGameState }: Props) {
 		);
 	} else if (gameStateKind === "starting") {
 		return <GolfPlayAppStarting gameDisplayName={game.display_name} />;
-	} else if (gameStateKind === "gaming" || gameStateKind === "finished") {
+	} else if (gameStateKind === "cancelled") {
+		return <GolfPlayAppCancelled gameDisplayName={game.display_name} />;
+	} else {
 		return (
 			<GolfPlayAppGaming
 				gameDisplayName={game.display_name}
 				playerProfile={playerProfile}
-				problemTitle={game.problem.title}
-				problemDescription={game.problem.description}
-				problemLanguage={game.problem.language}
-				sampleCode={game.problem.sample_code}
+				problems={game.problems}
+				problemId={problem.problem_id}
+				onProblemSelect={handleProblemSelect}
+				problemTitle={problem.title}
+				problemDescription={problem.description}
+				languages={problem.languages.map((l) => l.language)}
+				language={language}
+				onLanguageChange={setLanguage}
+				sampleCode={sampleCode}
 				initialCode={initialGameState.code}
 				onCodeChange={onCodeChange}
 				onCodeSubmit={onCodeSubmit}
+				onCodeRun={onCodeRun}
 				isFinished={gameStateKind === "finished"}
+				isPaused={gameStateKind === "paused"}
+				isPractice={gameStateKind === "finished" && game.allow_practice}
 			/>
 		);
 	}
@@ -0,0 +1,16 @@
+type Props = {
+	gameDisplayName: string;
+};
+
+export default function GolfPlayAppCancelled({ gameDisplayName }: Props) {
+	return (
+		<div className="min-h-screen bg-gray-100 flex flex-col font-bold text-center">
+			<div className="text-white bg-sky-600 p-10">
+				<div className="text-4xl">{gameDisplayName}</div>
+			</div>
+			<div className="grow grid place-content-center text-black text-4xl">
+				中止
+			</div>
+		</div>
+	);
+}
@@ -1,6 +1,7 @@
 import { useAtomValue } from "jotai";
 import React, { useRef, useState } from "react";
 import { Link } from "wouter";
+import type { components } from "../../api/schema";
 import {
 	calcCodeSize,
 	gamingLeftTimeSecondsAtom,
@@ -10,6 +11,7 @@ import {
 import type { PlayerProfile } from "../../types/PlayerProfile";
 import type { SupportedLanguage } from "../../types/SupportedLanguage";
 import BorderedContainer from "../BorderedContainer";
+import CustomRunPanel, { type RunResult } from "../Gaming/CustomRunPanel";
 import LeftTime from "../Gaming/LeftTime";
 import ProblemColumn from "../Gaming/ProblemColumn";
 import SubmitButton from "../SubmitButton";
@@ -18,60 +20,96 @@ import ThreeColumnLayout from "../ThreeColumnLayout";
 import TitledColumn from "../TitledColumn";
 import UserIcon from "../UserIcon";
 
+type Problem = components["schemas"]["Problem"];
+
 type Props = {
 	gameDisplayName: string;
 	playerProfile: PlayerProfile;
+	problems: Problem[];
+	problemId: number;
+	onProblemSelect: (problemId: number) => void;
 	problemTitle: string;
 	problemDescription: string;
-	problemLanguage: SupportedLanguage;
+	// The languages the player can choose, the one of the problem first.
+	languages: SupportedLanguage[];
+	language: SupportedLanguage;
+	onLanguageChange: (language: SupportedLanguage) => void;
 	sampleCode: string;
 	initialCode: string;
 	onCodeChange: (code: string) => void;
 	onCodeSubmit: (code: string) => void;
+	onCodeRun: (code: string, stdin: string) => Promise<RunResult>;
 	isFinished: boolean;
+	isPaused: boolean;
+	// Finished games that allow practice still take submissions, which are
+	// ranked apart from the official results.
+	isPractice: boolean;
 };
 
 export default function GolfPlayAppGaming({
 	gameDisplayName,
 	playerProfile,
+	problems,
+	problemId,
+	onProblemSelect,
 	problemTitle,
 	problemDescription,
-	problemLanguage,
+	languages,
+	language,
+	onLanguageChange,
 	sampleCode,
 	initialCode,
 	onCodeChange,
 	onCodeSubmit,
+	onCodeRun,
 	isFinished,
+	isPaused,
+	isPractice,
 }: Props) {
 	const leftTimeSeconds = useAtomValue(gamingLeftTimeSecondsAtom)!;
 	const score = useAtomValue(scoreAtom);
 	const status = useAtomValue(statusAtom);
 
+	const canSubmit = !isPaused && (!isFinished || isPractice);
+
 	const [codeSize, setCodeSize] = useState(
-		calcCodeSize(initialCode, problemLanguage),
+		calcCodeSize(initialCode, language),
 	);
 	const textareaRef = useRef<HTMLTextAreaElement>(null);
 
 	const handleTextChange = (e: React.ChangeEvent<HTMLTextAreaElement>) => {
-		setCodeSize(calcCodeSize(e.target.value, problemLanguage));
-		if (!isFinished) {
+		setCodeSize(calcCodeSize(e.target.value, language));
+		if (!isFinished && !isPaused) {
 			onCodeChange(e.target.value);
 		}
 	};
 
+	const handleLanguageChange = (e: React.ChangeEvent<HTMLSelectElement>) => {
+		const next = e.target.value as SupportedLanguage;
+		setCodeSize(calcCodeSize(textareaRef.current?.value ?? "", next));
+		onLanguageChange(next);
+	};
+
 	const handleSubmitButtonClick = () => {
-		if (textareaRef.current && !isFinished) {
+		if (textareaRef.current && canSubmit) {
 			onCodeSubmit(textareaRef.current.value);
 		}
 	};
 
+	const handleRun = (stdin: string) =>
+		onCodeRun(textareaRef.current?.value ?? "", stdin);
+
 	return (
 		<div className="min-h-screen bg-gray-100 flex flex-col">
 			<div className="text-white bg-sky-600 flex flex-row justify-between px-4 py-2">
 				<div className="font-bold">
 					<div className="text-gray-100">{gameDisplayName}</div>
 					{isFinished ? (
-						<div className="text-2xl md:text-3xl">終了</div>
+						<div className="text-2xl md:text-3xl">
+							{isPractice ? "終了 (練習中)" : "終了"}
+						</div>
+					) : isPaused ? (
+						<div className="text-2xl md:text-3xl">一時停止中</div>
 					) : (
 						<LeftTime sec={leftTimeSeconds} />
 					)}
@@ -92,11 +130,26 @@ export default function GolfPlayAppGaming({
 					</div>
 				</Link>
 			</div>
+			{problems.length > 1 && (
+				<div className="flex flex-row gap-2 px-4 pt-4">
+					{problems.map((p, i) => (
+						<button
+							key={p.problem_id}
+							type="button"
+							onClick={() => onProblemSelect(p.problem_id)}
+							disabled={p.problem_id === problemId}
+							className="px-4 py-2 rounded-lg font-bold border-2 border-sky-600 text-sky-600 bg-white hover:bg-sky-50 disabled:bg-sky-600 disabled:text-white"
+						>
+							{String.fromCharCode(65 + i)}. {p.title}
+						</button>
+					))}
+				</div>
+			)}
 			<ThreeColumnLayout>
 				<ProblemColumn
 					title={problemTitle}
 					description={problemDescription}
-					language={problemLanguage}
+					language={language}
 					sampleCode={sampleCode}
 				/>
 				<TitledColumn title="ソースコード">
@@ -105,9 +158,23 @@ export default function GolfPlayAppGaming({
 							<div className="grow font-semibold text-lg">
 								コードサイズ: {codeSize}
 							</div>
+							{languages.length > 1 && (
+								<select
+									value={language}
+									onChange={handleLanguageChange}
+									disabled={!canSubmit}
+									className="px-2 py-1 rounded-lg border border-gray-300 bg-white"
+								>
+									{languages.map((l) => (
+										<option key={l} value={l}>
+											{l}
+										</option>
+									))}
+								</select>
+							)}
 							<SubmitButton
 								onClick={handleSubmitButtonClick}
-								disabled={isFinished}
+								disabled={!canSubmit}
 							>
 								提出
 							</SubmitButton>
@@ -119,6 +186,10 @@ export default function GolfPlayAppGaming({
 							className="grow resize-none h-full w-full p-2 bg-gray-50 rounded-lg border border-gray-300 focus:outline-hidden focus:ring-2 focus:ring-gray-400 transition duration-300"
 							rows={10}
 						/>
+						<CustomRunPanel
+							onRun={handleRun}
+							disabled={!canSubmit}
+						/>
 					</BorderedContainer>
 				</TitledColumn>
 				<TitledColumn title="提出結果">
@@ -145,6 +216,11 @@ export default function GolfPlayAppGaming({
 							</tbody>
 						</table>
 					</div>
+					{isPractice && (
+						<p>
+							練習の提出は公式の順位には反映されません。結果は提出履歴から確認できます。
+						</p>
+					)}
 					<p>
 						NOTE:
 						過去の提出結果を閲覧する機能は現在実装中です。それまでは提出コードをお手元に保管しておいてください。
@@ -5,13 +5,15 @@ import { useTimer } from "react-use-precision-timer";
 import { ApiClientContext } from "../api/client";
 import type { components } from "../api/schema";
 import {
+	applyGameEventAtom,
 	gameStateKindAtom,
 	rankingAtom,
+	rankingFrozenAtom,
 	setCurrentTimestampAtom,
-	setDurationSecondsAtom,
-	setGameStartedAtAtom,
+	setGameAtom,
 	setLatestGameStatesAtom,
 } from "../states/watch";
+import GolfWatchAppCancelled from "./GolfWatchApps/GolfWatchAppCancelled";
 import GolfWatchAppGaming1v1 from "./GolfWatchApps/GolfWatchAppGaming1v1";
 import GolfWatchAppGamingMultiplayer from "./GolfWatchApps/GolfWatchAppGamingMultiplayer";
 import GolfWatchAppLoading from "./GolfWatchApps/GolfWatchAppLoading";
@@ -27,30 +29,37 @@ export type Props = {
 	game: Game;
 	initialGameStates: { [key: string]: LatestGameState };
 	initialRanking: RankingEntry[];
+	initialRankingFrozen: boolean;
 };
 
 export default function GolfWatchApp({
 	game,
 	initialGameStates,
 	initialRanking,
+	initialRankingFrozen,
 }: Props) {
 	useHydrateAtoms([
 		[rankingAtom, initialRanking],
-		[setDurationSecondsAtom, game.duration_seconds],
-		[setGameStartedAtAtom, game.started_at ?? null],
+		[rankingFrozenAtom, initialRankingFrozen],
+		[setGameAtom, game],
 		[setLatestGameStatesAtom, initialGameStates],
 	]);
 
 	const apiClient = useContext(ApiClientContext)!;
 
 	const gameStateKind = useAtomValue(gameStateKindAtom);
-	const setGameStartedAt = useSetAtom(setGameStartedAtAtom);
+	const setGame = useSetAtom(setGameAtom);
 	const setCurrentTimestamp = useSetAtom(setCurrentTimestampAtom);
 	const setLatestGameStates = useSetAtom(setLatestGameStatesAtom);
 	const setRanking = useSetAtom(rankingAtom);
+	const setRankingFrozen = useSetAtom(rankingFrozenAtom);
+	const applyGameEvent = useSetAtom(applyGameEventAtom);
 
 	useTimer({ delay: 1000, startImmediately: true }, setCurrentTimestamp);
 
+	// The states of the players are shown for the first problem.
+	const problem = game.problems[0];
+
 	const playerA = game.main_players[0];
 	const playerB = game.main_players[1];
 
@@ -72,7 +81,11 @@ export default function GolfWatchApp({
 	const [isDataPolling, setIsDataPolling] = useState(false);
 
 	useEffect(() => {
-		if (isDataPolling) {
+		// Poll until the game starts, as it may be rescheduled or cancelled.
+		if (
+			isDataPolling ||
+			(gameStateKind !== "waiting" && gameStateKind !== "starting")
+		) {
 			return;
 		}
 		const timerId = setInterval(async () => {
@@ -82,19 +95,8 @@ export default function GolfWatchApp({
 			setIsDataPolling(true);
 
 			try {
-				if (gameStateKind === "waiting") {
-					const { game: g } = await apiClient.getGame(game.game_id);
-					if (g.started_at != null) {
-						setGameStartedAt(g.started_at);
-					}
-				} else if (gameStateKind === "gaming") {
-					const { states } = await apiClient.getGameWatchLatestStates(
-						game.game_id,
-					);
-					setLatestGameStates(states);
-					const { ranking } = await apiClient.getGameWatchRanking(game.game_id);
-					setRanking(ranking);
-				}
+				const { game: g } = await apiClient.getGame(game.game_id);
+				setGame(g);
 			} catch (error) {
 				console.error(error);
 			} finally {
@@ -105,14 +107,82 @@ export default function GolfWatchApp({
 		return () => {
 			clearInterval(timerId);
 		};
+	}, [isDataPolling, apiClient, game.game_id, gameStateKind, setGame]);
+
+	const isLive = gameStateKind === "gaming" || gameStateKind === "paused";
+
+	useEffect(() => {
+		if (!isLive) {
+			return;
+		}
+
+		const refreshGame = async () => {
+			try {
+				const { game: g } = await apiClient.getGame(game.game_id);
+				setGame(g);
+			} catch (error) {
+				console.error(error);
+			}
+		};
+
+		const refreshRanking = async () => {
+			try {
+				const { ranking, is_frozen } =
+					await apiClient.getGameWatchFullRanking(game.game_id);
+				setRanking(ranking);
+				setRankingFrozen(is_frozen);
+			} catch (error) {
+				console.error(error);
+			}
+		};
+
+		const unsubscribe = apiClient.subscribeGameWatchEvents(
+			game.game_id,
+			(event) => {
+				if (
+					event.type !== "game" &&
+					event.problem_id === problem?.problem_id
+				) {
+					applyGameEvent(event);
+				}
+				if (event.type === "best_score") {
+					refreshRanking();
+				} else if (event.type === "game") {
+					// Revealing a frozen ranking is also notified as a game event.
+					refreshGame();
+					refreshRanking();
+				}
+			},
+		);
+
+		// Catch up with the changes made before the subscription started.
+		(async () => {
+			if (!problem) {
+				return;
+			}
+			try {
+				const { states } = await apiClient.getGameWatchLatestStates(
+					game.game_id,
+					problem.problem_id,
+				);
+				setLatestGameStates(states);
+			} catch (error) {
+				console.error(error);
+			}
+		})();
+		refreshRanking();
+
+		return unsubscribe;
 	}, [
-		isDataPolling,
+		isLive,
 		apiClient,
 		game.game_id,
-		gameStateKind,
-		setGameStartedAt,
+		problem,
+		applyGameEvent,
+		setGame,
 		setLatestGameStates,
 		setRanking,
+		setRankingFrozen,
 	]);
 
 	if (gameStateKind === "loading") {
@@ -129,24 +199,34 @@ export default function GolfWatchApp({
 		);
 	} else if (gameStateKind === "starting") {
 		return <GolfWatchAppStarting gameDisplayName={game.display_name} />;
-	} else if (gameStateKind === "gaming" || gameStateKind === "finished") {
+	} else if (gameStateKind === "cancelled") {
+		return <GolfWatchAppCancelled gameDisplayName={game.display_name} />;
+	} else if (!problem) {
+		// Games cannot start without problems.
+		return <GolfWatchAppLoading />;
+	} else {
 		return game.game_type === "1v1" ? (
 			<GolfWatchAppGaming1v1
+				gameId={game.game_id}
 				gameDisplayName={game.display_name}
+				allowPractice={game.allow_practice}
 				playerProfileA={playerProfileA}
 				playerProfileB={playerProfileB}
-				problemTitle={game.problem.title}
-				problemDescription={game.problem.description}
-				problemLanguage={game.problem.language}
-				sampleCode={game.problem.sample_code}
+				problemId={problem.problem_id}
+				problemTitle={problem.title}
+				problemDescription={problem.description}
+				problemLanguage={problem.language}
+				sampleCode={problem.sample_code}
 			/>
 		) : (
 			<GolfWatchAppGamingMultiplayer
+				gameId={game.game_id}
 				gameDisplayName={game.display_name}
-				problemTitle={game.problem.title}
-				problemDescription={game.problem.description}
-				problemLanguage={game.problem.language}
-				sampleCode={game.problem.sample_code}
+				allowPractice={game.allow_practice}
+				problemTitle={problem.title}
+				problemDescription={problem.description}
+				problemLanguage={problem.language}
+				sampleCode={problem.sample_code}
 			/>
 		);
 	}
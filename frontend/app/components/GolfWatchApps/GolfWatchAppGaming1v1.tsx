@@ -10,7 +10,9 @@ import type { PlayerProfile } from "../../types/PlayerProfile";
 import type { SupportedLanguage } from "../../types/SupportedLanguage";
 import FoldableBorderedContainerWithCaption from "../FoldableBorderedContainerWithCaption";
 import CodeBlock from "../Gaming/CodeBlock";
+import CodeReplay from "../Gaming/CodeReplay";
 import LeftTime from "../Gaming/LeftTime";
+import PracticeRanking from "../Gaming/PracticeRanking";
 import ProblemColumnContent from "../Gaming/ProblemColumnContent";
 import RankingTable from "../Gaming/RankingTable";
 import Score from "../Gaming/Score";
@@ -21,9 +23,12 @@ import TitledColumn from "../TitledColumn";
 import UserIcon from "../UserIcon";
 
 type Props = {
+	gameId: number;
 	gameDisplayName: string;
+	allowPractice: boolean;
 	playerProfileA: PlayerProfile | null;
 	playerProfileB: PlayerProfile | null;
+	problemId: number;
 	problemTitle: string;
 	problemDescription: string;
 	problemLanguage: SupportedLanguage;
@@ -31,9 +36,12 @@ type Props = {
 };
 
 export default function GolfWatchAppGaming1v1({
+	gameId,
 	gameDisplayName,
+	allowPractice,
 	playerProfileA,
 	playerProfileB,
+	problemId,
 	problemTitle,
 	problemDescription,
 	problemLanguage,
@@ -46,16 +54,18 @@ export default function GolfWatchAppGaming1v1({
 	const stateA =
 		playerProfileA && (latestGameStates[`${playerProfileA.id}`] ?? null);
 	const codeA = stateA?.code ?? "";
+	const languageA = stateA?.language ?? problemLanguage;
 	const scoreA = stateA?.score ?? null;
 	const statusA = stateA?.status ?? "none";
 	const stateB =
 		playerProfileB && (latestGameStates[`${playerProfileB.id}`] ?? null);
 	const codeB = stateB?.code ?? "";
+	const languageB = stateB?.language ?? problemLanguage;
 	const scoreB = stateB?.score ?? null;
 	const statusB = stateB?.status ?? "none";
 
-	const codeSizeA = calcCodeSize(codeA, problemLanguage);
-	const codeSizeB = calcCodeSize(codeB, problemLanguage);
+	const codeSizeA = calcCodeSize(codeA, languageA);
+	const codeSizeB = calcCodeSize(codeB, languageB);
 
 	const gameResultKind = checkGameResultKind(gameStateKind, stateA, stateB);
 
@@ -133,8 +143,18 @@ export default function GolfWatchAppGaming1v1({
 					<FoldableBorderedContainerWithCaption
 						caption={`コードサイズ: ${codeSizeA}`}
 					>
-						<CodeBlock code={codeA} language={problemLanguage} />
+						<CodeBlock code={codeA} language={languageA} />
 					</FoldableBorderedContainerWithCaption>
+					{gameStateKind === "finished" && playerProfileA && (
+						<FoldableBorderedContainerWithCaption caption="リプレイ">
+							<CodeReplay
+								gameId={gameId}
+								userId={playerProfileA.id}
+								problemId={problemId}
+								language={problemLanguage}
+							/>
+						</FoldableBorderedContainerWithCaption>
+					)}
 				</TitledColumn>
 				<TitledColumn title={problemTitle} className="order-1 md:order-2">
 					<ProblemColumnContent
@@ -143,6 +163,14 @@ export default function GolfWatchAppGaming1v1({
 						sampleCode={sampleCode}
 					/>
 					<RankingTable problemLanguage={problemLanguage} />
+					{gameStateKind === "finished" && allowPractice && (
+						<FoldableBorderedContainerWithCaption caption="練習ランキング">
+							<PracticeRanking
+								gameId={gameId}
+								problemLanguage={problemLanguage}
+							/>
+						</FoldableBorderedContainerWithCaption>
+					)}
 				</TitledColumn>
 				<TitledColumn
 					title={<SubmitStatusLabel status={statusB} />}
@@ -151,8 +179,18 @@ export default function GolfWatchAppGaming1v1({
 					<FoldableBorderedContainerWithCaption
 						caption={`コードサイズ: ${codeSizeB}`}
 					>
-						<CodeBlock code={codeB} language={problemLanguage} />
+						<CodeBlock code={codeB} language={languageB} />
 					</FoldableBorderedContainerWithCaption>
+					{gameStateKind === "finished" && playerProfileB && (
+						<FoldableBorderedContainerWithCaption caption="リプレイ">
+							<CodeReplay
+								gameId={gameId}
+								userId={playerProfileB.id}
+								problemId={problemId}
+								language={problemLanguage}
+							/>
+						</FoldableBorderedContainerWithCaption>
+					)}
 				</TitledColumn>
 			</ThreeColumnLayout>
 		</div>
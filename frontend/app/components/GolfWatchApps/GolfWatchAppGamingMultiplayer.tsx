@@ -1,14 +1,20 @@
 import { useAtomValue } from "jotai";
-import { gamingLeftTimeSecondsAtom } from "../../states/watch";
+import {
+	gameStateKindAtom,
+	gamingLeftTimeSecondsAtom,
+} from "../../states/watch";
 import type { SupportedLanguage } from "../../types/SupportedLanguage";
 import LeftTime from "../Gaming/LeftTime";
+import PracticeRanking from "../Gaming/PracticeRanking";
 import ProblemColumn from "../Gaming/ProblemColumn";
 import RankingTable from "../Gaming/RankingTable";
 import TitledColumn from "../TitledColumn";
 import TwoColumnLayout from "../TwoColumnLayout";
 
 type Props = {
+	gameId: number;
 	gameDisplayName: string;
+	allowPractice: boolean;
 	problemTitle: string;
 	problemDescription: string;
 	problemLanguage: SupportedLanguage;
@@ -16,12 +22,15 @@ type Props = {
 };
 
 export default function GolfWatchAppGamingMultiplayer({
+	gameId,
 	gameDisplayName,
+	allowPractice,
 	problemTitle,
 	problemDescription,
 	problemLanguage,
 	sampleCode,
 }: Props) {
+	const gameStateKind = useAtomValue(gameStateKindAtom);
 	const leftTimeSeconds = useAtomValue(gamingLeftTimeSecondsAtom)!;
 
 	return (
@@ -43,6 +52,17 @@ export default function GolfWatchAppGamingMultiplayer({
 				/>
 				<TitledColumn title="順位表">
 					<RankingTable problemLanguage={problemLanguage} />
+					{gameStateKind === "finished" && allowPractice && (
+						<>
+							<h2 className="text-center text-lg font-semibold">
+								練習ランキング
+							</h2>
+							<PracticeRanking
+								gameId={gameId}
+								problemLanguage={problemLanguage}
+							/>
+						</>
+					)}
 				</TitledColumn>
 			</TwoColumnLayout>
 		</div>
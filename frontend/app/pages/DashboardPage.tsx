@@ -10,6 +10,7 @@ import { useAuth } from "../hooks/useAuth";
 import { usePageTitle } from "../hooks/usePageTitle";
 
 type Game = components["schemas"]["Game"];
+type QualifyingStage = components["schemas"]["QualifyingStage"];
 
 export default function DashboardPage() {
 	usePageTitle(`Dashboard | ${APP_NAME}`);
@@ -18,6 +19,7 @@ export default function DashboardPage() {
 	const [, navigate] = useLocation();
 
 	const [games, setGames] = useState<Game[]>([]);
+	const [stages, setStages] = useState<QualifyingStage[]>([]);
 	const [loading, setLoading] = useState(true);
 
 	useEffect(() => {
@@ -26,6 +28,10 @@ export default function DashboardPage() {
 			.getGames()
 			.then(({ games }) => setGames(games))
 			.finally(() => setLoading(false));
+		apiClient
+			.getQualifyingStages()
+			.then(({ stages }) => setStages(stages))
+			.catch(() => {});
 	}, []);
 
 	async function handleLogout() {
@@ -51,9 +57,16 @@ export default function DashboardPage() {
 				/>
 			)}
 			{isLoggedIn ? (
-				<h1 className="text-3xl font-bold text-gray-800">
-					{user?.display_name}
-				</h1>
+				<>
+					<h1 className="text-3xl font-bold text-gray-800">
+						{user?.display_name}
+					</h1>
+					{user?.rating_rank != null && (
+						<p className="text-gray-600">
+							レーティング {user.rating} ({user.rating_rank}位)
+						</p>
+					)}
+				</>
 			) : (
 				<h1 className="text-3xl font-bold text-gray-800">試合一覧</h1>
 			)}
@@ -99,6 +112,26 @@ export default function DashboardPage() {
 					)}
 				</div>
 			</BorderedContainerWithCaption>
+			{stages.length > 0 && (
+				<BorderedContainerWithCaption caption="予選">
+					<ul className="px-4 divide-y divide-gray-300">
+						{stages.map((stage) => (
+							<li
+								key={stage.stage_id}
+								className="flex justify-between items-center py-2 gap-4"
+							>
+								<span className="font-medium text-gray-800">
+									{stage.display_name}
+								</span>
+								<NavigateLink to={`/qualifying/${stage.stage_id}`}>
+									ランキング
+								</NavigateLink>
+							</li>
+						))}
+					</ul>
+				</BorderedContainerWithCaption>
+			)}
+			<NavigateLink to="/ratings">レーティング</NavigateLink>
 			{isLoggedIn ? (
 				<button
 					type="button"
@@ -16,6 +16,7 @@ export default function GolfPlayPage({ gameId }: { gameId: string }) {
 	const [, navigate] = useLocation();
 
 	const [game, setGame] = useState<Game | null>(null);
+	const [problemId, setProblemId] = useState<number | null>(null);
 	const [gameState, setGameState] = useState<LatestGameState | null>(null);
 	const [loading, setLoading] = useState(true);
 
@@ -29,24 +30,43 @@ export default function GolfPlayPage({ gameId }: { gameId: string }) {
 
 	useEffect(() => {
 		const apiClient = createApiClient();
-		Promise.all([
-			apiClient.getGame(gameIdNum),
-			apiClient.getGamePlayLatestState(gameIdNum),
-		])
-			.then(([{ game }, { state }]) => {
+		apiClient
+			.getGame(gameIdNum)
+			.then(({ game }) => {
+				const firstProblem = game.problems[0];
+				if (!firstProblem) {
+					navigate("/dashboard");
+					return;
+				}
 				setGame(game);
-				setGameState(state);
+				setProblemId(firstProblem.problem_id);
 			})
 			.catch(() => navigate("/dashboard"))
 			.finally(() => setLoading(false));
 	}, [gameIdNum, navigate]);
 
+	useEffect(() => {
+		if (problemId === null) {
+			return;
+		}
+		setGameState(null);
+		const apiClient = createApiClient();
+		apiClient
+			.getGamePlayLatestState(gameIdNum, problemId)
+			.then(({ state }) => setGameState(state))
+			.catch(() => navigate("/dashboard"));
+	}, [gameIdNum, problemId, navigate]);
+
+	// Each problem has its own state, so switching problems starts over with a
+	// fresh store.
 	const store = useMemo(() => {
-		if (!game || !user) return null;
+		if (!game || !user || problemId === null) return null;
 		return createStore();
-	}, [game, user]);
+	}, [game, user, problemId]);
+
+	const problem = game?.problems.find((p) => p.problem_id === problemId);
 
-	if (loading || !game || !gameState || !user || !store) {
+	if (loading || !game || !problem || !gameState || !user || !store) {
 		return (
 			<div className="min-h-screen bg-gray-100 flex items-center justify-center">
 				<p className="text-gray-500">Loading...</p>
@@ -58,10 +78,12 @@ export default function GolfPlayPage({ gameId }: { gameId: string }) {
 		<JotaiProvider store={store}>
 			<ApiClientContext.Provider value={createApiClient()}>
 				<GolfPlayApp
-					key={game.game_id}
+					key={`${game.game_id}-${problem.problem_id}`}
 					game={game}
+					problem={problem}
 					player={user}
 					initialGameState={gameState}
+					onProblemSelect={setProblemId}
 				/>
 			</ApiClientContext.Provider>
 		</JotaiProvider>
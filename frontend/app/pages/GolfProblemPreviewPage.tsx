@@ -43,11 +43,18 @@ export default function GolfProblemPreviewPage({ gameId }: { gameId: string }) {
 		<div className="p-6 bg-gray-100 min-h-screen flex flex-col items-center gap-4">
 			<h1 className="text-3xl font-bold text-gray-800">{game.display_name}</h1>
 			<div className="w-full max-w-3xl flex flex-col gap-4">
-				<ProblemColumnContent
-					description={game.problem.description}
-					language={game.problem.language}
-					sampleCode={game.problem.sample_code}
-				/>
+				{game.problems.map((problem) => (
+					<div key={problem.problem_id} className="flex flex-col gap-2">
+						<h2 className="text-2xl font-bold text-gray-800">
+							{problem.title}
+						</h2>
+						<ProblemColumnContent
+							description={problem.description}
+							language={problem.language}
+							sampleCode={problem.sample_code}
+						/>
+					</div>
+				))}
 			</div>
 			{game.started_at != null && (
 				<NavigateLink to={`/golf/${game.game_id}/play`}>
@@ -13,6 +13,7 @@ type RankingEntry = components["schemas"]["RankingEntry"];
 export default function GolfWatchPage({ gameId }: { gameId: string }) {
 	const [game, setGame] = useState<Game | null>(null);
 	const [ranking, setRanking] = useState<RankingEntry[]>([]);
+	const [rankingFrozen, setRankingFrozen] = useState(false);
 	const [gameStates, setGameStates] = useState<{
 		[key: string]: LatestGameState;
 	}>({});
@@ -31,12 +32,20 @@ export default function GolfWatchPage({ gameId }: { gameId: string }) {
 		const apiClient = createApiClient();
 		Promise.all([
 			apiClient.getGame(gameIdNum),
-			apiClient.getGameWatchRanking(gameIdNum),
-			apiClient.getGameWatchLatestStates(gameIdNum),
+			apiClient.getGameWatchFullRanking(gameIdNum),
 		])
-			.then(([{ game }, { ranking }, { states }]) => {
+			.then(async ([{ game }, { ranking, is_frozen }]) => {
+				// The states of the players are shown for the first problem.
+				const problem = game.problems[0];
+				const { states } = problem
+					? await apiClient.getGameWatchLatestStates(
+							gameIdNum,
+							problem.problem_id,
+						)
+					: { states: {} };
 				setGame(game);
 				setRanking(ranking);
+				setRankingFrozen(is_frozen);
 				setGameStates(states);
 			})
 			.catch(() => setError(true))
@@ -72,6 +81,7 @@ export default function GolfWatchPage({ gameId }: { gameId: string }) {
 					game={game}
 					initialGameStates={gameStates}
 					initialRanking={ranking}
+					initialRankingFrozen={rankingFrozen}
 				/>
 			</ApiClientContext.Provider>
 		</JotaiProvider>
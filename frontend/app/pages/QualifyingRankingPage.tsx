@@ -0,0 +1,111 @@
+import { useEffect, useState } from "react";
+import { createApiClient } from "../api/client";
+import type { components } from "../api/schema";
+import BorderedContainerWithCaption from "../components/BorderedContainerWithCaption";
+import NavigateLink from "../components/NavigateLink";
+import { APP_NAME } from "../config";
+import { usePageTitle } from "../hooks/usePageTitle";
+
+type QualifyingStage = components["schemas"]["QualifyingStage"];
+type QualifyingEntry = components["schemas"]["QualifyingEntry"];
+
+export default function QualifyingRankingPage({
+	stageId,
+}: {
+	stageId: string;
+}) {
+	usePageTitle(`Qualifying | ${APP_NAME}`);
+
+	const [stage, setStage] = useState<QualifyingStage | null>(null);
+	const [ranking, setRanking] = useState<QualifyingEntry[]>([]);
+	const [loading, setLoading] = useState(true);
+	const [notFound, setNotFound] = useState(false);
+
+	useEffect(() => {
+		const apiClient = createApiClient();
+		apiClient
+			.getQualifyingStageRanking(Number(stageId))
+			.then(({ stage, ranking }) => {
+				setStage(stage);
+				setRanking(ranking);
+			})
+			.catch(() => setNotFound(true))
+			.finally(() => setLoading(false));
+	}, [stageId]);
+
+	if (loading) {
+		return (
+			<div className="min-h-screen bg-gray-100 flex items-center justify-center">
+				<p className="text-gray-500">Loading...</p>
+			</div>
+		);
+	}
+
+	if (notFound || !stage) {
+		return (
+			<div className="min-h-screen bg-gray-100 flex items-center justify-center">
+				<p className="text-gray-500 text-xl">予選が見つかりません</p>
+			</div>
+		);
+	}
+
+	return (
+		<div className="p-6 bg-gray-100 min-h-screen flex flex-col items-center gap-4">
+			<BorderedContainerWithCaption caption={stage.display_name}>
+				<div className="px-4 overflow-x-auto">
+					{ranking.length === 0 ? (
+						<p>まだ記録はありません</p>
+					) : (
+						<table className="min-w-full divide-y divide-gray-400">
+							<thead>
+								<tr>
+									<th className="px-3 py-2 text-left">順位</th>
+									<th className="px-3 py-2 text-left">プレイヤー</th>
+									{stage.games.map((g) => (
+										<th key={g.game_id} className="px-3 py-2 text-left">
+											{g.display_name}
+											{g.weight !== 1 && (
+												<span className="text-sm text-gray-500 ml-1">
+													×{g.weight}
+												</span>
+											)}
+										</th>
+									))}
+									<th className="px-3 py-2 text-left">合計</th>
+								</tr>
+							</thead>
+							<tbody className="divide-y divide-gray-300">
+								{ranking.map((e) => (
+									<tr key={e.user.user_id}>
+										<td className="px-3 py-2">{e.rank}</td>
+										<td className="px-3 py-2">
+											{e.user.display_name}
+											{e.user.label && ` (${e.user.label})`}
+										</td>
+										{stage.games.map((g) => (
+											<td key={g.game_id} className="px-3 py-2">
+												{e.results.find((r) => r.game_id === g.game_id)
+													?.score ?? "-"}
+											</td>
+										))}
+										<td className="px-3 py-2 font-bold">{e.total}</td>
+									</tr>
+								))}
+							</tbody>
+						</table>
+					)}
+					{stage.missing_policy === "penalty" ? (
+						<p className="mt-2 text-sm text-gray-500">
+							記録のない試合は {stage.missing_penalty} として計算します
+						</p>
+					) : (
+						<p className="mt-2 text-sm text-gray-500">
+							すべての試合に記録のあるプレイヤーのみ表示しています
+						</p>
+					)}
+				</div>
+			</BorderedContainerWithCaption>
+			<NavigateLink to="/dashboard">ダッシュボードに戻る</NavigateLink>
+		</div>
+	);
+}
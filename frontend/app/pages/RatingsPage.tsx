@@ -0,0 +1,131 @@
+import { useEffect, useState } from "react";
+import { createApiClient } from "../api/client";
+import type { components } from "../api/schema";
+import BorderedContainerWithCaption from "../components/BorderedContainerWithCaption";
+import NavigateLink from "../components/NavigateLink";
+import UserIcon from "../components/UserIcon";
+import { APP_NAME } from "../config";
+import { usePageTitle } from "../hooks/usePageTitle";
+
+type User = components["schemas"]["User"];
+type RatingChange = components["schemas"]["RatingChange"];
+
+export default function RatingsPage() {
+	usePageTitle(`Ratings | ${APP_NAME}`);
+
+	const [users, setUsers] = useState<User[]>([]);
+	const [loading, setLoading] = useState(true);
+	const [expandedId, setExpandedId] = useState<number | null>(null);
+
+	useEffect(() => {
+		const apiClient = createApiClient();
+		apiClient
+			.getRatings()
+			.then(({ users }) => setUsers(users))
+			.catch(() => {})
+			.finally(() => setLoading(false));
+	}, []);
+
+	if (loading) {
+		return (
+			<div className="min-h-screen bg-gray-100 flex items-center justify-center">
+				<p className="text-gray-500">Loading...</p>
+			</div>
+		);
+	}
+
+	return (
+		<div className="p-6 bg-gray-100 min-h-screen flex flex-col items-center gap-4">
+			<BorderedContainerWithCaption caption="レーティング">
+				<div className="px-4">
+					{users.length === 0 ? (
+						<p>レーティング対象の試合はまだありません</p>
+					) : (
+						<ul className="divide-y divide-gray-300">
+							{users.map((u) => (
+								<li key={u.user_id} className="py-3">
+									<div className="flex justify-between items-center gap-4">
+										<div className="flex items-center gap-3">
+											<span className="w-8 text-right font-bold">
+												{u.rating_rank}
+											</span>
+											{u.icon_path && (
+												<UserIcon
+													iconPath={u.icon_path}
+													displayName={u.display_name}
+													className="w-8 h-8"
+												/>
+											)}
+											<span className="font-medium text-gray-800">
+												{u.display_name}
+											</span>
+										</div>
+										<div className="flex items-center gap-3">
+											<span className="font-mono text-lg font-bold">
+												{u.rating}
+											</span>
+											<button
+												type="button"
+												onClick={() =>
+													setExpandedId(
+														expandedId === u.user_id ? null : u.user_id,
+													)
+												}
+												className="text-sm text-sky-600 hover:text-sky-800 underline"
+											>
+												{expandedId === u.user_id ? "履歴を隠す" : "履歴を見る"}
+											</button>
+										</div>
+									</div>
+									{expandedId === u.user_id && (
+										<RatingHistory userId={u.user_id} />
+									)}
+								</li>
+							))}
+						</ul>
+					)}
+				</div>
+			</BorderedContainerWithCaption>
+			<NavigateLink to="/dashboard">ダッシュボードに戻る</NavigateLink>
+		</div>
+	);
+}
+
+function RatingHistory({ userId }: { userId: number }) {
+	const [history, setHistory] = useState<RatingChange[] | null>(null);
+
+	useEffect(() => {
+		const apiClient = createApiClient();
+		apiClient
+			.getUserRatingHistory(userId)
+			.then(({ history }) => setHistory(history))
+			.catch(() => {});
+	}, [userId]);
+
+	if (!history) {
+		return null;
+	}
+	return (
+		<ul className="mt-2 flex flex-col gap-1 text-sm">
+			{[...history].reverse().map((c) => {
+				const delta = c.rating_after - c.rating_before;
+				return (
+					<li key={c.game_id} className="flex justify-between gap-4">
+						<span>
+							{c.game_display_name}
+							<span className="text-gray-500 ml-2">{c.game_rank}位</span>
+						</span>
+						<span className="font-mono">
+							{c.rating_after}
+							<span
+								className={`ml-2 ${delta >= 0 ? "text-green-700" : "text-red-700"}`}
+							>
+								{delta >= 0 ? `+${delta}` : delta}
+							</span>
+						</span>
+					</li>
+				);
+			})}
+		</ul>
+	);
+}
@@ -8,6 +8,8 @@ import { APP_NAME } from "../config";
 import { usePageTitle } from "../hooks/usePageTitle";
 
 type Submission = components["schemas"]["Submission"];
+type TestcaseResult = components["schemas"]["TestcaseResult"];
+type DiffLine = components["schemas"]["DiffLine"];
 
 export default function SubmissionsPage({ gameId }: { gameId: string }) {
 	usePageTitle(`Submissions | ${APP_NAME}`);
@@ -15,6 +17,10 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 	const [submissions, setSubmissions] = useState<Submission[]>([]);
 	const [loading, setLoading] = useState(true);
 	const [expandedId, setExpandedId] = useState<number | null>(null);
+	const [testcaseResults, setTestcaseResults] = useState<
+		TestcaseResult[] | null
+	>(null);
+	const [diffId, setDiffId] = useState<number | null>(null);
 
 	const numericGameId = Number(gameId);
 
@@ -27,6 +33,18 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 			.finally(() => setLoading(false));
 	}, [numericGameId]);
 
+	useEffect(() => {
+		setTestcaseResults(null);
+		if (expandedId === null) {
+			return;
+		}
+		const apiClient = createApiClient();
+		apiClient
+			.getGamePlaySubmission(numericGameId, expandedId)
+			.then(({ testcase_results }) => setTestcaseResults(testcase_results))
+			.catch(() => {});
+	}, [numericGameId, expandedId]);
+
 	if (loading) {
 		return (
 			<div className="min-h-screen bg-gray-100 flex items-center justify-center">
@@ -43,11 +61,16 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 						<p>提出履歴はありません</p>
 					) : (
 						<ul className="divide-y divide-gray-300">
-							{submissions.map((s) => (
+							{submissions.map((s, i) => (
 								<li key={s.submission_id} className="py-3">
 									<div className="flex justify-between items-center gap-4">
 										<div className="flex items-center gap-3">
 											<StatusBadge status={s.status} />
+											{s.is_practice && (
+												<span className="px-2 py-1 rounded text-sm font-medium bg-sky-100 text-sky-800">
+													練習
+												</span>
+											)}
 											<span className="font-mono text-lg font-bold">
 												{s.code_size}
 												<span className="text-sm font-normal text-gray-500 ml-1">
@@ -59,6 +82,23 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 											<span className="text-sm text-gray-500">
 												{formatDate(s.created_at)}
 											</span>
+											{submissions[i + 1] && (
+												<button
+													type="button"
+													onClick={() =>
+														setDiffId(
+															diffId === s.submission_id
+																? null
+																: s.submission_id,
+														)
+													}
+													className="text-sm text-sky-600 hover:text-sky-800 underline"
+												>
+													{diffId === s.submission_id
+														? "差分を隠す"
+														: "前回との差分"}
+												</button>
+											)}
 											<button
 												type="button"
 												onClick={() =>
@@ -76,10 +116,22 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 											</button>
 										</div>
 									</div>
+									{diffId === s.submission_id && submissions[i + 1] && (
+										<SubmissionDiff
+											gameId={numericGameId}
+											from={submissions[i + 1]!.submission_id}
+											to={s.submission_id}
+										/>
+									)}
 									{expandedId === s.submission_id && (
-										<pre className="mt-2 p-3 bg-gray-800 text-gray-100 rounded text-sm overflow-x-auto">
-											{s.code}
-										</pre>
+										<>
+											<pre className="mt-2 p-3 bg-gray-800 text-gray-100 rounded text-sm overflow-x-auto">
+												{s.code}
+											</pre>
+											{testcaseResults && (
+												<TestcaseResultList results={testcaseResults} />
+											)}
+										</>
 									)}
 								</li>
 							))}
@@ -93,6 +145,108 @@ export default function SubmissionsPage({ gameId }: { gameId: string }) {
 	);
 }
 
+function SubmissionDiff({
+	gameId,
+	from,
+	to,
+}: {
+	gameId: number;
+	from: number;
+	to: number;
+}) {
+	const [diff, setDiff] = useState<{
+		lines: DiffLine[];
+		size_delta: number;
+	} | null>(null);
+
+	useEffect(() => {
+		const apiClient = createApiClient();
+		apiClient
+			.getGameSubmissionDiff(gameId, from, to)
+			.then(setDiff)
+			.catch(() => {});
+	}, [gameId, from, to]);
+
+	if (!diff) {
+		return null;
+	}
+	return (
+		<div className="mt-2">
+			<p className="text-sm text-gray-500">
+				{diff.size_delta > 0 ? `+${diff.size_delta}` : diff.size_delta} bytes
+			</p>
+			<pre className="mt-1 p-3 bg-gray-800 text-gray-100 rounded text-sm overflow-x-auto">
+				{diff.lines.map((l) => (
+					<div
+						key={`${l.old_line ?? ""}-${l.new_line ?? ""}`}
+						className={
+							l.op === "insert"
+								? "bg-green-900"
+								: l.op === "delete"
+									? "bg-red-900"
+									: undefined
+						}
+					>
+						{l.op === "insert" ? "+" : l.op === "delete" ? "-" : " "}
+						{l.text}
+					</div>
+				))}
+			</pre>
+		</div>
+	);
+}
+
+function TestcaseResultList({ results }: { results: TestcaseResult[] }) {
+	if (results.length === 0) {
+		return null;
+	}
+	return (
+		<ul className="mt-2 flex flex-col gap-2">
+			{results.map((r, i) => (
+				<li key={r.testcase_id} className="p-2 bg-white rounded">
+					<div className="flex items-center gap-3">
+						<span className="text-sm font-bold">
+							{r.is_sample ? `サンプル ${i + 1}` : `テストケース ${i + 1}`}
+						</span>
+						{r.status ? (
+							<StatusBadge status={r.status} />
+						) : (
+							<span
+								className={`px-2 py-1 rounded text-sm font-medium ${
+									r.passed
+										? "bg-green-100 text-green-800"
+										: "bg-red-100 text-red-800"
+								}`}
+							>
+								{r.passed ? "成功" : "失敗"}
+							</span>
+						)}
+					</div>
+					{r.is_sample && (
+						<div className="mt-2 grid grid-cols-1 md:grid-cols-2 gap-2 text-sm">
+							<SampleOutput caption="入力" text={r.stdin} />
+							<SampleOutput caption="期待される出力" text={r.expected_stdout} />
+							<SampleOutput caption="標準出力" text={r.stdout} />
+							<SampleOutput caption="標準エラー出力" text={r.stderr} />
+						</div>
+					)}
+				</li>
+			))}
+		</ul>
+	);
+}
+
+function SampleOutput({ caption, text }: { caption: string; text?: string }) {
+	return (
+		<div>
+			<p className="text-gray-500">{caption}</p>
+			<pre className="p-2 bg-gray-800 text-gray-100 rounded overflow-x-auto">
+				{text}
+			</pre>
+		</div>
+	);
+}
+
 function StatusBadge({
 	status,
 }: {
@@ -1,5 +1,6 @@
 import { createStore } from "jotai";
 import { describe, expect, test } from "vitest";
+import type { components } from "../api/schema";
 import {
 	calcCodeSize,
 	gameStateKindAtom,
@@ -9,12 +10,40 @@ import {
 	scoreAtom,
 	setCurrentTimestampAtom,
 	setDurationSecondsAtom,
+	setGameAtom,
 	setGameStartedAtAtom,
 	setLatestGameStateAtom,
 	startingLeftTimeSecondsAtom,
 	statusAtom,
 } from "./play";
 
+type Game = components["schemas"]["Game"];
+
+function makeGame(overrides: Partial<Game>): Game {
+	return {
+		game_id: 1,
+		game_type: "multiplayer",
+		is_public: true,
+		allow_practice: false,
+		display_name: "Game",
+		duration_seconds: 300,
+		state: "waiting",
+		problems: [
+			{
+				problem_id: 1,
+				title: "Problem",
+				description: "",
+				language: "php",
+				sample_code: "",
+				scoring: "bytes",
+				languages: [{ language: "php", sample_code: "" }],
+			},
+		],
+		main_players: [],
+		...overrides,
+	};
+}
+
 describe("calcCodeSize", () => {
 	test("counts UTF-8 bytes after removing whitespace (swift)", () => {
 		expect(calcCodeSize("print(1)", "swift")).toBe(8);
@@ -156,6 +185,47 @@ describe("Jotai atoms", () => {
 		expect(store.get(gamingLeftTimeSecondsAtom)).toBe(300);
 	});
 
+	test("gameStateKindAtom returns 'paused' and freezes the left time", () => {
+		const store = createStore();
+		const now = Math.floor(Date.now() / 1000);
+		store.set(setCurrentTimestampAtom);
+		store.set(
+			setGameAtom,
+			makeGame({ started_at: now - 100, state: "paused", paused_at: now - 50 }),
+		);
+		expect(store.get(gameStateKindAtom)).toBe("paused");
+		expect(store.get(gamingLeftTimeSecondsAtom)).toBe(250);
+	});
+
+	test("gameStateKindAtom returns 'finished' when the game is ended early", () => {
+		const store = createStore();
+		const now = Math.floor(Date.now() / 1000);
+		store.set(setCurrentTimestampAtom);
+		store.set(
+			setGameAtom,
+			makeGame({ started_at: now - 10, state: "finished" }),
+		);
+		expect(store.get(gameStateKindAtom)).toBe("finished");
+	});
+
+	test("gameStateKindAtom returns 'cancelled' when the game is cancelled", () => {
+		const store = createStore();
+		store.set(setCurrentTimestampAtom);
+		store.set(setGameAtom, makeGame({ state: "cancelled" }));
+		expect(store.get(gameStateKindAtom)).toBe("cancelled");
+	});
+
+	test("gameStateKindAtom follows the clock while the game is running", () => {
+		const store = createStore();
+		const now = Math.floor(Date.now() / 1000);
+		store.set(setCurrentTimestampAtom);
+		store.set(
+			setGameAtom,
+			makeGame({ started_at: now - 400, state: "running" }),
+		);
+		expect(store.get(gameStateKindAtom)).toBe("finished");
+	});
+
 	test("statusAtom returns 'running' when submitting code", () => {
 		const store = createStore();
 		store.set(handleSubmitCodePreAtom);
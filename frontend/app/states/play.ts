@@ -7,20 +7,43 @@ export const setGameStartedAtAtom = atom(null, (_, set, value: number | null) =>
 	set(gameStartedAtAtom, value),
 );
 
+// The state reported by the server. It is only consulted for the states that
+// the clock cannot tell: paused, cancelled and ended early.
+const serverGameStateAtom = atom<GameState | null>(null);
+const gamePausedAtAtom = atom<number | null>(null);
+
+export const setGameAtom = atom(null, (_, set, game: Game) => {
+	set(gameStartedAtAtom, game.started_at ?? null);
+	set(durationSecondsAtom, game.duration_seconds);
+	set(serverGameStateAtom, game.state);
+	set(gamePausedAtAtom, game.paused_at ?? null);
+});
+
 export type GameStateKind =
 	| "loading"
 	| "waiting"
 	| "starting"
 	| "gaming"
-	| "finished";
+	| "paused"
+	| "finished"
+	| "cancelled";
+type Game = components["schemas"]["Game"];
+type GameState = components["schemas"]["GameState"];
 type ExecutionStatus = components["schemas"]["ExecutionStatus"];
 type LatestGameState = components["schemas"]["LatestGameState"];
+type GameEvent = components["schemas"]["GameEvent"];
 
 export const gameStateKindAtom = atom<GameStateKind>((get) => {
 	const now = get(currentTimestampAtom);
 	if (!now) {
 		return "loading";
 	}
+	const serverGameState = get(serverGameStateAtom);
+	if (serverGameState === "paused" || serverGameState === "cancelled") {
+		return serverGameState;
+	} else if (serverGameState === "finished") {
+		return "finished";
+	}
 	const startedAt = get(gameStartedAtAtom);
 	if (!startedAt) {
 		return "waiting";
@@ -65,7 +88,8 @@ export const gamingLeftTimeSecondsAtom = atom<number | null>((get) => {
 	}
 	const durationSeconds = get(durationSecondsAtom);
 	const finishedAt = startedAt + durationSeconds;
-	const currentTimestamp = get(currentTimestampAtom);
+	// The clock stops while the game is paused.
+	const currentTimestamp = get(gamePausedAtAtom) ?? get(currentTimestampAtom);
 	if (currentTimestamp === null) {
 		return null;
 	}
@@ -102,6 +126,14 @@ export const setLatestGameStateAtom = atom(
 	},
 );
 
+export const applyGameEventAtom = atom(null, (_, set, event: GameEvent) => {
+	if (event.type === "status" && event.status) {
+		set(rawStatusAtom, event.status);
+	} else if (event.type === "best_score" && event.score != null) {
+		set(rawScoreAtom, event.score);
+	}
+});
+
 function cleanCode(code: string, language: SupportedLanguage) {
 	if (language === "php") {
 		return code
@@ -1,6 +1,7 @@
 import { createStore } from "jotai";
 import { describe, expect, test } from "vitest";
 import {
+	applyGameEventAtom,
 	calcCodeSize,
 	checkGameResultKind,
 	gameStateKindAtom,
@@ -194,6 +195,61 @@ describe("watch Jotai atoms", () => {
 		expect(store.get(latestGameStatesAtom)).toEqual(states);
 	});
 
+	test("applyGameEventAtom merges events into states", () => {
+		const store = createStore();
+		store.set(setLatestGameStatesAtom, {
+			"1": {
+				code: "echo 1;",
+				status: "none" as const,
+				score: null,
+				best_score_submitted_at: null,
+			},
+		});
+		store.set(applyGameEventAtom, {
+			type: "code",
+			user_id: 1,
+			team_id: 1,
+			problem_id: 1,
+			code: "echo 2;",
+		});
+		store.set(applyGameEventAtom, {
+			type: "status",
+			user_id: 1,
+			team_id: 1,
+			problem_id: 1,
+			status: "success",
+		});
+		store.set(applyGameEventAtom, {
+			type: "best_score",
+			user_id: 1,
+			team_id: 1,
+			problem_id: 1,
+			score: 7,
+			best_score_submitted_at: 1000,
+		});
+		store.set(applyGameEventAtom, {
+			type: "status",
+			user_id: 2,
+			team_id: 2,
+			problem_id: 1,
+			status: "running",
+		});
+		expect(store.get(latestGameStatesAtom)).toEqual({
+			"1": {
+				code: "echo 2;",
+				status: "success",
+				score: 7,
+				best_score_submitted_at: 1000,
+			},
+			"2": {
+				code: "",
+				status: "running",
+				score: null,
+				best_score_submitted_at: null,
+			},
+		});
+	});
+
 	test("startingLeftTimeSecondsAtom returns null initially", () => {
 		const store = createStore();
 		expect(store.get(startingLeftTimeSecondsAtom)).toBeNull();
@@ -7,12 +7,29 @@ export const setGameStartedAtAtom = atom(null, (_, set, value: number | null) =>
 	set(gameStartedAtAtom, value),
 );
 
+// The state reported by the server. It is only consulted for the states that
+// the clock cannot tell: paused, cancelled and ended early.
+const serverGameStateAtom = atom<GameState | null>(null);
+const gamePausedAtAtom = atom<number | null>(null);
+
+export const setGameAtom = atom(null, (_, set, game: Game) => {
+	set(gameStartedAtAtom, game.started_at ?? null);
+	set(durationSecondsAtom, game.duration_seconds);
+	set(serverGameStateAtom, game.state);
+	set(gamePausedAtAtom, game.paused_at ?? null);
+});
+
 export type GameStateKind =
 	| "loading"
 	| "waiting"
 	| "starting"
 	| "gaming"
-	| "finished";
+	| "paused"
+	| "finished"
+	| "cancelled";
+type Game = components["schemas"]["Game"];
+type GameState = components["schemas"]["GameState"];
+type GameEvent = components["schemas"]["GameEvent"];
 type LatestGameState = components["schemas"]["LatestGameState"];
 type RankingEntry = components["schemas"]["RankingEntry"];
 
@@ -21,6 +38,12 @@ export const gameStateKindAtom = atom<GameStateKind>((get) => {
 	if (!now) {
 		return "loading";
 	}
+	const serverGameState = get(serverGameStateAtom);
+	if (serverGameState === "paused" || serverGameState === "cancelled") {
+		return serverGameState;
+	} else if (serverGameState === "finished") {
+		return "finished";
+	}
 	const startedAt = get(gameStartedAtAtom);
 	if (!startedAt) {
 		return "waiting";
@@ -65,7 +88,8 @@ export const gamingLeftTimeSecondsAtom = atom<number | null>((get) => {
 	}
 	const durationSeconds = get(durationSecondsAtom);
 	const finishedAt = startedAt + durationSeconds;
-	const currentTimestamp = get(currentTimestampAtom);
+	// The clock stops while the game is paused.
+	const currentTimestamp = get(gamePausedAtAtom) ?? get(currentTimestampAtom);
 	if (currentTimestamp === null) {
 		return null;
 	}
@@ -73,6 +97,9 @@ export const gamingLeftTimeSecondsAtom = atom<number | null>((get) => {
 });
 
 export const rankingAtom = atom<RankingEntry[]>([]);
+// Whether the ranking is frozen near the end of the game. While frozen, the
+// ranking and the scores of players do not reflect the latest submissions.
+export const rankingFrozenAtom = atom(false);
 
 const rawLatestGameStatesAtom = atom<{
 	[key: string]: LatestGameState | undefined;
@@ -84,6 +111,41 @@ export const setLatestGameStatesAtom = atom(
 		set(rawLatestGameStatesAtom, value);
 	},
 );
+export const applyGameEventAtom = atom(null, (get, set, event: GameEvent) => {
+	if (event.type === "game") {
+		return;
+	}
+	const states = get(rawLatestGameStatesAtom);
+	const key = String(event.user_id);
+	const current: LatestGameState = states[key] ?? {
+		code: "",
+		score: null,
+		best_score_submitted_at: null,
+		status: "none",
+	};
+	let next: LatestGameState;
+	switch (event.type) {
+		case "code":
+			next = {
+				...current,
+				language: event.language ?? current.language,
+				code: event.code ?? current.code,
+			};
+			break;
+		case "status":
+			next = { ...current, status: event.status ?? current.status };
+			break;
+		case "best_score":
+			next = {
+				...current,
+				score: event.score ?? current.score,
+				best_score_submitted_at:
+					event.best_score_submitted_at ?? current.best_score_submitted_at,
+			};
+			break;
+	}
+	set(rawLatestGameStatesAtom, { ...states, [key]: next });
+});
 
 function cleanCode(code: string, language: SupportedLanguage) {
 	if (language === "php") {
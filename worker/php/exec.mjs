@@ -1,23 +1,45 @@
+import { performance } from "node:perf_hooks";
 import { buildResult, createIOCallbacks, preprocessCode } from "./lib.mjs";
 import PHPWasm from "./php-wasm.js";
 
-process.once("message", async ({ code: originalCode, input }) => {
-	const code = preprocessCode(originalCode);
-	const io = createIOCallbacks(input);
+process.once(
+	"message",
+	async ({ code: originalCode, input, memoryLimitBytes }) => {
+		const code = preprocessCode(originalCode);
+		const io = createIOCallbacks(input);
 
-	const { ccall } = await PHPWasm({
-		stdin: io.stdin,
-		stdout: io.stdout,
-		stderr: io.stderr,
-	});
+		const { ccall } = await PHPWasm({
+			stdin: io.stdin,
+			stdout: io.stdout,
+			stderr: io.stderr,
+		});
 
-	let err;
-	let result;
-	try {
-		result = ccall("php_wasm_run", "number", ["string"], [code]);
-	} catch (e) {
-		err = e;
-	}
+		let err;
+		let result;
+		const start = performance.now();
+		try {
+			result = ccall(
+				"php_wasm_run",
+				"number",
+				["string", "number"],
+				[code, memoryLimitBytes],
+			);
+		} catch (e) {
+			err = e;
+		}
+		const timeMs = Math.round(performance.now() - start);
 
-	process.send(buildResult(err, result, io.getStdout, io.getStderr));
-});
+		// The engine may be unusable after an abort, so the peak memory is
+		// only reported when the run returned.
+		let memoryBytes;
+		if (!err) {
+			memoryBytes = ccall("php_wasm_peak_memory", "number", [], []);
+		}
+
+		process.send({
+			...buildResult(err, result, io.getStdout, io.getStderr),
+			time_ms: timeMs,
+			memory_bytes: memoryBytes,
+		});
+	},
+);
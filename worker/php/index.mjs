@@ -1,12 +1,22 @@
 import { fork } from "node:child_process";
 import { serve } from "@hono/node-server";
 import { Hono } from "hono";
+import { BUFFER_MAX, MAX_MEMORY_BYTES, validateLimits } from "./lib.mjs";
 
-const execPhp = (code, input, timeoutMsec) => {
+// Keep in sync with the branch of php-src built in the Dockerfile.
+const PHP_VERSION = "8.4.4";
+
+let running = 0;
+
+// execPhp runs the code in a child process. time_limit_ms and
+// memory_limit_bytes of the testcase take the place of the defaults if set.
+const execPhp = (code, input, maxDurationMsec, limits = {}) => {
+	const timeoutMsec = limits.time_limit_ms || maxDurationMsec;
+	const memoryLimitBytes = limits.memory_limit_bytes || 0;
 	return new Promise((resolve, _reject) => {
 		const proc = fork("./exec.mjs");
 
-		proc.send({ code, input });
+		proc.send({ code, input, memoryLimitBytes });
 
 		proc.on("message", (result) => {
 			resolve(result);
@@ -24,13 +34,64 @@ const execPhp = (code, input, timeoutMsec) => {
 	});
 };
 
+// badRequest answers as the Swift worker does for invalid requests.
+const badRequest = (c, message) =>
+	c.json({ message: `Invalid request: ${message}` }, 400);
+
 const app = new Hono();
 
+app.get("/info", (c) => {
+	return c.json({
+		language: "php",
+		version: PHP_VERSION,
+		max_memory_bytes: MAX_MEMORY_BYTES,
+		max_output_bytes: BUFFER_MAX,
+		running,
+	});
+});
+
 app.post("/exec", async (c) => {
 	console.log("worker/exec");
-	const { code, stdin, max_duration_ms } = await c.req.json();
-	const result = await execPhp(code, stdin, max_duration_ms);
-	return c.json(result);
+	const { code, stdin, max_duration_ms, time_limit_ms, memory_limit_bytes } =
+		await c.req.json();
+	const invalid = validateLimits({ time_limit_ms, memory_limit_bytes });
+	if (invalid) {
+		return badRequest(c, invalid);
+	}
+	running++;
+	try {
+		const result = await execPhp(code, stdin, max_duration_ms, {
+			time_limit_ms,
+			memory_limit_bytes,
+		});
+		return c.json(result);
+	} finally {
+		running--;
+	}
+});
+
+// PHP needs no compilation, so the testcases are just run one by one.
+app.post("/exec_batch", async (c) => {
+	console.log("worker/exec_batch");
+	const { code, testcases, max_duration_ms } = await c.req.json();
+	for (const testcase of testcases) {
+		const invalid = validateLimits(testcase);
+		if (invalid) {
+			return badRequest(c, invalid);
+		}
+	}
+	running++;
+	try {
+		const results = [];
+		for (const testcase of testcases) {
+			results.push(
+				await execPhp(code, testcase.stdin, max_duration_ms, testcase),
+			);
+		}
+		return c.json({ results });
+	} finally {
+		running--;
+	}
 });
 
 serve({
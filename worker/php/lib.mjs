@@ -7,7 +7,11 @@ const PRELUDE = `
 
   `;
 
-const BUFFER_MAX = 10 * 1024;
+export const BUFFER_MAX = 10 * 1024;
+
+// INITIAL_MEMORY of the wasm module built in the Dockerfile. PHP cannot
+// allocate more than that, so higher memory limits are refused.
+export const MAX_MEMORY_BYTES = 16 * 1024 * 1024;
 
 export function preprocessCode(originalCode) {
 	if (originalCode.startsWith("<?php")) {
@@ -19,6 +23,18 @@ export function preprocessCode(originalCode) {
 	return PRELUDE + originalCode;
 }
 
+// validateLimits returns why the limits of a run cannot be applied, or null
+// if they can.
+export function validateLimits({ time_limit_ms, memory_limit_bytes } = {}) {
+	if (time_limit_ms < 0 || memory_limit_bytes < 0) {
+		return "'time_limit_ms' and 'memory_limit_bytes' must not be negative";
+	}
+	if (memory_limit_bytes > MAX_MEMORY_BYTES) {
+		return `'memory_limit_bytes' must not exceed ${MAX_MEMORY_BYTES}`;
+	}
+	return null;
+}
+
 export function createIOCallbacks(input) {
 	let stdinPos = 0;
 	const stdinBuf = Buffer.from(input);
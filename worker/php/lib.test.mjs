@@ -1,5 +1,11 @@
 import { describe, expect, it } from "vitest";
-import { buildResult, createIOCallbacks, preprocessCode } from "./lib.mjs";
+import {
+	buildResult,
+	createIOCallbacks,
+	MAX_MEMORY_BYTES,
+	preprocessCode,
+	validateLimits,
+} from "./lib.mjs";
 
 describe("preprocessCode", () => {
 	it("removes <?php tag and prepends PRELUDE", () => {
@@ -31,6 +37,38 @@ describe("preprocessCode", () => {
 		const result = preprocessCode('echo "x"; <?php echo "y";');
 		expect(result).toContain("<?php");
 	});
+
+	it("leaves memory_limit to the engine", () => {
+		const result = preprocessCode('<?php echo "hello";');
+		expect(result).not.toContain("memory_limit");
+	});
+});
+
+describe("validateLimits", () => {
+	it("accepts no limits", () => {
+		expect(validateLimits({})).toBeNull();
+		expect(validateLimits()).toBeNull();
+	});
+
+	it("accepts limits the module can satisfy", () => {
+		expect(
+			validateLimits({
+				time_limit_ms: 1000,
+				memory_limit_bytes: MAX_MEMORY_BYTES,
+			}),
+		).toBeNull();
+	});
+
+	it("rejects negative limits", () => {
+		expect(validateLimits({ time_limit_ms: -1 })).not.toBeNull();
+		expect(validateLimits({ memory_limit_bytes: -1 })).not.toBeNull();
+	});
+
+	it("rejects memory limits above what the module can allocate", () => {
+		expect(
+			validateLimits({ memory_limit_bytes: MAX_MEMORY_BYTES + 1 }),
+		).toContain("memory_limit_bytes");
+	});
 });
 
 describe("createIOCallbacks", () => {
@@ -1,22 +1,70 @@
 #include <stdio.h>
+#include <string.h>
 #include <emscripten.h>
+#include <Zend/zend_alloc.h>
 #include <Zend/zend_execute.h>
+#include <Zend/zend_ini.h>
 #include <sapi/embed/php_embed.h>
 
-int EMSCRIPTEN_KEEPALIVE php_wasm_run(const char* code) {
-    zend_result result;
+// Peak memory allocated by the last run, read before the engine shuts down.
+static size_t peak_memory = 0;
+
+// memory_limit of the next run as an INI value, empty for the default.
+static char memory_limit[32] = "";
+
+// php_wasm_ini_defaults sets memory_limit while the engine reads its
+// configuration, the same way the CLI sets its own defaults.
+static void php_wasm_ini_defaults(HashTable *configuration_hash) {
+    zval value;
+
+    if (memory_limit[0] == '\0') {
+        return;
+    }
+    ZVAL_NEW_STR(&value, zend_string_init(memory_limit, strlen(memory_limit), 1));
+    zend_hash_str_update(configuration_hash, "memory_limit", sizeof("memory_limit") - 1, &value);
+}
+
+// lock_memory_limit keeps the code from changing memory_limit with ini_set,
+// which only changes entries modifiable by PHP_INI_USER.
+static void lock_memory_limit(void) {
+    zend_ini_entry *entry = zend_hash_str_find_ptr(EG(ini_directives), "memory_limit", sizeof("memory_limit") - 1);
+
+    if (entry != NULL) {
+        entry->modifiable = ZEND_INI_SYSTEM;
+    }
+}
+
+// php_wasm_run runs the code. A positive memory_limit_bytes takes the place of
+// the default memory_limit of PHP.
+int EMSCRIPTEN_KEEPALIVE php_wasm_run(const char* code, size_t memory_limit_bytes) {
+    zend_result result = FAILURE;
 
     int argc = 1;
     char* argv[] = { "php.wasm", NULL };
 
-    PHP_EMBED_START_BLOCK(argc, argv);
-
-    result = zend_eval_string_ex(code, NULL, "php.wasm code", 1);
+    if (memory_limit_bytes > 0) {
+        snprintf(memory_limit, sizeof(memory_limit), "%zu", memory_limit_bytes);
+    } else {
+        memory_limit[0] = '\0';
+    }
+    php_embed_module.ini_defaults = php_wasm_ini_defaults;
 
-    PHP_EMBED_END_BLOCK();
+    // PHP_EMBED_START_BLOCK and PHP_EMBED_END_BLOCK, expanded to read the
+    // peak memory also when the code bails out, e.g. on the memory limit.
+    php_embed_init(argc, argv);
+    lock_memory_limit();
+    zend_first_try {
+        result = zend_eval_string_ex(code, NULL, "php.wasm code", 1);
+    } zend_end_try();
+    peak_memory = zend_memory_peak_usage(0);
+    php_embed_shutdown();
 
     fflush(stdout);
     fflush(stderr);
 
     return result == SUCCESS ? 0 : 1;
 }
+
+size_t EMSCRIPTEN_KEEPALIVE php_wasm_peak_memory(void) {
+    return peak_memory;
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const artifactCacheMaxBytes = 512 * 1024 * 1024 // 512 MiB
+
+// artifacts is the cache of the compiled programs, set up on startup.
+var artifacts *artifactCache
+
+// sourceHash is the key of the compiled program of code. The compiler version
+// is part of it so that a cache kept across upgrades is not reused.
+func sourceHash(code string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(swiftVersion+"\x00"+code)))
+}
+
+// artifactCache keeps compiled wasm files on disk, keyed by the hash of the
+// source. The least recently used files are removed once the total size
+// exceeds maxBytes, except for those being run.
+type artifactCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	entries map[string]*cacheEntry
+	// lru has the keys of the entries, the most recently used first.
+	lru  *list.List
+	size int64
+	// building has the builds in progress, so that concurrent requests for
+	// the same code wait for a single build.
+	building map[string]*buildCall
+}
+
+type cacheEntry struct {
+	size int64
+	// pins is the number of requests running the file.
+	pins int
+	elem *list.Element
+}
+
+type buildCall struct {
+	done chan struct{}
+	res  execResponseData
+}
+
+// newArtifactCache opens the cache in dir, keeping the files left by a
+// previous run in the order of their modification times.
+func newArtifactCache(dir string, maxBytes int64) (*artifactCache, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	c := &artifactCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		entries:  make(map[string]*cacheEntry),
+		lru:      list.New(),
+		building: make(map[string]*buildCall),
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var files []os.FileInfo
+	for _, de := range dirEntries {
+		info, err := de.Info()
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasSuffix(de.Name(), ".wasm") {
+			// Partial files of interrupted builds.
+			_ = os.Remove(filepath.Join(dir, de.Name()))
+			continue
+		}
+		files = append(files, info)
+	}
+	slices.SortFunc(files, func(a, b os.FileInfo) int {
+		return b.ModTime().Compare(a.ModTime())
+	})
+	for _, info := range files {
+		key := strings.TrimSuffix(info.Name(), ".wasm")
+		c.entries[key] = &cacheEntry{size: info.Size(), elem: c.lru.PushBack(key)}
+		c.size += info.Size()
+	}
+	c.evict()
+	return c, nil
+}
+
+func (c *artifactCache) path(key string) string {
+	return filepath.Join(c.dir, key+".wasm")
+}
+
+// get returns the path to the compiled program for key, calling build to
+// write it to dst if it is not cached. The file is kept until release is
+// called. If the build fails, its result is returned and nothing is cached.
+func (c *artifactCache) get(key string, build func(dst string) execResponseData) (string, func(), execResponseData) {
+	c.mu.Lock()
+	for {
+		if e, ok := c.entries[key]; ok {
+			e.pins++
+			c.lru.MoveToFront(e.elem)
+			c.mu.Unlock()
+			// The order of use survives restarts through the modification
+			// times.
+			now := time.Now()
+			_ = os.Chtimes(c.path(key), now, now)
+			return c.path(key), c.releaseFunc(key), execResponseData{Status: resultSuccess}
+		}
+		call, ok := c.building[key]
+		if !ok {
+			break
+		}
+		c.mu.Unlock()
+		<-call.done
+		if !call.res.success() {
+			return "", func() {}, call.res
+		}
+		c.mu.Lock()
+	}
+	call := &buildCall{done: make(chan struct{})}
+	c.building[key] = call
+	c.mu.Unlock()
+
+	tmp := c.path(key) + ".tmp"
+	res := build(tmp)
+	var size int64
+	if res.success() {
+		info, err := os.Stat(tmp)
+		if err == nil {
+			size = info.Size()
+			err = os.Rename(tmp, c.path(key))
+		}
+		if err != nil {
+			res = execResponseData{
+				Status: resultInternalError,
+				Stderr: "Failed to store the compiled program",
+			}
+		}
+	}
+	if !res.success() {
+		_ = os.Remove(tmp)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.building, key)
+	call.res = res
+	close(call.done)
+	if !res.success() {
+		return "", func() {}, res
+	}
+	c.entries[key] = &cacheEntry{size: size, pins: 1, elem: c.lru.PushFront(key)}
+	c.size += size
+	c.evict()
+	return c.path(key), c.releaseFunc(key), res
+}
+
+func (c *artifactCache) releaseFunc(key string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			c.mu.Lock()
+			defer c.mu.Unlock()
+			if e, ok := c.entries[key]; ok {
+				e.pins--
+				c.evict()
+			}
+		})
+	}
+}
+
+// evict must be called with the lock held.
+func (c *artifactCache) evict() {
+	for elem := c.lru.Back(); elem != nil && c.size > c.maxBytes; {
+		prev := elem.Prev()
+		key := elem.Value.(string)
+		e := c.entries[key]
+		if e.pins == 0 {
+			if err := os.Remove(c.path(key)); err != nil && !os.IsNotExist(err) {
+				slog.Warn("failed to remove cached program", "key", key, "error", err)
+			}
+			c.lru.Remove(elem)
+			delete(c.entries, key)
+			c.size -= e.size
+		}
+		elem = prev
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// writeArtifact is a build that writes size bytes to dst.
+func writeArtifact(t *testing.T, size int) func(string) execResponseData {
+	return func(dst string) execResponseData {
+		if err := os.WriteFile(dst, make([]byte, size), 0644); err != nil {
+			t.Errorf("failed to write artifact: %v", err)
+			return execResponseData{Status: resultInternalError}
+		}
+		return execResponseData{Status: resultSuccess}
+	}
+}
+
+func TestArtifactCache_BuildsOnce(t *testing.T) {
+	c, err := newArtifactCache(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("newArtifactCache: %v", err)
+	}
+
+	var builds atomic.Int32
+	start := make(chan struct{})
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Go(func() {
+			<-start
+			path, release, res := c.get("a", func(dst string) execResponseData {
+				builds.Add(1)
+				time.Sleep(10 * time.Millisecond)
+				return writeArtifact(t, 10)(dst)
+			})
+			defer release()
+			if !res.success() {
+				t.Errorf("status = %q, want %q", res.Status, resultSuccess)
+			}
+			if _, err := os.Stat(path); err != nil {
+				t.Errorf("artifact not found: %v", err)
+			}
+		})
+	}
+	close(start)
+	wg.Wait()
+
+	if n := builds.Load(); n != 1 {
+		t.Errorf("built %d times, want 1", n)
+	}
+}
+
+func TestArtifactCache_FailedBuildNotCached(t *testing.T) {
+	c, err := newArtifactCache(t.TempDir(), 1024)
+	if err != nil {
+		t.Fatalf("newArtifactCache: %v", err)
+	}
+
+	_, _, res := c.get("a", func(string) execResponseData {
+		return execResponseData{Status: resultCompileError, Stderr: "error"}
+	})
+	if res.Status != resultCompileError || res.Stderr != "error" {
+		t.Errorf("result = %+v, want the compile error", res)
+	}
+
+	built := false
+	_, release, res := c.get("a", func(dst string) execResponseData {
+		built = true
+		return writeArtifact(t, 10)(dst)
+	})
+	defer release()
+	if !built || !res.success() {
+		t.Errorf("expected the code to be built again, got %+v", res)
+	}
+}
+
+func TestArtifactCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newArtifactCache(dir, 25)
+	if err != nil {
+		t.Fatalf("newArtifactCache: %v", err)
+	}
+
+	for _, key := range []string{"a", "b"} {
+		_, release, _ := c.get(key, writeArtifact(t, 10))
+		release()
+	}
+	// Using a makes b the least recently used.
+	_, release, _ := c.get("a", func(string) execResponseData {
+		t.Error("expected a to be cached")
+		return execResponseData{Status: resultInternalError}
+	})
+	release()
+	_, release, _ = c.get("c", writeArtifact(t, 10))
+	release()
+
+	for key, want := range map[string]bool{"a": true, "b": false, "c": true} {
+		_, err := os.Stat(filepath.Join(dir, key+".wasm"))
+		if got := err == nil; got != want {
+			t.Errorf("%s cached = %v, want %v", key, got, want)
+		}
+	}
+}
+
+func TestArtifactCache_KeepsRunningArtifacts(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newArtifactCache(dir, 15)
+	if err != nil {
+		t.Fatalf("newArtifactCache: %v", err)
+	}
+
+	pathA, releaseA, _ := c.get("a", writeArtifact(t, 10))
+	defer releaseA()
+	pathB, releaseB, _ := c.get("b", writeArtifact(t, 10))
+	releaseB()
+
+	// a is the least recently used, but b goes as a is being run.
+	if _, err := os.Stat(pathA); err != nil {
+		t.Errorf("artifact in use was evicted: %v", err)
+	}
+	if _, err := os.Stat(pathB); !os.IsNotExist(err) {
+		t.Errorf("expected b to be evicted")
+	}
+}
+
+func TestNewArtifactCache_LoadsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+	for i, name := range []string{"old.wasm", "new.wasm", "partial.wasm.tmp"} {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, make([]byte, 10), 0644); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		modTime := now.Add(time.Duration(i) * time.Minute)
+		if err := os.Chtimes(path, modTime, modTime); err != nil {
+			t.Fatalf("failed to set times: %v", err)
+		}
+	}
+
+	c, err := newArtifactCache(dir, 15)
+	if err != nil {
+		t.Fatalf("newArtifactCache: %v", err)
+	}
+	if _, ok := c.entries["new"]; !ok {
+		t.Error("expected the newest file to be kept")
+	}
+	if _, ok := c.entries["old"]; ok {
+		t.Error("expected the oldest file to be evicted")
+	}
+	if _, err := os.Stat(filepath.Join(dir, "partial.wasm.tmp")); !os.IsNotExist(err) {
+		t.Error("expected the partial file to be removed")
+	}
+}
@@ -12,15 +12,72 @@ import (
 
 const (
 	dataRootDir = "/app/data"
+	buildsDir   = dataRootDir + "/builds"
+	cacheDir    = dataRootDir + "/cache"
+	// templateDir has the package that code is built in, created by
+	// `swift package init` in the image or on startup.
+	templateDir = "/app/template"
+	// wasmBuildPath is where the package in templateDir puts the program.
+	wasmBuildPath = ".build/wasm32-unknown-wasi/debug/Main.wasm"
 
 	wasmMaxMemorySize = 10 * 1024 * 1024 // 10 MiB
 )
 
+// swiftVersion is the version of the compiler, detected on startup.
+var swiftVersion string
+
+// detectSwiftVersion returns the first line of `swift --version`, e.g.
+// "Swift version 6.1.2 (swift-6.1.2-RELEASE)".
+func detectSwiftVersion(ctx context.Context) (string, error) {
+	stdout, _, err := execCommandWithTimeout(
+		ctx,
+		"/",
+		10*time.Second,
+		func(ctx context.Context) *exec.Cmd {
+			return exec.CommandContext(ctx, "swift", "--version")
+		},
+	)
+	if err != nil {
+		return "", err
+	}
+	for line := range strings.SplitSeq(stdout, "\n") {
+		if strings.Contains(line, "Swift version") {
+			return strings.TrimSpace(line), nil
+		}
+	}
+	return strings.TrimSpace(stdout), nil
+}
+
 func prepareDirectories() error {
 	if err := os.MkdirAll(dataRootDir, 0755); err != nil {
 		return err
 	}
-	return nil
+	// Builds interrupted by a restart are of no use.
+	if err := os.RemoveAll(buildsDir); err != nil {
+		return err
+	}
+	return os.MkdirAll(buildsDir, 0755)
+}
+
+// prepareTemplate creates the package in templateDir unless it already exists.
+func prepareTemplate(ctx context.Context) error {
+	if _, err := os.Stat(templateDir + "/Package.swift"); err == nil {
+		return nil
+	}
+	res := prepareWorkingDir(templateDir)
+	if !res.success() {
+		return fmt.Errorf("%s", res.Stderr)
+	}
+	maxDuration := 30 * time.Second
+	for range 3 {
+		res = initSwiftProject(ctx, templateDir, maxDuration)
+		if res.success() {
+			return nil
+		}
+		time.Sleep(1 * time.Second)
+		maxDuration += 1 * time.Second
+	}
+	return fmt.Errorf("swift package init failed: %s", res.Stderr)
 }
 
 func execCommandWithTimeout(
@@ -100,6 +157,7 @@ func initSwiftProject(
 				"package",
 				"init",
 				"--type", "executable",
+				"--name", "Main",
 			)
 		},
 	)
@@ -110,6 +168,22 @@ func initSwiftProject(
 	}
 }
 
+// copySwiftTemplate puts the package of templateDir into workingDir.
+func copySwiftTemplate(workingDir string) execResponseData {
+	if err := os.CopyFS(workingDir, os.DirFS(templateDir)); err != nil {
+		return execResponseData{
+			Status: resultInternalError,
+			Stdout: "",
+			Stderr: "Failed to copy template package",
+		}
+	}
+	return execResponseData{
+		Status: resultSuccess,
+		Stdout: "",
+		Stderr: "",
+	}
+}
+
 func putSwiftSourceFile(
 	workingDir string,
 	code string,
@@ -155,43 +229,90 @@ func buildSwiftProject(
 	}
 }
 
+// runWasm runs the program within the limits, measuring its wall time. The
+// peak memory is not reported, as wasmtime does not tell how much of its
+// linear memory the program used, and the resident set size of wasmtime
+// itself would mostly be that of the runtime.
 func runWasm(
 	ctx context.Context,
-	workingDir string,
-	codeHash string,
+	wasmPath string,
 	stdin string,
+	limits execLimits,
 	maxDuration time.Duration,
 ) execResponseData {
+	start := time.Now()
 	stdout, stderr, err := execCommandWithTimeout(
 		ctx,
-		workingDir,
-		maxDuration,
+		dataRootDir,
+		limits.runDuration(maxDuration),
 		func(ctx context.Context) *exec.Cmd {
 			cmd := exec.CommandContext(
 				ctx,
 				"wasmtime",
-				"-W", fmt.Sprintf("max-memory-size=%d", wasmMaxMemorySize),
-				".build/wasm32-unknown-wasi/debug/"+codeHash+".wasm",
+				"-W", fmt.Sprintf("max-memory-size=%d", limits.memorySize()),
+				wasmPath,
 			)
 			cmd.Stdin = strings.NewReader(stdin)
 			return cmd
 		},
 	)
+	timeMs := int(time.Since(start).Milliseconds())
 	return execResponseData{
 		Status: convertCommandErrorToResultType(err, resultRuntimeError),
 		Stdout: stdout,
 		Stderr: stderr,
+		TimeMs: &timeMs,
 	}
 }
 
 func doExec(
 	ctx context.Context,
 	code string,
-	codeHash string,
-	stdin string,
+	testcase execTestcase,
 	maxDuration time.Duration,
 ) execResponseData {
-	workingDir := dataRootDir + "/" + codeHash
+	return doExecBatch(ctx, code, []execTestcase{testcase}, maxDuration)[0]
+}
+
+// doExecBatch runs the code with each of the testcases. The code is built
+// unless it is in the cache. If the build fails, its result is returned for
+// all of them.
+func doExecBatch(
+	ctx context.Context,
+	code string,
+	testcases []execTestcase,
+	maxDuration time.Duration,
+) []execResponseData {
+	results := make([]execResponseData, len(testcases))
+
+	key := sourceHash(code)
+	wasmPath, release, res := artifacts.get(key, func(dst string) execResponseData {
+		return buildWasm(ctx, key, code, dst, maxDuration)
+	})
+	if !res.success() {
+		for i := range results {
+			results[i] = res
+		}
+		return results
+	}
+	defer release()
+
+	for i, testcase := range testcases {
+		results[i] = runWasm(ctx, wasmPath, testcase.Stdin, testcase.execLimits, maxDuration)
+	}
+	return results
+}
+
+// buildWasm builds the code in a copy of the template package and moves the
+// program to dst.
+func buildWasm(
+	ctx context.Context,
+	key string,
+	code string,
+	dst string,
+	maxDuration time.Duration,
+) execResponseData {
+	workingDir := buildsDir + "/" + key
 
 	res := prepareWorkingDir(workingDir)
 	if !res.success() {
@@ -199,14 +320,7 @@ func doExec(
 	}
 	defer removeWorkingDir(workingDir)
 
-	for range 3 {
-		res = initSwiftProject(ctx, workingDir, maxDuration)
-		if res.success() {
-			break
-		}
-		time.Sleep(1 * time.Second)
-		maxDuration += 1 * time.Second
-	}
+	res = copySwiftTemplate(workingDir)
 	if !res.success() {
 		return res
 	}
@@ -221,10 +335,12 @@ func doExec(
 		return res
 	}
 
-	res = runWasm(ctx, workingDir, codeHash, stdin, maxDuration)
-	if !res.success() {
-		return res
+	if err := os.Rename(workingDir+"/"+wasmBuildPath, dst); err != nil {
+		return execResponseData{
+			Status: resultInternalError,
+			Stdout: "",
+			Stderr: "Failed to move the compiled program",
+		}
 	}
-
 	return res
 }
@@ -3,10 +3,14 @@ package main
 import (
 	"fmt"
 	"net/http"
+	"sync/atomic"
 
 	"github.com/labstack/echo/v4"
 )
 
+// running is the number of requests being executed.
+var running atomic.Int64
+
 func newBadRequestError(err error) *echo.HTTPError {
 	return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("Invalid request: %s", err.Error()))
 }
@@ -20,13 +24,47 @@ func handleExec(c echo.Context) error {
 		return newBadRequestError(err)
 	}
 
+	running.Add(1)
+	defer running.Add(-1)
+
 	res := doExec(
 		c.Request().Context(),
 		req.Code,
-		req.CodeHash,
-		req.Stdin,
+		execTestcase{Stdin: req.Stdin, execLimits: req.execLimits},
 		req.maxDuration(),
 	)
 
 	return c.JSON(http.StatusOK, res)
 }
+
+func handleExecBatch(c echo.Context) error {
+	var req execBatchRequestData
+	if err := c.Bind(&req); err != nil {
+		return newBadRequestError(err)
+	}
+	if err := req.validate(); err != nil {
+		return newBadRequestError(err)
+	}
+
+	running.Add(1)
+	defer running.Add(-1)
+
+	results := doExecBatch(
+		c.Request().Context(),
+		req.Code,
+		req.Testcases,
+		req.maxDuration(),
+	)
+
+	return c.JSON(http.StatusOK, execBatchResponseData{Results: results})
+}
+
+// handleInfo reports the worker as healthy along with what it runs.
+func handleInfo(c echo.Context) error {
+	return c.JSON(http.StatusOK, infoResponseData{
+		Language:       "swift",
+		Version:        swiftVersion,
+		MaxMemoryBytes: wasmMaxMemorySize,
+		Running:        running.Load(),
+	})
+}
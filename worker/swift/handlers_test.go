@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -64,3 +65,48 @@ func TestHandleExec_ZeroMaxDuration(t *testing.T) {
 		t.Errorf("status code = %d, want %d", httpErr.Code, http.StatusBadRequest)
 	}
 }
+
+func TestHandleExecBatch_NoTestcases(t *testing.T) {
+	e := echo.New()
+	body := `{"code":"print(1)","code_hash":"abc","testcases":[],"max_duration_ms":1000}`
+	req := httptest.NewRequest(http.MethodPost, "/exec_batch", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	err := handleExecBatch(c)
+	if err == nil {
+		t.Fatal("expected error, got nil")
+	}
+	httpErr, ok := err.(*echo.HTTPError)
+	if !ok {
+		t.Fatalf("expected *echo.HTTPError, got %T", err)
+	}
+	if httpErr.Code != http.StatusBadRequest {
+		t.Errorf("status code = %d, want %d", httpErr.Code, http.StatusBadRequest)
+	}
+}
+
+func TestHandleInfo(t *testing.T) {
+	e := echo.New()
+	req := httptest.NewRequest(http.MethodGet, "/info", nil)
+	rec := httptest.NewRecorder()
+	c := e.NewContext(req, rec)
+
+	if err := handleInfo(c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK {
+		t.Errorf("status code = %d, want %d", rec.Code, http.StatusOK)
+	}
+	var res infoResponseData
+	if err := json.Unmarshal(rec.Body.Bytes(), &res); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if res.Language != "swift" {
+		t.Errorf("language = %q, want %q", res.Language, "swift")
+	}
+	if res.MaxMemoryBytes != wasmMaxMemorySize {
+		t.Errorf("max_memory_bytes = %d, want %d", res.MaxMemoryBytes, wasmMaxMemorySize)
+	}
+}
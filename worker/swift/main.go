@@ -16,6 +16,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	version, err := detectSwiftVersion(context.Background())
+	if err != nil {
+		slog.Warn("failed to detect swift version", "error", err)
+	}
+	swiftVersion = version
+
+	if err := prepareTemplate(context.Background()); err != nil {
+		slog.Error("failed to prepare template package", "error", err)
+		os.Exit(1)
+	}
+	artifacts, err = newArtifactCache(cacheDir, artifactCacheMaxBytes)
+	if err != nil {
+		slog.Error("failed to open artifact cache", "error", err)
+		os.Exit(1)
+	}
+
 	e := echo.New()
 
 	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
@@ -41,7 +57,9 @@ func main() {
 	}))
 	e.Use(middleware.Recover())
 
+	e.GET("/info", handleInfo)
 	e.POST("/exec", handleExec)
+	e.POST("/exec_batch", handleExecBatch)
 
 	if err := e.Start(":80"); err != http.ErrServerClosed {
 		slog.Error("failed to start server", "error", err)
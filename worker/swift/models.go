@@ -15,13 +15,46 @@ const (
 
 var (
 	errInvalidMaxDuration = errors.New("'max_duration_ms' must be positive")
+	errNoTestcases        = errors.New("'testcases' must not be empty")
+	errInvalidLimits      = errors.New("'time_limit_ms' and 'memory_limit_bytes' must not be negative")
 )
 
+// execLimits bound running the code. Zero means the defaults of the worker:
+// max_duration_ms and wasmMaxMemorySize.
+type execLimits struct {
+	TimeLimitMilliseconds int   `json:"time_limit_ms"`
+	MemoryLimitBytes      int64 `json:"memory_limit_bytes"`
+}
+
+// runDuration is the time the program may run, given the limit of each step.
+func (l *execLimits) runDuration(maxDuration time.Duration) time.Duration {
+	if l.TimeLimitMilliseconds > 0 {
+		return time.Duration(l.TimeLimitMilliseconds) * time.Millisecond
+	}
+	return maxDuration
+}
+
+func (l *execLimits) memorySize() int64 {
+	if l.MemoryLimitBytes > 0 {
+		return l.MemoryLimitBytes
+	}
+	return wasmMaxMemorySize
+}
+
+func (l *execLimits) validate() error {
+	if l.TimeLimitMilliseconds < 0 || l.MemoryLimitBytes < 0 {
+		return errInvalidLimits
+	}
+	return nil
+}
+
+// execRequestData runs Code with Stdin. The code_hash sent by the backend is
+// ignored, as compiled programs are cached by the hash of the code itself.
 type execRequestData struct {
 	Code                    string `json:"code"`
-	CodeHash                string `json:"code_hash"`
 	Stdin                   string `json:"stdin"`
 	MaxDurationMilliseconds int    `json:"max_duration_ms"`
+	execLimits
 }
 
 func (req *execRequestData) maxDuration() time.Duration {
@@ -32,13 +65,62 @@ func (req *execRequestData) validate() error {
 	if req.MaxDurationMilliseconds <= 0 {
 		return errInvalidMaxDuration
 	}
+	return req.execLimits.validate()
+}
+
+// execTestcase is an input to run the code with, along with its limits.
+type execTestcase struct {
+	Stdin string `json:"stdin"`
+	execLimits
+}
+
+// execBatchRequestData runs the same code with each of Testcases, building it
+// only once.
+type execBatchRequestData struct {
+	Code                    string         `json:"code"`
+	Testcases               []execTestcase `json:"testcases"`
+	MaxDurationMilliseconds int            `json:"max_duration_ms"`
+}
+
+// maxDuration is the limit for each step, not for the whole batch.
+func (req *execBatchRequestData) maxDuration() time.Duration {
+	return time.Duration(req.MaxDurationMilliseconds) * time.Millisecond
+}
+
+func (req *execBatchRequestData) validate() error {
+	if req.MaxDurationMilliseconds <= 0 {
+		return errInvalidMaxDuration
+	}
+	if len(req.Testcases) == 0 {
+		return errNoTestcases
+	}
+	for i := range req.Testcases {
+		if err := req.Testcases[i].validate(); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
+type infoResponseData struct {
+	Language       string `json:"language"`
+	Version        string `json:"version"`
+	MaxMemoryBytes int64  `json:"max_memory_bytes"`
+	Running        int64  `json:"running"`
+}
+
 type execResponseData struct {
 	Status string `json:"status"`
 	Stdout string `json:"stdout"`
 	Stderr string `json:"stderr"`
+	// TimeMs is the wall time of running the program, omitted if it was not
+	// run. memory_bytes is never reported; see runWasm.
+	TimeMs *int `json:"time_ms,omitempty"`
+}
+
+// execBatchResponseData has the results in the order of the testcases.
+type execBatchResponseData struct {
+	Results []execResponseData `json:"results"`
 }
 
 func (res *execResponseData) success() bool {
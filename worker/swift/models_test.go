@@ -26,6 +26,29 @@ func TestExecRequestData_Validate(t *testing.T) {
 	}
 }
 
+func TestExecBatchRequestData_Validate(t *testing.T) {
+	tests := []struct {
+		name          string
+		maxDurationMs int
+		testcases     []execTestcase
+		wantErr       error
+	}{
+		{"valid", 1000, []execTestcase{{Stdin: "1"}, {Stdin: "2", execLimits: execLimits{TimeLimitMilliseconds: 100}}}, nil},
+		{"zero duration", 0, []execTestcase{{Stdin: "1"}}, errInvalidMaxDuration},
+		{"no testcases", 1000, nil, errNoTestcases},
+		{"negative limit", 1000, []execTestcase{{Stdin: "1", execLimits: execLimits{MemoryLimitBytes: -1}}}, errInvalidLimits},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := &execBatchRequestData{MaxDurationMilliseconds: tt.maxDurationMs, Testcases: tt.testcases}
+			err := req.validate()
+			if err != tt.wantErr {
+				t.Errorf("validate() = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestExecRequestData_MaxDuration(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -46,6 +69,28 @@ func TestExecRequestData_MaxDuration(t *testing.T) {
 	}
 }
 
+func TestExecLimits(t *testing.T) {
+	tests := []struct {
+		name       string
+		limits     execLimits
+		wantRun    time.Duration
+		wantMemory int64
+	}{
+		{"defaults", execLimits{}, 30 * time.Second, wasmMaxMemorySize},
+		{"limits", execLimits{TimeLimitMilliseconds: 2000, MemoryLimitBytes: 64 << 20}, 2 * time.Second, 64 << 20},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.limits.runDuration(30 * time.Second); got != tt.wantRun {
+				t.Errorf("runDuration() = %v, want %v", got, tt.wantRun)
+			}
+			if got := tt.limits.memorySize(); got != tt.wantMemory {
+				t.Errorf("memorySize() = %d, want %d", got, tt.wantMemory)
+			}
+		})
+	}
+}
+
 func TestExecResponseData_Success(t *testing.T) {
 	tests := []struct {
 		name   string